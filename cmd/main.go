@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 
@@ -9,6 +10,11 @@ import (
 	"github.com/kefirchick13/memoria-collect-platform-golang/internal/handler"
 	"github.com/kefirchick13/memoria-collect-platform-golang/internal/repository"
 	"github.com/kefirchick13/memoria-collect-platform-golang/internal/service"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/blobstore"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/metadata"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/notify"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/realtime"
 	_ "github.com/lib/pq"
 	"github.com/spf13/viper" // чтение конфиг файлов разных  форматов
 	"go.uber.org/zap"        // самый быстрый логгер для go от uber
@@ -42,23 +48,183 @@ func main() {
 		log.Fatal(".env not found; continuing without it, ", err)
 	}
 
-	db, err := repository.NewPostgresDB(repository.Config{
+	pagination.SetCursorSecret(os.Getenv("CURSOR_SECRET"))
+
+	dbConfig := repository.Config{
 		Host:     viper.GetString("db.host"),
 		Port:     viper.GetString(("db.port")),
 		Username: viper.GetString("db.username"),
 		DBName:   viper.GetString("db.name"),
 		SSLMode:  viper.GetString("db.sslmode"),
 		Password: os.Getenv("DB_PASSWORD"),
-	})
+	}
+	db, err := repository.NewPostgresDB(dbConfig)
+
+	if err != nil {
+		log.Fatal(err)
+	}
 
+	storage, err := blobstore.New(blobstore.Config{
+		Driver: viper.GetString("storage.driver"),
+		Local: blobstore.LocalConfig{
+			Root:    viper.GetString("storage.local.root"),
+			BaseURL: viper.GetString("storage.public_url") + "/files",
+			Secret:  os.Getenv("STORAGE_SECRET"),
+		},
+		S3: blobstore.S3Config{
+			Endpoint:  viper.GetString("storage.s3.endpoint"),
+			Region:    viper.GetString("storage.s3.region"),
+			Bucket:    viper.GetString("storage.s3.bucket"),
+			PathStyle: viper.GetBool("storage.s3.path_style"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+		},
+	})
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	metadataProviders := metadata.NewRegistryFromConfig(metadata.Config{
+		Timeout: viper.GetDuration("metadata.timeout"),
+		OpenLibrary: metadata.ProviderConfig[metadata.OpenLibraryConfig]{
+			Enabled: viper.GetBool("metadata.openlibrary.enabled"),
+			Config: metadata.OpenLibraryConfig{
+				BaseURL:   viper.GetString("metadata.openlibrary.base_url"),
+				CoversURL: viper.GetString("metadata.openlibrary.covers_url"),
+			},
+		},
+		TMDB: metadata.ProviderConfig[metadata.TMDBConfig]{
+			Enabled: viper.GetBool("metadata.tmdb.enabled"),
+			Config: metadata.TMDBConfig{
+				BaseURL:     viper.GetString("metadata.tmdb.base_url"),
+				ImageURL:    viper.GetString("metadata.tmdb.image_url"),
+				Language:    viper.GetString("metadata.tmdb.language"),
+				APIKey:      os.Getenv("TMDB_API_KEY"),
+				AccessToken: os.Getenv("TMDB_ACCESS_TOKEN"),
+			},
+		},
+		AniList: metadata.ProviderConfig[metadata.AniListConfig]{
+			Enabled: viper.GetBool("metadata.anilist.enabled"),
+			Config: metadata.AniListConfig{
+				BaseURL: viper.GetString("metadata.anilist.base_url"),
+			},
+		},
+		Shikimori: metadata.ProviderConfig[metadata.ShikimoriConfig]{
+			Enabled: viper.GetBool("metadata.shikimori.enabled"),
+			Config: metadata.ShikimoriConfig{
+				BaseURL: viper.GetString("metadata.shikimori.base_url"),
+			},
+		},
+	})
+
+	// Каналы доставки уведомлений вне приложения. Почта подключается, если задан notifications.smtp.host
+	notifyChannels := notify.NewRegistry()
+	if viper.GetString("notifications.smtp.host") != "" {
+		email, err := notify.NewEmail(notify.SMTPConfig{
+			Host:     viper.GetString("notifications.smtp.host"),
+			Port:     viper.GetInt("notifications.smtp.port"),
+			Username: viper.GetString("notifications.smtp.username"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     viper.GetString("notifications.smtp.from"),
+			Timeout:  viper.GetDuration("notifications.smtp.timeout"),
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+		notifyChannels.Register(email)
+	}
+
+	// Брокер событий /stream: в памяти для одного экземпляра, postgres - для нескольких
+	var broker realtime.Broker
+	switch viper.GetString("realtime.broker") {
+	case "", realtime.BrokerMemory:
+		broker = realtime.NewMemoryBroker()
+	case realtime.BrokerPostgres:
+		broker = realtime.NewPostgresBroker(db, dbConfig.DSN(), viper.GetString("realtime.channel"))
+	default:
+		log.Fatalf("Unknown realtime broker %q", viper.GetString("realtime.broker"))
+	}
+
 	repos := repository.NewRepository(db, log)
-	services := service.NewService(repos, log)
+	services := service.NewService(repos, service.Dependencies{
+		Storage: storage,
+		Images: service.ImageConfig{
+			PublicURL:     viper.GetString("storage.public_url"),
+			MaxUploadSize: viper.GetInt64("storage.max_upload_size"),
+			SignedURLTTL:  viper.GetDuration("storage.signed_url_ttl"),
+		},
+		Metadata: metadataProviders,
+		MetadataRefresh: service.MetadataRefreshConfig{
+			Enabled:   viper.GetBool("metadata.refresh.enabled"),
+			Interval:  viper.GetDuration("metadata.refresh.interval"),
+			MaxAge:    viper.GetDuration("metadata.refresh.max_age"),
+			BatchSize: viper.GetInt("metadata.refresh.batch_size"),
+			RateLimits: map[string]int{
+				"openlibrary": viper.GetInt("metadata.openlibrary.rate_limit"),
+				"tmdb":        viper.GetInt("metadata.tmdb.rate_limit"),
+				"anilist":     viper.GetInt("metadata.anilist.rate_limit"),
+				"shikimori":   viper.GetInt("metadata.shikimori.rate_limit"),
+			},
+		},
+		Feeds: service.FeedConfig{
+			PublicURL: viper.GetString("storage.public_url"),
+			Limit:     viper.GetInt("feeds.limit"),
+			MaxAge:    viper.GetDuration("feeds.max_age"),
+		},
+		Notifications: service.NotificationConfig{
+			Channels:    notifyChannels,
+			Interval:    viper.GetDuration("notifications.delivery.interval"),
+			BatchSize:   viper.GetInt("notifications.delivery.batch_size"),
+			MaxAttempts: viper.GetInt("notifications.delivery.max_attempts"),
+		},
+		Realtime: broker,
+		Webhooks: service.WebhookConfig{
+			AllowPrivateNetworks: viper.GetBool("webhooks.allow_private_networks"),
+			Timeout:              viper.GetDuration("webhooks.timeout"),
+			Interval:             viper.GetDuration("webhooks.delivery.interval"),
+			BatchSize:            viper.GetInt("webhooks.delivery.batch_size"),
+			MaxAttempts:          viper.GetInt("webhooks.delivery.max_attempts"),
+		},
+		Recommendations: service.RecommendationConfig{
+			Enabled:          viper.GetBool("recommendations.enabled"),
+			Interval:         viper.GetDuration("recommendations.interval"),
+			UserHistory:      viper.GetInt("recommendations.user_history"),
+			MinCoOccurrences: viper.GetInt("recommendations.min_co_occurrences"),
+			Shrinkage:        viper.GetFloat64("recommendations.shrinkage"),
+			PerItem:          viper.GetInt("recommendations.per_item"),
+		},
+		Charts: service.ChartConfig{
+			Enabled:          viper.GetBool("charts.enabled"),
+			Interval:         viper.GetDuration("charts.interval"),
+			TrendingWindow:   viper.GetDuration("charts.trending.window"),
+			TrendingHalfLife: viper.GetDuration("charts.trending.half_life"),
+			PriorVotes:       viper.GetInt("charts.top_rated.prior_votes"),
+			Size:             viper.GetInt("charts.size"),
+		},
+		ItemStats: service.ItemStatsConfig{
+			Enabled:  viper.GetBool("item_stats.enabled"),
+			Interval: viper.GetDuration("item_stats.interval"),
+		},
+	}, log)
 	handlers := handler.NewHandler(services, log)
 
+	// Фоновое обновление импортированных элементов из внешних каталогов
+	go services.MetadataRefreshService.RunMetadataRefresh(context.Background())
+	// Фоновая обработка импорта выгрузок других трекеров
+	go services.ImportService.RunImports(context.Background())
+	// Фоновая доставка уведомлений во внешние каналы
+	go services.NotificationService.RunDeliveries(context.Background())
+	// Раздача событий брокера подключенным клиентам /stream
+	go services.RealtimeService.RunRealtime(context.Background())
+	// Фоновая отправка событий на вебхуки пользователей
+	go services.WebhookService.RunWebhookDeliveries(context.Background())
+	// Фоновый пересчет похожих элементов для рекомендаций
+	go services.RecommendationService.RunRecommendations(context.Background())
+	// Фоновый пересчет чартов каталога
+	go services.ChartService.RunCharts(context.Background())
+	// Фоновый пересчет популярности и оценок элементов для ленты
+	go services.ItemStatsService.RunItemStats(context.Background())
+
 	server := memoria.Server{}
 
 	if err := server.Run(viper.GetString("port"), handlers.InitRoutes()); err != nil {
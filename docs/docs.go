@@ -150,6 +150,204 @@ const docTemplate = `{
                 }
             }
         },
+        "/charts/most-collected": {
+            "get": {
+                "description": "Элементы, которые есть в коллекциях у наибольшего числа пользователей за все время",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "charts"
+                ],
+                "summary": "Самое собираемое",
+                "parameters": [
+                    {
+                        "enum": [
+                            "books",
+                            "anime",
+                            "series",
+                            "movies"
+                        ],
+                        "type": "string",
+                        "description": "Тип элементов",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Slug жанра",
+                        "name": "genre",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Кол-во элементов на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Чарт",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ChartResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неизвестный тип",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Жанр не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/charts/top-rated": {
+            "get": {
+                "description": "Байесовское среднее: к оценкам элемента добавляется несколько средних оценок каталога,\nпоэтому элемент с одной высокой оценкой не обгоняет элементы со многими оценками",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "charts"
+                ],
+                "summary": "Лучшие по оценкам",
+                "parameters": [
+                    {
+                        "enum": [
+                            "books",
+                            "anime",
+                            "series",
+                            "movies"
+                        ],
+                        "type": "string",
+                        "description": "Тип элементов",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Slug жанра",
+                        "name": "genre",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Кол-во элементов на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Чарт",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ChartResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неизвестный тип",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Жанр не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/charts/trending": {
+            "get": {
+                "description": "Элементы, которые чаще всего добавляли в коллекции за последние дни, свежие добавления весят больше.\nЧарты пересчитываются фоновой задачей, в каждом хранится ограниченное число мест",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "charts"
+                ],
+                "summary": "Популярное на этой неделе",
+                "parameters": [
+                    {
+                        "enum": [
+                            "books",
+                            "anime",
+                            "series",
+                            "movies"
+                        ],
+                        "type": "string",
+                        "description": "Тип элементов",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Slug жанра",
+                        "name": "genre",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Кол-во элементов на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Чарт",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ChartResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неизвестный тип",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Жанр не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/collections": {
             "get": {
                 "security": [
@@ -157,7 +355,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Возвращает пагинированный список коллекций пользователя",
+                "description": "Возвращает пагинированный список коллекций пользователя.\nС параметром cursor (пустой - первая страница) отдается в keyset-режиме:\nответ {data, pagination: {next, prev, limit, total}}, total - только при with_total=true",
                 "produces": [
                     "application/json"
                 ],
@@ -166,6 +364,23 @@ const docTemplate = `{
                 ],
                 "summary": "Получить список коллекций",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Теги коллекций через запятую",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "and",
+                            "or"
+                        ],
+                        "type": "string",
+                        "default": "or",
+                        "description": "Режим совпадения тегов",
+                        "name": "tags_mode",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "default": 1,
@@ -179,6 +394,18 @@ const docTemplate = `{
                         "description": "Кол-во элементов на странице",
                         "name": "per_page",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор keyset-пагинации",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Считать общее кол-во в keyset-режиме",
+                        "name": "with_total",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -188,6 +415,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.PaginatedCollectionsResponse"
                         }
                     },
+                    "400": {
+                        "description": "Неверный курсор",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Ошибка сервера",
                         "schema": {
@@ -246,21 +479,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/collections/{id}/items": {
+        "/collections/{id}/comments": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Возвращает все элементы указанной коллекции",
+                "description": "Ветки комментариев, сначала новые. Пагинация по корневым комментариям, ответы приходят целиком в replies.\nУдаленный комментарий остается без текста, если на него есть ответы. Комментарии закрытой коллекции видит только владелец",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "collections"
+                    "engagement"
                 ],
-                "summary": "Получить элементы коллекции",
+                "summary": "Комментарии коллекции",
                 "parameters": [
                     {
                         "type": "string",
@@ -268,21 +501,37 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Кол-во веток на странице",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Список элементов коллекции",
-                        "schema": {}
+                        "description": "Ветки комментариев",
+                        "schema": {
+                            "$ref": "#/definitions/handler.PaginatedCommentsResponse"
+                        }
                     },
-                    "400": {
-                        "description": "Не указан ID коллекции",
+                    "403": {
+                        "description": "Коллекция приватная",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Элементы не найдены",
+                        "description": "Коллекция не найдена",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -295,7 +544,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Добавляет существующий элемент в указанную коллекцию",
+                "description": "Комментарий к публичной коллекции или ответ на комментарий (parent_id). Владелец коллекции и автор комментария, на который ответили, получают уведомление",
                 "consumes": [
                     "application/json"
                 ],
@@ -303,9 +552,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "collections"
+                    "engagement"
                 ],
-                "summary": "Добавить элемент в коллекцию",
+                "summary": "Комментировать коллекцию",
                 "parameters": [
                     {
                         "type": "string",
@@ -315,48 +564,36 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Данные для добавления элемента",
+                        "description": "Комментарий",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.AddItemInput"
+                            "$ref": "#/definitions/handler.CommentInput"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Элемент успешно добавлен",
+                    "201": {
+                        "description": "Комментарий создан",
                         "schema": {
-                            "$ref": "#/definitions/handler.SuccessResponse"
+                            "$ref": "#/definitions/models.CollectionComment"
                         }
                     },
                     "400": {
-                        "description": "Неверные входные данные",
+                        "description": "Пустой или слишком длинный комментарий",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "Доступ запрещен",
+                        "description": "Коллекция приватная",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Коллекция или элемент не найдены",
-                        "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
-                        }
-                    },
-                    "409": {
-                        "description": "Элемент уже в коллекции",
-                        "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Внутренняя ошибка сервера",
+                        "description": "Коллекция или родительский комментарий не найдены",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -364,91 +601,114 @@ const docTemplate = `{
                 }
             }
         },
-        "/items": {
-            "get": {
-                "description": "Возвращает пагинированный список элементов по указанному типу",
+        "/collections/{id}/cover": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Изображение jpeg, png или gif проверяется по содержимому, для него создаются миниатюры small, medium и large",
+                "consumes": [
+                    "multipart/form-data"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "items"
+                    "images"
                 ],
-                "summary": "Получить элементы по типу",
+                "summary": "Загрузить обложку коллекции",
                 "parameters": [
                     {
                         "type": "string",
-                        "default": "book",
-                        "description": "Тип элементов",
-                        "name": "type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Номер страницы",
-                        "name": "page",
-                        "in": "query"
+                        "description": "ID коллекции",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "Кол-во элементов на странице",
-                        "name": "per_page",
-                        "in": "query"
+                        "type": "file",
+                        "description": "Изображение",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Успешный ответ",
+                    "201": {
+                        "description": "Загруженное изображение",
                         "schema": {
-                            "$ref": "#/definitions/handler.PaginatedItemsResponse"
+                            "$ref": "#/definitions/models.ImageUpload"
                         }
                     },
-                    "502": {
-                        "description": "Ошибка сервера",
+                    "400": {
+                        "description": "Файл не передан или не является изображением",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Коллекция принадлежит другому пользователю",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Коллекция не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Файл слишком большой",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     }
-                }
-            },
-            "post": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Создает новый элемент коллекции",
-                "consumes": [
-                    "application/json"
-                ],
+                }
+            }
+        },
+        "/collections/{id}/cover.png": {
+            "get": {
+                "description": "Собственная обложка коллекции (перенаправление) или мозаика из обложек первых 4 или 9 элементов. Если обложек нет - заглушка по типу коллекции. Мозаика пересобирается при изменении содержимого коллекции. Токен нужен только для приватных коллекций",
                 "produces": [
-                    "application/json"
+                    "image/png"
                 ],
                 "tags": [
-                    "items"
+                    "images"
                 ],
-                "summary": "Создать элемент коллекции",
+                "summary": "Обложка коллекции",
                 "parameters": [
                     {
-                        "description": "Данные для создания элемента",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.CreateCollectionItemInput"
-                        }
+                        "type": "string",
+                        "description": "ID коллекции",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Элемент создан, возвращает ID",
+                        "description": "Обложка в PNG",
                         "schema": {
-                            "$ref": "#/definitions/handler.IDResponse"
+                            "type": "file"
                         }
                     },
-                    "400": {
-                        "description": "Неверные входные данные",
+                    "302": {
+                        "description": "Перенаправление на собственную обложку"
+                    },
+                    "304": {
+                        "description": "Обложка не изменилась"
+                    },
+                    "403": {
+                        "description": "Коллекция приватная",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Коллекция не найдена",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -456,39 +716,45 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/account": {
-            "delete": {
+        "/collections/{id}/engagement": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Полностью удаляет аккаунт текущего пользователя",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Счетчики коллекции и поставил ли текущий пользователь лайк и сохранил ли ее",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "engagement"
+                ],
+                "summary": "Лайки, сохранения и комментарии коллекции",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID коллекции",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Удалить аккаунт",
                 "responses": {
                     "200": {
-                        "description": "Аккаунт успешно удален",
+                        "description": "Счетчики",
                         "schema": {
-                            "$ref": "#/definitions/handler.MessageResponse"
+                            "$ref": "#/definitions/models.CollectionEngagement"
                         }
                     },
-                    "404": {
-                        "description": "Пользователь не найден",
+                    "403": {
+                        "description": "Коллекция приватная",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Ошибка сервера",
+                    "404": {
+                        "description": "Коллекция не найдена",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -496,56 +762,65 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/github/link": {
-            "post": {
+        "/collections/{id}/export": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Привязывает GitHub аккаунт к текущему пользователю",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Элементы коллекции с метаданными, жанрами, заметками, статусами и оценками. Форматы: csv, json, md (Markdown) и html (страница для печати).\nЧужую публичную коллекцию можно выгрузить без статусов, оценок и тегов владельца",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/plain",
+                    "text/html"
                 ],
                 "tags": [
-                    "users"
+                    "collections"
                 ],
-                "summary": "Привязать GitHub аккаунт",
+                "summary": "Выгрузить коллекцию",
                 "parameters": [
                     {
-                        "description": "Код авторизации GitHub",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.LinkGitHubInput"
-                        }
+                        "type": "string",
+                        "description": "ID коллекции",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "json",
+                            "md",
+                            "html"
+                        ],
+                        "type": "string",
+                        "description": "Формат (по умолчанию json)",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "GitHub аккаунт успешно привязан",
+                        "description": "Файл выгрузки",
                         "schema": {
-                            "$ref": "#/definitions/handler.MessageResponse"
+                            "type": "file"
                         }
                     },
                     "400": {
-                        "description": "Неверные входные данные",
+                        "description": "Неизвестный формат",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "GitHub аккаунт уже привязан к другому пользователю",
+                    "403": {
+                        "description": "Коллекция приватная",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Ошибка сервера",
+                    "404": {
+                        "description": "Коллекция не найдена",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -553,65 +828,73 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/github/unlink": {
-            "post": {
+        "/collections/{id}/items": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Отвязывает GitHub аккаунт от текущего пользователя",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Возвращает все элементы указанной коллекции",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "collections"
                 ],
-                "summary": "Отвязать GitHub аккаунт",
+                "summary": "Получить элементы коллекции",
                 "parameters": [
                     {
-                        "description": "Пароль для подтверждения",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.UnlinkGitHubInput"
-                        }
+                        "type": "string",
+                        "description": "ID коллекции",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Свои теги элементов через запятую",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "and",
+                            "or"
+                        ],
+                        "type": "string",
+                        "default": "or",
+                        "description": "Режим совпадения тегов",
+                        "name": "tags_mode",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "GitHub аккаунт успешно отвязан",
-                        "schema": {
-                            "$ref": "#/definitions/handler.MessageResponse"
-                        }
+                        "description": "Список элементов коллекции",
+                        "schema": {}
                     },
                     "400": {
-                        "description": "Неверные входные данные",
+                        "description": "Не указан ID коллекции",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Ошибка сервера",
+                    "404": {
+                        "description": "Элементы не найдены",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/users/password": {
-            "put": {
+            },
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Изменяет пароль текущего пользователя",
+                "description": "Добавляет существующий элемент в указанную коллекцию",
                 "consumes": [
                     "application/json"
                 ],
@@ -619,25 +902,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "collections"
                 ],
-                "summary": "Изменить пароль",
+                "summary": "Добавить элемент в коллекцию",
                 "parameters": [
                     {
-                        "description": "Данные для изменения пароля",
+                        "type": "string",
+                        "description": "ID коллекции",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные для добавления элемента",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.ChangePasswordInput"
+                            "$ref": "#/definitions/handler.AddItemInput"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Пароль успешно изменен",
+                        "description": "Элемент успешно добавлен",
                         "schema": {
-                            "$ref": "#/definitions/handler.MessageResponse"
+                            "$ref": "#/definitions/handler.SuccessResponse"
                         }
                     },
                     "400": {
@@ -646,14 +936,26 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
                     "404": {
-                        "description": "Пользователь не найден",
+                        "description": "Коллекция или элемент не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Элемент уже в коллекции",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка сервера",
+                        "description": "Внутренняя ошибка сервера",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -661,436 +963,8309 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/profile": {
-            "get": {
+        "/collections/{id}/like": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Возвращает профиль текущего аутентифицированного пользователя",
+                "description": "Лайк публичной коллекции. Повторный лайк ничего не меняет",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "engagement"
+                ],
+                "summary": "Лайкнуть коллекцию",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID коллекции",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Получить профиль пользователя",
                 "responses": {
                     "200": {
-                        "description": "Профиль пользователя",
+                        "description": "Счетчики после лайка",
                         "schema": {
-                            "$ref": "#/definitions/handler.UserProfileResponse"
+                            "$ref": "#/definitions/models.CollectionEngagement"
+                        }
+                    },
+                    "403": {
+                        "description": "Коллекция приватная",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Пользователь не найден",
+                        "description": "Коллекция не найдена",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     }
                 }
             },
-            "put": {
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Обновляет данные профиля текущего пользователя",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Снимает лайк, в том числе с коллекции, которую владелец закрыл",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "engagement"
                 ],
-                "summary": "Обновить профиль пользователя",
+                "summary": "Снять лайк",
                 "parameters": [
                     {
-                        "description": "Данные для обновления профиля",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.UpdateUserProfileInput"
-                        }
+                        "type": "string",
+                        "description": "ID коллекции",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Профиль успешно обновлен",
-                        "schema": {
-                            "$ref": "#/definitions/handler.MessageResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Неверные входные данные",
+                        "description": "Счетчики после снятия лайка",
                         "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
+                            "$ref": "#/definitions/models.CollectionEngagement"
                         }
                     },
                     "404": {
-                        "description": "Пользователь не найден",
+                        "description": "Коллекция не найдена",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
-                    },
-                    "409": {
-                        "description": "Email уже используется",
+                    }
+                }
+            }
+        },
+        "/collections/{id}/save": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Добавляет публичную коллекцию в сохраненные. Повторное сохранение ничего не меняет",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "engagement"
+                ],
+                "summary": "Сохранить коллекцию",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID коллекции",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Счетчики после сохранения",
+                        "schema": {
+                            "$ref": "#/definitions/models.CollectionEngagement"
+                        }
+                    },
+                    "403": {
+                        "description": "Коллекция приватная",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Ошибка сервера",
+                    "404": {
+                        "description": "Коллекция не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "engagement"
+                ],
+                "summary": "Убрать из сохраненных",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID коллекции",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Счетчики после удаления из сохраненных",
+                        "schema": {
+                            "$ref": "#/definitions/models.CollectionEngagement"
+                        }
+                    },
+                    "404": {
+                        "description": "Коллекция не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}/tags": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "taxonomy"
+                ],
+                "summary": "Получить теги коллекции",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID коллекции",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Теги",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Tag"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Коллекция не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Доступно только владельцу коллекции. Отсутствующие теги создаются",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "taxonomy"
+                ],
+                "summary": "Задать теги коллекции",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID коллекции",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Теги",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.SetTagsInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Теги коллекции",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Tag"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные теги",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Коллекция не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}/visibility": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Открывает или закрывает коллекцию. Лайки, сохранения и комментарии закрытой коллекции сохраняются,\nно другим пользователям не видны, пока коллекция снова не станет публичной",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "Видимость коллекции",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID коллекции",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Видимость",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CollectionVisibilityInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Видимость изменена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные данные",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Чужая коллекция",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
+                    },
+                    "404": {
+                        "description": "Коллекция не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/comments/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Изменить текст может только автор комментария",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "engagement"
+                ],
+                "summary": "Изменить комментарий",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID комментария",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новый текст",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.EditCommentInput"
+                        }
                     }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Комментарий изменен",
+                        "schema": {
+                            "$ref": "#/definitions/models.CollectionComment"
+                        }
+                    },
+                    "400": {
+                        "description": "Пустой или слишком длинный комментарий",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Чужой комментарий",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Комментарий не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Удалить комментарий может его автор или владелец коллекции",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "engagement"
+                ],
+                "summary": "Удалить комментарий",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID комментария",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Комментарий удален",
+                        "schema": {
+                            "$ref": "#/definitions/handler.SuccessResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Чужой комментарий",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Комментарий не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/catalog": {
+            "get": {
+                "description": "Лента Atom или RSS элементов, недавно появившихся в публичном каталоге, включая одобренные модераторами. Токен не нужен.\nПоддерживаются условные запросы (If-None-Match, If-Modified-Since)",
+                "produces": [
+                    "text/xml"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Лента каталога",
+                "parameters": [
+                    {
+                        "enum": [
+                            "atom",
+                            "rss"
+                        ],
+                        "type": "string",
+                        "description": "Формат ленты (по умолчанию atom)",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Лента",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "304": {
+                        "description": "Лента не изменилась"
+                    },
+                    "400": {
+                        "description": "Неизвестный формат",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/collections/{id}": {
+            "get": {
+                "description": "Лента Atom или RSS публичной коллекции: последние добавленные элементы с заметками владельца. Токен не нужен.\nПоддерживаются условные запросы (If-None-Match, If-Modified-Since)",
+                "produces": [
+                    "text/xml"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Лента коллекции",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID коллекции",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "atom",
+                            "rss"
+                        ],
+                        "type": "string",
+                        "description": "Формат ленты (по умолчанию atom)",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Лента",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "304": {
+                        "description": "Лента не изменилась"
+                    },
+                    "400": {
+                        "description": "Неизвестный формат",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Коллекция приватная",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Коллекция не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/users/{id}": {
+            "get": {
+                "description": "Лента Atom или RSS публичной активности пользователя: новые публичные коллекции и элементы, добавленные в них. Токен не нужен.\nПоддерживаются условные запросы (If-None-Match, If-Modified-Since)",
+                "produces": [
+                    "text/xml"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Лента пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "atom",
+                            "rss"
+                        ],
+                        "type": "string",
+                        "description": "Формат ленты (по умолчанию atom)",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Лента",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "304": {
+                        "description": "Лента не изменилась"
+                    },
+                    "400": {
+                        "description": "Неизвестный формат",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден или аккаунт закрыт",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/files/{key}": {
+            "get": {
+                "description": "Используется локальным хранилищем. Ссылки выдает /images/{id}",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "images"
+                ],
+                "summary": "Файл по подписанной ссылке",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Ключ файла",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Срок действия ссылки (unix)",
+                        "name": "expires",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Подпись",
+                        "name": "signature",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Файл",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "403": {
+                        "description": "Неверная или просроченная подпись",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Файл не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/franchises": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "franchises"
+                ],
+                "summary": "Получить список франшиз",
+                "responses": {
+                    "200": {
+                        "description": "Франшизы",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Franchise"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Создает франшизу. Доступно модераторам и администраторам",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "franchises"
+                ],
+                "summary": "Создать франшизу",
+                "parameters": [
+                    {
+                        "description": "Данные франшизы",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateFranchiseInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Франшиза создана",
+                        "schema": {
+                            "$ref": "#/definitions/models.Franchise"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные входные данные",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Франшиза уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/franchises/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает франшизу с элементами, доступными пользователю",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "franchises"
+                ],
+                "summary": "Получить франшизу",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID франшизы",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Франшиза",
+                        "schema": {
+                            "$ref": "#/definitions/models.Franchise"
+                        }
+                    },
+                    "404": {
+                        "description": "Франшиза не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/franchises/{id}/items": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Доступно модераторам и администраторам",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "franchises"
+                ],
+                "summary": "Добавить элемент во франшизу",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID франшизы",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "ID элемента",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.AddFranchiseItemInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Элемент добавлен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.SuccessResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Франшиза или элемент не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Элемент уже во франшизе",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/franchises/{id}/items/{item_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Доступно модераторам и администраторам",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "franchises"
+                ],
+                "summary": "Удалить элемент из франшизы",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID франшизы",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID элемента",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Элемент удален из франшизы",
+                        "schema": {
+                            "$ref": "#/definitions/handler.SuccessResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Элемент не найден во франшизе",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/genres": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "taxonomy"
+                ],
+                "summary": "Получить список жанров",
+                "responses": {
+                    "200": {
+                        "description": "Жанры",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Genre"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Доступно модераторам и администраторам",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "taxonomy"
+                ],
+                "summary": "Создать жанр",
+                "parameters": [
+                    {
+                        "description": "Данные жанра",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateGenreInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Жанр создан",
+                        "schema": {
+                            "$ref": "#/definitions/models.Genre"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные входные данные",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Жанр уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/images/{id}": {
+            "get": {
+                "description": "Постоянная ссылка на изображение: перенаправляет на подписанную ссылку с ограниченным сроком действия",
+                "tags": [
+                    "images"
+                ],
+                "summary": "Получить изображение",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID изображения",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "original",
+                            "small",
+                            "medium",
+                            "large"
+                        ],
+                        "type": "string",
+                        "description": "Размер",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "Перенаправление на подписанную ссылку"
+                    },
+                    "400": {
+                        "description": "Неизвестный размер",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Изображение не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/imports": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Задачи импорта, сначала новые",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "imports"
+                ],
+                "summary": "Импорты пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Кол-во задач на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Задачи импорта",
+                        "schema": {
+                            "$ref": "#/definitions/handler.PaginatedImportsResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Выгрузка Goodreads (CSV), MyAnimeList (XML, можно .xml.gz), Letterboxd (ZIP или отдельный CSV) или IMDb (CSV оценок или списка).\nСтроки сопоставляются с каталогом в фоне: по внешним ID, затем по названию и году. Полки и списки становятся коллекциями,\nстатусы, оценки, даты и отзывы переносятся в прогресс. Ход импорта - в GET /imports/{id}, по завершении приходит уведомление",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "imports"
+                ],
+                "summary": "Импортировать библиотеку",
+                "parameters": [
+                    {
+                        "enum": [
+                            "goodreads",
+                            "mal",
+                            "letterboxd",
+                            "imdb"
+                        ],
+                        "type": "string",
+                        "description": "Формат выгрузки",
+                        "name": "format",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Файл выгрузки (до 20 МБ)",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Создавать свои элементы для несопоставленных строк",
+                        "name": "create_custom",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Задача импорта",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Неизвестный формат или файл не соответствует формату",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Файл слишком большой",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/imports/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Статус задачи, счетчики строк и процент обработанных строк",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "imports"
+                ],
+                "summary": "Ход импорта",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID импорта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Задача импорта",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportJob"
+                        }
+                    },
+                    "404": {
+                        "description": "Импорт не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/imports/{id}/rows/{row_id}/resolve": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Элемент добавляется в коллекции строки, переносятся статус, оценка и отзыв. Свой элемент, созданный импортом для строки, сливается с выбранным",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "imports"
+                ],
+                "summary": "Сопоставить строку импорта",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID импорта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID строки",
+                        "name": "row_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Элемент каталога",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ResolveImportRowInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Сопоставленная строка",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportRow"
+                        }
+                    },
+                    "400": {
+                        "description": "Тип элемента не совпадает со строкой",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Импорт, строка или элемент не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Строка уже сопоставлена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/imports/{id}/unmatched": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Строки, для которых не нашелся элемент каталога: созданные свои элементы (created), пропущенные (unmatched) и ошибочные (failed)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "imports"
+                ],
+                "summary": "Несопоставленные строки импорта",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID импорта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Кол-во строк на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Строки импорта",
+                        "schema": {
+                            "$ref": "#/definitions/handler.PaginatedImportRowsResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Импорт не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/items": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает пагинированную ленту публичных элементов с фильтрами, сортировкой и фасетами.\nФасеты (типы, жанры, источник) считаются с учетом всех фильтров, кроме собственного.\nС параметром cursor (пустой - первая страница) лента отдается в keyset-режиме без фасетов:\nответ {data, pagination: {next, prev, limit, total}}, total - только при with_total=true",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Лента элементов",
+                "parameters": [
+                    {
+                        "enum": [
+                            "books",
+                            "anime",
+                            "series",
+                            "movies"
+                        ],
+                        "type": "string",
+                        "description": "Тип элементов",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Slug жанров через запятую",
+                        "name": "genres",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "and",
+                            "or"
+                        ],
+                        "type": "string",
+                        "default": "or",
+                        "description": "Режим совпадения жанров",
+                        "name": "genres_mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Свои теги через запятую",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "and",
+                            "or"
+                        ],
+                        "type": "string",
+                        "default": "or",
+                        "description": "Режим совпадения тегов",
+                        "name": "tags_mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Год выхода от",
+                        "name": "year_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Год выхода до",
+                        "name": "year_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Средняя оценка от (1-10)",
+                        "name": "rating_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Средняя оценка до (1-10)",
+                        "name": "rating_max",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "custom",
+                            "catalog"
+                        ],
+                        "type": "string",
+                        "description": "Источник элемента",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Исключить элементы из своих коллекций",
+                        "name": "not_in_library",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "title",
+                            "newest",
+                            "popular",
+                            "top_rated"
+                        ],
+                        "type": "string",
+                        "description": "Сортировка",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Кол-во элементов на странице",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор keyset-пагинации",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Считать общее кол-во в keyset-режиме",
+                        "name": "with_total",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешный ответ",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ItemFeedResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный фильтр",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Создает новый элемент коллекции. В possible_duplicates возвращаются уже существующие элементы\nтого же типа с похожим названием и годом - клиент может предупредить о дубликате.\nЭлементы обычных пользователей создаются приватными, is_public = true отправляет элемент на модерацию",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Создать элемент коллекции",
+                "parameters": [
+                    {
+                        "description": "Данные для создания элемента",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateCollectionItemInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Элемент создан, возвращает ID и возможные дубликаты",
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateItemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные входные данные",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/items/duplicates": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Проверка перед созданием элемента: элементы того же типа с похожим нормализованным названием и годом",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Найти возможные дубликаты",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Название",
+                        "name": "title",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "books",
+                            "anime",
+                            "series",
+                            "movies"
+                        ],
+                        "type": "string",
+                        "description": "Тип элемента",
+                        "name": "type",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Год выхода",
+                        "name": "year",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Возможные дубликаты",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.DuplicateCandidate"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/items/import": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Создает публичный элемент каталога с названием, описанием, годом, обложкой и жанрами из записи провайдера.\nВнешний ID сохраняется: повторный импорт той же записи возвращает существующий элемент с кодом 200",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Импортировать элемент из внешнего каталога",
+                "parameters": [
+                    {
+                        "description": "Запись внешнего каталога",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ImportItemInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Запись уже импортирована",
+                        "schema": {
+                            "$ref": "#/definitions/models.ItemImportResult"
+                        }
+                    },
+                    "201": {
+                        "description": "Элемент создан",
+                        "schema": {
+                            "$ref": "#/definitions/models.ItemImportResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Неизвестный провайдер или неверный внешний ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Запись не найдена во внешнем каталоге",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Внешний каталог недоступен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/items/lookup": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Поиск в OpenLibrary (книги по названию или ISBN), TMDB (фильмы и сериалы), AniList и Shikimori (аниме).\nПровайдеры опрашиваются параллельно, ошибка одного из них возвращается в errors и не прерывает поиск.\nУже импортированные записи содержат existing_item_id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Найти элемент во внешних каталогах",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Название",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISBN книги",
+                        "name": "isbn",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "books",
+                            "anime",
+                            "series",
+                            "movies"
+                        ],
+                        "type": "string",
+                        "description": "Тип элементов",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "openlibrary",
+                            "tmdb",
+                            "anilist",
+                            "shikimori"
+                        ],
+                        "type": "string",
+                        "description": "Только один провайдер",
+                        "name": "provider",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Результатов от каждого провайдера (по умолчанию 10, максимум 25)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Найденные записи",
+                        "schema": {
+                            "$ref": "#/definitions/models.LookupResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/items/suggest": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает элементы, название которых начинается с q или похоже на q, с учетом популярности. Результаты кешируются",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Автодополнение элементов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Начало названия",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "books",
+                            "anime",
+                            "series",
+                            "movies"
+                        ],
+                        "type": "string",
+                        "description": "Тип элементов",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Кол-во подсказок",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Подсказки",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ItemSuggestion"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/items/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Если элемент был слит с другим, отвечает 301 с адресом канонического элемента",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Получить элемент",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID элемента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Элемент",
+                        "schema": {
+                            "$ref": "#/definitions/models.CollectionItem"
+                        }
+                    },
+                    "301": {
+                        "description": "Элемент слит, Location - канонический элемент"
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Элемент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Автор меняет поля своего элемента, пока он не на проверке и не опубликован: до отправки, после отклонения\nили запроса правок. После изменения элемент можно снова отправить на проверку через /items/{id}/submit",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "moderation"
+                ],
+                "summary": "Изменить свой элемент",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID элемента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Изменения",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ItemChanges"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Измененный элемент",
+                        "schema": {
+                            "$ref": "#/definitions/models.CollectionItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные входные данные",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Элемент создан другим пользователем",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Элемент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Элемент на проверке или опубликован",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/items/{id}/cover": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Изображение jpeg, png или gif проверяется по содержимому, для него создаются миниатюры small, medium и large. Обложку публичного элемента меняет модератор, приватного - автор",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "images"
+                ],
+                "summary": "Загрузить обложку элемента",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID элемента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Изображение",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Загруженное изображение",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImageUpload"
+                        }
+                    },
+                    "400": {
+                        "description": "Файл не передан или не является изображением",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Элемент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Файл слишком большой",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/items/{id}/edits": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Правки элемента, сначала новые. Фильтр по статусу: pending, accepted, rejected",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "edits"
+                ],
+                "summary": "Предложенные правки элемента",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID элемента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "accepted",
+                            "rejected"
+                        ],
+                        "type": "string",
+                        "description": "Статус правки",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Кол-во элементов на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Правки",
+                        "schema": {
+                            "$ref": "#/definitions/handler.PaginatedEditSuggestionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный статус",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Элемент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Пользователь предлагает изменить поля публичного элемента каталога. Правка применяется после проверки модератором",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "edits"
+                ],
+                "summary": "Предложить правку элемента",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID элемента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Изменения",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.SuggestEditInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Правка создана",
+                        "schema": {
+                            "$ref": "#/definitions/models.EditSuggestion"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные входные данные",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Элемент не опубликован в каталоге",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Элемент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/items/{id}/episodes/{episode_id}/watched": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отмечает эпизод просмотренным и пересчитывает прогресс и статус элемента",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "episodes"
+                ],
+                "summary": "Отметить эпизод просмотренным",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID элемента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID эпизода",
+                        "name": "episode_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновленный прогресс",
+                        "schema": {
+                            "$ref": "#/definitions/models.ItemProgress"
+                        }
+                    },
+                    "404": {
+                        "description": "Эпизод не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Снимает отметку просмотра с эпизода и пересчитывает прогресс и статус элемента",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "episodes"
+                ],
+                "summary": "Снять отметку просмотра с эпизода",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID элемента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID эпизода",
+                        "name": "episode_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновленный прогресс",
+                        "schema": {
+                            "$ref": "#/definitions/models.ItemProgress"
+                        }
+                    },
+                    "404": {
+                        "description": "Эпизод не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/items/{id}/external-ids": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Внешние ID элемента",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID элемента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Записи внешних каталогов",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ExternalID"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/items/{id}/genres": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "taxonomy"
+                ],
+                "summary": "Получить жанры элемента",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID элемента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Жанры элемента",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Genre"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Элемент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Жанры публичных элементов задают модераторы, своих приватных элементов - создатель",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "taxonomy"
+                ],
+                "summary": "Задать жанры элемента",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID элемента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Slug жанров",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.SetGenresInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Жанры элемента",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Genre"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Элемент или жанр не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/items/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Принятые изменения элемента с автором и диффом полей, сначала новые",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "edits"
+                ],
+                "summary": "История изменений элемента",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID элемента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "История",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ItemRevision"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Элемент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/items/{id}/history/{revision_id}/revert": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Поля ревизии возвращаются к прежним значениям, откат сохраняется новой ревизией. Доступно модераторам и администраторам",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "edits"
+                ],
+                "summary": "Откатить изменение элемента",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID элемента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID ревизии",
+                        "name": "revision_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ревизия отката",
+                        "schema": {
+                            "$ref": "#/definitions/models.ItemRevision"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID ревизии",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Ревизия не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Откатывать нечего",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/items/{id}/locks": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Поля, которые обновление из внешних каталогов не меняет",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Закрепленные поля элемента",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID элемента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Закрепленные поля",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ItemFieldLock"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Элемент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/items/{id}/locks/{field}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Доступно модераторам. Обновление из внешних каталогов перестает менять поле",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Закрепить поле элемента",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID элемента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "title",
+                            "description",
+                            "cover_image",
+                            "release_year"
+                        ],
+                        "type": "string",
+                        "description": "Поле",
+                        "name": "field",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Поле закреплено",
+                        "schema": {
+                            "$ref": "#/definitions/models.ItemFieldLock"
+                        }
+                    },
+                    "400": {
+                        "description": "Поле нельзя закрепить",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нужна роль модератора",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Элемент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Доступно модераторам",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Открепить поле элемента",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID элемента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "title",
+                            "description",
+                            "cover_image",
+                            "release_year"
+                        ],
+                        "type": "string",
+                        "description": "Поле",
+                        "name": "field",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Поле откреплено",
+                        "schema": {
+                            "$ref": "#/definitions/handler.SuccessResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нужна роль модератора",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Поле не закреплено",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/items/{id}/merge": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Доступно администраторам. Переносит элементы коллекций, прогресс, оценки, теги, жанры и связи\nдубликата на канонический элемент в одной транзакции, удаляет дубликат и оставляет редирект со старого ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Слить дубликат с элементом",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID канонического элемента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "ID дубликата",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.MergeItemsInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Итог слияния",
+                        "schema": {
+                            "$ref": "#/definitions/models.ItemMergeResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Элементы нельзя слить",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Элемент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/items/{id}/moderation": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Доступно автору элемента и модераторам",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "moderation"
+                ],
+                "summary": "Журнал модерации элемента",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID элемента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Журнал",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ModerationDecision"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Элемент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/items/{id}/progress": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает прогресс и статус текущего пользователя по элементу",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "episodes"
+                ],
+                "summary": "Получить прогресс по элементу",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID элемента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Прогресс",
+                        "schema": {
+                            "$ref": "#/definitions/models.ItemProgress"
+                        }
+                    },
+                    "404": {
+                        "description": "Прогресс не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/items/{id}/rating": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Ставит оценку от 1 до 10. Если элемента нет в прогрессе пользователя, он добавляется в статусе planned",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "episodes"
+                ],
+                "summary": "Оценить элемент",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID элемента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Оценка",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.RateItemInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Прогресс с оценкой",
+                        "schema": {
+                            "$ref": "#/definitions/models.ItemProgress"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверная оценка",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Элемент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "episodes"
+                ],
+                "summary": "Снять оценку элемента",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID элемента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Прогресс без оценки",
+                        "schema": {
+                            "$ref": "#/definitions/models.ItemProgress"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Элемент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/items/{id}/refresh": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Доступно модераторам. Загружает запись основного внешнего каталога элемента и применяет изменения,\nкроме закрепленных полей. Изменения сохраняются ревизией с именем провайдера, ошибка каталога - в поле error",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "items"
+                ],
+                "summary": "Обновить элемент из внешнего каталога",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID элемента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Итог обновления",
+                        "schema": {
+                            "$ref": "#/definitions/models.MetadataRefreshResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Элемент не импортирован из внешнего каталога",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нужна роль модератора",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Элемент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/items/{id}/relations": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает связанные элементы (сиквелы, экранизации и т.д.) на заданную глубину и франшизы элемента",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "relations"
+                ],
+                "summary": "Получить граф связей элемента",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID элемента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Глубина обхода графа (1-5)",
+                        "name": "depth",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Граф связей",
+                        "schema": {
+                            "$ref": "#/definitions/models.RelationGraph"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Элемент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Создает направленную связь: элемент {id} является relation_type для target_item_id. Связи с публичными элементами создают только модераторы",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "relations"
+                ],
+                "summary": "Создать связь между элементами",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID элемента-источника",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные связи",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateRelationInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Связь создана",
+                        "schema": {
+                            "$ref": "#/definitions/models.ItemRelation"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные входные данные",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Элемент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Связь уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/items/{id}/relations/{relation_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет связь элемента. Связи с публичными элементами удаляют только модераторы",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "relations"
+                ],
+                "summary": "Удалить связь",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID элемента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID связи",
+                        "name": "relation_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Связь удалена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.SuccessResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Связь не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/items/{id}/seasons": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает сезоны сериала/аниме с эпизодами и отметками просмотра текущего пользователя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "episodes"
+                ],
+                "summary": "Получить сезоны элемента",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID элемента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Сезоны с эпизодами",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Season"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Элемент не поддерживает эпизоды",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Элемент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Создает сезон сериала/аниме вместе с эпизодами. Для публичных элементов доступно модераторам, для приватных - создателю",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "episodes"
+                ],
+                "summary": "Создать сезон",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID элемента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные сезона",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateSeasonInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Сезон создан",
+                        "schema": {
+                            "$ref": "#/definitions/models.Season"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные входные данные",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Элемент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Сезон уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/items/{id}/seasons/{season}/episodes": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Добавляет эпизод в сезон. Для публичных элементов доступно модераторам, для приватных - создателю",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "episodes"
+                ],
+                "summary": "Добавить эпизод",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID элемента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер сезона",
+                        "name": "season",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные эпизода",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.EpisodeInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Эпизод создан, возвращает ID",
+                        "schema": {
+                            "$ref": "#/definitions/handler.IDResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные входные данные",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Сезон не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Эпизод уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/items/{id}/seasons/{season}/watched": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отмечает все эпизоды сезона просмотренными и пересчитывает прогресс и статус элемента",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "episodes"
+                ],
+                "summary": "Отметить сезон просмотренным",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID элемента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер сезона",
+                        "name": "season",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновленный прогресс",
+                        "schema": {
+                            "$ref": "#/definitions/models.ItemProgress"
+                        }
+                    },
+                    "404": {
+                        "description": "Сезон не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Снимает отметки просмотра со всех эпизодов сезона и пересчитывает прогресс и статус элемента",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "episodes"
+                ],
+                "summary": "Снять отметку просмотра с сезона",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID элемента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер сезона",
+                        "name": "season",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновленный прогресс",
+                        "schema": {
+                            "$ref": "#/definitions/models.ItemProgress"
+                        }
+                    },
+                    "404": {
+                        "description": "Сезон не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/items/{id}/similar": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Элементы, которые часто встречаются в библиотеках вместе с этим элементом.\nПересчитываются фоновой задачей, новые элементы появляются не сразу",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recommendations"
+                ],
+                "summary": "Похожие элементы",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID элемента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Не показывать элементы из своей библиотеки",
+                        "name": "exclude_library",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Кол-во элементов",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Похожие элементы",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SimilarItem"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Элемент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/items/{id}/submit": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Автор отправляет свой элемент на проверку для публикации в каталоге, в том числе после отклонения или запроса правок",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "moderation"
+                ],
+                "summary": "Отправить элемент на модерацию",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID элемента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Элемент отправлен на проверку",
+                        "schema": {
+                            "$ref": "#/definitions/handler.SuccessResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Элемент создан другим пользователем",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Элемент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Элемент уже на проверке или опубликован",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/items/{id}/tags": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "taxonomy"
+                ],
+                "summary": "Получить свои теги элемента",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID элемента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Теги",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Tag"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Заменяет теги пользователя на элементе из его медиатеки. Отсутствующие теги создаются",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "taxonomy"
+                ],
+                "summary": "Задать свои теги элемента",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID элемента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Теги",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.SetTagsInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Теги элемента",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Tag"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные теги",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Элемент не в медиатеке пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/moderation/edits": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Правки, ожидающие проверки, сначала самые старые. Доступно модераторам и администраторам",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "edits"
+                ],
+                "summary": "Очередь правок",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Кол-во элементов на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Очередь",
+                        "schema": {
+                            "$ref": "#/definitions/handler.PaginatedEditSuggestionsResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/moderation/edits/{id}/accept": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Изменения применяются к элементу и сохраняются в истории, автор правки получает уведомление",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "edits"
+                ],
+                "summary": "Принять правку",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID правки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Комментарий",
+                        "name": "input",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handler.EditReviewInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ревизия",
+                        "schema": {
+                            "$ref": "#/definitions/models.ItemRevision"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные входные данные",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Правка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Правка уже рассмотрена или ничего не меняет",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/moderation/edits/{id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Элемент не меняется, автор правки получает уведомление с комментарием",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "edits"
+                ],
+                "summary": "Отклонить правку",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID правки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Комментарий",
+                        "name": "input",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handler.EditReviewInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Правка отклонена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные входные данные",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Правка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Правка уже рассмотрена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/moderation/items/{id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Элемент становится публичным элементом каталога, автор получает уведомление",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "moderation"
+                ],
+                "summary": "Одобрить элемент",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID элемента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Решение",
+                        "schema": {
+                            "$ref": "#/definitions/models.ModerationDecision"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Элемент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Элемент не ожидает проверки",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/moderation/items/{id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Элемент остается приватным, автор получает уведомление с причиной",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "moderation"
+                ],
+                "summary": "Отклонить элемент",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID элемента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Причина",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ModerationReasonInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Решение",
+                        "schema": {
+                            "$ref": "#/definitions/models.ModerationDecision"
+                        }
+                    },
+                    "400": {
+                        "description": "Не указана причина",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Элемент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Элемент не ожидает проверки",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/moderation/items/{id}/request-changes": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Автор получает уведомление с комментарием и может отправить элемент повторно",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "moderation"
+                ],
+                "summary": "Запросить правки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID элемента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Что нужно исправить",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ModerationReasonInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Решение",
+                        "schema": {
+                            "$ref": "#/definitions/models.ModerationDecision"
+                        }
+                    },
+                    "400": {
+                        "description": "Не указана причина",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Элемент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Элемент не ожидает проверки",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/moderation/queue": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Элементы, ожидающие проверки, сначала самые старые. Доступно модераторам и администраторам",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "moderation"
+                ],
+                "summary": "Очередь модерации",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Кол-во элементов на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Очередь",
+                        "schema": {
+                            "$ref": "#/definitions/handler.PaginatedModerationQueueResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Доступ запрещен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/search": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Полнотекстовый поиск по названию и описанию (русский и английский) с нечетким совпадением названия, ранжированием и подсветкой. Ищет среди публичных элементов и собственных элементов пользователя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Поиск по каталогу",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Поисковый запрос",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "books",
+                            "anime",
+                            "series",
+                            "movies"
+                        ],
+                        "type": "string",
+                        "description": "Тип элементов",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Кол-во элементов на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Результаты поиска",
+                        "schema": {
+                            "$ref": "#/definitions/handler.PaginatedSearchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stream": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Server-Sent Events. Типы событий: notifications.unread - число непрочитанных уведомлений,\ncollection.item_added, collection.updated, collection.comment - изменения коллекций пользователя\nи коллекций из параметра collections, import.progress - прогресс импорта.\nПосле переподключения клиенту стоит перечитать данные: пропущенные события не повторяются",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "stream"
+                ],
+                "summary": "Поток событий",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Чужие публичные коллекции, за которыми следить, через запятую (до 20)",
+                        "name": "collections",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Токен для клиентов, которые не могут передать заголовок Authorization (EventSource)",
+                        "name": "access_token",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Поток событий",
+                        "schema": {
+                            "$ref": "#/definitions/handler.StreamEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "Слишком много коллекций",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Коллекция закрыта",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Коллекция не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает теги пользователя с количеством использований",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "taxonomy"
+                ],
+                "summary": "Получить свои теги",
+                "responses": {
+                    "200": {
+                        "description": "Теги",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Tag"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/suggest": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает теги пользователя, начинающиеся с q, отсортированные по популярности",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "taxonomy"
+                ],
+                "summary": "Автодополнение тегов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Префикс тега",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Кол-во подсказок",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Подсказки",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Tag"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/avatar": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Изображение jpeg, png или gif проверяется по содержимому, для него создаются миниатюры small, medium и large",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "images"
+                ],
+                "summary": "Загрузить аватар",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Изображение",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Загруженное изображение",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImageUpload"
+                        }
+                    },
+                    "400": {
+                        "description": "Файл не передан или не является изображением",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Файл слишком большой",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/backup": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Копия всей библиотеки без потерь: коллекции с порядком элементов и заметками, свои элементы с сезонами и эпизодами,\nпрогресс, оценки, теги и отмеченные эпизоды. Формат - сжатый JSON, восстанавливается через POST /user/restore на любом сервере Memoria",
+                "produces": [
+                    "application/gzip"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Резервная копия библиотеки",
+                "responses": {
+                    "200": {
+                        "description": "Файл копии .memoria.json.gz",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/export": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Все коллекции пользователя с элементами, заметками, статусами и оценками. Форматы: csv, json, md (Markdown) и html (страница для печати)",
+                "produces": [
+                    "application/json",
+                    "text/plain",
+                    "text/html"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Выгрузить библиотеку",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "json",
+                            "md",
+                            "html"
+                        ],
+                        "type": "string",
+                        "description": "Формат (по умолчанию json)",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Файл выгрузки",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Неизвестный формат",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/feed": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "События пользователей, на которых подписан текущий пользователь: что они добавили в публичные коллекции, закончили, оценили и какие коллекции опубликовали",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "social"
+                ],
+                "summary": "Лента подписок",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Курсор keyset-пагинации",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Кол-во событий на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "События, сначала новые",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ActivityCursorResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Невалидный курсор",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/follow-requests": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Входящие запросы на подписку на приватный аккаунт",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "social"
+                ],
+                "summary": "Запросы на подписку",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Кол-во запросов на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Запросы, сначала новые",
+                        "schema": {
+                            "$ref": "#/definitions/handler.PaginatedFollowUsersResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/follow-requests/{user_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "social"
+                ],
+                "summary": "Отклонить запрос на подписку",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя, отправившего запрос",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Запрос отклонен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.SuccessResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Запрос не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/follow-requests/{user_id}/accept": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "social"
+                ],
+                "summary": "Принять запрос на подписку",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя, отправившего запрос",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Запрос принят",
+                        "schema": {
+                            "$ref": "#/definitions/handler.SuccessResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Запрос не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/followers/{user_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "social"
+                ],
+                "summary": "Удалить подписчика",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID подписчика",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Подписчик удален",
+                        "schema": {
+                            "$ref": "#/definitions/handler.SuccessResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Подписчик не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/next-episodes": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает следующий непросмотренный эпизод для каждого сериала/аниме в процессе просмотра",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "episodes"
+                ],
+                "summary": "Следующие эпизоды к просмотру",
+                "responses": {
+                    "200": {
+                        "description": "Следующие эпизоды",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.NextEpisode"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/notification-preferences": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Канал для каждого типа уведомлений: in_app - только в приложении, off - не уведомлять,\nимя канала доставки (email) - в приложении и через этот канал",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Настройки уведомлений",
+                "responses": {
+                    "200": {
+                        "description": "Настройки",
+                        "schema": {
+                            "$ref": "#/definitions/handler.NotificationPreferencesResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Меняет каналы перечисленных типов, остальные типы не меняются",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Изменить настройки уведомлений",
+                "parameters": [
+                    {
+                        "description": "Настройки",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.NotificationPreferencesInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Настройки после изменения",
+                        "schema": {
+                            "$ref": "#/definitions/handler.NotificationPreferencesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неизвестный тип или недоступный канал",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/notifications": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Уведомления пользователя, сначала новые",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Получить уведомления",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Только непрочитанные",
+                        "name": "unread",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Кол-во уведомлений на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Уведомления",
+                        "schema": {
+                            "$ref": "#/definitions/handler.PaginatedNotificationsResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/notifications/read-all": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Прочитать все уведомления",
+                "responses": {
+                    "200": {
+                        "description": "Сколько уведомлений было непрочитано",
+                        "schema": {
+                            "$ref": "#/definitions/handler.MarkAllReadResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/notifications/unread-count": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Количество непрочитанных уведомлений",
+                "responses": {
+                    "200": {
+                        "description": "Количество",
+                        "schema": {
+                            "$ref": "#/definitions/models.UnreadNotifications"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/notifications/{id}/read": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Прочитать уведомление",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID уведомления",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Уведомление прочитано",
+                        "schema": {
+                            "$ref": "#/definitions/handler.SuccessResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Уведомление не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/privacy": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Подписка на приватный аккаунт требует подтверждения, его подписки и активность видят только подписчики. При открытии аккаунта ожидающие запросы принимаются",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "social"
+                ],
+                "summary": "Приватность аккаунта",
+                "parameters": [
+                    {
+                        "description": "Приватность",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.AccountPrivacyInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Приватность изменена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные данные",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/recommendations": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Элементы, похожие на элементы вашей библиотеки, с учетом оценок. Элементы из библиотеки не рекомендуются,\nbecause_of - элементы библиотеки, сильнее всего повлиявшие на рекомендацию",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recommendations"
+                ],
+                "summary": "Рекомендации для вас",
+                "parameters": [
+                    {
+                        "enum": [
+                            "books",
+                            "anime",
+                            "series",
+                            "movies"
+                        ],
+                        "type": "string",
+                        "description": "Тип элементов",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Кол-во элементов",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Рекомендации",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.RecommendedItem"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неизвестный тип",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Копия проверяется целиком и восстанавливается одной транзакцией: при любой ошибке библиотека не меняется.\nЭлементы каталога ищутся по ID, внешним ID и названию, отсутствующие создаются своими элементами. Коллекции и свои элементы получают новые ID, соответствие - в ответе.\nПри совпадении коллекции (по ID или названию и типу) или своего элемента: skip - оставить существующие, overwrite - заменить данными копии, duplicate - создать рядом копию",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Восстановить библиотеку из копии",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Файл копии (.memoria.json.gz или .json)",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "skip",
+                            "overwrite",
+                            "duplicate"
+                        ],
+                        "type": "string",
+                        "default": "skip",
+                        "description": "Что делать при совпадении",
+                        "name": "mode",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Итог восстановления",
+                        "schema": {
+                            "$ref": "#/definitions/models.RestoreResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Файл не является копией, версия не поддерживается или копия содержит ошибки",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Файл слишком большой",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/saved": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Коллекции, сохраненные текущим пользователем, сначала сохраненные последними. Коллекции, которые владелец закрыл, не показываются",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "engagement"
+                ],
+                "summary": "Сохраненные коллекции",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Кол-во коллекций на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Сохраненные коллекции",
+                        "schema": {
+                            "$ref": "#/definitions/handler.PaginatedSavedCollectionsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/account": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Полностью удаляет аккаунт текущего пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Удалить аккаунт",
+                "responses": {
+                    "200": {
+                        "description": "Аккаунт успешно удален",
+                        "schema": {
+                            "$ref": "#/definitions/handler.MessageResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/github/link": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Привязывает GitHub аккаунт к текущему пользователю",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Привязать GitHub аккаунт",
+                "parameters": [
+                    {
+                        "description": "Код авторизации GitHub",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.LinkGitHubInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "GitHub аккаунт успешно привязан",
+                        "schema": {
+                            "$ref": "#/definitions/handler.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные входные данные",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "GitHub аккаунт уже привязан к другому пользователю",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/github/unlink": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отвязывает GitHub аккаунт от текущего пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Отвязать GitHub аккаунт",
+                "parameters": [
+                    {
+                        "description": "Пароль для подтверждения",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.UnlinkGitHubInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "GitHub аккаунт успешно отвязан",
+                        "schema": {
+                            "$ref": "#/definitions/handler.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные входные данные",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/password": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Изменяет пароль текущего пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Изменить пароль",
+                "parameters": [
+                    {
+                        "description": "Данные для изменения пароля",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ChangePasswordInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пароль успешно изменен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные входные данные",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/profile": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает профиль текущего аутентифицированного пользователя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Получить профиль пользователя",
+                "responses": {
+                    "200": {
+                        "description": "Профиль пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/handler.UserProfileResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Обновляет данные профиля текущего пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Обновить профиль пользователя",
+                "parameters": [
+                    {
+                        "description": "Данные для обновления профиля",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.UpdateUserProfileInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Профиль успешно обновлен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные входные данные",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Email уже используется",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Имя, аватар, количество подписчиков и подписок, а также подписка текущего пользователя в обе стороны",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "social"
+                ],
+                "summary": "Профиль пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Профиль",
+                        "schema": {
+                            "$ref": "#/definitions/models.PublicProfile"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/activity": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "События пользователя: добавление в публичные коллекции, завершение, оценки и публикация коллекций. Активность приватного аккаунта видна только подписчикам",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "social"
+                ],
+                "summary": "Активность пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор keyset-пагинации",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Кол-во событий на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "События, сначала новые",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ActivityCursorResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Невалидный курсор",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Аккаунт приватный",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/follow": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Подписка на пользователя. На приватный аккаунт отправляется запрос (статус pending), подписка начнет действовать после его принятия. Повторный запрос возвращает текущий статус",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "social"
+                ],
+                "summary": "Подписаться",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Подписка или запрос на подписку",
+                        "schema": {
+                            "$ref": "#/definitions/models.Follow"
+                        }
+                    },
+                    "400": {
+                        "description": "Нельзя подписаться на себя",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отменяет подписку или неподтвержденный запрос на подписку",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "social"
+                ],
+                "summary": "Отписаться",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Подписка отменена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.SuccessResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Подписки нет",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/followers": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Подписчики приватного аккаунта видны только ему самому и его подписчикам",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "social"
+                ],
+                "summary": "Подписчики пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Кол-во пользователей на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Подписчики, сначала новые",
+                        "schema": {
+                            "$ref": "#/definitions/handler.PaginatedFollowUsersResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Аккаунт приватный",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/following": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Подписки приватного аккаунта видны только ему самому и его подписчикам",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "social"
+                ],
+                "summary": "Подписки пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Кол-во пользователей на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Подписки, сначала новые",
+                        "schema": {
+                            "$ref": "#/definitions/handler.PaginatedFollowUsersResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Аккаунт приватный",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Вебхуки",
+                "responses": {
+                    "200": {
+                        "description": "Вебхуки",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Webhook"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "События отправляются POST-запросом с JSON {id, type, user_id, created_at, data}.\nЗаголовок X-Memoria-Signature: sha256=HMAC-SHA256(secret, X-Memoria-Timestamp + \".\" + тело) в hex.\nНеудачные отправки (не 2xx) повторяются с нарастающей паузой. Секрет показывается только в этом ответе.\nИмпорт и восстановление из резервной копии событий не создают",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Создать вебхук",
+                "parameters": [
+                    {
+                        "description": "Вебхук",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Вебхук с секретом",
+                        "schema": {
+                            "$ref": "#/definitions/models.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный адрес, события или превышен лимит",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/events": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "item.added - элемент добавлен в коллекцию, item.status_changed - изменился статус просмотра,\nreview.posted - оценка элемента или отзыв при добавлении в коллекцию",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "События вебхуков",
+                "responses": {
+                    "200": {
+                        "description": "События",
+                        "schema": {
+                            "$ref": "#/definitions/handler.WebhookEventTypesResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Вебхук",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID вебхука",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Вебхук",
+                        "schema": {
+                            "$ref": "#/definitions/models.Webhook"
+                        }
+                    },
+                    "404": {
+                        "description": "Вебхук не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Меняет адрес, описание, события и включенность. Выключенный вебхук копит события и отправит их после включения",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Изменить вебхук",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID вебхука",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Вебхук",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Вебхук после изменения",
+                        "schema": {
+                            "$ref": "#/definitions/models.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный адрес или события",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Вебхук не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет вебхук вместе с журналом и неотправленными событиями",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Удалить вебхук",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID вебхука",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Вебхук удален",
+                        "schema": {
+                            "$ref": "#/definitions/handler.SuccessResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Вебхук не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отправки событий и ответы получателя, сначала новые",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Журнал отправок вебхука",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID вебхука",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "sent",
+                            "failed"
+                        ],
+                        "type": "string",
+                        "description": "Статус отправки",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Кол-во отправок на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Журнал",
+                        "schema": {
+                            "$ref": "#/definitions/handler.PaginatedWebhookDeliveriesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неизвестный статус",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Вебхук не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/test": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Сразу отправляет событие webhook.test и возвращает запись журнала с ответом получателя.\nТестовое событие не повторяется при ошибке и уходит даже на выключенный вебхук",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Отправить тестовое событие",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID вебхука",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Результат отправки",
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookDelivery"
+                        }
+                    },
+                    "404": {
+                        "description": "Вебхук не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handler.AccountPrivacyInput": {
+            "type": "object",
+            "required": [
+                "is_private"
+            ],
+            "properties": {
+                "is_private": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handler.ActivityCursorResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Activity"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/pagination.CursorPagination"
+                }
+            }
+        },
+        "handler.AddFranchiseItemInput": {
+            "type": "object",
+            "required": [
+                "item_id"
+            ],
+            "properties": {
+                "item_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.AddItemInput": {
+            "type": "object",
+            "required": [
+                "item_id",
+                "user_review"
+            ],
+            "properties": {
+                "item_id": {
+                    "type": "string"
+                },
+                "user_review": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.ChangePasswordInput": {
+            "type": "object",
+            "required": [
+                "current_password",
+                "new_password"
+            ],
+            "properties": {
+                "current_password": {
+                    "type": "string"
+                },
+                "new_password": {
+                    "type": "string",
+                    "minLength": 8
+                }
+            }
+        },
+        "handler.ChartResponse": {
+            "type": "object",
+            "properties": {
+                "computed_at": {
+                    "description": "ComputedAt - время последнего пересчета чартов",
+                    "type": "string"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ChartEntry"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/pagination.PaginationResponse"
+                }
+            }
+        },
+        "handler.CollectionResponse": {
+            "type": "object",
+            "properties": {
+                "cover_image": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_custom": {
+                    "type": "boolean"
+                },
+                "is_public": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.CollectionVisibilityInput": {
+            "type": "object",
+            "required": [
+                "is_public"
+            ],
+            "properties": {
+                "is_public": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handler.CommentInput": {
+            "type": "object",
+            "required": [
+                "body"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "example": "Отличная подборка!"
+                },
+                "parent_id": {
+                    "description": "ParentID - комментарий, на который дается ответ",
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.CreateCollectionInput": {
+            "type": "object",
+            "required": [
+                "name",
+                "type"
+            ],
+            "properties": {
+                "cover_image": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "is_custom": {
+                    "type": "boolean"
+                },
+                "is_public": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.CreateCollectionItemInput": {
+            "type": "object",
+            "required": [
+                "title",
+                "type"
+            ],
+            "properties": {
+                "cover_image": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "is_custom": {
+                    "type": "boolean"
+                },
+                "is_public": {
+                    "type": "boolean"
+                },
+                "release_year": {
+                    "type": "integer",
+                    "example": 1999
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.CreateFranchiseInput": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.CreateGenreInput": {
+            "type": "object",
+            "required": [
+                "name",
+                "slug"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "Научная фантастика"
+                },
+                "slug": {
+                    "type": "string",
+                    "example": "sci-fi"
+                }
+            }
+        },
+        "handler.CreateItemResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "possible_duplicates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DuplicateCandidate"
+                    }
+                }
+            }
+        },
+        "handler.CreateRelationInput": {
+            "type": "object",
+            "required": [
+                "relation_type",
+                "target_item_id"
+            ],
+            "properties": {
+                "relation_type": {
+                    "type": "string",
+                    "enum": [
+                        "sequel",
+                        "prequel",
+                        "adaptation",
+                        "spin_off",
+                        "same_franchise",
+                        "remake"
+                    ]
+                },
+                "target_item_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.CreateSeasonInput": {
+            "type": "object",
+            "properties": {
+                "episodes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.EpisodeInput"
+                    }
+                },
+                "number": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.EditCommentInput": {
+            "type": "object",
+            "required": [
+                "body"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.EditReviewInput": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string",
+                    "example": "Спасибо, исправлено"
+                }
+            }
+        },
+        "handler.EpisodeInput": {
+            "type": "object",
+            "required": [
+                "number"
+            ],
+            "properties": {
+                "air_date": {
+                    "type": "string",
+                    "example": "2024-01-31"
+                },
+                "number": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "runtime_minutes": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.IDResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.ImportItemInput": {
+            "type": "object",
+            "required": [
+                "external_id",
+                "provider"
+            ],
+            "properties": {
+                "external_id": {
+                    "type": "string",
+                    "example": "OL893415W"
+                },
+                "provider": {
+                    "type": "string",
+                    "example": "openlibrary"
+                }
+            }
+        },
+        "handler.ItemFeedResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FeedItem"
+                    }
+                },
+                "facets": {
+                    "$ref": "#/definitions/models.ItemFacets"
+                },
+                "pagination": {
+                    "$ref": "#/definitions/pagination.PaginationResponse"
+                }
+            }
+        },
+        "handler.LinkGitHubInput": {
+            "type": "object",
+            "required": [
+                "github_code"
+            ],
+            "properties": {
+                "github_code": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.MarkAllReadResponse": {
+            "type": "object",
+            "properties": {
+                "marked": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.MergeItemsInput": {
+            "type": "object",
+            "required": [
+                "duplicate_id"
+            ],
+            "properties": {
+                "duplicate_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.MessageResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.ModerationReasonInput": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "Укажите год выхода и обложку"
+                }
+            }
+        },
+        "handler.NotificationPreferencesInput": {
+            "type": "object",
+            "required": [
+                "preferences"
+            ],
+            "properties": {
+                "preferences": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.NotificationPreference"
+                    }
+                }
+            }
+        },
+        "handler.NotificationPreferencesResponse": {
+            "type": "object",
+            "properties": {
+                "channels": {
+                    "description": "Channels - значения, которые можно выбрать для типа",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "in_app",
+                        "off",
+                        "email"
+                    ]
+                },
+                "preferences": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.NotificationPreference"
+                    }
+                }
+            }
+        },
+        "handler.PaginatedCollectionsResponse": {
+            "type": "object",
+            "properties": {
+                "collections": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.CollectionResponse"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "per_page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.PaginatedCommentsResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CollectionComment"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/pagination.PaginationResponse"
+                }
+            }
+        },
+        "handler.PaginatedEditSuggestionsResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EditSuggestion"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/pagination.PaginationResponse"
+                }
+            }
+        },
+        "handler.PaginatedFollowUsersResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FollowUser"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/pagination.PaginationResponse"
+                }
+            }
+        },
+        "handler.PaginatedImportRowsResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ImportRow"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/pagination.PaginationResponse"
+                }
+            }
+        },
+        "handler.PaginatedImportsResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ImportJob"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/pagination.PaginationResponse"
+                }
+            }
+        },
+        "handler.PaginatedModerationQueueResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CollectionItem"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/pagination.PaginationResponse"
+                }
+            }
+        },
+        "handler.PaginatedNotificationsResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Notification"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/pagination.PaginationResponse"
+                }
+            }
+        },
+        "handler.PaginatedSavedCollectionsResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SavedCollection"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/pagination.PaginationResponse"
+                }
+            }
+        },
+        "handler.PaginatedSearchResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SearchResult"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/pagination.PaginationResponse"
+                }
+            }
+        },
+        "handler.PaginatedWebhookDeliveriesResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.WebhookDelivery"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/pagination.PaginationResponse"
+                }
+            }
+        },
+        "handler.RateItemInput": {
+            "type": "object",
+            "required": [
+                "rating"
+            ],
+            "properties": {
+                "rating": {
+                    "type": "integer",
+                    "example": 8
+                }
+            }
+        },
+        "handler.ResolveImportRowInput": {
+            "type": "object",
+            "required": [
+                "item_id"
+            ],
+            "properties": {
+                "item_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.SetGenresInput": {
+            "type": "object",
+            "properties": {
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "drama",
+                        "sci-fi"
+                    ]
+                }
+            }
+        },
+        "handler.SetTagsInput": {
+            "type": "object",
+            "properties": {
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "любимое",
+                        "перечитать"
+                    ]
+                }
+            }
+        },
+        "handler.StreamEvent": {
+            "type": "object",
+            "properties": {
+                "collection_id": {
+                    "type": "string"
+                },
+                "data": {
+                    "type": "object"
+                }
+            }
+        },
+        "handler.SuccessResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.SuggestEditInput": {
+            "type": "object",
+            "required": [
+                "changes"
+            ],
+            "properties": {
+                "changes": {
+                    "$ref": "#/definitions/models.ItemChanges"
+                },
+                "comment": {
+                    "type": "string",
+                    "example": "Опечатка в названии"
+                }
+            }
+        },
+        "handler.UnlinkGitHubInput": {
+            "type": "object",
+            "required": [
+                "password"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.UpdateUserProfileInput": {
+            "type": "object",
+            "properties": {
+                "avatar_url": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.UserProfileResponse": {
+            "type": "object",
+            "properties": {
+                "avatar_url": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "github_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.WebhookEventTypesResponse": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "item.added",
+                        "item.status_changed",
+                        "review.posted"
+                    ]
+                }
+            }
+        },
+        "handler.signInInput": {
+            "type": "object",
+            "required": [
+                "mail",
+                "password"
+            ],
+            "properties": {
+                "mail": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.signInResponse": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/models.UserResponse"
+                }
+            }
+        },
+        "handler.signUpInput": {
+            "type": "object",
+            "required": [
+                "mail",
+                "name",
+                "password"
+            ],
+            "properties": {
+                "mail": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "importer.Record": {
+            "type": "object",
+            "properties": {
+                "creators": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "external_ids": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "line": {
+                    "description": "Line - номер строки или позиция записи в файле, для отчета о несопоставленных строках",
+                    "type": "integer"
+                },
+                "lists": {
+                    "description": "Lists - полки и списки, для каждого создается коллекция",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "progress": {
+                    "type": "integer"
+                },
+                "rating": {
+                    "description": "Rating - оценка по шкале 1-10",
+                    "type": "integer"
+                },
+                "review": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Activity": {
+            "type": "object",
+            "properties": {
+                "collection": {
+                    "$ref": "#/definitions/models.ActivityCollection"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "item": {
+                    "$ref": "#/definitions/models.ActivityItem"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "item_rated"
+                },
+                "user": {
+                    "$ref": "#/definitions/models.UserSummary"
+                }
+            }
+        },
+        "models.ActivityCollection": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ActivityItem": {
+            "type": "object",
+            "properties": {
+                "cover_image": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ChartEntry": {
+            "type": "object",
+            "properties": {
+                "avg_rating": {
+                    "type": "number"
+                },
+                "collectors": {
+                    "description": "Collectors - у скольких пользователей элемент в коллекциях",
+                    "type": "integer"
+                },
+                "item": {
+                    "$ref": "#/definitions/models.CollectionItem"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "ratings_count": {
+                    "type": "integer"
+                },
+                "recent_adds": {
+                    "description": "RecentAdds - сколько пользователей добавили элемент за окно trending",
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.CollectionComment": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "$ref": "#/definitions/models.UserSummary"
+                },
+                "body": {
+                    "description": "Body пуст у удаленного комментария, он остается в ветке ради ответов на него",
+                    "type": "string"
+                },
+                "collection_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "edited_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_deleted": {
+                    "type": "boolean"
+                },
+                "parent_id": {
+                    "type": "integer"
+                },
+                "replies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CollectionComment"
+                    }
+                }
+            }
+        },
+        "models.CollectionEngagement": {
+            "type": "object",
+            "properties": {
+                "collection_id": {
+                    "type": "string"
+                },
+                "comments_count": {
+                    "type": "integer"
+                },
+                "liked": {
+                    "type": "boolean"
+                },
+                "likes_count": {
+                    "type": "integer"
+                },
+                "saved": {
+                    "type": "boolean"
+                },
+                "saves_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CollectionItem": {
+            "type": "object",
+            "properties": {
+                "cover_image": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "creator_id": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_custom": {
+                    "type": "boolean"
+                },
+                "is_public": {
+                    "type": "boolean"
+                },
+                "moderation_status": {
+                    "description": "Статус модерации: приватный элемент попадает в публичный каталог только после одобрения",
+                    "type": "string"
+                },
+                "release_year": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DuplicateCandidate": {
+            "type": "object",
+            "properties": {
+                "item": {
+                    "$ref": "#/definitions/models.CollectionItem"
+                },
+                "similarity": {
+                    "description": "похожесть нормализованных названий 0..1",
+                    "type": "number"
+                }
+            }
+        },
+        "models.EditSuggestion": {
+            "type": "object",
+            "properties": {
+                "author_id": {
+                    "type": "integer"
+                },
+                "changes": {
+                    "$ref": "#/definitions/models.ItemChanges"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "item_id": {
+                    "type": "string"
+                },
+                "review_comment": {
+                    "type": "string"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "reviewer_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Episode": {
+            "type": "object",
+            "properties": {
+                "air_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "number": {
+                    "type": "integer"
+                },
+                "runtime_minutes": {
+                    "type": "integer"
+                },
+                "season_id": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "watched": {
+                    "description": "Просмотрен ли эпизод текущим пользователем",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.ExternalID": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "external_id": {
+                    "type": "string"
+                },
+                "imported_by": {
+                    "type": "integer"
+                },
+                "item_id": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "refresh_error": {
+                    "type": "string"
+                },
+                "refreshed_at": {
+                    "description": "RefreshedAt - время последнего обновления из каталога, RefreshError - его ошибка",
+                    "type": "string"
+                }
+            }
+        },
+        "models.FacetValue": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FeedItem": {
+            "type": "object",
+            "properties": {
+                "avg_rating": {
+                    "type": "number"
+                },
+                "cover_image": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "creator_id": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_custom": {
+                    "type": "boolean"
+                },
+                "is_public": {
+                    "type": "boolean"
+                },
+                "moderation_status": {
+                    "description": "Статус модерации: приватный элемент попадает в публичный каталог только после одобрения",
+                    "type": "string"
+                },
+                "popularity": {
+                    "description": "кол-во коллекций с элементом",
+                    "type": "integer"
+                },
+                "ratings_count": {
+                    "type": "integer"
+                },
+                "release_year": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FieldChange": {
+            "type": "object",
+            "properties": {
+                "new": {},
+                "old": {}
+            }
+        },
+        "models.Follow": {
+            "type": "object",
+            "properties": {
+                "followee_id": {
+                    "type": "integer"
+                },
+                "follower_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "pending"
+                }
+            }
+        },
+        "models.FollowUser": {
+            "type": "object",
+            "properties": {
+                "accepted_at": {
+                    "type": "string"
+                },
+                "avatar_url": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_private": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "accepted"
+                }
+            }
+        },
+        "models.Franchise": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CollectionItem"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Genre": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ImageUpload": {
+            "type": "object",
+            "properties": {
+                "content_type": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "height": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "owner_id": {
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "target_id": {
+                    "type": "string"
+                },
+                "target_type": {
+                    "type": "string"
+                },
+                "thumbnails": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "url": {
+                    "type": "string",
+                    "example": "http://localhost:3000/api/v1/images/0b6c1f9e-3c55-4a3e-9a43-1f3f9f7a1c2d"
+                },
+                "width": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ImportJob": {
+            "type": "object",
+            "properties": {
+                "create_custom": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_rows": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "failed_rows": {
+                    "type": "integer"
+                },
+                "filename": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string",
+                    "example": "goodreads"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "matched_rows": {
+                    "type": "integer"
+                },
+                "processed_rows": {
+                    "type": "integer"
+                },
+                "progress": {
+                    "description": "процент обработанных строк",
+                    "type": "number",
+                    "example": 42.5
+                },
+                "resolved_rows": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "running"
+                },
+                "total_rows": {
+                    "type": "integer"
+                },
+                "unmatched_rows": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ImportRow": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "item_id": {
+                    "type": "string"
+                },
+                "job_id": {
+                    "type": "string"
+                },
+                "line": {
+                    "type": "integer"
+                },
+                "match_method": {
+                    "type": "string"
+                },
+                "record": {
+                    "$ref": "#/definitions/importer.Record"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ItemChanges": {
+            "type": "object",
+            "properties": {
+                "cover_image": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "release_year": {
+                    "type": "integer",
+                    "example": 1965
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Dune"
+                }
+            }
+        },
+        "models.ItemFacets": {
+            "type": "object",
+            "properties": {
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FacetValue"
+                    }
+                },
+                "sources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FacetValue"
+                    }
+                },
+                "types": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FacetValue"
+                    }
+                }
+            }
+        },
+        "models.ItemFieldLock": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "example": "title"
+                },
+                "item_id": {
+                    "type": "string"
+                },
+                "locked_at": {
+                    "type": "string"
+                },
+                "locked_by": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ItemImportResult": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "boolean"
+                },
+                "item_id": {
+                    "type": "string"
+                },
+                "possible_duplicates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DuplicateCandidate"
+                    }
+                }
+            }
+        },
+        "models.ItemMergeResult": {
+            "type": "object",
+            "properties": {
+                "canonical_id": {
+                    "type": "string"
+                },
+                "duplicate_id": {
+                    "type": "string"
+                },
+                "merged_at": {
+                    "type": "string"
+                },
+                "merged_by": {
+                    "type": "integer"
+                },
+                "merged_watched_episodes": {
+                    "description": "отметки о просмотре, перенесенные на совпадающие эпизоды",
+                    "type": "integer"
+                },
+                "moved_assignments": {
+                    "description": "перенесено элементов коллекций",
+                    "type": "integer"
+                },
+                "moved_episodes": {
+                    "description": "перенесено эпизодов в совпадающие сезоны",
+                    "type": "integer"
+                },
+                "moved_progress": {
+                    "description": "перенесено записей прогресса и оценок",
+                    "type": "integer"
+                },
+                "moved_seasons": {
+                    "description": "перенесено сезонов, которых не было у канонического элемента",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ItemProgress": {
+            "type": "object",
+            "properties": {
+                "finished_at": {
+                    "type": "string"
+                },
+                "item_id": {
+                    "type": "string"
+                },
+                "progress": {
+                    "type": "integer"
+                },
+                "rating": {
+                    "description": "Оценка пользователя 1-10",
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "total_episodes": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ItemRelation": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "relation_type": {
+                    "type": "string"
+                },
+                "source_item_id": {
+                    "type": "string"
+                },
+                "target_item_id": {
+                    "type": "string"
                 }
             }
-        }
-    },
-    "definitions": {
-        "handler.AddItemInput": {
+        },
+        "models.ItemRevision": {
             "type": "object",
-            "required": [
-                "item_id",
-                "user_review"
-            ],
             "properties": {
+                "author_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "diff": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.FieldChange"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
                 "item_id": {
                     "type": "string"
                 },
-                "user_review": {
+                "provider": {
+                    "description": "Внешний каталог, если элемент обновлен автоматически",
                     "type": "string"
+                },
+                "reverted_revision_id": {
+                    "type": "integer"
+                },
+                "suggestion_id": {
+                    "type": "integer"
                 }
             }
         },
-        "handler.ChangePasswordInput": {
+        "models.ItemSuggestion": {
             "type": "object",
-            "required": [
-                "current_password",
-                "new_password"
-            ],
             "properties": {
-                "current_password": {
+                "cover_image": {
                     "type": "string"
                 },
-                "new_password": {
+                "id": {
+                    "type": "string"
+                },
+                "is_custom": {
+                    "type": "boolean"
+                },
+                "popularity": {
+                    "description": "в скольких коллекциях есть элемент",
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LookupResponse": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.LookupResult"
+                    }
+                }
+            }
+        },
+        "models.LookupResult": {
+            "type": "object",
+            "properties": {
+                "authors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "cover_url": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "existing_item_id": {
+                    "type": "string"
+                },
+                "external_id": {
                     "type": "string",
-                    "minLength": 8
+                    "example": "OL893415W"
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "provider": {
+                    "type": "string",
+                    "example": "openlibrary"
+                },
+                "release_year": {
+                    "type": "integer",
+                    "example": 1965
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Dune"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "books"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MetadataRefreshResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "external_id": {
+                    "type": "string"
+                },
+                "item_id": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "revision": {
+                    "$ref": "#/definitions/models.ItemRevision"
+                },
+                "skipped_fields": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.ModerationDecision": {
+            "type": "object",
+            "properties": {
+                "actor_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "decision": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "item_id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
                 }
             }
         },
-        "handler.CollectionItemResponse": {
+        "models.NextEpisode": {
             "type": "object",
             "properties": {
                 "cover_image": {
                     "type": "string"
                 },
-                "creator_id": {
+                "episode": {
+                    "$ref": "#/definitions/models.Episode"
+                },
+                "item_id": {
+                    "type": "string"
+                },
+                "item_title": {
+                    "type": "string"
+                },
+                "item_type": {
+                    "type": "string"
+                },
+                "season_number": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Notification": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "read_at": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.NotificationPreference": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "type": "string",
+                    "example": "in_app"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "new_follower"
+                }
+            }
+        },
+        "models.PublicProfile": {
+            "type": "object",
+            "properties": {
+                "avatar_url": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "follow_status": {
+                    "description": "FollowStatus - подписка текущего пользователя: accepted, pending или пусто",
+                    "type": "string",
+                    "example": "accepted"
+                },
+                "followers_count": {
+                    "type": "integer"
+                },
+                "following_count": {
+                    "type": "integer"
+                },
+                "follows_you": {
+                    "description": "FollowsYou - подписан ли пользователь на текущего",
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_private": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RecommendationSource": {
+            "type": "object",
+            "properties": {
+                "item_id": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RecommendedItem": {
+            "type": "object",
+            "properties": {
+                "because_of": {
+                    "description": "BecauseOf - элементы библиотеки пользователя, сильнее всего повлиявшие на рекомендацию",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RecommendationSource"
+                    }
+                },
+                "item": {
+                    "$ref": "#/definitions/models.CollectionItem"
+                },
+                "score": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.RelationGraph": {
+            "type": "object",
+            "properties": {
+                "depth": {
+                    "type": "integer"
+                },
+                "edges": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ItemRelation"
+                    }
+                },
+                "franchises": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Franchise"
+                    }
+                },
+                "nodes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RelationGraphNode"
+                    }
+                },
+                "root_item_id": {
                     "type": "string"
+                }
+            }
+        },
+        "models.RelationGraphNode": {
+            "type": "object",
+            "properties": {
+                "depth": {
+                    "type": "integer"
+                },
+                "item": {
+                    "$ref": "#/definitions/models.CollectionItem"
+                }
+            }
+        },
+        "models.RestoreResult": {
+            "type": "object",
+            "properties": {
+                "assignments_restored": {
+                    "type": "integer"
+                },
+                "collection_ids": {
+                    "description": "CollectionIDs и ItemIDs - соответствие ID из копии новым ID",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "collections_created": {
+                    "type": "integer"
+                },
+                "collections_skipped": {
+                    "type": "integer"
+                },
+                "collections_updated": {
+                    "type": "integer"
+                },
+                "item_ids": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
                 },
-                "description": {
-                    "type": "string"
+                "items_created": {
+                    "description": "ItemsCreated - свои элементы, а также элементы каталога, которых нет на этом сервере",
+                    "type": "integer"
                 },
-                "id": {
-                    "type": "string"
+                "items_matched": {
+                    "description": "ItemsMatched - элементы, найденные в каталоге или среди своих элементов",
+                    "type": "integer"
                 },
-                "is_custom": {
-                    "type": "boolean"
+                "items_updated": {
+                    "type": "integer"
                 },
-                "is_public": {
-                    "type": "boolean"
+                "mode": {
+                    "type": "string",
+                    "example": "skip"
                 },
-                "title": {
-                    "type": "string"
+                "progress_restored": {
+                    "type": "integer"
                 },
-                "type": {
-                    "type": "string"
+                "progress_skipped": {
+                    "type": "integer"
                 }
             }
         },
-        "handler.CollectionResponse": {
+        "models.SavedCollection": {
             "type": "object",
+            "required": [
+                "name"
+            ],
             "properties": {
+                "comments_count": {
+                    "type": "integer"
+                },
                 "cover_image": {
                     "type": "string"
                 },
+                "created_at": {
+                    "type": "string"
+                },
                 "description": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "is_custom": {
-                    "type": "boolean"
-                },
                 "is_public": {
                     "type": "boolean"
                 },
+                "likes_count": {
+                    "type": "integer"
+                },
                 "name": {
                     "type": "string"
                 },
+                "owner": {
+                    "$ref": "#/definitions/models.UserSummary"
+                },
+                "saved_at": {
+                    "type": "string"
+                },
+                "saves_count": {
+                    "type": "integer"
+                },
                 "type": {
                     "type": "string"
                 },
                 "user_id": {
-                    "type": "string"
+                    "type": "integer"
                 }
             }
         },
-        "handler.CreateCollectionInput": {
+        "models.SearchResult": {
             "type": "object",
-            "required": [
-                "name",
-                "type"
-            ],
             "properties": {
-                "cover_image": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
+                "item": {
+                    "$ref": "#/definitions/models.CollectionItem"
                 },
-                "is_custom": {
-                    "type": "boolean"
-                },
-                "is_public": {
-                    "type": "boolean"
+                "rank": {
+                    "type": "number"
                 },
-                "name": {
+                "snippet": {
                     "type": "string"
                 },
-                "type": {
+                "title_highlight": {
                     "type": "string"
                 }
             }
         },
-        "handler.CreateCollectionItemInput": {
+        "models.Season": {
             "type": "object",
-            "required": [
-                "title",
-                "type"
-            ],
             "properties": {
-                "cover_image": {
+                "created_at": {
                     "type": "string"
                 },
-                "description": {
+                "episodes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Episode"
+                    }
+                },
+                "id": {
                     "type": "string"
                 },
-                "is_custom": {
-                    "type": "boolean"
+                "item_id": {
+                    "type": "string"
                 },
-                "is_public": {
-                    "type": "boolean"
+                "number": {
+                    "type": "integer"
                 },
                 "title": {
                     "type": "string"
-                },
-                "type": {
-                    "type": "string"
                 }
             }
         },
-        "handler.ErrorResponse": {
+        "models.SimilarItem": {
             "type": "object",
             "properties": {
-                "error": {
-                    "type": "string"
+                "co_occurrences": {
+                    "description": "у скольких пользователей есть оба элемента",
+                    "type": "integer"
+                },
+                "item": {
+                    "$ref": "#/definitions/models.CollectionItem"
+                },
+                "score": {
+                    "description": "похожесть 0..1",
+                    "type": "number"
                 }
             }
         },
-        "handler.IDResponse": {
+        "models.Tag": {
             "type": "object",
             "properties": {
+                "created_at": {
+                    "type": "string"
+                },
                 "id": {
+                    "type": "integer"
+                },
+                "name": {
                     "type": "string"
+                },
+                "usage_count": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
                 }
             }
         },
-        "handler.LinkGitHubInput": {
+        "models.UnreadNotifications": {
             "type": "object",
-            "required": [
-                "github_code"
-            ],
             "properties": {
-                "github_code": {
-                    "type": "string"
+                "count": {
+                    "type": "integer"
                 }
             }
         },
-        "handler.MessageResponse": {
+        "models.UserResponse": {
             "type": "object",
             "properties": {
-                "message": {
+                "avatar_url": {
                     "type": "string"
-                }
-            }
-        },
-        "handler.PaginatedCollectionsResponse": {
-            "type": "object",
-            "properties": {
-                "collections": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/handler.CollectionResponse"
-                    }
                 },
-                "page": {
-                    "type": "integer"
-                },
-                "per_page": {
+                "id": {
                     "type": "integer"
                 },
-                "total": {
-                    "type": "integer"
+                "mail": {
+                    "type": "string"
                 },
-                "total_pages": {
-                    "type": "integer"
+                "name": {
+                    "type": "string"
                 }
             }
         },
-        "handler.PaginatedItemsResponse": {
+        "models.UserSummary": {
             "type": "object",
             "properties": {
-                "items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/handler.CollectionItemResponse"
-                    }
-                },
-                "page": {
-                    "type": "integer"
+                "avatar_url": {
+                    "type": "string"
                 },
-                "per_page": {
+                "id": {
                     "type": "integer"
                 },
-                "total": {
-                    "type": "integer"
+                "is_private": {
+                    "type": "boolean"
                 },
-                "total_pages": {
-                    "type": "integer"
-                }
-            }
-        },
-        "handler.SuccessResponse": {
-            "type": "object",
-            "properties": {
-                "message": {
-                    "type": "string"
-                }
-            }
-        },
-        "handler.UnlinkGitHubInput": {
-            "type": "object",
-            "required": [
-                "password"
-            ],
-            "properties": {
-                "password": {
+                "name": {
                     "type": "string"
                 }
             }
         },
-        "handler.UpdateUserProfileInput": {
+        "models.Webhook": {
             "type": "object",
             "properties": {
-                "avatar_url": {
+                "created_at": {
                     "type": "string"
                 },
-                "email": {
+                "description": {
                     "type": "string"
                 },
-                "name": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "item.added",
+                        "review.posted"
+                    ]
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "secret": {
+                    "description": "Secret - ключ подписи, показывается только при создании вебхука",
+                    "type": "string",
+                    "example": "whsec_3f1c..."
+                },
+                "updated_at": {
                     "type": "string"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://example.com/hooks/memoria"
+                },
+                "user_id": {
+                    "type": "integer"
                 }
             }
         },
-        "handler.UserProfileResponse": {
+        "models.WebhookDelivery": {
             "type": "object",
             "properties": {
-                "avatar_url": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
                     "type": "string"
                 },
-                "email": {
+                "delivered_at": {
                     "type": "string"
                 },
-                "github_id": {
+                "duration_ms": {
                     "type": "integer"
                 },
+                "event_type": {
+                    "type": "string",
+                    "example": "item.added"
+                },
                 "id": {
+                    "type": "integer"
+                },
+                "last_error": {
                     "type": "string"
                 },
-                "name": {
+                "next_attempt_at": {
                     "type": "string"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "response_body": {
+                    "type": "string"
+                },
+                "response_status": {
+                    "description": "ResponseStatus и ResponseBody - ответ получателя на последнюю попытку",
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "sent"
+                },
+                "webhook_id": {
+                    "type": "integer"
                 }
             }
         },
-        "handler.signInInput": {
+        "models.WebhookInput": {
             "type": "object",
             "required": [
-                "mail",
-                "password"
+                "events",
+                "url"
             ],
             "properties": {
-                "mail": {
+                "description": {
                     "type": "string"
                 },
-                "password": {
-                    "type": "string"
-                }
-            }
-        },
-        "handler.signInResponse": {
-            "type": "object",
-            "properties": {
-                "token": {
-                    "type": "string"
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "item.added",
+                        "review.posted"
+                    ]
                 },
-                "user": {
-                    "$ref": "#/definitions/models.UserResponse"
+                "is_active": {
+                    "description": "IsActive - при изменении nil оставляет текущее значение, при создании вебхук включен",
+                    "type": "boolean"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://example.com/hooks/memoria"
                 }
             }
         },
-        "handler.signUpInput": {
+        "pagination.CursorPagination": {
             "type": "object",
-            "required": [
-                "mail",
-                "name",
-                "password"
-            ],
             "properties": {
-                "mail": {
-                    "type": "string"
+                "limit": {
+                    "type": "integer"
                 },
-                "name": {
+                "next": {
                     "type": "string"
                 },
-                "password": {
+                "prev": {
                     "type": "string"
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         },
-        "models.UserResponse": {
+        "pagination.PaginationResponse": {
             "type": "object",
             "properties": {
-                "avatar_url": {
-                    "type": "string"
+                "limit": {
+                    "type": "integer"
                 },
-                "id": {
+                "page": {
                     "type": "integer"
                 },
-                "mail": {
-                    "type": "string"
+                "total": {
+                    "type": "integer"
                 },
-                "name": {
-                    "type": "string"
+                "total_pages": {
+                    "type": "integer"
                 }
             }
         },
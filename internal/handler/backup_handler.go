@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/service"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/backup"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/responses"
+)
+
+// CreateBackup streams a full backup of user's library
+// @Summary Резервная копия библиотеки
+// @Description Копия всей библиотеки без потерь: коллекции с порядком элементов и заметками, свои элементы с сезонами и эпизодами,
+// @Description прогресс, оценки, теги и отмеченные эпизоды. Формат - сжатый JSON, восстанавливается через POST /user/restore на любом сервере Memoria
+// @Tags users
+// @Produce application/gzip
+// @Success 200 {file} file "Файл копии .memoria.json.gz"
+// @Security ApiKeyAuth
+// @Router /user/backup [get]
+func (h *Handler) CreateBackup(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	export, err := h.service.BackupService.CreateBackup(userID)
+	if err != nil {
+		h.handleBackupError(c, err)
+		return
+	}
+	h.writeExport(c, export)
+}
+
+// RestoreBackup restores user's library from a backup
+// @Summary Восстановить библиотеку из копии
+// @Description Копия проверяется целиком и восстанавливается одной транзакцией: при любой ошибке библиотека не меняется.
+// @Description Элементы каталога ищутся по ID, внешним ID и названию, отсутствующие создаются своими элементами. Коллекции и свои элементы получают новые ID, соответствие - в ответе.
+// @Description При совпадении коллекции (по ID или названию и типу) или своего элемента: skip - оставить существующие, overwrite - заменить данными копии, duplicate - создать рядом копию
+// @Tags users
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Файл копии (.memoria.json.gz или .json)"
+// @Param mode query string false "Что делать при совпадении" Enums(skip, overwrite, duplicate) default(skip)
+// @Success 200 {object} models.RestoreResult "Итог восстановления"
+// @Failure 400 {object} ErrorResponse "Файл не является копией, версия не поддерживается или копия содержит ошибки"
+// @Failure 413 {object} ErrorResponse "Файл слишком большой"
+// @Security ApiKeyAuth
+// @Router /user/restore [post]
+func (h *Handler) RestoreBackup(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxUploadRequestSize)
+
+	header, err := c.FormFile(uploadFormField)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			responses.NewErrorResponse(c, http.StatusRequestEntityTooLarge, "backup file is too large")
+			return
+		}
+		responses.BadRequest(c, "multipart field \""+uploadFormField+"\" with backup file is required")
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		responses.BadRequest(c, "failed to read uploaded file")
+		return
+	}
+	defer file.Close()
+
+	result, err := h.service.BackupService.Restore(userID, file, c.Query("mode"))
+	if err != nil {
+		h.handleBackupError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *Handler) handleBackupError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, backup.ErrInvalidArchive), errors.Is(err, backup.ErrUnsupportedVersion),
+		errors.Is(err, service.ErrInvalidRestoreMode):
+		responses.BadRequest(c, err.Error())
+	default:
+		h.logger.Errorf("Backup operation failed: %v", err)
+		responses.InternalServerErrorWithDetails(c, "internal server error")
+	}
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/service"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/responses"
+)
+
+// ChartResponse represents chart page
+type ChartResponse struct {
+	Data       []models.ChartEntry           `json:"data"`
+	Pagination pagination.PaginationResponse `json:"pagination"`
+	// ComputedAt - время последнего пересчета чартов
+	ComputedAt *time.Time `json:"computed_at"`
+}
+
+// GetTrendingChart returns items trending this week
+// @Summary Популярное на этой неделе
+// @Description Элементы, которые чаще всего добавляли в коллекции за последние дни, свежие добавления весят больше.
+// @Description Чарты пересчитываются фоновой задачей, в каждом хранится ограниченное число мест
+// @Tags charts
+// @Produce json
+// @Param type query string false "Тип элементов" Enums(books, anime, series, movies)
+// @Param genre query string false "Slug жанра"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Кол-во элементов на странице" default(10)
+// @Success 200 {object} ChartResponse "Чарт"
+// @Failure 400 {object} ErrorResponse "Неизвестный тип"
+// @Failure 404 {object} ErrorResponse "Жанр не найден"
+// @Router /charts/trending [get]
+func (h *Handler) GetTrendingChart(c *gin.Context) {
+	h.getChart(c, models.ChartTrending)
+}
+
+// GetMostCollectedChart returns items collected by most users
+// @Summary Самое собираемое
+// @Description Элементы, которые есть в коллекциях у наибольшего числа пользователей за все время
+// @Tags charts
+// @Produce json
+// @Param type query string false "Тип элементов" Enums(books, anime, series, movies)
+// @Param genre query string false "Slug жанра"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Кол-во элементов на странице" default(10)
+// @Success 200 {object} ChartResponse "Чарт"
+// @Failure 400 {object} ErrorResponse "Неизвестный тип"
+// @Failure 404 {object} ErrorResponse "Жанр не найден"
+// @Router /charts/most-collected [get]
+func (h *Handler) GetMostCollectedChart(c *gin.Context) {
+	h.getChart(c, models.ChartMostCollected)
+}
+
+// GetTopRatedChart returns best rated items
+// @Summary Лучшие по оценкам
+// @Description Байесовское среднее: к оценкам элемента добавляется несколько средних оценок каталога,
+// @Description поэтому элемент с одной высокой оценкой не обгоняет элементы со многими оценками
+// @Tags charts
+// @Produce json
+// @Param type query string false "Тип элементов" Enums(books, anime, series, movies)
+// @Param genre query string false "Slug жанра"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Кол-во элементов на странице" default(10)
+// @Success 200 {object} ChartResponse "Чарт"
+// @Failure 400 {object} ErrorResponse "Неизвестный тип"
+// @Failure 404 {object} ErrorResponse "Жанр не найден"
+// @Router /charts/top-rated [get]
+func (h *Handler) GetTopRatedChart(c *gin.Context) {
+	h.getChart(c, models.ChartTopRated)
+}
+
+func (h *Handler) getChart(c *gin.Context, name string) {
+	query := models.ChartQuery{
+		Chart: name,
+		Type:  c.Query("type"),
+		Genre: c.Query("genre"),
+	}
+
+	chart, err := h.service.ChartService.GetChart(query, GetPaginationParams(c))
+	if err != nil {
+		h.handleChartError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, chart)
+}
+
+func (h *Handler) handleChartError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrGenreNotFound):
+		responses.NotFound(c, "Genre not found")
+	case errors.Is(err, service.ErrInvalidItemFilter):
+		responses.BadRequest(c, err.Error())
+	default:
+		h.logger.Errorf("Chart operation failed: %v", err)
+		responses.InternalServerErrorWithDetails(c, "internal server error")
+	}
+}
@@ -1,10 +1,14 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/service"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/responses"
 )
 
 // CollectionResponse represents a collection response
@@ -28,6 +32,12 @@ type PaginatedCollectionsResponse struct {
 	TotalPages  int                  `json:"total_pages"`
 }
 
+// CursorCollectionsResponse represents collections page in cursor mode
+type CursorCollectionsResponse struct {
+	Data       []models.Collection         `json:"data"`
+	Pagination pagination.CursorPagination `json:"pagination"`
+}
+
 // CreateCollectionInput represents input for creating a collection
 type CreateCollectionInput struct {
 	Name        string  `json:"name" binding:"required"`
@@ -45,20 +55,46 @@ type ErrorResponse struct {
 
 // GetCollectionList returns paginated list of collections for user
 // @Summary Получить список коллекций
-// @Description Возвращает пагинированный список коллекций пользователя
+// @Description Возвращает пагинированный список коллекций пользователя.
+// @Description С параметром cursor (пустой - первая страница) отдается в keyset-режиме:
+// @Description ответ {data, pagination: {next, prev, limit, total}}, total - только при with_total=true
 // @Tags collections
 // @Produce json
+// @Param tags query string false "Теги коллекций через запятую"
+// @Param tags_mode query string false "Режим совпадения тегов" Enums(and, or) default(or)
 // @Param page query int false "Номер страницы" default(1)
 // @Param per_page query int false "Кол-во элементов на странице" default(10)
+// @Param cursor query string false "Курсор keyset-пагинации"
+// @Param with_total query bool false "Считать общее кол-во в keyset-режиме"
 // @Success 200 {object} PaginatedCollectionsResponse "Успешный ответ"
+// @Failure 400 {object} ErrorResponse "Неверный курсор"
 // @Failure 500 {object} ErrorResponse "Ошибка сервера"
 // @Security ApiKeyAuth
 // @Router /collections [get]
 func (h *Handler) GetCollectionList(c *gin.Context) {
 	userId, _ := h.GetUserId(c)
-	pagination := GetPaginationParams(c)
 
-	PaginatedResponse, err := h.service.CollectionService.GetCollectionsWithPagination(userId, pagination)
+	if cursorReq, ok, err := GetCursorParams(c); ok {
+		if err != nil {
+			responses.BadRequest(c, err.Error())
+			return
+		}
+		page, err := h.service.CollectionService.GetCollectionsByCursor(userId, GetTagFilterParams(c), cursorReq)
+		if err != nil {
+			if errors.Is(err, pagination.ErrInvalidCursor) {
+				responses.BadRequest(c, err.Error())
+				return
+			}
+			h.logger.Errorf("Failed to get collections for user %d: %v", userId, err)
+			responses.InternalServerErrorWithDetails(c, "failed to recieved collections")
+			return
+		}
+		c.JSON(http.StatusOK, CursorCollectionsResponse{Data: page.Data, Pagination: page.Pagination})
+		return
+	}
+
+	pagination := GetPaginationParams(c)
+	PaginatedResponse, err := h.service.CollectionService.GetCollectionsWithPagination(userId, GetTagFilterParams(c), pagination)
 	if err != nil {
 		h.logger.Errorf("Failed to get collections for user %d: %v", userId, err)
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -112,3 +148,46 @@ func (h *Handler) CreateCollection(c *gin.Context) {
 
 	c.JSON(http.StatusCreated, newCollection)
 }
+
+// CollectionVisibilityInput represents collection visibility change
+type CollectionVisibilityInput struct {
+	IsPublic *bool `json:"is_public" binding:"required"`
+}
+
+// SetCollectionVisibility makes collection public or private
+// @Summary Видимость коллекции
+// @Description Открывает или закрывает коллекцию. Лайки, сохранения и комментарии закрытой коллекции сохраняются,
+// @Description но другим пользователям не видны, пока коллекция снова не станет публичной
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Param id path string true "ID коллекции"
+// @Param input body CollectionVisibilityInput true "Видимость"
+// @Success 200 {object} SuccessResponse "Видимость изменена"
+// @Failure 400 {object} ErrorResponse "Неверные данные"
+// @Failure 403 {object} ErrorResponse "Чужая коллекция"
+// @Failure 404 {object} ErrorResponse "Коллекция не найдена"
+// @Security ApiKeyAuth
+// @Router /collections/{id}/visibility [put]
+func (h *Handler) SetCollectionVisibility(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	var input CollectionVisibilityInput
+	if err := c.BindJSON(&input); err != nil {
+		responses.BadRequest(c, "is_public is required")
+		return
+	}
+
+	err := h.service.CollectionService.SetCollectionVisibility(userID, c.Param("id"), *input.IsPublic)
+	switch {
+	case err == nil:
+		responses.Success(c, "Visibility has been updated")
+	case errors.Is(err, service.ErrCollectionNotFound):
+		responses.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrNotCollectionOwner):
+		responses.Forbidden(c, err.Error())
+	default:
+		h.logger.Errorf("Failed to update visibility of collection %s: %v", c.Param("id"), err)
+		responses.InternalServerErrorWithDetails(c, "internal server error")
+	}
+}
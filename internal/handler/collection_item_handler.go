@@ -1,12 +1,16 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
 	"github.com/kefirchick13/memoria-collect-platform-golang/internal/service"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
 	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/responses"
 )
 
@@ -41,24 +45,76 @@ type IDResponse struct {
 	ID string `json:"id"`
 }
 
-// GetItemsByType returns paginated list of items by type
-// @Summary Получить элементы по типу
-// @Description Возвращает пагинированный список элементов по указанному типу
+// ItemFeedResponse represents items feed page with facets
+type ItemFeedResponse struct {
+	Data       []models.FeedItem             `json:"data"`
+	Pagination pagination.PaginationResponse `json:"pagination"`
+	Facets     models.ItemFacets             `json:"facets"`
+}
+
+// ItemFeedCursorResponse represents items feed page in cursor mode
+type ItemFeedCursorResponse struct {
+	Data       []models.FeedItem           `json:"data"`
+	Pagination pagination.CursorPagination `json:"pagination"`
+}
+
+// GetItemsByType returns paginated, filtered and sorted items feed
+// @Summary Лента элементов
+// @Description Возвращает пагинированную ленту публичных элементов с фильтрами, сортировкой и фасетами.
+// @Description Фасеты (типы, жанры, источник) считаются с учетом всех фильтров, кроме собственного.
+// @Description С параметром cursor (пустой - первая страница) лента отдается в keyset-режиме без фасетов:
+// @Description ответ {data, pagination: {next, prev, limit, total}}, total - только при with_total=true
 // @Tags items
 // @Produce json
-// @Param type query string false "Тип элементов" default(book)
+// @Param type query string false "Тип элементов" Enums(books, anime, series, movies)
+// @Param genres query string false "Slug жанров через запятую"
+// @Param genres_mode query string false "Режим совпадения жанров" Enums(and, or) default(or)
+// @Param tags query string false "Свои теги через запятую"
+// @Param tags_mode query string false "Режим совпадения тегов" Enums(and, or) default(or)
+// @Param year_from query int false "Год выхода от"
+// @Param year_to query int false "Год выхода до"
+// @Param rating_min query number false "Средняя оценка от (1-10)"
+// @Param rating_max query number false "Средняя оценка до (1-10)"
+// @Param source query string false "Источник элемента" Enums(custom, catalog)
+// @Param not_in_library query bool false "Исключить элементы из своих коллекций"
+// @Param sort query string false "Сортировка" Enums(title, newest, popular, top_rated)
 // @Param page query int false "Номер страницы" default(1)
-// @Param per_page query int false "Кол-во элементов на странице" default(10)
-// @Success 200 {object} PaginatedItemsResponse "Успешный ответ"
+// @Param limit query int false "Кол-во элементов на странице" default(10)
+// @Param cursor query string false "Курсор keyset-пагинации"
+// @Param with_total query bool false "Считать общее кол-во в keyset-режиме"
+// @Success 200 {object} ItemFeedResponse "Успешный ответ"
+// @Failure 400 {object} ErrorResponse "Неверный фильтр"
 // @Failure 502 {object} ErrorResponse "Ошибка сервера"
+// @Security ApiKeyAuth
 // @Router /items [get]
 func (h *Handler) GetItemsByType(c *gin.Context) {
-	// search for a type in query params, Default as "book"
-	currType := c.DefaultQuery("type", "book")
+	user_id, _ := h.GetUserId(c)
+
+	filter, err := getItemFilterParams(c)
+	if err != nil {
+		responses.BadRequest(c, err.Error())
+		return
+	}
+	filter.UserID = user_id
+	filter.Tags.UserID = user_id
+
+	if cursorReq, ok, err := GetCursorParams(c); ok {
+		if err != nil {
+			responses.BadRequest(c, err.Error())
+			return
+		}
+		h.getItemsFeedByCursor(c, filter, cursorReq)
+		return
+	}
+
 	pagination := GetPaginationParams(c)
-	PaginatedResponse, err := h.service.CollectionItemService.GetItemsByCurrentType(currType, pagination)
+	feed, err := h.service.CollectionItemService.GetItemsByCurrentType(filter, pagination)
 
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidItemFilter) {
+			responses.BadRequest(c, err.Error())
+			return
+		}
 		h.logger.Errorf("Error during getting items by type: %s", err)
 		c.JSON(http.StatusBadGateway, gin.H{
 			"error": err.Error(),
@@ -66,7 +122,50 @@ func (h *Handler) GetItemsByType(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, PaginatedResponse)
+	c.JSON(http.StatusOK, feed)
+}
+
+func (h *Handler) getItemsFeedByCursor(c *gin.Context, filter models.ItemFilter, req pagination.CursorRequest) {
+	feed, err := h.service.CollectionItemService.GetItemsFeedByCursor(filter, req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidItemFilter) || errors.Is(err, pagination.ErrInvalidCursor) {
+			responses.BadRequest(c, err.Error())
+			return
+		}
+		h.logger.Errorf("Error during getting items feed by cursor: %s", err)
+		responses.InternalServerErrorWithDetails(c, "failed to get items")
+		return
+	}
+
+	c.JSON(http.StatusOK, ItemFeedCursorResponse{Data: feed.Data, Pagination: feed.Pagination})
+}
+
+// getItemFilterParams парсит фильтры и сортировку ленты из query
+func getItemFilterParams(c *gin.Context) (models.ItemFilter, error) {
+	filter := models.ItemFilter{
+		Type:           c.Query("type"),
+		Genres:         GetListQueryParam(c, "genres"),
+		GenresMatchAll: strings.EqualFold(c.Query("genres_mode"), "and"),
+		Tags:           GetTagFilterParams(c),
+		Source:         c.Query("source"),
+		NotInLibrary:   c.Query("not_in_library") == "true",
+		Sort:           c.Query("sort"),
+	}
+
+	var err error
+	if filter.YearFrom, err = GetOptionalIntQuery(c, "year_from"); err != nil {
+		return filter, err
+	}
+	if filter.YearTo, err = GetOptionalIntQuery(c, "year_to"); err != nil {
+		return filter, err
+	}
+	if filter.RatingMin, err = GetOptionalFloatQuery(c, "rating_min"); err != nil {
+		return filter, err
+	}
+	if filter.RatingMax, err = GetOptionalFloatQuery(c, "rating_max"); err != nil {
+		return filter, err
+	}
+	return filter, nil
 }
 
 // GetCollectionItems returns all items in a collection
@@ -75,6 +174,8 @@ func (h *Handler) GetItemsByType(c *gin.Context) {
 // @Tags collections
 // @Produce json
 // @Param id path string true "ID коллекции"
+// @Param tags query string false "Свои теги элементов через запятую"
+// @Param tags_mode query string false "Режим совпадения тегов" Enums(and, or) default(or)
 // @Success 200 {object} interface{} "Список элементов коллекции"
 // @Failure 400 {object} ErrorResponse "Не указан ID коллекции"
 // @Failure 404 {object} ErrorResponse "Элементы не найдены"
@@ -89,7 +190,7 @@ func (h *Handler) GetCollectionItems(c *gin.Context) {
 		return
 	}
 
-	items, err := h.service.CollectionItemService.GetItemsByCollection(collection_uid, user_id)
+	items, err := h.service.CollectionItemService.GetItemsByCollection(collection_uid, user_id, GetTagFilterParams(c))
 	if err != nil {
 		responses.NewErrorResponse(c, http.StatusNotFound, "items aren't find")
 		return
@@ -97,6 +198,41 @@ func (h *Handler) GetCollectionItems(c *gin.Context) {
 	c.JSON(http.StatusOK, items)
 }
 
+// SuggestItems returns autocomplete suggestions for item titles
+// @Summary Автодополнение элементов
+// @Description Возвращает элементы, название которых начинается с q или похоже на q, с учетом популярности. Результаты кешируются
+// @Tags items
+// @Produce json
+// @Param q query string true "Начало названия"
+// @Param type query string false "Тип элементов" Enums(books, anime, series, movies)
+// @Param limit query int false "Кол-во подсказок" default(10)
+// @Success 200 {array} models.ItemSuggestion "Подсказки"
+// @Failure 400 {object} ErrorResponse "Неверный запрос"
+// @Security ApiKeyAuth
+// @Router /items/suggest [get]
+func (h *Handler) SuggestItems(c *gin.Context) {
+	user_id, _ := h.GetUserId(c)
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		responses.BadRequest(c, "limit is not valid")
+		return
+	}
+
+	suggestions, err := h.service.CollectionItemService.SuggestItems(c.Query("q"), c.Query("type"), user_id, limit)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidSuggestQuery) {
+			responses.BadRequest(c, err.Error())
+			return
+		}
+		h.logger.Errorf("Failed to suggest items: %v", err)
+		responses.InternalServerErrorWithDetails(c, "failed to suggest items")
+		return
+	}
+
+	c.JSON(http.StatusOK, suggestions)
+}
+
 // AddItemInput represents input for adding item to collection
 type AddItemInput struct {
 	UserReview string `json:"user_review" binding:"required"`
@@ -161,18 +297,27 @@ type CreateCollectionItemInput struct {
 	Title       string  `json:"title" binding:"required"`
 	Description string  `json:"description"`
 	CoverImage  *string `json:"cover_image"`
+	ReleaseYear *int    `json:"release_year" example:"1999"`
 	IsCustom    bool    `json:"is_custom"`
 	IsPublic    bool    `json:"is_public"`
 }
 
+// CreateItemResponse represents created item ID with possible duplicates
+type CreateItemResponse struct {
+	ID                 string                      `json:"id"`
+	PossibleDuplicates []models.DuplicateCandidate `json:"possible_duplicates"`
+}
+
 // CreateCollectionItem creates a new collection item
 // @Summary Создать элемент коллекции
-// @Description Создает новый элемент коллекции
+// @Description Создает новый элемент коллекции. В possible_duplicates возвращаются уже существующие элементы
+// @Description того же типа с похожим названием и годом - клиент может предупредить о дубликате.
+// @Description Элементы обычных пользователей создаются приватными, is_public = true отправляет элемент на модерацию
 // @Tags items
 // @Accept json
 // @Produce json
 // @Param input body CreateCollectionItemInput true "Данные для создания элемента"
-// @Success 200 {object} IDResponse "Элемент создан, возвращает ID"
+// @Success 200 {object} CreateItemResponse "Элемент создан, возвращает ID и возможные дубликаты"
 // @Failure 400 {object} ErrorResponse "Неверные входные данные"
 // @Security ApiKeyAuth
 // @Router /items [post]
@@ -191,11 +336,12 @@ func (h *Handler) CreateCollectionItem(c *gin.Context) {
 		Title:       input.Title,
 		Description: input.Description,
 		CoverImage:  input.CoverImage,
+		ReleaseYear: input.ReleaseYear,
 		IsCustom:    input.IsCustom,
 		IsPublic:    input.IsPublic,
 		CreatorID:   &user_id,
 	}
-	id, err := h.service.CollectionItemService.CreateCollectionItem(&collection_item)
+	id, duplicates, err := h.service.CollectionItemService.CreateCollectionItem(&collection_item)
 
 	if err != nil {
 		h.logger.Errorf("Can't create collectionsItem, error: %d", err.Error())
@@ -205,7 +351,129 @@ func (h *Handler) CreateCollectionItem(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"id": id,
+	c.JSON(http.StatusOK, CreateItemResponse{
+		ID:                 id,
+		PossibleDuplicates: duplicates,
 	})
 }
+
+// GetItem returns an item by ID
+// @Summary Получить элемент
+// @Description Если элемент был слит с другим, отвечает 301 с адресом канонического элемента
+// @Tags items
+// @Produce json
+// @Param id path string true "ID элемента"
+// @Success 200 {object} models.CollectionItem "Элемент"
+// @Success 301 "Элемент слит, Location - канонический элемент"
+// @Failure 403 {object} ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} ErrorResponse "Элемент не найден"
+// @Security ApiKeyAuth
+// @Router /items/{id} [get]
+func (h *Handler) GetItem(c *gin.Context) {
+	user_id, _ := h.GetUserId(c)
+	id := c.Param("id")
+
+	item, redirectID, err := h.service.CollectionItemService.GetItem(user_id, id)
+	if err != nil {
+		h.handleItemError(c, err)
+		return
+	}
+	if redirectID != "" {
+		c.Redirect(http.StatusMovedPermanently, strings.TrimSuffix(c.Request.URL.Path, id)+redirectID)
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// FindDuplicates returns existing items similar to the one being created
+// @Summary Найти возможные дубликаты
+// @Description Проверка перед созданием элемента: элементы того же типа с похожим нормализованным названием и годом
+// @Tags items
+// @Produce json
+// @Param title query string true "Название"
+// @Param type query string true "Тип элемента" Enums(books, anime, series, movies)
+// @Param year query int false "Год выхода"
+// @Success 200 {array} models.DuplicateCandidate "Возможные дубликаты"
+// @Failure 400 {object} ErrorResponse "Неверный запрос"
+// @Security ApiKeyAuth
+// @Router /items/duplicates [get]
+func (h *Handler) FindDuplicates(c *gin.Context) {
+	user_id, _ := h.GetUserId(c)
+
+	year, err := GetOptionalIntQuery(c, "year")
+	if err != nil {
+		responses.BadRequest(c, err.Error())
+		return
+	}
+	item := models.CollectionItem{
+		Title:       strings.TrimSpace(c.Query("title")),
+		Type:        c.Query("type"),
+		ReleaseYear: year,
+	}
+	if item.Title == "" || item.Type == "" {
+		responses.BadRequest(c, "title and type are required")
+		return
+	}
+
+	candidates, err := h.service.CollectionItemService.FindDuplicates(user_id, &item)
+	if err != nil {
+		h.handleItemError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, candidates)
+}
+
+// MergeItemsInput represents input for merging a duplicate item
+type MergeItemsInput struct {
+	DuplicateID string `json:"duplicate_id" binding:"required"`
+}
+
+// MergeItems merges a duplicate into the canonical item
+// @Summary Слить дубликат с элементом
+// @Description Доступно администраторам. Переносит элементы коллекций, прогресс, оценки, теги, жанры и связи
+// @Description дубликата на канонический элемент в одной транзакции, удаляет дубликат и оставляет редирект со старого ID
+// @Tags items
+// @Accept json
+// @Produce json
+// @Param id path string true "ID канонического элемента"
+// @Param input body MergeItemsInput true "ID дубликата"
+// @Success 200 {object} models.ItemMergeResult "Итог слияния"
+// @Failure 400 {object} ErrorResponse "Элементы нельзя слить"
+// @Failure 403 {object} ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} ErrorResponse "Элемент не найден"
+// @Security ApiKeyAuth
+// @Router /items/{id}/merge [post]
+func (h *Handler) MergeItems(c *gin.Context) {
+	user_id, _ := h.GetUserId(c)
+
+	var input MergeItemsInput
+	if err := c.BindJSON(&input); err != nil {
+		responses.NewErrorResponse(c, http.StatusBadRequest, "input is not valid")
+		return
+	}
+
+	result, err := h.service.CollectionItemService.MergeItems(user_id, c.Param("id"), input.DuplicateID)
+	if err != nil {
+		h.handleItemError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *Handler) handleItemError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrItemNotFound):
+		responses.NotFound(c, "Item not found")
+	case errors.Is(err, service.ErrItemAccessDenied), errors.Is(err, service.ErrAdminRequired):
+		responses.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrMergeSameItem), errors.Is(err, service.ErrMergeTypeMismatch),
+		errors.Is(err, service.ErrMergePrivateCanonical):
+		responses.BadRequest(c, err.Error())
+	default:
+		h.logger.Errorf("Item operation failed: %v", err)
+		responses.InternalServerErrorWithDetails(c, "internal server error")
+	}
+}
@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/service"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/responses"
+)
+
+// SuggestEditInput represents a field-level edit proposal for a catalog item
+type SuggestEditInput struct {
+	Changes models.ItemChanges `json:"changes" binding:"required"`
+	Comment *string            `json:"comment" example:"Опечатка в названии"`
+}
+
+// EditReviewInput represents moderator's optional comment on an edit suggestion
+type EditReviewInput struct {
+	Comment *string `json:"comment" example:"Спасибо, исправлено"`
+}
+
+// PaginatedEditSuggestionsResponse represents edit suggestions page
+type PaginatedEditSuggestionsResponse struct {
+	Data       []models.EditSuggestion       `json:"data"`
+	Pagination pagination.PaginationResponse `json:"pagination"`
+}
+
+// SuggestItemEdit proposes changes to a public catalog item
+// @Summary Предложить правку элемента
+// @Description Пользователь предлагает изменить поля публичного элемента каталога. Правка применяется после проверки модератором
+// @Tags edits
+// @Accept json
+// @Produce json
+// @Param id path string true "ID элемента"
+// @Param input body SuggestEditInput true "Изменения"
+// @Success 201 {object} models.EditSuggestion "Правка создана"
+// @Failure 400 {object} ErrorResponse "Неверные входные данные"
+// @Failure 403 {object} ErrorResponse "Элемент не опубликован в каталоге"
+// @Failure 404 {object} ErrorResponse "Элемент не найден"
+// @Security ApiKeyAuth
+// @Router /items/{id}/edits [post]
+func (h *Handler) SuggestItemEdit(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	var input SuggestEditInput
+	if err := c.BindJSON(&input); err != nil {
+		responses.NewErrorResponse(c, http.StatusBadRequest, "input is not valid")
+		return
+	}
+
+	suggestion, err := h.service.EditService.SuggestEdit(userID, c.Param("id"), input.Changes, input.Comment)
+	if err != nil {
+		h.handleEditError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, suggestion)
+}
+
+// GetItemEditSuggestions returns edit suggestions of an item
+// @Summary Предложенные правки элемента
+// @Description Правки элемента, сначала новые. Фильтр по статусу: pending, accepted, rejected
+// @Tags edits
+// @Produce json
+// @Param id path string true "ID элемента"
+// @Param status query string false "Статус правки" Enums(pending, accepted, rejected)
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Кол-во элементов на странице" default(10)
+// @Success 200 {object} PaginatedEditSuggestionsResponse "Правки"
+// @Failure 400 {object} ErrorResponse "Неверный статус"
+// @Failure 404 {object} ErrorResponse "Элемент не найден"
+// @Security ApiKeyAuth
+// @Router /items/{id}/edits [get]
+func (h *Handler) GetItemEditSuggestions(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	suggestions, err := h.service.EditService.GetItemSuggestions(userID, c.Param("id"), c.Query("status"), GetPaginationParams(c))
+	if err != nil {
+		h.handleEditError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, suggestions)
+}
+
+// GetItemHistory returns accepted revisions of an item
+// @Summary История изменений элемента
+// @Description Принятые изменения элемента с автором и диффом полей, сначала новые
+// @Tags edits
+// @Produce json
+// @Param id path string true "ID элемента"
+// @Success 200 {array} models.ItemRevision "История"
+// @Failure 404 {object} ErrorResponse "Элемент не найден"
+// @Security ApiKeyAuth
+// @Router /items/{id}/history [get]
+func (h *Handler) GetItemHistory(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	history, err := h.service.EditService.GetItemHistory(userID, c.Param("id"))
+	if err != nil {
+		h.handleEditError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// RevertItemRevision restores item fields to values before a revision
+// @Summary Откатить изменение элемента
+// @Description Поля ревизии возвращаются к прежним значениям, откат сохраняется новой ревизией. Доступно модераторам и администраторам
+// @Tags edits
+// @Produce json
+// @Param id path string true "ID элемента"
+// @Param revision_id path int true "ID ревизии"
+// @Success 200 {object} models.ItemRevision "Ревизия отката"
+// @Failure 400 {object} ErrorResponse "Неверный ID ревизии"
+// @Failure 403 {object} ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} ErrorResponse "Ревизия не найдена"
+// @Failure 409 {object} ErrorResponse "Откатывать нечего"
+// @Security ApiKeyAuth
+// @Router /items/{id}/history/{revision_id}/revert [post]
+func (h *Handler) RevertItemRevision(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	revisionID, err := strconv.Atoi(c.Param("revision_id"))
+	if err != nil {
+		responses.BadRequest(c, "revision id is not valid")
+		return
+	}
+
+	revision, err := h.service.EditService.RevertRevision(userID, c.Param("id"), revisionID)
+	if err != nil {
+		h.handleEditError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, revision)
+}
+
+// GetPendingEdits returns edit suggestions awaiting review
+// @Summary Очередь правок
+// @Description Правки, ожидающие проверки, сначала самые старые. Доступно модераторам и администраторам
+// @Tags edits
+// @Produce json
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Кол-во элементов на странице" default(10)
+// @Success 200 {object} PaginatedEditSuggestionsResponse "Очередь"
+// @Failure 403 {object} ErrorResponse "Доступ запрещен"
+// @Security ApiKeyAuth
+// @Router /moderation/edits [get]
+func (h *Handler) GetPendingEdits(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	suggestions, err := h.service.EditService.GetPendingSuggestions(userID, GetPaginationParams(c))
+	if err != nil {
+		h.handleEditError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, suggestions)
+}
+
+// AcceptEdit applies an edit suggestion to the item
+// @Summary Принять правку
+// @Description Изменения применяются к элементу и сохраняются в истории, автор правки получает уведомление
+// @Tags edits
+// @Accept json
+// @Produce json
+// @Param id path int true "ID правки"
+// @Param input body EditReviewInput false "Комментарий"
+// @Success 200 {object} models.ItemRevision "Ревизия"
+// @Failure 400 {object} ErrorResponse "Неверные входные данные"
+// @Failure 403 {object} ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} ErrorResponse "Правка не найдена"
+// @Failure 409 {object} ErrorResponse "Правка уже рассмотрена или ничего не меняет"
+// @Security ApiKeyAuth
+// @Router /moderation/edits/{id}/accept [post]
+func (h *Handler) AcceptEdit(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	suggestionID, input, ok := h.getEditReviewInput(c)
+	if !ok {
+		return
+	}
+
+	revision, err := h.service.EditService.AcceptSuggestion(userID, suggestionID, input.Comment)
+	if err != nil {
+		h.handleEditError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, revision)
+}
+
+// RejectEdit rejects an edit suggestion
+// @Summary Отклонить правку
+// @Description Элемент не меняется, автор правки получает уведомление с комментарием
+// @Tags edits
+// @Accept json
+// @Produce json
+// @Param id path int true "ID правки"
+// @Param input body EditReviewInput false "Комментарий"
+// @Success 200 {object} SuccessResponse "Правка отклонена"
+// @Failure 400 {object} ErrorResponse "Неверные входные данные"
+// @Failure 403 {object} ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} ErrorResponse "Правка не найдена"
+// @Failure 409 {object} ErrorResponse "Правка уже рассмотрена"
+// @Security ApiKeyAuth
+// @Router /moderation/edits/{id}/reject [post]
+func (h *Handler) RejectEdit(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	suggestionID, input, ok := h.getEditReviewInput(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.EditService.RejectSuggestion(userID, suggestionID, input.Comment); err != nil {
+		h.handleEditError(c, err)
+		return
+	}
+
+	responses.Success(c, "Edit suggestion rejected")
+}
+
+// getEditReviewInput читает ID правки и необязательное тело с комментарием
+func (h *Handler) getEditReviewInput(c *gin.Context) (int, EditReviewInput, bool) {
+	var input EditReviewInput
+
+	suggestionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		responses.BadRequest(c, "edit suggestion id is not valid")
+		return 0, input, false
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+		responses.NewErrorResponse(c, http.StatusBadRequest, "input is not valid")
+		return 0, input, false
+	}
+
+	return suggestionID, input, true
+}
+
+func (h *Handler) handleEditError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrItemNotFound):
+		responses.NotFound(c, "Item not found")
+	case errors.Is(err, service.ErrEditNotFound), errors.Is(err, service.ErrRevisionNotFound):
+		responses.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrModeratorRequired), errors.Is(err, service.ErrEditPrivateItem):
+		responses.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrEditEmpty), errors.Is(err, service.ErrEditInvalidTitle),
+		errors.Is(err, service.ErrEditInvalidYear), errors.Is(err, service.ErrEditInvalidStatus):
+		responses.BadRequest(c, err.Error())
+	case errors.Is(err, service.ErrEditNotPending), errors.Is(err, service.ErrEditNoEffect),
+		errors.Is(err, service.ErrRevisionNothingToUndo):
+		responses.Conflict(c, err.Error())
+	default:
+		h.logger.Errorf("Edit operation failed: %v", err)
+		responses.InternalServerErrorWithDetails(c, "internal server error")
+	}
+}
@@ -0,0 +1,294 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/service"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/responses"
+)
+
+// PaginatedCommentsResponse represents comment threads page
+type PaginatedCommentsResponse struct {
+	Data       []models.CollectionComment    `json:"data"`
+	Pagination pagination.PaginationResponse `json:"pagination"`
+}
+
+// PaginatedSavedCollectionsResponse represents saved collections page
+type PaginatedSavedCollectionsResponse struct {
+	Data       []models.SavedCollection      `json:"data"`
+	Pagination pagination.PaginationResponse `json:"pagination"`
+}
+
+// CommentInput represents new comment or reply
+type CommentInput struct {
+	Body string `json:"body" binding:"required" example:"Отличная подборка!"`
+	// ParentID - комментарий, на который дается ответ
+	ParentID *int64 `json:"parent_id"`
+}
+
+// EditCommentInput represents comment text change
+type EditCommentInput struct {
+	Body string `json:"body" binding:"required"`
+}
+
+// GetCollectionEngagement returns collection counters
+// @Summary Лайки, сохранения и комментарии коллекции
+// @Description Счетчики коллекции и поставил ли текущий пользователь лайк и сохранил ли ее
+// @Tags engagement
+// @Produce json
+// @Param id path string true "ID коллекции"
+// @Success 200 {object} models.CollectionEngagement "Счетчики"
+// @Failure 403 {object} ErrorResponse "Коллекция приватная"
+// @Failure 404 {object} ErrorResponse "Коллекция не найдена"
+// @Security ApiKeyAuth
+// @Router /collections/{id}/engagement [get]
+func (h *Handler) GetCollectionEngagement(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	engagement, err := h.service.EngagementService.GetEngagement(userID, c.Param("id"))
+	if err != nil {
+		h.handleEngagementError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, engagement)
+}
+
+// LikeCollection likes a public collection
+// @Summary Лайкнуть коллекцию
+// @Description Лайк публичной коллекции. Повторный лайк ничего не меняет
+// @Tags engagement
+// @Produce json
+// @Param id path string true "ID коллекции"
+// @Success 200 {object} models.CollectionEngagement "Счетчики после лайка"
+// @Failure 403 {object} ErrorResponse "Коллекция приватная"
+// @Failure 404 {object} ErrorResponse "Коллекция не найдена"
+// @Security ApiKeyAuth
+// @Router /collections/{id}/like [put]
+func (h *Handler) LikeCollection(c *gin.Context) {
+	h.setCollectionReaction(c, h.service.EngagementService.SetLiked, true)
+}
+
+// UnlikeCollection removes like
+// @Summary Снять лайк
+// @Description Снимает лайк, в том числе с коллекции, которую владелец закрыл
+// @Tags engagement
+// @Produce json
+// @Param id path string true "ID коллекции"
+// @Success 200 {object} models.CollectionEngagement "Счетчики после снятия лайка"
+// @Failure 404 {object} ErrorResponse "Коллекция не найдена"
+// @Security ApiKeyAuth
+// @Router /collections/{id}/like [delete]
+func (h *Handler) UnlikeCollection(c *gin.Context) {
+	h.setCollectionReaction(c, h.service.EngagementService.SetLiked, false)
+}
+
+// SaveCollection bookmarks a public collection
+// @Summary Сохранить коллекцию
+// @Description Добавляет публичную коллекцию в сохраненные. Повторное сохранение ничего не меняет
+// @Tags engagement
+// @Produce json
+// @Param id path string true "ID коллекции"
+// @Success 200 {object} models.CollectionEngagement "Счетчики после сохранения"
+// @Failure 403 {object} ErrorResponse "Коллекция приватная"
+// @Failure 404 {object} ErrorResponse "Коллекция не найдена"
+// @Security ApiKeyAuth
+// @Router /collections/{id}/save [put]
+func (h *Handler) SaveCollection(c *gin.Context) {
+	h.setCollectionReaction(c, h.service.EngagementService.SetSaved, true)
+}
+
+// UnsaveCollection removes collection from saved
+// @Summary Убрать из сохраненных
+// @Tags engagement
+// @Produce json
+// @Param id path string true "ID коллекции"
+// @Success 200 {object} models.CollectionEngagement "Счетчики после удаления из сохраненных"
+// @Failure 404 {object} ErrorResponse "Коллекция не найдена"
+// @Security ApiKeyAuth
+// @Router /collections/{id}/save [delete]
+func (h *Handler) UnsaveCollection(c *gin.Context) {
+	h.setCollectionReaction(c, h.service.EngagementService.SetSaved, false)
+}
+
+func (h *Handler) setCollectionReaction(c *gin.Context, set func(userID int, collectionID string, value bool) (*models.CollectionEngagement, error), value bool) {
+	userID, _ := h.GetUserId(c)
+
+	engagement, err := set(userID, c.Param("id"), value)
+	if err != nil {
+		h.handleEngagementError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, engagement)
+}
+
+// GetSavedCollections returns collections saved by current user
+// @Summary Сохраненные коллекции
+// @Description Коллекции, сохраненные текущим пользователем, сначала сохраненные последними. Коллекции, которые владелец закрыл, не показываются
+// @Tags engagement
+// @Produce json
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Кол-во коллекций на странице" default(10)
+// @Success 200 {object} PaginatedSavedCollectionsResponse "Сохраненные коллекции"
+// @Security ApiKeyAuth
+// @Router /user/saved [get]
+func (h *Handler) GetSavedCollections(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	saved, err := h.service.EngagementService.GetSavedCollections(userID, GetPaginationParams(c))
+	if err != nil {
+		h.handleEngagementError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, PaginatedSavedCollectionsResponse{Data: saved.Data, Pagination: saved.Pagination})
+}
+
+// GetCollectionComments returns comment threads of a collection
+// @Summary Комментарии коллекции
+// @Description Ветки комментариев, сначала новые. Пагинация по корневым комментариям, ответы приходят целиком в replies.
+// @Description Удаленный комментарий остается без текста, если на него есть ответы. Комментарии закрытой коллекции видит только владелец
+// @Tags engagement
+// @Produce json
+// @Param id path string true "ID коллекции"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Кол-во веток на странице" default(10)
+// @Success 200 {object} PaginatedCommentsResponse "Ветки комментариев"
+// @Failure 403 {object} ErrorResponse "Коллекция приватная"
+// @Failure 404 {object} ErrorResponse "Коллекция не найдена"
+// @Security ApiKeyAuth
+// @Router /collections/{id}/comments [get]
+func (h *Handler) GetCollectionComments(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	comments, err := h.service.EngagementService.GetComments(userID, c.Param("id"), GetPaginationParams(c))
+	if err != nil {
+		h.handleEngagementError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, PaginatedCommentsResponse{Data: comments.Data, Pagination: comments.Pagination})
+}
+
+// AddCollectionComment comments a public collection
+// @Summary Комментировать коллекцию
+// @Description Комментарий к публичной коллекции или ответ на комментарий (parent_id). Владелец коллекции и автор комментария, на который ответили, получают уведомление
+// @Tags engagement
+// @Accept json
+// @Produce json
+// @Param id path string true "ID коллекции"
+// @Param input body CommentInput true "Комментарий"
+// @Success 201 {object} models.CollectionComment "Комментарий создан"
+// @Failure 400 {object} ErrorResponse "Пустой или слишком длинный комментарий"
+// @Failure 403 {object} ErrorResponse "Коллекция приватная"
+// @Failure 404 {object} ErrorResponse "Коллекция или родительский комментарий не найдены"
+// @Security ApiKeyAuth
+// @Router /collections/{id}/comments [post]
+func (h *Handler) AddCollectionComment(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	var input CommentInput
+	if err := c.BindJSON(&input); err != nil {
+		responses.BadRequest(c, "body is required")
+		return
+	}
+
+	comment, err := h.service.EngagementService.AddComment(userID, c.Param("id"), input.ParentID, input.Body)
+	if err != nil {
+		h.handleEngagementError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, comment)
+}
+
+// EditCollectionComment edits own comment
+// @Summary Изменить комментарий
+// @Description Изменить текст может только автор комментария
+// @Tags engagement
+// @Accept json
+// @Produce json
+// @Param id path int true "ID комментария"
+// @Param input body EditCommentInput true "Новый текст"
+// @Success 200 {object} models.CollectionComment "Комментарий изменен"
+// @Failure 400 {object} ErrorResponse "Пустой или слишком длинный комментарий"
+// @Failure 403 {object} ErrorResponse "Чужой комментарий"
+// @Failure 404 {object} ErrorResponse "Комментарий не найден"
+// @Security ApiKeyAuth
+// @Router /comments/{id} [put]
+func (h *Handler) EditCollectionComment(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+	commentID, ok := commentIDParam(c)
+	if !ok {
+		return
+	}
+
+	var input EditCommentInput
+	if err := c.BindJSON(&input); err != nil {
+		responses.BadRequest(c, "body is required")
+		return
+	}
+
+	comment, err := h.service.EngagementService.EditComment(userID, commentID, input.Body)
+	if err != nil {
+		h.handleEngagementError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, comment)
+}
+
+// DeleteCollectionComment deletes a comment
+// @Summary Удалить комментарий
+// @Description Удалить комментарий может его автор или владелец коллекции
+// @Tags engagement
+// @Produce json
+// @Param id path int true "ID комментария"
+// @Success 200 {object} SuccessResponse "Комментарий удален"
+// @Failure 403 {object} ErrorResponse "Чужой комментарий"
+// @Failure 404 {object} ErrorResponse "Комментарий не найден"
+// @Security ApiKeyAuth
+// @Router /comments/{id} [delete]
+func (h *Handler) DeleteCollectionComment(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+	commentID, ok := commentIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.EngagementService.DeleteComment(userID, commentID); err != nil {
+		h.handleEngagementError(c, err)
+		return
+	}
+
+	responses.Success(c, "Comment has been deleted")
+}
+
+func commentIDParam(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		responses.BadRequest(c, "comment id is not valid")
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *Handler) handleEngagementError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrCollectionNotFound), errors.Is(err, service.ErrCommentNotFound):
+		responses.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrCollectionPrivate), errors.Is(err, service.ErrNotCommentAuthor):
+		responses.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrInvalidComment):
+		responses.BadRequest(c, err.Error())
+	default:
+		h.logger.Errorf("Engagement operation failed: %v", err)
+		responses.InternalServerErrorWithDetails(c, "internal server error")
+	}
+}
@@ -0,0 +1,380 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/service"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/responses"
+)
+
+const airDateLayout = "2006-01-02"
+
+// EpisodeInput represents input for creating an episode
+type EpisodeInput struct {
+	Number         int     `json:"number" binding:"required,min=1"`
+	Title          *string `json:"title"`
+	AirDate        *string `json:"air_date" example:"2024-01-31"`
+	RuntimeMinutes *int    `json:"runtime_minutes" binding:"omitempty,min=1"`
+}
+
+// CreateSeasonInput represents input for creating a season with episodes
+type CreateSeasonInput struct {
+	Number   int            `json:"number" binding:"min=0"`
+	Title    *string        `json:"title"`
+	Episodes []EpisodeInput `json:"episodes" binding:"dive"`
+}
+
+func (i EpisodeInput) toModel() (models.Episode, error) {
+	episode := models.Episode{
+		Number:         i.Number,
+		Title:          i.Title,
+		RuntimeMinutes: i.RuntimeMinutes,
+	}
+	if i.AirDate != nil && *i.AirDate != "" {
+		airDate, err := time.Parse(airDateLayout, *i.AirDate)
+		if err != nil {
+			return episode, errors.New("air_date must be in YYYY-MM-DD format")
+		}
+		episode.AirDate = &airDate
+	}
+	return episode, nil
+}
+
+// GetItemSeasons returns seasons with episodes of an item
+// @Summary Получить сезоны элемента
+// @Description Возвращает сезоны сериала/аниме с эпизодами и отметками просмотра текущего пользователя
+// @Tags episodes
+// @Produce json
+// @Param id path string true "ID элемента"
+// @Success 200 {array} models.Season "Сезоны с эпизодами"
+// @Failure 400 {object} ErrorResponse "Элемент не поддерживает эпизоды"
+// @Failure 403 {object} ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} ErrorResponse "Элемент не найден"
+// @Security ApiKeyAuth
+// @Router /items/{id}/seasons [get]
+func (h *Handler) GetItemSeasons(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	seasons, err := h.service.EpisodeService.GetSeasons(c.Param("id"), userID)
+	if err != nil {
+		h.handleEpisodeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, seasons)
+}
+
+// CreateSeason creates a season of an item
+// @Summary Создать сезон
+// @Description Создает сезон сериала/аниме вместе с эпизодами. Для публичных элементов доступно модераторам, для приватных - создателю
+// @Tags episodes
+// @Accept json
+// @Produce json
+// @Param id path string true "ID элемента"
+// @Param input body CreateSeasonInput true "Данные сезона"
+// @Success 201 {object} models.Season "Сезон создан"
+// @Failure 400 {object} ErrorResponse "Неверные входные данные"
+// @Failure 403 {object} ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} ErrorResponse "Элемент не найден"
+// @Failure 409 {object} ErrorResponse "Сезон уже существует"
+// @Security ApiKeyAuth
+// @Router /items/{id}/seasons [post]
+func (h *Handler) CreateSeason(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	var input CreateSeasonInput
+	if err := c.BindJSON(&input); err != nil {
+		responses.NewErrorResponse(c, http.StatusBadRequest, "input is not valid")
+		return
+	}
+
+	season := models.Season{
+		ItemID:   c.Param("id"),
+		Number:   input.Number,
+		Title:    input.Title,
+		Episodes: make([]models.Episode, 0, len(input.Episodes)),
+	}
+	for _, episodeInput := range input.Episodes {
+		episode, err := episodeInput.toModel()
+		if err != nil {
+			responses.BadRequest(c, err.Error())
+			return
+		}
+		season.Episodes = append(season.Episodes, episode)
+	}
+
+	created, err := h.service.EpisodeService.CreateSeason(userID, &season)
+	if err != nil {
+		h.handleEpisodeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// AddEpisode adds an episode to a season
+// @Summary Добавить эпизод
+// @Description Добавляет эпизод в сезон. Для публичных элементов доступно модераторам, для приватных - создателю
+// @Tags episodes
+// @Accept json
+// @Produce json
+// @Param id path string true "ID элемента"
+// @Param season path int true "Номер сезона"
+// @Param input body EpisodeInput true "Данные эпизода"
+// @Success 201 {object} IDResponse "Эпизод создан, возвращает ID"
+// @Failure 400 {object} ErrorResponse "Неверные входные данные"
+// @Failure 403 {object} ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} ErrorResponse "Сезон не найден"
+// @Failure 409 {object} ErrorResponse "Эпизод уже существует"
+// @Security ApiKeyAuth
+// @Router /items/{id}/seasons/{season}/episodes [post]
+func (h *Handler) AddEpisode(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	seasonNumber, err := strconv.Atoi(c.Param("season"))
+	if err != nil {
+		responses.BadRequest(c, "season number is not valid")
+		return
+	}
+
+	var input EpisodeInput
+	if err := c.BindJSON(&input); err != nil {
+		responses.NewErrorResponse(c, http.StatusBadRequest, "input is not valid")
+		return
+	}
+
+	episode, err := input.toModel()
+	if err != nil {
+		responses.BadRequest(c, err.Error())
+		return
+	}
+
+	id, err := h.service.EpisodeService.AddEpisode(userID, c.Param("id"), seasonNumber, &episode)
+	if err != nil {
+		h.handleEpisodeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, IDResponse{ID: id})
+}
+
+// MarkEpisodeWatched marks an episode as watched
+// @Summary Отметить эпизод просмотренным
+// @Description Отмечает эпизод просмотренным и пересчитывает прогресс и статус элемента
+// @Tags episodes
+// @Produce json
+// @Param id path string true "ID элемента"
+// @Param episode_id path string true "ID эпизода"
+// @Success 200 {object} models.ItemProgress "Обновленный прогресс"
+// @Failure 404 {object} ErrorResponse "Эпизод не найден"
+// @Security ApiKeyAuth
+// @Router /items/{id}/episodes/{episode_id}/watched [put]
+func (h *Handler) MarkEpisodeWatched(c *gin.Context) {
+	h.setEpisodeWatched(c, true)
+}
+
+// UnmarkEpisodeWatched removes watched mark from an episode
+// @Summary Снять отметку просмотра с эпизода
+// @Description Снимает отметку просмотра с эпизода и пересчитывает прогресс и статус элемента
+// @Tags episodes
+// @Produce json
+// @Param id path string true "ID элемента"
+// @Param episode_id path string true "ID эпизода"
+// @Success 200 {object} models.ItemProgress "Обновленный прогресс"
+// @Failure 404 {object} ErrorResponse "Эпизод не найден"
+// @Security ApiKeyAuth
+// @Router /items/{id}/episodes/{episode_id}/watched [delete]
+func (h *Handler) UnmarkEpisodeWatched(c *gin.Context) {
+	h.setEpisodeWatched(c, false)
+}
+
+func (h *Handler) setEpisodeWatched(c *gin.Context, watched bool) {
+	userID, _ := h.GetUserId(c)
+
+	progress, err := h.service.EpisodeService.MarkEpisodeWatched(userID, c.Param("id"), c.Param("episode_id"), watched)
+	if err != nil {
+		h.handleEpisodeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+// MarkSeasonWatched marks all episodes of a season as watched
+// @Summary Отметить сезон просмотренным
+// @Description Отмечает все эпизоды сезона просмотренными и пересчитывает прогресс и статус элемента
+// @Tags episodes
+// @Produce json
+// @Param id path string true "ID элемента"
+// @Param season path int true "Номер сезона"
+// @Success 200 {object} models.ItemProgress "Обновленный прогресс"
+// @Failure 404 {object} ErrorResponse "Сезон не найден"
+// @Security ApiKeyAuth
+// @Router /items/{id}/seasons/{season}/watched [put]
+func (h *Handler) MarkSeasonWatched(c *gin.Context) {
+	h.setSeasonWatched(c, true)
+}
+
+// UnmarkSeasonWatched removes watched marks from all episodes of a season
+// @Summary Снять отметку просмотра с сезона
+// @Description Снимает отметки просмотра со всех эпизодов сезона и пересчитывает прогресс и статус элемента
+// @Tags episodes
+// @Produce json
+// @Param id path string true "ID элемента"
+// @Param season path int true "Номер сезона"
+// @Success 200 {object} models.ItemProgress "Обновленный прогресс"
+// @Failure 404 {object} ErrorResponse "Сезон не найден"
+// @Security ApiKeyAuth
+// @Router /items/{id}/seasons/{season}/watched [delete]
+func (h *Handler) UnmarkSeasonWatched(c *gin.Context) {
+	h.setSeasonWatched(c, false)
+}
+
+func (h *Handler) setSeasonWatched(c *gin.Context, watched bool) {
+	userID, _ := h.GetUserId(c)
+
+	seasonNumber, err := strconv.Atoi(c.Param("season"))
+	if err != nil {
+		responses.BadRequest(c, "season number is not valid")
+		return
+	}
+
+	progress, err := h.service.EpisodeService.MarkSeasonWatched(userID, c.Param("id"), seasonNumber, watched)
+	if err != nil {
+		h.handleEpisodeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+// GetItemProgress returns user's progress on an item
+// @Summary Получить прогресс по элементу
+// @Description Возвращает прогресс и статус текущего пользователя по элементу
+// @Tags episodes
+// @Produce json
+// @Param id path string true "ID элемента"
+// @Success 200 {object} models.ItemProgress "Прогресс"
+// @Failure 404 {object} ErrorResponse "Прогресс не найден"
+// @Security ApiKeyAuth
+// @Router /items/{id}/progress [get]
+func (h *Handler) GetItemProgress(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	progress, err := h.service.EpisodeService.GetItemProgress(userID, c.Param("id"))
+	if err != nil {
+		h.handleEpisodeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+// RateItemInput represents input for rating an item
+type RateItemInput struct {
+	Rating int `json:"rating" binding:"required" example:"8"`
+}
+
+// RateItem sets user's rating of an item
+// @Summary Оценить элемент
+// @Description Ставит оценку от 1 до 10. Если элемента нет в прогрессе пользователя, он добавляется в статусе planned
+// @Tags episodes
+// @Accept json
+// @Produce json
+// @Param id path string true "ID элемента"
+// @Param input body RateItemInput true "Оценка"
+// @Success 200 {object} models.ItemProgress "Прогресс с оценкой"
+// @Failure 400 {object} ErrorResponse "Неверная оценка"
+// @Failure 403 {object} ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} ErrorResponse "Элемент не найден"
+// @Security ApiKeyAuth
+// @Router /items/{id}/rating [put]
+func (h *Handler) RateItem(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	var input RateItemInput
+	if err := c.BindJSON(&input); err != nil {
+		responses.NewErrorResponse(c, http.StatusBadRequest, "input is not valid")
+		return
+	}
+
+	progress, err := h.service.EpisodeService.RateItem(userID, c.Param("id"), &input.Rating)
+	if err != nil {
+		h.handleEpisodeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+// DeleteItemRating removes user's rating of an item
+// @Summary Снять оценку элемента
+// @Tags episodes
+// @Produce json
+// @Param id path string true "ID элемента"
+// @Success 200 {object} models.ItemProgress "Прогресс без оценки"
+// @Failure 403 {object} ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} ErrorResponse "Элемент не найден"
+// @Security ApiKeyAuth
+// @Router /items/{id}/rating [delete]
+func (h *Handler) DeleteItemRating(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	progress, err := h.service.EpisodeService.RateItem(userID, c.Param("id"), nil)
+	if err != nil {
+		h.handleEpisodeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+// GetNextEpisodes returns next episode to watch for each show in progress
+// @Summary Следующие эпизоды к просмотру
+// @Description Возвращает следующий непросмотренный эпизод для каждого сериала/аниме в процессе просмотра
+// @Tags episodes
+// @Produce json
+// @Success 200 {array} models.NextEpisode "Следующие эпизоды"
+// @Failure 500 {object} ErrorResponse "Ошибка сервера"
+// @Security ApiKeyAuth
+// @Router /user/next-episodes [get]
+func (h *Handler) GetNextEpisodes(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	episodes, err := h.service.EpisodeService.GetNextEpisodes(userID)
+	if err != nil {
+		h.logger.Errorf("Failed to get next episodes for user %d: %v", userID, err)
+		responses.InternalServerErrorWithDetails(c, "failed to get next episodes")
+		return
+	}
+
+	c.JSON(http.StatusOK, episodes)
+}
+
+func (h *Handler) handleEpisodeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrItemNotFound):
+		responses.NotFound(c, "Item not found")
+	case errors.Is(err, service.ErrSeasonNotFound):
+		responses.NotFound(c, "Season not found")
+	case errors.Is(err, service.ErrEpisodeNotFound):
+		responses.NotFound(c, "Episode not found")
+	case errors.Is(err, service.ErrProgressNotFound):
+		responses.NotFound(c, "Progress not found")
+	case errors.Is(err, service.ErrItemAccessDenied), errors.Is(err, service.ErrNotItemCreator),
+		errors.Is(err, service.ErrModeratorRequired):
+		responses.Forbidden(c, "Access denied")
+	case errors.Is(err, service.ErrEpisodesNotSupported), errors.Is(err, service.ErrInvalidRating):
+		responses.BadRequest(c, err.Error())
+	case errors.Is(err, service.ErrSeasonAlreadyExists):
+		responses.Conflict(c, err.Error())
+	default:
+		h.logger.Errorf("Episode operation failed: %v", err)
+		responses.InternalServerErrorWithDetails(c, "internal server error")
+	}
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/service"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/responses"
+)
+
+// ExportCollection streams a collection as a file
+// @Summary Выгрузить коллекцию
+// @Description Элементы коллекции с метаданными, жанрами, заметками, статусами и оценками. Форматы: csv, json, md (Markdown) и html (страница для печати).
+// @Description Чужую публичную коллекцию можно выгрузить без статусов, оценок и тегов владельца
+// @Tags collections
+// @Produce json,plain,html
+// @Param id path string true "ID коллекции"
+// @Param format query string false "Формат (по умолчанию json)" Enums(csv, json, md, html)
+// @Success 200 {file} file "Файл выгрузки"
+// @Failure 400 {object} ErrorResponse "Неизвестный формат"
+// @Failure 403 {object} ErrorResponse "Коллекция приватная"
+// @Failure 404 {object} ErrorResponse "Коллекция не найдена"
+// @Security ApiKeyAuth
+// @Router /collections/{id}/export [get]
+func (h *Handler) ExportCollection(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	export, err := h.service.ExportService.ExportCollection(userID, c.Param("id"), c.Query("format"))
+	if err != nil {
+		h.handleExportError(c, err)
+		return
+	}
+	h.writeExport(c, export)
+}
+
+// ExportLibrary streams all user's collections as a file
+// @Summary Выгрузить библиотеку
+// @Description Все коллекции пользователя с элементами, заметками, статусами и оценками. Форматы: csv, json, md (Markdown) и html (страница для печати)
+// @Tags users
+// @Produce json,plain,html
+// @Param format query string false "Формат (по умолчанию json)" Enums(csv, json, md, html)
+// @Success 200 {file} file "Файл выгрузки"
+// @Failure 400 {object} ErrorResponse "Неизвестный формат"
+// @Security ApiKeyAuth
+// @Router /user/export [get]
+func (h *Handler) ExportLibrary(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	export, err := h.service.ExportService.ExportLibrary(userID, c.Query("format"))
+	if err != nil {
+		h.handleExportError(c, err)
+		return
+	}
+	h.writeExport(c, export)
+}
+
+// writeExport отдает выгрузку файлом. После первого байта статус уже не поменять,
+// поэтому ошибка записи только логируется, а клиент получает оборванный файл
+func (h *Handler) writeExport(c *gin.Context, export *models.Export) {
+	c.Header("Content-Type", export.ContentType)
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": export.Filename}))
+	c.Header("Cache-Control", "private, no-store")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Status(http.StatusOK)
+
+	if err := export.Write(c.Writer); err != nil {
+		h.logger.Errorf("Failed to write export %q: %v", export.Filename, err)
+	}
+}
+
+func (h *Handler) handleExportError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrCollectionNotFound):
+		responses.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrNotCollectionOwner):
+		responses.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrUnknownExportFormat):
+		responses.BadRequest(c, err.Error())
+	default:
+		h.logger.Errorf("Export failed: %v", err)
+		responses.InternalServerErrorWithDetails(c, "internal server error")
+	}
+}
@@ -37,6 +37,30 @@ func (h *Handler) InitRoutes() *gin.Engine {
 		auth.GET("/callback/github", h.GithubCallback)
 	}
 
+	// Изображения без аутентификации: ссылки вставляются в <img>, доступ к файлам ограничивает подпись
+	api.GET("/images/:id", h.GetImage)
+	api.GET("/files/*key", h.GetFile)
+	api.GET("/collections/:id/cover.png", h.optionalUserIdentity, h.GetCollectionCover)
+
+	// Ленты Atom/RSS без аутентификации: ридеры не передают токен, в ленты попадают только публичные данные
+	feeds := api.Group("/feeds")
+	{
+		feeds.GET("/catalog", h.GetCatalogFeed)
+		feeds.GET("/collections/:id", h.GetCollectionFeed)
+		feeds.GET("/users/:id", h.GetUserFeed)
+	}
+
+	// Чарты без аутентификации: в них только публичные элементы, данные заранее посчитаны фоновой задачей
+	charts := api.Group("/charts")
+	{
+		charts.GET("/trending", h.GetTrendingChart)
+		charts.GET("/most-collected", h.GetMostCollectedChart)
+		charts.GET("/top-rated", h.GetTopRatedChart)
+	}
+
+	// События в реальном времени (Server-Sent Events)
+	api.GET("/stream", h.streamIdentity, h.Stream)
+
 	user := api.Group("/user")
 	user.Use(h.userIdentity) // все эндпоинты требуют аутентификации
 	{
@@ -46,6 +70,38 @@ func (h *Handler) InitRoutes() *gin.Engine {
 		user.DELETE("/account", h.DeleteAccount)
 		user.POST("/github/link", h.LinkGitHubAccount)
 		user.POST("/github/unlink", h.UnlinkGitHubAccount)
+		user.GET("/next-episodes", h.GetNextEpisodes)
+		user.GET("/notifications", h.GetNotifications)
+		user.GET("/notifications/unread-count", h.GetUnreadNotificationsCount)
+		user.POST("/notifications/read-all", h.MarkAllNotificationsRead)
+		user.POST("/notifications/:id/read", h.MarkNotificationRead)
+		user.GET("/notification-preferences", h.GetNotificationPreferences)
+		user.PUT("/notification-preferences", h.SetNotificationPreferences)
+		user.POST("/avatar", h.UploadAvatar)
+		user.GET("/export", h.ExportLibrary)
+		user.GET("/backup", h.CreateBackup)
+		user.POST("/restore", h.RestoreBackup)
+
+		// Подписки и лента
+		user.GET("/feed", h.GetHomeFeed)
+		user.GET("/saved", h.GetSavedCollections)
+		user.PUT("/privacy", h.SetAccountPrivacy)
+		user.GET("/follow-requests", h.GetFollowRequests)
+		user.POST("/follow-requests/:user_id/accept", h.AcceptFollowRequest)
+		user.DELETE("/follow-requests/:user_id", h.RejectFollowRequest)
+		user.DELETE("/followers/:user_id", h.RemoveFollower)
+		user.GET("/recommendations", h.GetRecommendations)
+	}
+
+	users := api.Group("/users")
+	users.Use(h.userIdentity)
+	{
+		users.GET("/:id", h.GetUserPublicProfile)
+		users.POST("/:id/follow", h.FollowUser)
+		users.DELETE("/:id/follow", h.UnfollowUser)
+		users.GET("/:id/followers", h.GetUserFollowers)
+		users.GET("/:id/following", h.GetUserFollowing)
+		users.GET("/:id/activity", h.GetUserActivity)
 	}
 
 	collectinons := api.Group("/collections")
@@ -57,6 +113,27 @@ func (h *Handler) InitRoutes() *gin.Engine {
 		collectinons.GET("/:id/items", h.GetCollectionItems)
 		// Add item to collection
 		collectinons.POST("/:id/items", h.AddItemToCollection)
+		collectinons.GET("/:id/tags", h.GetCollectionTags)
+		collectinons.PUT("/:id/tags", h.SetCollectionTags)
+		collectinons.POST("/:id/cover", h.UploadCollectionCover)
+		collectinons.GET("/:id/export", h.ExportCollection)
+		collectinons.PUT("/:id/visibility", h.SetCollectionVisibility)
+
+		// Лайки, сохранения и комментарии
+		collectinons.GET("/:id/engagement", h.GetCollectionEngagement)
+		collectinons.PUT("/:id/like", h.LikeCollection)
+		collectinons.DELETE("/:id/like", h.UnlikeCollection)
+		collectinons.PUT("/:id/save", h.SaveCollection)
+		collectinons.DELETE("/:id/save", h.UnsaveCollection)
+		collectinons.GET("/:id/comments", h.GetCollectionComments)
+		collectinons.POST("/:id/comments", h.AddCollectionComment)
+	}
+
+	comments := api.Group("/comments")
+	comments.Use(h.userIdentity)
+	{
+		comments.PUT("/:id", h.EditCollectionComment)
+		comments.DELETE("/:id", h.DeleteCollectionComment)
 	}
 
 	collectinon_items := api.Group("/items")
@@ -64,6 +141,118 @@ func (h *Handler) InitRoutes() *gin.Engine {
 	{
 		collectinon_items.GET("", h.GetItemsByType)
 		collectinon_items.POST("", h.CreateCollectionItem)
+		collectinon_items.GET("/suggest", h.SuggestItems)
+		collectinon_items.GET("/duplicates", h.FindDuplicates)
+		collectinon_items.GET("/lookup", h.LookupItems)
+		collectinon_items.POST("/import", h.ImportItem)
+		collectinon_items.GET("/:id", h.GetItem)
+		collectinon_items.PATCH("/:id", h.UpdateOwnItem)
+		collectinon_items.GET("/:id/similar", h.GetSimilarItems)
+		collectinon_items.POST("/:id/merge", h.MergeItems)
+		collectinon_items.POST("/:id/submit", h.SubmitItemForReview)
+		collectinon_items.GET("/:id/moderation", h.GetItemModerationHistory)
+		collectinon_items.POST("/:id/cover", h.UploadItemCover)
+
+		// Внешние каталоги: обновление и закрепленные поля
+		collectinon_items.GET("/:id/external-ids", h.GetItemExternalIDs)
+		collectinon_items.POST("/:id/refresh", h.RefreshItemMetadata)
+		collectinon_items.GET("/:id/locks", h.GetItemLockedFields)
+		collectinon_items.PUT("/:id/locks/:field", h.LockItemField)
+		collectinon_items.DELETE("/:id/locks/:field", h.UnlockItemField)
+
+		// Предложенные правки и история изменений
+		collectinon_items.POST("/:id/edits", h.SuggestItemEdit)
+		collectinon_items.GET("/:id/edits", h.GetItemEditSuggestions)
+		collectinon_items.GET("/:id/history", h.GetItemHistory)
+		collectinon_items.POST("/:id/history/:revision_id/revert", h.RevertItemRevision)
+
+		// Сезоны и эпизоды сериалов/аниме
+		collectinon_items.GET("/:id/seasons", h.GetItemSeasons)
+		collectinon_items.POST("/:id/seasons", h.CreateSeason)
+		collectinon_items.POST("/:id/seasons/:season/episodes", h.AddEpisode)
+		collectinon_items.PUT("/:id/seasons/:season/watched", h.MarkSeasonWatched)
+		collectinon_items.DELETE("/:id/seasons/:season/watched", h.UnmarkSeasonWatched)
+		collectinon_items.PUT("/:id/episodes/:episode_id/watched", h.MarkEpisodeWatched)
+		collectinon_items.DELETE("/:id/episodes/:episode_id/watched", h.UnmarkEpisodeWatched)
+		collectinon_items.GET("/:id/progress", h.GetItemProgress)
+		collectinon_items.PUT("/:id/rating", h.RateItem)
+		collectinon_items.DELETE("/:id/rating", h.DeleteItemRating)
+
+		// Связи между элементами
+		collectinon_items.GET("/:id/relations", h.GetItemRelations)
+		collectinon_items.POST("/:id/relations", h.CreateItemRelation)
+		collectinon_items.DELETE("/:id/relations/:relation_id", h.DeleteItemRelation)
+
+		// Жанры и теги
+		collectinon_items.GET("/:id/genres", h.GetItemGenres)
+		collectinon_items.PUT("/:id/genres", h.SetItemGenres)
+		collectinon_items.GET("/:id/tags", h.GetItemTags)
+		collectinon_items.PUT("/:id/tags", h.SetItemTags)
+	}
+
+	search := api.Group("/search")
+	search.Use(h.userIdentity)
+	{
+		search.GET("", h.Search)
+	}
+
+	genres := api.Group("/genres")
+	genres.Use(h.userIdentity)
+	{
+		genres.GET("", h.GetGenres)
+		genres.POST("", h.CreateGenre)
+	}
+
+	tags := api.Group("/tags")
+	tags.Use(h.userIdentity)
+	{
+		tags.GET("", h.GetUserTags)
+		tags.GET("/suggest", h.SuggestTags)
+	}
+
+	franchises := api.Group("/franchises")
+	franchises.Use(h.userIdentity)
+	{
+		franchises.GET("", h.GetFranchises)
+		franchises.POST("", h.CreateFranchise)
+		franchises.GET("/:id", h.GetFranchise)
+		franchises.POST("/:id/items", h.AddItemToFranchise)
+		franchises.DELETE("/:id/items/:item_id", h.RemoveItemFromFranchise)
+	}
+
+	moderation := api.Group("/moderation")
+	moderation.Use(h.userIdentity)
+	{
+		moderation.GET("/queue", h.GetModerationQueue)
+		moderation.POST("/items/:id/approve", h.ApproveItem)
+		moderation.POST("/items/:id/reject", h.RejectItem)
+		moderation.POST("/items/:id/request-changes", h.RequestItemChanges)
+		moderation.GET("/edits", h.GetPendingEdits)
+		moderation.POST("/edits/:id/accept", h.AcceptEdit)
+		moderation.POST("/edits/:id/reject", h.RejectEdit)
+	}
+
+	imports := api.Group("/imports")
+	imports.Use(h.userIdentity)
+	{
+		imports.POST("", h.CreateImport)
+		imports.GET("", h.GetImports)
+		imports.GET("/:id", h.GetImport)
+		imports.GET("/:id/unmatched", h.GetImportUnmatchedRows)
+		imports.POST("/:id/rows/:row_id/resolve", h.ResolveImportRow)
+	}
+
+	webhooks := api.Group("/webhooks")
+	webhooks.Use(h.userIdentity)
+	{
+		webhooks.GET("", h.GetWebhooks)
+		webhooks.POST("", h.CreateWebhook)
+		webhooks.GET("/events", h.GetWebhookEventTypes)
+		webhooks.GET("/:id", h.GetWebhook)
+		webhooks.PUT("/:id", h.UpdateWebhook)
+		webhooks.DELETE("/:id", h.DeleteWebhook)
+		webhooks.GET("/:id/deliveries", h.GetWebhookDeliveries)
+		webhooks.POST("/:id/test", h.SendWebhookTestEvent)
 	}
 
 	return router
@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/service"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/blobstore"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/responses"
+)
+
+const (
+	// Жесткий предел тела multipart-запроса. Допустимый размер самого файла проверяет сервис
+	maxUploadRequestSize = 32 << 20
+	uploadFormField      = "file"
+	// Браузер может кешировать редирект, пока подписанная ссылка гарантированно действует
+	imageRedirectMaxAge = 60
+)
+
+// UploadItemCover uploads a cover image for an item
+// @Summary Загрузить обложку элемента
+// @Description Изображение jpeg, png или gif проверяется по содержимому, для него создаются миниатюры small, medium и large. Обложку публичного элемента меняет модератор, приватного - автор
+// @Tags images
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "ID элемента"
+// @Param file formData file true "Изображение"
+// @Success 201 {object} models.ImageUpload "Загруженное изображение"
+// @Failure 400 {object} ErrorResponse "Файл не передан или не является изображением"
+// @Failure 403 {object} ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} ErrorResponse "Элемент не найден"
+// @Failure 413 {object} ErrorResponse "Файл слишком большой"
+// @Security ApiKeyAuth
+// @Router /items/{id}/cover [post]
+func (h *Handler) UploadItemCover(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	h.handleUpload(c, func(file io.Reader) (*models.ImageUpload, error) {
+		return h.service.ImageService.UploadItemCover(userID, c.Param("id"), file)
+	})
+}
+
+// UploadCollectionCover uploads a cover image for a collection
+// @Summary Загрузить обложку коллекции
+// @Description Изображение jpeg, png или gif проверяется по содержимому, для него создаются миниатюры small, medium и large
+// @Tags images
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "ID коллекции"
+// @Param file formData file true "Изображение"
+// @Success 201 {object} models.ImageUpload "Загруженное изображение"
+// @Failure 400 {object} ErrorResponse "Файл не передан или не является изображением"
+// @Failure 403 {object} ErrorResponse "Коллекция принадлежит другому пользователю"
+// @Failure 404 {object} ErrorResponse "Коллекция не найдена"
+// @Failure 413 {object} ErrorResponse "Файл слишком большой"
+// @Security ApiKeyAuth
+// @Router /collections/{id}/cover [post]
+func (h *Handler) UploadCollectionCover(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	h.handleUpload(c, func(file io.Reader) (*models.ImageUpload, error) {
+		return h.service.ImageService.UploadCollectionCover(userID, c.Param("id"), file)
+	})
+}
+
+// UploadAvatar uploads user's avatar
+// @Summary Загрузить аватар
+// @Description Изображение jpeg, png или gif проверяется по содержимому, для него создаются миниатюры small, medium и large
+// @Tags images
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Изображение"
+// @Success 201 {object} models.ImageUpload "Загруженное изображение"
+// @Failure 400 {object} ErrorResponse "Файл не передан или не является изображением"
+// @Failure 413 {object} ErrorResponse "Файл слишком большой"
+// @Security ApiKeyAuth
+// @Router /user/avatar [post]
+func (h *Handler) UploadAvatar(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	h.handleUpload(c, func(file io.Reader) (*models.ImageUpload, error) {
+		return h.service.ImageService.UploadAvatar(userID, file)
+	})
+}
+
+// GetImage redirects to a signed url of an image
+// @Summary Получить изображение
+// @Description Постоянная ссылка на изображение: перенаправляет на подписанную ссылку с ограниченным сроком действия
+// @Tags images
+// @Param id path string true "ID изображения"
+// @Param size query string false "Размер" Enums(original, small, medium, large)
+// @Success 302 "Перенаправление на подписанную ссылку"
+// @Failure 400 {object} ErrorResponse "Неизвестный размер"
+// @Failure 404 {object} ErrorResponse "Изображение не найдено"
+// @Router /images/{id} [get]
+func (h *Handler) GetImage(c *gin.Context) {
+	url, err := h.service.ImageService.GetImageURL(c.Param("id"), c.Query("size"))
+	if err != nil {
+		h.handleImageError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age="+strconv.Itoa(imageRedirectMaxAge))
+	c.Redirect(http.StatusFound, url)
+}
+
+// GetFile serves a stored file by a signed url
+// @Summary Файл по подписанной ссылке
+// @Description Используется локальным хранилищем. Ссылки выдает /images/{id}
+// @Tags images
+// @Produce octet-stream
+// @Param key path string true "Ключ файла"
+// @Param expires query int true "Срок действия ссылки (unix)"
+// @Param signature query string true "Подпись"
+// @Success 200 {file} file "Файл"
+// @Failure 403 {object} ErrorResponse "Неверная или просроченная подпись"
+// @Failure 404 {object} ErrorResponse "Файл не найден"
+// @Router /files/{key} [get]
+func (h *Handler) GetFile(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+
+	object, err := h.service.ImageService.OpenFile(c.Request.Context(), key, c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		h.handleImageError(c, err)
+		return
+	}
+	defer object.Body.Close()
+
+	// Содержимое по ключу не меняется, но кеш не должен переживать подпись
+	c.Header("Cache-Control", "private, max-age="+strconv.Itoa(imageRedirectMaxAge))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.DataFromReader(http.StatusOK, object.Size, object.ContentType, object.Body, nil)
+}
+
+// GetCollectionCover returns collection cover or a generated mosaic
+// @Summary Обложка коллекции
+// @Description Собственная обложка коллекции (перенаправление) или мозаика из обложек первых 4 или 9 элементов. Если обложек нет - заглушка по типу коллекции. Мозаика пересобирается при изменении содержимого коллекции. Токен нужен только для приватных коллекций
+// @Tags images
+// @Produce png
+// @Param id path string true "ID коллекции"
+// @Success 200 {file} file "Обложка в PNG"
+// @Success 302 "Перенаправление на собственную обложку"
+// @Success 304 "Обложка не изменилась"
+// @Failure 403 {object} ErrorResponse "Коллекция приватная"
+// @Failure 404 {object} ErrorResponse "Коллекция не найдена"
+// @Router /collections/{id}/cover.png [get]
+func (h *Handler) GetCollectionCover(c *gin.Context) {
+	cover, err := h.service.CollectionCoverService.GetCollectionCover(c.Request.Context(), c.GetInt(userCtx), c.Param("id"))
+	if err != nil {
+		h.handleImageError(c, err)
+		return
+	}
+
+	if cover.RedirectURL != "" {
+		c.Redirect(http.StatusFound, cover.RedirectURL)
+		return
+	}
+
+	etag := `"` + cover.ETag + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "private, no-cache")
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, cover.ContentType, cover.Data)
+}
+
+// handleUpload достает файл из multipart-формы и передает его в upload
+func (h *Handler) handleUpload(c *gin.Context, upload func(file io.Reader) (*models.ImageUpload, error)) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxUploadRequestSize)
+
+	header, err := c.FormFile(uploadFormField)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			responses.NewErrorResponse(c, http.StatusRequestEntityTooLarge, service.ErrImageTooLarge.Error())
+			return
+		}
+		responses.BadRequest(c, "multipart field \""+uploadFormField+"\" with image file is required")
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		responses.BadRequest(c, "failed to read uploaded file")
+		return
+	}
+	defer file.Close()
+
+	image, err := upload(file)
+	if err != nil {
+		h.handleImageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, image)
+}
+
+func (h *Handler) handleImageError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrItemNotFound):
+		responses.NotFound(c, "Item not found")
+	case errors.Is(err, service.ErrCollectionNotFound):
+		responses.NotFound(c, "Collection not found")
+	case errors.Is(err, service.ErrImageNotFound), errors.Is(err, service.ErrFileNotFound),
+		errors.Is(err, service.ErrSignedURLUnsupported):
+		responses.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrItemCoverForbidden), errors.Is(err, service.ErrNotCollectionOwner),
+		errors.Is(err, blobstore.ErrInvalidSignature):
+		responses.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrImageUnsupported), errors.Is(err, service.ErrInvalidImageSize):
+		responses.BadRequest(c, err.Error())
+	case errors.Is(err, service.ErrImageTooLarge):
+		responses.NewErrorResponse(c, http.StatusRequestEntityTooLarge, err.Error())
+	default:
+		h.logger.Errorf("Image operation failed: %v", err)
+		responses.InternalServerErrorWithDetails(c, "internal server error")
+	}
+}
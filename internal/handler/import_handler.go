@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/service"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/importer"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/responses"
+)
+
+// PaginatedImportsResponse represents import jobs page
+type PaginatedImportsResponse struct {
+	Data       []models.ImportJob            `json:"data"`
+	Pagination pagination.PaginationResponse `json:"pagination"`
+}
+
+// PaginatedImportRowsResponse represents import rows page
+type PaginatedImportRowsResponse struct {
+	Data       []models.ImportRow            `json:"data"`
+	Pagination pagination.PaginationResponse `json:"pagination"`
+}
+
+// ResolveImportRowInput represents manual match of an import row
+type ResolveImportRowInput struct {
+	ItemID string `json:"item_id" binding:"required"`
+}
+
+// CreateImport uploads an export of another tracker
+// @Summary Импортировать библиотеку
+// @Description Выгрузка Goodreads (CSV), MyAnimeList (XML, можно .xml.gz), Letterboxd (ZIP или отдельный CSV) или IMDb (CSV оценок или списка).
+// @Description Строки сопоставляются с каталогом в фоне: по внешним ID, затем по названию и году. Полки и списки становятся коллекциями,
+// @Description статусы, оценки, даты и отзывы переносятся в прогресс. Ход импорта - в GET /imports/{id}, по завершении приходит уведомление
+// @Tags imports
+// @Accept multipart/form-data
+// @Produce json
+// @Param format formData string true "Формат выгрузки" Enums(goodreads, mal, letterboxd, imdb)
+// @Param file formData file true "Файл выгрузки (до 20 МБ)"
+// @Param create_custom formData bool false "Создавать свои элементы для несопоставленных строк" default(true)
+// @Success 202 {object} models.ImportJob "Задача импорта"
+// @Failure 400 {object} ErrorResponse "Неизвестный формат или файл не соответствует формату"
+// @Failure 413 {object} ErrorResponse "Файл слишком большой"
+// @Security ApiKeyAuth
+// @Router /imports [post]
+func (h *Handler) CreateImport(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxUploadRequestSize)
+
+	header, err := c.FormFile(uploadFormField)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			responses.NewErrorResponse(c, http.StatusRequestEntityTooLarge, service.ErrImportFileTooLarge.Error())
+			return
+		}
+		responses.BadRequest(c, "multipart field \""+uploadFormField+"\" with export file is required")
+		return
+	}
+
+	createCustom := true
+	if value := c.PostForm("create_custom"); value != "" {
+		if createCustom, err = strconv.ParseBool(value); err != nil {
+			responses.BadRequest(c, "create_custom must be a boolean")
+			return
+		}
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		responses.BadRequest(c, "failed to read uploaded file")
+		return
+	}
+	defer file.Close()
+
+	job, err := h.service.ImportService.CreateImport(userID, c.PostForm("format"), header.Filename, file, createCustom)
+	if err != nil {
+		h.handleImportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+// GetImports returns current user's imports
+// @Summary Импорты пользователя
+// @Description Задачи импорта, сначала новые
+// @Tags imports
+// @Produce json
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Кол-во задач на странице" default(10)
+// @Success 200 {object} PaginatedImportsResponse "Задачи импорта"
+// @Security ApiKeyAuth
+// @Router /imports [get]
+func (h *Handler) GetImports(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	jobs, err := h.service.ImportService.GetImports(userID, GetPaginationParams(c))
+	if err != nil {
+		h.handleImportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, jobs)
+}
+
+// GetImport returns import progress
+// @Summary Ход импорта
+// @Description Статус задачи, счетчики строк и процент обработанных строк
+// @Tags imports
+// @Produce json
+// @Param id path string true "ID импорта"
+// @Success 200 {object} models.ImportJob "Задача импорта"
+// @Failure 404 {object} ErrorResponse "Импорт не найден"
+// @Security ApiKeyAuth
+// @Router /imports/{id} [get]
+func (h *Handler) GetImport(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	job, err := h.service.ImportService.GetImport(userID, c.Param("id"))
+	if err != nil {
+		h.handleImportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// GetImportUnmatchedRows returns rows without a catalog item
+// @Summary Несопоставленные строки импорта
+// @Description Строки, для которых не нашелся элемент каталога: созданные свои элементы (created), пропущенные (unmatched) и ошибочные (failed)
+// @Tags imports
+// @Produce json
+// @Param id path string true "ID импорта"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Кол-во строк на странице" default(10)
+// @Success 200 {object} PaginatedImportRowsResponse "Строки импорта"
+// @Failure 404 {object} ErrorResponse "Импорт не найден"
+// @Security ApiKeyAuth
+// @Router /imports/{id}/unmatched [get]
+func (h *Handler) GetImportUnmatchedRows(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	rows, err := h.service.ImportService.GetUnmatchedRows(userID, c.Param("id"), GetPaginationParams(c))
+	if err != nil {
+		h.handleImportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rows)
+}
+
+// ResolveImportRow matches an import row with a catalog item manually
+// @Summary Сопоставить строку импорта
+// @Description Элемент добавляется в коллекции строки, переносятся статус, оценка и отзыв. Свой элемент, созданный импортом для строки, сливается с выбранным
+// @Tags imports
+// @Accept json
+// @Produce json
+// @Param id path string true "ID импорта"
+// @Param row_id path int true "ID строки"
+// @Param input body ResolveImportRowInput true "Элемент каталога"
+// @Success 200 {object} models.ImportRow "Сопоставленная строка"
+// @Failure 400 {object} ErrorResponse "Тип элемента не совпадает со строкой"
+// @Failure 404 {object} ErrorResponse "Импорт, строка или элемент не найдены"
+// @Failure 409 {object} ErrorResponse "Строка уже сопоставлена"
+// @Security ApiKeyAuth
+// @Router /imports/{id}/rows/{row_id}/resolve [post]
+func (h *Handler) ResolveImportRow(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	rowID, err := strconv.Atoi(c.Param("row_id"))
+	if err != nil {
+		responses.BadRequest(c, "row id is not valid")
+		return
+	}
+
+	var input ResolveImportRowInput
+	if err := c.BindJSON(&input); err != nil {
+		responses.NewErrorResponse(c, http.StatusBadRequest, "input is not valid")
+		return
+	}
+
+	row, err := h.service.ImportService.ResolveRow(userID, c.Param("id"), rowID, input.ItemID)
+	if err != nil {
+		h.handleImportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, row)
+}
+
+func (h *Handler) handleImportError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrImportNotFound), errors.Is(err, service.ErrImportRowNotFound),
+		errors.Is(err, service.ErrItemNotFound):
+		responses.NotFound(c, err.Error())
+	case errors.Is(err, importer.ErrUnsupportedFormat), errors.Is(err, importer.ErrInvalidFile),
+		errors.Is(err, importer.ErrTooManyRecords), errors.Is(err, service.ErrImportFileEmpty),
+		errors.Is(err, service.ErrImportNothingToImport), errors.Is(err, service.ErrImportTypeMismatch):
+		responses.BadRequest(c, err.Error())
+	case errors.Is(err, service.ErrImportRowNotResolvable):
+		responses.Conflict(c, err.Error())
+	case errors.Is(err, service.ErrImportFileTooLarge):
+		responses.NewErrorResponse(c, http.StatusRequestEntityTooLarge, err.Error())
+	default:
+		h.logger.Errorf("Import operation failed: %v", err)
+		responses.InternalServerErrorWithDetails(c, "internal server error")
+	}
+}
@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/service"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/responses"
+)
+
+// ImportItemInput represents an external catalog record to import
+type ImportItemInput struct {
+	Provider   string `json:"provider" binding:"required" example:"openlibrary"`
+	ExternalID string `json:"external_id" binding:"required" example:"OL893415W"`
+}
+
+// LookupItems searches external catalogs
+// @Summary Найти элемент во внешних каталогах
+// @Description Поиск в OpenLibrary (книги по названию или ISBN), TMDB (фильмы и сериалы), AniList и Shikimori (аниме).
+// @Description Провайдеры опрашиваются параллельно, ошибка одного из них возвращается в errors и не прерывает поиск.
+// @Description Уже импортированные записи содержат existing_item_id
+// @Tags items
+// @Produce json
+// @Param q query string false "Название"
+// @Param isbn query string false "ISBN книги"
+// @Param type query string false "Тип элементов" Enums(books, anime, series, movies)
+// @Param provider query string false "Только один провайдер" Enums(openlibrary, tmdb, anilist, shikimori)
+// @Param limit query int false "Результатов от каждого провайдера (по умолчанию 10, максимум 25)"
+// @Success 200 {object} models.LookupResponse "Найденные записи"
+// @Failure 400 {object} ErrorResponse "Неверный запрос"
+// @Security ApiKeyAuth
+// @Router /items/lookup [get]
+func (h *Handler) LookupItems(c *gin.Context) {
+	limit, err := GetOptionalIntQuery(c, "limit")
+	if err != nil {
+		responses.BadRequest(c, err.Error())
+		return
+	}
+	query := models.LookupQuery{
+		Text:     c.Query("q"),
+		ISBN:     c.Query("isbn"),
+		Type:     c.Query("type"),
+		Provider: c.Query("provider"),
+	}
+	if limit != nil {
+		query.Limit = *limit
+	}
+
+	result, err := h.service.MetadataService.Lookup(c.Request.Context(), query)
+	if err != nil {
+		h.handleMetadataError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ImportItem creates a catalog item from an external catalog record
+// @Summary Импортировать элемент из внешнего каталога
+// @Description Создает публичный элемент каталога с названием, описанием, годом, обложкой и жанрами из записи провайдера.
+// @Description Внешний ID сохраняется: повторный импорт той же записи возвращает существующий элемент с кодом 200
+// @Tags items
+// @Accept json
+// @Produce json
+// @Param input body ImportItemInput true "Запись внешнего каталога"
+// @Success 201 {object} models.ItemImportResult "Элемент создан"
+// @Success 200 {object} models.ItemImportResult "Запись уже импортирована"
+// @Failure 400 {object} ErrorResponse "Неизвестный провайдер или неверный внешний ID"
+// @Failure 404 {object} ErrorResponse "Запись не найдена во внешнем каталоге"
+// @Failure 502 {object} ErrorResponse "Внешний каталог недоступен"
+// @Security ApiKeyAuth
+// @Router /items/import [post]
+func (h *Handler) ImportItem(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	var input ImportItemInput
+	if err := c.BindJSON(&input); err != nil {
+		responses.NewErrorResponse(c, http.StatusBadRequest, "input is not valid")
+		return
+	}
+
+	result, err := h.service.MetadataService.Import(c.Request.Context(), userID, input.Provider, input.ExternalID)
+	if err != nil {
+		h.handleMetadataError(c, err)
+		return
+	}
+
+	status := http.StatusOK
+	if result.Created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, result)
+}
+
+// GetItemExternalIDs returns external catalog records linked to an item
+// @Summary Внешние ID элемента
+// @Tags items
+// @Produce json
+// @Param id path string true "ID элемента"
+// @Success 200 {array} models.ExternalID "Записи внешних каталогов"
+// @Security ApiKeyAuth
+// @Router /items/{id}/external-ids [get]
+func (h *Handler) GetItemExternalIDs(c *gin.Context) {
+	ids, err := h.service.MetadataService.GetItemExternalIDs(c.Param("id"))
+	if err != nil {
+		h.handleMetadataError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ids)
+}
+
+// RefreshItemMetadata refreshes an imported item from its external catalog
+// @Summary Обновить элемент из внешнего каталога
+// @Description Доступно модераторам. Загружает запись основного внешнего каталога элемента и применяет изменения,
+// @Description кроме закрепленных полей. Изменения сохраняются ревизией с именем провайдера, ошибка каталога - в поле error
+// @Tags items
+// @Produce json
+// @Param id path string true "ID элемента"
+// @Success 200 {object} models.MetadataRefreshResult "Итог обновления"
+// @Failure 400 {object} ErrorResponse "Элемент не импортирован из внешнего каталога"
+// @Failure 403 {object} ErrorResponse "Нужна роль модератора"
+// @Failure 404 {object} ErrorResponse "Элемент не найден"
+// @Security ApiKeyAuth
+// @Router /items/{id}/refresh [post]
+func (h *Handler) RefreshItemMetadata(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	result, err := h.service.MetadataRefreshService.RefreshItem(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		h.handleMetadataError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetItemLockedFields returns item fields locked from metadata refresh
+// @Summary Закрепленные поля элемента
+// @Description Поля, которые обновление из внешних каталогов не меняет
+// @Tags items
+// @Produce json
+// @Param id path string true "ID элемента"
+// @Success 200 {array} models.ItemFieldLock "Закрепленные поля"
+// @Failure 404 {object} ErrorResponse "Элемент не найден"
+// @Security ApiKeyAuth
+// @Router /items/{id}/locks [get]
+func (h *Handler) GetItemLockedFields(c *gin.Context) {
+	locks, err := h.service.MetadataRefreshService.GetLockedFields(c.Param("id"))
+	if err != nil {
+		h.handleMetadataError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, locks)
+}
+
+// LockItemField locks an item field from metadata refresh
+// @Summary Закрепить поле элемента
+// @Description Доступно модераторам. Обновление из внешних каталогов перестает менять поле
+// @Tags items
+// @Produce json
+// @Param id path string true "ID элемента"
+// @Param field path string true "Поле" Enums(title, description, cover_image, release_year)
+// @Success 200 {object} models.ItemFieldLock "Поле закреплено"
+// @Failure 400 {object} ErrorResponse "Поле нельзя закрепить"
+// @Failure 403 {object} ErrorResponse "Нужна роль модератора"
+// @Failure 404 {object} ErrorResponse "Элемент не найден"
+// @Security ApiKeyAuth
+// @Router /items/{id}/locks/{field} [put]
+func (h *Handler) LockItemField(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	lock, err := h.service.MetadataRefreshService.LockField(userID, c.Param("id"), c.Param("field"))
+	if err != nil {
+		h.handleMetadataError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, lock)
+}
+
+// UnlockItemField unlocks an item field
+// @Summary Открепить поле элемента
+// @Description Доступно модераторам
+// @Tags items
+// @Produce json
+// @Param id path string true "ID элемента"
+// @Param field path string true "Поле" Enums(title, description, cover_image, release_year)
+// @Success 200 {object} SuccessResponse "Поле откреплено"
+// @Failure 403 {object} ErrorResponse "Нужна роль модератора"
+// @Failure 404 {object} ErrorResponse "Поле не закреплено"
+// @Security ApiKeyAuth
+// @Router /items/{id}/locks/{field} [delete]
+func (h *Handler) UnlockItemField(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	if err := h.service.MetadataRefreshService.UnlockField(userID, c.Param("id"), c.Param("field")); err != nil {
+		h.handleMetadataError(c, err)
+		return
+	}
+
+	responses.Success(c, "Field has been unlocked")
+}
+
+func (h *Handler) handleMetadataError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrExternalItemNotFound), errors.Is(err, service.ErrItemNotFound),
+		errors.Is(err, service.ErrFieldNotLocked):
+		responses.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrModeratorRequired):
+		responses.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrUnknownProvider), errors.Is(err, service.ErrInvalidExternalID),
+		errors.Is(err, service.ErrLookupQueryEmpty), errors.Is(err, service.ErrLookupInvalidType),
+		errors.Is(err, service.ErrItemNotImported), errors.Is(err, service.ErrInvalidLockField):
+		responses.BadRequest(c, err.Error())
+	case errors.Is(err, service.ErrProviderUnavailable):
+		responses.NewErrorResponse(c, http.StatusBadGateway, err.Error())
+	default:
+		h.logger.Errorf("Metadata operation failed: %v", err)
+		responses.InternalServerErrorWithDetails(c, "internal server error")
+	}
+}
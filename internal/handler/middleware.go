@@ -2,11 +2,13 @@ package handler
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
 	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
 )
 
@@ -46,6 +48,28 @@ func (h *Handler) userIdentity(c *gin.Context) {
 
 }
 
+// optionalUserIdentity определяет пользователя, если передан валидный токен, и пропускает анонимные запросы
+func (h *Handler) optionalUserIdentity(c *gin.Context) {
+	headerParts := strings.Split(c.GetHeader(authorizationHeader), " ")
+	if len(headerParts) != 2 {
+		return
+	}
+	if userId, err := h.service.AuthService.ParseToken(headerParts[1]); err == nil {
+		c.Set(userCtx, userId)
+	}
+}
+
+// streamIdentity - EventSource в браузере не умеет передавать заголовки, поэтому для потока событий
+// токен можно передать еще и параметром access_token
+func (h *Handler) streamIdentity(c *gin.Context) {
+	if c.GetHeader(authorizationHeader) == "" {
+		if token := c.Query("access_token"); token != "" {
+			c.Request.Header.Set(authorizationHeader, "Bearer "+token)
+		}
+	}
+	h.userIdentity(c)
+}
+
 func (h *Handler) GetUserId(c *gin.Context) (int, error) {
 	id, ok := c.Get(userCtx)
 	if !ok {
@@ -84,3 +108,73 @@ func GetPaginationParams(c *gin.Context) pagination.PaginationRequest {
 
 	return pagination.NewPaginationRequest(page, limit)
 }
+
+// GetCursorParams - keyset-пагинация включается параметром cursor (пустой - первая страница):
+// ?cursor=&limit=20&with_total=true. ok = false - клиент использует постраничный режим
+func GetCursorParams(c *gin.Context) (req pagination.CursorRequest, ok bool, err error) {
+	raw, ok := c.GetQuery("cursor")
+	if !ok {
+		return req, false, nil
+	}
+
+	var cursor *pagination.Cursor
+	if raw != "" {
+		if cursor, err = pagination.DecodeCursor(raw); err != nil {
+			return req, true, err
+		}
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		return req, true, fmt.Errorf("limit is not valid")
+	}
+
+	return pagination.NewCursorRequest(cursor, limit, c.Query("with_total") == "true"), true, nil
+}
+
+// GetListQueryParam парсит список значений из query: ?name=a,b или ?name=a&name=b
+func GetListQueryParam(c *gin.Context, name string) []string {
+	var values []string
+	for _, raw := range c.QueryArray(name) {
+		for _, value := range strings.Split(raw, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// GetTagFilterParams парсит фильтр по тегам: ?tags=a,b&tags_mode=and|or (по умолчанию or)
+func GetTagFilterParams(c *gin.Context) models.TagFilter {
+	return models.TagFilter{
+		Tags:     GetListQueryParam(c, "tags"),
+		MatchAll: strings.EqualFold(c.Query("tags_mode"), "and"),
+	}
+}
+
+// GetOptionalIntQuery возвращает nil, если параметр не передан
+func GetOptionalIntQuery(c *gin.Context, name string) (*int, error) {
+	raw, ok := c.GetQuery(name)
+	if !ok || raw == "" {
+		return nil, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not valid", name)
+	}
+	return &value, nil
+}
+
+// GetOptionalFloatQuery возвращает nil, если параметр не передан
+func GetOptionalFloatQuery(c *gin.Context, name string) (*float64, error) {
+	raw, ok := c.GetQuery(name)
+	if !ok || raw == "" {
+		return nil, nil
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not valid", name)
+	}
+	return &value, nil
+}
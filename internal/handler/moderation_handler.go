@@ -0,0 +1,234 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/service"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/responses"
+)
+
+// ModerationReasonInput represents moderator's reason for rejection or change request
+type ModerationReasonInput struct {
+	Reason string `json:"reason" binding:"required" example:"Укажите год выхода и обложку"`
+}
+
+// PaginatedModerationQueueResponse represents moderation queue page
+type PaginatedModerationQueueResponse struct {
+	Data       []models.CollectionItem       `json:"data"`
+	Pagination pagination.PaginationResponse `json:"pagination"`
+}
+
+// GetModerationQueue returns items pending review
+// @Summary Очередь модерации
+// @Description Элементы, ожидающие проверки, сначала самые старые. Доступно модераторам и администраторам
+// @Tags moderation
+// @Produce json
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Кол-во элементов на странице" default(10)
+// @Success 200 {object} PaginatedModerationQueueResponse "Очередь"
+// @Failure 403 {object} ErrorResponse "Доступ запрещен"
+// @Security ApiKeyAuth
+// @Router /moderation/queue [get]
+func (h *Handler) GetModerationQueue(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	queue, err := h.service.ModerationService.GetQueue(userID, GetPaginationParams(c))
+	if err != nil {
+		h.handleModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, queue)
+}
+
+// SubmitItemForReview submits own item for publication
+// @Summary Отправить элемент на модерацию
+// @Description Автор отправляет свой элемент на проверку для публикации в каталоге, в том числе после отклонения или запроса правок
+// @Tags moderation
+// @Produce json
+// @Param id path string true "ID элемента"
+// @Success 200 {object} SuccessResponse "Элемент отправлен на проверку"
+// @Failure 403 {object} ErrorResponse "Элемент создан другим пользователем"
+// @Failure 404 {object} ErrorResponse "Элемент не найден"
+// @Failure 409 {object} ErrorResponse "Элемент уже на проверке или опубликован"
+// @Security ApiKeyAuth
+// @Router /items/{id}/submit [post]
+func (h *Handler) SubmitItemForReview(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	if err := h.service.ModerationService.SubmitItem(userID, c.Param("id")); err != nil {
+		h.handleModerationError(c, err)
+		return
+	}
+
+	responses.Success(c, "Item submitted for review")
+}
+
+// UpdateOwnItem changes fields of own item before submission or after review
+// @Summary Изменить свой элемент
+// @Description Автор меняет поля своего элемента, пока он не на проверке и не опубликован: до отправки, после отклонения
+// @Description или запроса правок. После изменения элемент можно снова отправить на проверку через /items/{id}/submit
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Param id path string true "ID элемента"
+// @Param input body models.ItemChanges true "Изменения"
+// @Success 200 {object} models.CollectionItem "Измененный элемент"
+// @Failure 400 {object} ErrorResponse "Неверные входные данные"
+// @Failure 403 {object} ErrorResponse "Элемент создан другим пользователем"
+// @Failure 404 {object} ErrorResponse "Элемент не найден"
+// @Failure 409 {object} ErrorResponse "Элемент на проверке или опубликован"
+// @Security ApiKeyAuth
+// @Router /items/{id} [patch]
+func (h *Handler) UpdateOwnItem(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	var changes models.ItemChanges
+	if err := c.BindJSON(&changes); err != nil {
+		responses.NewErrorResponse(c, http.StatusBadRequest, "input is not valid")
+		return
+	}
+
+	item, err := h.service.ModerationService.UpdateItem(userID, c.Param("id"), changes)
+	if err != nil {
+		h.handleModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// GetItemModerationHistory returns moderation audit trail of an item
+// @Summary Журнал модерации элемента
+// @Description Доступно автору элемента и модераторам
+// @Tags moderation
+// @Produce json
+// @Param id path string true "ID элемента"
+// @Success 200 {array} models.ModerationDecision "Журнал"
+// @Failure 403 {object} ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} ErrorResponse "Элемент не найден"
+// @Security ApiKeyAuth
+// @Router /items/{id}/moderation [get]
+func (h *Handler) GetItemModerationHistory(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	history, err := h.service.ModerationService.GetItemHistory(userID, c.Param("id"))
+	if err != nil {
+		h.handleModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// ApproveItem promotes a pending item into the public catalog
+// @Summary Одобрить элемент
+// @Description Элемент становится публичным элементом каталога, автор получает уведомление
+// @Tags moderation
+// @Produce json
+// @Param id path string true "ID элемента"
+// @Success 200 {object} models.ModerationDecision "Решение"
+// @Failure 403 {object} ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} ErrorResponse "Элемент не найден"
+// @Failure 409 {object} ErrorResponse "Элемент не ожидает проверки"
+// @Security ApiKeyAuth
+// @Router /moderation/items/{id}/approve [post]
+func (h *Handler) ApproveItem(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	decision, err := h.service.ModerationService.ApproveItem(userID, c.Param("id"))
+	if err != nil {
+		h.handleModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, decision)
+}
+
+// RejectItem rejects a pending item
+// @Summary Отклонить элемент
+// @Description Элемент остается приватным, автор получает уведомление с причиной
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Param id path string true "ID элемента"
+// @Param input body ModerationReasonInput true "Причина"
+// @Success 200 {object} models.ModerationDecision "Решение"
+// @Failure 400 {object} ErrorResponse "Не указана причина"
+// @Failure 403 {object} ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} ErrorResponse "Элемент не найден"
+// @Failure 409 {object} ErrorResponse "Элемент не ожидает проверки"
+// @Security ApiKeyAuth
+// @Router /moderation/items/{id}/reject [post]
+func (h *Handler) RejectItem(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	var input ModerationReasonInput
+	if err := c.BindJSON(&input); err != nil {
+		responses.NewErrorResponse(c, http.StatusBadRequest, "input is not valid")
+		return
+	}
+
+	decision, err := h.service.ModerationService.RejectItem(userID, c.Param("id"), input.Reason)
+	if err != nil {
+		h.handleModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, decision)
+}
+
+// RequestItemChanges asks the submitter to fix a pending item
+// @Summary Запросить правки
+// @Description Автор получает уведомление с комментарием и может отправить элемент повторно
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Param id path string true "ID элемента"
+// @Param input body ModerationReasonInput true "Что нужно исправить"
+// @Success 200 {object} models.ModerationDecision "Решение"
+// @Failure 400 {object} ErrorResponse "Не указана причина"
+// @Failure 403 {object} ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} ErrorResponse "Элемент не найден"
+// @Failure 409 {object} ErrorResponse "Элемент не ожидает проверки"
+// @Security ApiKeyAuth
+// @Router /moderation/items/{id}/request-changes [post]
+func (h *Handler) RequestItemChanges(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	var input ModerationReasonInput
+	if err := c.BindJSON(&input); err != nil {
+		responses.NewErrorResponse(c, http.StatusBadRequest, "input is not valid")
+		return
+	}
+
+	decision, err := h.service.ModerationService.RequestChanges(userID, c.Param("id"), input.Reason)
+	if err != nil {
+		h.handleModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, decision)
+}
+
+func (h *Handler) handleModerationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrItemNotFound):
+		responses.NotFound(c, "Item not found")
+	case errors.Is(err, service.ErrModeratorRequired), errors.Is(err, service.ErrNotItemCreator):
+		responses.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrModerationReasonEmpty), errors.Is(err, service.ErrEditEmpty),
+		errors.Is(err, service.ErrEditInvalidTitle), errors.Is(err, service.ErrEditInvalidYear):
+		responses.BadRequest(c, err.Error())
+	case errors.Is(err, service.ErrItemNotPending), errors.Is(err, service.ErrItemAlreadySubmitted),
+		errors.Is(err, service.ErrItemNotEditable):
+		responses.Conflict(c, err.Error())
+	default:
+		h.logger.Errorf("Moderation operation failed: %v", err)
+		responses.InternalServerErrorWithDetails(c, "internal server error")
+	}
+}
@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/service"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/responses"
+)
+
+// PaginatedNotificationsResponse represents notifications page
+type PaginatedNotificationsResponse struct {
+	Data       []models.Notification         `json:"data"`
+	Pagination pagination.PaginationResponse `json:"pagination"`
+}
+
+// MarkAllReadResponse represents result of marking all notifications as read
+type MarkAllReadResponse struct {
+	Marked int64 `json:"marked"`
+}
+
+// NotificationPreferencesResponse represents notification channels per type
+type NotificationPreferencesResponse struct {
+	Preferences []models.NotificationPreference `json:"preferences"`
+	// Channels - значения, которые можно выбрать для типа
+	Channels []string `json:"channels" example:"in_app,off,email"`
+}
+
+// NotificationPreferencesInput represents notification channels change
+type NotificationPreferencesInput struct {
+	Preferences []models.NotificationPreference `json:"preferences" binding:"required"`
+}
+
+// GetNotifications returns current user's notifications
+// @Summary Получить уведомления
+// @Description Уведомления пользователя, сначала новые
+// @Tags notifications
+// @Produce json
+// @Param unread query bool false "Только непрочитанные"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Кол-во уведомлений на странице" default(10)
+// @Success 200 {object} PaginatedNotificationsResponse "Уведомления"
+// @Failure 500 {object} ErrorResponse "Ошибка сервера"
+// @Security ApiKeyAuth
+// @Router /user/notifications [get]
+func (h *Handler) GetNotifications(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+	unreadOnly, _ := strconv.ParseBool(c.Query("unread"))
+
+	notifications, err := h.service.NotificationService.GetNotifications(userID, unreadOnly, GetPaginationParams(c))
+	if err != nil {
+		h.logger.Errorf("Failed to get notifications for user %d: %v", userID, err)
+		responses.InternalServerErrorWithDetails(c, "failed to get notifications")
+		return
+	}
+
+	c.JSON(http.StatusOK, notifications)
+}
+
+// GetUnreadNotificationsCount returns number of unread notifications
+// @Summary Количество непрочитанных уведомлений
+// @Tags notifications
+// @Produce json
+// @Success 200 {object} models.UnreadNotifications "Количество"
+// @Failure 500 {object} ErrorResponse "Ошибка сервера"
+// @Security ApiKeyAuth
+// @Router /user/notifications/unread-count [get]
+func (h *Handler) GetUnreadNotificationsCount(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	unread, err := h.service.NotificationService.GetUnreadCount(userID)
+	if err != nil {
+		h.handleNotificationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, unread)
+}
+
+// MarkNotificationRead marks a notification as read
+// @Summary Прочитать уведомление
+// @Tags notifications
+// @Produce json
+// @Param id path int true "ID уведомления"
+// @Success 200 {object} SuccessResponse "Уведомление прочитано"
+// @Failure 404 {object} ErrorResponse "Уведомление не найдено"
+// @Security ApiKeyAuth
+// @Router /user/notifications/{id}/read [post]
+func (h *Handler) MarkNotificationRead(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+	notificationID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		responses.BadRequest(c, "notification id is not valid")
+		return
+	}
+
+	if err := h.service.NotificationService.MarkRead(userID, notificationID); err != nil {
+		h.handleNotificationError(c, err)
+		return
+	}
+
+	responses.Success(c, "Notification has been read")
+}
+
+// MarkAllNotificationsRead marks all notifications as read
+// @Summary Прочитать все уведомления
+// @Tags notifications
+// @Produce json
+// @Success 200 {object} MarkAllReadResponse "Сколько уведомлений было непрочитано"
+// @Failure 500 {object} ErrorResponse "Ошибка сервера"
+// @Security ApiKeyAuth
+// @Router /user/notifications/read-all [post]
+func (h *Handler) MarkAllNotificationsRead(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	marked, err := h.service.NotificationService.MarkAllRead(userID)
+	if err != nil {
+		h.handleNotificationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, MarkAllReadResponse{Marked: marked})
+}
+
+// GetNotificationPreferences returns notification channels per type
+// @Summary Настройки уведомлений
+// @Description Канал для каждого типа уведомлений: in_app - только в приложении, off - не уведомлять,
+// @Description имя канала доставки (email) - в приложении и через этот канал
+// @Tags notifications
+// @Produce json
+// @Success 200 {object} NotificationPreferencesResponse "Настройки"
+// @Failure 500 {object} ErrorResponse "Ошибка сервера"
+// @Security ApiKeyAuth
+// @Router /user/notification-preferences [get]
+func (h *Handler) GetNotificationPreferences(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	preferences, err := h.service.NotificationService.GetPreferences(userID)
+	if err != nil {
+		h.handleNotificationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, NotificationPreferencesResponse{
+		Preferences: preferences,
+		Channels:    h.service.NotificationService.Channels(),
+	})
+}
+
+// SetNotificationPreferences changes notification channels
+// @Summary Изменить настройки уведомлений
+// @Description Меняет каналы перечисленных типов, остальные типы не меняются
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param input body NotificationPreferencesInput true "Настройки"
+// @Success 200 {object} NotificationPreferencesResponse "Настройки после изменения"
+// @Failure 400 {object} ErrorResponse "Неизвестный тип или недоступный канал"
+// @Security ApiKeyAuth
+// @Router /user/notification-preferences [put]
+func (h *Handler) SetNotificationPreferences(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	var input NotificationPreferencesInput
+	if err := c.BindJSON(&input); err != nil {
+		responses.BadRequest(c, "preferences are required")
+		return
+	}
+
+	preferences, err := h.service.NotificationService.SetPreferences(userID, input.Preferences)
+	if err != nil {
+		h.handleNotificationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, NotificationPreferencesResponse{
+		Preferences: preferences,
+		Channels:    h.service.NotificationService.Channels(),
+	})
+}
+
+func (h *Handler) handleNotificationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrNotificationNotFound):
+		responses.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrUnknownNotificationType), errors.Is(err, service.ErrUnsupportedNotificationChannel):
+		responses.BadRequest(c, err.Error())
+	default:
+		h.logger.Errorf("Notification operation failed: %v", err)
+		responses.InternalServerErrorWithDetails(c, "internal server error")
+	}
+}
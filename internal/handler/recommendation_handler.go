@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/service"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/responses"
+)
+
+// GetSimilarItems returns items that are often collected together with the item
+// @Summary Похожие элементы
+// @Description Элементы, которые часто встречаются в библиотеках вместе с этим элементом.
+// @Description Пересчитываются фоновой задачей, новые элементы появляются не сразу
+// @Tags recommendations
+// @Produce json
+// @Param id path string true "ID элемента"
+// @Param exclude_library query bool false "Не показывать элементы из своей библиотеки"
+// @Param limit query int false "Кол-во элементов" default(20)
+// @Success 200 {array} models.SimilarItem "Похожие элементы"
+// @Failure 403 {object} ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} ErrorResponse "Элемент не найден"
+// @Security ApiKeyAuth
+// @Router /items/{id}/similar [get]
+func (h *Handler) GetSimilarItems(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+	excludeLibrary, _ := strconv.ParseBool(c.Query("exclude_library"))
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil {
+		responses.BadRequest(c, "limit is not valid")
+		return
+	}
+
+	items, err := h.service.RecommendationService.GetSimilarItems(userID, c.Param("id"), excludeLibrary, limit)
+	if err != nil {
+		h.handleRecommendationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+// GetRecommendations returns items recommended for current user
+// @Summary Рекомендации для вас
+// @Description Элементы, похожие на элементы вашей библиотеки, с учетом оценок. Элементы из библиотеки не рекомендуются,
+// @Description because_of - элементы библиотеки, сильнее всего повлиявшие на рекомендацию
+// @Tags recommendations
+// @Produce json
+// @Param type query string false "Тип элементов" Enums(books, anime, series, movies)
+// @Param limit query int false "Кол-во элементов" default(20)
+// @Success 200 {array} models.RecommendedItem "Рекомендации"
+// @Failure 400 {object} ErrorResponse "Неизвестный тип"
+// @Security ApiKeyAuth
+// @Router /user/recommendations [get]
+func (h *Handler) GetRecommendations(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil {
+		responses.BadRequest(c, "limit is not valid")
+		return
+	}
+
+	items, err := h.service.RecommendationService.GetRecommendations(userID, c.Query("type"), limit)
+	if err != nil {
+		h.handleRecommendationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+func (h *Handler) handleRecommendationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrItemNotFound):
+		responses.NotFound(c, "Item not found")
+	case errors.Is(err, service.ErrItemAccessDenied):
+		responses.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrInvalidItemFilter):
+		responses.BadRequest(c, err.Error())
+	default:
+		h.logger.Errorf("Recommendation operation failed: %v", err)
+		responses.InternalServerErrorWithDetails(c, "internal server error")
+	}
+}
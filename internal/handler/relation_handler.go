@@ -0,0 +1,275 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/service"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/responses"
+)
+
+// CreateRelationInput represents input for creating a relation between items
+type CreateRelationInput struct {
+	TargetItemID string `json:"target_item_id" binding:"required"`
+	RelationType string `json:"relation_type" binding:"required" enums:"sequel,prequel,adaptation,spin_off,same_franchise,remake"`
+}
+
+// CreateFranchiseInput represents input for creating a franchise
+type CreateFranchiseInput struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+// AddFranchiseItemInput represents input for adding an item to a franchise
+type AddFranchiseItemInput struct {
+	ItemID string `json:"item_id" binding:"required"`
+}
+
+// GetItemRelations returns relation graph of an item
+// @Summary Получить граф связей элемента
+// @Description Возвращает связанные элементы (сиквелы, экранизации и т.д.) на заданную глубину и франшизы элемента
+// @Tags relations
+// @Produce json
+// @Param id path string true "ID элемента"
+// @Param depth query int false "Глубина обхода графа (1-5)" default(1)
+// @Success 200 {object} models.RelationGraph "Граф связей"
+// @Failure 403 {object} ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} ErrorResponse "Элемент не найден"
+// @Security ApiKeyAuth
+// @Router /items/{id}/relations [get]
+func (h *Handler) GetItemRelations(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	depth, err := strconv.Atoi(c.DefaultQuery("depth", "1"))
+	if err != nil {
+		responses.BadRequest(c, "depth is not valid")
+		return
+	}
+
+	graph, err := h.service.RelationService.GetRelationGraph(userID, c.Param("id"), depth)
+	if err != nil {
+		h.handleRelationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, graph)
+}
+
+// CreateItemRelation creates a directional relation from an item to another item
+// @Summary Создать связь между элементами
+// @Description Создает направленную связь: элемент {id} является relation_type для target_item_id. Связи с публичными элементами создают только модераторы
+// @Tags relations
+// @Accept json
+// @Produce json
+// @Param id path string true "ID элемента-источника"
+// @Param input body CreateRelationInput true "Данные связи"
+// @Success 201 {object} models.ItemRelation "Связь создана"
+// @Failure 400 {object} ErrorResponse "Неверные входные данные"
+// @Failure 403 {object} ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} ErrorResponse "Элемент не найден"
+// @Failure 409 {object} ErrorResponse "Связь уже существует"
+// @Security ApiKeyAuth
+// @Router /items/{id}/relations [post]
+func (h *Handler) CreateItemRelation(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	var input CreateRelationInput
+	if err := c.BindJSON(&input); err != nil {
+		responses.NewErrorResponse(c, http.StatusBadRequest, "input is not valid")
+		return
+	}
+
+	relation, err := h.service.RelationService.CreateRelation(userID, &models.ItemRelation{
+		SourceItemID: c.Param("id"),
+		TargetItemID: input.TargetItemID,
+		RelationType: input.RelationType,
+	})
+	if err != nil {
+		h.handleRelationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, relation)
+}
+
+// DeleteItemRelation deletes a relation of an item
+// @Summary Удалить связь
+// @Description Удаляет связь элемента. Связи с публичными элементами удаляют только модераторы
+// @Tags relations
+// @Produce json
+// @Param id path string true "ID элемента"
+// @Param relation_id path int true "ID связи"
+// @Success 200 {object} SuccessResponse "Связь удалена"
+// @Failure 403 {object} ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} ErrorResponse "Связь не найдена"
+// @Security ApiKeyAuth
+// @Router /items/{id}/relations/{relation_id} [delete]
+func (h *Handler) DeleteItemRelation(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	relationID, err := strconv.Atoi(c.Param("relation_id"))
+	if err != nil {
+		responses.BadRequest(c, "relation id is not valid")
+		return
+	}
+
+	if err := h.service.RelationService.DeleteRelation(userID, c.Param("id"), relationID); err != nil {
+		h.handleRelationError(c, err)
+		return
+	}
+
+	responses.Success(c, "Relation has been deleted")
+}
+
+// GetFranchises returns all franchises
+// @Summary Получить список франшиз
+// @Tags franchises
+// @Produce json
+// @Success 200 {array} models.Franchise "Франшизы"
+// @Failure 500 {object} ErrorResponse "Ошибка сервера"
+// @Security ApiKeyAuth
+// @Router /franchises [get]
+func (h *Handler) GetFranchises(c *gin.Context) {
+	franchises, err := h.service.RelationService.GetFranchises()
+	if err != nil {
+		h.handleRelationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, franchises)
+}
+
+// GetFranchise returns a franchise with its items
+// @Summary Получить франшизу
+// @Description Возвращает франшизу с элементами, доступными пользователю
+// @Tags franchises
+// @Produce json
+// @Param id path string true "ID франшизы"
+// @Success 200 {object} models.Franchise "Франшиза"
+// @Failure 404 {object} ErrorResponse "Франшиза не найдена"
+// @Security ApiKeyAuth
+// @Router /franchises/{id} [get]
+func (h *Handler) GetFranchise(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	franchise, err := h.service.RelationService.GetFranchise(userID, c.Param("id"))
+	if err != nil {
+		h.handleRelationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, franchise)
+}
+
+// CreateFranchise creates a franchise
+// @Summary Создать франшизу
+// @Description Создает франшизу. Доступно модераторам и администраторам
+// @Tags franchises
+// @Accept json
+// @Produce json
+// @Param input body CreateFranchiseInput true "Данные франшизы"
+// @Success 201 {object} models.Franchise "Франшиза создана"
+// @Failure 400 {object} ErrorResponse "Неверные входные данные"
+// @Failure 403 {object} ErrorResponse "Доступ запрещен"
+// @Failure 409 {object} ErrorResponse "Франшиза уже существует"
+// @Security ApiKeyAuth
+// @Router /franchises [post]
+func (h *Handler) CreateFranchise(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	var input CreateFranchiseInput
+	if err := c.BindJSON(&input); err != nil {
+		responses.NewErrorResponse(c, http.StatusBadRequest, "input is not valid")
+		return
+	}
+
+	franchise, err := h.service.RelationService.CreateFranchise(userID, &models.Franchise{
+		Name:        input.Name,
+		Description: input.Description,
+	})
+	if err != nil {
+		h.handleRelationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, franchise)
+}
+
+// AddItemToFranchise adds an item to a franchise
+// @Summary Добавить элемент во франшизу
+// @Description Доступно модераторам и администраторам
+// @Tags franchises
+// @Accept json
+// @Produce json
+// @Param id path string true "ID франшизы"
+// @Param input body AddFranchiseItemInput true "ID элемента"
+// @Success 200 {object} SuccessResponse "Элемент добавлен"
+// @Failure 403 {object} ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} ErrorResponse "Франшиза или элемент не найдены"
+// @Failure 409 {object} ErrorResponse "Элемент уже во франшизе"
+// @Security ApiKeyAuth
+// @Router /franchises/{id}/items [post]
+func (h *Handler) AddItemToFranchise(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	var input AddFranchiseItemInput
+	if err := c.BindJSON(&input); err != nil {
+		responses.NewErrorResponse(c, http.StatusBadRequest, "input is not valid")
+		return
+	}
+
+	if err := h.service.RelationService.AddItemToFranchise(userID, c.Param("id"), input.ItemID); err != nil {
+		h.handleRelationError(c, err)
+		return
+	}
+
+	responses.Success(c, "Item has been added to franchise")
+}
+
+// RemoveItemFromFranchise removes an item from a franchise
+// @Summary Удалить элемент из франшизы
+// @Description Доступно модераторам и администраторам
+// @Tags franchises
+// @Produce json
+// @Param id path string true "ID франшизы"
+// @Param item_id path string true "ID элемента"
+// @Success 200 {object} SuccessResponse "Элемент удален из франшизы"
+// @Failure 403 {object} ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} ErrorResponse "Элемент не найден во франшизе"
+// @Security ApiKeyAuth
+// @Router /franchises/{id}/items/{item_id} [delete]
+func (h *Handler) RemoveItemFromFranchise(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	if err := h.service.RelationService.RemoveItemFromFranchise(userID, c.Param("id"), c.Param("item_id")); err != nil {
+		h.handleRelationError(c, err)
+		return
+	}
+
+	responses.Success(c, "Item has been removed from franchise")
+}
+
+func (h *Handler) handleRelationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrItemNotFound):
+		responses.NotFound(c, "Item not found")
+	case errors.Is(err, service.ErrRelationNotFound):
+		responses.NotFound(c, "Relation not found")
+	case errors.Is(err, service.ErrFranchiseNotFound):
+		responses.NotFound(c, "Franchise not found")
+	case errors.Is(err, service.ErrItemAccessDenied), errors.Is(err, service.ErrModeratorRequired):
+		responses.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrInvalidRelationType), errors.Is(err, service.ErrSelfRelation):
+		responses.BadRequest(c, err.Error())
+	case errors.Is(err, service.ErrRelationAlreadyExists),
+		errors.Is(err, service.ErrFranchiseExists),
+		errors.Is(err, service.ErrItemAlreadyInFranchise):
+		responses.Conflict(c, err.Error())
+	default:
+		h.logger.Errorf("Relation operation failed: %v", err)
+		responses.InternalServerErrorWithDetails(c, "internal server error")
+	}
+}
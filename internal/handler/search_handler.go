@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/service"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/responses"
+)
+
+// PaginatedSearchResponse represents paginated search results
+type PaginatedSearchResponse struct {
+	Data       []models.SearchResult         `json:"data"`
+	Pagination pagination.PaginationResponse `json:"pagination"`
+}
+
+// Search performs full-text search over the catalog
+// @Summary Поиск по каталогу
+// @Description Полнотекстовый поиск по названию и описанию (русский и английский) с нечетким совпадением названия, ранжированием и подсветкой. Ищет среди публичных элементов и собственных элементов пользователя
+// @Tags search
+// @Produce json
+// @Param q query string true "Поисковый запрос"
+// @Param type query string false "Тип элементов" Enums(books, anime, series, movies)
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Кол-во элементов на странице" default(10)
+// @Success 200 {object} PaginatedSearchResponse "Результаты поиска"
+// @Failure 400 {object} ErrorResponse "Неверный запрос"
+// @Failure 500 {object} ErrorResponse "Ошибка сервера"
+// @Security ApiKeyAuth
+// @Router /search [get]
+func (h *Handler) Search(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	query := models.SearchQuery{
+		Query:  c.Query("q"),
+		Type:   c.Query("type"),
+		UserID: userID,
+	}
+
+	results, err := h.service.SearchService.SearchItems(query, GetPaginationParams(c))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidSearchQuery) {
+			responses.BadRequest(c, err.Error())
+			return
+		}
+		h.logger.Errorf("Search failed for query %q: %v", query.Query, err)
+		responses.InternalServerErrorWithDetails(c, "search failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
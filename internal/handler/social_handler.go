@@ -0,0 +1,389 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/service"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/responses"
+)
+
+// PaginatedFollowUsersResponse represents followers, following or follow requests page
+type PaginatedFollowUsersResponse struct {
+	Data       []models.FollowUser           `json:"data"`
+	Pagination pagination.PaginationResponse `json:"pagination"`
+}
+
+// ActivityCursorResponse represents activity page in cursor mode
+type ActivityCursorResponse struct {
+	Data       []models.Activity           `json:"data"`
+	Pagination pagination.CursorPagination `json:"pagination"`
+}
+
+// AccountPrivacyInput represents account privacy change
+type AccountPrivacyInput struct {
+	IsPrivate *bool `json:"is_private" binding:"required"`
+}
+
+// GetUserPublicProfile returns a profile of another user
+// @Summary Профиль пользователя
+// @Description Имя, аватар, количество подписчиков и подписок, а также подписка текущего пользователя в обе стороны
+// @Tags social
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Success 200 {object} models.PublicProfile "Профиль"
+// @Failure 404 {object} ErrorResponse "Пользователь не найден"
+// @Security ApiKeyAuth
+// @Router /users/{id} [get]
+func (h *Handler) GetUserPublicProfile(c *gin.Context) {
+	viewerID, _ := h.GetUserId(c)
+	userID, ok := userIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	profile, err := h.service.SocialService.GetProfile(viewerID, userID)
+	if err != nil {
+		h.handleSocialError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// FollowUser follows a user
+// @Summary Подписаться
+// @Description Подписка на пользователя. На приватный аккаунт отправляется запрос (статус pending), подписка начнет действовать после его принятия. Повторный запрос возвращает текущий статус
+// @Tags social
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Success 200 {object} models.Follow "Подписка или запрос на подписку"
+// @Failure 400 {object} ErrorResponse "Нельзя подписаться на себя"
+// @Failure 404 {object} ErrorResponse "Пользователь не найден"
+// @Security ApiKeyAuth
+// @Router /users/{id}/follow [post]
+func (h *Handler) FollowUser(c *gin.Context) {
+	followerID, _ := h.GetUserId(c)
+	followeeID, ok := userIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	follow, err := h.service.SocialService.Follow(followerID, followeeID)
+	if err != nil {
+		h.handleSocialError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, follow)
+}
+
+// UnfollowUser unfollows a user
+// @Summary Отписаться
+// @Description Отменяет подписку или неподтвержденный запрос на подписку
+// @Tags social
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Success 200 {object} SuccessResponse "Подписка отменена"
+// @Failure 404 {object} ErrorResponse "Подписки нет"
+// @Security ApiKeyAuth
+// @Router /users/{id}/follow [delete]
+func (h *Handler) UnfollowUser(c *gin.Context) {
+	followerID, _ := h.GetUserId(c)
+	followeeID, ok := userIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.SocialService.Unfollow(followerID, followeeID); err != nil {
+		h.handleSocialError(c, err)
+		return
+	}
+
+	responses.Success(c, "Unfollowed")
+}
+
+// GetUserFollowers returns user's followers
+// @Summary Подписчики пользователя
+// @Description Подписчики приватного аккаунта видны только ему самому и его подписчикам
+// @Tags social
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Кол-во пользователей на странице" default(10)
+// @Success 200 {object} PaginatedFollowUsersResponse "Подписчики, сначала новые"
+// @Failure 403 {object} ErrorResponse "Аккаунт приватный"
+// @Failure 404 {object} ErrorResponse "Пользователь не найден"
+// @Security ApiKeyAuth
+// @Router /users/{id}/followers [get]
+func (h *Handler) GetUserFollowers(c *gin.Context) {
+	viewerID, _ := h.GetUserId(c)
+	userID, ok := userIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	followers, err := h.service.SocialService.GetFollowers(viewerID, userID, GetPaginationParams(c))
+	if err != nil {
+		h.handleSocialError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, PaginatedFollowUsersResponse{Data: followers.Data, Pagination: followers.Pagination})
+}
+
+// GetUserFollowing returns users followed by the user
+// @Summary Подписки пользователя
+// @Description Подписки приватного аккаунта видны только ему самому и его подписчикам
+// @Tags social
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Кол-во пользователей на странице" default(10)
+// @Success 200 {object} PaginatedFollowUsersResponse "Подписки, сначала новые"
+// @Failure 403 {object} ErrorResponse "Аккаунт приватный"
+// @Failure 404 {object} ErrorResponse "Пользователь не найден"
+// @Security ApiKeyAuth
+// @Router /users/{id}/following [get]
+func (h *Handler) GetUserFollowing(c *gin.Context) {
+	viewerID, _ := h.GetUserId(c)
+	userID, ok := userIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	following, err := h.service.SocialService.GetFollowing(viewerID, userID, GetPaginationParams(c))
+	if err != nil {
+		h.handleSocialError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, PaginatedFollowUsersResponse{Data: following.Data, Pagination: following.Pagination})
+}
+
+// GetUserActivity returns user's activity
+// @Summary Активность пользователя
+// @Description События пользователя: добавление в публичные коллекции, завершение, оценки и публикация коллекций. Активность приватного аккаунта видна только подписчикам
+// @Tags social
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Param cursor query string false "Курсор keyset-пагинации"
+// @Param limit query int false "Кол-во событий на странице" default(10)
+// @Success 200 {object} ActivityCursorResponse "События, сначала новые"
+// @Failure 400 {object} ErrorResponse "Невалидный курсор"
+// @Failure 403 {object} ErrorResponse "Аккаунт приватный"
+// @Failure 404 {object} ErrorResponse "Пользователь не найден"
+// @Security ApiKeyAuth
+// @Router /users/{id}/activity [get]
+func (h *Handler) GetUserActivity(c *gin.Context) {
+	viewerID, _ := h.GetUserId(c)
+	userID, ok := userIDParam(c, "id")
+	if !ok {
+		return
+	}
+	req, err := getActivityCursorParams(c)
+	if err != nil {
+		responses.BadRequest(c, err.Error())
+		return
+	}
+
+	activity, err := h.service.SocialService.GetUserActivity(viewerID, userID, req)
+	if err != nil {
+		h.handleSocialError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ActivityCursorResponse{Data: activity.Data, Pagination: activity.Pagination})
+}
+
+// GetHomeFeed returns activity of followed users
+// @Summary Лента подписок
+// @Description События пользователей, на которых подписан текущий пользователь: что они добавили в публичные коллекции, закончили, оценили и какие коллекции опубликовали
+// @Tags social
+// @Produce json
+// @Param cursor query string false "Курсор keyset-пагинации"
+// @Param limit query int false "Кол-во событий на странице" default(10)
+// @Success 200 {object} ActivityCursorResponse "События, сначала новые"
+// @Failure 400 {object} ErrorResponse "Невалидный курсор"
+// @Security ApiKeyAuth
+// @Router /user/feed [get]
+func (h *Handler) GetHomeFeed(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+	req, err := getActivityCursorParams(c)
+	if err != nil {
+		responses.BadRequest(c, err.Error())
+		return
+	}
+
+	feed, err := h.service.SocialService.GetHomeFeed(userID, req)
+	if err != nil {
+		h.handleSocialError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ActivityCursorResponse{Data: feed.Data, Pagination: feed.Pagination})
+}
+
+// SetAccountPrivacy makes current user's account private or public
+// @Summary Приватность аккаунта
+// @Description Подписка на приватный аккаунт требует подтверждения, его подписки и активность видят только подписчики. При открытии аккаунта ожидающие запросы принимаются
+// @Tags social
+// @Accept json
+// @Produce json
+// @Param input body AccountPrivacyInput true "Приватность"
+// @Success 200 {object} SuccessResponse "Приватность изменена"
+// @Failure 400 {object} ErrorResponse "Неверные данные"
+// @Security ApiKeyAuth
+// @Router /user/privacy [put]
+func (h *Handler) SetAccountPrivacy(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	var input AccountPrivacyInput
+	if err := c.BindJSON(&input); err != nil {
+		responses.BadRequest(c, "is_private is required")
+		return
+	}
+
+	if err := h.service.SocialService.SetAccountPrivate(userID, *input.IsPrivate); err != nil {
+		h.handleSocialError(c, err)
+		return
+	}
+
+	responses.Success(c, "Privacy has been updated")
+}
+
+// GetFollowRequests returns pending follow requests
+// @Summary Запросы на подписку
+// @Description Входящие запросы на подписку на приватный аккаунт
+// @Tags social
+// @Produce json
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Кол-во запросов на странице" default(10)
+// @Success 200 {object} PaginatedFollowUsersResponse "Запросы, сначала новые"
+// @Security ApiKeyAuth
+// @Router /user/follow-requests [get]
+func (h *Handler) GetFollowRequests(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	requests, err := h.service.SocialService.GetFollowRequests(userID, GetPaginationParams(c))
+	if err != nil {
+		h.handleSocialError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, PaginatedFollowUsersResponse{Data: requests.Data, Pagination: requests.Pagination})
+}
+
+// AcceptFollowRequest accepts a follow request
+// @Summary Принять запрос на подписку
+// @Tags social
+// @Produce json
+// @Param user_id path int true "ID пользователя, отправившего запрос"
+// @Success 200 {object} SuccessResponse "Запрос принят"
+// @Failure 404 {object} ErrorResponse "Запрос не найден"
+// @Security ApiKeyAuth
+// @Router /user/follow-requests/{user_id}/accept [post]
+func (h *Handler) AcceptFollowRequest(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+	followerID, ok := userIDParam(c, "user_id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.SocialService.AcceptFollowRequest(userID, followerID); err != nil {
+		h.handleSocialError(c, err)
+		return
+	}
+
+	responses.Success(c, "Follow request has been accepted")
+}
+
+// RejectFollowRequest rejects a follow request
+// @Summary Отклонить запрос на подписку
+// @Tags social
+// @Produce json
+// @Param user_id path int true "ID пользователя, отправившего запрос"
+// @Success 200 {object} SuccessResponse "Запрос отклонен"
+// @Failure 404 {object} ErrorResponse "Запрос не найден"
+// @Security ApiKeyAuth
+// @Router /user/follow-requests/{user_id} [delete]
+func (h *Handler) RejectFollowRequest(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+	followerID, ok := userIDParam(c, "user_id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.SocialService.RejectFollowRequest(userID, followerID); err != nil {
+		h.handleSocialError(c, err)
+		return
+	}
+
+	responses.Success(c, "Follow request has been rejected")
+}
+
+// RemoveFollower removes a follower
+// @Summary Удалить подписчика
+// @Tags social
+// @Produce json
+// @Param user_id path int true "ID подписчика"
+// @Success 200 {object} SuccessResponse "Подписчик удален"
+// @Failure 404 {object} ErrorResponse "Подписчик не найден"
+// @Security ApiKeyAuth
+// @Router /user/followers/{user_id} [delete]
+func (h *Handler) RemoveFollower(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+	followerID, ok := userIDParam(c, "user_id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.SocialService.RemoveFollower(userID, followerID); err != nil {
+		h.handleSocialError(c, err)
+		return
+	}
+
+	responses.Success(c, "Follower has been removed")
+}
+
+// userIDParam разбирает ID пользователя из пути, при ошибке отвечает 400
+func userIDParam(c *gin.Context, name string) (int, bool) {
+	id, err := strconv.Atoi(c.Param(name))
+	if err != nil || id <= 0 {
+		responses.BadRequest(c, "user id is not valid")
+		return 0, false
+	}
+	return id, true
+}
+
+// getActivityCursorParams - ленты активности всегда отдаются в keyset-режиме, без cursor - первая страница
+func getActivityCursorParams(c *gin.Context) (pagination.CursorRequest, error) {
+	req, ok, err := GetCursorParams(c)
+	if err != nil {
+		return req, err
+	}
+	if !ok {
+		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+		req = pagination.NewCursorRequest(nil, limit, false)
+	}
+	return req, nil
+}
+
+func (h *Handler) handleSocialError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound), errors.Is(err, service.ErrNotFollowing),
+		errors.Is(err, service.ErrFollowRequestNotFound), errors.Is(err, service.ErrFollowerNotFound):
+		responses.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrProfilePrivate):
+		responses.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrCannotFollowSelf), errors.Is(err, pagination.ErrInvalidCursor):
+		responses.BadRequest(c, err.Error())
+	default:
+		h.logger.Errorf("Social operation failed: %v", err)
+		responses.InternalServerErrorWithDetails(c, "internal server error")
+	}
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/service"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/responses"
+)
+
+const (
+	// streamHeartbeatInterval - комментарий-пинг не дает прокси закрыть молчащее соединение
+	streamHeartbeatInterval = 25 * time.Second
+	// streamRetry - через сколько миллисекунд EventSource переподключается после обрыва
+	streamRetry = 5000
+)
+
+// StreamEvent - данные события потока, тип события передается в поле event
+type StreamEvent struct {
+	CollectionID string          `json:"collection_id,omitempty"`
+	Data         json.RawMessage `json:"data,omitempty" swaggertype:"object"`
+}
+
+// Stream отправляет события пользователя в реальном времени
+// @Summary Поток событий
+// @Description Server-Sent Events. Типы событий: notifications.unread - число непрочитанных уведомлений,
+// @Description collection.item_added, collection.updated, collection.comment - изменения коллекций пользователя
+// @Description и коллекций из параметра collections, import.progress - прогресс импорта.
+// @Description После переподключения клиенту стоит перечитать данные: пропущенные события не повторяются
+// @Tags stream
+// @Produce text/event-stream
+// @Param collections query string false "Чужие публичные коллекции, за которыми следить, через запятую (до 20)"
+// @Param access_token query string false "Токен для клиентов, которые не могут передать заголовок Authorization (EventSource)"
+// @Success 200 {object} StreamEvent "Поток событий"
+// @Failure 400 {object} ErrorResponse "Слишком много коллекций"
+// @Failure 403 {object} ErrorResponse "Коллекция закрыта"
+// @Failure 404 {object} ErrorResponse "Коллекция не найдена"
+// @Security ApiKeyAuth
+// @Router /stream [get]
+func (h *Handler) Stream(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	sub, err := h.service.RealtimeService.Subscribe(userID, GetListQueryParam(c, "collections"))
+	if err != nil {
+		h.handleStreamError(c, err)
+		return
+	}
+	defer sub.Close()
+
+	// Таймауты сервера рассчитаны на обычные запросы, поток снимает их для своего соединения
+	controller := http.NewResponseController(c.Writer)
+	if err := controller.SetReadDeadline(time.Time{}); err != nil {
+		h.logger.Warnf("Failed to reset stream read deadline: %v", err)
+	}
+	if err := controller.SetWriteDeadline(time.Time{}); err != nil {
+		h.logger.Warnf("Failed to reset stream write deadline: %v", err)
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// Отключает буферизацию ответа в nginx
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", streamRetry)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-sub.Events():
+			if !ok {
+				// Клиент не успевал читать события и был отключен, EventSource переподключится сам
+				return
+			}
+			c.SSEvent(event.Type, StreamEvent{CollectionID: event.CollectionID, Data: event.Data})
+			c.Writer.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(c.Writer, ": ping\n\n")
+			c.Writer.Flush()
+		}
+	}
+}
+
+func (h *Handler) handleStreamError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrTooManyWatchedCollections):
+		responses.BadRequest(c, err.Error())
+	case errors.Is(err, service.ErrCollectionPrivate):
+		responses.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrCollectionNotFound):
+		responses.NotFound(c, err.Error())
+	default:
+		h.logger.Errorf("Failed to open event stream: %v", err)
+		responses.InternalServerErrorWithDetails(c, "internal server error")
+	}
+}
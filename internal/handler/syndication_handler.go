@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/service"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/responses"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/syndication"
+)
+
+// GetCollectionFeed returns Atom/RSS feed of a public collection
+// @Summary Лента коллекции
+// @Description Лента Atom или RSS публичной коллекции: последние добавленные элементы с заметками владельца. Токен не нужен.
+// @Description Поддерживаются условные запросы (If-None-Match, If-Modified-Since)
+// @Tags feeds
+// @Produce xml
+// @Param id path string true "ID коллекции"
+// @Param format query string false "Формат ленты (по умолчанию atom)" Enums(atom, rss)
+// @Success 200 {string} string "Лента"
+// @Success 304 "Лента не изменилась"
+// @Failure 400 {object} ErrorResponse "Неизвестный формат"
+// @Failure 403 {object} ErrorResponse "Коллекция приватная"
+// @Failure 404 {object} ErrorResponse "Коллекция не найдена"
+// @Router /feeds/collections/{id} [get]
+func (h *Handler) GetCollectionFeed(c *gin.Context) {
+	feed, err := h.service.SyndicationService.GetCollectionFeed(c.Param("id"), c.Query("format"))
+	if err != nil {
+		h.handleSyndicationError(c, err)
+		return
+	}
+	h.writeFeed(c, feed)
+}
+
+// GetUserFeed returns Atom/RSS feed of user's public activity
+// @Summary Лента пользователя
+// @Description Лента Atom или RSS публичной активности пользователя: новые публичные коллекции и элементы, добавленные в них. Токен не нужен.
+// @Description Поддерживаются условные запросы (If-None-Match, If-Modified-Since)
+// @Tags feeds
+// @Produce xml
+// @Param id path int true "ID пользователя"
+// @Param format query string false "Формат ленты (по умолчанию atom)" Enums(atom, rss)
+// @Success 200 {string} string "Лента"
+// @Success 304 "Лента не изменилась"
+// @Failure 400 {object} ErrorResponse "Неизвестный формат"
+// @Failure 404 {object} ErrorResponse "Пользователь не найден или аккаунт закрыт"
+// @Router /feeds/users/{id} [get]
+func (h *Handler) GetUserFeed(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || userID <= 0 {
+		responses.BadRequest(c, "invalid user id")
+		return
+	}
+
+	feed, err := h.service.SyndicationService.GetUserFeed(userID, c.Query("format"))
+	if err != nil {
+		h.handleSyndicationError(c, err)
+		return
+	}
+	h.writeFeed(c, feed)
+}
+
+// GetCatalogFeed returns Atom/RSS feed of items recently added to the catalog
+// @Summary Лента каталога
+// @Description Лента Atom или RSS элементов, недавно появившихся в публичном каталоге, включая одобренные модераторами. Токен не нужен.
+// @Description Поддерживаются условные запросы (If-None-Match, If-Modified-Since)
+// @Tags feeds
+// @Produce xml
+// @Param format query string false "Формат ленты (по умолчанию atom)" Enums(atom, rss)
+// @Success 200 {string} string "Лента"
+// @Success 304 "Лента не изменилась"
+// @Failure 400 {object} ErrorResponse "Неизвестный формат"
+// @Router /feeds/catalog [get]
+func (h *Handler) GetCatalogFeed(c *gin.Context) {
+	feed, err := h.service.SyndicationService.GetCatalogFeed(c.Query("format"))
+	if err != nil {
+		h.handleSyndicationError(c, err)
+		return
+	}
+	h.writeFeed(c, feed)
+}
+
+// writeFeed отдает ленту с заголовками кеширования. Ридеры опрашивают ленты по расписанию,
+// поэтому неизменившаяся лента отдается ответом 304 без тела
+func (h *Handler) writeFeed(c *gin.Context, feed *models.SyndicationFeed) {
+	etag := `"` + feed.ETag + `"`
+	lastModified := feed.LastModified.UTC().Truncate(time.Second)
+	c.Header("ETag", etag)
+	c.Header("Last-Modified", lastModified.Format(http.TimeFormat))
+	c.Header("Cache-Control", "public, max-age="+strconv.Itoa(int(feed.MaxAge.Seconds())))
+	c.Header("X-Content-Type-Options", "nosniff")
+
+	// If-None-Match важнее If-Modified-Since: удаление элемента не меняет время ленты
+	if match := c.GetHeader("If-None-Match"); match != "" {
+		if match == etag || match == "*" {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	} else if since, err := http.ParseTime(c.GetHeader("If-Modified-Since")); err == nil && !lastModified.After(since) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, feed.ContentType, feed.Body)
+}
+
+func (h *Handler) handleSyndicationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrCollectionNotFound), errors.Is(err, service.ErrUserNotFound):
+		responses.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrNotCollectionOwner):
+		responses.Forbidden(c, err.Error())
+	case errors.Is(err, syndication.ErrUnknownFormat):
+		responses.BadRequest(c, err.Error())
+	default:
+		h.logger.Errorf("Feed failed: %v", err)
+		responses.InternalServerErrorWithDetails(c, "internal server error")
+	}
+}
@@ -0,0 +1,315 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/service"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/responses"
+)
+
+// CreateGenreInput represents input for creating a genre
+type CreateGenreInput struct {
+	Slug string `json:"slug" binding:"required" example:"sci-fi"`
+	Name string `json:"name" binding:"required" example:"Научная фантастика"`
+}
+
+// SetGenresInput represents input for replacing item genres
+type SetGenresInput struct {
+	Genres []string `json:"genres" example:"drama,sci-fi"`
+}
+
+// SetTagsInput represents input for replacing tags
+type SetTagsInput struct {
+	Tags []string `json:"tags" example:"любимое,перечитать"`
+}
+
+// GetGenres returns genre taxonomy
+// @Summary Получить список жанров
+// @Tags taxonomy
+// @Produce json
+// @Success 200 {array} models.Genre "Жанры"
+// @Failure 500 {object} ErrorResponse "Ошибка сервера"
+// @Security ApiKeyAuth
+// @Router /genres [get]
+func (h *Handler) GetGenres(c *gin.Context) {
+	genres, err := h.service.TaxonomyService.GetGenres()
+	if err != nil {
+		h.handleTaxonomyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, genres)
+}
+
+// CreateGenre creates a genre
+// @Summary Создать жанр
+// @Description Доступно модераторам и администраторам
+// @Tags taxonomy
+// @Accept json
+// @Produce json
+// @Param input body CreateGenreInput true "Данные жанра"
+// @Success 201 {object} models.Genre "Жанр создан"
+// @Failure 400 {object} ErrorResponse "Неверные входные данные"
+// @Failure 403 {object} ErrorResponse "Доступ запрещен"
+// @Failure 409 {object} ErrorResponse "Жанр уже существует"
+// @Security ApiKeyAuth
+// @Router /genres [post]
+func (h *Handler) CreateGenre(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	var input CreateGenreInput
+	if err := c.BindJSON(&input); err != nil {
+		responses.NewErrorResponse(c, http.StatusBadRequest, "input is not valid")
+		return
+	}
+
+	genre, err := h.service.TaxonomyService.CreateGenre(userID, &models.Genre{Slug: input.Slug, Name: input.Name})
+	if err != nil {
+		h.handleTaxonomyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, genre)
+}
+
+// GetItemGenres returns genres of an item
+// @Summary Получить жанры элемента
+// @Tags taxonomy
+// @Produce json
+// @Param id path string true "ID элемента"
+// @Success 200 {array} models.Genre "Жанры элемента"
+// @Failure 403 {object} ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} ErrorResponse "Элемент не найден"
+// @Security ApiKeyAuth
+// @Router /items/{id}/genres [get]
+func (h *Handler) GetItemGenres(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	genres, err := h.service.TaxonomyService.GetItemGenres(userID, c.Param("id"))
+	if err != nil {
+		h.handleTaxonomyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, genres)
+}
+
+// SetItemGenres replaces genres of an item
+// @Summary Задать жанры элемента
+// @Description Жанры публичных элементов задают модераторы, своих приватных элементов - создатель
+// @Tags taxonomy
+// @Accept json
+// @Produce json
+// @Param id path string true "ID элемента"
+// @Param input body SetGenresInput true "Slug жанров"
+// @Success 200 {array} models.Genre "Жанры элемента"
+// @Failure 403 {object} ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} ErrorResponse "Элемент или жанр не найден"
+// @Security ApiKeyAuth
+// @Router /items/{id}/genres [put]
+func (h *Handler) SetItemGenres(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	var input SetGenresInput
+	if err := c.BindJSON(&input); err != nil {
+		responses.NewErrorResponse(c, http.StatusBadRequest, "input is not valid")
+		return
+	}
+
+	if err := h.service.TaxonomyService.SetItemGenres(userID, c.Param("id"), input.Genres); err != nil {
+		h.handleTaxonomyError(c, err)
+		return
+	}
+
+	genres, err := h.service.TaxonomyService.GetItemGenres(userID, c.Param("id"))
+	if err != nil {
+		h.handleTaxonomyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, genres)
+}
+
+// GetUserTags returns tags of current user
+// @Summary Получить свои теги
+// @Description Возвращает теги пользователя с количеством использований
+// @Tags taxonomy
+// @Produce json
+// @Success 200 {array} models.Tag "Теги"
+// @Failure 500 {object} ErrorResponse "Ошибка сервера"
+// @Security ApiKeyAuth
+// @Router /tags [get]
+func (h *Handler) GetUserTags(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	tags, err := h.service.TaxonomyService.GetUserTags(userID)
+	if err != nil {
+		h.handleTaxonomyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// SuggestTags returns tag autocomplete suggestions
+// @Summary Автодополнение тегов
+// @Description Возвращает теги пользователя, начинающиеся с q, отсортированные по популярности
+// @Tags taxonomy
+// @Produce json
+// @Param q query string false "Префикс тега"
+// @Param limit query int false "Кол-во подсказок" default(10)
+// @Success 200 {array} models.Tag "Подсказки"
+// @Failure 500 {object} ErrorResponse "Ошибка сервера"
+// @Security ApiKeyAuth
+// @Router /tags/suggest [get]
+func (h *Handler) SuggestTags(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		responses.BadRequest(c, "limit is not valid")
+		return
+	}
+
+	tags, err := h.service.TaxonomyService.SuggestTags(userID, c.Query("q"), limit)
+	if err != nil {
+		h.handleTaxonomyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// GetItemTags returns current user's tags on an item
+// @Summary Получить свои теги элемента
+// @Tags taxonomy
+// @Produce json
+// @Param id path string true "ID элемента"
+// @Success 200 {array} models.Tag "Теги"
+// @Security ApiKeyAuth
+// @Router /items/{id}/tags [get]
+func (h *Handler) GetItemTags(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	tags, err := h.service.TaxonomyService.GetItemTags(userID, c.Param("id"))
+	if err != nil {
+		h.handleTaxonomyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// SetItemTags replaces current user's tags on an item
+// @Summary Задать свои теги элемента
+// @Description Заменяет теги пользователя на элементе из его медиатеки. Отсутствующие теги создаются
+// @Tags taxonomy
+// @Accept json
+// @Produce json
+// @Param id path string true "ID элемента"
+// @Param input body SetTagsInput true "Теги"
+// @Success 200 {array} models.Tag "Теги элемента"
+// @Failure 400 {object} ErrorResponse "Неверные теги"
+// @Failure 403 {object} ErrorResponse "Элемент не в медиатеке пользователя"
+// @Security ApiKeyAuth
+// @Router /items/{id}/tags [put]
+func (h *Handler) SetItemTags(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	var input SetTagsInput
+	if err := c.BindJSON(&input); err != nil {
+		responses.NewErrorResponse(c, http.StatusBadRequest, "input is not valid")
+		return
+	}
+
+	tags, err := h.service.TaxonomyService.SetItemTags(userID, c.Param("id"), input.Tags)
+	if err != nil {
+		h.handleTaxonomyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// GetCollectionTags returns tags of a collection
+// @Summary Получить теги коллекции
+// @Tags taxonomy
+// @Produce json
+// @Param id path string true "ID коллекции"
+// @Success 200 {array} models.Tag "Теги"
+// @Failure 403 {object} ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} ErrorResponse "Коллекция не найдена"
+// @Security ApiKeyAuth
+// @Router /collections/{id}/tags [get]
+func (h *Handler) GetCollectionTags(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	tags, err := h.service.TaxonomyService.GetCollectionTags(userID, c.Param("id"))
+	if err != nil {
+		h.handleTaxonomyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// SetCollectionTags replaces tags of a collection
+// @Summary Задать теги коллекции
+// @Description Доступно только владельцу коллекции. Отсутствующие теги создаются
+// @Tags taxonomy
+// @Accept json
+// @Produce json
+// @Param id path string true "ID коллекции"
+// @Param input body SetTagsInput true "Теги"
+// @Success 200 {array} models.Tag "Теги коллекции"
+// @Failure 400 {object} ErrorResponse "Неверные теги"
+// @Failure 403 {object} ErrorResponse "Доступ запрещен"
+// @Failure 404 {object} ErrorResponse "Коллекция не найдена"
+// @Security ApiKeyAuth
+// @Router /collections/{id}/tags [put]
+func (h *Handler) SetCollectionTags(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	var input SetTagsInput
+	if err := c.BindJSON(&input); err != nil {
+		responses.NewErrorResponse(c, http.StatusBadRequest, "input is not valid")
+		return
+	}
+
+	tags, err := h.service.TaxonomyService.SetCollectionTags(userID, c.Param("id"), input.Tags)
+	if err != nil {
+		h.handleTaxonomyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+func (h *Handler) handleTaxonomyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrItemNotFound):
+		responses.NotFound(c, "Item not found")
+	case errors.Is(err, service.ErrCollectionNotFound):
+		responses.NotFound(c, "Collection not found")
+	case errors.Is(err, service.ErrGenreNotFound):
+		responses.NotFound(c, "Genre not found")
+	case errors.Is(err, service.ErrItemAccessDenied),
+		errors.Is(err, service.ErrModeratorRequired),
+		errors.Is(err, service.ErrNotCollectionOwner),
+		errors.Is(err, service.ErrItemNotInLibrary):
+		responses.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrInvalidGenreSlug),
+		errors.Is(err, service.ErrInvalidTag),
+		errors.Is(err, service.ErrTooManyTags):
+		responses.BadRequest(c, err.Error())
+	case errors.Is(err, service.ErrGenreAlreadyExists):
+		responses.Conflict(c, err.Error())
+	default:
+		h.logger.Errorf("Taxonomy operation failed: %v", err)
+		responses.InternalServerErrorWithDetails(c, "internal server error")
+	}
+}
@@ -0,0 +1,255 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/service"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/responses"
+)
+
+// PaginatedWebhookDeliveriesResponse represents webhook delivery log page
+type PaginatedWebhookDeliveriesResponse struct {
+	Data       []models.WebhookDelivery      `json:"data"`
+	Pagination pagination.PaginationResponse `json:"pagination"`
+}
+
+// WebhookEventTypesResponse represents events available for webhooks
+type WebhookEventTypesResponse struct {
+	Events []string `json:"events" example:"item.added,item.status_changed,review.posted"`
+}
+
+// GetWebhookEventTypes returns events available for webhooks
+// @Summary События вебхуков
+// @Description item.added - элемент добавлен в коллекцию, item.status_changed - изменился статус просмотра,
+// @Description review.posted - оценка элемента или отзыв при добавлении в коллекцию
+// @Tags webhooks
+// @Produce json
+// @Success 200 {object} WebhookEventTypesResponse "События"
+// @Security ApiKeyAuth
+// @Router /webhooks/events [get]
+func (h *Handler) GetWebhookEventTypes(c *gin.Context) {
+	c.JSON(http.StatusOK, WebhookEventTypesResponse{Events: h.service.WebhookService.EventTypes()})
+}
+
+// GetWebhooks returns user's webhooks
+// @Summary Вебхуки
+// @Tags webhooks
+// @Produce json
+// @Success 200 {array} models.Webhook "Вебхуки"
+// @Failure 500 {object} ErrorResponse "Ошибка сервера"
+// @Security ApiKeyAuth
+// @Router /webhooks [get]
+func (h *Handler) GetWebhooks(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	webhooks, err := h.service.WebhookService.GetWebhooks(userID)
+	if err != nil {
+		h.handleWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, webhooks)
+}
+
+// CreateWebhook registers a webhook
+// @Summary Создать вебхук
+// @Description События отправляются POST-запросом с JSON {id, type, user_id, created_at, data}.
+// @Description Заголовок X-Memoria-Signature: sha256=HMAC-SHA256(secret, X-Memoria-Timestamp + "." + тело) в hex.
+// @Description Неудачные отправки (не 2xx) повторяются с нарастающей паузой. Секрет показывается только в этом ответе.
+// @Description Импорт и восстановление из резервной копии событий не создают
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param input body models.WebhookInput true "Вебхук"
+// @Success 201 {object} models.Webhook "Вебхук с секретом"
+// @Failure 400 {object} ErrorResponse "Неверный адрес, события или превышен лимит"
+// @Security ApiKeyAuth
+// @Router /webhooks [post]
+func (h *Handler) CreateWebhook(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+
+	var input models.WebhookInput
+	if err := c.BindJSON(&input); err != nil {
+		responses.BadRequest(c, "url and events are required")
+		return
+	}
+
+	webhook, err := h.service.WebhookService.CreateWebhook(userID, input)
+	if err != nil {
+		h.handleWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, webhook)
+}
+
+// GetWebhook returns a webhook
+// @Summary Вебхук
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "ID вебхука"
+// @Success 200 {object} models.Webhook "Вебхук"
+// @Failure 404 {object} ErrorResponse "Вебхук не найден"
+// @Security ApiKeyAuth
+// @Router /webhooks/{id} [get]
+func (h *Handler) GetWebhook(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+	webhookID, ok := webhookIDParam(c)
+	if !ok {
+		return
+	}
+
+	webhook, err := h.service.WebhookService.GetWebhook(userID, webhookID)
+	if err != nil {
+		h.handleWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook)
+}
+
+// UpdateWebhook changes a webhook
+// @Summary Изменить вебхук
+// @Description Меняет адрес, описание, события и включенность. Выключенный вебхук копит события и отправит их после включения
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "ID вебхука"
+// @Param input body models.WebhookInput true "Вебхук"
+// @Success 200 {object} models.Webhook "Вебхук после изменения"
+// @Failure 400 {object} ErrorResponse "Неверный адрес или события"
+// @Failure 404 {object} ErrorResponse "Вебхук не найден"
+// @Security ApiKeyAuth
+// @Router /webhooks/{id} [put]
+func (h *Handler) UpdateWebhook(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+	webhookID, ok := webhookIDParam(c)
+	if !ok {
+		return
+	}
+
+	var input models.WebhookInput
+	if err := c.BindJSON(&input); err != nil {
+		responses.BadRequest(c, "url and events are required")
+		return
+	}
+
+	webhook, err := h.service.WebhookService.UpdateWebhook(userID, webhookID, input)
+	if err != nil {
+		h.handleWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook)
+}
+
+// DeleteWebhook deletes a webhook
+// @Summary Удалить вебхук
+// @Description Удаляет вебхук вместе с журналом и неотправленными событиями
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "ID вебхука"
+// @Success 200 {object} SuccessResponse "Вебхук удален"
+// @Failure 404 {object} ErrorResponse "Вебхук не найден"
+// @Security ApiKeyAuth
+// @Router /webhooks/{id} [delete]
+func (h *Handler) DeleteWebhook(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+	webhookID, ok := webhookIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.WebhookService.DeleteWebhook(userID, webhookID); err != nil {
+		h.handleWebhookError(c, err)
+		return
+	}
+
+	responses.Success(c, "Webhook has been deleted")
+}
+
+// GetWebhookDeliveries returns webhook delivery log
+// @Summary Журнал отправок вебхука
+// @Description Отправки событий и ответы получателя, сначала новые
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "ID вебхука"
+// @Param status query string false "Статус отправки" Enums(pending, sent, failed)
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Кол-во отправок на странице" default(10)
+// @Success 200 {object} PaginatedWebhookDeliveriesResponse "Журнал"
+// @Failure 400 {object} ErrorResponse "Неизвестный статус"
+// @Failure 404 {object} ErrorResponse "Вебхук не найден"
+// @Security ApiKeyAuth
+// @Router /webhooks/{id}/deliveries [get]
+func (h *Handler) GetWebhookDeliveries(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+	webhookID, ok := webhookIDParam(c)
+	if !ok {
+		return
+	}
+
+	deliveries, err := h.service.WebhookService.GetDeliveries(userID, webhookID, c.Query("status"), GetPaginationParams(c))
+	if err != nil {
+		h.handleWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// SendWebhookTestEvent sends a test event
+// @Summary Отправить тестовое событие
+// @Description Сразу отправляет событие webhook.test и возвращает запись журнала с ответом получателя.
+// @Description Тестовое событие не повторяется при ошибке и уходит даже на выключенный вебхук
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "ID вебхука"
+// @Success 200 {object} models.WebhookDelivery "Результат отправки"
+// @Failure 404 {object} ErrorResponse "Вебхук не найден"
+// @Security ApiKeyAuth
+// @Router /webhooks/{id}/test [post]
+func (h *Handler) SendWebhookTestEvent(c *gin.Context) {
+	userID, _ := h.GetUserId(c)
+	webhookID, ok := webhookIDParam(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.service.WebhookService.SendTestEvent(c.Request.Context(), userID, webhookID)
+	if err != nil {
+		h.handleWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
+
+func webhookIDParam(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		responses.BadRequest(c, "webhook id is not valid")
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *Handler) handleWebhookError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrWebhookNotFound):
+		responses.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrInvalidWebhookURL), errors.Is(err, service.ErrWebhookPrivateURL),
+		errors.Is(err, service.ErrUnknownWebhookEvent), errors.Is(err, service.ErrWebhookEventsEmpty),
+		errors.Is(err, service.ErrWebhookDescriptionTooLong), errors.Is(err, service.ErrTooManyWebhooks),
+		errors.Is(err, service.ErrUnknownDeliveryStatus):
+		responses.BadRequest(c, err.Error())
+	default:
+		h.logger.Errorf("Webhook operation failed: %v", err)
+		responses.InternalServerErrorWithDetails(c, "internal server error")
+	}
+}
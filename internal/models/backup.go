@@ -0,0 +1,27 @@
+package models
+
+// Режимы восстановления при совпадении коллекции или своего элемента с уже существующими
+const (
+	RestoreModeSkip      = "skip"      // оставить существующие данные
+	RestoreModeOverwrite = "overwrite" // заменить данными копии
+	RestoreModeDuplicate = "duplicate" // создать рядом копию
+)
+
+// RestoreResult - итог восстановления библиотеки из резервной копии
+type RestoreResult struct {
+	Mode               string `json:"mode" example:"skip"`
+	CollectionsCreated int    `json:"collections_created"`
+	CollectionsUpdated int    `json:"collections_updated"`
+	CollectionsSkipped int    `json:"collections_skipped"`
+	// ItemsCreated - свои элементы, а также элементы каталога, которых нет на этом сервере
+	ItemsCreated int `json:"items_created"`
+	// ItemsMatched - элементы, найденные в каталоге или среди своих элементов
+	ItemsMatched        int `json:"items_matched"`
+	ItemsUpdated        int `json:"items_updated"`
+	AssignmentsRestored int `json:"assignments_restored"`
+	ProgressRestored    int `json:"progress_restored"`
+	ProgressSkipped     int `json:"progress_skipped"`
+	// CollectionIDs и ItemIDs - соответствие ID из копии новым ID
+	CollectionIDs map[string]string `json:"collection_ids"`
+	ItemIDs       map[string]string `json:"item_ids"`
+}
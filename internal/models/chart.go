@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
+)
+
+const (
+	// ChartTrending - чаще всего добавляемые в библиотеки за последние дни, свежие добавления весят больше
+	ChartTrending = "trending"
+	// ChartMostCollected - у наибольшего числа пользователей в коллекциях за все время
+	ChartMostCollected = "most_collected"
+	// ChartTopRated - лучшие по байесовскому среднему оценок
+	ChartTopRated = "top_rated"
+)
+
+// ChartEntry - место элемента в чарте
+type ChartEntry struct {
+	Position int            `json:"position"`
+	Item     CollectionItem `json:"item"`
+	Score    float64        `json:"score"`
+	// Collectors - у скольких пользователей элемент в коллекциях
+	Collectors int `json:"collectors"`
+	// RecentAdds - сколько пользователей добавили элемент за окно trending
+	RecentAdds   int      `json:"recent_adds"`
+	RatingsCount int      `json:"ratings_count"`
+	AvgRating    *float64 `json:"avg_rating"`
+}
+
+// ChartQuery - выбор чарта: тип и жанр необязательны
+type ChartQuery struct {
+	Chart string
+	Type  string
+	Genre string
+}
+
+// Chart - страница чарта
+type Chart struct {
+	pagination.PaginatedResponse[ChartEntry]
+	// ComputedAt - время последнего пересчета, nil если чарты еще не считались
+	ComputedAt *time.Time `json:"computed_at"`
+}
+
+// ChartParams - параметры пересчета чартов
+type ChartParams struct {
+	// TrendingWindow - за какой период учитываются добавления в trending
+	TrendingWindow time.Duration
+	// TrendingHalfLife - через сколько вес добавления уменьшается вдвое
+	TrendingHalfLife time.Duration
+	// PriorVotes - сколько средних оценок каталога добавляется к оценкам элемента в байесовском среднем
+	PriorVotes int
+	// Size - сколько мест хранится в каждом чарте
+	Size int
+}
@@ -13,4 +13,8 @@ type Collection struct {
 
 	UserID int    `json:"user_id" db:"user_id"`
 	Type   string `json:"type" db:"type"`
+
+	LikesCount    int `json:"likes_count" db:"likes_count"`
+	SavesCount    int `json:"saves_count" db:"saves_count"`
+	CommentsCount int `json:"comments_count" db:"comments_count"`
 }
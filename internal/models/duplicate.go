@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// DuplicateCandidate - возможный дубликат элемента каталога
+type DuplicateCandidate struct {
+	Item       CollectionItem `json:"item"`
+	Similarity float64        `json:"similarity"` // похожесть нормализованных названий 0..1
+}
+
+// ItemMergeResult - итог слияния дубликата с каноническим элементом
+type ItemMergeResult struct {
+	CanonicalID           string    `json:"canonical_id"`
+	DuplicateID           string    `json:"duplicate_id"`
+	MovedAssignments      int64     `json:"moved_assignments"`       // перенесено элементов коллекций
+	MovedProgress         int64     `json:"moved_progress"`          // перенесено записей прогресса и оценок
+	MovedSeasons          int64     `json:"moved_seasons"`           // перенесено сезонов, которых не было у канонического элемента
+	MovedEpisodes         int64     `json:"moved_episodes"`          // перенесено эпизодов в совпадающие сезоны
+	MergedWatchedEpisodes int64     `json:"merged_watched_episodes"` // отметки о просмотре, перенесенные на совпадающие эпизоды
+	MergedBy              int       `json:"merged_by"`
+	MergedAt              time.Time `json:"merged_at"`
+}
@@ -0,0 +1,75 @@
+package models
+
+import "time"
+
+// Статусы предложенной правки
+const (
+	EditStatusPending  = "pending"
+	EditStatusAccepted = "accepted"
+	EditStatusRejected = "rejected"
+)
+
+// ItemChanges - поля элемента, которые можно предложить изменить. nil - поле не меняется
+type ItemChanges struct {
+	Title       *string `json:"title,omitempty" example:"Dune"`
+	Description *string `json:"description,omitempty"`
+	CoverImage  *string `json:"cover_image,omitempty"`
+	ReleaseYear *int    `json:"release_year,omitempty" example:"1965"`
+}
+
+// ToMap возвращает только заданные поля: имя поля -> новое значение
+func (c ItemChanges) ToMap() map[string]any {
+	changes := map[string]any{}
+	if c.Title != nil {
+		changes["title"] = *c.Title
+	}
+	if c.Description != nil {
+		changes["description"] = *c.Description
+	}
+	if c.CoverImage != nil {
+		changes["cover_image"] = *c.CoverImage
+	}
+	if c.ReleaseYear != nil {
+		changes["release_year"] = *c.ReleaseYear
+	}
+	return changes
+}
+
+type EditSuggestion struct {
+	ID            int         `json:"id" db:"id"`
+	ItemID        string      `json:"item_id" db:"item_id"`
+	AuthorID      *int        `json:"author_id" db:"author_id"`
+	Changes       ItemChanges `json:"changes" db:"changes"`
+	Comment       *string     `json:"comment" db:"comment"`
+	Status        string      `json:"status" db:"status"`
+	ReviewerID    *int        `json:"reviewer_id" db:"reviewer_id"`
+	ReviewComment *string     `json:"review_comment" db:"review_comment"`
+	CreatedAt     time.Time   `json:"created_at" db:"created_at"`
+	ReviewedAt    *time.Time  `json:"reviewed_at" db:"reviewed_at"`
+}
+
+// FieldChange - значение поля до и после правки
+type FieldChange struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+// ItemRevision - принятое изменение элемента с автором и диффом
+type ItemRevision struct {
+	ID                 int                    `json:"id" db:"id"`
+	ItemID             string                 `json:"item_id" db:"item_id"`
+	AuthorID           *int                   `json:"author_id" db:"author_id"`
+	SuggestionID       *int                   `json:"suggestion_id" db:"suggestion_id"`
+	RevertedRevisionID *int                   `json:"reverted_revision_id" db:"reverted_revision_id"`
+	Provider           *string                `json:"provider" db:"provider"` // Внешний каталог, если элемент обновлен автоматически
+	Diff               map[string]FieldChange `json:"diff" db:"diff"`
+	CreatedAt          time.Time              `json:"created_at" db:"created_at"`
+}
+
+// EditReviewNotificationPayload - данные уведомления о решении по предложенной правке
+type EditReviewNotificationPayload struct {
+	SuggestionID  int     `json:"suggestion_id"`
+	ItemID        string  `json:"item_id"`
+	Status        string  `json:"status"`
+	ReviewComment *string `json:"review_comment,omitempty"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// CollectionEngagement - счетчики коллекции и реакции текущего пользователя на нее
+type CollectionEngagement struct {
+	CollectionID  string `json:"collection_id"`
+	LikesCount    int    `json:"likes_count"`
+	SavesCount    int    `json:"saves_count"`
+	CommentsCount int    `json:"comments_count"`
+	Liked         bool   `json:"liked"`
+	Saved         bool   `json:"saved"`
+}
+
+// SavedCollection - коллекция из списка сохраненных пользователем
+type SavedCollection struct {
+	Collection
+	Owner   UserSummary `json:"owner"`
+	SavedAt time.Time   `json:"saved_at"`
+}
+
+// CollectionComment - комментарий к коллекции с ответами на него
+type CollectionComment struct {
+	ID           int64       `json:"id"`
+	CollectionID string      `json:"collection_id"`
+	ParentID     *int64      `json:"parent_id"`
+	Author       UserSummary `json:"author"`
+	// Body пуст у удаленного комментария, он остается в ветке ради ответов на него
+	Body      string              `json:"body"`
+	IsDeleted bool                `json:"is_deleted"`
+	CreatedAt time.Time           `json:"created_at"`
+	EditedAt  *time.Time          `json:"edited_at"`
+	Replies   []CollectionComment `json:"replies"`
+}
+
+// CommentNotificationPayload - данные уведомления о комментарии к коллекции или ответе на комментарий
+type CommentNotificationPayload struct {
+	CollectionID   string `json:"collection_id"`
+	CollectionName string `json:"collection_name"`
+	CommentID      int64  `json:"comment_id"`
+	UserID         int    `json:"user_id"`
+	UserName       string `json:"user_name"`
+}
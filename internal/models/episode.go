@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// Статусы прогресса пользователя по элементу
+const (
+	ProgressStatusPlanned    = "planned"
+	ProgressStatusInProgress = "in_progress"
+	ProgressStatusCompleted  = "completed"
+	ProgressStatusDropped    = "dropped"
+)
+
+type Season struct {
+	ID        string    `json:"id" db:"id"`
+	ItemID    string    `json:"item_id" db:"item_id"`
+	Number    int       `json:"number" db:"number"`
+	Title     *string   `json:"title" db:"title"`
+	Episodes  []Episode `json:"episodes"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+type Episode struct {
+	ID             string     `json:"id" db:"id"`
+	SeasonID       string     `json:"season_id" db:"season_id"`
+	Number         int        `json:"number" db:"number"`
+	Title          *string    `json:"title" db:"title"`
+	AirDate        *time.Time `json:"air_date" db:"air_date"`
+	RuntimeMinutes *int       `json:"runtime_minutes" db:"runtime_minutes"`
+	Watched        bool       `json:"watched"` // Просмотрен ли эпизод текущим пользователем
+}
+
+// ItemProgress - прогресс пользователя по элементу
+type ItemProgress struct {
+	UserID        int        `json:"user_id" db:"user_id"`
+	ItemID        string     `json:"item_id" db:"item_id"`
+	Status        string     `json:"status" db:"status"`
+	Progress      int        `json:"progress" db:"progress"`
+	TotalEpisodes int        `json:"total_episodes"`
+	Rating        *int       `json:"rating" db:"rating"` // Оценка пользователя 1-10
+	StartedAt     *time.Time `json:"started_at" db:"started_at"`
+	FinishedAt    *time.Time `json:"finished_at" db:"finished_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// NextEpisode - следующий непросмотренный эпизод сериала в процессе просмотра
+type NextEpisode struct {
+	ItemID       string  `json:"item_id"`
+	ItemTitle    string  `json:"item_title"`
+	ItemType     string  `json:"item_type"`
+	CoverImage   *string `json:"cover_image"`
+	SeasonNumber int     `json:"season_number"`
+	Episode      Episode `json:"episode"`
+}
@@ -0,0 +1,12 @@
+package models
+
+import "io"
+
+// Export - подготовленная выгрузка. Заголовки ответа известны до начала записи,
+// поэтому ошибки доступа и формата возвращаются до того, как клиент получит первый байт
+type Export struct {
+	Filename    string
+	ContentType string
+	// Write потоково записывает выгрузку
+	Write func(w io.Writer) error
+}
@@ -0,0 +1,71 @@
+package models
+
+import "github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
+
+// Сортировки ленты элементов
+const (
+	FeedSortDefault  = ""
+	FeedSortTitle    = "title"
+	FeedSortNewest   = "newest"
+	FeedSortPopular  = "popular"
+	FeedSortTopRated = "top_rated"
+)
+
+var FeedSorts = map[string]bool{
+	FeedSortDefault:  true,
+	FeedSortTitle:    true,
+	FeedSortNewest:   true,
+	FeedSortPopular:  true,
+	FeedSortTopRated: true,
+}
+
+// Источник элемента: пользовательский или из каталога
+const (
+	ItemSourceCustom  = "custom"
+	ItemSourceCatalog = "catalog"
+)
+
+// ItemFilter - фильтр ленты элементов
+type ItemFilter struct {
+	UserID         int
+	Type           string
+	Genres         []string // slug жанров
+	GenresMatchAll bool
+	Tags           TagFilter
+	YearFrom       *int
+	YearTo         *int
+	RatingMin      *float64 // по средней оценке
+	RatingMax      *float64
+	Source         string // custom, catalog или пусто - все
+	NotInLibrary   bool   // исключить элементы из коллекций пользователя
+	Sort           string
+}
+
+// FeedItem - элемент ленты со статистикой
+type FeedItem struct {
+	CollectionItem
+	Popularity   int      `json:"popularity"` // кол-во коллекций с элементом
+	AvgRating    *float64 `json:"avg_rating"`
+	RatingsCount int      `json:"ratings_count"`
+}
+
+// FacetValue - значение фасета и кол-во элементов с ним
+type FacetValue struct {
+	Value string `json:"value"`
+	Label string `json:"label,omitempty"`
+	Count int64  `json:"count"`
+}
+
+// ItemFacets - счетчики для фильтров ленты. Каждый фасет считается
+// с учетом всех фильтров, кроме собственного
+type ItemFacets struct {
+	Types   []FacetValue `json:"types"`
+	Genres  []FacetValue `json:"genres"`
+	Sources []FacetValue `json:"sources"`
+}
+
+// ItemFeed - страница ленты с фасетами
+type ItemFeed struct {
+	pagination.PaginatedResponse[FeedItem]
+	Facets ItemFacets `json:"facets"`
+}
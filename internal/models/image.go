@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// Куда загружено изображение
+const (
+	ImageTargetItem       = "item"
+	ImageTargetCollection = "collection"
+	ImageTargetAvatar     = "avatar"
+)
+
+// Размеры миниатюр: имя -> максимальная сторона в пикселях
+const (
+	ImageSizeOriginal = "original"
+	ImageSizeSmall    = "small"
+	ImageSizeMedium   = "medium"
+	ImageSizeLarge    = "large"
+)
+
+var ImageThumbnailSizes = map[string]int{
+	ImageSizeSmall:  160,
+	ImageSizeMedium: 320,
+	ImageSizeLarge:  640,
+}
+
+type Image struct {
+	ID          string    `json:"id" db:"id"`
+	OwnerID     *int      `json:"owner_id" db:"owner_id"`
+	TargetType  string    `json:"target_type" db:"target_type"`
+	TargetID    string    `json:"target_id" db:"target_id"`
+	BlobPrefix  string    `json:"-" db:"blob_prefix"`
+	OriginalKey string    `json:"-" db:"original_key"`
+	ContentType string    `json:"content_type" db:"content_type"`
+	Size        int64     `json:"size" db:"size"`
+	Width       int       `json:"width" db:"width"`
+	Height      int       `json:"height" db:"height"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// ImageUpload - результат загрузки: постоянные ссылки на оригинал и миниатюры
+type ImageUpload struct {
+	Image
+	URL        string            `json:"url" example:"http://localhost:3000/api/v1/images/0b6c1f9e-3c55-4a3e-9a43-1f3f9f7a1c2d"`
+	Thumbnails map[string]string `json:"thumbnails"`
+}
+
+// CollectionCover - обложка коллекции: своя (RedirectURL) или сгенерированная мозаика (Data)
+type CollectionCover struct {
+	RedirectURL string
+	Data        []byte
+	ContentType string
+	ETag        string
+}
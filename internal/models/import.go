@@ -0,0 +1,89 @@
+package models
+
+import (
+	"time"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/importer"
+)
+
+// Статусы задачи импорта
+const (
+	ImportStatusPending   = "pending"
+	ImportStatusRunning   = "running"
+	ImportStatusCompleted = "completed"
+	ImportStatusFailed    = "failed"
+)
+
+// Статусы строки импорта
+const (
+	ImportRowPending   = "pending"
+	ImportRowMatched   = "matched"   // найден элемент каталога
+	ImportRowCreated   = "created"   // создан свой элемент пользователя
+	ImportRowUnmatched = "unmatched" // элемент не найден и не создан
+	ImportRowFailed    = "failed"
+	ImportRowResolved  = "resolved" // сопоставлен пользователем вручную
+)
+
+// Способы сопоставления строки с элементом
+const (
+	ImportMatchExternalID = "external_id"
+	ImportMatchTitle      = "title"
+	ImportMatchManual     = "manual"
+)
+
+// ImportJob - задача импорта выгрузки другого трекера
+type ImportJob struct {
+	ID            string     `json:"id" db:"id"`
+	UserID        int        `json:"user_id" db:"user_id"`
+	Format        string     `json:"format" db:"format" example:"goodreads"`
+	Filename      *string    `json:"filename" db:"filename"`
+	CreateCustom  bool       `json:"create_custom" db:"create_custom"`
+	Status        string     `json:"status" db:"status" example:"running"`
+	TotalRows     int        `json:"total_rows" db:"total_rows"`
+	ProcessedRows int        `json:"processed_rows" db:"processed_rows"`
+	MatchedRows   int        `json:"matched_rows" db:"matched_rows"`
+	CreatedRows   int        `json:"created_rows" db:"created_rows"`
+	UnmatchedRows int        `json:"unmatched_rows" db:"unmatched_rows"`
+	FailedRows    int        `json:"failed_rows" db:"failed_rows"`
+	ResolvedRows  int        `json:"resolved_rows" db:"resolved_rows"`
+	Progress      float64    `json:"progress" example:"42.5"` // процент обработанных строк
+	Error         *string    `json:"error" db:"error"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	StartedAt     *time.Time `json:"started_at" db:"started_at"`
+	FinishedAt    *time.Time `json:"finished_at" db:"finished_at"`
+}
+
+// ImportRow - строка выгрузки и результат ее сопоставления
+type ImportRow struct {
+	ID          int             `json:"id" db:"id"`
+	JobID       string          `json:"job_id" db:"job_id"`
+	Line        int             `json:"line" db:"line"`
+	Record      importer.Record `json:"record" db:"data"`
+	Status      string          `json:"status" db:"status"`
+	MatchMethod *string         `json:"match_method" db:"match_method"`
+	ItemID      *string         `json:"item_id" db:"item_id"`
+	Error       *string         `json:"error" db:"error"`
+}
+
+// ImportRowResult - итог обработки строки для сохранения
+type ImportRowResult struct {
+	Status      string
+	MatchMethod string
+	ItemID      *string
+	// CollectionIDs - коллекции пользователя, соответствующие спискам строки
+	CollectionIDs []string
+	// SourceIDs - внешние ID строки, по которым следующие импорты пользователя найдут элемент
+	SourceIDs map[string]string
+	Error     *string
+}
+
+// ImportFinishedNotificationPayload - данные уведомления о завершении импорта
+type ImportFinishedNotificationPayload struct {
+	JobID         string `json:"job_id"`
+	Format        string `json:"format"`
+	Status        string `json:"status"`
+	MatchedRows   int    `json:"matched_rows"`
+	CreatedRows   int    `json:"created_rows"`
+	UnmatchedRows int    `json:"unmatched_rows"`
+	FailedRows    int    `json:"failed_rows"`
+}
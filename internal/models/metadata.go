@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/metadata"
+)
+
+// ExternalID - запись элемента во внешнем каталоге
+type ExternalID struct {
+	ItemID     string    `json:"item_id" db:"item_id"`
+	Provider   string    `json:"provider" db:"provider"`
+	ExternalID string    `json:"external_id" db:"external_id"`
+	ImportedBy *int      `json:"imported_by" db:"imported_by"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	// RefreshedAt - время последнего обновления из каталога, RefreshError - его ошибка
+	RefreshedAt  time.Time `json:"refreshed_at" db:"refreshed_at"`
+	RefreshError *string   `json:"refresh_error" db:"refresh_error"`
+}
+
+// LookupQuery - параметры поиска во внешних каталогах
+type LookupQuery struct {
+	Text     string
+	ISBN     string
+	Type     string
+	Provider string
+	Limit    int
+}
+
+// LookupResult - найденная запись и элемент каталога, если она уже импортирована
+type LookupResult struct {
+	metadata.Result
+	ExistingItemID *string `json:"existing_item_id"`
+}
+
+// LookupResponse - результаты всех опрошенных провайдеров. Ошибка одного провайдера не прерывает поиск
+type LookupResponse struct {
+	Results []LookupResult    `json:"results"`
+	Errors  map[string]string `json:"errors,omitempty"`
+}
+
+// ItemImportResult - итог импорта: созданный или уже существующий элемент
+type ItemImportResult struct {
+	ItemID             string               `json:"item_id"`
+	Created            bool                 `json:"created"`
+	PossibleDuplicates []DuplicateCandidate `json:"possible_duplicates"`
+}
+
+// LockableItemFields - поля элемента, которые модератор может закрепить от обновления из внешних каталогов
+var LockableItemFields = map[string]bool{
+	"title":        true,
+	"description":  true,
+	"cover_image":  true,
+	"release_year": true,
+}
+
+// ItemFieldLock - поле элемента, закрепленное модератором
+type ItemFieldLock struct {
+	ItemID   string    `json:"item_id" db:"item_id"`
+	Field    string    `json:"field" db:"field" example:"title"`
+	LockedBy *int      `json:"locked_by" db:"locked_by"`
+	LockedAt time.Time `json:"locked_at" db:"locked_at"`
+}
+
+// MetadataRefreshResult - итог обновления элемента из одного внешнего каталога.
+// Revision пустая, если данные не изменились
+type MetadataRefreshResult struct {
+	ItemID        string        `json:"item_id"`
+	Provider      string        `json:"provider"`
+	ExternalID    string        `json:"external_id"`
+	Revision      *ItemRevision `json:"revision"`
+	SkippedFields []string      `json:"skipped_fields"`
+	Error         string        `json:"error,omitempty"`
+}
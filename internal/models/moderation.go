@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Статусы модерации элемента
+const (
+	ModerationStatusNone             = "none"
+	ModerationStatusPending          = "pending"
+	ModerationStatusApproved         = "approved"
+	ModerationStatusRejected         = "rejected"
+	ModerationStatusChangesRequested = "changes_requested"
+)
+
+// Решения в журнале модерации
+const (
+	ModerationDecisionSubmitted        = "submitted"
+	ModerationDecisionApproved         = "approved"
+	ModerationDecisionRejected         = "rejected"
+	ModerationDecisionChangesRequested = "changes_requested"
+)
+
+// ModerationDecision - запись журнала модерации: отправка на проверку автором или решение модератора
+type ModerationDecision struct {
+	ID        int       `json:"id" db:"id"`
+	ItemID    string    `json:"item_id" db:"item_id"`
+	ActorID   *int      `json:"actor_id" db:"actor_id"`
+	Decision  string    `json:"decision" db:"decision"`
+	Reason    *string   `json:"reason" db:"reason"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Типы уведомлений
+const (
+	NotificationModerationDecision = "moderation_decision"
+	NotificationEditReviewed       = "edit_reviewed"
+	NotificationImportFinished     = "import_finished"
+	NotificationNewFollower        = "new_follower"
+	NotificationFollowRequest      = "follow_request"
+	NotificationFollowAccepted     = "follow_accepted"
+	NotificationCollectionComment  = "collection_comment"
+	NotificationCommentReply       = "comment_reply"
+)
+
+// NotificationTypes - все типы уведомлений, для каждого пользователь выбирает канал
+var NotificationTypes = []string{
+	NotificationModerationDecision,
+	NotificationEditReviewed,
+	NotificationImportFinished,
+	NotificationNewFollower,
+	NotificationFollowRequest,
+	NotificationFollowAccepted,
+	NotificationCollectionComment,
+	NotificationCommentReply,
+}
+
+// Значения настройки уведомлений. Кроме них значением может быть имя подключенного канала доставки,
+// тогда уведомление показывается в приложении и отправляется в канал
+const (
+	NotificationChannelInApp = "in_app"
+	NotificationChannelEmail = "email"
+	NotificationChannelOff   = "off"
+)
+
+// Статусы доставки уведомления во внешний канал
+const (
+	DeliveryStatusPending = "pending"
+	DeliveryStatusSent    = "sent"
+	DeliveryStatusFailed  = "failed"
+)
+
+type Notification struct {
+	ID        int             `json:"id" db:"id"`
+	UserID    int             `json:"user_id" db:"user_id"`
+	Type      string          `json:"type" db:"type"`
+	Payload   json.RawMessage `json:"payload" db:"payload" swaggertype:"object"`
+	ReadAt    *time.Time      `json:"read_at" db:"read_at"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+}
+
+// ModerationNotificationPayload - данные уведомления о решении модератора
+type ModerationNotificationPayload struct {
+	ItemID    string  `json:"item_id"`
+	ItemTitle string  `json:"item_title"`
+	Decision  string  `json:"decision"`
+	Reason    *string `json:"reason,omitempty"`
+}
+
+// NotificationPreference - канал уведомлений одного типа
+type NotificationPreference struct {
+	Type    string `json:"type" example:"new_follower"`
+	Channel string `json:"channel" example:"in_app"`
+}
+
+// UnreadNotifications - количество непрочитанных уведомлений
+type UnreadNotifications struct {
+	Count int `json:"count"`
+}
+
+// NotificationDelivery - отправка уведомления во внешний канал вместе с получателем
+type NotificationDelivery struct {
+	ID             int64
+	Channel        string
+	Attempts       int
+	Notification   Notification
+	RecipientName  string
+	RecipientEmail string
+}
@@ -0,0 +1,49 @@
+package models
+
+// Типы событий потока /stream
+const (
+	// EventNotificationsUnread - изменилось число непрочитанных уведомлений: пришло новое или их прочитали
+	EventNotificationsUnread = "notifications.unread"
+	// EventCollectionItemAdded - в коллекцию добавлен элемент
+	EventCollectionItemAdded = "collection.item_added"
+	// EventCollectionUpdated - изменились видимость, теги или обложка коллекции
+	EventCollectionUpdated = "collection.updated"
+	// EventCollectionComment - комментарий к коллекции добавлен, изменен или удален
+	EventCollectionComment = "collection.comment"
+	// EventImportProgress - прогресс задачи импорта, последнее событие приходит после завершения
+	EventImportProgress = "import.progress"
+)
+
+// Что изменилось в коллекции (EventCollectionUpdated) и что произошло с комментарием (EventCollectionComment)
+const (
+	CollectionChangeVisibility = "visibility"
+	CollectionChangeTags       = "tags"
+	CollectionChangeCover      = "cover"
+
+	CommentAdded   = "added"
+	CommentEdited  = "edited"
+	CommentDeleted = "deleted"
+)
+
+// CollectionItemAddedEvent - данные EventCollectionItemAdded
+type CollectionItemAddedEvent struct {
+	ItemID     string `json:"item_id"`
+	Title      string `json:"title"`
+	UserReview string `json:"user_review,omitempty"`
+}
+
+// CollectionUpdatedEvent - данные EventCollectionUpdated
+type CollectionUpdatedEvent struct {
+	Change   string `json:"change" example:"visibility"`
+	IsPublic *bool  `json:"is_public,omitempty"`
+	Tags     []Tag  `json:"tags,omitempty"`
+	CoverURL string `json:"cover_url,omitempty"`
+}
+
+// CollectionCommentEvent - данные EventCollectionComment
+type CollectionCommentEvent struct {
+	Action    string `json:"action" example:"added"`
+	CommentID int64  `json:"comment_id"`
+	// Comment - комментарий после изменения, у удаленного не передается
+	Comment *CollectionComment `json:"comment,omitempty"`
+}
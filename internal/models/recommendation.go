@@ -0,0 +1,34 @@
+package models
+
+// SimilarItem - элемент, который часто встречается в библиотеках вместе с исходным
+type SimilarItem struct {
+	Item          CollectionItem `json:"item"`
+	Score         float64        `json:"score"`          // похожесть 0..1
+	CoOccurrences int            `json:"co_occurrences"` // у скольких пользователей есть оба элемента
+}
+
+// RecommendedItem - рекомендация пользователю
+type RecommendedItem struct {
+	Item  CollectionItem `json:"item"`
+	Score float64        `json:"score"`
+	// BecauseOf - элементы библиотеки пользователя, сильнее всего повлиявшие на рекомендацию
+	BecauseOf []RecommendationSource `json:"because_of"`
+}
+
+// RecommendationSource - элемент библиотеки, из-за которого элемент рекомендован
+type RecommendationSource struct {
+	ItemID string `json:"item_id"`
+	Title  string `json:"title"`
+}
+
+// SimilarityParams - параметры пересчета похожих элементов
+type SimilarityParams struct {
+	// UserHistory - сколько последних элементов каждого пользователя учитывается
+	UserHistory int
+	// MinCoOccurrences - минимум пользователей, у которых есть оба элемента
+	MinCoOccurrences int
+	// Shrinkage - поправка на малое число совпадений: похожесть умножается на n/(n+Shrinkage)
+	Shrinkage float64
+	// PerItem - сколько похожих элементов хранится для каждого элемента
+	PerItem int
+}
@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// Типы связей между элементами. Связь направленная: source является <type> для target
+const (
+	RelationSequel        = "sequel"
+	RelationPrequel       = "prequel"
+	RelationAdaptation    = "adaptation"
+	RelationSpinOff       = "spin_off"
+	RelationSameFranchise = "same_franchise"
+	RelationRemake        = "remake"
+)
+
+var RelationTypes = map[string]bool{
+	RelationSequel:        true,
+	RelationPrequel:       true,
+	RelationAdaptation:    true,
+	RelationSpinOff:       true,
+	RelationSameFranchise: true,
+	RelationRemake:        true,
+}
+
+type ItemRelation struct {
+	ID           int       `json:"id" db:"id"`
+	SourceItemID string    `json:"source_item_id" db:"source_item_id"`
+	TargetItemID string    `json:"target_item_id" db:"target_item_id"`
+	RelationType string    `json:"relation_type" db:"relation_type"`
+	CreatedBy    *int      `json:"created_by" db:"created_by"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// RelationGraphNode - элемент графа связей с расстоянием от корневого элемента
+type RelationGraphNode struct {
+	Item  CollectionItem `json:"item"`
+	Depth int            `json:"depth"`
+}
+
+// RelationGraph - граф связей элемента на заданную глубину
+type RelationGraph struct {
+	RootItemID string              `json:"root_item_id"`
+	Depth      int                 `json:"depth"`
+	Nodes      []RelationGraphNode `json:"nodes"`
+	Edges      []ItemRelation      `json:"edges"`
+	Franchises []Franchise         `json:"franchises"`
+}
+
+type Franchise struct {
+	ID          string           `json:"id" db:"id"`
+	Name        string           `json:"name" db:"name"`
+	Description string           `json:"description" db:"description"`
+	CreatedBy   *int             `json:"created_by" db:"created_by"`
+	CreatedAt   time.Time        `json:"created_at" db:"created_at"`
+	Items       []CollectionItem `json:"items,omitempty"`
+}
@@ -0,0 +1,17 @@
+package models
+
+// SearchQuery - параметры полнотекстового поиска по каталогу
+type SearchQuery struct {
+	Query  string
+	Type   string
+	UserID int // для видимости собственных приватных элементов
+}
+
+// SearchResult - найденный элемент с релевантностью и подсветкой совпадений.
+// TitleHighlight и Snippet - экранированный HTML, совпадения обернуты в <mark>
+type SearchResult struct {
+	Item           CollectionItem `json:"item"`
+	Rank           float64        `json:"rank"`
+	TitleHighlight string         `json:"title_highlight"`
+	Snippet        string         `json:"snippet"`
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Статусы подписки
+const (
+	FollowStatusAccepted = "accepted"
+	// FollowStatusPending - запрос на подписку к приватному аккаунту
+	FollowStatusPending = "pending"
+)
+
+// Типы событий активности
+const (
+	ActivityItemAdded           = "item_added"
+	ActivityItemCompleted       = "item_completed"
+	ActivityItemRated           = "item_rated"
+	ActivityCollectionPublished = "collection_published"
+)
+
+// UserSummary - пользователь в списках подписчиков и ленте
+type UserSummary struct {
+	ID        int     `json:"id"`
+	Name      string  `json:"name"`
+	AvatarURL *string `json:"avatar_url"`
+	IsPrivate bool    `json:"is_private"`
+}
+
+// PublicProfile - профиль пользователя для других пользователей
+type PublicProfile struct {
+	UserSummary
+	FollowersCount int `json:"followers_count"`
+	FollowingCount int `json:"following_count"`
+	// FollowStatus - подписка текущего пользователя: accepted, pending или пусто
+	FollowStatus string `json:"follow_status,omitempty" example:"accepted"`
+	// FollowsYou - подписан ли пользователь на текущего
+	FollowsYou bool      `json:"follows_you"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// FollowUser - подписчик, подписка или запрос на подписку
+type FollowUser struct {
+	UserSummary
+	Status     string     `json:"status" example:"accepted"`
+	CreatedAt  time.Time  `json:"created_at"`
+	AcceptedAt *time.Time `json:"accepted_at"`
+}
+
+// Follow - результат подписки
+type Follow struct {
+	FollowerID int    `json:"follower_id"`
+	FolloweeID int    `json:"followee_id"`
+	Status     string `json:"status" example:"pending"`
+}
+
+// FollowNotificationPayload - данные уведомлений о подписке
+type FollowNotificationPayload struct {
+	UserID   int    `json:"user_id"`
+	UserName string `json:"user_name"`
+}
+
+// ActivityItem и ActivityCollection - краткие данные элемента и коллекции события
+type ActivityItem struct {
+	ID         string  `json:"id"`
+	Type       string  `json:"type"`
+	Title      string  `json:"title"`
+	CoverImage *string `json:"cover_image"`
+}
+
+type ActivityCollection struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Activity - событие активности пользователя
+type Activity struct {
+	ID         int64               `json:"id"`
+	User       UserSummary         `json:"user"`
+	Type       string              `json:"type" example:"item_rated"`
+	Item       *ActivityItem       `json:"item,omitempty"`
+	Collection *ActivityCollection `json:"collection,omitempty"`
+	Payload    json.RawMessage     `json:"payload" swaggertype:"object"`
+	CreatedAt  time.Time           `json:"created_at"`
+}
+
+// ActivityRatingPayload - данные события оценки
+type ActivityRatingPayload struct {
+	Rating int `json:"rating"`
+}
@@ -0,0 +1,12 @@
+package models
+
+// ItemSuggestion - подсказка автодополнения при добавлении элемента
+type ItemSuggestion struct {
+	ID         string  `json:"id"`
+	Type       string  `json:"type"`
+	Title      string  `json:"title"`
+	CoverImage *string `json:"cover_image"`
+	IsCustom   bool    `json:"is_custom"`
+	Popularity int     `json:"popularity"` // в скольких коллекциях есть элемент
+	Score      float64 `json:"score"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// CollectionEntry - элемент коллекции с датой добавления и заметкой
+type CollectionEntry struct {
+	CollectionItem
+	AddedAt time.Time `json:"added_at"`
+	Review  *string   `json:"user_review"`
+}
+
+// Виды публичной активности пользователя
+const (
+	PublicActivityCollectionCreated = "collection_created"
+	PublicActivityItemAdded         = "item_added"
+)
+
+// PublicActivity - событие в публичных коллекциях пользователя. Item есть только у добавления элемента
+type PublicActivity struct {
+	Type           string
+	CollectionID   string
+	CollectionName string
+	Item           *CollectionItem
+	Review         *string
+	CreatedAt      time.Time
+}
+
+// CatalogEntry - элемент публичного каталога. PublishedAt - время появления в каталоге:
+// создания или одобрения модератором
+type CatalogEntry struct {
+	CollectionItem
+	PublishedAt time.Time
+}
+
+// SyndicationFeed - готовая лента Atom или RSS с данными для кеширования
+type SyndicationFeed struct {
+	ContentType  string
+	Body         []byte
+	ETag         string
+	LastModified time.Time
+	// MaxAge - сколько ридеры и прокси могут не перезапрашивать ленту
+	MaxAge time.Duration
+}
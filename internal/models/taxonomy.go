@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+type Genre struct {
+	ID        int       `json:"id" db:"id"`
+	Slug      string    `json:"slug" db:"slug"`
+	Name      string    `json:"name" db:"name"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+type Tag struct {
+	ID         int       `json:"id" db:"id"`
+	UserID     int       `json:"user_id" db:"user_id"`
+	Name       string    `json:"name" db:"name"`
+	UsageCount int       `json:"usage_count"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// TagFilter - фильтр по тегам пользователя. MatchAll = true - AND, иначе OR
+type TagFilter struct {
+	UserID   int
+	Tags     []string
+	MatchAll bool
+}
+
+func (f TagFilter) IsEmpty() bool {
+	return len(f.Tags) == 0
+}
@@ -3,6 +3,13 @@ package models
 
 import "time"
 
+// Роли пользователей
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
 type User struct {
 	ID       int     `json:"id" db:"id"`
 	Name     string  `json:"name" binding:"required"`
@@ -0,0 +1,134 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Типы событий вебхуков
+const (
+	// WebhookEventItemAdded - элемент добавлен в коллекцию
+	WebhookEventItemAdded = "item.added"
+	// WebhookEventItemStatusChanged - изменился статус просмотра/чтения элемента
+	WebhookEventItemStatusChanged = "item.status_changed"
+	// WebhookEventReviewPosted - пользователь оценил элемент или добавил его в коллекцию с отзывом
+	WebhookEventReviewPosted = "review.posted"
+	// WebhookEventTest - тестовое событие, отправляется только вручную
+	WebhookEventTest = "webhook.test"
+)
+
+// WebhookEventTypes - события, на которые можно подписать вебхук
+var WebhookEventTypes = []string{
+	WebhookEventItemAdded,
+	WebhookEventItemStatusChanged,
+	WebhookEventReviewPosted,
+}
+
+type Webhook struct {
+	ID          int      `json:"id"`
+	UserID      int      `json:"user_id"`
+	URL         string   `json:"url" example:"https://example.com/hooks/memoria"`
+	Description *string  `json:"description"`
+	Events      []string `json:"events" example:"item.added,review.posted"`
+	IsActive    bool     `json:"is_active"`
+	// Secret - ключ подписи, показывается только при создании вебхука
+	Secret    string    `json:"secret,omitempty" example:"whsec_3f1c..."`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// WebhookInput - создание и изменение вебхука
+type WebhookInput struct {
+	URL         string   `json:"url" binding:"required" example:"https://example.com/hooks/memoria"`
+	Description *string  `json:"description"`
+	Events      []string `json:"events" binding:"required" example:"item.added,review.posted"`
+	// IsActive - при изменении nil оставляет текущее значение, при создании вебхук включен
+	IsActive *bool `json:"is_active"`
+}
+
+// WebhookDelivery - запись журнала отправок вебхука
+type WebhookDelivery struct {
+	ID        int64           `json:"id"`
+	WebhookID int             `json:"webhook_id"`
+	EventType string          `json:"event_type" example:"item.added"`
+	Payload   json.RawMessage `json:"payload" swaggertype:"object"`
+	Status    string          `json:"status" example:"sent"`
+	Attempts  int             `json:"attempts"`
+	// ResponseStatus и ResponseBody - ответ получателя на последнюю попытку
+	ResponseStatus *int       `json:"response_status"`
+	ResponseBody   *string    `json:"response_body"`
+	DurationMs     *int       `json:"duration_ms"`
+	LastError      *string    `json:"last_error"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// WebhookDeliveryTask - отправка вместе с адресом и секретом вебхука
+type WebhookDeliveryTask struct {
+	WebhookDelivery
+	UserID int
+	URL    string
+	Secret string
+}
+
+// WebhookDeliveryResult - результат попытки отправки
+type WebhookDeliveryResult struct {
+	ResponseStatus *int
+	ResponseBody   *string
+	DurationMs     *int
+	// Error - nil, если получатель ответил 2xx
+	Error error
+}
+
+// WebhookEnvelope - тело запроса вебхука
+type WebhookEnvelope struct {
+	// ID - ID отправки, повторные попытки приходят с тем же ID
+	ID        int64           `json:"id"`
+	Type      string          `json:"type" example:"item.added"`
+	UserID    int             `json:"user_id"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data" swaggertype:"object"`
+}
+
+// WebhookItem и WebhookCollection - краткие данные элемента и коллекции в событиях
+type WebhookItem struct {
+	ID    string `json:"id"`
+	Type  string `json:"type"`
+	Title string `json:"title"`
+}
+
+type WebhookCollection struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// WebhookItemAddedPayload - данные item.added
+type WebhookItemAddedPayload struct {
+	Collection WebhookCollection `json:"collection"`
+	Item       WebhookItem       `json:"item"`
+	UserReview *string           `json:"user_review,omitempty"`
+}
+
+// WebhookStatusChangedPayload - данные item.status_changed
+type WebhookStatusChangedPayload struct {
+	Item           WebhookItem `json:"item"`
+	PreviousStatus *string     `json:"previous_status"`
+	Status         string      `json:"status"`
+	Progress       int         `json:"progress"`
+	TotalEpisodes  int         `json:"total_episodes"`
+}
+
+// WebhookReviewPayload - данные review.posted: оценка или отзыв при добавлении в коллекцию
+type WebhookReviewPayload struct {
+	Item       WebhookItem        `json:"item"`
+	Collection *WebhookCollection `json:"collection,omitempty"`
+	Rating     *int               `json:"rating,omitempty"`
+	Review     *string            `json:"review,omitempty"`
+}
+
+// WebhookTestPayload - данные webhook.test
+type WebhookTestPayload struct {
+	WebhookID int    `json:"webhook_id"`
+	Message   string `json:"message"`
+}
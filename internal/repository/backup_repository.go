@@ -0,0 +1,750 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/backup"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+type BackupRepository struct {
+	db     *sql.DB
+	logger *zap.SugaredLogger
+}
+
+func NewBackupPostgres(db *sql.DB, logger *zap.SugaredLogger) *BackupRepository {
+	return &BackupRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// uuidPattern - ID из копии проверяются до запроса: неверный UUID в запросе прервал бы транзакцию
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// GetBackup собирает копию библиотеки пользователя. Все читается в одном снимке базы
+func (r *BackupRepository) GetBackup(userID int) (*backup.Archive, error) {
+	tx, err := r.db.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	archive := &backup.Archive{
+		Format:      backup.Format,
+		Version:     backup.Version,
+		CreatedAt:   time.Now(),
+		Collections: []backup.Collection{},
+		Items:       []backup.Item{},
+		Progress:    []backup.Progress{},
+	}
+	// Элементы, на которые ссылается копия, в порядке первого упоминания
+	var itemIDs []string
+	referenced := map[string]bool{}
+	reference := func(itemID string) {
+		if !referenced[itemID] {
+			referenced[itemID] = true
+			itemIDs = append(itemIDs, itemID)
+		}
+	}
+
+	if err := r.backupCollections(tx, userID, archive, reference); err != nil {
+		return nil, err
+	}
+	if err := r.backupProgress(tx, userID, archive, reference); err != nil {
+		return nil, err
+	}
+	itemTags, err := r.backupItemTags(tx, userID, reference)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.backupItems(tx, userID, itemIDs, itemTags, archive); err != nil {
+		return nil, err
+	}
+
+	return archive, nil
+}
+
+func (r *BackupRepository) backupCollections(tx *sql.Tx, userID int, archive *backup.Archive, reference func(string)) error {
+	rows, err := tx.Query(fmt.Sprintf(`
+		SELECT id, name, COALESCE(description, ''), COALESCE(type, ''), COALESCE(is_public, false), cover_image, created_at
+		FROM %s
+		WHERE user_id = $1
+		ORDER BY created_at, id
+	`, collectionsTable), userID)
+	if err != nil {
+		return r.backupError("collections", err)
+	}
+	defer rows.Close()
+
+	index := map[string]int{}
+	for rows.Next() {
+		collection := backup.Collection{Items: []backup.Assignment{}}
+		err := rows.Scan(&collection.ID, &collection.Name, &collection.Description, &collection.Type,
+			&collection.IsPublic, &collection.CoverImage, &collection.CreatedAt)
+		if err != nil {
+			return r.backupError("collections", err)
+		}
+		index[collection.ID] = len(archive.Collections)
+		archive.Collections = append(archive.Collections, collection)
+	}
+	if err := rows.Err(); err != nil {
+		return r.backupError("collections", err)
+	}
+
+	tagRows, err := tx.Query(fmt.Sprintf(`
+		SELECT ct.collection_id, t.name
+		FROM %s ct
+		JOIN %s t ON t.id = ct.tag_id
+		JOIN %s c ON c.id = ct.collection_id
+		WHERE c.user_id = $1
+		ORDER BY lower(t.name)
+	`, collectionTagsTable, tagsTable, collectionsTable), userID)
+	if err != nil {
+		return r.backupError("collection tags", err)
+	}
+	defer tagRows.Close()
+	for tagRows.Next() {
+		var collectionID, tag string
+		if err := tagRows.Scan(&collectionID, &tag); err != nil {
+			return r.backupError("collection tags", err)
+		}
+		collection := &archive.Collections[index[collectionID]]
+		collection.Tags = append(collection.Tags, tag)
+	}
+	if err := tagRows.Err(); err != nil {
+		return r.backupError("collection tags", err)
+	}
+
+	assignmentRows, err := tx.Query(fmt.Sprintf(`
+		SELECT cia.collection_id, cia.item_id, cia.user_review, COALESCE(cia.added_at, LOCALTIMESTAMP)
+		FROM %s cia
+		JOIN %s c ON c.id = cia.collection_id
+		WHERE c.user_id = $1
+		ORDER BY cia.added_at, cia.item_id
+	`, collectionItemsAssignmentTable, collectionsTable), userID)
+	if err != nil {
+		return r.backupError("collection items", err)
+	}
+	defer assignmentRows.Close()
+	for assignmentRows.Next() {
+		var collectionID string
+		var assignment backup.Assignment
+		if err := assignmentRows.Scan(&collectionID, &assignment.ItemID, &assignment.Review, &assignment.AddedAt); err != nil {
+			return r.backupError("collection items", err)
+		}
+		collection := &archive.Collections[index[collectionID]]
+		collection.Items = append(collection.Items, assignment)
+		reference(assignment.ItemID)
+	}
+	if err := assignmentRows.Err(); err != nil {
+		return r.backupError("collection items", err)
+	}
+	return nil
+}
+
+func (r *BackupRepository) backupProgress(tx *sql.Tx, userID int, archive *backup.Archive, reference func(string)) error {
+	rows, err := tx.Query(fmt.Sprintf(`
+		SELECT item_id, status, progress, rating, started_at, finished_at, updated_at
+		FROM %s
+		WHERE user_id = $1
+		ORDER BY item_id
+	`, userItemProgressTable), userID)
+	if err != nil {
+		return r.backupError("progress", err)
+	}
+	defer rows.Close()
+
+	index := map[string]int{}
+	for rows.Next() {
+		var progress backup.Progress
+		err := rows.Scan(&progress.ItemID, &progress.Status, &progress.Progress, &progress.Rating,
+			&progress.StartedAt, &progress.FinishedAt, &progress.UpdatedAt)
+		if err != nil {
+			return r.backupError("progress", err)
+		}
+		index[progress.ItemID] = len(archive.Progress)
+		archive.Progress = append(archive.Progress, progress)
+		reference(progress.ItemID)
+	}
+	if err := rows.Err(); err != nil {
+		return r.backupError("progress", err)
+	}
+
+	watchedRows, err := tx.Query(fmt.Sprintf(`
+		SELECT s.item_id, s.number, e.number, COALESCE(w.watched_at, NOW())
+		FROM %s w
+		JOIN %s e ON e.id = w.episode_id
+		JOIN %s s ON s.id = e.season_id
+		WHERE w.user_id = $1
+		ORDER BY s.item_id, s.number, e.number
+	`, userWatchedEpisodesTable, itemEpisodesTable, itemSeasonsTable), userID)
+	if err != nil {
+		return r.backupError("watched episodes", err)
+	}
+	defer watchedRows.Close()
+	for watchedRows.Next() {
+		var itemID string
+		var watched backup.WatchedEpisode
+		if err := watchedRows.Scan(&itemID, &watched.Season, &watched.Episode, &watched.WatchedAt); err != nil {
+			return r.backupError("watched episodes", err)
+		}
+		i, ok := index[itemID]
+		if !ok {
+			// Отметки без записи прогресса: элемент в процессе просмотра
+			i = len(archive.Progress)
+			index[itemID] = i
+			archive.Progress = append(archive.Progress, backup.Progress{ItemID: itemID, Status: models.ProgressStatusInProgress})
+			reference(itemID)
+		}
+		archive.Progress[i].Watched = append(archive.Progress[i].Watched, watched)
+	}
+	if err := watchedRows.Err(); err != nil {
+		return r.backupError("watched episodes", err)
+	}
+	return nil
+}
+
+func (r *BackupRepository) backupItemTags(tx *sql.Tx, userID int, reference func(string)) (map[string][]string, error) {
+	rows, err := tx.Query(fmt.Sprintf(`
+		SELECT it.item_id, t.name
+		FROM %s it
+		JOIN %s t ON t.id = it.tag_id
+		WHERE it.user_id = $1
+		ORDER BY lower(t.name)
+	`, itemTagsTable, tagsTable), userID)
+	if err != nil {
+		return nil, r.backupError("item tags", err)
+	}
+	defer rows.Close()
+
+	tags := map[string][]string{}
+	for rows.Next() {
+		var itemID, tag string
+		if err := rows.Scan(&itemID, &tag); err != nil {
+			return nil, r.backupError("item tags", err)
+		}
+		tags[itemID] = append(tags[itemID], tag)
+		reference(itemID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, r.backupError("item tags", err)
+	}
+	return tags, nil
+}
+
+// backupItems - данные элементов. Своими считаются приватные элементы пользователя,
+// опубликованные элементы переносятся ссылкой на каталог
+func (r *BackupRepository) backupItems(tx *sql.Tx, userID int, itemIDs []string, itemTags map[string][]string, archive *backup.Archive) error {
+	if len(itemIDs) == 0 {
+		return nil
+	}
+	rows, err := tx.Query(fmt.Sprintf(`
+		SELECT id, COALESCE(type, ''), title, COALESCE(description, ''), cover_image, release_year,
+			COALESCE(is_custom, false) AND NOT COALESCE(is_public, false) AND creator_id IS NOT DISTINCT FROM $2,
+			created_at
+		FROM %s
+		WHERE id = ANY($1)
+		ORDER BY created_at, id
+	`, collectionItemsTable), pq.Array(itemIDs), userID)
+	if err != nil {
+		return r.backupError("items", err)
+	}
+	defer rows.Close()
+
+	index := map[string]int{}
+	for rows.Next() {
+		var item backup.Item
+		err := rows.Scan(&item.ID, &item.Type, &item.Title, &item.Description, &item.CoverImage, &item.ReleaseYear,
+			&item.Custom, &item.CreatedAt)
+		if err != nil {
+			return r.backupError("items", err)
+		}
+		item.Tags = itemTags[item.ID]
+		index[item.ID] = len(archive.Items)
+		archive.Items = append(archive.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return r.backupError("items", err)
+	}
+
+	externalRows, err := tx.Query(fmt.Sprintf(`
+		SELECT item_id, provider, external_id FROM %s WHERE item_id = ANY($1) ORDER BY created_at, provider
+	`, itemExternalIDsTable), pq.Array(itemIDs))
+	if err != nil {
+		return r.backupError("external ids", err)
+	}
+	defer externalRows.Close()
+	for externalRows.Next() {
+		var itemID string
+		var externalID backup.ExternalID
+		if err := externalRows.Scan(&itemID, &externalID.Provider, &externalID.ExternalID); err != nil {
+			return r.backupError("external ids", err)
+		}
+		item := &archive.Items[index[itemID]]
+		item.ExternalIDs = append(item.ExternalIDs, externalID)
+	}
+	if err := externalRows.Err(); err != nil {
+		return r.backupError("external ids", err)
+	}
+
+	genreRows, err := tx.Query(fmt.Sprintf(`
+		SELECT ig.item_id, g.slug FROM %s ig JOIN %s g ON g.id = ig.genre_id
+		WHERE ig.item_id = ANY($1)
+		ORDER BY g.slug
+	`, itemGenresTable, genresTable), pq.Array(itemIDs))
+	if err != nil {
+		return r.backupError("genres", err)
+	}
+	defer genreRows.Close()
+	for genreRows.Next() {
+		var itemID, slug string
+		if err := genreRows.Scan(&itemID, &slug); err != nil {
+			return r.backupError("genres", err)
+		}
+		item := &archive.Items[index[itemID]]
+		item.Genres = append(item.Genres, slug)
+	}
+	if err := genreRows.Err(); err != nil {
+		return r.backupError("genres", err)
+	}
+
+	// Сезоны сохраняются и у элементов каталога: если на другом сервере элемента нет,
+	// он восстанавливается своим элементом вместе с эпизодами
+	episodeRows, err := tx.Query(fmt.Sprintf(`
+		SELECT s.item_id, s.number, s.title, e.number, e.title, to_char(e.air_date, 'YYYY-MM-DD'), e.runtime_minutes
+		FROM %s s
+		LEFT JOIN %s e ON e.season_id = s.id
+		WHERE s.item_id = ANY($1)
+		ORDER BY s.item_id, s.number, e.number
+	`, itemSeasonsTable, itemEpisodesTable), pq.Array(itemIDs))
+	if err != nil {
+		return r.backupError("seasons", err)
+	}
+	defer episodeRows.Close()
+	for episodeRows.Next() {
+		var itemID string
+		var season backup.Season
+		var episodeNumber sql.NullInt64
+		var episode backup.Episode
+		err := episodeRows.Scan(&itemID, &season.Number, &season.Title, &episodeNumber, &episode.Title,
+			&episode.AirDate, &episode.RuntimeMinutes)
+		if err != nil {
+			return r.backupError("seasons", err)
+		}
+		item := &archive.Items[index[itemID]]
+		if n := len(item.Seasons); n == 0 || item.Seasons[n-1].Number != season.Number {
+			item.Seasons = append(item.Seasons, season)
+		}
+		if episodeNumber.Valid {
+			episode.Number = int(episodeNumber.Int64)
+			last := &item.Seasons[len(item.Seasons)-1]
+			last.Episodes = append(last.Episodes, episode)
+		}
+	}
+	if err := episodeRows.Err(); err != nil {
+		return r.backupError("seasons", err)
+	}
+	return nil
+}
+
+func (r *BackupRepository) backupError(step string, err error) error {
+	r.logger.Errorf("Failed to back up %s: %v", step, err)
+	return fmt.Errorf("failed to back up %s: %w", step, err)
+}
+
+// restoreState - состояние одного восстановления внутри транзакции
+type restoreState struct {
+	tx     *sql.Tx
+	userID int
+	mode   string
+	result *models.RestoreResult
+	// tags - ID тегов пользователя по имени в нижнем регистре
+	tags map[string]int
+}
+
+// RestoreBackup восстанавливает копию в одной транзакции: любая ошибка откатывает восстановление целиком.
+// ID коллекций и своих элементов назначаются заново, соответствие возвращается в результате
+func (r *BackupRepository) RestoreBackup(userID int, archive *backup.Archive, mode string) (*models.RestoreResult, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	s := &restoreState{
+		tx:     tx,
+		userID: userID,
+		mode:   mode,
+		result: &models.RestoreResult{
+			Mode:          mode,
+			CollectionIDs: map[string]string{},
+			ItemIDs:       map[string]string{},
+		},
+		tags: map[string]int{},
+	}
+
+	for _, item := range archive.Items {
+		if err := r.restoreItem(s, item); err != nil {
+			return nil, err
+		}
+	}
+	for _, collection := range archive.Collections {
+		if err := r.restoreCollection(s, collection); err != nil {
+			return nil, err
+		}
+	}
+	for _, progress := range archive.Progress {
+		if err := r.restoreProgress(s, progress); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return s.result, nil
+}
+
+// restoreItem сопоставляет элемент копии с элементом базы или создает свой элемент
+func (r *BackupRepository) restoreItem(s *restoreState, item backup.Item) error {
+	var itemID string
+	var err error
+	if item.Custom {
+		itemID, err = r.restoreCustomItem(s, item)
+	} else {
+		itemID, err = r.findCatalogItem(s, item)
+		if err == nil && itemID == "" {
+			// Элемента нет в каталоге этого сервера
+			itemID, err = r.createCustomItem(s, item)
+		} else if err == nil {
+			s.result.ItemsMatched++
+		}
+	}
+	if err != nil {
+		return err
+	}
+	s.result.ItemIDs[item.ID] = itemID
+
+	for _, tag := range item.Tags {
+		tagID, err := s.tag(tag)
+		if err != nil {
+			return err
+		}
+		_, err = s.tx.Exec(fmt.Sprintf(`
+			INSERT INTO %s (user_id, item_id, tag_id) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING
+		`, itemTagsTable), s.userID, itemID, tagID)
+		if err != nil {
+			return r.restoreError("item tags", err)
+		}
+	}
+	return nil
+}
+
+func (r *BackupRepository) restoreCustomItem(s *restoreState, item backup.Item) (string, error) {
+	var existingID string
+	if uuidPattern.MatchString(item.ID) {
+		err := s.tx.QueryRow(fmt.Sprintf(`
+			SELECT id FROM %s WHERE id = $1 AND creator_id = $2 AND is_custom = TRUE
+		`, collectionItemsTable), item.ID, s.userID).Scan(&existingID)
+		if err != nil && err != sql.ErrNoRows {
+			return "", r.restoreError("items", err)
+		}
+	}
+
+	switch {
+	case existingID == "" || s.mode == models.RestoreModeDuplicate:
+		return r.createCustomItem(s, item)
+	case s.mode == models.RestoreModeOverwrite:
+		_, err := s.tx.Exec(fmt.Sprintf(`
+			UPDATE %s SET title = $2, description = $3, cover_image = $4, release_year = $5, updated_at = NOW()
+			WHERE id = $1
+		`, collectionItemsTable), existingID, item.Title, item.Description, item.CoverImage, item.ReleaseYear)
+		if err != nil {
+			return "", r.restoreError("items", err)
+		}
+		if err := r.restoreItemDetails(s, existingID, item); err != nil {
+			return "", err
+		}
+		s.result.ItemsUpdated++
+		return existingID, nil
+	default:
+		s.result.ItemsMatched++
+		return existingID, nil
+	}
+}
+
+// findCatalogItem ищет элемент каталога по ID (с учетом слияний), по внешним ID, затем по точному
+// совпадению нормализованного названия, типа и года. Пустой ID - элемент не найден
+func (r *BackupRepository) findCatalogItem(s *restoreState, item backup.Item) (string, error) {
+	var itemID string
+	if uuidPattern.MatchString(item.ID) {
+		err := s.tx.QueryRow(fmt.Sprintf(`
+			SELECT ci.id FROM %s ci
+			WHERE ci.id = COALESCE((SELECT new_id FROM %s WHERE old_id = $1), $1)
+				AND (ci.is_public = TRUE OR ci.creator_id = $2)
+		`, collectionItemsTable, itemRedirectsTable), item.ID, s.userID).Scan(&itemID)
+		if err == nil {
+			return itemID, nil
+		}
+		if err != sql.ErrNoRows {
+			return "", r.restoreError("items", err)
+		}
+	}
+
+	if len(item.ExternalIDs) > 0 {
+		providers := make([]string, len(item.ExternalIDs))
+		externalIDs := make([]string, len(item.ExternalIDs))
+		for i, externalID := range item.ExternalIDs {
+			providers[i], externalIDs[i] = externalID.Provider, externalID.ExternalID
+		}
+		err := s.tx.QueryRow(fmt.Sprintf(`
+			SELECT e.item_id FROM %s e
+			JOIN %s ci ON ci.id = e.item_id
+			WHERE (e.provider, e.external_id) IN (SELECT * FROM unnest($1::text[], $2::text[]))
+				AND ci.type = $3 AND (ci.is_public = TRUE OR ci.creator_id = $4)
+			ORDER BY e.created_at
+			LIMIT 1
+		`, itemExternalIDsTable, collectionItemsTable), pq.Array(providers), pq.Array(externalIDs), item.Type, s.userID).Scan(&itemID)
+		if err == nil {
+			return itemID, nil
+		}
+		if err != sql.ErrNoRows {
+			return "", r.restoreError("items", err)
+		}
+	}
+
+	err := s.tx.QueryRow(fmt.Sprintf(`
+		SELECT id FROM %s
+		WHERE type = $1 AND is_public = TRUE AND normalized_title = normalize_item_title($2)
+			AND ($3::int IS NULL OR release_year IS NULL OR release_year = $3)
+		ORDER BY is_custom, created_at
+		LIMIT 1
+	`, collectionItemsTable), item.Type, item.Title, item.ReleaseYear).Scan(&itemID)
+	if err != nil && err != sql.ErrNoRows {
+		return "", r.restoreError("items", err)
+	}
+	return itemID, nil
+}
+
+func (r *BackupRepository) createCustomItem(s *restoreState, item backup.Item) (string, error) {
+	var itemID string
+	err := s.tx.QueryRow(fmt.Sprintf(`
+		INSERT INTO %s (type, title, description, cover_image, release_year, is_custom, is_public, creator_id, moderation_status, created_at)
+		VALUES ($1, $2, $3, $4, $5, TRUE, FALSE, $6, $7, COALESCE($8, NOW()))
+		RETURNING id
+	`, collectionItemsTable), item.Type, item.Title, item.Description, item.CoverImage, item.ReleaseYear,
+		s.userID, models.ModerationStatusNone, nullTime(item.CreatedAt)).Scan(&itemID)
+	if err != nil {
+		return "", r.restoreError("items", err)
+	}
+	if err := r.restoreItemDetails(s, itemID, item); err != nil {
+		return "", err
+	}
+	s.result.ItemsCreated++
+	return itemID, nil
+}
+
+// restoreItemDetails добавляет своему элементу жанры, сезоны и эпизоды, которых у него нет
+func (r *BackupRepository) restoreItemDetails(s *restoreState, itemID string, item backup.Item) error {
+	if len(item.Genres) > 0 {
+		_, err := s.tx.Exec(fmt.Sprintf(`
+			INSERT INTO %s (item_id, genre_id)
+			SELECT $1, id FROM %s WHERE slug = ANY($2)
+			ON CONFLICT DO NOTHING
+		`, itemGenresTable, genresTable), itemID, pq.Array(item.Genres))
+		if err != nil {
+			return r.restoreError("genres", err)
+		}
+	}
+
+	for _, season := range item.Seasons {
+		var seasonID string
+		err := s.tx.QueryRow(fmt.Sprintf(`
+			INSERT INTO %s (item_id, number, title) VALUES ($1, $2, $3)
+			ON CONFLICT (item_id, number) DO UPDATE SET title = COALESCE(%[1]s.title, EXCLUDED.title)
+			RETURNING id
+		`, itemSeasonsTable), itemID, season.Number, season.Title).Scan(&seasonID)
+		if err != nil {
+			return r.restoreError("seasons", err)
+		}
+		for _, episode := range season.Episodes {
+			_, err := s.tx.Exec(fmt.Sprintf(`
+				INSERT INTO %s (season_id, number, title, air_date, runtime_minutes) VALUES ($1, $2, $3, $4::date, $5)
+				ON CONFLICT (season_id, number) DO NOTHING
+			`, itemEpisodesTable), seasonID, episode.Number, episode.Title, episode.AirDate, episode.RuntimeMinutes)
+			if err != nil {
+				return r.restoreError("episodes", err)
+			}
+		}
+	}
+	return nil
+}
+
+// restoreCollection создает коллекцию или разрешает совпадение с существующей: по ID,
+// если копия сделана на этом сервере, иначе по названию и типу
+func (r *BackupRepository) restoreCollection(s *restoreState, collection backup.Collection) error {
+	var existingID string
+	if uuidPattern.MatchString(collection.ID) {
+		err := s.tx.QueryRow(fmt.Sprintf(`SELECT id FROM %s WHERE id = $1 AND user_id = $2`, collectionsTable),
+			collection.ID, s.userID).Scan(&existingID)
+		if err != nil && err != sql.ErrNoRows {
+			return r.restoreError("collections", err)
+		}
+	}
+	if existingID == "" {
+		err := s.tx.QueryRow(fmt.Sprintf(`
+			SELECT id FROM %s
+			WHERE user_id = $1 AND lower(name) = lower($2) AND COALESCE(type, '') = $3
+			ORDER BY created_at
+			LIMIT 1
+		`, collectionsTable), s.userID, collection.Name, collection.Type).Scan(&existingID)
+		if err != nil && err != sql.ErrNoRows {
+			return r.restoreError("collections", err)
+		}
+	}
+
+	var collectionID string
+	switch {
+	case existingID != "" && s.mode == models.RestoreModeSkip:
+		s.result.CollectionIDs[collection.ID] = existingID
+		s.result.CollectionsSkipped++
+		return nil
+	case existingID != "" && s.mode == models.RestoreModeOverwrite:
+		collectionID = existingID
+		_, err := s.tx.Exec(fmt.Sprintf(`
+			UPDATE %s SET name = $2, description = $3, type = NULLIF($4, ''), is_public = $5, cover_image = $6
+			WHERE id = $1
+		`, collectionsTable), collectionID, collection.Name, collection.Description, collection.Type,
+			collection.IsPublic, collection.CoverImage)
+		if err != nil {
+			return r.restoreError("collections", err)
+		}
+		// Содержимое и теги заменяются содержимым копии
+		if _, err := s.tx.Exec(fmt.Sprintf(`DELETE FROM %s WHERE collection_id = $1`, collectionItemsAssignmentTable), collectionID); err != nil {
+			return r.restoreError("collection items", err)
+		}
+		if _, err := s.tx.Exec(fmt.Sprintf(`DELETE FROM %s WHERE collection_id = $1`, collectionTagsTable), collectionID); err != nil {
+			return r.restoreError("collection tags", err)
+		}
+		s.result.CollectionsUpdated++
+	default:
+		err := s.tx.QueryRow(fmt.Sprintf(`
+			INSERT INTO %s (user_id, name, description, type, is_public, cover_image, created_at)
+			VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, COALESCE($7, NOW()))
+			RETURNING id
+		`, collectionsTable), s.userID, collection.Name, collection.Description, collection.Type,
+			collection.IsPublic, collection.CoverImage, nullTime(collection.CreatedAt)).Scan(&collectionID)
+		if err != nil {
+			return r.restoreError("collections", err)
+		}
+		s.result.CollectionsCreated++
+	}
+	s.result.CollectionIDs[collection.ID] = collectionID
+
+	// Порядок элементов в коллекции определяется временем добавления, поэтому оно переносится как есть
+	for _, assignment := range collection.Items {
+		res, err := s.tx.Exec(fmt.Sprintf(`
+			INSERT INTO %s (collection_id, item_id, user_review, added_at) VALUES ($1, $2, $3, COALESCE($4, NOW()))
+			ON CONFLICT (collection_id, item_id) DO NOTHING
+		`, collectionItemsAssignmentTable), collectionID, s.result.ItemIDs[assignment.ItemID], assignment.Review,
+			nullTime(assignment.AddedAt))
+		if err != nil {
+			return r.restoreError("collection items", err)
+		}
+		if affected, _ := res.RowsAffected(); affected > 0 {
+			s.result.AssignmentsRestored++
+		}
+	}
+
+	for _, tag := range collection.Tags {
+		tagID, err := s.tag(tag)
+		if err != nil {
+			return err
+		}
+		_, err = s.tx.Exec(fmt.Sprintf(`
+			INSERT INTO %s (collection_id, tag_id) VALUES ($1, $2) ON CONFLICT DO NOTHING
+		`, collectionTagsTable), collectionID, tagID)
+		if err != nil {
+			return r.restoreError("collection tags", err)
+		}
+	}
+	return nil
+}
+
+// restoreProgress переносит статус, оценку и даты. Существующий прогресс заменяется только
+// в режиме overwrite, отметки эпизодов всегда добавляются к уже сделанным
+func (r *BackupRepository) restoreProgress(s *restoreState, progress backup.Progress) error {
+	itemID := s.result.ItemIDs[progress.ItemID]
+
+	conflict := "DO NOTHING"
+	if s.mode == models.RestoreModeOverwrite {
+		conflict = `DO UPDATE SET status = EXCLUDED.status, progress = EXCLUDED.progress, rating = EXCLUDED.rating,
+			started_at = EXCLUDED.started_at, finished_at = EXCLUDED.finished_at, updated_at = EXCLUDED.updated_at`
+	}
+	res, err := s.tx.Exec(fmt.Sprintf(`
+		INSERT INTO %s (user_id, item_id, status, progress, rating, started_at, finished_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, COALESCE($8, NOW()))
+		ON CONFLICT (user_id, item_id) %s
+	`, userItemProgressTable, conflict), s.userID, itemID, progress.Status, progress.Progress, progress.Rating,
+		progress.StartedAt, progress.FinishedAt, progress.UpdatedAt)
+	if err != nil {
+		return r.restoreError("progress", err)
+	}
+	if affected, _ := res.RowsAffected(); affected > 0 {
+		s.result.ProgressRestored++
+	} else {
+		s.result.ProgressSkipped++
+	}
+
+	for _, watched := range progress.Watched {
+		_, err := s.tx.Exec(fmt.Sprintf(`
+			INSERT INTO %s (user_id, episode_id, watched_at)
+			SELECT $1, e.id, $5
+			FROM %s e
+			JOIN %s s ON s.id = e.season_id
+			WHERE s.item_id = $2 AND s.number = $3 AND e.number = $4
+			ON CONFLICT DO NOTHING
+		`, userWatchedEpisodesTable, itemEpisodesTable, itemSeasonsTable), s.userID, itemID, watched.Season,
+			watched.Episode, watched.WatchedAt)
+		if err != nil {
+			return r.restoreError("watched episodes", err)
+		}
+	}
+	return nil
+}
+
+// tag - ID тега пользователя, тег создается при первом упоминании
+func (s *restoreState) tag(name string) (int, error) {
+	name = strings.TrimSpace(name)
+	key := strings.ToLower(name)
+	if id, ok := s.tags[key]; ok {
+		return id, nil
+	}
+	id, err := upsertTag(s.tx, s.userID, name)
+	if err != nil {
+		return 0, fmt.Errorf("failed to restore tag %q: %w", name, err)
+	}
+	s.tags[key] = id
+	return id, nil
+}
+
+func (r *BackupRepository) restoreError(step string, err error) error {
+	r.logger.Errorf("Failed to restore %s: %v", step, err)
+	return fmt.Errorf("failed to restore %s: %w", step, err)
+}
+
+// nullTime - NULL вместо нулевого времени, чтобы база подставила текущее
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
+	"go.uber.org/zap"
+)
+
+// chartsLockKey - ключ advisory-блокировки пересчета чартов
+const chartsLockKey = 50001
+
+type ChartRepository struct {
+	db     *sql.DB
+	logger *zap.SugaredLogger
+}
+
+func NewChartPostgres(db *sql.DB, logger *zap.SugaredLogger) *ChartRepository {
+	return &ChartRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// RecomputeCharts пересчитывает все чарты в одной транзакции, читатели видят старые чарты до ее завершения.
+// Добавления считаются по пользователям: элемент в нескольких коллекциях одного пользователя - одно добавление.
+// Байесовское среднее: (n*avg + m*C) / (n + m), где C - средняя оценка по каталогу, m - PriorVotes.
+// Возвращает число сохраненных мест и false, если пересчет уже идет в другом экземпляре приложения
+func (r *ChartRepository) RecomputeCharts(params models.ChartParams) (int64, bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	if err := tx.QueryRow(`SELECT pg_try_advisory_xact_lock($1)`, chartsLockKey).Scan(&locked); err != nil {
+		return 0, false, fmt.Errorf("failed to lock charts: %w", err)
+	}
+	if !locked {
+		return 0, false, nil
+	}
+
+	if _, err := tx.Exec(fmt.Sprintf(`DELETE FROM %s`, itemChartsTable)); err != nil {
+		return 0, false, fmt.Errorf("failed to clear charts: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		WITH collected AS (
+			SELECT cia.item_id, c.user_id, MIN(cia.added_at)::timestamptz AS added_at
+			FROM %[1]s cia
+			JOIN %[2]s c ON c.id = cia.collection_id
+			GROUP BY cia.item_id, c.user_id
+		),
+		adds AS (
+			SELECT item_id, COUNT(*) AS collectors,
+			       COUNT(*) FILTER (WHERE added_at >= CURRENT_TIMESTAMP - $1::float8 * interval '1 second') AS recent_adds,
+			       COALESCE(SUM(power(0.5::float8, extract(epoch FROM CURRENT_TIMESTAMP - added_at)::float8 / $2::float8))
+			                FILTER (WHERE added_at >= CURRENT_TIMESTAMP - $1::float8 * interval '1 second'), 0) AS trending
+			FROM collected
+			GROUP BY item_id
+		),
+		ratings AS (
+			SELECT p.item_id, COUNT(*) AS ratings_count, AVG(p.rating)::float8 AS avg_rating
+			FROM %[3]s p
+			JOIN %[4]s ci ON ci.id = p.item_id AND ci.is_public = TRUE
+			WHERE p.rating IS NOT NULL
+			GROUP BY p.item_id
+		),
+		prior AS (
+			SELECT COALESCE(SUM(avg_rating * ratings_count) / NULLIF(SUM(ratings_count), 0), 0) AS mean FROM ratings
+		),
+		stats AS (
+			SELECT ci.id AS item_id, ci.type AS item_type,
+			       COALESCE(a.collectors, 0) AS collectors,
+			       COALESCE(a.recent_adds, 0) AS recent_adds,
+			       COALESCE(a.trending, 0) AS trending,
+			       COALESCE(rt.ratings_count, 0) AS ratings_count,
+			       rt.avg_rating,
+			       (rt.ratings_count * rt.avg_rating + $3::float8 * prior.mean) / (rt.ratings_count + $3::float8) AS bayesian
+			FROM %[4]s ci
+			LEFT JOIN adds a ON a.item_id = ci.id
+			LEFT JOIN ratings rt ON rt.item_id = ci.id
+			CROSS JOIN prior
+			WHERE ci.is_public = TRUE AND (a.item_id IS NOT NULL OR rt.item_id IS NOT NULL)
+		),
+		scored AS (
+			SELECT s.item_id, s.item_type, s.collectors, s.recent_adds, s.ratings_count, s.avg_rating, charts.chart, charts.score
+			FROM stats s
+			CROSS JOIN LATERAL (VALUES
+				('%[7]s', s.trending),
+				('%[8]s', s.collectors::float8),
+				('%[9]s', s.bayesian)
+			) AS charts(chart, score)
+			WHERE charts.score > 0
+		),
+		scoped AS (
+			SELECT sc.*, '' AS scope_type, '' AS scope_genre FROM scored sc
+			UNION ALL
+			SELECT sc.*, sc.item_type, '' FROM scored sc
+			UNION ALL
+			SELECT sc.*, '', g.slug
+			FROM scored sc JOIN %[5]s ig ON ig.item_id = sc.item_id JOIN %[6]s g ON g.id = ig.genre_id
+			UNION ALL
+			SELECT sc.*, sc.item_type, g.slug
+			FROM scored sc JOIN %[5]s ig ON ig.item_id = sc.item_id JOIN %[6]s g ON g.id = ig.genre_id
+		),
+		ranked AS (
+			SELECT *, row_number() OVER (
+				PARTITION BY chart, scope_type, scope_genre
+				ORDER BY score DESC, collectors DESC, item_id
+			) AS rn
+			FROM scoped
+		)
+		INSERT INTO %[10]s (chart, item_type, genre, position, item_id, score, collectors, recent_adds, ratings_count, avg_rating, computed_at)
+		SELECT chart, scope_type, scope_genre, rn, item_id, score, collectors, recent_adds, ratings_count, avg_rating, CURRENT_TIMESTAMP
+		FROM ranked
+		WHERE rn <= $4
+	`, collectionItemsAssignmentTable, collectionsTable, userItemProgressTable, collectionItemsTable,
+		itemGenresTable, genresTable, models.ChartTrending, models.ChartMostCollected, models.ChartTopRated, itemChartsTable)
+
+	res, err := tx.Exec(query, params.TrendingWindow.Seconds(), params.TrendingHalfLife.Seconds(), params.PriorVotes, params.Size)
+	if err != nil {
+		r.logger.Errorf("Failed to compute charts: %v", err)
+		return 0, false, fmt.Errorf("failed to compute charts: %w", err)
+	}
+	entries, _ := res.RowsAffected()
+
+	if err := tx.Commit(); err != nil {
+		return 0, false, fmt.Errorf("failed to commit charts: %w", err)
+	}
+	return entries, true, nil
+}
+
+// GetChartsComputedAt - время последнего пересчета, nil если чарты еще не считались
+func (r *ChartRepository) GetChartsComputedAt() (*time.Time, error) {
+	var computedAt *time.Time
+	query := fmt.Sprintf(`SELECT MAX(computed_at) FROM %s`, itemChartsTable)
+	if err := r.db.QueryRow(query).Scan(&computedAt); err != nil {
+		return nil, fmt.Errorf("failed to get charts time: %w", err)
+	}
+	return computedAt, nil
+}
+
+// GetChart - страница чарта. Элементы, скрытые после пересчета, пропускаются
+func (r *ChartRepository) GetChart(query models.ChartQuery, req pagination.PaginationRequest) (*models.Chart, error) {
+	f := &queryFilter{}
+	f.where("ch.chart = " + f.arg(query.Chart))
+	f.where("ch.item_type = " + f.arg(query.Type))
+	f.where("ch.genre = " + f.arg(query.Genre))
+	f.where("ci.is_public = TRUE")
+
+	countQuery := fmt.Sprintf(`
+		SELECT COUNT(*), MAX(ch.computed_at)
+		FROM %s ch
+		JOIN %s ci ON ci.id = ch.item_id
+		%s
+	`, itemChartsTable, collectionItemsTable, f.sql())
+
+	chart := &models.Chart{}
+	var total int64
+	if err := r.db.QueryRow(countQuery, f.args...).Scan(&total, &chart.ComputedAt); err != nil {
+		r.logger.Errorf("Failed to count chart %s: %v", query.Chart, err)
+		return nil, fmt.Errorf("failed to count chart: %w", err)
+	}
+
+	entriesQuery := fmt.Sprintf(`
+		SELECT %[1]s, ch.position, ch.score, ch.collectors, ch.recent_adds, ch.ratings_count, ch.avg_rating
+		FROM %[2]s ch
+		JOIN %[3]s ci ON ci.id = ch.item_id
+		%[4]s
+		ORDER BY ch.position
+		LIMIT %[5]s OFFSET %[6]s
+	`, collectionItemColumns, itemChartsTable, collectionItemsTable, f.sql(), f.arg(req.Limit()), f.arg(req.Offset()))
+
+	rows, err := r.db.Query(entriesQuery, f.args...)
+	if err != nil {
+		r.logger.Errorf("Failed to get chart %s: %v", query.Chart, err)
+		return nil, fmt.Errorf("failed to get chart: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []models.ChartEntry{}
+	for rows.Next() {
+		var entry models.ChartEntry
+		err := scanCollectionItem(rows, &entry.Item,
+			&entry.Position, &entry.Score, &entry.Collectors, &entry.RecentAdds, &entry.RatingsCount, &entry.AvgRating)
+		if err != nil {
+			r.logger.Errorf("Scan failed: %v", err)
+			return nil, fmt.Errorf("failed to scan chart entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	chart.Data = entries
+	chart.Pagination = req.ToPagination(total)
+	return chart, nil
+}
@@ -3,12 +3,18 @@ package repository
 import (
 	"database/sql"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
 	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
 	"go.uber.org/zap"
 )
 
+// itemStatsLockKey - ключ advisory-блокировки пересчета статистики элементов
+const itemStatsLockKey = 31001
+
 type CollectionItemRepository struct {
 	logger *zap.SugaredLogger
 	db     *sql.DB
@@ -22,51 +28,107 @@ func NewCollectionItemPostgres(db *sql.DB, logger *zap.SugaredLogger) *Collectio
 }
 
 // FOR items ribbon in public view
-func (r *CollectionItemRepository) GetAllItemsWithCurrentTypePaginated(currentType string, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.CollectionItem], error) {
+func (r *CollectionItemRepository) GetAllItemsWithCurrentTypePaginated(filter models.ItemFilter, req pagination.PaginationRequest) (*models.ItemFeed, error) {
+	f := feedFilter(filter, "")
 
-	// Сначала получаем общее количество коллекций пользователя
+	// Сначала получаем общее количество элементов
 	countQuery := fmt.Sprintf(`
         SELECT COUNT(*) 
-        FROM %s 
-        WHERE type = $1
-    `, collectionItemsTable)
+        FROM %s ci
+        LEFT JOIN %s st ON st.item_id = ci.id
+        %s
+    `, collectionItemsTable, itemStatsTable, f.sql())
 
 	var total int64
-	err := r.db.QueryRow(countQuery, currentType).Scan(&total)
+	err := r.db.QueryRow(countQuery, f.args...).Scan(&total)
 	if err != nil {
-		r.logger.Errorf("Failed to count collection_items with type %d: %v", currentType, err)
+		r.logger.Errorf("Failed to count collection_items with type %s: %v", filter.Type, err)
 		return nil, fmt.Errorf("failed to count collection_items: %w", err)
 	}
 
-	// Order by ASC - по возрастанию(сначала is_custom = false) для ленты
 	query := fmt.Sprintf(`
-		SELECT id, type, title, description, cover_image, is_public, is_custom, created_at, updated_at 
-		FROM %s 
-		WHERE type = $1 AND is_public = TRUE ORDER BY is_custom ASC
-		LIMIT $2 OFFSET $3`, collectionItemsTable)
+		SELECT %s, COALESCE(st.popularity, 0), st.avg_rating, COALESCE(st.ratings_count, 0)
+		FROM %s ci
+		LEFT JOIN %s st ON st.item_id = ci.id
+		%s
+		ORDER BY %s
+		LIMIT %s OFFSET %s`, collectionItemColumns, collectionItemsTable, itemStatsTable, f.sql(),
+		feedSorts[filter.Sort].orderBy(false), f.arg(req.Limit()), f.arg(req.Offset()))
+
+	items, err := r.queryFeedItems(query, f.args)
+	if err != nil {
+		return nil, err
+	}
+
+	facets, err := r.getFeedFacets(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ItemFeed{
+		PaginatedResponse: pagination.PaginatedResponse[models.FeedItem]{
+			Data:       items,
+			Pagination: req.ToPagination(total),
+		},
+		Facets: *facets,
+	}, nil
+}
+
+// GetItemsFeedByCursor - лента в keyset-режиме: без OFFSET и фасетов, общее кол-во считается по запросу
+func (r *CollectionItemRepository) GetItemsFeedByCursor(filter models.ItemFilter, req pagination.CursorRequest) (*pagination.CursorResponse[models.FeedItem], error) {
+	var total *int64
+	if req.WithTotal() {
+		f := feedFilter(filter, "")
+		countQuery := fmt.Sprintf(`
+			SELECT COUNT(*)
+			FROM %s ci
+			LEFT JOIN %s st ON st.item_id = ci.id
+			%s
+		`, collectionItemsTable, itemStatsTable, f.sql())
+
+		var count int64
+		if err := r.db.QueryRow(countQuery, f.args...).Scan(&count); err != nil {
+			r.logger.Errorf("Failed to count collection_items with type %s: %v", filter.Type, err)
+			return nil, fmt.Errorf("failed to count collection_items: %w", err)
+		}
+		total = &count
+	}
+
+	sort := feedSorts[filter.Sort]
+	f := feedFilter(filter, "")
+	orderBy, err := sort.apply(f, req)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s, COALESCE(st.popularity, 0), st.avg_rating, COALESCE(st.ratings_count, 0)
+		FROM %s ci
+		LEFT JOIN %s st ON st.item_id = ci.id
+		%s
+		ORDER BY %s
+		LIMIT %s`, collectionItemColumns, collectionItemsTable, itemStatsTable, f.sql(), orderBy, f.arg(req.FetchLimit()))
+
+	items, err := r.queryFeedItems(query, f.args)
+	if err != nil {
+		return nil, err
+	}
+
+	return pagination.NewCursorResponse(items, req, sort.scope, sort.keys, total), nil
+}
 
-	rows, err := r.db.Query(query, currentType, req.Limit(), req.Offset())
+func (r *CollectionItemRepository) queryFeedItems(query string, args []any) ([]models.FeedItem, error) {
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		r.logger.Errorf("Error executing query: %v", err)
 		return nil, fmt.Errorf("failed to get collection items: %w", err)
 	}
 	defer rows.Close()
 
-	var items []models.CollectionItem
-
+	items := []models.FeedItem{}
 	for rows.Next() {
-		var item models.CollectionItem
-		err := rows.Scan(
-			&item.ID,
-			&item.Type,
-			&item.Title,
-			&item.Description,
-			&item.CoverImage,
-			&item.IsPublic,
-			&item.IsCustom,
-			&item.CreatedAt,
-			&item.UpdatedAt,
-		)
+		var item models.FeedItem
+		err := scanCollectionItem(rows, &item.CollectionItem, &item.Popularity, &item.AvgRating, &item.RatingsCount)
 		if err != nil {
 			r.logger.Errorf("Error scanning row: %v", err)
 			return nil, fmt.Errorf("failed to scan collection item: %w", err)
@@ -77,108 +139,282 @@ func (r *CollectionItemRepository) GetAllItemsWithCurrentTypePaginated(currentTy
 		r.logger.Errorf("Error iterating rows: %v", err)
 		return nil, fmt.Errorf("error during rows iteration: %w", err)
 	}
+	return items, nil
+}
 
-	return &pagination.PaginatedResponse[models.CollectionItem]{
-		Data:       items,
-		Pagination: req.ToPagination(total),
-	}, nil
+// getFeedFacets считает фасеты ленты. Для каждого фасета его собственный фильтр не применяется,
+// чтобы клиент видел, сколько элементов даст выбор другого значения
+func (r *CollectionItemRepository) getFeedFacets(filter models.ItemFilter) (*models.ItemFacets, error) {
+	var facets models.ItemFacets
+	var err error
+
+	f := feedFilter(filter, "type")
+	facets.Types, err = r.queryFacet(fmt.Sprintf(`
+		SELECT ci.type, '', COUNT(*)
+		FROM %s ci
+		LEFT JOIN %s st ON st.item_id = ci.id
+		%s
+		GROUP BY ci.type
+		ORDER BY COUNT(*) DESC, ci.type
+	`, collectionItemsTable, itemStatsTable, f.sql()), f.args)
+	if err != nil {
+		return nil, err
+	}
+
+	f = feedFilter(filter, "genres")
+	facets.Genres, err = r.queryFacet(fmt.Sprintf(`
+		SELECT g.slug, g.name, COUNT(*)
+		FROM %s ci
+		LEFT JOIN %s st ON st.item_id = ci.id
+		JOIN %s ig ON ig.item_id = ci.id
+		JOIN %s g ON g.id = ig.genre_id
+		%s
+		GROUP BY g.slug, g.name
+		ORDER BY COUNT(*) DESC, g.name
+	`, collectionItemsTable, itemStatsTable, itemGenresTable, genresTable, f.sql()), f.args)
+	if err != nil {
+		return nil, err
+	}
+
+	f = feedFilter(filter, "source")
+	facets.Sources, err = r.queryFacet(fmt.Sprintf(`
+		SELECT CASE WHEN ci.is_custom THEN '%s' ELSE '%s' END AS source, '', COUNT(*)
+		FROM %s ci
+		LEFT JOIN %s st ON st.item_id = ci.id
+		%s
+		GROUP BY source
+		ORDER BY COUNT(*) DESC
+	`, models.ItemSourceCustom, models.ItemSourceCatalog, collectionItemsTable, itemStatsTable, f.sql()), f.args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &facets, nil
+}
+
+func (r *CollectionItemRepository) queryFacet(query string, args []any) ([]models.FacetValue, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		r.logger.Errorf("Failed to count facet: %v", err)
+		return nil, fmt.Errorf("failed to count facet: %w", err)
+	}
+	defer rows.Close()
+
+	values := []models.FacetValue{}
+	for rows.Next() {
+		var value models.FacetValue
+		if err := rows.Scan(&value.Value, &value.Label, &value.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan facet: %w", err)
+		}
+		values = append(values, value)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+	return values, nil
+}
+
+// feedFilter строит условия ленты. skip - фасет, собственный фильтр которого не применяется
+func feedFilter(filter models.ItemFilter, skip string) *queryFilter {
+	f := &queryFilter{}
+	f.where("ci.is_public = TRUE")
+	if filter.Type != "" && skip != "type" {
+		f.where("ci.type = " + f.arg(filter.Type))
+	}
+	if len(filter.Genres) > 0 && skip != "genres" {
+		f.where(itemGenresCondition(f, "ci.id", filter.Genres, filter.GenresMatchAll))
+	}
+	if filter.Source != "" && skip != "source" {
+		f.where("ci.is_custom = " + f.arg(filter.Source == models.ItemSourceCustom))
+	}
+	if !filter.Tags.IsEmpty() {
+		f.where(itemTagsCondition(f, "ci.id", filter.Tags))
+	}
+	if filter.YearFrom != nil {
+		f.where("ci.release_year >= " + f.arg(*filter.YearFrom))
+	}
+	if filter.YearTo != nil {
+		f.where("ci.release_year <= " + f.arg(*filter.YearTo))
+	}
+	if filter.RatingMin != nil {
+		f.where("st.avg_rating >= " + f.arg(*filter.RatingMin))
+	}
+	if filter.RatingMax != nil {
+		f.where("st.avg_rating <= " + f.arg(*filter.RatingMax))
+	}
+	if filter.NotInLibrary {
+		f.where(fmt.Sprintf(`NOT EXISTS (
+			SELECT 1 FROM %s cia JOIN %s c ON c.id = cia.collection_id
+			WHERE cia.item_id = ci.id AND c.user_id = %s
+		)`, collectionItemsAssignmentTable, collectionsTable, f.arg(filter.UserID)))
+	}
+	return f
+}
+
+// feedSort - сортировка ленты: keyset для SQL и значения ключа элемента для курсора.
+// Все колонки сортируются в одном направлении, ci.id в конце делает порядок стабильным
+type feedSort struct {
+	keyset
+	keys func(item models.FeedItem) []string
+}
+
+var feedSorts = map[string]feedSort{
+	// Сначала элементы каталога (is_custom = false), затем пользовательские
+	models.FeedSortDefault: {
+		keyset: keyset{scope: "items", columns: []keysetColumn{
+			{expr: "ci.is_custom", cast: "boolean"},
+			{expr: "ci.title", cast: "text"},
+			{expr: "ci.id", cast: "uuid"},
+		}},
+		keys: func(item models.FeedItem) []string {
+			return []string{strconv.FormatBool(item.IsCustom), item.Title, item.ID}
+		},
+	},
+	models.FeedSortTitle: {
+		keyset: keyset{scope: "items:title", columns: []keysetColumn{
+			{expr: "ci.title", cast: "text"},
+			{expr: "ci.id", cast: "uuid"},
+		}},
+		keys: func(item models.FeedItem) []string {
+			return []string{item.Title, item.ID}
+		},
+	},
+	models.FeedSortNewest: {
+		keyset: keyset{scope: "items:newest", desc: true, columns: []keysetColumn{
+			{expr: "COALESCE(ci.release_year, 0)", cast: "int"},
+			{expr: "ci.created_at", cast: "timestamptz"},
+			{expr: "ci.id", cast: "uuid"},
+		}},
+		keys: func(item models.FeedItem) []string {
+			year := 0
+			if item.ReleaseYear != nil {
+				year = *item.ReleaseYear
+			}
+			return []string{strconv.Itoa(year), item.CreatedAt.Format(time.RFC3339Nano), item.ID}
+		},
+	},
+	models.FeedSortPopular: {
+		keyset: keyset{scope: "items:popular", desc: true, columns: []keysetColumn{
+			{expr: "COALESCE(st.popularity, 0)", cast: "bigint"},
+			{expr: "ci.id", cast: "uuid"},
+		}},
+		keys: func(item models.FeedItem) []string {
+			return []string{strconv.Itoa(item.Popularity), item.ID}
+		},
+	},
+	models.FeedSortTopRated: {
+		keyset: keyset{scope: "items:top_rated", desc: true, columns: []keysetColumn{
+			{expr: "COALESCE(st.avg_rating, 0)", cast: "float8"},
+			{expr: "COALESCE(st.ratings_count, 0)", cast: "bigint"},
+			{expr: "ci.id", cast: "uuid"},
+		}},
+		keys: func(item models.FeedItem) []string {
+			rating := 0.0
+			if item.AvgRating != nil {
+				rating = *item.AvgRating
+			}
+			return []string{strconv.FormatFloat(rating, 'g', -1, 64), strconv.Itoa(item.RatingsCount), item.ID}
+		},
+	},
 }
 
-// For private Collection
-func (r *CollectionItemRepository) GetItemsByCollection(collection_id string) ([]models.CollectionItem, error) {
+// GetItemsByCollection возвращает элементы коллекции с датой добавления и заметкой владельца,
+// сначала добавленные последними. limit 0 - без ограничения
+func (r *CollectionItemRepository) GetItemsByCollection(collection_id string, tags models.TagFilter, limit int) ([]models.CollectionEntry, error) {
+	f := &queryFilter{}
+	f.where("cia.collection_id = " + f.arg(collection_id))
+	if !tags.IsEmpty() {
+		f.where(itemTagsCondition(f, "ci.id", tags))
+	}
+	limitSQL := ""
+	if limit > 0 {
+		limitSQL = "LIMIT " + f.arg(limit)
+	}
+
 	query := fmt.Sprintf(`
-	SELECT ci.id, ci.type, ci.title, ci.description, ci.cover_image, ci.is_public, is_custom, ci.created_at, ci.updated_at 
+	SELECT %s, COALESCE(cia.added_at, ci.created_at), cia.user_review
 	FROM %s ci
 	JOIN %s cia ON ci.id = cia.item_id
-	WHERE cia.collection_id = $1
+	%s
 	ORDER BY cia.added_at DESC
-	`, collectionItemsTable, collectionItemsAssignmentTable)
+	%s
+	`, collectionItemColumns, collectionItemsTable, collectionItemsAssignmentTable, f.sql(), limitSQL)
 
-	var items []models.CollectionItem
-	rows, err := r.db.Query(query, collection_id)
+	var entries []models.CollectionEntry
+	rows, err := r.db.Query(query, f.args...)
 	if err != nil {
 		r.logger.Errorf("Error executing query: %v", err)
 		return nil, fmt.Errorf("failed to get collection items: %w", err)
 	}
+	defer rows.Close()
 
 	for rows.Next() {
-		var item models.CollectionItem
-		rows.Scan(
-			&item.ID,
-			&item.Type,
-			&item.Title,
-			&item.Description,
-			&item.CoverImage,
-			&item.IsPublic,
-			&item.IsCustom,
-			&item.CreatedAt,
-			&item.UpdatedAt,
-		)
+		var entry models.CollectionEntry
+		err := scanCollectionItem(rows, &entry.CollectionItem, &entry.AddedAt, &entry.Review)
 		if err != nil {
 			r.logger.Errorf("Error scanning row: %v", err)
 			return nil, fmt.Errorf("failed to scan collection item: %w", err)
 		}
-		items = append(items, item)
+		entries = append(entries, entry)
 	}
 	if err = rows.Err(); err != nil {
 		r.logger.Errorf("Error iterating rows: %v", err)
 		return nil, fmt.Errorf("error during rows iteration: %w", err)
 	}
 
-	return items, nil
+	return entries, nil
 }
 
 func (r *CollectionItemRepository) GetItemByID(id string) (*models.CollectionItem, error) {
-	query := fmt.Sprintf("SELECT id, type, title, description, cover_image, is_custom, created_at, updated_at FROM %s WHERE id = $1", collectionItemsTable)
+	query := fmt.Sprintf("SELECT %s FROM %s ci WHERE ci.id = $1", collectionItemColumns, collectionItemsTable)
 
 	var collectionItem models.CollectionItem
-	err := r.db.QueryRow(
-		query,
-		id,
-	).Scan(
-		&collectionItem.ID,
-		&collectionItem.Type,
-		&collectionItem.Title,
-		&collectionItem.Description,
-		&collectionItem.CoverImage,
-		&collectionItem.IsCustom,
-		&collectionItem.CreatedAt,
-		&collectionItem.UpdatedAt,
-	)
+	err := scanCollectionItem(r.db.QueryRow(query, id), &collectionItem)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
 		r.logger.Error("Failed to execute collectionItem: " + err.Error())
+		return nil, err
 	}
 	return &collectionItem, nil
 }
 
 func (r *CollectionItemRepository) CreateItem(collectionItem *models.CollectionItem) (string, error) {
+	id, err := insertCollectionItem(r.db, collectionItem)
+	if err != nil {
+		r.logger.Errorf("Error creating item collection: ", err.Error())
+		return "", err
+	}
+
+	return id, nil
+
+}
+
+// insertCollectionItem создает элемент, пустой статус модерации сохраняется как none
+func insertCollectionItem(q queryRower, collectionItem *models.CollectionItem) (string, error) {
 	query := fmt.Sprintf(
-		`INSERT INTO %s (type, title, description, cover_image, is_custom, is_public, creator_id) 
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`INSERT INTO %s (type, title, description, cover_image, release_year, is_custom, is_public, creator_id, moderation_status) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, COALESCE(NULLIF($9, ''), 'none'))
 		RETURNING id
 		`, collectionItemsTable)
 
 	var id string
-	err := r.db.QueryRow(
+	err := q.QueryRow(
 		query,
 		&collectionItem.Type,
 		&collectionItem.Title,
 		&collectionItem.Description,
 		&collectionItem.CoverImage,
+		&collectionItem.ReleaseYear,
 		&collectionItem.IsCustom,
 		&collectionItem.IsPublic,
 		&collectionItem.CreatorID,
+		&collectionItem.ModerationStatus,
 	).Scan(
 		&id,
 	)
-
-	if err != nil {
-		r.logger.Errorf("Error creating item collection: ", err.Error())
-		return "", err
-	}
-
-	return id, nil
-
+	return id, err
 }
 
 func (r *CollectionItemRepository) DeleteCollectionItem(id string) error {
@@ -196,9 +432,10 @@ func (r *CollectionItemRepository) UpdateCollectionItem(item *models.CollectionI
             title = $2, 
             description = $3, 
             cover_image = $4, 
-            is_custom = $5, 
+            release_year = $5, 
+            is_custom = $6, 
             updated_at = NOW()
-        WHERE id = $6
+        WHERE id = $7
     `, collectionItemsTable)
 
 	result, err := r.db.Exec(
@@ -207,6 +444,7 @@ func (r *CollectionItemRepository) UpdateCollectionItem(item *models.CollectionI
 		item.Title,
 		item.Description,
 		item.CoverImage,
+		item.ReleaseYear,
 		item.IsCustom,
 		item.ID,
 	)
@@ -224,14 +462,198 @@ func (r *CollectionItemRepository) UpdateCollectionItem(item *models.CollectionI
 	return nil
 }
 
+// AddItemToCollection добавляет элемент в коллекцию, пишет событие в журнал активности владельца
+// и ставит в очередь его вебхуки item.added и, если есть отзыв, review.posted
 func (r *CollectionItemRepository) AddItemToCollection(collection_id string, item_id string, user_review string) (int, error) {
-	query := fmt.Sprintf(`INSERT INTO %s (collection_id, item_id, user_review) VALUES ($1, $2, $3) RETURNING id`, collectionItemsAssignmentTable)
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (collection_id, item_id, user_review) VALUES ($1, $2, $3)
+		RETURNING id, (SELECT user_id FROM %s WHERE id = $1)
+	`, collectionItemsAssignmentTable, collectionsTable)
 
-	var id int
-	err := r.db.QueryRow(query, collection_id, item_id, user_review).Scan(&id)
+	var id, ownerID int
+	err = tx.QueryRow(query, collection_id, item_id, user_review).Scan(&id, &ownerID)
 	if err != nil {
 		return 0, err
 	}
+	if err := insertActivity(tx, ownerID, models.ActivityItemAdded, item_id, collection_id, nil); err != nil {
+		return 0, err
+	}
 
+	var review *string
+	if strings.TrimSpace(user_review) != "" {
+		review = &user_review
+	}
+	err = enqueueWebhookEvent(tx, ownerID, models.WebhookEventItemAdded, func() (any, error) {
+		item, collection, err := webhookItemInCollection(tx, item_id, collection_id)
+		return models.WebhookItemAddedPayload{Collection: collection, Item: item, UserReview: review}, err
+	})
+	if err != nil {
+		return 0, err
+	}
+	if review != nil {
+		err = enqueueWebhookEvent(tx, ownerID, models.WebhookEventReviewPosted, func() (any, error) {
+			item, collection, err := webhookItemInCollection(tx, item_id, collection_id)
+			return models.WebhookReviewPayload{Item: item, Collection: &collection, Review: review}, err
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
 	return id, nil
 }
+
+// SuggestItems - автодополнение по префиксу и триграммной похожести названия.
+// Итоговый score учитывает совпадение префикса, похожесть и популярность (кол-во коллекций с элементом).
+func (r *CollectionItemRepository) SuggestItems(query string, itemType string, userID int, limit int) ([]models.ItemSuggestion, error) {
+	f := &queryFilter{}
+	q := f.arg(query)
+	prefix := f.arg(escapeLike(query) + "%")
+	f.where(fmt.Sprintf("(lower(ci.title) LIKE %s OR lower(ci.title) %% %s)", prefix, q))
+	f.where(fmt.Sprintf("(ci.is_public = TRUE OR ci.creator_id = %s)", f.arg(userID)))
+	if itemType != "" {
+		f.where("ci.type = " + f.arg(itemType))
+	}
+
+	suggestQuery := fmt.Sprintf(`
+		SELECT id, type, title, cover_image, is_custom, popularity,
+		       (CASE WHEN lower(title) LIKE %[1]s THEN 1.0 ELSE 0.0 END)
+		       + similarity(lower(title), %[2]s)
+		       + 0.1 * ln(1 + popularity) AS score
+		FROM (
+			SELECT ci.id, ci.type, ci.title, ci.cover_image, ci.is_custom,
+			       (SELECT COUNT(*) FROM %[3]s cia WHERE cia.item_id = ci.id) AS popularity
+			FROM %[4]s ci
+			%[5]s
+		) candidates
+		ORDER BY score DESC, title ASC
+		LIMIT %[6]s
+	`, prefix, q, collectionItemsAssignmentTable, collectionItemsTable, f.sql(), f.arg(limit))
+
+	rows, err := r.db.Query(suggestQuery, f.args...)
+	if err != nil {
+		r.logger.Errorf("Failed to suggest items for %q: %v", query, err)
+		return nil, fmt.Errorf("failed to suggest items: %w", err)
+	}
+	defer rows.Close()
+
+	suggestions := []models.ItemSuggestion{}
+	for rows.Next() {
+		var suggestion models.ItemSuggestion
+		err := rows.Scan(
+			&suggestion.ID,
+			&suggestion.Type,
+			&suggestion.Title,
+			&suggestion.CoverImage,
+			&suggestion.IsCustom,
+			&suggestion.Popularity,
+			&suggestion.Score,
+		)
+		if err != nil {
+			r.logger.Errorf("Scan failed: %v", err)
+			return nil, fmt.Errorf("failed to scan suggestion: %w", err)
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return suggestions, nil
+}
+
+// collectionItemColumns - список колонок элемента для scanCollectionItem (с алиасом ci)
+const collectionItemColumns = "ci.id, ci.type, ci.title, COALESCE(ci.description, ''), ci.cover_image, ci.release_year, ci.is_public, ci.is_custom, ci.creator_id, ci.moderation_status, ci.created_at, ci.updated_at"
+
+// rowScanner - общий интерфейс для *sql.Row и *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanCollectionItem сканирует колонки collectionItemColumns, extra - дополнительные колонки после них
+func scanCollectionItem(row rowScanner, item *models.CollectionItem, extra ...any) error {
+	dest := []any{
+		&item.ID,
+		&item.Type,
+		&item.Title,
+		&item.Description,
+		&item.CoverImage,
+		&item.ReleaseYear,
+		&item.IsPublic,
+		&item.IsCustom,
+		&item.CreatorID,
+		&item.ModerationStatus,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+	}
+	return row.Scan(append(dest, extra...)...)
+}
+
+// RecomputeItemStats пересчитывает популярность и оценки элементов для ленты в одной транзакции,
+// лента видит старую статистику до ее завершения.
+// Возвращает число сохраненных строк и false, если пересчет уже идет в другом экземпляре приложения
+func (r *CollectionItemRepository) RecomputeItemStats() (int64, bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	if err := tx.QueryRow(`SELECT pg_try_advisory_xact_lock($1)`, itemStatsLockKey).Scan(&locked); err != nil {
+		return 0, false, fmt.Errorf("failed to lock item stats: %w", err)
+	}
+	if !locked {
+		return 0, false, nil
+	}
+
+	if _, err := tx.Exec(fmt.Sprintf(`DELETE FROM %s`, itemStatsTable)); err != nil {
+		return 0, false, fmt.Errorf("failed to clear item stats: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %[1]s (item_id, popularity, avg_rating, ratings_count)
+		SELECT ci.id, COALESCE(a.collections_count, 0), rt.avg_rating, COALESCE(rt.ratings_count, 0)
+		FROM %[2]s ci
+		LEFT JOIN (
+			SELECT item_id, COUNT(*) AS collections_count
+			FROM %[3]s
+			GROUP BY item_id
+		) a ON a.item_id = ci.id
+		LEFT JOIN (
+			SELECT item_id, AVG(rating)::float8 AS avg_rating, COUNT(*) AS ratings_count
+			FROM %[4]s
+			WHERE rating IS NOT NULL
+			GROUP BY item_id
+		) rt ON rt.item_id = ci.id`, itemStatsTable, collectionItemsTable, collectionItemsAssignmentTable, userItemProgressTable)
+	res, err := tx.Exec(query)
+	if err != nil {
+		r.logger.Errorf("Failed to compute item stats: %v", err)
+		return 0, false, fmt.Errorf("failed to compute item stats: %w", err)
+	}
+	rows, _ := res.RowsAffected()
+
+	if err := tx.Commit(); err != nil {
+		return 0, false, fmt.Errorf("failed to commit item stats: %w", err)
+	}
+	return rows, true, nil
+}
+
+// GetItemStatsComputedAt - время последнего пересчета, nil если статистика еще не считалась
+func (r *CollectionItemRepository) GetItemStatsComputedAt() (*time.Time, error) {
+	var computedAt *time.Time
+	query := fmt.Sprintf(`SELECT MAX(computed_at) FROM %s`, itemStatsTable)
+	if err := r.db.QueryRow(query).Scan(&computedAt); err != nil {
+		return nil, fmt.Errorf("failed to get item stats time: %w", err)
+	}
+	return computedAt, nil
+}
@@ -4,6 +4,7 @@ package repository
 import (
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
 	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
@@ -22,7 +23,14 @@ func NewCollectionPostgres(db *sql.DB, logger *zap.SugaredLogger) *CollectionRep
 	}
 }
 
+// CreateCollection создает коллекцию. Публичная коллекция попадает в журнал активности владельца
 func (r *CollectionRepository) CreateCollection(collection *models.Collection) (*models.Collection, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	query := fmt.Sprintf(`
         INSERT INTO %s (name, description, is_public, cover_image, type, user_id) 
         VALUES ($1, $2, $3, $4, $5, $6) 
@@ -30,7 +38,7 @@ func (r *CollectionRepository) CreateCollection(collection *models.Collection) (
     `, collectionsTable)
 
 	var createdCollection models.Collection
-	err := r.db.QueryRow(
+	err = tx.QueryRow(
 		query,
 		collection.Name,
 		collection.Description,
@@ -54,20 +62,36 @@ func (r *CollectionRepository) CreateCollection(collection *models.Collection) (
 		return nil, err
 	}
 
+	if createdCollection.IsPublic {
+		err := insertActivity(tx, createdCollection.UserID, models.ActivityCollectionPublished, "", createdCollection.ID, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return &createdCollection, nil
 }
 
 // GetCollectionsWithPagination - метод с пагинацией
-func (r *CollectionRepository) GetCollectionsWithPagination(userID int, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.Collection], error) {
+func (r *CollectionRepository) GetCollectionsWithPagination(userID int, tags models.TagFilter, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.Collection], error) {
+	f := &queryFilter{}
+	f.where("c.user_id = " + f.arg(userID))
+	if !tags.IsEmpty() {
+		f.where(collectionTagsCondition(f, "c.id", tags))
+	}
+
 	// Сначала получаем общее количество коллекций пользователя
 	countQuery := fmt.Sprintf(`
         SELECT COUNT(*) 
-        FROM %s 
-        WHERE user_id = $1
-    `, collectionsTable)
+        FROM %s c
+        %s
+    `, collectionsTable, f.sql())
 
 	var total int64
-	err := r.db.QueryRow(countQuery, userID).Scan(&total)
+	err := r.db.QueryRow(countQuery, f.args...).Scan(&total)
 	if err != nil {
 		r.logger.Errorf("Failed to count collections for user %d: %v", userID, err)
 		return nil, fmt.Errorf("failed to count collections: %w", err)
@@ -75,23 +99,87 @@ func (r *CollectionRepository) GetCollectionsWithPagination(userID int, req pagi
 
 	// Получаем данные с пагинацией
 	query := fmt.Sprintf(`
-        SELECT id, name, description, is_public, cover_image, created_at, user_id, type
-        FROM %s
-        WHERE user_id = $1
-        ORDER BY created_at DESC
-        LIMIT $2 OFFSET $3
-    `, collectionsTable)
+        SELECT c.id, c.name, c.description, c.is_public, c.cover_image, c.created_at, c.user_id, c.type,
+            c.likes_count, c.saves_count, c.comments_count
+        FROM %s c
+        %s
+        ORDER BY %s
+        LIMIT %s OFFSET %s
+    `, collectionsTable, f.sql(), collectionsKeyset.orderBy(false), f.arg(req.Limit()), f.arg(req.Offset()))
 
 	r.logger.Infof("Getting collections for user_id: %d, page: %d, limit: %d", userID, req.Page(), req.Limit())
 
-	rows, err := r.db.Query(query, userID, req.Limit(), req.Offset())
+	collections, err := r.queryCollections(query, f.args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pagination.PaginatedResponse[models.Collection]{
+		Data:       collections,
+		Pagination: req.ToPagination(total),
+	}, nil
+}
+
+// Новые коллекции первыми
+var collectionsKeyset = keyset{scope: "collections", desc: true, columns: []keysetColumn{
+	{expr: "c.created_at", cast: "timestamptz"},
+	{expr: "c.id", cast: "uuid"},
+}}
+
+// GetCollectionsByCursor - коллекции пользователя в keyset-режиме, общее кол-во считается по запросу
+func (r *CollectionRepository) GetCollectionsByCursor(userID int, tags models.TagFilter, req pagination.CursorRequest) (*pagination.CursorResponse[models.Collection], error) {
+	f := &queryFilter{}
+	f.where("c.user_id = " + f.arg(userID))
+	if !tags.IsEmpty() {
+		f.where(collectionTagsCondition(f, "c.id", tags))
+	}
+
+	var total *int64
+	if req.WithTotal() {
+		countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM %s c %s`, collectionsTable, f.sql())
+
+		var count int64
+		if err := r.db.QueryRow(countQuery, f.args...).Scan(&count); err != nil {
+			r.logger.Errorf("Failed to count collections for user %d: %v", userID, err)
+			return nil, fmt.Errorf("failed to count collections: %w", err)
+		}
+		total = &count
+	}
+
+	orderBy, err := collectionsKeyset.apply(f, req)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+        SELECT c.id, c.name, c.description, c.is_public, c.cover_image, c.created_at, c.user_id, c.type,
+            c.likes_count, c.saves_count, c.comments_count
+        FROM %s c
+        %s
+        ORDER BY %s
+        LIMIT %s
+    `, collectionsTable, f.sql(), orderBy, f.arg(req.FetchLimit()))
+
+	collections, err := r.queryCollections(query, f.args)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := func(collection models.Collection) []string {
+		return []string{collection.CreatedAt.Format(time.RFC3339Nano), collection.ID}
+	}
+	return pagination.NewCursorResponse(collections, req, collectionsKeyset.scope, keys, total), nil
+}
+
+func (r *CollectionRepository) queryCollections(query string, args []any) ([]models.Collection, error) {
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		r.logger.Errorf("Query execution failed: %v", err)
 		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
 	defer rows.Close()
 
-	var collections []models.Collection
+	collections := []models.Collection{}
 	for rows.Next() {
 		var collection models.Collection
 		err = rows.Scan(
@@ -103,6 +191,9 @@ func (r *CollectionRepository) GetCollectionsWithPagination(userID int, req pagi
 			&collection.CreatedAt,
 			&collection.UserID,
 			&collection.Type,
+			&collection.LikesCount,
+			&collection.SavesCount,
+			&collection.CommentsCount,
 		)
 
 		if err != nil {
@@ -117,28 +208,25 @@ func (r *CollectionRepository) GetCollectionsWithPagination(userID int, req pagi
 		r.logger.Errorf("Rows iteration error: %v", err)
 		return nil, fmt.Errorf("error during rows iteration: %w", err)
 	}
-
-	return &pagination.PaginatedResponse[models.Collection]{
-		Data:       collections,
-		Pagination: req.ToPagination(total),
-	}, nil
+	return collections, nil
 }
 
 func (r *CollectionRepository) GetCollections(userID int) ([]models.Collection, error) {
-	// Используем пагинацию с большим лимитом для эмуляции получения всех данных
-	req := pagination.NewUnlimitedPagination()
-
-	result, err := r.GetCollectionsWithPagination(userID, req)
-	if err != nil {
-		return nil, err
-	}
+	query := fmt.Sprintf(`
+        SELECT c.id, c.name, c.description, c.is_public, c.cover_image, c.created_at, c.user_id, c.type,
+            c.likes_count, c.saves_count, c.comments_count
+        FROM %s c
+        WHERE c.user_id = $1
+        ORDER BY %s
+    `, collectionsTable, collectionsKeyset.orderBy(false))
 
-	return result.Data, nil
+	return r.queryCollections(query, []any{userID})
 }
 
 func (r *CollectionRepository) GetCollectionByID(collectionID string) (*models.Collection, error) {
 	query := fmt.Sprintf(`
-        SELECT id, name, description, is_public, cover_image, created_at, user_id, type
+        SELECT id, name, description, is_public, cover_image, created_at, user_id, type,
+            likes_count, saves_count, comments_count
         FROM %s
         WHERE id = $1
     `, collectionsTable)
@@ -153,6 +241,9 @@ func (r *CollectionRepository) GetCollectionByID(collectionID string) (*models.C
 		&collection.CreatedAt,
 		&collection.UserID,
 		&collection.Type,
+		&collection.LikesCount,
+		&collection.SavesCount,
+		&collection.CommentsCount,
 	)
 
 	if err != nil {
@@ -166,6 +257,73 @@ func (r *CollectionRepository) GetCollectionByID(collectionID string) (*models.C
 	return &collection, nil
 }
 
+// SetCollectionPublic меняет видимость коллекции. Открытая коллекция попадает в журнал активности владельца.
+// Лайки, сохранения и комментарии закрытой коллекции сохраняются и снова видны, когда она открывается
+func (r *CollectionRepository) SetCollectionPublic(collectionID string, isPublic bool) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var userID int
+	err = tx.QueryRow(fmt.Sprintf(`
+		UPDATE %s SET is_public = $2
+		WHERE id = $1 AND is_public IS DISTINCT FROM $2
+		RETURNING user_id
+	`, collectionsTable), collectionID, isPublic).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return ErrNothingChanged
+	}
+	if err != nil {
+		r.logger.Errorf("Failed to update visibility of collection %s: %v", collectionID, err)
+		return fmt.Errorf("failed to update collection visibility: %w", err)
+	}
+
+	if isPublic {
+		if err := insertActivity(tx, userID, models.ActivityCollectionPublished, "", collectionID, nil); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetCollectionCovers - ссылки на обложки элементов коллекции в порядке ее списка (новые первыми)
+func (r *CollectionRepository) GetCollectionCovers(collectionID string, limit int) ([]string, error) {
+	query := fmt.Sprintf(`
+		SELECT ci.cover_image
+		FROM %s cia
+		JOIN %s ci ON ci.id = cia.item_id
+		WHERE cia.collection_id = $1 AND COALESCE(ci.cover_image, '') <> ''
+		ORDER BY cia.added_at DESC, cia.id DESC
+		LIMIT $2
+	`, collectionItemsAssignmentTable, collectionItemsTable)
+
+	rows, err := r.db.Query(query, collectionID, limit)
+	if err != nil {
+		r.logger.Errorf("Failed to get covers of collection %s: %v", collectionID, err)
+		return nil, fmt.Errorf("failed to get collection covers: %w", err)
+	}
+	defer rows.Close()
+
+	covers := []string{}
+	for rows.Next() {
+		var cover string
+		if err := rows.Scan(&cover); err != nil {
+			return nil, fmt.Errorf("failed to scan cover: %w", err)
+		}
+		covers = append(covers, cover)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return covers, nil
+}
+
 func (r *CollectionRepository) DeleteCollection(collectionID string) error {
 	query := fmt.Sprintf(`
         DELETE FROM %s 
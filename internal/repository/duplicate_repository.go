@@ -0,0 +1,265 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"go.uber.org/zap"
+)
+
+const (
+	// Минимальная похожесть нормализованных названий для кандидата в дубликаты
+	duplicateSimilarityThreshold = 0.6
+	// Допустимая разница годов выхода: переиздания и разные даты премьер
+	duplicateYearTolerance = 1
+)
+
+type DuplicateRepository struct {
+	db     *sql.DB
+	logger *zap.SugaredLogger
+}
+
+func NewDuplicatePostgres(db *sql.DB, logger *zap.SugaredLogger) *DuplicateRepository {
+	return &DuplicateRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// FindDuplicates ищет видимые пользователю элементы того же типа с похожим нормализованным названием.
+// Если год известен у обоих элементов (release_year или год в скобках в названии), он должен совпадать с точностью до допуска
+func (r *DuplicateRepository) FindDuplicates(item *models.CollectionItem, userID int, limit int) ([]models.DuplicateCandidate, error) {
+	query := fmt.Sprintf(`
+		WITH candidate AS (
+			SELECT normalize_item_title($1) AS title, COALESCE($2::int, item_title_year($1)) AS year
+		)
+		SELECT %[1]s, similarity(ci.normalized_title, candidate.title) AS score
+		FROM %[2]s ci, candidate
+		WHERE ci.type = $3
+		  AND (ci.is_public = TRUE OR ci.creator_id = $4)
+		  AND (ci.normalized_title = candidate.title
+		       OR (ci.normalized_title %% candidate.title AND similarity(ci.normalized_title, candidate.title) >= $5))
+		  AND (candidate.year IS NULL
+		       OR COALESCE(ci.release_year, item_title_year(ci.title)) IS NULL
+		       OR abs(COALESCE(ci.release_year, item_title_year(ci.title)) - candidate.year) <= $6)
+		ORDER BY score DESC, ci.is_custom ASC, ci.created_at ASC
+		LIMIT $7
+	`, collectionItemColumns, collectionItemsTable)
+
+	rows, err := r.db.Query(query, item.Title, item.ReleaseYear, item.Type, userID,
+		duplicateSimilarityThreshold, duplicateYearTolerance, limit)
+	if err != nil {
+		r.logger.Errorf("Failed to find duplicates for %q: %v", item.Title, err)
+		return nil, fmt.Errorf("failed to find duplicates: %w", err)
+	}
+	defer rows.Close()
+
+	candidates := []models.DuplicateCandidate{}
+	for rows.Next() {
+		var candidate models.DuplicateCandidate
+		if err := scanCollectionItem(rows, &candidate.Item, &candidate.Similarity); err != nil {
+			r.logger.Errorf("Scan failed: %v", err)
+			return nil, fmt.Errorf("failed to scan duplicate candidate: %w", err)
+		}
+		candidates = append(candidates, candidate)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return candidates, nil
+}
+
+// MergeItems переносит все ссылки с дубликата на канонический элемент, удаляет дубликат
+// и оставляет редирект со старого ID. Все в одной транзакции
+func (r *DuplicateRepository) MergeItems(duplicateID string, canonicalID string, userID int) (*models.ItemMergeResult, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result := &models.ItemMergeResult{
+		CanonicalID: canonicalID,
+		DuplicateID: duplicateID,
+		MergedBy:    userID,
+	}
+
+	// Коллекции, где уже есть канонический элемент, теряют дубликат
+	if _, err := tx.Exec(fmt.Sprintf(`
+		DELETE FROM %[1]s
+		WHERE item_id = $1 AND collection_id IN (SELECT collection_id FROM %[1]s WHERE item_id = $2)
+	`, collectionItemsAssignmentTable), duplicateID, canonicalID); err != nil {
+		return nil, r.mergeError("assignments", err)
+	}
+	res, err := tx.Exec(fmt.Sprintf(`UPDATE %s SET item_id = $2 WHERE item_id = $1`, collectionItemsAssignmentTable), duplicateID, canonicalID)
+	if err != nil {
+		return nil, r.mergeError("assignments", err)
+	}
+	result.MovedAssignments, _ = res.RowsAffected()
+
+	// Прогресс и оценки: при конфликте приоритет у канонического элемента, пустые поля дополняются
+	res, err = tx.Exec(fmt.Sprintf(`
+		INSERT INTO %[1]s (user_id, item_id, status, progress, rating, started_at, finished_at, updated_at)
+		SELECT user_id, $2, status, progress, rating, started_at, finished_at, NOW()
+		FROM %[1]s
+		WHERE item_id = $1
+		ON CONFLICT (user_id, item_id) DO UPDATE SET
+			rating = COALESCE(%[1]s.rating, EXCLUDED.rating),
+			progress = GREATEST(%[1]s.progress, EXCLUDED.progress),
+			started_at = LEAST(%[1]s.started_at, EXCLUDED.started_at),
+			finished_at = COALESCE(%[1]s.finished_at, EXCLUDED.finished_at),
+			updated_at = NOW()
+	`, userItemProgressTable), duplicateID, canonicalID)
+	if err != nil {
+		return nil, r.mergeError("progress", err)
+	}
+	result.MovedProgress, _ = res.RowsAffected()
+
+	// Эпизоды сопоставляются с каноническими по номерам сезона и эпизода: отметки о просмотре
+	// совпадающих эпизодов переносятся, недостающие эпизоды и сезоны переходят к каноническому элементу
+	res, err = tx.Exec(fmt.Sprintf(`
+		INSERT INTO %[1]s (user_id, episode_id, watched_at)
+		SELECT w.user_id, ce.id, w.watched_at
+		FROM %[1]s w
+		JOIN %[2]s de ON de.id = w.episode_id
+		JOIN %[3]s ds ON ds.id = de.season_id AND ds.item_id = $1
+		JOIN %[3]s cs ON cs.item_id = $2 AND cs.number = ds.number
+		JOIN %[2]s ce ON ce.season_id = cs.id AND ce.number = de.number
+		ON CONFLICT (user_id, episode_id) DO UPDATE SET
+			watched_at = LEAST(%[1]s.watched_at, EXCLUDED.watched_at)
+	`, userWatchedEpisodesTable, itemEpisodesTable, itemSeasonsTable), duplicateID, canonicalID)
+	if err != nil {
+		return nil, r.mergeError("watched episodes", err)
+	}
+	result.MergedWatchedEpisodes, _ = res.RowsAffected()
+
+	res, err = tx.Exec(fmt.Sprintf(`
+		UPDATE %[1]s de SET season_id = cs.id
+		FROM %[2]s ds, %[2]s cs
+		WHERE de.season_id = ds.id AND ds.item_id = $1
+			AND cs.item_id = $2 AND cs.number = ds.number
+			AND NOT EXISTS (SELECT 1 FROM %[1]s ce WHERE ce.season_id = cs.id AND ce.number = de.number)
+	`, itemEpisodesTable, itemSeasonsTable), duplicateID, canonicalID)
+	if err != nil {
+		return nil, r.mergeError("episodes", err)
+	}
+	result.MovedEpisodes, _ = res.RowsAffected()
+
+	res, err = tx.Exec(fmt.Sprintf(`
+		UPDATE %[1]s ds SET item_id = $2
+		WHERE ds.item_id = $1 AND NOT EXISTS (SELECT 1 FROM %[1]s cs WHERE cs.item_id = $2 AND cs.number = ds.number)
+	`, itemSeasonsTable), duplicateID, canonicalID)
+	if err != nil {
+		return nil, r.mergeError("seasons", err)
+	}
+	result.MovedSeasons, _ = res.RowsAffected()
+
+	// Прогресс по эпизодам не может быть меньше числа просмотренных эпизодов после слияния
+	if _, err := tx.Exec(fmt.Sprintf(`
+		UPDATE %[1]s p SET progress = GREATEST(p.progress, w.watched), updated_at = NOW()
+		FROM (
+			SELECT w.user_id, COUNT(*) AS watched
+			FROM %[2]s w
+			JOIN %[3]s e ON e.id = w.episode_id
+			JOIN %[4]s s ON s.id = e.season_id
+			WHERE s.item_id = $1 AND s.number > 0
+			GROUP BY w.user_id
+		) w
+		WHERE p.user_id = w.user_id AND p.item_id = $1
+	`, userItemProgressTable, userWatchedEpisodesTable, itemEpisodesTable, itemSeasonsTable), canonicalID); err != nil {
+		return nil, r.mergeError("progress", err)
+	}
+
+	// Внешние ID переходят к каноническому элементу, чтобы импорт и обновление из каталогов находили его
+	if _, err := tx.Exec(fmt.Sprintf(`UPDATE %s SET item_id = $2 WHERE item_id = $1`, itemExternalIDsTable), duplicateID, canonicalID); err != nil {
+		return nil, r.mergeError("external ids", err)
+	}
+	if _, err := tx.Exec(fmt.Sprintf(`UPDATE %s SET item_id = $2 WHERE item_id = $1`, importExternalIDsTable), duplicateID, canonicalID); err != nil {
+		return nil, r.mergeError("import external ids", err)
+	}
+
+	copyQueries := map[string]string{
+		"genres": fmt.Sprintf(`
+			INSERT INTO %[1]s (item_id, genre_id) SELECT $2, genre_id FROM %[1]s WHERE item_id = $1
+			ON CONFLICT DO NOTHING`, itemGenresTable),
+		"tags": fmt.Sprintf(`
+			INSERT INTO %[1]s (user_id, item_id, tag_id) SELECT user_id, $2, tag_id FROM %[1]s WHERE item_id = $1
+			ON CONFLICT DO NOTHING`, itemTagsTable),
+		"franchises": fmt.Sprintf(`
+			INSERT INTO %[1]s (franchise_id, item_id) SELECT franchise_id, $2 FROM %[1]s WHERE item_id = $1
+			ON CONFLICT DO NOTHING`, franchiseItemsTable),
+	}
+	for name, query := range copyQueries {
+		if _, err := tx.Exec(query, duplicateID, canonicalID); err != nil {
+			return nil, r.mergeError(name, err)
+		}
+	}
+
+	// Связи: связь между дубликатом и каноническим теряет смысл, остальные перевешиваются,
+	// если у канонического элемента еще нет такой же. Оставшиеся удалятся каскадно вместе с дубликатом
+	relationQueries := []string{
+		fmt.Sprintf(`DELETE FROM %s WHERE (source_item_id = $1 AND target_item_id = $2) OR (source_item_id = $2 AND target_item_id = $1)`, itemRelationsTable),
+		fmt.Sprintf(`
+			UPDATE %[1]s rel SET source_item_id = $2
+			WHERE rel.source_item_id = $1 AND NOT EXISTS (
+				SELECT 1 FROM %[1]s ex
+				WHERE ex.source_item_id = $2 AND ex.target_item_id = rel.target_item_id AND ex.relation_type = rel.relation_type
+			)`, itemRelationsTable),
+		fmt.Sprintf(`
+			UPDATE %[1]s rel SET target_item_id = $2
+			WHERE rel.target_item_id = $1 AND NOT EXISTS (
+				SELECT 1 FROM %[1]s ex
+				WHERE ex.target_item_id = $2 AND ex.source_item_id = rel.source_item_id AND ex.relation_type = rel.relation_type
+			)`, itemRelationsTable),
+	}
+	for _, query := range relationQueries {
+		if _, err := tx.Exec(query, duplicateID, canonicalID); err != nil {
+			return nil, r.mergeError("relations", err)
+		}
+	}
+
+	// Старые редиректы на дубликат теперь ведут на канонический элемент
+	if _, err := tx.Exec(fmt.Sprintf(`UPDATE %s SET new_id = $2 WHERE new_id = $1`, itemRedirectsTable), duplicateID, canonicalID); err != nil {
+		return nil, r.mergeError("redirects", err)
+	}
+
+	if _, err := tx.Exec(fmt.Sprintf(`DELETE FROM %s WHERE id = $1`, collectionItemsTable), duplicateID); err != nil {
+		return nil, r.mergeError("duplicate item", err)
+	}
+
+	err = tx.QueryRow(fmt.Sprintf(`
+		INSERT INTO %s (old_id, new_id, merged_by) VALUES ($1, $2, $3)
+		RETURNING merged_at
+	`, itemRedirectsTable), duplicateID, canonicalID, userID).Scan(&result.MergedAt)
+	if err != nil {
+		return nil, r.mergeError("redirects", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetItemRedirect возвращает ID элемента, с которым был слит oldID
+func (r *DuplicateRepository) GetItemRedirect(oldID string) (string, error) {
+	query := fmt.Sprintf(`SELECT new_id FROM %s WHERE old_id = $1`, itemRedirectsTable)
+
+	var newID string
+	if err := r.db.QueryRow(query, oldID).Scan(&newID); err != nil {
+		if err == sql.ErrNoRows {
+			return "", ErrNotFound
+		}
+		r.logger.Errorf("Failed to get redirect for item %s: %v", oldID, err)
+		return "", err
+	}
+	return newID, nil
+}
+
+func (r *DuplicateRepository) mergeError(step string, err error) error {
+	r.logger.Errorf("Failed to merge %s: %v", step, err)
+	return fmt.Errorf("failed to merge %s: %w", step, err)
+}
@@ -0,0 +1,407 @@
+package repository
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
+	"go.uber.org/zap"
+)
+
+// editableItemFields - поля элемента, доступные для правок, и их колонки
+var editableItemFields = map[string]string{
+	"title":        "title",
+	"description":  "description",
+	"cover_image":  "cover_image",
+	"release_year": "release_year",
+}
+
+const editSuggestionColumns = "id, item_id, author_id, changes, comment, status, reviewer_id, review_comment, created_at, reviewed_at"
+
+type EditRepository struct {
+	db     *sql.DB
+	logger *zap.SugaredLogger
+}
+
+func NewEditPostgres(db *sql.DB, logger *zap.SugaredLogger) *EditRepository {
+	return &EditRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *EditRepository) CreateSuggestion(suggestion *models.EditSuggestion) (*models.EditSuggestion, error) {
+	changes, err := json.Marshal(suggestion.Changes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal changes: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (item_id, author_id, changes, comment)
+		VALUES ($1, $2, $3, $4)
+		RETURNING %s
+	`, itemEditSuggestionsTable, editSuggestionColumns)
+
+	created, err := scanEditSuggestion(r.db.QueryRow(query, suggestion.ItemID, suggestion.AuthorID, changes, suggestion.Comment))
+	if err != nil {
+		r.logger.Errorf("Failed to create edit suggestion for item %s: %v", suggestion.ItemID, err)
+		return nil, fmt.Errorf("failed to create edit suggestion: %w", err)
+	}
+	return created, nil
+}
+
+func (r *EditRepository) GetSuggestionByID(id int) (*models.EditSuggestion, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE id = $1`, editSuggestionColumns, itemEditSuggestionsTable)
+
+	suggestion, err := scanEditSuggestion(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		r.logger.Errorf("Failed to get edit suggestion %d: %v", id, err)
+		return nil, err
+	}
+	return suggestion, nil
+}
+
+// GetSuggestions - правки элемента (itemID) или всех элементов (пустой itemID) с фильтром по статусу.
+// Ожидающие проверки идут первыми старые, остальные - первыми новые
+func (r *EditRepository) GetSuggestions(itemID string, status string, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.EditSuggestion], error) {
+	f := &queryFilter{}
+	if itemID != "" {
+		f.where("item_id = " + f.arg(itemID))
+	}
+	if status != "" {
+		f.where("status = " + f.arg(status))
+	}
+
+	var total int64
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM %s %s`, itemEditSuggestionsTable, f.sql())
+	if err := r.db.QueryRow(countQuery, f.args...).Scan(&total); err != nil {
+		r.logger.Errorf("Failed to count edit suggestions: %v", err)
+		return nil, fmt.Errorf("failed to count edit suggestions: %w", err)
+	}
+
+	orderBy := "created_at DESC, id DESC"
+	if status == models.EditStatusPending {
+		orderBy = "created_at ASC, id ASC"
+	}
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s
+		%s
+		ORDER BY %s
+		LIMIT %s OFFSET %s
+	`, editSuggestionColumns, itemEditSuggestionsTable, f.sql(), orderBy, f.arg(req.Limit()), f.arg(req.Offset()))
+
+	rows, err := r.db.Query(query, f.args...)
+	if err != nil {
+		r.logger.Errorf("Failed to get edit suggestions: %v", err)
+		return nil, fmt.Errorf("failed to get edit suggestions: %w", err)
+	}
+	defer rows.Close()
+
+	suggestions := []models.EditSuggestion{}
+	for rows.Next() {
+		suggestion, err := scanEditSuggestion(rows)
+		if err != nil {
+			r.logger.Errorf("Scan failed: %v", err)
+			return nil, fmt.Errorf("failed to scan edit suggestion: %w", err)
+		}
+		suggestions = append(suggestions, *suggestion)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return &pagination.PaginatedResponse[models.EditSuggestion]{
+		Data:       suggestions,
+		Pagination: req.ToPagination(total),
+	}, nil
+}
+
+// AcceptSuggestion применяет правку к элементу, сохраняет ревизию и уведомляет автора правки
+func (r *EditRepository) AcceptSuggestion(id int, reviewerID int, comment *string) (*models.ItemRevision, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	suggestion, err := scanEditSuggestion(tx.QueryRow(fmt.Sprintf(`
+		SELECT %s FROM %s WHERE id = $1 AND status = $2 FOR UPDATE
+	`, editSuggestionColumns, itemEditSuggestionsTable), id, models.EditStatusPending))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get edit suggestion: %w", err)
+	}
+
+	revision, err := applyItemChanges(tx, r.logger, suggestion.Changes.ToMap(), &models.ItemRevision{
+		ItemID:       suggestion.ItemID,
+		AuthorID:     suggestion.AuthorID,
+		SuggestionID: &suggestion.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.reviewSuggestion(tx, suggestion, models.EditStatusAccepted, reviewerID, comment); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return revision, nil
+}
+
+func (r *EditRepository) RejectSuggestion(id int, reviewerID int, comment *string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	suggestion, err := scanEditSuggestion(tx.QueryRow(fmt.Sprintf(`
+		SELECT %s FROM %s WHERE id = $1 AND status = $2 FOR UPDATE
+	`, editSuggestionColumns, itemEditSuggestionsTable), id, models.EditStatusPending))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrNotFound
+		}
+		return fmt.Errorf("failed to get edit suggestion: %w", err)
+	}
+
+	if err := r.reviewSuggestion(tx, suggestion, models.EditStatusRejected, reviewerID, comment); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *EditRepository) GetRevisions(itemID string) ([]models.ItemRevision, error) {
+	query := fmt.Sprintf(`
+		SELECT id, item_id, author_id, suggestion_id, reverted_revision_id, provider, diff, created_at
+		FROM %s
+		WHERE item_id = $1
+		ORDER BY created_at DESC, id DESC
+	`, itemRevisionsTable)
+
+	rows, err := r.db.Query(query, itemID)
+	if err != nil {
+		r.logger.Errorf("Failed to get revisions of item %s: %v", itemID, err)
+		return nil, fmt.Errorf("failed to get revisions: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := []models.ItemRevision{}
+	for rows.Next() {
+		revision, err := scanItemRevision(rows)
+		if err != nil {
+			r.logger.Errorf("Scan failed: %v", err)
+			return nil, fmt.Errorf("failed to scan revision: %w", err)
+		}
+		revisions = append(revisions, *revision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// RevertRevision возвращает поля ревизии к значениям до нее. Откат сохраняется новой ревизией
+func (r *EditRepository) RevertRevision(itemID string, revisionID int, userID int) (*models.ItemRevision, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	revision, err := scanItemRevision(tx.QueryRow(fmt.Sprintf(`
+		SELECT id, item_id, author_id, suggestion_id, reverted_revision_id, provider, diff, created_at
+		FROM %s WHERE id = $1 AND item_id = $2
+	`, itemRevisionsTable), revisionID, itemID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get revision: %w", err)
+	}
+
+	changes := make(map[string]any, len(revision.Diff))
+	for field, change := range revision.Diff {
+		changes[field] = change.Old
+	}
+
+	reverted, err := applyItemChanges(tx, r.logger, changes, &models.ItemRevision{
+		ItemID:             itemID,
+		AuthorID:           &userID,
+		RevertedRevisionID: &revision.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return reverted, nil
+}
+
+// applyItemChanges обновляет поля элемента и сохраняет ревизию с диффом реально измененных полей.
+// В revision заданы элемент и источник изменения: автор, правка, откатываемая ревизия или провайдер
+func applyItemChanges(tx *sql.Tx, logger *zap.SugaredLogger, changes map[string]any, revision *models.ItemRevision) (*models.ItemRevision, error) {
+	itemID := revision.ItemID
+	current := map[string]any{}
+	var title, description string
+	var coverImage *string
+	var releaseYear *int
+	err := tx.QueryRow(fmt.Sprintf(`
+		SELECT title, COALESCE(description, ''), cover_image, release_year
+		FROM %s WHERE id = $1 FOR UPDATE
+	`, collectionItemsTable), itemID).Scan(&title, &description, &coverImage, &releaseYear)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to lock item: %w", err)
+	}
+	current["title"] = title
+	current["description"] = description
+	current["cover_image"] = coverImage
+	current["release_year"] = releaseYear
+
+	fields := make([]string, 0, len(changes))
+	for field := range changes {
+		if _, ok := editableItemFields[field]; ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	diff := map[string]models.FieldChange{}
+	f := &queryFilter{}
+	sets := []string{}
+	for _, field := range fields {
+		if sameJSON(current[field], changes[field]) {
+			continue
+		}
+		diff[field] = models.FieldChange{Old: current[field], New: changes[field]}
+		sets = append(sets, fmt.Sprintf("%s = %s", editableItemFields[field], f.arg(changes[field])))
+	}
+	if len(diff) == 0 {
+		return nil, ErrNothingChanged
+	}
+
+	updateQuery := fmt.Sprintf(`UPDATE %s SET %s, updated_at = NOW() WHERE id = %s`,
+		collectionItemsTable, strings.Join(sets, ", "), f.arg(itemID))
+	if _, err := tx.Exec(updateQuery, f.args...); err != nil {
+		logger.Errorf("Failed to update item %s: %v", itemID, err)
+		return nil, fmt.Errorf("failed to update item: %w", err)
+	}
+
+	diffJSON, err := json.Marshal(diff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal diff: %w", err)
+	}
+
+	revision.Diff = diff
+	err = tx.QueryRow(fmt.Sprintf(`
+		INSERT INTO %s (item_id, author_id, suggestion_id, reverted_revision_id, provider, diff)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`, itemRevisionsTable), itemID, revision.AuthorID, revision.SuggestionID, revision.RevertedRevisionID, revision.Provider, diffJSON).Scan(&revision.ID, &revision.CreatedAt)
+	if err != nil {
+		logger.Errorf("Failed to save revision of item %s: %v", itemID, err)
+		return nil, fmt.Errorf("failed to save revision: %w", err)
+	}
+
+	return revision, nil
+}
+
+func (r *EditRepository) reviewSuggestion(tx *sql.Tx, suggestion *models.EditSuggestion, status string, reviewerID int, comment *string) error {
+	_, err := tx.Exec(fmt.Sprintf(`
+		UPDATE %s SET status = $2, reviewer_id = $3, review_comment = $4, reviewed_at = NOW()
+		WHERE id = $1
+	`, itemEditSuggestionsTable), suggestion.ID, status, reviewerID, comment)
+	if err != nil {
+		r.logger.Errorf("Failed to review edit suggestion %d: %v", suggestion.ID, err)
+		return fmt.Errorf("failed to review edit suggestion: %w", err)
+	}
+
+	if suggestion.AuthorID != nil {
+		payload := models.EditReviewNotificationPayload{
+			SuggestionID:  suggestion.ID,
+			ItemID:        suggestion.ItemID,
+			Status:        status,
+			ReviewComment: comment,
+		}
+		if _, err := insertNotification(tx, *suggestion.AuthorID, models.NotificationEditReviewed, payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func scanEditSuggestion(row rowScanner) (*models.EditSuggestion, error) {
+	var suggestion models.EditSuggestion
+	var changes []byte
+	err := row.Scan(
+		&suggestion.ID,
+		&suggestion.ItemID,
+		&suggestion.AuthorID,
+		&changes,
+		&suggestion.Comment,
+		&suggestion.Status,
+		&suggestion.ReviewerID,
+		&suggestion.ReviewComment,
+		&suggestion.CreatedAt,
+		&suggestion.ReviewedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(changes, &suggestion.Changes); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal changes: %w", err)
+	}
+	return &suggestion, nil
+}
+
+func scanItemRevision(row rowScanner) (*models.ItemRevision, error) {
+	var revision models.ItemRevision
+	var diff []byte
+	err := row.Scan(
+		&revision.ID,
+		&revision.ItemID,
+		&revision.AuthorID,
+		&revision.SuggestionID,
+		&revision.RevertedRevisionID,
+		&revision.Provider,
+		&diff,
+		&revision.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(diff, &revision.Diff); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal diff: %w", err)
+	}
+	return &revision, nil
+}
+
+// sameJSON сравнивает значения по их JSON-представлению: *string и string, int и float64 из JSONB
+func sameJSON(a, b any) bool {
+	left, errLeft := json.Marshal(a)
+	right, errRight := json.Marshal(b)
+	return errLeft == nil && errRight == nil && bytes.Equal(left, right)
+}
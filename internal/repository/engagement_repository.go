@@ -0,0 +1,387 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
+	"go.uber.org/zap"
+)
+
+type EngagementRepository struct {
+	db     *sql.DB
+	logger *zap.SugaredLogger
+}
+
+func NewEngagementPostgres(db *sql.DB, logger *zap.SugaredLogger) *EngagementRepository {
+	return &EngagementRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetEngagement возвращает счетчики коллекции и реакции на нее пользователя userID
+func (r *EngagementRepository) GetEngagement(collectionID string, userID int) (*models.CollectionEngagement, error) {
+	query := fmt.Sprintf(`
+		SELECT c.id, c.likes_count, c.saves_count, c.comments_count,
+			EXISTS (SELECT 1 FROM %[2]s WHERE collection_id = c.id AND user_id = $2),
+			EXISTS (SELECT 1 FROM %[3]s WHERE collection_id = c.id AND user_id = $2)
+		FROM %[1]s c
+		WHERE c.id = $1
+	`, collectionsTable, collectionLikesTable, collectionSavesTable)
+
+	var engagement models.CollectionEngagement
+	err := r.db.QueryRow(query, collectionID, userID).Scan(
+		&engagement.CollectionID, &engagement.LikesCount, &engagement.SavesCount, &engagement.CommentsCount,
+		&engagement.Liked, &engagement.Saved,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		r.logger.Errorf("Failed to get engagement of collection %s: %v", collectionID, err)
+		return nil, fmt.Errorf("failed to get engagement: %w", err)
+	}
+	return &engagement, nil
+}
+
+// SetLiked ставит или снимает лайк. Повторный вызов ничего не меняет
+func (r *EngagementRepository) SetLiked(collectionID string, userID int, liked bool) error {
+	return r.setReaction(collectionLikesTable, "likes_count", collectionID, userID, liked)
+}
+
+// SetSaved добавляет коллекцию в сохраненные или убирает из них. Повторный вызов ничего не меняет
+func (r *EngagementRepository) SetSaved(collectionID string, userID int, saved bool) error {
+	return r.setReaction(collectionSavesTable, "saves_count", collectionID, userID, saved)
+}
+
+// setReaction добавляет или удаляет строку реакции и в той же транзакции сдвигает счетчик коллекции,
+// только если строка действительно появилась или исчезла
+func (r *EngagementRepository) setReaction(table string, counter string, collectionID string, userID int, set bool) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var res sql.Result
+	delta := 1
+	if set {
+		res, err = tx.Exec(fmt.Sprintf(`
+			INSERT INTO %s (user_id, collection_id) VALUES ($1, $2)
+			ON CONFLICT (user_id, collection_id) DO NOTHING
+		`, table), userID, collectionID)
+	} else {
+		delta = -1
+		res, err = tx.Exec(fmt.Sprintf(`DELETE FROM %s WHERE user_id = $1 AND collection_id = $2`, table), userID, collectionID)
+	}
+	if err != nil {
+		r.logger.Errorf("Failed to update %s of collection %s by user %d: %v", table, collectionID, userID, err)
+		return fmt.Errorf("failed to update %s: %w", table, err)
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return nil
+	}
+
+	_, err = tx.Exec(fmt.Sprintf(`UPDATE %s SET %s = GREATEST(%[2]s + $2, 0) WHERE id = $1`, collectionsTable, counter),
+		collectionID, delta)
+	if err != nil {
+		return fmt.Errorf("failed to update %s: %w", counter, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetSavedCollections - сохраненные пользователем коллекции, сначала сохраненные последними.
+// Коллекции, которые владелец закрыл, в списке не показываются, но закладка на них остается
+func (r *EngagementRepository) GetSavedCollections(userID int, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.SavedCollection], error) {
+	from := fmt.Sprintf(`
+		FROM %s s
+		JOIN %s c ON c.id = s.collection_id
+		JOIN %s u ON u.id = c.user_id AND u.deleted_at IS NULL
+		WHERE s.user_id = $1 AND (c.is_public = TRUE OR c.user_id = $1)
+	`, collectionSavesTable, collectionsTable, usersTable)
+
+	var total int64
+	if err := r.db.QueryRow("SELECT COUNT(*) "+from, userID).Scan(&total); err != nil {
+		r.logger.Errorf("Failed to count saved collections of user %d: %v", userID, err)
+		return nil, fmt.Errorf("failed to count saved collections: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT c.id, c.name, COALESCE(c.description, ''), COALESCE(c.is_public, false), c.cover_image, c.created_at, c.user_id, COALESCE(c.type, ''),
+			c.likes_count, c.saves_count, c.comments_count,
+			u.id, u.name, u.avatar_url, u.is_private, s.created_at
+		%s
+		ORDER BY s.created_at DESC, c.id
+		LIMIT $2 OFFSET $3
+	`, from)
+	rows, err := r.db.Query(query, userID, req.Limit(), req.Offset())
+	if err != nil {
+		r.logger.Errorf("Failed to get saved collections of user %d: %v", userID, err)
+		return nil, fmt.Errorf("failed to get saved collections: %w", err)
+	}
+	defer rows.Close()
+
+	collections := []models.SavedCollection{}
+	for rows.Next() {
+		var saved models.SavedCollection
+		err := rows.Scan(
+			&saved.ID, &saved.Name, &saved.Description, &saved.IsPublic, &saved.CoverImage, &saved.CreatedAt, &saved.UserID, &saved.Type,
+			&saved.LikesCount, &saved.SavesCount, &saved.CommentsCount,
+			&saved.Owner.ID, &saved.Owner.Name, &saved.Owner.AvatarURL, &saved.Owner.IsPrivate, &saved.SavedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan saved collection: %w", err)
+		}
+		collections = append(collections, saved)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return &pagination.PaginatedResponse[models.SavedCollection]{
+		Data:       collections,
+		Pagination: req.ToPagination(total),
+	}, nil
+}
+
+const commentColumns = `cc.id, cc.collection_id, cc.parent_id, cc.body, cc.deleted_at IS NOT NULL, cc.created_at, cc.edited_at,
+	u.id, u.name, u.avatar_url, u.is_private`
+
+func scanComment(row interface{ Scan(...any) error }, comment *models.CollectionComment) error {
+	return row.Scan(&comment.ID, &comment.CollectionID, &comment.ParentID, &comment.Body, &comment.IsDeleted,
+		&comment.CreatedAt, &comment.EditedAt,
+		&comment.Author.ID, &comment.Author.Name, &comment.Author.AvatarURL, &comment.Author.IsPrivate)
+}
+
+// GetComments - страница веток комментариев коллекции, сначала новые. Ветка приходит целиком:
+// ответы собираются под своими комментариями в порядке написания.
+// Удаленный комментарий остается в ветке, только если на него есть неудаленные ответы
+func (r *EngagementRepository) GetComments(collectionID string, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.CollectionComment], error) {
+	rootsFilter := fmt.Sprintf(`
+		cc.collection_id = $1 AND cc.root_id IS NULL
+		AND (cc.deleted_at IS NULL OR EXISTS (SELECT 1 FROM %s r WHERE r.root_id = cc.id AND r.deleted_at IS NULL))
+	`, collectionCommentsTable)
+
+	var total int64
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM %s cc WHERE %s`, collectionCommentsTable, rootsFilter)
+	if err := r.db.QueryRow(countQuery, collectionID).Scan(&total); err != nil {
+		r.logger.Errorf("Failed to count comments of collection %s: %v", collectionID, err)
+		return nil, fmt.Errorf("failed to count comments: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		WITH roots AS (
+			SELECT cc.id, cc.created_at FROM %[1]s cc
+			WHERE %[3]s
+			ORDER BY cc.created_at DESC, cc.id DESC
+			LIMIT $2 OFFSET $3
+		)
+		SELECT %[4]s
+		FROM %[1]s cc
+		JOIN %[2]s u ON u.id = cc.user_id
+		JOIN roots ON roots.id = COALESCE(cc.root_id, cc.id)
+		ORDER BY roots.created_at DESC, roots.id DESC, cc.created_at, cc.id
+	`, collectionCommentsTable, usersTable, rootsFilter, commentColumns)
+	rows, err := r.db.Query(query, collectionID, req.Limit(), req.Offset())
+	if err != nil {
+		r.logger.Errorf("Failed to get comments of collection %s: %v", collectionID, err)
+		return nil, fmt.Errorf("failed to get comments: %w", err)
+	}
+	defer rows.Close()
+
+	comments := []models.CollectionComment{}
+	for rows.Next() {
+		var comment models.CollectionComment
+		if err := scanComment(rows, &comment); err != nil {
+			return nil, fmt.Errorf("failed to scan comment: %w", err)
+		}
+		comments = append(comments, comment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return &pagination.PaginatedResponse[models.CollectionComment]{
+		Data:       buildCommentThreads(comments),
+		Pagination: req.ToPagination(total),
+	}, nil
+}
+
+// buildCommentThreads раскладывает комментарии по веткам. Родитель в выборке всегда раньше ответа,
+// поэтому ответы собираются с конца, а удаленные комментарии без ответов отбрасываются
+func buildCommentThreads(comments []models.CollectionComment) []models.CollectionComment {
+	replies := make(map[int64][]models.CollectionComment)
+	roots := []models.CollectionComment{}
+	for i := len(comments) - 1; i >= 0; i-- {
+		comment := comments[i]
+		comment.Replies = reverseComments(replies[comment.ID])
+		if comment.IsDeleted {
+			comment.Body = ""
+			if len(comment.Replies) == 0 {
+				continue
+			}
+		}
+		if comment.ParentID == nil {
+			roots = append(roots, comment)
+		} else {
+			replies[*comment.ParentID] = append(replies[*comment.ParentID], comment)
+		}
+	}
+	return reverseComments(roots)
+}
+
+func reverseComments(comments []models.CollectionComment) []models.CollectionComment {
+	reversed := make([]models.CollectionComment, len(comments))
+	for i, comment := range comments {
+		reversed[len(comments)-1-i] = comment
+	}
+	return reversed
+}
+
+// GetComment возвращает комментарий без ответов, в том числе удаленный
+func (r *EngagementRepository) GetComment(commentID int64) (*models.CollectionComment, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s cc JOIN %s u ON u.id = cc.user_id WHERE cc.id = $1
+	`, commentColumns, collectionCommentsTable, usersTable)
+
+	var comment models.CollectionComment
+	if err := scanComment(r.db.QueryRow(query, commentID), &comment); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		r.logger.Errorf("Failed to get comment %d: %v", commentID, err)
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+	return &comment, nil
+}
+
+// CreateComment добавляет комментарий или ответ и уведомляет владельца коллекции и автора комментария,
+// на который ответили. Ответить можно только на неудаленный комментарий той же коллекции
+func (r *EngagementRepository) CreateComment(collectionID string, userID int, parentID *int64, body string) (*models.CollectionComment, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var rootID *int64
+	parentAuthor := 0
+	if parentID != nil {
+		var parentRoot sql.NullInt64
+		err := tx.QueryRow(fmt.Sprintf(`
+			SELECT root_id, user_id FROM %s WHERE id = $1 AND collection_id = $2 AND deleted_at IS NULL
+		`, collectionCommentsTable), *parentID, collectionID).Scan(&parentRoot, &parentAuthor)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, ErrNotFound
+			}
+			return nil, fmt.Errorf("failed to get parent comment: %w", err)
+		}
+		rootID = parentID
+		if parentRoot.Valid {
+			rootID = &parentRoot.Int64
+		}
+	}
+
+	var commentID int64
+	err = tx.QueryRow(fmt.Sprintf(`
+		INSERT INTO %s (collection_id, user_id, parent_id, root_id, body) VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, collectionCommentsTable), collectionID, userID, parentID, rootID, body).Scan(&commentID)
+	if err != nil {
+		r.logger.Errorf("Failed to create comment on collection %s: %v", collectionID, err)
+		return nil, fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	var ownerID int
+	var collectionName string
+	err = tx.QueryRow(fmt.Sprintf(`
+		UPDATE %s SET comments_count = comments_count + 1 WHERE id = $1
+		RETURNING user_id, name
+	`, collectionsTable), collectionID).Scan(&ownerID, &collectionName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update comments count: %w", err)
+	}
+
+	var authorName string
+	if err := tx.QueryRow(fmt.Sprintf(`SELECT name FROM %s WHERE id = $1`, usersTable), userID).Scan(&authorName); err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	payload := models.CommentNotificationPayload{
+		CollectionID:   collectionID,
+		CollectionName: collectionName,
+		CommentID:      commentID,
+		UserID:         userID,
+		UserName:       authorName,
+	}
+	// Владелец, которому ответили на его комментарий, получает одно уведомление - об ответе
+	if parentAuthor != 0 && parentAuthor != userID {
+		if _, err := insertNotification(tx, parentAuthor, models.NotificationCommentReply, payload); err != nil {
+			return nil, err
+		}
+	}
+	if ownerID != userID && ownerID != parentAuthor {
+		if _, err := insertNotification(tx, ownerID, models.NotificationCollectionComment, payload); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return r.GetComment(commentID)
+}
+
+// UpdateComment меняет текст неудаленного комментария
+func (r *EngagementRepository) UpdateComment(commentID int64, body string) error {
+	res, err := r.db.Exec(fmt.Sprintf(`
+		UPDATE %s SET body = $2, edited_at = NOW() WHERE id = $1 AND deleted_at IS NULL
+	`, collectionCommentsTable), commentID, body)
+	if err != nil {
+		r.logger.Errorf("Failed to update comment %d: %v", commentID, err)
+		return fmt.Errorf("failed to update comment: %w", err)
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// DeleteComment удаляет комментарий: текст стирается, а сам он остается, чтобы ответы на него не потерялись
+func (r *EngagementRepository) DeleteComment(commentID int64) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var collectionID string
+	err = tx.QueryRow(fmt.Sprintf(`
+		UPDATE %s SET body = '', deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL
+		RETURNING collection_id
+	`, collectionCommentsTable), commentID).Scan(&collectionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrNotFound
+		}
+		r.logger.Errorf("Failed to delete comment %d: %v", commentID, err)
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+
+	_, err = tx.Exec(fmt.Sprintf(`UPDATE %s SET comments_count = GREATEST(comments_count - 1, 0) WHERE id = $1`, collectionsTable),
+		collectionID)
+	if err != nil {
+		return fmt.Errorf("failed to update comments count: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
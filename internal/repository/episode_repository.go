@@ -0,0 +1,502 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"go.uber.org/zap"
+)
+
+type EpisodeRepository struct {
+	db     *sql.DB
+	logger *zap.SugaredLogger
+}
+
+func NewEpisodePostgres(db *sql.DB, logger *zap.SugaredLogger) *EpisodeRepository {
+	return &EpisodeRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetSeasonsByItem возвращает сезоны элемента с эпизодами и отметками просмотра пользователя
+func (r *EpisodeRepository) GetSeasonsByItem(itemID string, userID int) ([]models.Season, error) {
+	seasonsQuery := fmt.Sprintf(`
+		SELECT id, item_id, number, title, created_at
+		FROM %s
+		WHERE item_id = $1
+		ORDER BY number ASC
+	`, itemSeasonsTable)
+
+	rows, err := r.db.Query(seasonsQuery, itemID)
+	if err != nil {
+		r.logger.Errorf("Failed to get seasons for item %s: %v", itemID, err)
+		return nil, fmt.Errorf("failed to get seasons: %w", err)
+	}
+	defer rows.Close()
+
+	seasons := []models.Season{}
+	seasonIndex := make(map[string]int)
+	for rows.Next() {
+		var season models.Season
+		if err := rows.Scan(&season.ID, &season.ItemID, &season.Number, &season.Title, &season.CreatedAt); err != nil {
+			r.logger.Errorf("Scan failed: %v", err)
+			return nil, fmt.Errorf("failed to scan season: %w", err)
+		}
+		season.Episodes = []models.Episode{}
+		seasonIndex[season.ID] = len(seasons)
+		seasons = append(seasons, season)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	episodesQuery := fmt.Sprintf(`
+		SELECT e.id, e.season_id, e.number, e.title, e.air_date, e.runtime_minutes, w.episode_id IS NOT NULL
+		FROM %s e
+		JOIN %s s ON s.id = e.season_id
+		LEFT JOIN %s w ON w.episode_id = e.id AND w.user_id = $2
+		WHERE s.item_id = $1
+		ORDER BY s.number ASC, e.number ASC
+	`, itemEpisodesTable, itemSeasonsTable, userWatchedEpisodesTable)
+
+	episodeRows, err := r.db.Query(episodesQuery, itemID, userID)
+	if err != nil {
+		r.logger.Errorf("Failed to get episodes for item %s: %v", itemID, err)
+		return nil, fmt.Errorf("failed to get episodes: %w", err)
+	}
+	defer episodeRows.Close()
+
+	for episodeRows.Next() {
+		var episode models.Episode
+		if err := scanEpisode(episodeRows, &episode); err != nil {
+			r.logger.Errorf("Scan failed: %v", err)
+			return nil, fmt.Errorf("failed to scan episode: %w", err)
+		}
+		if idx, ok := seasonIndex[episode.SeasonID]; ok {
+			seasons[idx].Episodes = append(seasons[idx].Episodes, episode)
+		}
+	}
+	if err := episodeRows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return seasons, nil
+}
+
+// GetSeasonByNumber возвращает сезон элемента по его номеру (без эпизодов)
+func (r *EpisodeRepository) GetSeasonByNumber(itemID string, number int) (*models.Season, error) {
+	query := fmt.Sprintf(`
+		SELECT id, item_id, number, title, created_at
+		FROM %s
+		WHERE item_id = $1 AND number = $2
+	`, itemSeasonsTable)
+
+	var season models.Season
+	err := r.db.QueryRow(query, itemID, number).Scan(&season.ID, &season.ItemID, &season.Number, &season.Title, &season.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		r.logger.Errorf("Failed to get season %d for item %s: %v", number, itemID, err)
+		return nil, err
+	}
+
+	return &season, nil
+}
+
+// CreateSeason создает сезон вместе с переданными эпизодами в одной транзакции
+func (r *EpisodeRepository) CreateSeason(season *models.Season) (*models.Season, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (item_id, number, title)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`, itemSeasonsTable)
+
+	if err := tx.QueryRow(query, season.ItemID, season.Number, season.Title).Scan(&season.ID, &season.CreatedAt); err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrAlreadyExists
+		}
+		r.logger.Errorf("Failed to create season for item %s: %v", season.ItemID, err)
+		return nil, fmt.Errorf("failed to create season: %w", err)
+	}
+
+	for i := range season.Episodes {
+		season.Episodes[i].SeasonID = season.ID
+		id, err := insertEpisode(tx, &season.Episodes[i])
+		if err != nil {
+			if isUniqueViolation(err) {
+				return nil, ErrAlreadyExists
+			}
+			r.logger.Errorf("Failed to create episode for season %s: %v", season.ID, err)
+			return nil, fmt.Errorf("failed to create episode: %w", err)
+		}
+		season.Episodes[i].ID = id
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return season, nil
+}
+
+func (r *EpisodeRepository) CreateEpisode(episode *models.Episode) (string, error) {
+	id, err := insertEpisode(r.db, episode)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return "", ErrAlreadyExists
+		}
+		r.logger.Errorf("Failed to create episode for season %s: %v", episode.SeasonID, err)
+		return "", fmt.Errorf("failed to create episode: %w", err)
+	}
+	return id, nil
+}
+
+// SetEpisodeWatched отмечает (или снимает отметку) эпизод как просмотренный и пересчитывает прогресс по элементу
+func (r *EpisodeRepository) SetEpisodeWatched(userID int, itemID string, episodeID string, watched bool) (*models.ItemProgress, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Эпизод должен принадлежать элементу
+	var exists bool
+	checkQuery := fmt.Sprintf(`
+		SELECT EXISTS(
+			SELECT 1 FROM %s e JOIN %s s ON s.id = e.season_id
+			WHERE e.id = $1 AND s.item_id = $2
+		)
+	`, itemEpisodesTable, itemSeasonsTable)
+	if err := tx.QueryRow(checkQuery, episodeID, itemID).Scan(&exists); err != nil {
+		r.logger.Errorf("Failed to check episode %s: %v", episodeID, err)
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	var query string
+	if watched {
+		query = fmt.Sprintf(`INSERT INTO %s (user_id, episode_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, userWatchedEpisodesTable)
+	} else {
+		query = fmt.Sprintf(`DELETE FROM %s WHERE user_id = $1 AND episode_id = $2`, userWatchedEpisodesTable)
+	}
+	if _, err := tx.Exec(query, userID, episodeID); err != nil {
+		r.logger.Errorf("Failed to update watched episode %s for user %d: %v", episodeID, userID, err)
+		return nil, fmt.Errorf("failed to update watched episode: %w", err)
+	}
+
+	progress, err := r.recalculateProgress(tx, userID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return progress, nil
+}
+
+// SetSeasonWatched отмечает (или снимает отметку) все эпизоды сезона и пересчитывает прогресс по элементу
+func (r *EpisodeRepository) SetSeasonWatched(userID int, itemID string, seasonID string, watched bool) (*models.ItemProgress, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var query string
+	if watched {
+		query = fmt.Sprintf(`
+			INSERT INTO %s (user_id, episode_id)
+			SELECT $1, id FROM %s WHERE season_id = $2
+			ON CONFLICT DO NOTHING
+		`, userWatchedEpisodesTable, itemEpisodesTable)
+	} else {
+		query = fmt.Sprintf(`
+			DELETE FROM %s
+			WHERE user_id = $1 AND episode_id IN (SELECT id FROM %s WHERE season_id = $2)
+		`, userWatchedEpisodesTable, itemEpisodesTable)
+	}
+	if _, err := tx.Exec(query, userID, seasonID); err != nil {
+		r.logger.Errorf("Failed to update watched season %s for user %d: %v", seasonID, userID, err)
+		return nil, fmt.Errorf("failed to update watched season: %w", err)
+	}
+
+	progress, err := r.recalculateProgress(tx, userID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return progress, nil
+}
+
+func (r *EpisodeRepository) GetItemProgress(userID int, itemID string) (*models.ItemProgress, error) {
+	query := fmt.Sprintf(`
+		SELECT p.user_id, p.item_id, p.status, p.progress, p.rating, p.started_at, p.finished_at, p.updated_at,
+		       (SELECT COUNT(*) FROM %s e JOIN %s s ON s.id = e.season_id WHERE s.item_id = p.item_id AND s.number > 0)
+		FROM %s p
+		WHERE p.user_id = $1 AND p.item_id = $2
+	`, itemEpisodesTable, itemSeasonsTable, userItemProgressTable)
+
+	var progress models.ItemProgress
+	err := r.db.QueryRow(query, userID, itemID).Scan(
+		&progress.UserID,
+		&progress.ItemID,
+		&progress.Status,
+		&progress.Progress,
+		&progress.Rating,
+		&progress.StartedAt,
+		&progress.FinishedAt,
+		&progress.UpdatedAt,
+		&progress.TotalEpisodes,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		r.logger.Errorf("Failed to get progress for user %d item %s: %v", userID, itemID, err)
+		return nil, err
+	}
+
+	return &progress, nil
+}
+
+// SetItemRating ставит или снимает (rating = nil) оценку пользователя.
+// Если записи прогресса нет, она создается в статусе planned. Новая или измененная оценка попадает в журнал активности
+func (r *EpisodeRepository) SetItemRating(userID int, itemID string, rating *int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// previous видит данные до вставки, поэтому возвращает прежнюю оценку
+	query := fmt.Sprintf(`
+		WITH previous AS (SELECT rating FROM %[1]s WHERE user_id = $1 AND item_id = $2)
+		INSERT INTO %[1]s (user_id, item_id, status, rating, updated_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (user_id, item_id) DO UPDATE SET
+			rating = EXCLUDED.rating,
+			updated_at = NOW()
+		RETURNING (SELECT rating FROM previous)
+	`, userItemProgressTable)
+
+	var previous *int
+	if err := tx.QueryRow(query, userID, itemID, models.ProgressStatusPlanned, rating).Scan(&previous); err != nil {
+		r.logger.Errorf("Failed to set rating for user %d item %s: %v", userID, itemID, err)
+		return fmt.Errorf("failed to set rating: %w", err)
+	}
+	if rating != nil && (previous == nil || *previous != *rating) {
+		payload := models.ActivityRatingPayload{Rating: *rating}
+		if err := insertActivity(tx, userID, models.ActivityItemRated, itemID, "", payload); err != nil {
+			return err
+		}
+		err := enqueueWebhookEvent(tx, userID, models.WebhookEventReviewPosted, func() (any, error) {
+			item, err := webhookItem(tx, itemID)
+			return models.WebhookReviewPayload{Item: item, Rating: rating}, err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetNextEpisodes возвращает первый непросмотренный эпизод для каждого сериала в статусе in_progress
+func (r *EpisodeRepository) GetNextEpisodes(userID int) ([]models.NextEpisode, error) {
+	query := fmt.Sprintf(`
+		SELECT item_id, item_title, item_type, cover_image, season_number,
+		       episode_id, season_id, episode_number, episode_title, air_date, runtime_minutes
+		FROM (
+			SELECT DISTINCT ON (ci.id)
+			       ci.id AS item_id, ci.title AS item_title, ci.type AS item_type, ci.cover_image,
+			       s.number AS season_number, e.id AS episode_id, e.season_id, e.number AS episode_number,
+			       e.title AS episode_title, e.air_date, e.runtime_minutes, p.updated_at
+			FROM %s p
+			JOIN %s ci ON ci.id = p.item_id
+			JOIN %s s ON s.item_id = ci.id AND s.number > 0
+			JOIN %s e ON e.season_id = s.id
+			LEFT JOIN %s w ON w.episode_id = e.id AND w.user_id = p.user_id
+			WHERE p.user_id = $1 AND p.status = $2 AND w.episode_id IS NULL
+				AND (ci.is_public = TRUE OR ci.creator_id = $1)
+			ORDER BY ci.id, s.number ASC, e.number ASC
+		) next
+		ORDER BY updated_at DESC
+	`, userItemProgressTable, collectionItemsTable, itemSeasonsTable, itemEpisodesTable, userWatchedEpisodesTable)
+
+	rows, err := r.db.Query(query, userID, models.ProgressStatusInProgress)
+	if err != nil {
+		r.logger.Errorf("Failed to get next episodes for user %d: %v", userID, err)
+		return nil, fmt.Errorf("failed to get next episodes: %w", err)
+	}
+	defer rows.Close()
+
+	result := []models.NextEpisode{}
+	for rows.Next() {
+		var next models.NextEpisode
+		err := rows.Scan(
+			&next.ItemID,
+			&next.ItemTitle,
+			&next.ItemType,
+			&next.CoverImage,
+			&next.SeasonNumber,
+			&next.Episode.ID,
+			&next.Episode.SeasonID,
+			&next.Episode.Number,
+			&next.Episode.Title,
+			&next.Episode.AirDate,
+			&next.Episode.RuntimeMinutes,
+		)
+		if err != nil {
+			r.logger.Errorf("Scan failed: %v", err)
+			return nil, fmt.Errorf("failed to scan next episode: %w", err)
+		}
+		result = append(result, next)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return result, nil
+}
+
+// recalculateProgress пересчитывает прогресс и статус пользователя по элементу на основе просмотренных эпизодов.
+// Спецвыпуски (сезон 0) не учитываются при расчете завершенности.
+// Статус dropped, выставленный пользователем, сохраняется, пока все эпизоды не просмотрены
+func (r *EpisodeRepository) recalculateProgress(tx *sql.Tx, userID int, itemID string) (*models.ItemProgress, error) {
+	countQuery := fmt.Sprintf(`
+		SELECT COUNT(e.id), COUNT(w.episode_id)
+		FROM %s e
+		JOIN %s s ON s.id = e.season_id
+		LEFT JOIN %s w ON w.episode_id = e.id AND w.user_id = $1
+		WHERE s.item_id = $2 AND s.number > 0
+	`, itemEpisodesTable, itemSeasonsTable, userWatchedEpisodesTable)
+
+	var total, watched int
+	if err := tx.QueryRow(countQuery, userID, itemID).Scan(&total, &watched); err != nil {
+		r.logger.Errorf("Failed to count episodes for item %s: %v", itemID, err)
+		return nil, fmt.Errorf("failed to count episodes: %w", err)
+	}
+
+	status := models.ProgressStatusPlanned
+	var startedAt, finishedAt *time.Time
+	now := time.Now()
+	if watched > 0 {
+		status = models.ProgressStatusInProgress
+		startedAt = &now
+	}
+	if total > 0 && watched == total {
+		status = models.ProgressStatusCompleted
+		finishedAt = &now
+	}
+
+	upsertQuery := fmt.Sprintf(`
+		WITH previous AS (SELECT status FROM %[1]s WHERE user_id = $1 AND item_id = $2)
+		INSERT INTO %[1]s (user_id, item_id, status, progress, started_at, finished_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		ON CONFLICT (user_id, item_id) DO UPDATE SET
+			status = CASE WHEN %[1]s.status = '%[2]s' AND EXCLUDED.status <> '%[3]s' THEN %[1]s.status
+			              ELSE EXCLUDED.status END,
+			progress = EXCLUDED.progress,
+			started_at = CASE WHEN EXCLUDED.started_at IS NULL THEN NULL
+			                  ELSE COALESCE(%[1]s.started_at, EXCLUDED.started_at) END,
+			finished_at = CASE WHEN EXCLUDED.finished_at IS NULL THEN NULL
+			                   ELSE COALESCE(%[1]s.finished_at, EXCLUDED.finished_at) END,
+			updated_at = NOW()
+		RETURNING user_id, item_id, status, progress, rating, started_at, finished_at, updated_at,
+			COALESCE((SELECT status FROM previous), '')
+	`, userItemProgressTable, models.ProgressStatusDropped, models.ProgressStatusCompleted)
+
+	progress := models.ItemProgress{TotalEpisodes: total}
+	var previousStatus string
+	err := tx.QueryRow(upsertQuery, userID, itemID, status, watched, startedAt, finishedAt).Scan(
+		&progress.UserID,
+		&progress.ItemID,
+		&progress.Status,
+		&progress.Progress,
+		&progress.Rating,
+		&progress.StartedAt,
+		&progress.FinishedAt,
+		&progress.UpdatedAt,
+		&previousStatus,
+	)
+	if err != nil {
+		r.logger.Errorf("Failed to update progress for user %d item %s: %v", userID, itemID, err)
+		return nil, fmt.Errorf("failed to update progress: %w", err)
+	}
+
+	if progress.Status == models.ProgressStatusCompleted && previousStatus != models.ProgressStatusCompleted {
+		if err := insertActivity(tx, userID, models.ActivityItemCompleted, itemID, "", nil); err != nil {
+			return nil, err
+		}
+	}
+	if progress.Status != previousStatus {
+		err := enqueueWebhookEvent(tx, userID, models.WebhookEventItemStatusChanged, func() (any, error) {
+			payload := models.WebhookStatusChangedPayload{
+				Status:        progress.Status,
+				Progress:      progress.Progress,
+				TotalEpisodes: total,
+			}
+			if previousStatus != "" {
+				payload.PreviousStatus = &previousStatus
+			}
+			var err error
+			payload.Item, err = webhookItem(tx, itemID)
+			return payload, err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &progress, nil
+}
+
+// queryRower - общий интерфейс для *sql.DB и *sql.Tx
+type queryRower interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+func insertEpisode(q queryRower, episode *models.Episode) (string, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (season_id, number, title, air_date, runtime_minutes)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, itemEpisodesTable)
+
+	var id string
+	err := q.QueryRow(query, episode.SeasonID, episode.Number, episode.Title, episode.AirDate, episode.RuntimeMinutes).Scan(&id)
+	return id, err
+}
+
+func scanEpisode(rows *sql.Rows, episode *models.Episode) error {
+	return rows.Scan(
+		&episode.ID,
+		&episode.SeasonID,
+		&episode.Number,
+		&episode.Title,
+		&episode.AirDate,
+		&episode.RuntimeMinutes,
+		&episode.Watched,
+	)
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/exporter"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+type ExportRepository struct {
+	db     *sql.DB
+	logger *zap.SugaredLogger
+}
+
+func NewExportPostgres(db *sql.DB, logger *zap.SugaredLogger) *ExportRepository {
+	return &ExportRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// StreamCollectionEntries передает в fn элементы коллекции в порядке добавления вместе с заметками,
+// жанрами, тегами и прогрессом пользователя userID. Строки читаются по одной, ошибка fn прерывает чтение
+func (r *ExportRepository) StreamCollectionEntries(collectionID string, userID int, fn func(entry exporter.Entry) error) error {
+	query := fmt.Sprintf(`
+		SELECT ci.id, ci.type, ci.title, ci.release_year, COALESCE(ci.description, ''), COALESCE(ci.cover_image, ''),
+			COALESCE(cia.user_review, ''), cia.added_at,
+			COALESCE(p.status, ''), COALESCE(p.progress, 0), p.rating, p.started_at, p.finished_at,
+			ARRAY(
+				SELECT g.name FROM %[4]s ig JOIN %[5]s g ON g.id = ig.genre_id
+				WHERE ig.item_id = ci.id ORDER BY g.name
+			),
+			ARRAY(
+				SELECT t.name FROM %[6]s it JOIN %[7]s t ON t.id = it.tag_id
+				WHERE it.item_id = ci.id AND it.user_id = $2 ORDER BY t.name
+			)
+		FROM %[1]s cia
+		JOIN %[2]s ci ON ci.id = cia.item_id
+		LEFT JOIN %[3]s p ON p.item_id = ci.id AND p.user_id = $2
+		WHERE cia.collection_id = $1
+		ORDER BY cia.added_at, cia.id
+	`, collectionItemsAssignmentTable, collectionItemsTable, userItemProgressTable,
+		itemGenresTable, genresTable, itemTagsTable, tagsTable)
+
+	rows, err := r.db.Query(query, collectionID, userID)
+	if err != nil {
+		r.logger.Errorf("Failed to export collection %s: %v", collectionID, err)
+		return fmt.Errorf("failed to export collection: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry exporter.Entry
+		err := rows.Scan(
+			&entry.ItemID,
+			&entry.Type,
+			&entry.Title,
+			&entry.ReleaseYear,
+			&entry.Description,
+			&entry.CoverImage,
+			&entry.Note,
+			&entry.AddedAt,
+			&entry.Status,
+			&entry.Progress,
+			&entry.Rating,
+			&entry.StartedAt,
+			&entry.FinishedAt,
+			pq.Array(&entry.Genres),
+			pq.Array(&entry.Tags),
+		)
+		if err != nil {
+			r.logger.Errorf("Scan failed: %v", err)
+			return fmt.Errorf("failed to scan export entry: %w", err)
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error during rows iteration: %w", err)
+	}
+	return nil
+}
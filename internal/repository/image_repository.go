@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"go.uber.org/zap"
+)
+
+// imageTargets - таблица и колонка со ссылкой на изображение для каждого типа загрузки
+var imageTargets = map[string]struct{ table, column string }{
+	models.ImageTargetItem:       {collectionItemsTable, "cover_image"},
+	models.ImageTargetCollection: {collectionsTable, "cover_image"},
+	models.ImageTargetAvatar:     {usersTable, "avatar_url"},
+}
+
+type ImageRepository struct {
+	db     *sql.DB
+	logger *zap.SugaredLogger
+}
+
+func NewImagePostgres(db *sql.DB, logger *zap.SugaredLogger) *ImageRepository {
+	return &ImageRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// SaveImage сохраняет загруженное изображение и проставляет ссылку на него владельцу (элементу, коллекции или пользователю).
+// Смена обложки публичного элемента сохраняется ревизией
+func (r *ImageRepository) SaveImage(image *models.Image, url string) error {
+	target, ok := imageTargets[image.TargetType]
+	if !ok {
+		return fmt.Errorf("unknown image target %q", image.TargetType)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (id, owner_id, target_type, target_id, blob_prefix, original_key, content_type, size, width, height)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING created_at
+	`, imagesTable)
+	err = tx.QueryRow(query,
+		image.ID,
+		image.OwnerID,
+		image.TargetType,
+		image.TargetID,
+		image.BlobPrefix,
+		image.OriginalKey,
+		image.ContentType,
+		image.Size,
+		image.Width,
+		image.Height,
+	).Scan(&image.CreatedAt)
+	if err != nil {
+		r.logger.Errorf("Failed to save image %s: %v", image.ID, err)
+		return fmt.Errorf("failed to save image: %w", err)
+	}
+
+	if err := r.attachImage(tx, image, target.table, target.column, url); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// attachImage проставляет ссылку на изображение. Обложка публичного элемента меняется
+// через ревизию, как и другие правки каталога, чтобы ее можно было увидеть в истории и откатить
+func (r *ImageRepository) attachImage(tx *sql.Tx, image *models.Image, table string, column string, url string) error {
+	if image.TargetType == models.ImageTargetItem {
+		var isPublic bool
+		err := tx.QueryRow(fmt.Sprintf(`SELECT is_public FROM %s WHERE id = $1`, collectionItemsTable), image.TargetID).Scan(&isPublic)
+		if err == sql.ErrNoRows {
+			return ErrNotFound
+		}
+		if err != nil {
+			r.logger.Errorf("Failed to get item %s: %v", image.TargetID, err)
+			return fmt.Errorf("failed to get item: %w", err)
+		}
+		if isPublic {
+			_, err := applyItemChanges(tx, r.logger, map[string]any{"cover_image": url}, &models.ItemRevision{
+				ItemID:   image.TargetID,
+				AuthorID: image.OwnerID,
+			})
+			return err
+		}
+	}
+
+	result, err := tx.Exec(fmt.Sprintf(`UPDATE %s SET %s = $1 WHERE id = $2`, table, column), url, image.TargetID)
+	if err != nil {
+		r.logger.Errorf("Failed to attach image %s to %s %s: %v", image.ID, image.TargetType, image.TargetID, err)
+		return fmt.Errorf("failed to attach image: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *ImageRepository) GetImageByID(id string) (*models.Image, error) {
+	query := fmt.Sprintf(`
+		SELECT id, owner_id, target_type, target_id, blob_prefix, original_key, content_type, size, width, height, created_at
+		FROM %s WHERE id = $1
+	`, imagesTable)
+
+	var image models.Image
+	err := r.db.QueryRow(query, id).Scan(
+		&image.ID,
+		&image.OwnerID,
+		&image.TargetType,
+		&image.TargetID,
+		&image.BlobPrefix,
+		&image.OriginalKey,
+		&image.ContentType,
+		&image.Size,
+		&image.Width,
+		&image.Height,
+		&image.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		r.logger.Errorf("Failed to get image %s: %v", id, err)
+		return nil, err
+	}
+	return &image, nil
+}
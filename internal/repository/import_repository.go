@@ -0,0 +1,528 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/importer"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+const importJobColumns = `id, user_id, format, filename, create_custom, status, total_rows, processed_rows,
+	matched_rows, created_rows, unmatched_rows, failed_rows, resolved_rows, error, created_at, started_at, finished_at`
+
+const importRowColumns = "id, job_id, line, data, status, match_method, item_id, error"
+
+// importRowCounters - счетчик задачи для каждого итогового статуса строки
+var importRowCounters = map[string]string{
+	models.ImportRowMatched:   "matched_rows",
+	models.ImportRowCreated:   "created_rows",
+	models.ImportRowUnmatched: "unmatched_rows",
+	models.ImportRowFailed:    "failed_rows",
+	models.ImportRowResolved:  "resolved_rows",
+}
+
+type ImportRepository struct {
+	db     *sql.DB
+	logger *zap.SugaredLogger
+}
+
+func NewImportPostgres(db *sql.DB, logger *zap.SugaredLogger) *ImportRepository {
+	return &ImportRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateJob создает задачу и сохраняет все строки выгрузки со статусом pending
+func (r *ImportRepository) CreateJob(job *models.ImportJob, records []importer.Record) (*models.ImportJob, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	created, err := scanImportJob(tx.QueryRow(fmt.Sprintf(`
+		INSERT INTO %s (user_id, format, filename, create_custom, total_rows)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING %s
+	`, importJobsTable, importJobColumns), job.UserID, job.Format, job.Filename, job.CreateCustom, len(records)))
+	if err != nil {
+		r.logger.Errorf("Failed to create import job for user %d: %v", job.UserID, err)
+		return nil, fmt.Errorf("failed to create import job: %w", err)
+	}
+
+	stmt, err := tx.Prepare(pq.CopyIn(importJobRowsTable, "job_id", "line", "data"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare import rows copy: %w", err)
+	}
+	for _, record := range records {
+		data, err := json.Marshal(record)
+		if err != nil {
+			stmt.Close()
+			return nil, fmt.Errorf("failed to marshal import row: %w", err)
+		}
+		if _, err := stmt.Exec(created.ID, record.Line, string(data)); err != nil {
+			stmt.Close()
+			return nil, fmt.Errorf("failed to copy import row: %w", err)
+		}
+	}
+	if _, err := stmt.Exec(); err != nil {
+		stmt.Close()
+		r.logger.Errorf("Failed to save rows of import job %s: %v", created.ID, err)
+		return nil, fmt.Errorf("failed to save import rows: %w", err)
+	}
+	if err := stmt.Close(); err != nil {
+		return nil, fmt.Errorf("failed to save import rows: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return created, nil
+}
+
+func (r *ImportRepository) GetJob(userID int, jobID string) (*models.ImportJob, error) {
+	job, err := scanImportJob(r.db.QueryRow(fmt.Sprintf(`
+		SELECT %s FROM %s WHERE id = $1 AND user_id = $2
+	`, importJobColumns, importJobsTable), jobID, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		r.logger.Errorf("Failed to get import job %s: %v", jobID, err)
+		return nil, fmt.Errorf("failed to get import job: %w", err)
+	}
+	return job, nil
+}
+
+func (r *ImportRepository) GetJobs(userID int, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.ImportJob], error) {
+	var total int64
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE user_id = $1`, importJobsTable)
+	if err := r.db.QueryRow(countQuery, userID).Scan(&total); err != nil {
+		r.logger.Errorf("Failed to count import jobs for user %d: %v", userID, err)
+		return nil, fmt.Errorf("failed to count import jobs: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`, importJobColumns, importJobsTable)
+
+	rows, err := r.db.Query(query, userID, req.Limit(), req.Offset())
+	if err != nil {
+		r.logger.Errorf("Failed to get import jobs for user %d: %v", userID, err)
+		return nil, fmt.Errorf("failed to get import jobs: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := []models.ImportJob{}
+	for rows.Next() {
+		job, err := scanImportJob(rows)
+		if err != nil {
+			r.logger.Errorf("Scan failed: %v", err)
+			return nil, fmt.Errorf("failed to scan import job: %w", err)
+		}
+		jobs = append(jobs, *job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return &pagination.PaginatedResponse[models.ImportJob]{
+		Data:       jobs,
+		Pagination: req.ToPagination(total),
+	}, nil
+}
+
+// ClaimJob берет в работу самую старую ожидающую задачу или задачу, обработчик которой
+// не подавал признаков жизни с staleBefore. Возвращает ErrNotFound, если очередь пуста
+func (r *ImportRepository) ClaimJob(staleBefore time.Time) (*models.ImportJob, error) {
+	job, err := scanImportJob(r.db.QueryRow(fmt.Sprintf(`
+		UPDATE %[1]s SET
+			status = $1,
+			started_at = COALESCE(started_at, NOW()),
+			heartbeat_at = NOW()
+		WHERE id = (
+			SELECT id FROM %[1]s
+			WHERE status = $2 OR (status = $1 AND heartbeat_at < $3)
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING %[2]s
+	`, importJobsTable, importJobColumns), models.ImportStatusRunning, models.ImportStatusPending, staleBefore))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		r.logger.Errorf("Failed to claim import job: %v", err)
+		return nil, fmt.Errorf("failed to claim import job: %w", err)
+	}
+	return job, nil
+}
+
+// FinishJob завершает задачу и уведомляет пользователя
+func (r *ImportRepository) FinishJob(jobID string, status string, message *string) (*models.ImportJob, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	job, err := scanImportJob(tx.QueryRow(fmt.Sprintf(`
+		UPDATE %s SET status = $2, error = $3, finished_at = NOW(), heartbeat_at = NOW()
+		WHERE id = $1
+		RETURNING %s
+	`, importJobsTable, importJobColumns), jobID, status, message))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		r.logger.Errorf("Failed to finish import job %s: %v", jobID, err)
+		return nil, fmt.Errorf("failed to finish import job: %w", err)
+	}
+
+	payload := models.ImportFinishedNotificationPayload{
+		JobID:         job.ID,
+		Format:        job.Format,
+		Status:        job.Status,
+		MatchedRows:   job.MatchedRows,
+		CreatedRows:   job.CreatedRows,
+		UnmatchedRows: job.UnmatchedRows,
+		FailedRows:    job.FailedRows,
+	}
+	if _, err := insertNotification(tx, job.UserID, models.NotificationImportFinished, payload); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return job, nil
+}
+
+func (r *ImportRepository) GetPendingRows(jobID string, limit int) ([]models.ImportRow, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s
+		WHERE job_id = $1 AND status = $2
+		ORDER BY id
+		LIMIT $3
+	`, importRowColumns, importJobRowsTable)
+
+	rows, err := r.db.Query(query, jobID, models.ImportRowPending, limit)
+	if err != nil {
+		r.logger.Errorf("Failed to get pending rows of import job %s: %v", jobID, err)
+		return nil, fmt.Errorf("failed to get import rows: %w", err)
+	}
+	defer rows.Close()
+
+	return scanImportRows(rows)
+}
+
+// GetRows - строки задачи с указанными статусами
+func (r *ImportRepository) GetRows(jobID string, statuses []string, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.ImportRow], error) {
+	var total int64
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE job_id = $1 AND status = ANY($2)`, importJobRowsTable)
+	if err := r.db.QueryRow(countQuery, jobID, pq.Array(statuses)).Scan(&total); err != nil {
+		r.logger.Errorf("Failed to count rows of import job %s: %v", jobID, err)
+		return nil, fmt.Errorf("failed to count import rows: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s
+		WHERE job_id = $1 AND status = ANY($2)
+		ORDER BY line, id
+		LIMIT $3 OFFSET $4
+	`, importRowColumns, importJobRowsTable)
+
+	rows, err := r.db.Query(query, jobID, pq.Array(statuses), req.Limit(), req.Offset())
+	if err != nil {
+		r.logger.Errorf("Failed to get rows of import job %s: %v", jobID, err)
+		return nil, fmt.Errorf("failed to get import rows: %w", err)
+	}
+	defer rows.Close()
+
+	importRows, err := scanImportRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pagination.PaginatedResponse[models.ImportRow]{
+		Data:       importRows,
+		Pagination: req.ToPagination(total),
+	}, nil
+}
+
+func (r *ImportRepository) GetRow(jobID string, rowID int) (*models.ImportRow, error) {
+	row, err := scanImportRow(r.db.QueryRow(fmt.Sprintf(`
+		SELECT %s FROM %s WHERE id = $1 AND job_id = $2
+	`, importRowColumns, importJobRowsTable), rowID, jobID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		r.logger.Errorf("Failed to get import row %d: %v", rowID, err)
+		return nil, fmt.Errorf("failed to get import row: %w", err)
+	}
+	return row, nil
+}
+
+// FindItemByExternalIDs ищет доступный пользователю элемент типа itemType по любому из внешних ID:
+// среди ID провайдеров метаданных и ID, запомненных прошлыми импортами этого пользователя
+func (r *ImportRepository) FindItemByExternalIDs(externalIDs map[string]string, itemType string, userID int) (string, error) {
+	providers := make([]string, 0, len(externalIDs))
+	ids := make([]string, 0, len(externalIDs))
+	for provider, id := range externalIDs {
+		providers = append(providers, provider)
+		ids = append(ids, id)
+	}
+
+	query := fmt.Sprintf(`
+		WITH ids AS (SELECT * FROM unnest($1::text[], $2::text[]) AS ids(provider, external_id))
+		SELECT e.item_id
+		FROM (
+			SELECT item_id, created_at FROM %[1]s WHERE (provider, external_id) IN (SELECT * FROM ids)
+			UNION ALL
+			SELECT item_id, created_at FROM %[2]s WHERE user_id = $4 AND (provider, external_id) IN (SELECT * FROM ids)
+		) e
+		JOIN %[3]s ci ON ci.id = e.item_id
+		WHERE ci.type = $3
+			AND (ci.is_public = TRUE OR ci.creator_id = $4)
+		ORDER BY ci.is_custom, e.created_at
+		LIMIT 1
+	`, itemExternalIDsTable, importExternalIDsTable, collectionItemsTable)
+
+	var itemID string
+	err := r.db.QueryRow(query, pq.Array(providers), pq.Array(ids), itemType, userID).Scan(&itemID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", ErrNotFound
+		}
+		r.logger.Errorf("Failed to find item by external ids: %v", err)
+		return "", fmt.Errorf("failed to find item by external ids: %w", err)
+	}
+	return itemID, nil
+}
+
+// EnsureCollection возвращает коллекцию пользователя с таким названием и типом, создавая ее при необходимости
+func (r *ImportRepository) EnsureCollection(userID int, name string, itemType string) (string, error) {
+	var id string
+	err := r.db.QueryRow(fmt.Sprintf(`
+		SELECT id FROM %s WHERE user_id = $1 AND lower(name) = lower($2) AND type = $3
+		ORDER BY created_at
+		LIMIT 1
+	`, collectionsTable), userID, name, itemType).Scan(&id)
+	if err == nil {
+		return id, nil
+	}
+	if err != sql.ErrNoRows {
+		r.logger.Errorf("Failed to find collection %q of user %d: %v", name, userID, err)
+		return "", fmt.Errorf("failed to find collection: %w", err)
+	}
+
+	err = r.db.QueryRow(fmt.Sprintf(`
+		INSERT INTO %s (user_id, name, type, description, is_public) VALUES ($1, $2, $3, '', false)
+		RETURNING id
+	`, collectionsTable), userID, name, itemType).Scan(&id)
+	if err != nil {
+		r.logger.Errorf("Failed to create collection %q for user %d: %v", name, userID, err)
+		return "", fmt.Errorf("failed to create collection: %w", err)
+	}
+	return id, nil
+}
+
+// SaveRowResult сохраняет итог строки: кладет элемент в коллекции, переносит прогресс, оценку и отзыв,
+// запоминает внешние ID строки для следующих импортов пользователя и обновляет счетчики задачи. Все в одной транзакции.
+// Возвращает ErrNothingChanged, если строку уже обработали
+func (r *ImportRepository) SaveRowResult(userID int, row *models.ImportRow, result models.ImportRowResult) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if result.ItemID != nil {
+		if err := r.applyRecord(tx, userID, *result.ItemID, row.Record, result); err != nil {
+			return err
+		}
+	}
+
+	var matchMethod *string
+	if result.MatchMethod != "" {
+		matchMethod = &result.MatchMethod
+	}
+	// Условие на прежний статус защищает счетчики от повторной обработки строки
+	res, err := tx.Exec(fmt.Sprintf(`
+		UPDATE %s SET status = $2, match_method = $3, item_id = $4, error = $5 WHERE id = $1 AND status = $6
+	`, importJobRowsTable), row.ID, result.Status, matchMethod, result.ItemID, result.Error, row.Status)
+	if err != nil {
+		r.logger.Errorf("Failed to save import row %d: %v", row.ID, err)
+		return fmt.Errorf("failed to save import row: %w", err)
+	}
+	if affected, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to save import row: %w", err)
+	} else if affected == 0 {
+		return ErrNothingChanged
+	}
+
+	counter, ok := importRowCounters[result.Status]
+	if !ok {
+		return fmt.Errorf("unknown import row status %q", result.Status)
+	}
+	// Новая строка увеличивает processed_rows, строка, сопоставленная повторно, уходит из прежнего счетчика
+	counters := fmt.Sprintf("%[1]s = %[1]s + 1, processed_rows = processed_rows + 1", counter)
+	if row.Status != models.ImportRowPending {
+		previous, ok := importRowCounters[row.Status]
+		if !ok {
+			return fmt.Errorf("unknown import row status %q", row.Status)
+		}
+		counters = fmt.Sprintf("%[1]s = %[1]s + 1, %[2]s = %[2]s - 1", counter, previous)
+	}
+	_, err = tx.Exec(fmt.Sprintf(`
+		UPDATE %s SET %s, heartbeat_at = NOW() WHERE id = $1
+	`, importJobsTable, counters), row.JobID)
+	if err != nil {
+		r.logger.Errorf("Failed to update counters of import job %s: %v", row.JobID, err)
+		return fmt.Errorf("failed to update import job: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	row.Status = result.Status
+	row.ItemID = result.ItemID
+	row.MatchMethod = matchMethod
+	row.Error = result.Error
+	return nil
+}
+
+func (r *ImportRepository) applyRecord(tx *sql.Tx, userID int, itemID string, record importer.Record, result models.ImportRowResult) error {
+	var review *string
+	if record.Review != "" {
+		review = &record.Review
+	}
+	for _, collectionID := range result.CollectionIDs {
+		_, err := tx.Exec(fmt.Sprintf(`
+			INSERT INTO %[1]s (collection_id, item_id, user_review) VALUES ($1, $2, $3)
+			ON CONFLICT (collection_id, item_id) DO UPDATE SET
+				user_review = COALESCE(%[1]s.user_review, EXCLUDED.user_review)
+		`, collectionItemsAssignmentTable), collectionID, itemID, review)
+		if err != nil {
+			r.logger.Errorf("Failed to add imported item %s to collection %s: %v", itemID, collectionID, err)
+			return fmt.Errorf("failed to add item to collection: %w", err)
+		}
+	}
+
+	// Статус и оценка из выгрузки заменяют текущие, даты и прогресс только дополняют
+	status := record.Status
+	if status == "" && record.Rating != nil {
+		status = models.ProgressStatusCompleted
+	}
+	if status != "" {
+		_, err := tx.Exec(fmt.Sprintf(`
+			INSERT INTO %[1]s (user_id, item_id, status, progress, rating, started_at, finished_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+			ON CONFLICT (user_id, item_id) DO UPDATE SET
+				status = EXCLUDED.status,
+				progress = GREATEST(%[1]s.progress, EXCLUDED.progress),
+				rating = COALESCE(EXCLUDED.rating, %[1]s.rating),
+				started_at = COALESCE(%[1]s.started_at, EXCLUDED.started_at),
+				finished_at = COALESCE(EXCLUDED.finished_at, %[1]s.finished_at),
+				updated_at = NOW()
+		`, userItemProgressTable), userID, itemID, status, record.Progress, record.Rating, record.StartedAt, record.FinishedAt)
+		if err != nil {
+			r.logger.Errorf("Failed to save imported progress of item %s: %v", itemID, err)
+			return fmt.Errorf("failed to save progress: %w", err)
+		}
+	}
+
+	// ID из файла пользователя не закрепляются за элементом в item_external_ids: по ним элементы каталога
+	// обновляются из провайдеров метаданных, и чужая выгрузка могла бы подменить данные каталога
+	for provider, externalID := range result.SourceIDs {
+		_, err := tx.Exec(fmt.Sprintf(`
+			INSERT INTO %s (user_id, provider, external_id, item_id) VALUES ($1, $2, $3, $4)
+			ON CONFLICT (user_id, provider, external_id) DO UPDATE SET item_id = EXCLUDED.item_id
+		`, importExternalIDsTable), userID, provider, externalID, itemID)
+		if err != nil {
+			r.logger.Errorf("Failed to save external id %s/%s: %v", provider, externalID, err)
+			return fmt.Errorf("failed to save external id: %w", err)
+		}
+	}
+	return nil
+}
+
+func scanImportJob(row rowScanner) (*models.ImportJob, error) {
+	var job models.ImportJob
+	err := row.Scan(
+		&job.ID,
+		&job.UserID,
+		&job.Format,
+		&job.Filename,
+		&job.CreateCustom,
+		&job.Status,
+		&job.TotalRows,
+		&job.ProcessedRows,
+		&job.MatchedRows,
+		&job.CreatedRows,
+		&job.UnmatchedRows,
+		&job.FailedRows,
+		&job.ResolvedRows,
+		&job.Error,
+		&job.CreatedAt,
+		&job.StartedAt,
+		&job.FinishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	job.Progress = 100
+	if job.TotalRows > 0 {
+		job.Progress = float64(job.ProcessedRows*1000/job.TotalRows) / 10
+	}
+	return &job, nil
+}
+
+func scanImportRow(row rowScanner) (*models.ImportRow, error) {
+	var importRow models.ImportRow
+	var data []byte
+	err := row.Scan(
+		&importRow.ID,
+		&importRow.JobID,
+		&importRow.Line,
+		&data,
+		&importRow.Status,
+		&importRow.MatchMethod,
+		&importRow.ItemID,
+		&importRow.Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &importRow.Record); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal import row: %w", err)
+	}
+	return &importRow, nil
+}
+
+func scanImportRows(rows *sql.Rows) ([]models.ImportRow, error) {
+	importRows := []models.ImportRow{}
+	for rows.Next() {
+		row, err := scanImportRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan import row: %w", err)
+		}
+		importRows = append(importRows, *row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+	return importRows, nil
+}
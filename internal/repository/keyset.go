@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
+)
+
+// keysetColumn - колонка ключа сортировки. Выражение не должно давать NULL,
+// иначе сравнение кортежей в условии keyset перестает работать
+type keysetColumn struct {
+	expr string
+	cast string // тип, к которому приводится значение из курсора
+}
+
+// keyset - сортировка по кортежу колонок в одном направлении, последняя колонка - уникальный id
+type keyset struct {
+	scope   string
+	columns []keysetColumn
+	desc    bool
+}
+
+// orderBy - ORDER BY для выборки. backward разворачивает порядок для запроса предыдущей страницы
+func (k keyset) orderBy(backward bool) string {
+	direction := "ASC"
+	if k.desc != backward {
+		direction = "DESC"
+	}
+
+	parts := make([]string, len(k.columns))
+	for i, column := range k.columns {
+		parts[i] = column.expr + " " + direction
+	}
+	return strings.Join(parts, ", ")
+}
+
+// condition - условие "строки после курсора" в направлении курсора
+func (k keyset) condition(f *queryFilter, cursor *pagination.Cursor) (string, error) {
+	if cursor.Scope != k.scope || len(cursor.Keys) != len(k.columns) {
+		return "", pagination.ErrInvalidCursor
+	}
+
+	operator := ">"
+	if k.desc != cursor.Backward {
+		operator = "<"
+	}
+
+	exprs := make([]string, len(k.columns))
+	values := make([]string, len(k.columns))
+	for i, column := range k.columns {
+		exprs[i] = column.expr
+		values[i] = f.arg(cursor.Keys[i]) + "::" + column.cast
+	}
+	return fmt.Sprintf("(%s) %s (%s)", strings.Join(exprs, ", "), operator, strings.Join(values, ", ")), nil
+}
+
+// apply добавляет условие курсора, если он передан, и возвращает ORDER BY
+func (k keyset) apply(f *queryFilter, req pagination.CursorRequest) (string, error) {
+	if cursor := req.Cursor(); cursor != nil {
+		condition, err := k.condition(f, cursor)
+		if err != nil {
+			return "", err
+		}
+		f.where(condition)
+	}
+	return k.orderBy(req.Backward()), nil
+}
@@ -0,0 +1,318 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+const externalIDColumns = "item_id, provider, external_id, imported_by, created_at, refreshed_at, refresh_error"
+
+type MetadataRepository struct {
+	db     *sql.DB
+	logger *zap.SugaredLogger
+}
+
+func NewMetadataPostgres(db *sql.DB, logger *zap.SugaredLogger) *MetadataRepository {
+	return &MetadataRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// ImportItem создает элемент каталога вместе с внешним ID и жанрами, которые уже есть в справочнике.
+// Если запись провайдера уже импортирована, возвращается ErrAlreadyExists
+func (r *MetadataRepository) ImportItem(item *models.CollectionItem, externalID models.ExternalID, genres []string) (string, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var id string
+	err = tx.QueryRow(fmt.Sprintf(`
+		INSERT INTO %s (type, title, description, cover_image, release_year, is_custom, is_public, creator_id, moderation_status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`, collectionItemsTable),
+		item.Type,
+		item.Title,
+		item.Description,
+		item.CoverImage,
+		item.ReleaseYear,
+		item.IsCustom,
+		item.IsPublic,
+		item.CreatorID,
+		item.ModerationStatus,
+	).Scan(&id)
+	if err != nil {
+		r.logger.Errorf("Failed to create imported item %q: %v", item.Title, err)
+		return "", fmt.Errorf("failed to create item: %w", err)
+	}
+
+	_, err = tx.Exec(fmt.Sprintf(`
+		INSERT INTO %s (provider, external_id, item_id, imported_by) VALUES ($1, $2, $3, $4)
+	`, itemExternalIDsTable), externalID.Provider, externalID.ExternalID, id, externalID.ImportedBy)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return "", ErrAlreadyExists
+		}
+		r.logger.Errorf("Failed to save external id %s/%s: %v", externalID.Provider, externalID.ExternalID, err)
+		return "", fmt.Errorf("failed to save external id: %w", err)
+	}
+
+	if len(genres) > 0 {
+		names := make([]string, len(genres))
+		for i, genre := range genres {
+			names[i] = strings.ToLower(strings.TrimSpace(genre))
+		}
+		_, err = tx.Exec(fmt.Sprintf(`
+			INSERT INTO %s (item_id, genre_id)
+			SELECT $1, id FROM %s WHERE lower(name) = ANY($2) OR slug = ANY($2)
+			ON CONFLICT DO NOTHING
+		`, itemGenresTable, genresTable), id, pq.Array(names))
+		if err != nil {
+			r.logger.Errorf("Failed to link genres of imported item %s: %v", id, err)
+			return "", fmt.Errorf("failed to link genres: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return id, nil
+}
+
+func (r *MetadataRepository) GetItemIDByExternalID(provider string, externalID string) (string, error) {
+	query := fmt.Sprintf(`SELECT item_id FROM %s WHERE provider = $1 AND external_id = $2`, itemExternalIDsTable)
+
+	var itemID string
+	if err := r.db.QueryRow(query, provider, externalID).Scan(&itemID); err != nil {
+		if err == sql.ErrNoRows {
+			return "", ErrNotFound
+		}
+		r.logger.Errorf("Failed to get item by external id %s/%s: %v", provider, externalID, err)
+		return "", err
+	}
+	return itemID, nil
+}
+
+// GetItemIDsByExternalIDs - уже импортированные записи провайдера: внешний ID -> ID элемента
+func (r *MetadataRepository) GetItemIDsByExternalIDs(provider string, externalIDs []string) (map[string]string, error) {
+	query := fmt.Sprintf(`
+		SELECT external_id, item_id FROM %s WHERE provider = $1 AND external_id = ANY($2)
+	`, itemExternalIDsTable)
+
+	rows, err := r.db.Query(query, provider, pq.Array(externalIDs))
+	if err != nil {
+		r.logger.Errorf("Failed to get items by external ids of %s: %v", provider, err)
+		return nil, fmt.Errorf("failed to get items by external ids: %w", err)
+	}
+	defer rows.Close()
+
+	items := make(map[string]string, len(externalIDs))
+	for rows.Next() {
+		var externalID, itemID string
+		if err := rows.Scan(&externalID, &itemID); err != nil {
+			return nil, fmt.Errorf("failed to scan external id: %w", err)
+		}
+		items[externalID] = itemID
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+	return items, nil
+}
+
+func (r *MetadataRepository) GetExternalIDs(itemID string) ([]models.ExternalID, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s WHERE item_id = $1
+		ORDER BY created_at, provider, external_id
+	`, externalIDColumns, itemExternalIDsTable)
+
+	rows, err := r.db.Query(query, itemID)
+	if err != nil {
+		r.logger.Errorf("Failed to get external ids of item %s: %v", itemID, err)
+		return nil, fmt.Errorf("failed to get external ids: %w", err)
+	}
+	defer rows.Close()
+
+	return scanExternalIDs(rows)
+}
+
+// GetStaleExternalIDs - записи каталогов providers, которые не обновлялись с before. У элемента с несколькими
+// внешними ID обновляется только самый ранний, чтобы каталоги не перезаписывали данные друг друга.
+// ID из импортированных выгрузок (imdb, goodreads и т.п.) не относятся к каталогам и не учитываются
+func (r *MetadataRepository) GetStaleExternalIDs(providers []string, before time.Time, limit int) ([]models.ExternalID, error) {
+	query := fmt.Sprintf(`
+		SELECT %[1]s
+		FROM %[2]s e
+		WHERE e.refreshed_at < $1 AND e.provider = ANY($2) AND NOT EXISTS (
+			SELECT 1 FROM %[2]s p
+			WHERE p.item_id = e.item_id AND p.provider = ANY($2)
+				AND (p.created_at, p.provider, p.external_id) < (e.created_at, e.provider, e.external_id)
+		)
+		ORDER BY e.refreshed_at
+		LIMIT $3
+	`, externalIDColumns, itemExternalIDsTable)
+
+	rows, err := r.db.Query(query, before, pq.Array(providers), limit)
+	if err != nil {
+		r.logger.Errorf("Failed to get stale external ids: %v", err)
+		return nil, fmt.Errorf("failed to get stale external ids: %w", err)
+	}
+	defer rows.Close()
+
+	return scanExternalIDs(rows)
+}
+
+// ApplyRefresh применяет данные каталога к элементу в обход закрепленных полей и сохраняет ревизию
+// с именем провайдера. Возвращает ревизию (nil, если ничего не изменилось) и пропущенные поля
+func (r *MetadataRepository) ApplyRefresh(externalID models.ExternalID, changes map[string]any) (*models.ItemRevision, []string, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	locked, err := r.lockedFields(tx, externalID.ItemID)
+	if err != nil {
+		return nil, nil, err
+	}
+	skipped := []string{}
+	for _, field := range locked {
+		if _, ok := changes[field]; ok {
+			delete(changes, field)
+			skipped = append(skipped, field)
+		}
+	}
+
+	revision, err := applyItemChanges(tx, r.logger, changes, &models.ItemRevision{
+		ItemID:   externalID.ItemID,
+		Provider: &externalID.Provider,
+	})
+	if errors.Is(err, ErrNothingChanged) {
+		revision, err = nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, err = tx.Exec(fmt.Sprintf(`
+		UPDATE %s SET refreshed_at = NOW(), refresh_error = NULL WHERE provider = $1 AND external_id = $2
+	`, itemExternalIDsTable), externalID.Provider, externalID.ExternalID)
+	if err != nil {
+		r.logger.Errorf("Failed to mark %s/%s refreshed: %v", externalID.Provider, externalID.ExternalID, err)
+		return nil, nil, fmt.Errorf("failed to mark external id refreshed: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return revision, skipped, nil
+}
+
+// MarkRefreshFailed откладывает следующую попытку до очередного цикла и сохраняет ошибку
+func (r *MetadataRepository) MarkRefreshFailed(provider string, externalID string, message string) error {
+	_, err := r.db.Exec(fmt.Sprintf(`
+		UPDATE %s SET refreshed_at = NOW(), refresh_error = $3 WHERE provider = $1 AND external_id = $2
+	`, itemExternalIDsTable), provider, externalID, message)
+	if err != nil {
+		r.logger.Errorf("Failed to save refresh error of %s/%s: %v", provider, externalID, err)
+		return fmt.Errorf("failed to save refresh error: %w", err)
+	}
+	return nil
+}
+
+func (r *MetadataRepository) GetLockedFields(itemID string) ([]models.ItemFieldLock, error) {
+	query := fmt.Sprintf(`
+		SELECT item_id, field, locked_by, locked_at FROM %s WHERE item_id = $1 ORDER BY field
+	`, itemLockedFieldsTable)
+
+	rows, err := r.db.Query(query, itemID)
+	if err != nil {
+		r.logger.Errorf("Failed to get locked fields of item %s: %v", itemID, err)
+		return nil, fmt.Errorf("failed to get locked fields: %w", err)
+	}
+	defer rows.Close()
+
+	locks := []models.ItemFieldLock{}
+	for rows.Next() {
+		var lock models.ItemFieldLock
+		if err := rows.Scan(&lock.ItemID, &lock.Field, &lock.LockedBy, &lock.LockedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan locked field: %w", err)
+		}
+		locks = append(locks, lock)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+	return locks, nil
+}
+
+func (r *MetadataRepository) LockField(itemID string, field string, userID int) (*models.ItemFieldLock, error) {
+	lock := &models.ItemFieldLock{ItemID: itemID, Field: field}
+	err := r.db.QueryRow(fmt.Sprintf(`
+		INSERT INTO %s (item_id, field, locked_by) VALUES ($1, $2, $3)
+		ON CONFLICT (item_id, field) DO UPDATE SET locked_by = EXCLUDED.locked_by, locked_at = NOW()
+		RETURNING locked_by, locked_at
+	`, itemLockedFieldsTable), itemID, field, userID).Scan(&lock.LockedBy, &lock.LockedAt)
+	if err != nil {
+		r.logger.Errorf("Failed to lock field %s of item %s: %v", field, itemID, err)
+		return nil, fmt.Errorf("failed to lock field: %w", err)
+	}
+	return lock, nil
+}
+
+func (r *MetadataRepository) UnlockField(itemID string, field string) error {
+	res, err := r.db.Exec(fmt.Sprintf(`DELETE FROM %s WHERE item_id = $1 AND field = $2`, itemLockedFieldsTable), itemID, field)
+	if err != nil {
+		r.logger.Errorf("Failed to unlock field %s of item %s: %v", field, itemID, err)
+		return fmt.Errorf("failed to unlock field: %w", err)
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *MetadataRepository) lockedFields(tx *sql.Tx, itemID string) ([]string, error) {
+	rows, err := tx.Query(fmt.Sprintf(`SELECT field FROM %s WHERE item_id = $1`, itemLockedFieldsTable), itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get locked fields: %w", err)
+	}
+	defer rows.Close()
+
+	fields := []string{}
+	for rows.Next() {
+		var field string
+		if err := rows.Scan(&field); err != nil {
+			return nil, fmt.Errorf("failed to scan locked field: %w", err)
+		}
+		fields = append(fields, field)
+	}
+	return fields, rows.Err()
+}
+
+func scanExternalIDs(rows *sql.Rows) ([]models.ExternalID, error) {
+	ids := []models.ExternalID{}
+	for rows.Next() {
+		var id models.ExternalID
+		err := rows.Scan(&id.ItemID, &id.Provider, &id.ExternalID, &id.ImportedBy, &id.CreatedAt, &id.RefreshedAt, &id.RefreshError)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan external id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+	return ids, nil
+}
@@ -0,0 +1,270 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
+	"go.uber.org/zap"
+)
+
+type ModerationRepository struct {
+	db     *sql.DB
+	logger *zap.SugaredLogger
+}
+
+func NewModerationPostgres(db *sql.DB, logger *zap.SugaredLogger) *ModerationRepository {
+	return &ModerationRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetQueue - очередь элементов на проверку, сначала самые старые
+func (r *ModerationRepository) GetQueue(req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.CollectionItem], error) {
+	var total int64
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE moderation_status = $1`, collectionItemsTable)
+	if err := r.db.QueryRow(countQuery, models.ModerationStatusPending).Scan(&total); err != nil {
+		r.logger.Errorf("Failed to count moderation queue: %v", err)
+		return nil, fmt.Errorf("failed to count moderation queue: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s ci
+		WHERE ci.moderation_status = $1
+		ORDER BY ci.updated_at ASC, ci.id
+		LIMIT $2 OFFSET $3
+	`, collectionItemColumns, collectionItemsTable)
+
+	rows, err := r.db.Query(query, models.ModerationStatusPending, req.Limit(), req.Offset())
+	if err != nil {
+		r.logger.Errorf("Failed to get moderation queue: %v", err)
+		return nil, fmt.Errorf("failed to get moderation queue: %w", err)
+	}
+	defer rows.Close()
+
+	items := []models.CollectionItem{}
+	for rows.Next() {
+		var item models.CollectionItem
+		if err := scanCollectionItem(rows, &item); err != nil {
+			r.logger.Errorf("Scan failed: %v", err)
+			return nil, fmt.Errorf("failed to scan collection item: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return &pagination.PaginatedResponse[models.CollectionItem]{
+		Data:       items,
+		Pagination: req.ToPagination(total),
+	}, nil
+}
+
+// CreateSubmittedItem создает элемент пользователя сразу в статусе pending и пишет отправку в журнал
+// одной транзакцией: элемент не может остаться созданным, но не попавшим в очередь модерации
+func (r *ModerationRepository) CreateSubmittedItem(item *models.CollectionItem, userID int) (string, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	item.ModerationStatus = models.ModerationStatusPending
+	id, err := insertCollectionItem(tx, item)
+	if err != nil {
+		r.logger.Errorf("Failed to create item %q for review: %v", item.Title, err)
+		return "", fmt.Errorf("failed to create item: %w", err)
+	}
+
+	if _, err := insertModerationDecision(tx, id, &userID, models.ModerationDecisionSubmitted, nil); err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return id, nil
+}
+
+// SubmitItem отправляет элемент на проверку и пишет это в журнал
+func (r *ModerationRepository) SubmitItem(itemID string, userID int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := fmt.Sprintf(`
+		UPDATE %s SET moderation_status = $2, updated_at = NOW()
+		WHERE id = $1 AND moderation_status <> $2
+	`, collectionItemsTable)
+	result, err := tx.Exec(query, itemID, models.ModerationStatusPending)
+	if err != nil {
+		r.logger.Errorf("Failed to submit item %s: %v", itemID, err)
+		return fmt.Errorf("failed to submit item: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrNotFound
+	}
+
+	if _, err := insertModerationDecision(tx, itemID, &userID, models.ModerationDecisionSubmitted, nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// UpdateDraftItem меняет поля своего элемента автора, пока он не на проверке и не опубликован.
+// Возвращает ErrNotFound, если элемент уже отправлен на проверку или стал публичным
+func (r *ModerationRepository) UpdateDraftItem(itemID string, userID int, changes map[string]any) error {
+	fields := make([]string, 0, len(changes))
+	for field := range changes {
+		if _, ok := editableItemFields[field]; ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	if len(fields) == 0 {
+		return ErrNothingChanged
+	}
+
+	f := &queryFilter{}
+	sets := make([]string, 0, len(fields))
+	for _, field := range fields {
+		sets = append(sets, fmt.Sprintf("%s = %s", editableItemFields[field], f.arg(changes[field])))
+	}
+	query := fmt.Sprintf(`
+		UPDATE %s SET %s, updated_at = NOW()
+		WHERE id = %s AND creator_id = %s AND is_public = FALSE AND moderation_status <> %s
+	`, collectionItemsTable, strings.Join(sets, ", "), f.arg(itemID), f.arg(userID), f.arg(models.ModerationStatusPending))
+
+	result, err := r.db.Exec(query, f.args...)
+	if err != nil {
+		r.logger.Errorf("Failed to update draft item %s: %v", itemID, err)
+		return fmt.Errorf("failed to update item: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// DecideItem применяет решение модератора к элементу в статусе pending, пишет журнал и уведомляет автора.
+// Одобренный элемент попадает в публичный каталог
+func (r *ModerationRepository) DecideItem(itemID string, moderatorID int, decision string, reason *string) (*models.ModerationDecision, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := fmt.Sprintf(`
+		UPDATE %[1]s SET
+			moderation_status = $2::text,
+			is_public = (CASE WHEN $2::text = '%[2]s' THEN TRUE ELSE is_public END),
+			is_custom = (CASE WHEN $2::text = '%[2]s' THEN FALSE ELSE is_custom END),
+			updated_at = NOW()
+		WHERE id = $1 AND moderation_status = '%[3]s'
+		RETURNING title, creator_id
+	`, collectionItemsTable, models.ModerationStatusApproved, models.ModerationStatusPending)
+
+	var title string
+	var creatorID *int
+	if err := tx.QueryRow(query, itemID, decision).Scan(&title, &creatorID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		r.logger.Errorf("Failed to apply moderation decision to item %s: %v", itemID, err)
+		return nil, fmt.Errorf("failed to apply moderation decision: %w", err)
+	}
+
+	record, err := insertModerationDecision(tx, itemID, &moderatorID, decision, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	if creatorID != nil {
+		payload := models.ModerationNotificationPayload{
+			ItemID:    itemID,
+			ItemTitle: title,
+			Decision:  decision,
+			Reason:    reason,
+		}
+		if _, err := insertNotification(tx, *creatorID, models.NotificationModerationDecision, payload); err != nil {
+			r.logger.Errorf("Failed to notify user %d about moderation of item %s: %v", *creatorID, itemID, err)
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return record, nil
+}
+
+// GetDecisions - журнал модерации элемента в хронологическом порядке
+func (r *ModerationRepository) GetDecisions(itemID string) ([]models.ModerationDecision, error) {
+	query := fmt.Sprintf(`
+		SELECT id, item_id, actor_id, decision, reason, created_at
+		FROM %s
+		WHERE item_id = $1
+		ORDER BY created_at ASC, id ASC
+	`, moderationDecisionsTable)
+
+	rows, err := r.db.Query(query, itemID)
+	if err != nil {
+		r.logger.Errorf("Failed to get moderation decisions for item %s: %v", itemID, err)
+		return nil, fmt.Errorf("failed to get moderation decisions: %w", err)
+	}
+	defer rows.Close()
+
+	decisions := []models.ModerationDecision{}
+	for rows.Next() {
+		var decision models.ModerationDecision
+		err := rows.Scan(
+			&decision.ID,
+			&decision.ItemID,
+			&decision.ActorID,
+			&decision.Decision,
+			&decision.Reason,
+			&decision.CreatedAt,
+		)
+		if err != nil {
+			r.logger.Errorf("Scan failed: %v", err)
+			return nil, fmt.Errorf("failed to scan moderation decision: %w", err)
+		}
+		decisions = append(decisions, decision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return decisions, nil
+}
+
+func insertModerationDecision(q queryRower, itemID string, actorID *int, decision string, reason *string) (*models.ModerationDecision, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (item_id, actor_id, decision, reason) VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`, moderationDecisionsTable)
+
+	record := &models.ModerationDecision{
+		ItemID:   itemID,
+		ActorID:  actorID,
+		Decision: decision,
+		Reason:   reason,
+	}
+	if err := q.QueryRow(query, itemID, actorID, decision, reason).Scan(&record.ID, &record.CreatedAt); err != nil {
+		return nil, fmt.Errorf("failed to record moderation decision: %w", err)
+	}
+	return record, nil
+}
@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
+	"go.uber.org/zap"
+)
+
+type NotificationRepository struct {
+	db     *sql.DB
+	logger *zap.SugaredLogger
+}
+
+func NewNotificationPostgres(db *sql.DB, logger *zap.SugaredLogger) *NotificationRepository {
+	return &NotificationRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetNotifications - уведомления пользователя, сначала новые. unreadOnly - только непрочитанные
+func (r *NotificationRepository) GetNotifications(userID int, unreadOnly bool, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.Notification], error) {
+	where := "WHERE user_id = $1"
+	if unreadOnly {
+		where += " AND read_at IS NULL"
+	}
+
+	var total int64
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM %s %s`, notificationsTable, where)
+	if err := r.db.QueryRow(countQuery, userID).Scan(&total); err != nil {
+		r.logger.Errorf("Failed to count notifications for user %d: %v", userID, err)
+		return nil, fmt.Errorf("failed to count notifications: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, user_id, type, payload, read_at, created_at
+		FROM %s
+		%s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`, notificationsTable, where)
+
+	rows, err := r.db.Query(query, userID, req.Limit(), req.Offset())
+	if err != nil {
+		r.logger.Errorf("Failed to get notifications for user %d: %v", userID, err)
+		return nil, fmt.Errorf("failed to get notifications: %w", err)
+	}
+	defer rows.Close()
+
+	notifications := []models.Notification{}
+	for rows.Next() {
+		var notification models.Notification
+		err := rows.Scan(
+			&notification.ID,
+			&notification.UserID,
+			&notification.Type,
+			&notification.Payload,
+			&notification.ReadAt,
+			&notification.CreatedAt,
+		)
+		if err != nil {
+			r.logger.Errorf("Scan failed: %v", err)
+			return nil, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		notifications = append(notifications, notification)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return &pagination.PaginatedResponse[models.Notification]{
+		Data:       notifications,
+		Pagination: req.ToPagination(total),
+	}, nil
+}
+
+// CountUnread - количество непрочитанных уведомлений пользователя
+func (r *NotificationRepository) CountUnread(userID int) (int, error) {
+	var count int
+	err := r.db.QueryRow(fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE user_id = $1 AND read_at IS NULL`, notificationsTable), userID).
+		Scan(&count)
+	if err != nil {
+		r.logger.Errorf("Failed to count unread notifications for user %d: %v", userID, err)
+		return 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+	return count, nil
+}
+
+// MarkRead отмечает уведомление пользователя прочитанным. Повторная отметка не меняет время прочтения
+func (r *NotificationRepository) MarkRead(userID int, notificationID int) error {
+	res, err := r.db.Exec(fmt.Sprintf(`
+		UPDATE %s SET read_at = COALESCE(read_at, NOW()) WHERE id = $1 AND user_id = $2
+	`, notificationsTable), notificationID, userID)
+	if err != nil {
+		r.logger.Errorf("Failed to mark notification %d as read: %v", notificationID, err)
+		return fmt.Errorf("failed to mark notification as read: %w", err)
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// MarkAllRead отмечает прочитанными все уведомления пользователя и возвращает, сколько их было непрочитано
+func (r *NotificationRepository) MarkAllRead(userID int) (int64, error) {
+	res, err := r.db.Exec(fmt.Sprintf(`UPDATE %s SET read_at = NOW() WHERE user_id = $1 AND read_at IS NULL`, notificationsTable), userID)
+	if err != nil {
+		r.logger.Errorf("Failed to mark notifications of user %d as read: %v", userID, err)
+		return 0, fmt.Errorf("failed to mark notifications as read: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// GetPreferences - настройки уведомлений пользователя, которые отличаются от канала по умолчанию
+func (r *NotificationRepository) GetPreferences(userID int) (map[string]string, error) {
+	rows, err := r.db.Query(fmt.Sprintf(`SELECT type, channel FROM %s WHERE user_id = $1`, notificationPreferencesTable), userID)
+	if err != nil {
+		r.logger.Errorf("Failed to get notification preferences of user %d: %v", userID, err)
+		return nil, fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+	defer rows.Close()
+
+	preferences := map[string]string{}
+	for rows.Next() {
+		var notificationType, channel string
+		if err := rows.Scan(&notificationType, &channel); err != nil {
+			return nil, fmt.Errorf("failed to scan notification preference: %w", err)
+		}
+		preferences[notificationType] = channel
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+	return preferences, nil
+}
+
+// SetPreferences сохраняет каналы уведомлений. Канал по умолчанию (in_app) не хранится
+func (r *NotificationRepository) SetPreferences(userID int, preferences []models.NotificationPreference) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, preference := range preferences {
+		if preference.Channel == models.NotificationChannelInApp {
+			_, err = tx.Exec(fmt.Sprintf(`DELETE FROM %s WHERE user_id = $1 AND type = $2`, notificationPreferencesTable),
+				userID, preference.Type)
+		} else {
+			_, err = tx.Exec(fmt.Sprintf(`
+				INSERT INTO %s (user_id, type, channel) VALUES ($1, $2, $3)
+				ON CONFLICT (user_id, type) DO UPDATE SET channel = EXCLUDED.channel
+			`, notificationPreferencesTable), userID, preference.Type, preference.Channel)
+		}
+		if err != nil {
+			r.logger.Errorf("Failed to save notification preference %s of user %d: %v", preference.Type, userID, err)
+			return fmt.Errorf("failed to save notification preference: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// ClaimDeliveries забирает до limit отправок, которым пора уйти. Забранная отправка откладывается на lease:
+// если обработчик упадет, не отметив результат, ее заберут повторно
+func (r *NotificationRepository) ClaimDeliveries(limit int, lease time.Duration) ([]models.NotificationDelivery, error) {
+	query := fmt.Sprintf(`
+		WITH claimed AS (
+			UPDATE %[1]s SET attempts = attempts + 1, next_attempt_at = NOW() + $2 * INTERVAL '1 second'
+			WHERE id IN (
+				SELECT id FROM %[1]s
+				WHERE status = '%[4]s' AND next_attempt_at <= NOW()
+				ORDER BY next_attempt_at
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING id, notification_id, channel, attempts
+		)
+		SELECT claimed.id, claimed.channel, claimed.attempts,
+			n.id, n.user_id, n.type, n.payload, n.read_at, n.created_at,
+			u.name, CASE WHEN u.deleted_at IS NULL THEN u.mail ELSE '' END
+		FROM claimed
+		JOIN %[2]s n ON n.id = claimed.notification_id
+		JOIN %[3]s u ON u.id = n.user_id
+		ORDER BY n.created_at, claimed.id
+	`, notificationDeliveriesTable, notificationsTable, usersTable, models.DeliveryStatusPending)
+
+	rows, err := r.db.Query(query, limit, int(lease.Seconds()))
+	if err != nil {
+		r.logger.Errorf("Failed to claim notification deliveries: %v", err)
+		return nil, fmt.Errorf("failed to claim notification deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []models.NotificationDelivery{}
+	for rows.Next() {
+		var delivery models.NotificationDelivery
+		n := &delivery.Notification
+		err := rows.Scan(&delivery.ID, &delivery.Channel, &delivery.Attempts,
+			&n.ID, &n.UserID, &n.Type, &n.Payload, &n.ReadAt, &n.CreatedAt,
+			&delivery.RecipientName, &delivery.RecipientEmail)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification delivery: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+	return deliveries, nil
+}
+
+// FinishDelivery записывает результат отправки. sendErr = nil - отправлено; retryAt = nil - попытки исчерпаны
+func (r *NotificationRepository) FinishDelivery(deliveryID int64, sendErr error, retryAt *time.Time) error {
+	var err error
+	switch {
+	case sendErr == nil:
+		_, err = r.db.Exec(fmt.Sprintf(`
+			UPDATE %s SET status = '%s', sent_at = NOW(), last_error = NULL WHERE id = $1
+		`, notificationDeliveriesTable, models.DeliveryStatusSent), deliveryID)
+	case retryAt != nil:
+		_, err = r.db.Exec(fmt.Sprintf(`
+			UPDATE %s SET last_error = $2, next_attempt_at = $3 WHERE id = $1
+		`, notificationDeliveriesTable), deliveryID, sendErr.Error(), *retryAt)
+	default:
+		_, err = r.db.Exec(fmt.Sprintf(`
+			UPDATE %s SET status = '%s', last_error = $2 WHERE id = $1
+		`, notificationDeliveriesTable, models.DeliveryStatusFailed), deliveryID, sendErr.Error())
+	}
+	if err != nil {
+		r.logger.Errorf("Failed to save result of notification delivery %d: %v", deliveryID, err)
+		return fmt.Errorf("failed to save delivery result: %w", err)
+	}
+	return nil
+}
+
+// insertNotification создает уведомление в рамках транзакции вызывающего события с учетом настроек получателя:
+// при канале off уведомление не создается (возвращается 0), при внешнем канале оно ставится в очередь доставки
+func insertNotification(q queryRower, userID int, notificationType string, payload any) (int, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal notification payload: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		WITH pref AS (
+			SELECT COALESCE((SELECT channel FROM %[2]s WHERE user_id = $1 AND type = $2), '%[4]s') AS channel
+		), notification AS (
+			INSERT INTO %[1]s (user_id, type, payload)
+			SELECT $1, $2, $3 FROM pref WHERE pref.channel <> '%[5]s'
+			RETURNING id
+		), delivery AS (
+			INSERT INTO %[3]s (notification_id, channel)
+			SELECT notification.id, pref.channel FROM notification, pref WHERE pref.channel <> '%[4]s'
+		)
+		SELECT id FROM notification
+	`, notificationsTable, notificationPreferencesTable, notificationDeliveriesTable,
+		models.NotificationChannelInApp, models.NotificationChannelOff)
+
+	var id int
+	if err := q.QueryRow(query, userID, notificationType, data).Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to create notification: %w", err)
+	}
+	return id, nil
+}
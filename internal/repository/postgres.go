@@ -2,7 +2,10 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+
+	"github.com/lib/pq"
 )
 
 type Config struct {
@@ -19,13 +22,68 @@ const (
 	collectionsTable               = "collections"
 	collectionItemsTable           = "collection_items"
 	collectionItemsAssignmentTable = "collections_items_assignment"
+	itemSeasonsTable               = "item_seasons"
+	itemEpisodesTable              = "item_episodes"
+	userWatchedEpisodesTable       = "user_watched_episodes"
+	userItemProgressTable          = "user_item_progress"
+	itemRelationsTable             = "item_relations"
+	franchisesTable                = "franchises"
+	franchiseItemsTable            = "franchise_items"
+	genresTable                    = "genres"
+	itemGenresTable                = "item_genres"
+	tagsTable                      = "tags"
+	itemTagsTable                  = "item_tags"
+	collectionTagsTable            = "collection_tags"
+	itemStatsTable                 = "item_stats"
+	itemRedirectsTable             = "item_redirects"
+	moderationDecisionsTable       = "moderation_decisions"
+	notificationsTable             = "notifications"
+	itemEditSuggestionsTable       = "item_edit_suggestions"
+	itemRevisionsTable             = "item_revisions"
+	imagesTable                    = "images"
+	itemExternalIDsTable           = "item_external_ids"
+	itemLockedFieldsTable          = "item_locked_fields"
+	importJobsTable                = "import_jobs"
+	importJobRowsTable             = "import_job_rows"
+	importExternalIDsTable         = "import_external_ids"
+	followsTable                   = "follows"
+	activityEventsTable            = "activity_events"
+	collectionLikesTable           = "collection_likes"
+	collectionSavesTable           = "collection_saves"
+	collectionCommentsTable        = "collection_comments"
+	notificationPreferencesTable   = "notification_preferences"
+	notificationDeliveriesTable    = "notification_deliveries"
+	webhooksTable                  = "webhooks"
+	webhookDeliveriesTable         = "webhook_deliveries"
+	itemSimilaritiesTable          = "item_similarities"
+	itemChartsTable                = "item_charts"
+)
+
+var (
+	// ErrNotFound возвращается, когда запрашиваемая запись отсутствует
+	ErrNotFound = errors.New("record not found")
+	// ErrAlreadyExists возвращается при нарушении ограничения уникальности
+	ErrAlreadyExists = errors.New("record already exists")
+	// ErrNothingChanged возвращается, когда изменение не отличается от текущих данных
+	ErrNothingChanged = errors.New("nothing changed")
 )
 
+// isUniqueViolation проверяет, что ошибка postgres - нарушение уникальности (23505)
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
 // docker run --name Memoria -P -p 127.0.0.1:5433:5432 -e POSTGRES_PASSWORD="1234" postgres:alpine
 
+// DSN - строка подключения, нужна и отдельным соединениям, например слушателю LISTEN/NOTIFY
+func (cfg Config) DSN() string {
+	return fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
+		cfg.Username, cfg.Password, cfg.Host, cfg.Port, cfg.DBName)
+}
+
 func NewPostgresDB(cfg Config) (*sql.DB, error) {
-	db, err := sql.Open("postgres", fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
-		cfg.Username, cfg.Password, cfg.Host, cfg.Port, cfg.DBName))
+	db, err := sql.Open("postgres", cfg.DSN())
 
 	if err != nil {
 		return nil, err
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+)
+
+// queryFilter собирает условия WHERE с позиционными параметрами ($1, $2, ...)
+type queryFilter struct {
+	conditions []string
+	args       []any
+}
+
+// arg добавляет параметр запроса и возвращает его плейсхолдер
+func (f *queryFilter) arg(value any) string {
+	f.args = append(f.args, value)
+	return fmt.Sprintf("$%d", len(f.args))
+}
+
+func (f *queryFilter) where(condition string) {
+	f.conditions = append(f.conditions, condition)
+}
+
+func (f *queryFilter) sql() string {
+	if len(f.conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(f.conditions, " AND ")
+}
@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"go.uber.org/zap"
+)
+
+// similarityLockKey - ключ advisory-блокировки пересчета, чтобы несколько экземпляров не считали одновременно
+const similarityLockKey = 49001
+
+// Сколько элементов библиотеки показывается в объяснении рекомендации
+const recommendationSourcesLimit = 3
+
+type RecommendationRepository struct {
+	db     *sql.DB
+	logger *zap.SugaredLogger
+}
+
+func NewRecommendationPostgres(db *sql.DB, logger *zap.SugaredLogger) *RecommendationRepository {
+	return &RecommendationRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// interactionsSQL - взаимодействия пользователей с публичными элементами: элемент в коллекции или с прогрессом.
+// Вес: оценка / 5.5 (оценки 1..10 дают 0.18..1.82), брошенный элемент - 0.3, иначе 1.
+// У каждого пользователя учитываются только historyParam последних элементов, userCondition ограничивает пользователей
+func interactionsSQL(userCondition string, historyParam string) string {
+	return fmt.Sprintf(`
+		SELECT user_id, item_id, weight
+		FROM (
+			SELECT x.user_id, x.item_id, x.weight,
+			       row_number() OVER (PARTITION BY x.user_id ORDER BY x.at DESC NULLS LAST, x.item_id) AS rn
+			FROM (
+				SELECT COALESCE(p.user_id, a.user_id) AS user_id,
+				       COALESCE(p.item_id, a.item_id) AS item_id,
+				       (CASE WHEN p.rating IS NOT NULL THEN p.rating / 5.5
+				             WHEN p.status = 'dropped' THEN 0.3
+				             ELSE 1.0 END)::float8 AS weight,
+				       GREATEST(p.updated_at, a.added_at) AS at
+				FROM (
+					SELECT c.user_id, cia.item_id, MAX(cia.added_at)::timestamptz AS added_at
+					FROM %[1]s cia
+					JOIN %[2]s c ON c.id = cia.collection_id
+					WHERE %[4]s
+					GROUP BY c.user_id, cia.item_id
+				) a
+				FULL JOIN (
+					SELECT user_id, item_id, status, rating, updated_at
+					FROM %[3]s
+					WHERE %[4]s
+				) p ON p.user_id = a.user_id AND p.item_id = a.item_id
+			) x
+			JOIN %[5]s ci ON ci.id = x.item_id AND ci.is_public = TRUE
+		) ranked
+		WHERE rn <= %[6]s
+	`, collectionItemsAssignmentTable, collectionsTable, userItemProgressTable, userCondition, collectionItemsTable, historyParam)
+}
+
+// notInLibrarySQL - условие, что элемент ci еще не в библиотеке пользователя: ни в одной коллекции и без прогресса
+func notInLibrarySQL(userParam string) string {
+	return fmt.Sprintf(`NOT EXISTS (
+			SELECT 1 FROM %[1]s lib_cia JOIN %[2]s lib_c ON lib_c.id = lib_cia.collection_id
+			WHERE lib_cia.item_id = ci.id AND lib_c.user_id = %[4]s
+		)
+		AND NOT EXISTS (SELECT 1 FROM %[3]s lib_p WHERE lib_p.item_id = ci.id AND lib_p.user_id = %[4]s)`,
+		collectionItemsAssignmentTable, collectionsTable, userItemProgressTable, userParam)
+}
+
+// RecomputeItemSimilarities пересчитывает таблицу похожих элементов целиком в одной транзакции:
+// читатели видят старые данные, пока пересчет не завершится. Похожесть - косинус векторов весов
+// по пользователям, умноженный на n/(n+shrinkage), где n - число пользователей, у которых есть оба элемента.
+// Возвращает число сохраненных пар и false, если пересчет уже идет в другом экземпляре приложения
+func (r *RecommendationRepository) RecomputeItemSimilarities(params models.SimilarityParams) (int64, bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	if err := tx.QueryRow(`SELECT pg_try_advisory_xact_lock($1)`, similarityLockKey).Scan(&locked); err != nil {
+		return 0, false, fmt.Errorf("failed to lock similarities: %w", err)
+	}
+	if !locked {
+		return 0, false, nil
+	}
+
+	if _, err := tx.Exec(fmt.Sprintf(`DELETE FROM %s`, itemSimilaritiesTable)); err != nil {
+		return 0, false, fmt.Errorf("failed to clear similarities: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		WITH interactions AS (%[1]s),
+		norms AS (
+			SELECT item_id, sqrt(SUM(weight * weight)) AS norm
+			FROM interactions
+			GROUP BY item_id
+		),
+		pairs AS (
+			SELECT a.item_id, b.item_id AS similar_item_id, SUM(a.weight * b.weight) AS dot, COUNT(*) AS co_count
+			FROM interactions a
+			JOIN interactions b ON b.user_id = a.user_id AND b.item_id <> a.item_id
+			GROUP BY a.item_id, b.item_id
+			HAVING COUNT(*) >= $2
+		),
+		scored AS (
+			SELECT p.item_id, p.similar_item_id, p.co_count,
+			       p.dot / (na.norm * nb.norm) * p.co_count / (p.co_count + $3::float8) AS score
+			FROM pairs p
+			JOIN norms na ON na.item_id = p.item_id
+			JOIN norms nb ON nb.item_id = p.similar_item_id
+		)
+		INSERT INTO %[2]s (item_id, similar_item_id, score, co_count, computed_at)
+		SELECT item_id, similar_item_id, score, co_count, CURRENT_TIMESTAMP
+		FROM (
+			SELECT *, row_number() OVER (PARTITION BY item_id ORDER BY score DESC, similar_item_id) AS rn
+			FROM scored
+		) ranked
+		WHERE rn <= $4
+	`, interactionsSQL("TRUE", "$1"), itemSimilaritiesTable)
+
+	res, err := tx.Exec(query, params.UserHistory, params.MinCoOccurrences, params.Shrinkage, params.PerItem)
+	if err != nil {
+		r.logger.Errorf("Failed to compute item similarities: %v", err)
+		return 0, false, fmt.Errorf("failed to compute similarities: %w", err)
+	}
+	pairs, _ := res.RowsAffected()
+
+	if err := tx.Commit(); err != nil {
+		return 0, false, fmt.Errorf("failed to commit similarities: %w", err)
+	}
+	return pairs, true, nil
+}
+
+// GetSimilaritiesComputedAt - время последнего пересчета, nil если похожих элементов еще нет
+func (r *RecommendationRepository) GetSimilaritiesComputedAt() (*time.Time, error) {
+	var computedAt *time.Time
+	query := fmt.Sprintf(`SELECT MAX(computed_at) FROM %s`, itemSimilaritiesTable)
+	if err := r.db.QueryRow(query).Scan(&computedAt); err != nil {
+		return nil, fmt.Errorf("failed to get similarities time: %w", err)
+	}
+	return computedAt, nil
+}
+
+// GetSimilarItems - похожие элементы, видимые пользователю. excludeLibrary убирает элементы из его библиотеки
+func (r *RecommendationRepository) GetSimilarItems(itemID string, userID int, excludeLibrary bool, limit int) ([]models.SimilarItem, error) {
+	f := &queryFilter{}
+	f.where("s.item_id = " + f.arg(itemID))
+	userParam := f.arg(userID)
+	f.where(fmt.Sprintf("(ci.is_public = TRUE OR ci.creator_id = %s)", userParam))
+	if excludeLibrary {
+		f.where(notInLibrarySQL(userParam))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %[1]s, s.score, s.co_count
+		FROM %[2]s s
+		JOIN %[3]s ci ON ci.id = s.similar_item_id
+		%[4]s
+		ORDER BY s.score DESC, ci.id
+		LIMIT %[5]s
+	`, collectionItemColumns, itemSimilaritiesTable, collectionItemsTable, f.sql(), f.arg(limit))
+
+	rows, err := r.db.Query(query, f.args...)
+	if err != nil {
+		r.logger.Errorf("Failed to get similar items for %s: %v", itemID, err)
+		return nil, fmt.Errorf("failed to get similar items: %w", err)
+	}
+	defer rows.Close()
+
+	items := []models.SimilarItem{}
+	for rows.Next() {
+		var item models.SimilarItem
+		if err := scanCollectionItem(rows, &item.Item, &item.Score, &item.CoOccurrences); err != nil {
+			r.logger.Errorf("Scan failed: %v", err)
+			return nil, fmt.Errorf("failed to scan similar item: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+	return items, nil
+}
+
+// GetRecommendations - рекомендации пользователю: сумма похожестей кандидата на элементы его библиотеки,
+// взвешенных оценками. Элементы из библиотеки и невидимые пользователю не рекомендуются
+func (r *RecommendationRepository) GetRecommendations(userID int, itemType string, history int, limit int) ([]models.RecommendedItem, error) {
+	f := &queryFilter{}
+	userParam := f.arg(userID)
+	historyParam := f.arg(history)
+	f.where(fmt.Sprintf("(ci.is_public = TRUE OR ci.creator_id = %s)", userParam))
+	f.where(notInLibrarySQL(userParam))
+	if itemType != "" {
+		f.where("ci.type = " + f.arg(itemType))
+	}
+
+	query := fmt.Sprintf(`
+		WITH history AS (%[1]s),
+		candidates AS (
+			SELECT s.similar_item_id AS item_id, SUM(h.weight * s.score) AS score,
+			       (array_agg(s.item_id ORDER BY h.weight * s.score DESC))[1:%[2]d] AS because_of
+			FROM history h
+			JOIN %[3]s s ON s.item_id = h.item_id
+			GROUP BY s.similar_item_id
+		)
+		SELECT %[4]s, c.score,
+		       (SELECT COALESCE(json_agg(json_build_object('item_id', src.id, 'title', src.title) ORDER BY u.ord), '[]')
+		        FROM unnest(c.because_of) WITH ORDINALITY AS u(item_id, ord)
+		        JOIN %[5]s src ON src.id = u.item_id)
+		FROM candidates c
+		JOIN %[5]s ci ON ci.id = c.item_id
+		%[6]s
+		ORDER BY c.score DESC, ci.id
+		LIMIT %[7]s
+	`, interactionsSQL("user_id = "+userParam, historyParam), recommendationSourcesLimit, itemSimilaritiesTable,
+		collectionItemColumns, collectionItemsTable, f.sql(), f.arg(limit))
+
+	rows, err := r.db.Query(query, f.args...)
+	if err != nil {
+		r.logger.Errorf("Failed to get recommendations for user %d: %v", userID, err)
+		return nil, fmt.Errorf("failed to get recommendations: %w", err)
+	}
+	defer rows.Close()
+
+	items := []models.RecommendedItem{}
+	for rows.Next() {
+		var item models.RecommendedItem
+		var sources []byte
+		if err := scanCollectionItem(rows, &item.Item, &item.Score, &sources); err != nil {
+			r.logger.Errorf("Scan failed: %v", err)
+			return nil, fmt.Errorf("failed to scan recommendation: %w", err)
+		}
+		if err := json.Unmarshal(sources, &item.BecauseOf); err != nil {
+			return nil, fmt.Errorf("failed to decode recommendation sources: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+	return items, nil
+}
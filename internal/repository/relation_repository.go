@@ -0,0 +1,342 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+type RelationRepository struct {
+	db     *sql.DB
+	logger *zap.SugaredLogger
+}
+
+func NewRelationPostgres(db *sql.DB, logger *zap.SugaredLogger) *RelationRepository {
+	return &RelationRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *RelationRepository) CreateRelation(relation *models.ItemRelation) (*models.ItemRelation, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (source_item_id, target_item_id, relation_type, created_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`, itemRelationsTable)
+
+	err := r.db.QueryRow(query, relation.SourceItemID, relation.TargetItemID, relation.RelationType, relation.CreatedBy).
+		Scan(&relation.ID, &relation.CreatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrAlreadyExists
+		}
+		r.logger.Errorf("Failed to create relation %s -> %s: %v", relation.SourceItemID, relation.TargetItemID, err)
+		return nil, fmt.Errorf("failed to create relation: %w", err)
+	}
+
+	return relation, nil
+}
+
+func (r *RelationRepository) GetRelationByID(id int) (*models.ItemRelation, error) {
+	query := fmt.Sprintf(`
+		SELECT id, source_item_id, target_item_id, relation_type, created_by, created_at
+		FROM %s WHERE id = $1
+	`, itemRelationsTable)
+
+	var relation models.ItemRelation
+	err := r.db.QueryRow(query, id).Scan(
+		&relation.ID,
+		&relation.SourceItemID,
+		&relation.TargetItemID,
+		&relation.RelationType,
+		&relation.CreatedBy,
+		&relation.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		r.logger.Errorf("Failed to get relation %d: %v", id, err)
+		return nil, err
+	}
+
+	return &relation, nil
+}
+
+func (r *RelationRepository) DeleteRelation(id int) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE id = $1`, itemRelationsTable)
+
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		r.logger.Errorf("Failed to delete relation %d: %v", id, err)
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// GetRelationGraph обходит связи элемента в обе стороны на глубину depth.
+// В граф попадают только элементы, видимые пользователю (публичные или созданные им),
+// и обход не проходит через чужие личные элементы к тем, что лежат за ними.
+func (r *RelationRepository) GetRelationGraph(itemID string, depth int, userID int) (*models.RelationGraph, error) {
+	nodesQuery := fmt.Sprintf(`
+		WITH RECURSIVE graph(item_id, depth) AS (
+			SELECT $1::uuid, 0
+			UNION
+			SELECT next.id, g.depth + 1
+			FROM graph g
+			JOIN %[1]s rel ON rel.source_item_id = g.item_id OR rel.target_item_id = g.item_id
+			JOIN %[2]s next ON next.id = CASE WHEN rel.source_item_id = g.item_id THEN rel.target_item_id ELSE rel.source_item_id END
+			WHERE g.depth < $2 AND (next.is_public = TRUE OR next.creator_id = $3)
+		)
+		SELECT %[3]s, MIN(g.depth) AS depth
+		FROM graph g
+		JOIN %[2]s ci ON ci.id = g.item_id
+		WHERE ci.is_public = TRUE OR ci.creator_id = $3
+		GROUP BY ci.id
+		ORDER BY depth ASC, ci.title ASC
+	`, itemRelationsTable, collectionItemsTable, collectionItemColumns)
+
+	rows, err := r.db.Query(nodesQuery, itemID, depth, userID)
+	if err != nil {
+		r.logger.Errorf("Failed to build relation graph for item %s: %v", itemID, err)
+		return nil, fmt.Errorf("failed to build relation graph: %w", err)
+	}
+	defer rows.Close()
+
+	graph := &models.RelationGraph{
+		RootItemID: itemID,
+		Depth:      depth,
+		Nodes:      []models.RelationGraphNode{},
+		Edges:      []models.ItemRelation{},
+		Franchises: []models.Franchise{},
+	}
+	nodeIDs := []string{}
+	for rows.Next() {
+		var node models.RelationGraphNode
+		err := scanCollectionItem(rows, &node.Item, &node.Depth)
+		if err != nil {
+			r.logger.Errorf("Scan failed: %v", err)
+			return nil, fmt.Errorf("failed to scan graph node: %w", err)
+		}
+		graph.Nodes = append(graph.Nodes, node)
+		nodeIDs = append(nodeIDs, node.Item.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	edgesQuery := fmt.Sprintf(`
+		SELECT id, source_item_id, target_item_id, relation_type, created_by, created_at
+		FROM %s
+		WHERE source_item_id = ANY($1) AND target_item_id = ANY($1)
+		ORDER BY id
+	`, itemRelationsTable)
+
+	edgeRows, err := r.db.Query(edgesQuery, pq.Array(nodeIDs))
+	if err != nil {
+		r.logger.Errorf("Failed to get graph edges for item %s: %v", itemID, err)
+		return nil, fmt.Errorf("failed to get graph edges: %w", err)
+	}
+	defer edgeRows.Close()
+
+	for edgeRows.Next() {
+		var relation models.ItemRelation
+		err := edgeRows.Scan(
+			&relation.ID,
+			&relation.SourceItemID,
+			&relation.TargetItemID,
+			&relation.RelationType,
+			&relation.CreatedBy,
+			&relation.CreatedAt,
+		)
+		if err != nil {
+			r.logger.Errorf("Scan failed: %v", err)
+			return nil, fmt.Errorf("failed to scan graph edge: %w", err)
+		}
+		graph.Edges = append(graph.Edges, relation)
+	}
+	if err := edgeRows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	franchises, err := r.GetFranchisesByItem(itemID)
+	if err != nil {
+		return nil, err
+	}
+	graph.Franchises = franchises
+
+	return graph, nil
+}
+
+func (r *RelationRepository) CreateFranchise(franchise *models.Franchise) (*models.Franchise, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (name, description, created_by)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`, franchisesTable)
+
+	err := r.db.QueryRow(query, franchise.Name, franchise.Description, franchise.CreatedBy).Scan(&franchise.ID, &franchise.CreatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrAlreadyExists
+		}
+		r.logger.Errorf("Failed to create franchise %s: %v", franchise.Name, err)
+		return nil, fmt.Errorf("failed to create franchise: %w", err)
+	}
+
+	return franchise, nil
+}
+
+func (r *RelationRepository) GetFranchises() ([]models.Franchise, error) {
+	query := fmt.Sprintf(`
+		SELECT id, name, description, created_by, created_at
+		FROM %s
+		ORDER BY name ASC
+	`, franchisesTable)
+
+	return r.queryFranchises(query)
+}
+
+func (r *RelationRepository) GetFranchisesByItem(itemID string) ([]models.Franchise, error) {
+	query := fmt.Sprintf(`
+		SELECT f.id, f.name, f.description, f.created_by, f.created_at
+		FROM %s f
+		JOIN %s fi ON fi.franchise_id = f.id
+		WHERE fi.item_id = $1
+		ORDER BY f.name ASC
+	`, franchisesTable, franchiseItemsTable)
+
+	return r.queryFranchises(query, itemID)
+}
+
+// GetFranchiseByID возвращает франшизу с элементами, видимыми пользователю
+func (r *RelationRepository) GetFranchiseByID(franchiseID string, userID int) (*models.Franchise, error) {
+	query := fmt.Sprintf(`
+		SELECT id, name, description, created_by, created_at
+		FROM %s WHERE id = $1
+	`, franchisesTable)
+
+	var franchise models.Franchise
+	err := r.db.QueryRow(query, franchiseID).Scan(
+		&franchise.ID,
+		&franchise.Name,
+		&franchise.Description,
+		&franchise.CreatedBy,
+		&franchise.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		r.logger.Errorf("Failed to get franchise %s: %v", franchiseID, err)
+		return nil, err
+	}
+
+	itemsQuery := fmt.Sprintf(`
+		SELECT %s
+		FROM %s ci
+		JOIN %s fi ON fi.item_id = ci.id
+		WHERE fi.franchise_id = $1 AND (ci.is_public = TRUE OR ci.creator_id = $2)
+		ORDER BY fi.added_at ASC
+	`, collectionItemColumns, collectionItemsTable, franchiseItemsTable)
+
+	rows, err := r.db.Query(itemsQuery, franchiseID, userID)
+	if err != nil {
+		r.logger.Errorf("Failed to get franchise %s items: %v", franchiseID, err)
+		return nil, fmt.Errorf("failed to get franchise items: %w", err)
+	}
+	defer rows.Close()
+
+	franchise.Items = []models.CollectionItem{}
+	for rows.Next() {
+		var item models.CollectionItem
+		if err := scanCollectionItem(rows, &item); err != nil {
+			r.logger.Errorf("Scan failed: %v", err)
+			return nil, fmt.Errorf("failed to scan collection item: %w", err)
+		}
+		franchise.Items = append(franchise.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return &franchise, nil
+}
+
+func (r *RelationRepository) AddItemToFranchise(franchiseID string, itemID string) error {
+	query := fmt.Sprintf(`INSERT INTO %s (franchise_id, item_id) VALUES ($1, $2)`, franchiseItemsTable)
+
+	if _, err := r.db.Exec(query, franchiseID, itemID); err != nil {
+		if isUniqueViolation(err) {
+			return ErrAlreadyExists
+		}
+		r.logger.Errorf("Failed to add item %s to franchise %s: %v", itemID, franchiseID, err)
+		return fmt.Errorf("failed to add item to franchise: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RelationRepository) RemoveItemFromFranchise(franchiseID string, itemID string) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE franchise_id = $1 AND item_id = $2`, franchiseItemsTable)
+
+	result, err := r.db.Exec(query, franchiseID, itemID)
+	if err != nil {
+		r.logger.Errorf("Failed to remove item %s from franchise %s: %v", itemID, franchiseID, err)
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *RelationRepository) queryFranchises(query string, args ...any) ([]models.Franchise, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		r.logger.Errorf("Failed to get franchises: %v", err)
+		return nil, fmt.Errorf("failed to get franchises: %w", err)
+	}
+	defer rows.Close()
+
+	franchises := []models.Franchise{}
+	for rows.Next() {
+		var franchise models.Franchise
+		err := rows.Scan(
+			&franchise.ID,
+			&franchise.Name,
+			&franchise.Description,
+			&franchise.CreatedBy,
+			&franchise.CreatedAt,
+		)
+		if err != nil {
+			r.logger.Errorf("Scan failed: %v", err)
+			return nil, fmt.Errorf("failed to scan franchise: %w", err)
+		}
+		franchises = append(franchises, franchise)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return franchises, nil
+}
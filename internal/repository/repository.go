@@ -2,8 +2,12 @@ package repository
 
 import (
 	"database/sql"
+	"time"
 
 	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/backup"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/exporter"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/importer"
 	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
 	"go.uber.org/zap"
 )
@@ -18,6 +22,7 @@ type UserRepository interface {
 	LinkGitHubToExistingUser(userID int, githubUser *models.GitHubUser) (*models.User, error)
 	UpdateUserPassword(userID int, hashedPassword string) error
 	UpdateLastLogin(userID int) error
+	GetUserRole(userID int) (string, error)
 }
 
 type Collection interface {
@@ -25,23 +30,225 @@ type Collection interface {
 	GetCollections(user_id int) ([]models.Collection, error)
 	DeleteCollection(collectionID string) error
 	GetCollectionByID(collectionID string) (*models.Collection, error)
-	GetCollectionsWithPagination(userID int, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.Collection], error)
+	GetCollectionsWithPagination(userID int, tags models.TagFilter, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.Collection], error)
+	GetCollectionsByCursor(userID int, tags models.TagFilter, req pagination.CursorRequest) (*pagination.CursorResponse[models.Collection], error)
+	GetCollectionCovers(collectionID string, limit int) ([]string, error)
+	SetCollectionPublic(collectionID string, isPublic bool) error
 }
 
 type CollectionItem interface {
-	GetAllItemsWithCurrentTypePaginated(currentType string, pagination pagination.PaginationRequest) (*pagination.PaginatedResponse[models.CollectionItem], error)
-	GetItemsByCollection(collection_id string) ([]models.CollectionItem, error)
+	GetAllItemsWithCurrentTypePaginated(filter models.ItemFilter, pagination pagination.PaginationRequest) (*models.ItemFeed, error)
+	GetItemsFeedByCursor(filter models.ItemFilter, req pagination.CursorRequest) (*pagination.CursorResponse[models.FeedItem], error)
+	GetItemsByCollection(collection_id string, tags models.TagFilter, limit int) ([]models.CollectionEntry, error)
 	GetItemByID(id string) (*models.CollectionItem, error)
 	CreateItem(collectionItem *models.CollectionItem) (string, error)
 	DeleteCollectionItem(id string) error
 	UpdateCollectionItem(item *models.CollectionItem) error
 	AddItemToCollection(collection_id string, item_id string, user_review string) (int, error)
+	SuggestItems(query string, itemType string, userID int, limit int) ([]models.ItemSuggestion, error)
+	RecomputeItemStats() (int64, bool, error)
+	GetItemStatsComputedAt() (*time.Time, error)
+}
+
+type Episode interface {
+	GetSeasonsByItem(itemID string, userID int) ([]models.Season, error)
+	GetSeasonByNumber(itemID string, number int) (*models.Season, error)
+	CreateSeason(season *models.Season) (*models.Season, error)
+	CreateEpisode(episode *models.Episode) (string, error)
+	SetEpisodeWatched(userID int, itemID string, episodeID string, watched bool) (*models.ItemProgress, error)
+	SetSeasonWatched(userID int, itemID string, seasonID string, watched bool) (*models.ItemProgress, error)
+	GetItemProgress(userID int, itemID string) (*models.ItemProgress, error)
+	SetItemRating(userID int, itemID string, rating *int) error
+	GetNextEpisodes(userID int) ([]models.NextEpisode, error)
+}
+
+type Relation interface {
+	CreateRelation(relation *models.ItemRelation) (*models.ItemRelation, error)
+	GetRelationByID(id int) (*models.ItemRelation, error)
+	DeleteRelation(id int) error
+	GetRelationGraph(itemID string, depth int, userID int) (*models.RelationGraph, error)
+	CreateFranchise(franchise *models.Franchise) (*models.Franchise, error)
+	GetFranchises() ([]models.Franchise, error)
+	GetFranchisesByItem(itemID string) ([]models.Franchise, error)
+	GetFranchiseByID(franchiseID string, userID int) (*models.Franchise, error)
+	AddItemToFranchise(franchiseID string, itemID string) error
+	RemoveItemFromFranchise(franchiseID string, itemID string) error
+}
+
+type Taxonomy interface {
+	GetGenres() ([]models.Genre, error)
+	CreateGenre(genre *models.Genre) (*models.Genre, error)
+	GetItemGenres(itemID string) ([]models.Genre, error)
+	SetItemGenres(itemID string, slugs []string) error
+	GetUserTags(userID int) ([]models.Tag, error)
+	SuggestTags(userID int, prefix string, limit int) ([]models.Tag, error)
+	GetItemTags(userID int, itemID string) ([]models.Tag, error)
+	SetItemTags(userID int, itemID string, names []string) error
+	GetCollectionTags(collectionID string) ([]models.Tag, error)
+	SetCollectionTags(userID int, collectionID string, names []string) error
+	IsItemInUserLibrary(userID int, itemID string) (bool, error)
+}
+
+type Search interface {
+	SearchItems(query models.SearchQuery, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.SearchResult], error)
+}
+
+type Duplicate interface {
+	FindDuplicates(item *models.CollectionItem, userID int, limit int) ([]models.DuplicateCandidate, error)
+	MergeItems(duplicateID string, canonicalID string, userID int) (*models.ItemMergeResult, error)
+	GetItemRedirect(oldID string) (string, error)
+}
+
+type Moderation interface {
+	GetQueue(req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.CollectionItem], error)
+	CreateSubmittedItem(item *models.CollectionItem, userID int) (string, error)
+	SubmitItem(itemID string, userID int) error
+	UpdateDraftItem(itemID string, userID int, changes map[string]any) error
+	DecideItem(itemID string, moderatorID int, decision string, reason *string) (*models.ModerationDecision, error)
+	GetDecisions(itemID string) ([]models.ModerationDecision, error)
+}
+
+type Notification interface {
+	GetNotifications(userID int, unreadOnly bool, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.Notification], error)
+	CountUnread(userID int) (int, error)
+	MarkRead(userID int, notificationID int) error
+	MarkAllRead(userID int) (int64, error)
+	GetPreferences(userID int) (map[string]string, error)
+	SetPreferences(userID int, preferences []models.NotificationPreference) error
+	ClaimDeliveries(limit int, lease time.Duration) ([]models.NotificationDelivery, error)
+	FinishDelivery(deliveryID int64, sendErr error, retryAt *time.Time) error
+}
+
+type Edit interface {
+	CreateSuggestion(suggestion *models.EditSuggestion) (*models.EditSuggestion, error)
+	GetSuggestionByID(id int) (*models.EditSuggestion, error)
+	GetSuggestions(itemID string, status string, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.EditSuggestion], error)
+	AcceptSuggestion(id int, reviewerID int, comment *string) (*models.ItemRevision, error)
+	RejectSuggestion(id int, reviewerID int, comment *string) error
+	GetRevisions(itemID string) ([]models.ItemRevision, error)
+	RevertRevision(itemID string, revisionID int, userID int) (*models.ItemRevision, error)
+}
+
+type Image interface {
+	SaveImage(image *models.Image, url string) error
+	GetImageByID(id string) (*models.Image, error)
+}
+
+type Metadata interface {
+	ImportItem(item *models.CollectionItem, externalID models.ExternalID, genres []string) (string, error)
+	GetItemIDByExternalID(provider string, externalID string) (string, error)
+	GetItemIDsByExternalIDs(provider string, externalIDs []string) (map[string]string, error)
+	GetExternalIDs(itemID string) ([]models.ExternalID, error)
+	GetStaleExternalIDs(providers []string, before time.Time, limit int) ([]models.ExternalID, error)
+	ApplyRefresh(externalID models.ExternalID, changes map[string]any) (*models.ItemRevision, []string, error)
+	MarkRefreshFailed(provider string, externalID string, message string) error
+	GetLockedFields(itemID string) ([]models.ItemFieldLock, error)
+	LockField(itemID string, field string, userID int) (*models.ItemFieldLock, error)
+	UnlockField(itemID string, field string) error
+}
+
+type Import interface {
+	CreateJob(job *models.ImportJob, records []importer.Record) (*models.ImportJob, error)
+	GetJob(userID int, jobID string) (*models.ImportJob, error)
+	GetJobs(userID int, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.ImportJob], error)
+	ClaimJob(staleBefore time.Time) (*models.ImportJob, error)
+	FinishJob(jobID string, status string, message *string) (*models.ImportJob, error)
+	GetPendingRows(jobID string, limit int) ([]models.ImportRow, error)
+	GetRows(jobID string, statuses []string, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.ImportRow], error)
+	GetRow(jobID string, rowID int) (*models.ImportRow, error)
+	FindItemByExternalIDs(externalIDs map[string]string, itemType string, userID int) (string, error)
+	EnsureCollection(userID int, name string, itemType string) (string, error)
+	SaveRowResult(userID int, row *models.ImportRow, result models.ImportRowResult) error
+}
+
+type Export interface {
+	StreamCollectionEntries(collectionID string, userID int, fn func(entry exporter.Entry) error) error
+}
+
+type Backup interface {
+	GetBackup(userID int) (*backup.Archive, error)
+	RestoreBackup(userID int, archive *backup.Archive, mode string) (*models.RestoreResult, error)
+}
+
+type Syndication interface {
+	GetPublicActivity(userID int, limit int) ([]models.PublicActivity, error)
+	GetRecentCatalogItems(limit int) ([]models.CatalogEntry, error)
+}
+
+type Social interface {
+	GetPublicProfile(userID int, viewerID int) (*models.PublicProfile, error)
+	SetAccountPrivate(userID int, private bool) error
+	Follow(followerID int, followeeID int) (*models.Follow, error)
+	DeleteFollow(followerID int, followeeID int) error
+	AcceptFollowRequest(userID int, followerID int) error
+	GetFollowStatus(followerID int, followeeID int) (string, error)
+	GetFollowers(userID int, status string, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.FollowUser], error)
+	GetFollowing(userID int, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.FollowUser], error)
+	GetHomeFeed(userID int, req pagination.CursorRequest) (*pagination.CursorResponse[models.Activity], error)
+	GetUserActivity(userID int, req pagination.CursorRequest) (*pagination.CursorResponse[models.Activity], error)
+}
+
+type Engagement interface {
+	GetEngagement(collectionID string, userID int) (*models.CollectionEngagement, error)
+	SetLiked(collectionID string, userID int, liked bool) error
+	SetSaved(collectionID string, userID int, saved bool) error
+	GetSavedCollections(userID int, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.SavedCollection], error)
+	GetComments(collectionID string, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.CollectionComment], error)
+	GetComment(commentID int64) (*models.CollectionComment, error)
+	CreateComment(collectionID string, userID int, parentID *int64, body string) (*models.CollectionComment, error)
+	UpdateComment(commentID int64, body string) error
+	DeleteComment(commentID int64) error
+}
+
+type Webhook interface {
+	CountWebhooks(userID int) (int, error)
+	CreateWebhook(webhook *models.Webhook) (*models.Webhook, error)
+	GetWebhooks(userID int) ([]models.Webhook, error)
+	GetWebhook(userID int, webhookID int) (*models.Webhook, error)
+	UpdateWebhook(webhook *models.Webhook) (*models.Webhook, error)
+	DeleteWebhook(userID int, webhookID int) error
+	GetWebhookDeliveries(webhookID int, status string, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.WebhookDelivery], error)
+	CreateWebhookDelivery(webhookID int, eventType string, payload any, lease time.Duration) (*models.WebhookDeliveryTask, error)
+	ClaimWebhookDeliveries(limit int, lease time.Duration) ([]models.WebhookDeliveryTask, error)
+	FinishWebhookDelivery(deliveryID int64, result models.WebhookDeliveryResult, retryAt *time.Time) (*models.WebhookDelivery, error)
+}
+
+type Recommendation interface {
+	RecomputeItemSimilarities(params models.SimilarityParams) (int64, bool, error)
+	GetSimilaritiesComputedAt() (*time.Time, error)
+	GetSimilarItems(itemID string, userID int, excludeLibrary bool, limit int) ([]models.SimilarItem, error)
+	GetRecommendations(userID int, itemType string, history int, limit int) ([]models.RecommendedItem, error)
+}
+
+type Chart interface {
+	RecomputeCharts(params models.ChartParams) (int64, bool, error)
+	GetChartsComputedAt() (*time.Time, error)
+	GetChart(query models.ChartQuery, req pagination.PaginationRequest) (*models.Chart, error)
 }
 
 type Repository struct {
 	UserRepository
 	Collection
 	CollectionItem
+	Episode
+	Relation
+	Taxonomy
+	Search
+	Duplicate
+	Moderation
+	Notification
+	Edit
+	Image
+	Metadata
+	Import
+	Export
+	Backup
+	Syndication
+	Social
+	Engagement
+	Webhook
+	Recommendation
+	Chart
 }
 
 func NewRepository(db *sql.DB, logger *zap.SugaredLogger) *Repository {
@@ -49,5 +256,24 @@ func NewRepository(db *sql.DB, logger *zap.SugaredLogger) *Repository {
 		UserRepository: NewUserPostgres(db, logger),
 		Collection:     NewCollectionPostgres(db, logger),
 		CollectionItem: NewCollectionItemPostgres(db, logger),
+		Episode:        NewEpisodePostgres(db, logger),
+		Relation:       NewRelationPostgres(db, logger),
+		Taxonomy:       NewTaxonomyPostgres(db, logger),
+		Search:         NewSearchPostgres(db, logger),
+		Duplicate:      NewDuplicatePostgres(db, logger),
+		Moderation:     NewModerationPostgres(db, logger),
+		Notification:   NewNotificationPostgres(db, logger),
+		Edit:           NewEditPostgres(db, logger),
+		Image:          NewImagePostgres(db, logger),
+		Metadata:       NewMetadataPostgres(db, logger),
+		Import:         NewImportPostgres(db, logger),
+		Export:         NewExportPostgres(db, logger),
+		Backup:         NewBackupPostgres(db, logger),
+		Syndication:    NewSyndicationPostgres(db, logger),
+		Social:         NewSocialPostgres(db, logger),
+		Engagement:     NewEngagementPostgres(db, logger),
+		Webhook:        NewWebhookPostgres(db, logger),
+		Recommendation: NewRecommendationPostgres(db, logger),
+		Chart:          NewChartPostgres(db, logger),
 	}
 }
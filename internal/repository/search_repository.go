@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
+	"go.uber.org/zap"
+)
+
+const (
+	// Подсветка совпадений в названии и фрагмент описания
+	searchTitleHeadlineOptions   = "StartSel=<mark>, StopSel=</mark>, HighlightAll=TRUE"
+	searchSnippetHeadlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=35, MinWords=15, MaxFragments=2"
+)
+
+// escapeHTMLSQL экранирует HTML в тексте до ts_headline: в подсветке разметкой остаются только теги <mark>,
+// а название и описание, введенные пользователями, не попадают к клиентам как HTML
+func escapeHTMLSQL(expr string) string {
+	return fmt.Sprintf(`replace(replace(replace(replace(%s, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;')`, expr)
+}
+
+type SearchRepository struct {
+	db     *sql.DB
+	logger *zap.SugaredLogger
+}
+
+func NewSearchPostgres(db *sql.DB, logger *zap.SugaredLogger) *SearchRepository {
+	return &SearchRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// SearchItems ищет элементы по полнотекстовому индексу и триграммной похожести названия.
+// Видимы публичные элементы и собственные элементы пользователя.
+func (r *SearchRepository) SearchItems(query models.SearchQuery, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.SearchResult], error) {
+	f := &queryFilter{}
+	q := f.arg(query.Query)
+	f.where(fmt.Sprintf("(ci.search_vector @@ tsq.query OR lower(ci.title) %% lower(%s))", q))
+	f.where(fmt.Sprintf("(ci.is_public = TRUE OR ci.creator_id = %s)", f.arg(query.UserID)))
+	if query.Type != "" {
+		f.where("ci.type = " + f.arg(query.Type))
+	}
+
+	tsQuery := fmt.Sprintf(`(SELECT websearch_to_tsquery('russian', %[1]s) || websearch_to_tsquery('english', %[1]s) AS query) tsq`, q)
+
+	countQuery := fmt.Sprintf(`
+		SELECT COUNT(*)
+		FROM %s ci, %s
+		%s
+	`, collectionItemsTable, tsQuery, f.sql())
+
+	var total int64
+	if err := r.db.QueryRow(countQuery, f.args...).Scan(&total); err != nil {
+		r.logger.Errorf("Failed to count search results for %q: %v", query.Query, err)
+		return nil, fmt.Errorf("failed to count search results: %w", err)
+	}
+
+	searchQuery := fmt.Sprintf(`
+		SELECT %[1]s,
+		       ts_rank_cd(ci.search_vector, tsq.query) + similarity(lower(ci.title), lower(%[2]s)) AS rank,
+		       ts_headline('russian', %[10]s, tsq.query, '%[3]s'),
+		       ts_headline('russian', %[11]s, tsq.query, '%[4]s')
+		FROM %[5]s ci, %[6]s
+		%[7]s
+		ORDER BY rank DESC, ci.is_custom ASC, ci.title ASC
+		LIMIT %[8]s OFFSET %[9]s
+	`, collectionItemColumns, q, searchTitleHeadlineOptions, searchSnippetHeadlineOptions,
+		collectionItemsTable, tsQuery, f.sql(), f.arg(req.Limit()), f.arg(req.Offset()),
+		escapeHTMLSQL("ci.title"), escapeHTMLSQL("COALESCE(ci.description, '')"))
+
+	rows, err := r.db.Query(searchQuery, f.args...)
+	if err != nil {
+		r.logger.Errorf("Failed to search items for %q: %v", query.Query, err)
+		return nil, fmt.Errorf("failed to search items: %w", err)
+	}
+	defer rows.Close()
+
+	results := []models.SearchResult{}
+	for rows.Next() {
+		var result models.SearchResult
+		err := scanCollectionItem(rows, &result.Item, &result.Rank, &result.TitleHighlight, &result.Snippet)
+		if err != nil {
+			r.logger.Errorf("Scan failed: %v", err)
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return &pagination.PaginatedResponse[models.SearchResult]{
+		Data:       results,
+		Pagination: req.ToPagination(total),
+	}, nil
+}
@@ -0,0 +1,378 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
+	"go.uber.org/zap"
+)
+
+type SocialRepository struct {
+	db     *sql.DB
+	logger *zap.SugaredLogger
+}
+
+func NewSocialPostgres(db *sql.DB, logger *zap.SugaredLogger) *SocialRepository {
+	return &SocialRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetPublicProfile возвращает профиль пользователя и его подписку с viewerID в обе стороны
+func (r *SocialRepository) GetPublicProfile(userID int, viewerID int) (*models.PublicProfile, error) {
+	query := fmt.Sprintf(`
+		SELECT u.id, u.name, u.avatar_url, u.is_private, u.created_at,
+			(SELECT COUNT(*) FROM %[2]s WHERE followee_id = u.id AND status = '%[3]s'),
+			(SELECT COUNT(*) FROM %[2]s WHERE follower_id = u.id AND status = '%[3]s'),
+			COALESCE((SELECT status FROM %[2]s WHERE follower_id = $2 AND followee_id = u.id), ''),
+			EXISTS (SELECT 1 FROM %[2]s WHERE follower_id = u.id AND followee_id = $2 AND status = '%[3]s')
+		FROM %[1]s u
+		WHERE u.id = $1 AND u.deleted_at IS NULL
+	`, usersTable, followsTable, models.FollowStatusAccepted)
+
+	var profile models.PublicProfile
+	err := r.db.QueryRow(query, userID, viewerID).Scan(
+		&profile.ID, &profile.Name, &profile.AvatarURL, &profile.IsPrivate, &profile.CreatedAt,
+		&profile.FollowersCount, &profile.FollowingCount, &profile.FollowStatus, &profile.FollowsYou,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		r.logger.Errorf("Failed to get profile of user %d: %v", userID, err)
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+	return &profile, nil
+}
+
+// SetAccountPrivate меняет приватность аккаунта. Когда аккаунт становится публичным,
+// ожидающие запросы на подписку принимаются
+func (r *SocialRepository) SetAccountPrivate(userID int, private bool) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(fmt.Sprintf(`UPDATE %s SET is_private = $2, updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`, usersTable),
+		userID, private)
+	if err != nil {
+		r.logger.Errorf("Failed to update privacy of user %d: %v", userID, err)
+		return fmt.Errorf("failed to update privacy: %w", err)
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return ErrNotFound
+	}
+
+	if !private {
+		_, err := tx.Exec(fmt.Sprintf(`
+			UPDATE %s SET status = '%s', accepted_at = NOW() WHERE followee_id = $1 AND status = '%s'
+		`, followsTable, models.FollowStatusAccepted, models.FollowStatusPending), userID)
+		if err != nil {
+			r.logger.Errorf("Failed to accept follow requests of user %d: %v", userID, err)
+			return fmt.Errorf("failed to accept follow requests: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Follow подписывает followerID на followeeID. На приватный аккаунт создается запрос на подписку.
+// Повторная подписка возвращает текущее состояние без нового уведомления
+func (r *SocialRepository) Follow(followerID int, followeeID int) (*models.Follow, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var followeePrivate bool
+	err = tx.QueryRow(fmt.Sprintf(`SELECT is_private FROM %s WHERE id = $1 AND deleted_at IS NULL`, usersTable), followeeID).
+		Scan(&followeePrivate)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	status := models.FollowStatusAccepted
+	if followeePrivate {
+		status = models.FollowStatusPending
+	}
+	var acceptedAt *time.Time
+	if status == models.FollowStatusAccepted {
+		now := time.Now()
+		acceptedAt = &now
+	}
+
+	follow := &models.Follow{FollowerID: followerID, FolloweeID: followeeID}
+	err = tx.QueryRow(fmt.Sprintf(`
+		INSERT INTO %s (follower_id, followee_id, status, accepted_at) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (follower_id, followee_id) DO NOTHING
+		RETURNING status
+	`, followsTable), followerID, followeeID, status, acceptedAt).Scan(&follow.Status)
+	if err == sql.ErrNoRows {
+		err = tx.QueryRow(fmt.Sprintf(`SELECT status FROM %s WHERE follower_id = $1 AND followee_id = $2`, followsTable),
+			followerID, followeeID).Scan(&follow.Status)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get follow: %w", err)
+		}
+		return follow, nil
+	}
+	if err != nil {
+		r.logger.Errorf("Failed to follow user %d by %d: %v", followeeID, followerID, err)
+		return nil, fmt.Errorf("failed to follow: %w", err)
+	}
+
+	notificationType := models.NotificationNewFollower
+	if follow.Status == models.FollowStatusPending {
+		notificationType = models.NotificationFollowRequest
+	}
+	if err := insertFollowNotification(tx, followeeID, followerID, notificationType); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return follow, nil
+}
+
+// DeleteFollow удаляет подписку или запрос на подписку. Используется для отписки,
+// отклонения запроса и удаления подписчика
+func (r *SocialRepository) DeleteFollow(followerID int, followeeID int) error {
+	res, err := r.db.Exec(fmt.Sprintf(`DELETE FROM %s WHERE follower_id = $1 AND followee_id = $2`, followsTable),
+		followerID, followeeID)
+	if err != nil {
+		r.logger.Errorf("Failed to delete follow %d -> %d: %v", followerID, followeeID, err)
+		return fmt.Errorf("failed to delete follow: %w", err)
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// AcceptFollowRequest принимает запрос followerID на подписку на userID и уведомляет автора запроса
+func (r *SocialRepository) AcceptFollowRequest(userID int, followerID int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(fmt.Sprintf(`
+		UPDATE %s SET status = '%s', accepted_at = NOW()
+		WHERE follower_id = $1 AND followee_id = $2 AND status = '%s'
+	`, followsTable, models.FollowStatusAccepted, models.FollowStatusPending), followerID, userID)
+	if err != nil {
+		r.logger.Errorf("Failed to accept follow request %d -> %d: %v", followerID, userID, err)
+		return fmt.Errorf("failed to accept follow request: %w", err)
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return ErrNotFound
+	}
+	if err := insertFollowNotification(tx, followerID, userID, models.NotificationFollowAccepted); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetFollowStatus - статус подписки followerID на followeeID, пустая строка - подписки нет
+func (r *SocialRepository) GetFollowStatus(followerID int, followeeID int) (string, error) {
+	var status string
+	err := r.db.QueryRow(fmt.Sprintf(`SELECT status FROM %s WHERE follower_id = $1 AND followee_id = $2`, followsTable),
+		followerID, followeeID).Scan(&status)
+	if err != nil && err != sql.ErrNoRows {
+		return "", fmt.Errorf("failed to get follow status: %w", err)
+	}
+	return status, nil
+}
+
+// GetFollowers - подписчики пользователя (status accepted) или входящие запросы на подписку (pending)
+func (r *SocialRepository) GetFollowers(userID int, status string, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.FollowUser], error) {
+	return r.getFollows("f.followee_id", "f.follower_id", userID, status, req)
+}
+
+// GetFollowing - подписки пользователя
+func (r *SocialRepository) GetFollowing(userID int, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.FollowUser], error) {
+	return r.getFollows("f.follower_id", "f.followee_id", userID, models.FollowStatusAccepted, req)
+}
+
+// getFollows - страница связей, где column = userID. other - колонка пользователя, который попадает в список
+func (r *SocialRepository) getFollows(column string, other string, userID int, status string, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.FollowUser], error) {
+	var total int64
+	countQuery := fmt.Sprintf(`
+		SELECT COUNT(*) FROM %s f JOIN %s u ON u.id = %s AND u.deleted_at IS NULL
+		WHERE %s = $1 AND f.status = $2
+	`, followsTable, usersTable, other, column)
+	if err := r.db.QueryRow(countQuery, userID, status).Scan(&total); err != nil {
+		r.logger.Errorf("Failed to count follows of user %d: %v", userID, err)
+		return nil, fmt.Errorf("failed to count follows: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT u.id, u.name, u.avatar_url, u.is_private, f.status, f.created_at, f.accepted_at
+		FROM %s f
+		JOIN %s u ON u.id = %s AND u.deleted_at IS NULL
+		WHERE %s = $1 AND f.status = $2
+		ORDER BY COALESCE(f.accepted_at, f.created_at) DESC, u.id
+		LIMIT $3 OFFSET $4
+	`, followsTable, usersTable, other, column)
+	rows, err := r.db.Query(query, userID, status, req.Limit(), req.Offset())
+	if err != nil {
+		r.logger.Errorf("Failed to get follows of user %d: %v", userID, err)
+		return nil, fmt.Errorf("failed to get follows: %w", err)
+	}
+	defer rows.Close()
+
+	users := []models.FollowUser{}
+	for rows.Next() {
+		var user models.FollowUser
+		err := rows.Scan(&user.ID, &user.Name, &user.AvatarURL, &user.IsPrivate, &user.Status, &user.CreatedAt, &user.AcceptedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan follow: %w", err)
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return &pagination.PaginatedResponse[models.FollowUser]{
+		Data:       users,
+		Pagination: req.ToPagination(total),
+	}, nil
+}
+
+// Новые события первыми
+var activityKeyset = keyset{scope: "activity", desc: true, columns: []keysetColumn{
+	{expr: "e.created_at", cast: "timestamptz"},
+	{expr: "e.id", cast: "bigint"},
+}}
+
+// activityVisible - условие видимости события другим пользователям: добавления в приватные коллекции
+// и свои приватные элементы вне публичных коллекций не показываются
+const activityVisible = "(e.collection_id IS NULL OR c.is_public = TRUE) AND (e.item_id IS NULL OR ci.is_public = TRUE OR e.collection_id IS NOT NULL)"
+
+// GetHomeFeed - события пользователей, на которых подписан userID. Лента собирается при чтении
+// по индексу (user_id, created_at): подписок немного, и каждая дает лишь последние события
+func (r *SocialRepository) GetHomeFeed(userID int, req pagination.CursorRequest) (*pagination.CursorResponse[models.Activity], error) {
+	f := &queryFilter{}
+	f.where(fmt.Sprintf("e.user_id IN (SELECT followee_id FROM %s WHERE follower_id = %s AND status = '%s')",
+		followsTable, f.arg(userID), models.FollowStatusAccepted))
+	return r.queryActivity(f, req)
+}
+
+// GetUserActivity - события пользователя, видимые другим пользователям
+func (r *SocialRepository) GetUserActivity(userID int, req pagination.CursorRequest) (*pagination.CursorResponse[models.Activity], error) {
+	f := &queryFilter{}
+	f.where("e.user_id = " + f.arg(userID))
+	return r.queryActivity(f, req)
+}
+
+func (r *SocialRepository) queryActivity(f *queryFilter, req pagination.CursorRequest) (*pagination.CursorResponse[models.Activity], error) {
+	f.where(activityVisible)
+	orderBy, err := activityKeyset.apply(f, req)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT e.id, e.type, e.payload, e.created_at,
+			u.id, u.name, u.avatar_url, u.is_private,
+			ci.id, COALESCE(ci.type, ''), ci.title, ci.cover_image,
+			c.id, c.name
+		FROM %s e
+		JOIN %s u ON u.id = e.user_id AND u.deleted_at IS NULL
+		LEFT JOIN %s ci ON ci.id = e.item_id
+		LEFT JOIN %s c ON c.id = e.collection_id
+		%s
+		ORDER BY %s
+		LIMIT %s
+	`, activityEventsTable, usersTable, collectionItemsTable, collectionsTable, f.sql(), orderBy, f.arg(req.FetchLimit()))
+
+	rows, err := r.db.Query(query, f.args...)
+	if err != nil {
+		r.logger.Errorf("Failed to get activity: %v", err)
+		return nil, fmt.Errorf("failed to get activity: %w", err)
+	}
+	defer rows.Close()
+
+	events := []models.Activity{}
+	for rows.Next() {
+		var event models.Activity
+		var itemID, itemTitle, collectionID, collectionName sql.NullString
+		var itemType string
+		var itemCover *string
+		err := rows.Scan(&event.ID, &event.Type, &event.Payload, &event.CreatedAt,
+			&event.User.ID, &event.User.Name, &event.User.AvatarURL, &event.User.IsPrivate,
+			&itemID, &itemType, &itemTitle, &itemCover,
+			&collectionID, &collectionName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan activity: %w", err)
+		}
+		if itemID.Valid {
+			event.Item = &models.ActivityItem{ID: itemID.String, Type: itemType, Title: itemTitle.String, CoverImage: itemCover}
+		}
+		if collectionID.Valid {
+			event.Collection = &models.ActivityCollection{ID: collectionID.String, Name: collectionName.String}
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	keys := func(event models.Activity) []string {
+		return []string{event.CreatedAt.Format(time.RFC3339Nano), strconv.FormatInt(event.ID, 10)}
+	}
+	return pagination.NewCursorResponse(events, req, activityKeyset.scope, keys, nil), nil
+}
+
+// insertActivity пишет событие активности в транзакции действия, которое его вызвало.
+// itemID и collectionID - пустая строка, если событие к ним не относится
+func insertActivity(q queryRower, userID int, activityType string, itemID string, collectionID string, payload any) error {
+	data := []byte("{}")
+	if payload != nil {
+		var err error
+		if data, err = json.Marshal(payload); err != nil {
+			return fmt.Errorf("failed to marshal activity payload: %w", err)
+		}
+	}
+
+	var id int64
+	err := q.QueryRow(fmt.Sprintf(`
+		INSERT INTO %s (user_id, type, item_id, collection_id, payload)
+		VALUES ($1, $2, NULLIF($3, '')::uuid, NULLIF($4, '')::uuid, $5)
+		RETURNING id
+	`, activityEventsTable), userID, activityType, itemID, collectionID, data).Scan(&id)
+	if err != nil {
+		return fmt.Errorf("failed to record activity: %w", err)
+	}
+	return nil
+}
+
+// insertFollowNotification уведомляет userID о действии actorID: подписке, запросе или принятии запроса
+func insertFollowNotification(q queryRower, userID int, actorID int, notificationType string) error {
+	var name string
+	if err := q.QueryRow(fmt.Sprintf(`SELECT name FROM %s WHERE id = $1`, usersTable), actorID).Scan(&name); err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	_, err := insertNotification(q, userID, notificationType, models.FollowNotificationPayload{UserID: actorID, UserName: name})
+	return err
+}
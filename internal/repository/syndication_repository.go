@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"go.uber.org/zap"
+)
+
+type SyndicationRepository struct {
+	db     *sql.DB
+	logger *zap.SugaredLogger
+}
+
+func NewSyndicationPostgres(db *sql.DB, logger *zap.SugaredLogger) *SyndicationRepository {
+	return &SyndicationRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetPublicActivity возвращает последние limit событий в публичных коллекциях пользователя:
+// создание коллекций и добавление в них элементов. Активность закрытого аккаунта не отдается - ErrNotFound
+func (r *SyndicationRepository) GetPublicActivity(userID int, limit int) ([]models.PublicActivity, error) {
+	var isPrivate bool
+	err := r.db.QueryRow(fmt.Sprintf(`SELECT is_private FROM %s WHERE id = $1 AND deleted_at IS NULL`, usersTable), userID).Scan(&isPrivate)
+	if err == sql.ErrNoRows || isPrivate {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		r.logger.Errorf("Failed to get privacy of user %d: %v", userID, err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	var activity []models.PublicActivity
+	collectionRows, err := r.db.Query(fmt.Sprintf(`
+		SELECT id, name, created_at
+		FROM %s
+		WHERE user_id = $1 AND is_public = TRUE
+		ORDER BY created_at DESC
+		LIMIT $2
+	`, collectionsTable), userID, limit)
+	if err != nil {
+		r.logger.Errorf("Failed to get public collections of user %d: %v", userID, err)
+		return nil, fmt.Errorf("failed to get public collections: %w", err)
+	}
+	defer collectionRows.Close()
+	for collectionRows.Next() {
+		event := models.PublicActivity{Type: models.PublicActivityCollectionCreated}
+		if err := collectionRows.Scan(&event.CollectionID, &event.CollectionName, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan collection: %w", err)
+		}
+		activity = append(activity, event)
+	}
+	if err := collectionRows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	itemRows, err := r.db.Query(fmt.Sprintf(`
+		SELECT %s, c.id, c.name, cia.user_review, cia.added_at
+		FROM %s cia
+		JOIN %s c ON c.id = cia.collection_id
+		JOIN %s ci ON ci.id = cia.item_id
+		WHERE c.user_id = $1 AND c.is_public = TRUE AND cia.added_at IS NOT NULL
+		ORDER BY cia.added_at DESC
+		LIMIT $2
+	`, collectionItemColumns, collectionItemsAssignmentTable, collectionsTable, collectionItemsTable), userID, limit)
+	if err != nil {
+		r.logger.Errorf("Failed to get public collection items of user %d: %v", userID, err)
+		return nil, fmt.Errorf("failed to get public collection items: %w", err)
+	}
+	defer itemRows.Close()
+	for itemRows.Next() {
+		event := models.PublicActivity{Type: models.PublicActivityItemAdded, Item: &models.CollectionItem{}}
+		err := scanCollectionItem(itemRows, event.Item, &event.CollectionID, &event.CollectionName, &event.Review, &event.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan collection item: %w", err)
+		}
+		activity = append(activity, event)
+	}
+	if err := itemRows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	sort.SliceStable(activity, func(i, j int) bool {
+		return activity[i].CreatedAt.After(activity[j].CreatedAt)
+	})
+	if len(activity) > limit {
+		activity = activity[:limit]
+	}
+	return activity, nil
+}
+
+// GetRecentCatalogItems возвращает limit элементов, последними появившихся в публичном каталоге.
+// Свой элемент появляется в каталоге в момент одобрения, а не создания
+func (r *SyndicationRepository) GetRecentCatalogItems(limit int) ([]models.CatalogEntry, error) {
+	query := fmt.Sprintf(`
+		SELECT %[1]s, p.published_at
+		FROM (
+			SELECT DISTINCT ON (id) id, published_at
+			FROM (
+				(SELECT id, created_at AS published_at FROM %[2]s WHERE is_public = TRUE ORDER BY created_at DESC LIMIT $1)
+				UNION ALL
+				(SELECT item_id, created_at FROM %[3]s WHERE decision = '%[4]s' ORDER BY created_at DESC LIMIT $1)
+			) recent
+			ORDER BY id, published_at DESC
+		) p
+		JOIN %[2]s ci ON ci.id = p.id
+		WHERE ci.is_public = TRUE
+		ORDER BY p.published_at DESC
+		LIMIT $1
+	`, collectionItemColumns, collectionItemsTable, moderationDecisionsTable, models.ModerationDecisionApproved)
+
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		r.logger.Errorf("Failed to get recent catalog items: %v", err)
+		return nil, fmt.Errorf("failed to get recent catalog items: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []models.CatalogEntry
+	for rows.Next() {
+		var entry models.CatalogEntry
+		if err := scanCollectionItem(rows, &entry.CollectionItem, &entry.PublishedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan catalog item: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,326 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+type TaxonomyRepository struct {
+	db     *sql.DB
+	logger *zap.SugaredLogger
+}
+
+func NewTaxonomyPostgres(db *sql.DB, logger *zap.SugaredLogger) *TaxonomyRepository {
+	return &TaxonomyRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *TaxonomyRepository) GetGenres() ([]models.Genre, error) {
+	query := fmt.Sprintf(`SELECT id, slug, name, created_at FROM %s ORDER BY name ASC`, genresTable)
+	return r.queryGenres(query)
+}
+
+func (r *TaxonomyRepository) CreateGenre(genre *models.Genre) (*models.Genre, error) {
+	query := fmt.Sprintf(`INSERT INTO %s (slug, name) VALUES ($1, $2) RETURNING id, created_at`, genresTable)
+
+	if err := r.db.QueryRow(query, genre.Slug, genre.Name).Scan(&genre.ID, &genre.CreatedAt); err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrAlreadyExists
+		}
+		r.logger.Errorf("Failed to create genre %s: %v", genre.Slug, err)
+		return nil, fmt.Errorf("failed to create genre: %w", err)
+	}
+
+	return genre, nil
+}
+
+func (r *TaxonomyRepository) GetItemGenres(itemID string) ([]models.Genre, error) {
+	query := fmt.Sprintf(`
+		SELECT g.id, g.slug, g.name, g.created_at
+		FROM %s g
+		JOIN %s ig ON ig.genre_id = g.id
+		WHERE ig.item_id = $1
+		ORDER BY g.name ASC
+	`, genresTable, itemGenresTable)
+
+	return r.queryGenres(query, itemID)
+}
+
+// SetItemGenres заменяет жанры элемента. Возвращает ErrNotFound, если какой-то из slug не существует
+func (r *TaxonomyRepository) SetItemGenres(itemID string, slugs []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(fmt.Sprintf(`DELETE FROM %s WHERE item_id = $1`, itemGenresTable), itemID); err != nil {
+		r.logger.Errorf("Failed to clear genres of item %s: %v", itemID, err)
+		return fmt.Errorf("failed to clear item genres: %w", err)
+	}
+
+	insertQuery := fmt.Sprintf(`
+		INSERT INTO %s (item_id, genre_id)
+		SELECT $1, id FROM %s WHERE slug = ANY($2)
+	`, itemGenresTable, genresTable)
+
+	result, err := tx.Exec(insertQuery, itemID, pq.Array(slugs))
+	if err != nil {
+		r.logger.Errorf("Failed to set genres of item %s: %v", itemID, err)
+		return fmt.Errorf("failed to set item genres: %w", err)
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if int(inserted) != len(slugs) {
+		return ErrNotFound
+	}
+
+	return tx.Commit()
+}
+
+// GetUserTags возвращает теги пользователя с количеством использований
+func (r *TaxonomyRepository) GetUserTags(userID int) ([]models.Tag, error) {
+	query := fmt.Sprintf(`
+		SELECT t.id, t.user_id, t.name, t.created_at,
+		       (SELECT COUNT(*) FROM %s it WHERE it.tag_id = t.id) +
+		       (SELECT COUNT(*) FROM %s ct WHERE ct.tag_id = t.id) AS usage_count
+		FROM %s t
+		WHERE t.user_id = $1
+		ORDER BY usage_count DESC, lower(t.name) ASC
+	`, itemTagsTable, collectionTagsTable, tagsTable)
+
+	return r.queryTags(query, userID)
+}
+
+// SuggestTags - автодополнение тегов пользователя по префиксу
+func (r *TaxonomyRepository) SuggestTags(userID int, prefix string, limit int) ([]models.Tag, error) {
+	query := fmt.Sprintf(`
+		SELECT t.id, t.user_id, t.name, t.created_at,
+		       (SELECT COUNT(*) FROM %s it WHERE it.tag_id = t.id) +
+		       (SELECT COUNT(*) FROM %s ct WHERE ct.tag_id = t.id) AS usage_count
+		FROM %s t
+		WHERE t.user_id = $1 AND lower(t.name) LIKE $2
+		ORDER BY usage_count DESC, lower(t.name) ASC
+		LIMIT $3
+	`, itemTagsTable, collectionTagsTable, tagsTable)
+
+	return r.queryTags(query, userID, escapeLike(prefix)+"%", limit)
+}
+
+func (r *TaxonomyRepository) GetItemTags(userID int, itemID string) ([]models.Tag, error) {
+	query := fmt.Sprintf(`
+		SELECT t.id, t.user_id, t.name, t.created_at, 0
+		FROM %s t
+		JOIN %s it ON it.tag_id = t.id
+		WHERE it.user_id = $1 AND it.item_id = $2
+		ORDER BY lower(t.name) ASC
+	`, tagsTable, itemTagsTable)
+
+	return r.queryTags(query, userID, itemID)
+}
+
+// SetItemTags заменяет теги пользователя на элементе, создавая отсутствующие теги
+func (r *TaxonomyRepository) SetItemTags(userID int, itemID string, names []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(fmt.Sprintf(`DELETE FROM %s WHERE user_id = $1 AND item_id = $2`, itemTagsTable), userID, itemID); err != nil {
+		r.logger.Errorf("Failed to clear tags of item %s for user %d: %v", itemID, userID, err)
+		return fmt.Errorf("failed to clear item tags: %w", err)
+	}
+
+	insertQuery := fmt.Sprintf(`INSERT INTO %s (user_id, item_id, tag_id) VALUES ($1, $2, $3)`, itemTagsTable)
+	for _, name := range names {
+		tagID, err := upsertTag(tx, userID, name)
+		if err != nil {
+			r.logger.Errorf("Failed to upsert tag %s for user %d: %v", name, userID, err)
+			return fmt.Errorf("failed to create tag: %w", err)
+		}
+		if _, err := tx.Exec(insertQuery, userID, itemID, tagID); err != nil {
+			r.logger.Errorf("Failed to tag item %s for user %d: %v", itemID, userID, err)
+			return fmt.Errorf("failed to tag item: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *TaxonomyRepository) GetCollectionTags(collectionID string) ([]models.Tag, error) {
+	query := fmt.Sprintf(`
+		SELECT t.id, t.user_id, t.name, t.created_at, 0
+		FROM %s t
+		JOIN %s ct ON ct.tag_id = t.id
+		WHERE ct.collection_id = $1
+		ORDER BY lower(t.name) ASC
+	`, tagsTable, collectionTagsTable)
+
+	return r.queryTags(query, collectionID)
+}
+
+// SetCollectionTags заменяет теги коллекции тегами ее владельца
+func (r *TaxonomyRepository) SetCollectionTags(userID int, collectionID string, names []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(fmt.Sprintf(`DELETE FROM %s WHERE collection_id = $1`, collectionTagsTable), collectionID); err != nil {
+		r.logger.Errorf("Failed to clear tags of collection %s: %v", collectionID, err)
+		return fmt.Errorf("failed to clear collection tags: %w", err)
+	}
+
+	insertQuery := fmt.Sprintf(`INSERT INTO %s (collection_id, tag_id) VALUES ($1, $2)`, collectionTagsTable)
+	for _, name := range names {
+		tagID, err := upsertTag(tx, userID, name)
+		if err != nil {
+			r.logger.Errorf("Failed to upsert tag %s for user %d: %v", name, userID, err)
+			return fmt.Errorf("failed to create tag: %w", err)
+		}
+		if _, err := tx.Exec(insertQuery, collectionID, tagID); err != nil {
+			r.logger.Errorf("Failed to tag collection %s: %v", collectionID, err)
+			return fmt.Errorf("failed to tag collection: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// IsItemInUserLibrary проверяет, что элемент добавлен хотя бы в одну коллекцию пользователя
+func (r *TaxonomyRepository) IsItemInUserLibrary(userID int, itemID string) (bool, error) {
+	query := fmt.Sprintf(`
+		SELECT EXISTS(
+			SELECT 1 FROM %s cia
+			JOIN %s c ON c.id = cia.collection_id
+			WHERE c.user_id = $1 AND cia.item_id = $2
+		)
+	`, collectionItemsAssignmentTable, collectionsTable)
+
+	var exists bool
+	if err := r.db.QueryRow(query, userID, itemID).Scan(&exists); err != nil {
+		r.logger.Errorf("Failed to check library of user %d for item %s: %v", userID, itemID, err)
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (r *TaxonomyRepository) queryGenres(query string, args ...any) ([]models.Genre, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		r.logger.Errorf("Failed to get genres: %v", err)
+		return nil, fmt.Errorf("failed to get genres: %w", err)
+	}
+	defer rows.Close()
+
+	genres := []models.Genre{}
+	for rows.Next() {
+		var genre models.Genre
+		if err := rows.Scan(&genre.ID, &genre.Slug, &genre.Name, &genre.CreatedAt); err != nil {
+			r.logger.Errorf("Scan failed: %v", err)
+			return nil, fmt.Errorf("failed to scan genre: %w", err)
+		}
+		genres = append(genres, genre)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return genres, nil
+}
+
+func (r *TaxonomyRepository) queryTags(query string, args ...any) ([]models.Tag, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		r.logger.Errorf("Failed to get tags: %v", err)
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []models.Tag{}
+	for rows.Next() {
+		var tag models.Tag
+		if err := rows.Scan(&tag.ID, &tag.UserID, &tag.Name, &tag.CreatedAt, &tag.UsageCount); err != nil {
+			r.logger.Errorf("Scan failed: %v", err)
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return tags, nil
+}
+
+func upsertTag(tx *sql.Tx, userID int, name string) (int, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO %[1]s (user_id, name) VALUES ($1, $2)
+		ON CONFLICT (user_id, (lower(name))) DO UPDATE SET name = %[1]s.name
+		RETURNING id
+	`, tagsTable)
+
+	var id int
+	err := tx.QueryRow(query, userID, name).Scan(&id)
+	return id, err
+}
+
+// matchCondition строит условие совпадения со значениями из связанной таблицы.
+// countQuery - подзапрос "SELECT COUNT(DISTINCT ...) ... = ANY(%s)", куда подставляется массив значений.
+// При matchAll должны совпасть все значения (AND), иначе хотя бы одно (OR).
+func matchCondition(f *queryFilter, countQuery string, values []string, matchAll bool) string {
+	subquery := fmt.Sprintf(countQuery, f.arg(pq.Array(values)))
+	if matchAll {
+		return fmt.Sprintf("(%s) = %s", subquery, f.arg(len(values)))
+	}
+	return fmt.Sprintf("(%s) > 0", subquery)
+}
+
+// itemTagsCondition - условие по тегам пользователя для элемента в колонке itemColumn
+func itemTagsCondition(f *queryFilter, itemColumn string, filter models.TagFilter) string {
+	countQuery := fmt.Sprintf(`
+		SELECT COUNT(DISTINCT lower(t.name)) FROM %s it JOIN %s t ON t.id = it.tag_id
+		WHERE it.item_id = %s AND it.user_id = %s AND lower(t.name) = ANY(%%s)
+	`, itemTagsTable, tagsTable, itemColumn, f.arg(filter.UserID))
+
+	return matchCondition(f, countQuery, filter.Tags, filter.MatchAll)
+}
+
+// collectionTagsCondition - условие по тегам коллекции в колонке collectionColumn
+func collectionTagsCondition(f *queryFilter, collectionColumn string, filter models.TagFilter) string {
+	countQuery := fmt.Sprintf(`
+		SELECT COUNT(DISTINCT lower(t.name)) FROM %s ct JOIN %s t ON t.id = ct.tag_id
+		WHERE ct.collection_id = %s AND lower(t.name) = ANY(%%s)
+	`, collectionTagsTable, tagsTable, collectionColumn)
+
+	return matchCondition(f, countQuery, filter.Tags, filter.MatchAll)
+}
+
+// itemGenresCondition - условие по slug жанров для элемента в колонке itemColumn
+func itemGenresCondition(f *queryFilter, itemColumn string, slugs []string, matchAll bool) string {
+	countQuery := fmt.Sprintf(`
+		SELECT COUNT(DISTINCT g.id) FROM %s ig JOIN %s g ON g.id = ig.genre_id
+		WHERE ig.item_id = %s AND g.slug = ANY(%%s)
+	`, itemGenresTable, genresTable, itemColumn)
+
+	return matchCondition(f, countQuery, slugs, matchAll)
+}
+
+// escapeLike экранирует спецсимволы LIKE в пользовательском вводе
+func escapeLike(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(value)
+}
@@ -313,6 +313,22 @@ func (r *userRepository) UpdateLastLogin(userID int) error {
 	return nil
 }
 
+func (r *userRepository) GetUserRole(userID int) (string, error) {
+	query := `SELECT role FROM users WHERE id = $1 AND deleted_at IS NULL`
+
+	var role string
+	err := r.db.QueryRow(query, userID).Scan(&role)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", fmt.Errorf("user not found")
+		}
+		r.logger.Errorf("Failed to get role for user %d: %v", userID, err)
+		return "", err
+	}
+
+	return role, nil
+}
+
 func (r *userRepository) GetActiveUsersCount(since time.Time) (int, error) {
 	query := `SELECT COUNT(*) FROM users 
 	          WHERE last_login_at >= $1 AND deleted_at IS NULL`
@@ -0,0 +1,376 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+const webhookColumns = "id, user_id, url, description, events, is_active, created_at, updated_at"
+
+const webhookDeliveryColumns = `d.id, d.webhook_id, d.event_type, d.payload, d.status, d.attempts,
+	d.response_status, d.response_body, d.duration_ms, d.last_error,
+	CASE WHEN d.status = 'pending' THEN d.next_attempt_at END, d.delivered_at, d.created_at`
+
+type WebhookRepository struct {
+	db     *sql.DB
+	logger *zap.SugaredLogger
+}
+
+func NewWebhookPostgres(db *sql.DB, logger *zap.SugaredLogger) *WebhookRepository {
+	return &WebhookRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *WebhookRepository) CountWebhooks(userID int) (int, error) {
+	var count int
+	if err := r.db.QueryRow(fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE user_id = $1`, webhooksTable), userID).Scan(&count); err != nil {
+		r.logger.Errorf("Failed to count webhooks of user %d: %v", userID, err)
+		return 0, fmt.Errorf("failed to count webhooks: %w", err)
+	}
+	return count, nil
+}
+
+// CreateWebhook сохраняет вебхук, секрет возвращается только в результате создания
+func (r *WebhookRepository) CreateWebhook(webhook *models.Webhook) (*models.Webhook, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (user_id, url, description, secret, events, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING %s
+	`, webhooksTable, webhookColumns)
+
+	created, err := scanWebhook(r.db.QueryRow(query, webhook.UserID, webhook.URL, webhook.Description,
+		webhook.Secret, pq.Array(webhook.Events), webhook.IsActive))
+	if err != nil {
+		r.logger.Errorf("Failed to create webhook for user %d: %v", webhook.UserID, err)
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+	created.Secret = webhook.Secret
+	return created, nil
+}
+
+func (r *WebhookRepository) GetWebhooks(userID int) ([]models.Webhook, error) {
+	rows, err := r.db.Query(fmt.Sprintf(`
+		SELECT %s FROM %s WHERE user_id = $1 ORDER BY created_at, id
+	`, webhookColumns, webhooksTable), userID)
+	if err != nil {
+		r.logger.Errorf("Failed to get webhooks of user %d: %v", userID, err)
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := []models.Webhook{}
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		webhooks = append(webhooks, *webhook)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+	return webhooks, nil
+}
+
+func (r *WebhookRepository) GetWebhook(userID int, webhookID int) (*models.Webhook, error) {
+	webhook, err := scanWebhook(r.db.QueryRow(fmt.Sprintf(`
+		SELECT %s FROM %s WHERE id = $1 AND user_id = $2
+	`, webhookColumns, webhooksTable), webhookID, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		r.logger.Errorf("Failed to get webhook %d: %v", webhookID, err)
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+	return webhook, nil
+}
+
+// UpdateWebhook меняет адрес, описание, события и активность. Секрет не меняется
+func (r *WebhookRepository) UpdateWebhook(webhook *models.Webhook) (*models.Webhook, error) {
+	query := fmt.Sprintf(`
+		UPDATE %s SET url = $3, description = $4, events = $5, is_active = $6, updated_at = NOW()
+		WHERE id = $1 AND user_id = $2
+		RETURNING %s
+	`, webhooksTable, webhookColumns)
+
+	updated, err := scanWebhook(r.db.QueryRow(query, webhook.ID, webhook.UserID, webhook.URL, webhook.Description,
+		pq.Array(webhook.Events), webhook.IsActive))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		r.logger.Errorf("Failed to update webhook %d: %v", webhook.ID, err)
+		return nil, fmt.Errorf("failed to update webhook: %w", err)
+	}
+	return updated, nil
+}
+
+// DeleteWebhook удаляет вебхук вместе с журналом отправок
+func (r *WebhookRepository) DeleteWebhook(userID int, webhookID int) error {
+	result, err := r.db.Exec(fmt.Sprintf(`DELETE FROM %s WHERE id = $1 AND user_id = $2`, webhooksTable), webhookID, userID)
+	if err != nil {
+		r.logger.Errorf("Failed to delete webhook %d: %v", webhookID, err)
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetWebhookDeliveries - журнал отправок вебхука, сначала новые. status - фильтр по статусу, пустой - все
+func (r *WebhookRepository) GetWebhookDeliveries(webhookID int, status string, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.WebhookDelivery], error) {
+	f := &queryFilter{}
+	f.where("d.webhook_id = " + f.arg(webhookID))
+	if status != "" {
+		f.where("d.status = " + f.arg(status))
+	}
+
+	var total int64
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM %s d %s`, webhookDeliveriesTable, f.sql())
+	if err := r.db.QueryRow(countQuery, f.args...).Scan(&total); err != nil {
+		r.logger.Errorf("Failed to count deliveries of webhook %d: %v", webhookID, err)
+		return nil, fmt.Errorf("failed to count webhook deliveries: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s d
+		%s
+		ORDER BY d.created_at DESC, d.id DESC
+		LIMIT %s OFFSET %s
+	`, webhookDeliveryColumns, webhookDeliveriesTable, f.sql(), f.arg(req.Limit()), f.arg(req.Offset()))
+
+	rows, err := r.db.Query(query, f.args...)
+	if err != nil {
+		r.logger.Errorf("Failed to get deliveries of webhook %d: %v", webhookID, err)
+		return nil, fmt.Errorf("failed to get webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		var delivery models.WebhookDelivery
+		if err := rows.Scan(webhookDeliveryFields(&delivery)...); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	return &pagination.PaginatedResponse[models.WebhookDelivery]{
+		Data:       deliveries,
+		Pagination: req.ToPagination(total),
+	}, nil
+}
+
+// CreateWebhookDelivery создает уже забранную отправку, чтобы отправить ее сразу (тестовое событие).
+// Фоновый обработчик ее не тронет до истечения lease
+func (r *WebhookRepository) CreateWebhookDelivery(webhookID int, eventType string, payload any, lease time.Duration) (*models.WebhookDeliveryTask, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		WITH d AS (
+			INSERT INTO %[1]s (webhook_id, event_type, payload, attempts, next_attempt_at)
+			VALUES ($1, $2, $3, 1, NOW() + $4 * INTERVAL '1 second')
+			RETURNING *
+		)
+		SELECT %[3]s, w.user_id, w.url, w.secret
+		FROM d JOIN %[2]s w ON w.id = d.webhook_id
+	`, webhookDeliveriesTable, webhooksTable, webhookDeliveryColumns)
+
+	var task models.WebhookDeliveryTask
+	err = r.db.QueryRow(query, webhookID, eventType, data, int(lease.Seconds())).
+		Scan(append(webhookDeliveryFields(&task.WebhookDelivery), &task.UserID, &task.URL, &task.Secret)...)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		r.logger.Errorf("Failed to create delivery for webhook %d: %v", webhookID, err)
+		return nil, fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+	return &task, nil
+}
+
+// ClaimWebhookDeliveries забирает отправки, которым пора уйти, и откладывает их на lease, чтобы их не забрал другой обработчик.
+// Отправки выключенных вебхуков не забираются и ждут, пока вебхук снова включат
+func (r *WebhookRepository) ClaimWebhookDeliveries(limit int, lease time.Duration) ([]models.WebhookDeliveryTask, error) {
+	query := fmt.Sprintf(`
+		WITH d AS (
+			UPDATE %[1]s SET attempts = attempts + 1, next_attempt_at = NOW() + $2 * INTERVAL '1 second'
+			WHERE id IN (
+				SELECT d.id FROM %[1]s d
+				JOIN %[2]s w ON w.id = d.webhook_id
+				WHERE d.status = '%[4]s' AND d.next_attempt_at <= NOW() AND w.is_active
+				ORDER BY d.next_attempt_at
+				LIMIT $1
+				FOR UPDATE OF d SKIP LOCKED
+			)
+			RETURNING *
+		)
+		SELECT %[3]s, w.user_id, w.url, w.secret
+		FROM d JOIN %[2]s w ON w.id = d.webhook_id
+		ORDER BY d.created_at, d.id
+	`, webhookDeliveriesTable, webhooksTable, webhookDeliveryColumns, models.DeliveryStatusPending)
+
+	rows, err := r.db.Query(query, limit, int(lease.Seconds()))
+	if err != nil {
+		r.logger.Errorf("Failed to claim webhook deliveries: %v", err)
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := []models.WebhookDeliveryTask{}
+	for rows.Next() {
+		var task models.WebhookDeliveryTask
+		if err := rows.Scan(append(webhookDeliveryFields(&task.WebhookDelivery), &task.UserID, &task.URL, &task.Secret)...); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+	return tasks, nil
+}
+
+// FinishWebhookDelivery записывает результат попытки. result.Error = nil - доставлено; retryAt = nil - попытки исчерпаны
+func (r *WebhookRepository) FinishWebhookDelivery(deliveryID int64, result models.WebhookDeliveryResult, retryAt *time.Time) (*models.WebhookDelivery, error) {
+	var lastError *string
+	status := models.DeliveryStatusPending
+	switch {
+	case result.Error == nil:
+		status = models.DeliveryStatusSent
+	case retryAt == nil:
+		status = models.DeliveryStatusFailed
+	}
+	if result.Error != nil {
+		message := result.Error.Error()
+		lastError = &message
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s d SET status = $2, response_status = $3, response_body = $4, duration_ms = $5, last_error = $6,
+			next_attempt_at = COALESCE($7, d.next_attempt_at),
+			delivered_at = CASE WHEN $2 = '%s' THEN NOW() END
+		WHERE d.id = $1
+		RETURNING %s
+	`, webhookDeliveriesTable, models.DeliveryStatusSent, webhookDeliveryColumns)
+
+	var delivery models.WebhookDelivery
+	err := r.db.QueryRow(query, deliveryID, status, result.ResponseStatus, result.ResponseBody, result.DurationMs,
+		lastError, retryAt).Scan(webhookDeliveryFields(&delivery)...)
+	if err != nil {
+		r.logger.Errorf("Failed to save result of webhook delivery %d: %v", deliveryID, err)
+		return nil, fmt.Errorf("failed to save webhook delivery result: %w", err)
+	}
+	return &delivery, nil
+}
+
+// enqueueWebhookEvent ставит событие в очередь отправки всех активных вебхуков пользователя, подписанных на этот тип,
+// в транзакции вызывающего. build собирает данные события и вызывается, только если такие вебхуки есть
+func enqueueWebhookEvent(q queryRower, userID int, eventType string, build func() (any, error)) error {
+	var subscribed bool
+	err := q.QueryRow(fmt.Sprintf(`
+		SELECT EXISTS (SELECT 1 FROM %s WHERE user_id = $1 AND is_active AND $2 = ANY(events))
+	`, webhooksTable), userID, eventType).Scan(&subscribed)
+	if err != nil {
+		return fmt.Errorf("failed to check webhooks: %w", err)
+	}
+	if !subscribed {
+		return nil
+	}
+
+	payload, err := build()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	var queued int
+	err = q.QueryRow(fmt.Sprintf(`
+		WITH queued AS (
+			INSERT INTO %s (webhook_id, event_type, payload)
+			SELECT id, $2, $3 FROM %s WHERE user_id = $1 AND is_active AND $2 = ANY(events)
+			RETURNING id
+		)
+		SELECT COUNT(*) FROM queued
+	`, webhookDeliveriesTable, webhooksTable), userID, eventType, data).Scan(&queued)
+	if err != nil {
+		return fmt.Errorf("failed to queue webhook event: %w", err)
+	}
+	return nil
+}
+
+// webhookItem - краткие данные элемента для событий вебхуков
+func webhookItem(q queryRower, itemID string) (models.WebhookItem, error) {
+	item := models.WebhookItem{ID: itemID}
+	err := q.QueryRow(fmt.Sprintf(`SELECT type, title FROM %s WHERE id = $1`, collectionItemsTable), itemID).
+		Scan(&item.Type, &item.Title)
+	if err != nil {
+		return item, fmt.Errorf("failed to get webhook item: %w", err)
+	}
+	return item, nil
+}
+
+func webhookCollection(q queryRower, collectionID string) (models.WebhookCollection, error) {
+	collection := models.WebhookCollection{ID: collectionID}
+	err := q.QueryRow(fmt.Sprintf(`SELECT name FROM %s WHERE id = $1`, collectionsTable), collectionID).
+		Scan(&collection.Name)
+	if err != nil {
+		return collection, fmt.Errorf("failed to get webhook collection: %w", err)
+	}
+	return collection, nil
+}
+
+func webhookItemInCollection(q queryRower, itemID string, collectionID string) (models.WebhookItem, models.WebhookCollection, error) {
+	item, err := webhookItem(q, itemID)
+	if err != nil {
+		return item, models.WebhookCollection{}, err
+	}
+	collection, err := webhookCollection(q, collectionID)
+	return item, collection, err
+}
+
+func scanWebhook(row rowScanner) (*models.Webhook, error) {
+	var webhook models.Webhook
+	err := row.Scan(
+		&webhook.ID,
+		&webhook.UserID,
+		&webhook.URL,
+		&webhook.Description,
+		pq.Array(&webhook.Events),
+		&webhook.IsActive,
+		&webhook.CreatedAt,
+		&webhook.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func webhookDeliveryFields(d *models.WebhookDelivery) []any {
+	return []any{
+		&d.ID, &d.WebhookID, &d.EventType, &d.Payload, &d.Status, &d.Attempts,
+		&d.ResponseStatus, &d.ResponseBody, &d.DurationMs, &d.LastError,
+		&d.NextAttemptAt, &d.DeliveredAt, &d.CreatedAt,
+	}
+}
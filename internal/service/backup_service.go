@@ -0,0 +1,86 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/repository"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/backup"
+	"go.uber.org/zap"
+)
+
+var ErrInvalidRestoreMode = errors.New("invalid restore mode, allowed: skip, overwrite, duplicate")
+
+// MaxBackupSize - наибольший размер копии после распаковки
+const MaxBackupSize = 50 << 20
+
+const backupContentType = "application/gzip"
+
+var restoreModes = map[string]bool{
+	models.RestoreModeSkip:      true,
+	models.RestoreModeOverwrite: true,
+	models.RestoreModeDuplicate: true,
+}
+
+type backupService struct {
+	backupRepo repository.Backup
+	events     *eventPublisher
+	logger     *zap.SugaredLogger
+}
+
+func NewBackupService(backupRepo repository.Backup, events *eventPublisher, logger *zap.SugaredLogger) *backupService {
+	return &backupService{
+		backupRepo: backupRepo,
+		events:     events,
+		logger:     logger,
+	}
+}
+
+// CreateBackup собирает копию библиотеки заранее: ошибка чтения из базы возвращается
+// до начала ответа, а не обрывает файл
+func (s *backupService) CreateBackup(userID int) (*models.Export, error) {
+	archive, err := s.backupRepo.GetBackup(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.Export{
+		Filename:    "memoria-backup-" + archive.CreatedAt.Format("2006-01-02") + "." + backup.Extension,
+		ContentType: backupContentType,
+		Write: func(w io.Writer) error {
+			return backup.Encode(w, archive)
+		},
+	}, nil
+}
+
+// Restore проверяет копию целиком и восстанавливает ее одной транзакцией.
+// mode определяет, что делать с совпавшими коллекциями и своими элементами
+func (s *backupService) Restore(userID int, file io.Reader, mode string) (*models.RestoreResult, error) {
+	if mode == "" {
+		mode = models.RestoreModeSkip
+	}
+	if !restoreModes[mode] {
+		return nil, ErrInvalidRestoreMode
+	}
+
+	archive, err := backup.Decode(file, MaxBackupSize)
+	if err != nil {
+		return nil, err
+	}
+	if err := archive.Validate(); err != nil {
+		return nil, err
+	}
+
+	started := time.Now()
+	result, err := s.backupRepo.RestoreBackup(userID, archive, mode)
+	if err != nil {
+		return nil, err
+	}
+	if result.ItemsCreated > 0 || result.ItemsUpdated > 0 {
+		s.events.itemsChanged()
+	}
+	s.logger.Infof("User %d restored backup from %s in %s mode: %d collections, %d items in %s",
+		userID, archive.CreatedAt.Format(time.RFC3339), mode, len(archive.Collections), len(archive.Items), time.Since(started))
+	return result, nil
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/repository"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultChartsInterval         = time.Hour
+	defaultChartsTrendingWindow   = 7 * 24 * time.Hour
+	defaultChartsTrendingHalfLife = 3 * 24 * time.Hour
+	defaultChartsPriorVotes       = 10
+	defaultChartsSize             = 100
+)
+
+// ChartConfig - пересчет чартов каталога
+type ChartConfig struct {
+	Enabled bool
+	// Interval - как часто пересчитываются чарты
+	Interval time.Duration
+	// TrendingWindow - за какой период учитываются добавления в trending
+	TrendingWindow time.Duration
+	// TrendingHalfLife - через сколько вес добавления в trending уменьшается вдвое
+	TrendingHalfLife time.Duration
+	// PriorVotes - сколько средних оценок каталога добавляется к оценкам элемента в top_rated,
+	// чтобы элемент с одной высокой оценкой не оказывался первым
+	PriorVotes int
+	// Size - сколько мест хранится в каждом чарте
+	Size int
+}
+
+type chartService struct {
+	chartRepo    repository.Chart
+	taxonomyRepo repository.Taxonomy
+	cfg          ChartConfig
+	logger       *zap.SugaredLogger
+}
+
+func NewChartService(chartRepo repository.Chart, taxonomyRepo repository.Taxonomy, cfg ChartConfig, logger *zap.SugaredLogger) *chartService {
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultChartsInterval
+	}
+	if cfg.TrendingWindow <= 0 {
+		cfg.TrendingWindow = defaultChartsTrendingWindow
+	}
+	if cfg.TrendingHalfLife <= 0 {
+		cfg.TrendingHalfLife = defaultChartsTrendingHalfLife
+	}
+	if cfg.PriorVotes <= 0 {
+		cfg.PriorVotes = defaultChartsPriorVotes
+	}
+	if cfg.Size <= 0 {
+		cfg.Size = defaultChartsSize
+	}
+	return &chartService{
+		chartRepo:    chartRepo,
+		taxonomyRepo: taxonomyRepo,
+		cfg:          cfg,
+		logger:       logger,
+	}
+}
+
+// GetChart - страница чарта, тип и жанр необязательны
+func (s *chartService) GetChart(query models.ChartQuery, req pagination.PaginationRequest) (*models.Chart, error) {
+	if query.Type != "" && !lookupItemTypes[query.Type] {
+		return nil, fmt.Errorf("%w: unknown type %q", ErrInvalidItemFilter, query.Type)
+	}
+	if query.Genre != "" {
+		genres, err := s.taxonomyRepo.GetGenres()
+		if err != nil {
+			return nil, err
+		}
+		if !slices.ContainsFunc(genres, func(g models.Genre) bool { return g.Slug == query.Genre }) {
+			return nil, ErrGenreNotFound
+		}
+	}
+	return s.chartRepo.GetChart(query, req)
+}
+
+// RunCharts - фоновый пересчет чартов раз в Interval, пока не отменен ctx
+func (s *chartService) RunCharts(ctx context.Context) {
+	if !s.cfg.Enabled {
+		return
+	}
+	s.logger.Infof("Charts started: every %s", s.cfg.Interval)
+
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		if err := s.recomputeIfStale(); err != nil && ctx.Err() == nil {
+			s.logger.Errorf("Charts recompute failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// recomputeIfStale пересчитывает чарты, если их не пересчитывал недавно другой экземпляр
+// или предыдущий запуск приложения
+func (s *chartService) recomputeIfStale() error {
+	computedAt, err := s.chartRepo.GetChartsComputedAt()
+	if err != nil {
+		return err
+	}
+	if computedAt != nil && time.Since(*computedAt) < s.cfg.Interval/2 {
+		return nil
+	}
+
+	started := time.Now()
+	entries, ran, err := s.chartRepo.RecomputeCharts(models.ChartParams{
+		TrendingWindow:   s.cfg.TrendingWindow,
+		TrendingHalfLife: s.cfg.TrendingHalfLife,
+		PriorVotes:       s.cfg.PriorVotes,
+		Size:             s.cfg.Size,
+	})
+	if err != nil {
+		return err
+	}
+	if ran {
+		s.logger.Infof("Charts recomputed: %d entries in %s", entries, time.Since(started).Round(time.Millisecond))
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/repository"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/blobstore"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/in_memory_cache"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/safehttp"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/thumbnail"
+	"go.uber.org/zap"
+)
+
+const (
+	mosaicSize         = 600
+	mosaicSmallGrid    = 2
+	mosaicLargeGrid    = 3
+	mosaicCacheTTL     = time.Hour
+	remoteCoverTimeout = 5 * time.Second
+	maxRemoteCoverSize = 5 << 20
+	// Клетка мозаики не больше 300x300, поэтому обложки больше 4 Мп не декодируются вовсе
+	maxCoverPixels = 4_000_000
+	// Сколько обложек загружается и декодируется одновременно
+	coverLoadConcurrency = 3
+)
+
+// Градиенты заглушек по типу коллекции
+var coverPlaceholders = map[string][2]color.RGBA{
+	"movies": {{R: 0x8e, G: 0x2d, B: 0xe2, A: 0xff}, {R: 0x4a, G: 0x00, B: 0xe0, A: 0xff}},
+	"series": {{R: 0xf7, G: 0x79, B: 0x7d, A: 0xff}, {R: 0xc6, G: 0x42, B: 0x6e, A: 0xff}},
+	"anime":  {{R: 0xff, G: 0x9a, B: 0x8b, A: 0xff}, {R: 0xff, G: 0x6a, B: 0x88, A: 0xff}},
+	"books":  {{R: 0x43, G: 0xc6, B: 0xac, A: 0xff}, {R: 0x19, G: 0x16, B: 0x54, A: 0xff}},
+	"":       {{R: 0x83, G: 0x83, B: 0x9a, A: 0xff}, {R: 0x3a, G: 0x3a, B: 0x4e, A: 0xff}},
+}
+
+// Цвет клетки мозаики, для которой обложку не удалось загрузить
+var emptyTileColor = color.RGBA{R: 0x2b, G: 0x2b, B: 0x35, A: 0xff}
+
+// cachedCover - последняя сгенерированная обложка коллекции и отпечаток ее исходных данных
+type cachedCover struct {
+	fingerprint string
+	data        []byte
+}
+
+type collectionCoverService struct {
+	collectionRepo repository.Collection
+	imageRepo      repository.Image
+	storage        blobstore.BlobStore
+	publicURL      string
+	httpClient     *http.Client
+	cache          *in_memory_cache.Cache[cachedCover]
+	logger         *zap.SugaredLogger
+}
+
+func NewCollectionCoverService(collectionRepo repository.Collection, imageRepo repository.Image, storage blobstore.BlobStore, publicURL string, logger *zap.SugaredLogger) *collectionCoverService {
+	return &collectionCoverService{
+		collectionRepo: collectionRepo,
+		imageRepo:      imageRepo,
+		storage:        storage,
+		publicURL:      publicURL,
+		httpClient:     safehttp.NewClient(remoteCoverTimeout),
+		cache:          in_memory_cache.NewMemoryCache[cachedCover](mosaicCacheTTL),
+		logger:         logger,
+	}
+}
+
+// GetCollectionCover возвращает собственную обложку коллекции или мозаику из обложек ее элементов:
+// 2x2 из первых 4, 3x3 если обложек хотя бы 9, заглушку по типу коллекции если обложек нет.
+// Мозаика привязана к отпечатку списка обложек и пересобирается, когда содержимое коллекции меняется
+func (s *collectionCoverService) GetCollectionCover(ctx context.Context, userID int, collectionID string) (*models.CollectionCover, error) {
+	collection, err := s.collectionRepo.GetCollectionByID(collectionID)
+	if err != nil {
+		return nil, ErrCollectionNotFound
+	}
+	if !collection.IsPublic && collection.UserID != userID {
+		return nil, ErrNotCollectionOwner
+	}
+	if collection.CoverImage != nil && *collection.CoverImage != "" {
+		return &models.CollectionCover{RedirectURL: *collection.CoverImage}, nil
+	}
+
+	covers, err := s.collectionRepo.GetCollectionCovers(collectionID, mosaicLargeGrid*mosaicLargeGrid)
+	if err != nil {
+		return nil, err
+	}
+	grid := mosaicSmallGrid
+	if len(covers) >= mosaicLargeGrid*mosaicLargeGrid {
+		grid = mosaicLargeGrid
+	}
+	covers = covers[:min(len(covers), grid*grid)]
+
+	fingerprint := coverFingerprint(collection.Type, covers)
+	data, err := s.getMosaic(ctx, collection, covers, grid, fingerprint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CollectionCover{
+		Data:        data,
+		ContentType: "image/png",
+		ETag:        fingerprint,
+	}, nil
+}
+
+// getMosaic ищет мозаику в памяти, затем в хранилище, и только потом собирает заново
+func (s *collectionCoverService) getMosaic(ctx context.Context, collection *models.Collection, covers []string, grid int, fingerprint string) ([]byte, error) {
+	cached, _ := s.cache.Get(collection.ID)
+	if cached != nil && cached.fingerprint == fingerprint {
+		return cached.data, nil
+	}
+
+	key := mosaicKey(collection.ID, fingerprint)
+	if object, err := s.storage.Get(ctx, key); err == nil {
+		data, err := io.ReadAll(object.Body)
+		object.Body.Close()
+		if err == nil {
+			s.cache.Set(collection.ID, cachedCover{fingerprint: fingerprint, data: data})
+			return data, nil
+		}
+	}
+
+	var img image.Image
+	if len(covers) == 0 {
+		colors, ok := coverPlaceholders[collection.Type]
+		if !ok {
+			colors = coverPlaceholders[""]
+		}
+		img = thumbnail.Placeholder(mosaicSize, colors[0], colors[1])
+	} else {
+		// Обложки грузятся параллельно, потому что внешние ссылки могут отвечать медленно, но не больше
+		// coverLoadConcurrency сразу. Каждая сразу уменьшается до размера клетки, чтобы в памяти
+		// не держались полноразмерные изображения всех обложек
+		tiles := make([]image.Image, len(covers))
+		tileSize := mosaicSize / grid
+		sem := make(chan struct{}, coverLoadConcurrency)
+		var wg sync.WaitGroup
+		for i, cover := range covers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+				tile, err := s.loadCover(ctx, cover)
+				if err != nil {
+					s.logger.Warnf("Failed to load cover %q for collection %s: %v", cover, collection.ID, err)
+					return
+				}
+				tiles[i] = thumbnail.Fill(tile, tileSize, tileSize)
+			}()
+		}
+		wg.Wait()
+		img = thumbnail.Mosaic(tiles, grid, mosaicSize, emptyTileColor)
+	}
+
+	data, err := thumbnail.EncodePNG(img)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.storage.Put(ctx, key, data, "image/png"); err != nil {
+		s.logger.Warnf("Failed to store cover mosaic of collection %s: %v", collection.ID, err)
+	} else if cached != nil {
+		if err := s.storage.Delete(ctx, mosaicKey(collection.ID, cached.fingerprint)); err != nil {
+			s.logger.Warnf("Failed to delete stale cover mosaic of collection %s: %v", collection.ID, err)
+		}
+	}
+	s.cache.Set(collection.ID, cachedCover{fingerprint: fingerprint, data: data})
+
+	return data, nil
+}
+
+// loadCover читает загруженные изображения напрямую из хранилища, внешние ссылки - через клиент без доступа во внутреннюю сеть
+func (s *collectionCoverService) loadCover(ctx context.Context, cover string) (image.Image, error) {
+	if imageID, ok := s.uploadedImageID(cover); ok {
+		return s.loadUploadedImage(ctx, imageID)
+	}
+
+	coverURL, err := url.Parse(cover)
+	if err != nil || (coverURL.Scheme != "http" && coverURL.Scheme != "https") {
+		return nil, fmt.Errorf("unsupported cover url")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, coverURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteCoverSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxRemoteCoverSize {
+		return nil, ErrImageTooLarge
+	}
+	return decodeImage(data)
+}
+
+func (s *collectionCoverService) loadUploadedImage(ctx context.Context, imageID string) (image.Image, error) {
+	uploaded, err := s.imageRepo.GetImageByID(imageID)
+	if err != nil {
+		return nil, err
+	}
+
+	object, err := s.storage.Get(ctx, thumbnailKey(uploaded.BlobPrefix, models.ImageSizeMedium))
+	if err != nil {
+		return nil, err
+	}
+	defer object.Body.Close()
+
+	data, err := io.ReadAll(object.Body)
+	if err != nil {
+		return nil, err
+	}
+	return decodeImage(data)
+}
+
+// uploadedImageID распознает постоянные ссылки вида <PublicURL>/images/<id>
+func (s *collectionCoverService) uploadedImageID(cover string) (string, bool) {
+	prefix := s.publicURL + "/images/"
+	if s.publicURL == "" || !strings.HasPrefix(cover, prefix) {
+		return "", false
+	}
+	id, _, _ := strings.Cut(strings.TrimPrefix(cover, prefix), "?")
+	return id, uuidPattern.MatchString(id)
+}
+
+func decodeImage(data []byte) (image.Image, error) {
+	if _, _, err := thumbnail.Sniff(data); err != nil {
+		return nil, err
+	}
+	return thumbnail.DecodeLimited(data, maxCoverPixels)
+}
+
+func coverFingerprint(collectionType string, covers []string) string {
+	hash := sha256.New()
+	hash.Write([]byte(collectionType))
+	for _, cover := range covers {
+		hash.Write([]byte{0})
+		hash.Write([]byte(cover))
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
+
+func mosaicKey(collectionID string, fingerprint string) string {
+	return fmt.Sprintf("covers/collections/%s/%s.png", collectionID, fingerprint)
+}
@@ -2,9 +2,14 @@ package service
 
 import (
 	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
 	"github.com/kefirchick13/memoria-collect-platform-golang/internal/repository"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/in_memory_cache"
 	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
 	"go.uber.org/zap"
 )
@@ -14,43 +19,254 @@ var (
 	ErrItemNotFound            = errors.New("item not found")
 	ErrNotCollectionOwner      = errors.New("user is not collection owner")
 	ErrItemAlreadyInCollection = errors.New("item already exists in collection")
+	ErrInvalidSuggestQuery     = errors.New("suggest query must be between 1 and 100 characters")
+	ErrInvalidItemFilter       = errors.New("invalid item filter")
+	ErrAdminRequired           = errors.New("admin role required")
+	ErrMergeSameItem           = errors.New("item can't be merged with itself")
+	ErrMergeTypeMismatch       = errors.New("only items of the same type can be merged")
+	ErrMergePrivateCanonical   = errors.New("public item can't be merged into a private item")
+)
+
+const (
+	suggestCacheTTL          = 5 * time.Minute
+	maxSuggestQueryLength    = 100
+	defaultItemSuggestLimit  = 10
+	maxItemSuggestLimit      = 20
+	duplicateCandidatesLimit = 5
 )
 
 type collectionItemService struct {
 	itemRepo       repository.CollectionItem
 	collectionRepo repository.Collection
-	logger         *zap.SugaredLogger
+	duplicateRepo  repository.Duplicate
+	moderationRepo repository.Moderation
+	userRepo       repository.UserRepository
+	events         *eventPublisher
+	// Кеш подсказок автодополнения, сбрасывается через events.itemsChanged при создании и изменении элементов
+	suggestCache *in_memory_cache.Cache[[]models.ItemSuggestion]
+	logger       *zap.SugaredLogger
 }
 
-func NewCollectionItemService(itemRepo repository.CollectionItem, collectionRepo repository.Collection, logger *zap.SugaredLogger) *collectionItemService {
+func NewCollectionItemService(itemRepo repository.CollectionItem, collectionRepo repository.Collection, duplicateRepo repository.Duplicate, moderationRepo repository.Moderation, userRepo repository.UserRepository, suggestCache *in_memory_cache.Cache[[]models.ItemSuggestion], events *eventPublisher, logger *zap.SugaredLogger) *collectionItemService {
 	return &collectionItemService{
 		itemRepo:       itemRepo,
 		collectionRepo: collectionRepo,
+		duplicateRepo:  duplicateRepo,
+		moderationRepo: moderationRepo,
+		userRepo:       userRepo,
+		events:         events,
+		suggestCache:   suggestCache,
 		logger:         logger,
 	}
 }
 
-func (s *collectionItemService) GetItemsByCurrentType(currentType string, pagination pagination.PaginationRequest) (*pagination.PaginatedResponse[models.CollectionItem], error) {
-	return s.itemRepo.GetAllItemsWithCurrentTypePaginated(currentType, pagination)
+func (s *collectionItemService) GetItemsByCurrentType(filter models.ItemFilter, pagination pagination.PaginationRequest) (*models.ItemFeed, error) {
+	filter, err := normalizeItemFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	return s.itemRepo.GetAllItemsWithCurrentTypePaginated(filter, pagination)
+}
+
+func (s *collectionItemService) GetItemsFeedByCursor(filter models.ItemFilter, req pagination.CursorRequest) (*pagination.CursorResponse[models.FeedItem], error) {
+	filter, err := normalizeItemFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	return s.itemRepo.GetItemsFeedByCursor(filter, req)
+}
+
+func normalizeItemFilter(filter models.ItemFilter) (models.ItemFilter, error) {
+	if !models.FeedSorts[filter.Sort] {
+		return filter, fmt.Errorf("%w: unknown sort %q", ErrInvalidItemFilter, filter.Sort)
+	}
+	if filter.Source != "" && filter.Source != models.ItemSourceCustom && filter.Source != models.ItemSourceCatalog {
+		return filter, fmt.Errorf("%w: unknown source %q", ErrInvalidItemFilter, filter.Source)
+	}
+	if filter.YearFrom != nil && filter.YearTo != nil && *filter.YearFrom > *filter.YearTo {
+		return filter, fmt.Errorf("%w: year_from is greater than year_to", ErrInvalidItemFilter)
+	}
+	if filter.RatingMin != nil && filter.RatingMax != nil && *filter.RatingMin > *filter.RatingMax {
+		return filter, fmt.Errorf("%w: rating_min is greater than rating_max", ErrInvalidItemFilter)
+	}
+
+	filter.Genres = uniqueLower(filter.Genres)
+	filter.Tags.Tags = uniqueLower(filter.Tags.Tags)
+	return filter, nil
 }
 
-func (s *collectionItemService) GetItemsByCollection(collection_id string, user_id int) ([]models.CollectionItem, error) {
-	return s.itemRepo.GetItemsByCollection(collection_id)
+func (s *collectionItemService) GetItemsByCollection(collection_id string, user_id int, tags models.TagFilter) ([]models.CollectionItem, error) {
+	tags.UserID = user_id
+	tags.Tags = uniqueLower(tags.Tags)
+	entries, err := s.itemRepo.GetItemsByCollection(collection_id, tags, 0)
+	if err != nil {
+		return nil, err
+	}
+	var items []models.CollectionItem
+	for _, entry := range entries {
+		items = append(items, entry.CollectionItem)
+	}
+	return items, nil
 }
 
 func (s *collectionItemService) GetItemByID(collection_item_id string) (*models.CollectionItem, error) {
 	return s.itemRepo.GetItemByID((collection_item_id))
 }
 
-func (s *collectionItemService) CreateCollectionItem(item *models.CollectionItem) (string, error) {
-	return s.itemRepo.CreateItem(item)
+// CreateCollectionItem создает элемент и возвращает похожие существующие элементы,
+// чтобы клиент мог предупредить о возможном дубликате.
+// Публиковать сразу могут только модераторы, элементы остальных пользователей с is_public
+// создаются приватными и уходят на проверку
+func (s *collectionItemService) CreateCollectionItem(item *models.CollectionItem) (string, []models.DuplicateCandidate, error) {
+	userID := 0
+	if item.CreatorID != nil {
+		userID = *item.CreatorID
+	}
+
+	role, err := s.userRepo.GetUserRole(userID)
+	if err != nil {
+		return "", nil, err
+	}
+	submitForReview := false
+	switch {
+	case isModeratorRole(role) && item.IsPublic:
+		item.ModerationStatus = models.ModerationStatusApproved
+	case isModeratorRole(role):
+		item.ModerationStatus = models.ModerationStatusNone
+	default:
+		submitForReview = item.IsPublic
+		item.IsCustom = true
+		item.IsPublic = false
+		item.ModerationStatus = models.ModerationStatusNone
+	}
+
+	// Поиск дубликатов не должен мешать созданию элемента
+	candidates, err := s.duplicateRepo.FindDuplicates(item, userID, duplicateCandidatesLimit)
+	if err != nil {
+		s.logger.Warnf("Failed to find duplicates for %q: %v", item.Title, err)
+		candidates = []models.DuplicateCandidate{}
+	}
+
+	var id string
+	if submitForReview {
+		id, err = s.moderationRepo.CreateSubmittedItem(item, userID)
+	} else {
+		id, err = s.itemRepo.CreateItem(item)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	s.events.itemsChanged()
+	return id, candidates, nil
+}
+
+// GetItem возвращает элемент. Если элемент был слит с другим, возвращается ID канонического элемента
+func (s *collectionItemService) GetItem(userID int, itemID string) (*models.CollectionItem, string, error) {
+	item, err := s.itemRepo.GetItemByID(itemID)
+	if errors.Is(err, repository.ErrNotFound) {
+		redirectID, err := s.duplicateRepo.GetItemRedirect(itemID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, "", ErrItemNotFound
+		}
+		return nil, redirectID, err
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	if !item.IsPublic && (item.CreatorID == nil || *item.CreatorID != userID) {
+		return nil, "", ErrItemAccessDenied
+	}
+	return item, "", nil
+}
+
+func (s *collectionItemService) FindDuplicates(userID int, item *models.CollectionItem) ([]models.DuplicateCandidate, error) {
+	return s.duplicateRepo.FindDuplicates(item, userID, duplicateCandidatesLimit)
+}
+
+// MergeItems - слияние дубликата с каноническим элементом, доступно только администраторам
+func (s *collectionItemService) MergeItems(userID int, canonicalID string, duplicateID string) (*models.ItemMergeResult, error) {
+	role, err := s.userRepo.GetUserRole(userID)
+	if err != nil {
+		return nil, err
+	}
+	if role != models.RoleAdmin {
+		return nil, ErrAdminRequired
+	}
+	if canonicalID == duplicateID {
+		return nil, ErrMergeSameItem
+	}
+
+	canonical, err := s.itemRepo.GetItemByID(canonicalID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrItemNotFound
+		}
+		return nil, err
+	}
+	duplicate, err := s.itemRepo.GetItemByID(duplicateID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrItemNotFound
+		}
+		return nil, err
+	}
+	if canonical.Type != duplicate.Type {
+		return nil, ErrMergeTypeMismatch
+	}
+	// Публичный элемент нельзя спрятать в личном: его коллекции и прогресс других пользователей потеряли бы доступ
+	if duplicate.IsPublic && !canonical.IsPublic {
+		return nil, ErrMergePrivateCanonical
+	}
+
+	result, err := s.duplicateRepo.MergeItems(duplicateID, canonicalID, userID)
+	if err != nil {
+		return nil, err
+	}
+	s.events.itemsChanged()
+	return result, nil
 }
 func (s *collectionItemService) DeleteItem(collection_item_id string) error {
-	return s.itemRepo.DeleteCollectionItem(collection_item_id)
+	if err := s.itemRepo.DeleteCollectionItem(collection_item_id); err != nil {
+		return err
+	}
+	s.events.itemsChanged()
+	return nil
 }
 
 func (s *collectionItemService) UpdateItem(item *models.CollectionItem) error {
-	return s.itemRepo.UpdateCollectionItem(item)
+	if err := s.itemRepo.UpdateCollectionItem(item); err != nil {
+		return err
+	}
+	s.events.itemsChanged()
+	return nil
+}
+
+// SuggestItems - подсказки для диалога добавления элемента, результаты кешируются
+func (s *collectionItemService) SuggestItems(query string, itemType string, userID int, limit int) ([]models.ItemSuggestion, error) {
+	query = strings.ToLower(strings.Join(strings.Fields(query), " "))
+	if query == "" || utf8.RuneCountInString(query) > maxSuggestQueryLength {
+		return nil, ErrInvalidSuggestQuery
+	}
+	if limit < 1 {
+		limit = defaultItemSuggestLimit
+	}
+	if limit > maxItemSuggestLimit {
+		limit = maxItemSuggestLimit
+	}
+
+	// Подсказки включают приватные элементы пользователя, поэтому ключ кеша персональный
+	cacheKey := fmt.Sprintf("%d|%s|%d|%s", userID, itemType, limit, query)
+	if cached, err := s.suggestCache.Get(cacheKey); err == nil {
+		return *cached, nil
+	}
+
+	suggestions, err := s.itemRepo.SuggestItems(query, itemType, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	s.suggestCache.Set(cacheKey, suggestions)
+	return suggestions, nil
 }
 
 func (s *collectionItemService) AddItemToCollection(item_id string, collection_id string, user_review string, user_id int) (int, error) {
@@ -79,5 +295,14 @@ func (s *collectionItemService) AddItemToCollection(item_id string, collection_i
 		return 0, errors.New("access denied to this item")
 	}
 
-	return s.itemRepo.AddItemToCollection(collection_id, item_id, user_review)
+	id, err := s.itemRepo.AddItemToCollection(collection_id, item_id, user_review)
+	if err != nil {
+		return 0, err
+	}
+	s.events.collectionChanged(collection, models.EventCollectionItemAdded, models.CollectionItemAddedEvent{
+		ItemID:     item.ID,
+		Title:      item.Title,
+		UserReview: user_review,
+	})
+	return id, nil
 }
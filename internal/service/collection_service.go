@@ -1,20 +1,25 @@
 package service
 
 import (
+	"errors"
+
 	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
 	"github.com/kefirchick13/memoria-collect-platform-golang/internal/repository"
 	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/realtime"
 	"go.uber.org/zap"
 )
 
 type collectionService struct {
 	repo   repository.Collection
+	events *eventPublisher
 	logger *zap.SugaredLogger
 }
 
-func NewCollectionService(repo repository.Collection, logger *zap.SugaredLogger) *collectionService {
+func NewCollectionService(repo repository.Collection, events *eventPublisher, logger *zap.SugaredLogger) *collectionService {
 	return &collectionService{
 		repo:   repo,
+		events: events,
 		logger: logger,
 	}
 }
@@ -27,6 +32,40 @@ func (s *collectionService) GetCollections(user_id int) ([]models.Collection, er
 	return s.repo.GetCollections(user_id)
 }
 
-func (s *collectionService) GetCollectionsWithPagination(user_id int, pagination pagination.PaginationRequest) (*pagination.PaginatedResponse[models.Collection], error) {
-	return s.repo.GetCollectionsWithPagination(user_id, pagination)
+func (s *collectionService) GetCollectionsWithPagination(user_id int, tags models.TagFilter, pagination pagination.PaginationRequest) (*pagination.PaginatedResponse[models.Collection], error) {
+	tags.UserID = user_id
+	tags.Tags = uniqueLower(tags.Tags)
+	return s.repo.GetCollectionsWithPagination(user_id, tags, pagination)
+}
+
+func (s *collectionService) GetCollectionsByCursor(user_id int, tags models.TagFilter, req pagination.CursorRequest) (*pagination.CursorResponse[models.Collection], error) {
+	tags.UserID = user_id
+	tags.Tags = uniqueLower(tags.Tags)
+	return s.repo.GetCollectionsByCursor(user_id, tags, req)
+}
+
+// SetCollectionVisibility открывает или закрывает коллекцию владельца
+func (s *collectionService) SetCollectionVisibility(userID int, collectionID string, isPublic bool) error {
+	collection, err := s.repo.GetCollectionByID(collectionID)
+	if err != nil {
+		return ErrCollectionNotFound
+	}
+	if collection.UserID != userID {
+		return ErrNotCollectionOwner
+	}
+	err = s.repo.SetCollectionPublic(collectionID, isPublic)
+	if errors.Is(err, repository.ErrNothingChanged) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// О закрытии коллекции узнают и те, кто за ней следит, дальше ее события им не приходят
+	s.events.publish(realtime.Event{
+		Type:         models.EventCollectionUpdated,
+		UserID:       collection.UserID,
+		CollectionID: collection.ID,
+	}, models.CollectionUpdatedEvent{Change: models.CollectionChangeVisibility, IsPublic: &isPublic})
+	return nil
 }
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/repository"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrEditNotFound          = errors.New("edit suggestion not found")
+	ErrEditNotPending        = errors.New("edit suggestion is already reviewed")
+	ErrEditEmpty             = errors.New("edit suggestion has no changes")
+	ErrEditNoEffect          = errors.New("changes don't differ from current item data")
+	ErrEditPrivateItem       = errors.New("edits can be suggested only for public catalog items")
+	ErrEditInvalidTitle      = errors.New("title can't be empty")
+	ErrEditInvalidYear       = errors.New("release year must be between 1000 and 3000")
+	ErrEditInvalidStatus     = errors.New("invalid edit suggestion status")
+	ErrRevisionNotFound      = errors.New("revision not found")
+	ErrRevisionNothingToUndo = errors.New("item already has the values before this revision")
+)
+
+const (
+	minReleaseYear = 1000
+	maxReleaseYear = 3000
+)
+
+var editStatuses = map[string]bool{
+	models.EditStatusPending:  true,
+	models.EditStatusAccepted: true,
+	models.EditStatusRejected: true,
+}
+
+type editService struct {
+	editRepo repository.Edit
+	itemRepo repository.CollectionItem
+	userRepo repository.UserRepository
+	events   *eventPublisher
+	logger   *zap.SugaredLogger
+}
+
+func NewEditService(editRepo repository.Edit, itemRepo repository.CollectionItem, userRepo repository.UserRepository, events *eventPublisher, logger *zap.SugaredLogger) *editService {
+	return &editService{
+		editRepo: editRepo,
+		itemRepo: itemRepo,
+		userRepo: userRepo,
+		events:   events,
+		logger:   logger,
+	}
+}
+
+// SuggestEdit - пользователь предлагает изменить поля публичного элемента каталога
+func (s *editService) SuggestEdit(userID int, itemID string, changes models.ItemChanges, comment *string) (*models.EditSuggestion, error) {
+	item, err := s.getItem(itemID)
+	if err != nil {
+		return nil, err
+	}
+	if !item.IsPublic {
+		return nil, ErrEditPrivateItem
+	}
+
+	changes, err = normalizeItemChanges(changes)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.editRepo.CreateSuggestion(&models.EditSuggestion{
+		ItemID:   itemID,
+		AuthorID: &userID,
+		Changes:  changes,
+		Comment:  trimComment(comment),
+	})
+}
+
+// GetItemSuggestions - правки элемента видны всем, кто видит сам элемент
+func (s *editService) GetItemSuggestions(userID int, itemID string, status string, pagination pagination.PaginationRequest) (*pagination.PaginatedResponse[models.EditSuggestion], error) {
+	if status != "" && !editStatuses[status] {
+		return nil, ErrEditInvalidStatus
+	}
+	if _, err := s.getVisibleItem(userID, itemID); err != nil {
+		return nil, err
+	}
+	return s.editRepo.GetSuggestions(itemID, status, pagination)
+}
+
+// GetPendingSuggestions - очередь правок для модераторов, сначала самые старые
+func (s *editService) GetPendingSuggestions(userID int, pagination pagination.PaginationRequest) (*pagination.PaginatedResponse[models.EditSuggestion], error) {
+	if err := requireModerator(s.userRepo, userID); err != nil {
+		return nil, err
+	}
+	return s.editRepo.GetSuggestions("", models.EditStatusPending, pagination)
+}
+
+func (s *editService) AcceptSuggestion(userID int, suggestionID int, comment *string) (*models.ItemRevision, error) {
+	if err := requireModerator(s.userRepo, userID); err != nil {
+		return nil, err
+	}
+	suggestion, err := s.getPending(suggestionID)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := s.editRepo.AcceptSuggestion(suggestionID, userID, trimComment(comment))
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return nil, ErrEditNotPending
+		case errors.Is(err, repository.ErrNothingChanged):
+			return nil, ErrEditNoEffect
+		}
+		return nil, err
+	}
+	s.events.itemsChanged()
+	if suggestion.AuthorID != nil {
+		s.events.notificationsChanged(*suggestion.AuthorID)
+	}
+	return revision, nil
+}
+
+func (s *editService) RejectSuggestion(userID int, suggestionID int, comment *string) error {
+	if err := requireModerator(s.userRepo, userID); err != nil {
+		return err
+	}
+	suggestion, err := s.getPending(suggestionID)
+	if err != nil {
+		return err
+	}
+
+	err = s.editRepo.RejectSuggestion(suggestionID, userID, trimComment(comment))
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrEditNotPending
+	}
+	if err != nil {
+		return err
+	}
+	if suggestion.AuthorID != nil {
+		s.events.notificationsChanged(*suggestion.AuthorID)
+	}
+	return nil
+}
+
+// GetItemHistory - история принятых изменений элемента, сначала новые
+func (s *editService) GetItemHistory(userID int, itemID string) ([]models.ItemRevision, error) {
+	if _, err := s.getVisibleItem(userID, itemID); err != nil {
+		return nil, err
+	}
+	return s.editRepo.GetRevisions(itemID)
+}
+
+// RevertRevision - модератор откатывает поля ревизии к прежним значениям
+func (s *editService) RevertRevision(userID int, itemID string, revisionID int) (*models.ItemRevision, error) {
+	if err := requireModerator(s.userRepo, userID); err != nil {
+		return nil, err
+	}
+	if _, err := s.getItem(itemID); err != nil {
+		return nil, err
+	}
+
+	revision, err := s.editRepo.RevertRevision(itemID, revisionID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return nil, ErrRevisionNotFound
+		case errors.Is(err, repository.ErrNothingChanged):
+			return nil, ErrRevisionNothingToUndo
+		}
+		return nil, err
+	}
+	s.events.itemsChanged()
+	return revision, nil
+}
+
+// getPending возвращает предложенную правку, которую еще не рассмотрели
+func (s *editService) getPending(suggestionID int) (*models.EditSuggestion, error) {
+	suggestion, err := s.editRepo.GetSuggestionByID(suggestionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrEditNotFound
+		}
+		return nil, err
+	}
+	if suggestion.Status != models.EditStatusPending {
+		return nil, ErrEditNotPending
+	}
+	return suggestion, nil
+}
+
+func (s *editService) getVisibleItem(userID int, itemID string) (*models.CollectionItem, error) {
+	item, err := s.getItem(itemID)
+	if err != nil {
+		return nil, err
+	}
+	if !item.IsPublic && (item.CreatorID == nil || *item.CreatorID != userID) {
+		return nil, ErrItemNotFound
+	}
+	return item, nil
+}
+
+func (s *editService) getItem(itemID string) (*models.CollectionItem, error) {
+	item, err := s.itemRepo.GetItemByID(itemID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrItemNotFound
+		}
+		return nil, err
+	}
+	return item, nil
+}
+
+// normalizeItemChanges обрезает пробелы и проверяет значения полей
+func normalizeItemChanges(changes models.ItemChanges) (models.ItemChanges, error) {
+	if len(changes.ToMap()) == 0 {
+		return changes, ErrEditEmpty
+	}
+	if changes.Title != nil {
+		title := strings.TrimSpace(*changes.Title)
+		if title == "" {
+			return changes, ErrEditInvalidTitle
+		}
+		changes.Title = &title
+	}
+	if changes.Description != nil {
+		description := strings.TrimSpace(*changes.Description)
+		changes.Description = &description
+	}
+	if changes.CoverImage != nil {
+		coverImage := strings.TrimSpace(*changes.CoverImage)
+		changes.CoverImage = &coverImage
+	}
+	if changes.ReleaseYear != nil && (*changes.ReleaseYear < minReleaseYear || *changes.ReleaseYear > maxReleaseYear) {
+		return changes, ErrEditInvalidYear
+	}
+	return changes, nil
+}
+
+func trimComment(comment *string) *string {
+	if comment == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*comment)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/repository"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
+	"go.uber.org/zap"
+)
+
+const maxCommentLength = 2000
+
+var (
+	ErrCollectionPrivate = errors.New("collection is private")
+	ErrCommentNotFound   = errors.New("comment not found")
+	ErrNotCommentAuthor  = errors.New("comment belongs to another user")
+	ErrInvalidComment    = fmt.Errorf("comment must be between 1 and %d characters", maxCommentLength)
+)
+
+type engagementService struct {
+	engagementRepo repository.Engagement
+	collectionRepo repository.Collection
+	events         *eventPublisher
+	logger         *zap.SugaredLogger
+}
+
+func NewEngagementService(engagementRepo repository.Engagement, collectionRepo repository.Collection, events *eventPublisher, logger *zap.SugaredLogger) *engagementService {
+	return &engagementService{
+		engagementRepo: engagementRepo,
+		collectionRepo: collectionRepo,
+		events:         events,
+		logger:         logger,
+	}
+}
+
+// GetEngagement - счетчики коллекции и реакции пользователя на нее
+func (s *engagementService) GetEngagement(userID int, collectionID string) (*models.CollectionEngagement, error) {
+	if _, err := s.visibleCollection(userID, collectionID); err != nil {
+		return nil, err
+	}
+	return s.getEngagement(userID, collectionID)
+}
+
+// SetLiked ставит или снимает лайк. Лайкнуть можно только публичную коллекцию,
+// а снять лайк - и с коллекции, которую закрыли
+func (s *engagementService) SetLiked(userID int, collectionID string, liked bool) (*models.CollectionEngagement, error) {
+	if err := s.checkReaction(collectionID, liked); err != nil {
+		return nil, err
+	}
+	if err := s.engagementRepo.SetLiked(collectionID, userID, liked); err != nil {
+		return nil, err
+	}
+	return s.getEngagement(userID, collectionID)
+}
+
+// SetSaved сохраняет коллекцию или убирает из сохраненных, правила те же, что у лайков
+func (s *engagementService) SetSaved(userID int, collectionID string, saved bool) (*models.CollectionEngagement, error) {
+	if err := s.checkReaction(collectionID, saved); err != nil {
+		return nil, err
+	}
+	if err := s.engagementRepo.SetSaved(collectionID, userID, saved); err != nil {
+		return nil, err
+	}
+	return s.getEngagement(userID, collectionID)
+}
+
+func (s *engagementService) GetSavedCollections(userID int, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.SavedCollection], error) {
+	return s.engagementRepo.GetSavedCollections(userID, req)
+}
+
+// GetComments - ветки комментариев коллекции. Комментарии закрытой коллекции видит только владелец
+func (s *engagementService) GetComments(userID int, collectionID string, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.CollectionComment], error) {
+	if _, err := s.visibleCollection(userID, collectionID); err != nil {
+		return nil, err
+	}
+	return s.engagementRepo.GetComments(collectionID, req)
+}
+
+// AddComment добавляет комментарий к публичной коллекции или ответ на комментарий parentID
+func (s *engagementService) AddComment(userID int, collectionID string, parentID *int64, body string) (*models.CollectionComment, error) {
+	body, err := normalizeComment(body)
+	if err != nil {
+		return nil, err
+	}
+	collection, err := s.visibleCollection(userID, collectionID)
+	if err != nil {
+		return nil, err
+	}
+	if !collection.IsPublic {
+		return nil, ErrCollectionPrivate
+	}
+
+	comment, err := s.engagementRepo.CreateComment(collectionID, userID, parentID, body)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrCommentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.commentChanged(collection, models.CommentAdded, comment.ID, comment)
+	notified := []int{collection.UserID}
+	if parentID != nil {
+		if parent, err := s.engagementRepo.GetComment(*parentID); err == nil {
+			notified = append(notified, parent.Author.ID)
+		}
+	}
+	// Автор не получает уведомлений о своих комментариях
+	notified = slices.DeleteFunc(notified, func(id int) bool { return id == userID })
+	s.events.notificationsChanged(notified...)
+	return comment, nil
+}
+
+// EditComment меняет текст комментария. Редактировать может только автор, пока коллекция ему видна
+func (s *engagementService) EditComment(userID int, commentID int64, body string) (*models.CollectionComment, error) {
+	body, err := normalizeComment(body)
+	if err != nil {
+		return nil, err
+	}
+	comment, err := s.getComment(commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.Author.ID != userID {
+		return nil, ErrNotCommentAuthor
+	}
+	collection, err := s.visibleCollection(userID, comment.CollectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.engagementRepo.UpdateComment(commentID, body); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, err
+	}
+	edited, err := s.getComment(commentID)
+	if err != nil {
+		return nil, err
+	}
+	s.commentChanged(collection, models.CommentEdited, commentID, edited)
+	return edited, nil
+}
+
+// DeleteComment удаляет комментарий. Удалить может автор, даже если коллекцию закрыли, или владелец коллекции (модерация)
+func (s *engagementService) DeleteComment(userID int, commentID int64) error {
+	comment, err := s.getComment(commentID)
+	if err != nil {
+		return err
+	}
+	collection, err := s.collectionRepo.GetCollectionByID(comment.CollectionID)
+	if err != nil {
+		return ErrCollectionNotFound
+	}
+	if comment.Author.ID != userID && collection.UserID != userID {
+		return ErrNotCommentAuthor
+	}
+
+	err = s.engagementRepo.DeleteComment(commentID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrCommentNotFound
+	}
+	if err != nil {
+		return err
+	}
+	s.commentChanged(collection, models.CommentDeleted, commentID, nil)
+	return nil
+}
+
+func (s *engagementService) commentChanged(collection *models.Collection, action string, commentID int64, comment *models.CollectionComment) {
+	s.events.collectionChanged(collection, models.EventCollectionComment, models.CollectionCommentEvent{
+		Action:    action,
+		CommentID: commentID,
+		Comment:   comment,
+	})
+}
+
+// visibleCollection возвращает коллекцию, если userID может ее видеть: публичную или свою
+func (s *engagementService) visibleCollection(userID int, collectionID string) (*models.Collection, error) {
+	collection, err := s.collectionRepo.GetCollectionByID(collectionID)
+	if err != nil {
+		return nil, ErrCollectionNotFound
+	}
+	if !collection.IsPublic && collection.UserID != userID {
+		return nil, ErrCollectionPrivate
+	}
+	return collection, nil
+}
+
+// checkReaction проверяет, что реакцию можно поставить (коллекция публичная) или снять (коллекция существует)
+func (s *engagementService) checkReaction(collectionID string, set bool) error {
+	collection, err := s.collectionRepo.GetCollectionByID(collectionID)
+	if err != nil {
+		return ErrCollectionNotFound
+	}
+	if set && !collection.IsPublic {
+		return ErrCollectionPrivate
+	}
+	return nil
+}
+
+func (s *engagementService) getEngagement(userID int, collectionID string) (*models.CollectionEngagement, error) {
+	engagement, err := s.engagementRepo.GetEngagement(collectionID, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrCollectionNotFound
+	}
+	return engagement, err
+}
+
+// getComment возвращает неудаленный комментарий
+func (s *engagementService) getComment(commentID int64) (*models.CollectionComment, error) {
+	comment, err := s.engagementRepo.GetComment(commentID)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && comment.IsDeleted) {
+		return nil, ErrCommentNotFound
+	}
+	return comment, err
+}
+
+func normalizeComment(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" || utf8.RuneCountInString(body) > maxCommentLength {
+		return "", ErrInvalidComment
+	}
+	return body, nil
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrSeasonNotFound       = errors.New("season not found")
+	ErrEpisodeNotFound      = errors.New("episode not found")
+	ErrSeasonAlreadyExists  = errors.New("season or episode with this number already exists")
+	ErrEpisodesNotSupported = errors.New("episodes are supported only for series and anime")
+	ErrItemAccessDenied     = errors.New("access denied to this item")
+	ErrProgressNotFound     = errors.New("progress not found")
+	ErrNotItemCreator       = errors.New("user is not item creator")
+	ErrInvalidRating        = errors.New("rating must be between 1 and 10")
+)
+
+const (
+	minItemRating = 1
+	maxItemRating = 10
+)
+
+// Типы элементов, у которых есть сезоны и эпизоды
+var episodicItemTypes = map[string]bool{
+	"series": true,
+	"anime":  true,
+}
+
+type episodeService struct {
+	episodeRepo repository.Episode
+	itemRepo    repository.CollectionItem
+	userRepo    repository.UserRepository
+	logger      *zap.SugaredLogger
+}
+
+func NewEpisodeService(episodeRepo repository.Episode, itemRepo repository.CollectionItem, userRepo repository.UserRepository, logger *zap.SugaredLogger) *episodeService {
+	return &episodeService{
+		episodeRepo: episodeRepo,
+		itemRepo:    itemRepo,
+		userRepo:    userRepo,
+		logger:      logger,
+	}
+}
+
+func (s *episodeService) GetSeasons(itemID string, userID int) ([]models.Season, error) {
+	if _, err := s.getEpisodicItem(itemID, userID); err != nil {
+		return nil, err
+	}
+	return s.episodeRepo.GetSeasonsByItem(itemID, userID)
+}
+
+func (s *episodeService) CreateSeason(userID int, season *models.Season) (*models.Season, error) {
+	if err := s.checkSeasonsEditor(season.ItemID, userID); err != nil {
+		return nil, err
+	}
+
+	created, err := s.episodeRepo.CreateSeason(season)
+	if errors.Is(err, repository.ErrAlreadyExists) {
+		return nil, ErrSeasonAlreadyExists
+	}
+	return created, err
+}
+
+func (s *episodeService) AddEpisode(userID int, itemID string, seasonNumber int, episode *models.Episode) (string, error) {
+	if err := s.checkSeasonsEditor(itemID, userID); err != nil {
+		return "", err
+	}
+
+	season, err := s.episodeRepo.GetSeasonByNumber(itemID, seasonNumber)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return "", ErrSeasonNotFound
+		}
+		return "", err
+	}
+
+	episode.SeasonID = season.ID
+	id, err := s.episodeRepo.CreateEpisode(episode)
+	if errors.Is(err, repository.ErrAlreadyExists) {
+		return "", ErrSeasonAlreadyExists
+	}
+	return id, err
+}
+
+func (s *episodeService) MarkEpisodeWatched(userID int, itemID string, episodeID string, watched bool) (*models.ItemProgress, error) {
+	if _, err := s.getEpisodicItem(itemID, userID); err != nil {
+		return nil, err
+	}
+
+	progress, err := s.episodeRepo.SetEpisodeWatched(userID, itemID, episodeID, watched)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrEpisodeNotFound
+	}
+	return progress, err
+}
+
+func (s *episodeService) MarkSeasonWatched(userID int, itemID string, seasonNumber int, watched bool) (*models.ItemProgress, error) {
+	if _, err := s.getEpisodicItem(itemID, userID); err != nil {
+		return nil, err
+	}
+
+	season, err := s.episodeRepo.GetSeasonByNumber(itemID, seasonNumber)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrSeasonNotFound
+		}
+		return nil, err
+	}
+
+	return s.episodeRepo.SetSeasonWatched(userID, itemID, season.ID, watched)
+}
+
+func (s *episodeService) GetItemProgress(userID int, itemID string) (*models.ItemProgress, error) {
+	progress, err := s.episodeRepo.GetItemProgress(userID, itemID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrProgressNotFound
+	}
+	return progress, err
+}
+
+// RateItem ставит оценку элементу, rating = nil снимает оценку
+func (s *episodeService) RateItem(userID int, itemID string, rating *int) (*models.ItemProgress, error) {
+	if rating != nil && (*rating < minItemRating || *rating > maxItemRating) {
+		return nil, ErrInvalidRating
+	}
+
+	item, err := s.itemRepo.GetItemByID(itemID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrItemNotFound
+		}
+		return nil, err
+	}
+	if !item.IsPublic && (item.CreatorID == nil || *item.CreatorID != userID) {
+		return nil, ErrItemAccessDenied
+	}
+
+	if err := s.episodeRepo.SetItemRating(userID, itemID, rating); err != nil {
+		return nil, err
+	}
+	return s.GetItemProgress(userID, itemID)
+}
+
+func (s *episodeService) GetNextEpisodes(userID int) ([]models.NextEpisode, error) {
+	return s.episodeRepo.GetNextEpisodes(userID)
+}
+
+// getEpisodicItem проверяет, что элемент существует, доступен пользователю и поддерживает эпизоды
+func (s *episodeService) getEpisodicItem(itemID string, userID int) (*models.CollectionItem, error) {
+	item, err := s.itemRepo.GetItemByID(itemID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrItemNotFound
+		}
+		return nil, err
+	}
+
+	if !item.IsPublic && (item.CreatorID == nil || *item.CreatorID != userID) {
+		return nil, ErrItemAccessDenied
+	}
+	if !episodicItemTypes[item.Type] {
+		return nil, ErrEpisodesNotSupported
+	}
+
+	return item, nil
+}
+
+// checkSeasonsEditor - структуру сезонов публичного элемента меняют модераторы, приватного - его создатель
+func (s *episodeService) checkSeasonsEditor(itemID string, userID int) error {
+	item, err := s.getEpisodicItem(itemID, userID)
+	if err != nil {
+		return err
+	}
+	if item.IsPublic {
+		return requireModerator(s.userRepo, userID)
+	}
+	if item.CreatorID == nil || *item.CreatorID != userID {
+		return ErrNotItemCreator
+	}
+	return nil
+}
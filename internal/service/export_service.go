@@ -0,0 +1,162 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/repository"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/exporter"
+	"go.uber.org/zap"
+)
+
+var ErrUnknownExportFormat = errors.New("unknown export format")
+
+const (
+	defaultExportFormat     = "json"
+	maxExportFilenameLength = 100
+)
+
+type exportService struct {
+	registry       *exporter.Registry
+	exportRepo     repository.Export
+	collectionRepo repository.Collection
+	userRepo       repository.UserRepository
+	logger         *zap.SugaredLogger
+}
+
+func NewExportService(registry *exporter.Registry, exportRepo repository.Export, collectionRepo repository.Collection, userRepo repository.UserRepository, logger *zap.SugaredLogger) *exportService {
+	if registry == nil {
+		registry = exporter.NewDefaultRegistry()
+	}
+	return &exportService{
+		registry:       registry,
+		exportRepo:     exportRepo,
+		collectionRepo: collectionRepo,
+		userRepo:       userRepo,
+		logger:         logger,
+	}
+}
+
+// ExportCollection готовит выгрузку коллекции. Чужую публичную коллекцию можно выгрузить
+// с заметками, но без статусов, оценок и тегов владельца
+func (s *exportService) ExportCollection(userID int, collectionID string, format string) (*models.Export, error) {
+	exp, err := s.exporter(format)
+	if err != nil {
+		return nil, err
+	}
+	collection, err := s.collectionRepo.GetCollectionByID(collectionID)
+	if err != nil {
+		return nil, ErrCollectionNotFound
+	}
+	owner := collection.UserID == userID
+	if !collection.IsPublic && !owner {
+		return nil, ErrNotCollectionOwner
+	}
+	user, err := s.userRepo.GetUserByID(collection.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := exporter.Document{Title: collection.Name, Owner: user.Name, GeneratedAt: time.Now()}
+	return &models.Export{
+		Filename:    exportFilename(collection.Name, exp),
+		ContentType: exp.ContentType(),
+		Write: func(w io.Writer) error {
+			return s.write(w, exp, doc, []models.Collection{*collection}, owner)
+		},
+	}, nil
+}
+
+// ExportLibrary готовит выгрузку всех коллекций пользователя
+func (s *exportService) ExportLibrary(userID int, format string) (*models.Export, error) {
+	exp, err := s.exporter(format)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	collections, err := s.collectionRepo.GetCollections(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	doc := exporter.Document{Title: "Библиотека " + user.Name, Owner: user.Name, GeneratedAt: now}
+	return &models.Export{
+		Filename:    exportFilename("library-"+now.Format("2006-01-02"), exp),
+		ContentType: exp.ContentType(),
+		Write: func(w io.Writer) error {
+			return s.write(w, exp, doc, collections, true)
+		},
+	}, nil
+}
+
+func (s *exportService) exporter(format string) (exporter.Exporter, error) {
+	if format == "" {
+		format = defaultExportFormat
+	}
+	exp, ok := s.registry.Get(strings.ToLower(format))
+	if !ok {
+		return nil, fmt.Errorf("%w: supported formats are %s", ErrUnknownExportFormat, strings.Join(s.registry.Formats(), ", "))
+	}
+	return exp, nil
+}
+
+// write записывает коллекции по очереди, элементы каждой читаются из базы потоком.
+// personal - добавлять ли статусы, оценки и теги владельца коллекции
+func (s *exportService) write(w io.Writer, exp exporter.Exporter, doc exporter.Document, collections []models.Collection, personal bool) error {
+	writer, err := exp.NewWriter(w, doc)
+	if err != nil {
+		return err
+	}
+	for _, collection := range collections {
+		err := writer.StartCollection(exporter.Collection{
+			ID:          collection.ID,
+			Name:        collection.Name,
+			Description: collection.Description,
+			Type:        collection.Type,
+			IsPublic:    collection.IsPublic,
+			CreatedAt:   collection.CreatedAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = s.exportRepo.StreamCollectionEntries(collection.ID, collection.UserID, func(entry exporter.Entry) error {
+			if !personal {
+				entry.Status, entry.Progress, entry.Rating = "", 0, nil
+				entry.StartedAt, entry.FinishedAt, entry.Tags = nil, nil, nil
+			}
+			return writer.WriteEntry(entry)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return writer.Close()
+}
+
+var exportFilenameReplacer = strings.NewReplacer(
+	"/", "_", `\`, "_", ":", "_", "*", "_", "?", "_", `"`, "_", "<", "_", ">", "_", "|", "_",
+)
+
+// exportFilename - имя файла выгрузки без символов, недопустимых в именах файлов
+func exportFilename(name string, exp exporter.Exporter) string {
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, exportFilenameReplacer.Replace(name))
+	name = strings.Trim(truncateRunes(strings.TrimSpace(name), maxExportFilenameLength), ". ")
+	if name == "" {
+		name = "collection"
+	}
+	return name + "." + exp.Extension()
+}
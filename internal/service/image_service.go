@@ -0,0 +1,286 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/repository"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/blobstore"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/thumbnail"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrImageTooLarge        = errors.New("image file is too large")
+	ErrImageUnsupported     = errors.New("only jpeg, png and gif images are supported")
+	ErrImageNotFound        = errors.New("image not found")
+	ErrInvalidImageSize     = errors.New("unknown image size")
+	ErrFileNotFound         = errors.New("file not found")
+	ErrItemCoverForbidden   = errors.New("only creator of a private item or a moderator can change its cover")
+	ErrSignedURLUnsupported = errors.New("blob store serves files by its own signed urls")
+)
+
+const (
+	defaultMaxUploadSize = 5 << 20
+	defaultSignedURLTTL  = 15 * time.Minute
+	storageTimeout       = 30 * time.Second
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// ImageConfig - настройки загрузки и выдачи изображений
+type ImageConfig struct {
+	// PublicURL - внешний адрес API, от него строятся постоянные ссылки /images/<id>
+	PublicURL string
+	// MaxUploadSize - максимальный размер загружаемого файла в байтах
+	MaxUploadSize int64
+	// SignedURLTTL - срок действия подписанных ссылок на файлы
+	SignedURLTTL time.Duration
+}
+
+type imageService struct {
+	imageRepo      repository.Image
+	itemRepo       repository.CollectionItem
+	collectionRepo repository.Collection
+	userRepo       repository.UserRepository
+	storage        blobstore.BlobStore
+	cfg            ImageConfig
+	events         *eventPublisher
+	logger         *zap.SugaredLogger
+}
+
+func NewImageService(imageRepo repository.Image, itemRepo repository.CollectionItem, collectionRepo repository.Collection, userRepo repository.UserRepository, storage blobstore.BlobStore, cfg ImageConfig, events *eventPublisher, logger *zap.SugaredLogger) *imageService {
+	if cfg.MaxUploadSize <= 0 {
+		cfg.MaxUploadSize = defaultMaxUploadSize
+	}
+	if cfg.SignedURLTTL <= 0 {
+		cfg.SignedURLTTL = defaultSignedURLTTL
+	}
+	return &imageService{
+		imageRepo:      imageRepo,
+		itemRepo:       itemRepo,
+		collectionRepo: collectionRepo,
+		userRepo:       userRepo,
+		storage:        storage,
+		cfg:            cfg,
+		events:         events,
+		logger:         logger,
+	}
+}
+
+// UploadItemCover - обложку публичного элемента меняет модератор, приватного - его автор
+func (s *imageService) UploadItemCover(userID int, itemID string, file io.Reader) (*models.ImageUpload, error) {
+	item, err := s.itemRepo.GetItemByID(itemID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrItemNotFound
+		}
+		return nil, err
+	}
+	if item.IsPublic || item.CreatorID == nil || *item.CreatorID != userID {
+		if err := requireModerator(s.userRepo, userID); err != nil {
+			if errors.Is(err, ErrModeratorRequired) {
+				return nil, ErrItemCoverForbidden
+			}
+			return nil, err
+		}
+	}
+
+	upload, err := s.upload(userID, models.ImageTargetItem, itemID, file)
+	if err != nil {
+		return nil, err
+	}
+	s.events.itemsChanged()
+	return upload, nil
+}
+
+func (s *imageService) UploadCollectionCover(userID int, collectionID string, file io.Reader) (*models.ImageUpload, error) {
+	collection, err := s.collectionRepo.GetCollectionByID(collectionID)
+	if err != nil {
+		return nil, ErrCollectionNotFound
+	}
+	if collection.UserID != userID {
+		return nil, ErrNotCollectionOwner
+	}
+
+	upload, err := s.upload(userID, models.ImageTargetCollection, collectionID, file)
+	if err != nil {
+		return nil, err
+	}
+	s.events.collectionChanged(collection, models.EventCollectionUpdated, models.CollectionUpdatedEvent{
+		Change:   models.CollectionChangeCover,
+		CoverURL: upload.URL,
+	})
+	return upload, nil
+}
+
+func (s *imageService) UploadAvatar(userID int, file io.Reader) (*models.ImageUpload, error) {
+	return s.upload(userID, models.ImageTargetAvatar, strconv.Itoa(userID), file)
+}
+
+// GetImageURL возвращает подписанную ссылку на оригинал или миниатюру
+func (s *imageService) GetImageURL(imageID string, size string) (string, error) {
+	if !uuidPattern.MatchString(imageID) {
+		return "", ErrImageNotFound
+	}
+	if size == "" {
+		size = models.ImageSizeOriginal
+	}
+	if _, ok := models.ImageThumbnailSizes[size]; !ok && size != models.ImageSizeOriginal {
+		return "", ErrInvalidImageSize
+	}
+
+	image, err := s.imageRepo.GetImageByID(imageID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return "", ErrImageNotFound
+		}
+		return "", err
+	}
+
+	key := image.OriginalKey
+	if size != models.ImageSizeOriginal {
+		key = thumbnailKey(image.BlobPrefix, size)
+	}
+	return s.storage.SignedURL(key, s.cfg.SignedURLTTL)
+}
+
+// OpenFile отдает файл по подписанной ссылке, если хранилище не умеет выдавать ссылки само (локальный диск)
+func (s *imageService) OpenFile(ctx context.Context, key string, expires string, signature string) (*blobstore.Object, error) {
+	verifier, ok := s.storage.(blobstore.SignatureVerifier)
+	if !ok {
+		return nil, ErrSignedURLUnsupported
+	}
+	if err := verifier.VerifySignature(key, expires, signature); err != nil {
+		return nil, err
+	}
+
+	object, err := s.storage.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, blobstore.ErrNotFound) || errors.Is(err, blobstore.ErrInvalidKey) {
+			return nil, ErrFileNotFound
+		}
+		return nil, err
+	}
+	return object, nil
+}
+
+// upload проверяет файл по содержимому, сохраняет оригинал и миниатюры и привязывает изображение к владельцу
+func (s *imageService) upload(userID int, targetType string, targetID string, file io.Reader) (*models.ImageUpload, error) {
+	data, err := io.ReadAll(io.LimitReader(file, s.cfg.MaxUploadSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+	if int64(len(data)) > s.cfg.MaxUploadSize {
+		return nil, ErrImageTooLarge
+	}
+
+	contentType, ext, err := thumbnail.Sniff(data)
+	if err != nil {
+		return nil, ErrImageUnsupported
+	}
+	img, err := thumbnail.Decode(data)
+	if err != nil {
+		if errors.Is(err, thumbnail.ErrTooManyPixels) {
+			return nil, ErrImageTooLarge
+		}
+		return nil, ErrImageUnsupported
+	}
+
+	id, err := newUUID()
+	if err != nil {
+		return nil, err
+	}
+	image := &models.Image{
+		ID:          id,
+		OwnerID:     &userID,
+		TargetType:  targetType,
+		TargetID:    targetID,
+		BlobPrefix:  fmt.Sprintf("images/%s/%s", targetType, id),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		Width:       img.Bounds().Dx(),
+		Height:      img.Bounds().Dy(),
+	}
+	image.OriginalKey = image.BlobPrefix + "/" + models.ImageSizeOriginal + ext
+
+	ctx, cancel := context.WithTimeout(context.Background(), storageTimeout)
+	defer cancel()
+
+	keys := []string{image.OriginalKey}
+	if err := s.storage.Put(ctx, image.OriginalKey, data, contentType); err != nil {
+		return nil, fmt.Errorf("failed to store image: %w", err)
+	}
+	for size, maxSide := range models.ImageThumbnailSizes {
+		thumb, err := thumbnail.EncodeJPEG(thumbnail.Fit(img, maxSide))
+		if err == nil {
+			key := thumbnailKey(image.BlobPrefix, size)
+			keys = append(keys, key)
+			err = s.storage.Put(ctx, key, thumb, "image/jpeg")
+		}
+		if err != nil {
+			s.cleanup(keys)
+			return nil, fmt.Errorf("failed to store %s thumbnail: %w", size, err)
+		}
+	}
+
+	if err := s.imageRepo.SaveImage(image, s.imageURL(id, "")); err != nil {
+		s.cleanup(keys)
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrImageNotFound
+		}
+		return nil, err
+	}
+
+	upload := &models.ImageUpload{
+		Image:      *image,
+		URL:        s.imageURL(id, ""),
+		Thumbnails: make(map[string]string, len(models.ImageThumbnailSizes)),
+	}
+	for size := range models.ImageThumbnailSizes {
+		upload.Thumbnails[size] = s.imageURL(id, size)
+	}
+	return upload, nil
+}
+
+// cleanup удаляет уже загруженные файлы, если изображение не удалось сохранить целиком
+func (s *imageService) cleanup(keys []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), storageTimeout)
+	defer cancel()
+	for _, key := range keys {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			s.logger.Warnf("Failed to delete orphaned blob %s: %v", key, err)
+		}
+	}
+}
+
+// imageURL - постоянная ссылка на изображение, не истекает и редиректит на подписанную
+func (s *imageService) imageURL(id string, size string) string {
+	url := fmt.Sprintf("%s/images/%s", s.cfg.PublicURL, id)
+	if size != "" {
+		url += "?size=" + size
+	}
+	return url
+}
+
+func thumbnailKey(prefix string, size string) string {
+	return prefix + "/" + size + ".jpg"
+}
+
+// newUUID генерирует UUID v4
+func newUUID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate id: %w", err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
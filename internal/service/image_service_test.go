@@ -0,0 +1,44 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"go.uber.org/zap"
+)
+
+func TestImageUploadSizeLimit(t *testing.T) {
+	s := NewImageService(nil, nil, nil, nil, nil, ImageConfig{MaxUploadSize: 16}, nil, zap.NewNop().Sugar())
+
+	tests := []struct {
+		name string
+		size int
+		want error
+	}{
+		// Файлы в пределах лимита доходят до проверки содержимого
+		{"below limit", 15, ErrImageUnsupported},
+		{"at limit", 16, ErrImageUnsupported},
+		{"one byte over", 17, ErrImageTooLarge},
+		{"far over", 1 << 20, ErrImageTooLarge},
+	}
+	for _, tt := range tests {
+		_, err := s.upload(1, models.ImageTargetAvatar, "1", bytes.NewReader(bytes.Repeat([]byte("x"), tt.size)))
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: upload of %d bytes = %v, want %v", tt.name, tt.size, err, tt.want)
+		}
+	}
+}
+
+func TestImageUploadDefaultSizeLimit(t *testing.T) {
+	s := NewImageService(nil, nil, nil, nil, nil, ImageConfig{}, nil, zap.NewNop().Sugar())
+	if s.cfg.MaxUploadSize != defaultMaxUploadSize {
+		t.Fatalf("MaxUploadSize = %d, want default %d", s.cfg.MaxUploadSize, defaultMaxUploadSize)
+	}
+
+	_, err := s.upload(1, models.ImageTargetAvatar, "1", bytes.NewReader(make([]byte, defaultMaxUploadSize+1)))
+	if !errors.Is(err, ErrImageTooLarge) {
+		t.Errorf("upload over default limit = %v, want ErrImageTooLarge", err)
+	}
+}
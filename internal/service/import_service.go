@@ -0,0 +1,412 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/repository"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/importer"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrImportNotFound         = errors.New("import not found")
+	ErrImportRowNotFound      = errors.New("import row not found")
+	ErrImportFileEmpty        = errors.New("import file is empty")
+	ErrImportFileTooLarge     = errors.New("import file is too large")
+	ErrImportNothingToImport  = errors.New("import file has no records")
+	ErrImportRowNotResolvable = errors.New("only created, unmatched and failed rows can be resolved")
+	ErrImportTypeMismatch     = errors.New("item type doesn't match import row")
+)
+
+const (
+	// MaxImportFileSize - ограничение на размер загружаемой выгрузки
+	MaxImportFileSize = 20 << 20
+	// importPollInterval - как часто обработчик проверяет очередь, если его не разбудили
+	importPollInterval = 5 * time.Second
+	// importStaleAfter - задачу, которая не обновлялась дольше, подхватывает другой обработчик
+	importStaleAfter = 5 * time.Minute
+	importBatchSize  = 100
+	// importTitleMatchThreshold - минимальная похожесть названий для сопоставления без внешних ID
+	importTitleMatchThreshold = 0.75
+	maxCollectionNameLength   = 100
+)
+
+// importDefaultLists - коллекция для записей без полок и списков
+var importDefaultLists = map[string]string{
+	importer.FormatGoodreads:  "Goodreads",
+	importer.FormatMAL:        "MyAnimeList",
+	importer.FormatLetterboxd: "Letterboxd",
+	importer.FormatIMDb:       "IMDb",
+}
+
+// importUnmatchedStatuses - строки, которые пользователь может сопоставить вручную
+var importUnmatchedStatuses = []string{models.ImportRowCreated, models.ImportRowUnmatched, models.ImportRowFailed}
+
+type importService struct {
+	importRepo    repository.Import
+	itemRepo      repository.CollectionItem
+	duplicateRepo repository.Duplicate
+	events        *eventPublisher
+	// wake будит обработчик после создания задачи, не дожидаясь очередной проверки очереди
+	wake   chan struct{}
+	logger *zap.SugaredLogger
+}
+
+func NewImportService(importRepo repository.Import, itemRepo repository.CollectionItem, duplicateRepo repository.Duplicate, events *eventPublisher, logger *zap.SugaredLogger) *importService {
+	return &importService{
+		importRepo:    importRepo,
+		itemRepo:      itemRepo,
+		duplicateRepo: duplicateRepo,
+		events:        events,
+		wake:          make(chan struct{}, 1),
+		logger:        logger,
+	}
+}
+
+// CreateImport разбирает выгрузку и ставит задачу в очередь. Сопоставление идет в фоне
+func (s *importService) CreateImport(userID int, format string, filename string, file io.Reader, createCustom bool) (*models.ImportJob, error) {
+	data, err := io.ReadAll(io.LimitReader(file, MaxImportFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read import file: %w", err)
+	}
+	if len(data) == 0 {
+		return nil, ErrImportFileEmpty
+	}
+	if len(data) > MaxImportFileSize {
+		return nil, ErrImportFileTooLarge
+	}
+
+	records, err := importer.Parse(format, filename, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, ErrImportNothingToImport
+	}
+
+	job := &models.ImportJob{
+		UserID:       userID,
+		Format:       format,
+		CreateCustom: createCustom,
+	}
+	if filename != "" {
+		name := truncateRunes(filename, 255)
+		job.Filename = &name
+	}
+	job, err = s.importRepo.CreateJob(job, records)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	s.logger.Infof("User %d queued %s import %s with %d records", userID, format, job.ID, len(records))
+	return job, nil
+}
+
+func (s *importService) GetImport(userID int, jobID string) (*models.ImportJob, error) {
+	job, err := s.importRepo.GetJob(userID, jobID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrImportNotFound
+	}
+	return job, err
+}
+
+func (s *importService) GetImports(userID int, pagination pagination.PaginationRequest) (*pagination.PaginatedResponse[models.ImportJob], error) {
+	return s.importRepo.GetJobs(userID, pagination)
+}
+
+// GetUnmatchedRows - строки без элемента каталога: созданные свои элементы, несопоставленные и ошибочные
+func (s *importService) GetUnmatchedRows(userID int, jobID string, pagination pagination.PaginationRequest) (*pagination.PaginatedResponse[models.ImportRow], error) {
+	if _, err := s.GetImport(userID, jobID); err != nil {
+		return nil, err
+	}
+	return s.importRepo.GetRows(jobID, importUnmatchedStatuses, pagination)
+}
+
+// ResolveRow сопоставляет строку с элементом вручную. Созданный импортом свой элемент сливается
+// с выбранным, чтобы не оставлять дубликат в библиотеке
+func (s *importService) ResolveRow(userID int, jobID string, rowID int, itemID string) (*models.ImportRow, error) {
+	job, err := s.GetImport(userID, jobID)
+	if err != nil {
+		return nil, err
+	}
+	row, err := s.importRepo.GetRow(jobID, rowID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrImportRowNotFound
+		}
+		return nil, err
+	}
+	if !isUnmatchedImportRow(row.Status) {
+		return nil, ErrImportRowNotResolvable
+	}
+
+	target, err := s.itemRepo.GetItemByID(itemID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrItemNotFound
+		}
+		return nil, err
+	}
+	if !target.IsPublic && (target.CreatorID == nil || *target.CreatorID != userID) {
+		return nil, ErrItemNotFound
+	}
+	if target.Type != row.Record.Type {
+		return nil, ErrImportTypeMismatch
+	}
+
+	if row.Status == models.ImportRowCreated && row.ItemID != nil && *row.ItemID != target.ID {
+		custom, err := s.itemRepo.GetItemByID(*row.ItemID)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+		if err == nil && custom.IsCustom && custom.CreatorID != nil && *custom.CreatorID == userID {
+			if _, err := s.duplicateRepo.MergeItems(custom.ID, target.ID, userID); err != nil {
+				return nil, err
+			}
+			s.events.itemsChanged()
+		}
+	}
+
+	collectionIDs, err := s.collections(job, row.Record, map[string]string{})
+	if err != nil {
+		return nil, err
+	}
+	err = s.importRepo.SaveRowResult(userID, row, models.ImportRowResult{
+		Status:        models.ImportRowResolved,
+		MatchMethod:   models.ImportMatchManual,
+		ItemID:        &target.ID,
+		CollectionIDs: collectionIDs,
+		SourceIDs:     row.Record.ExternalIDs,
+	})
+	if errors.Is(err, repository.ErrNothingChanged) {
+		return nil, ErrImportRowNotResolvable
+	}
+	if err != nil {
+		return nil, err
+	}
+	return row, nil
+}
+
+// RunImports - фоновый обработчик очереди импорта, работает, пока не отменен ctx
+func (s *importService) RunImports(ctx context.Context) {
+	ticker := time.NewTicker(importPollInterval)
+	defer ticker.Stop()
+	for {
+		for ctx.Err() == nil && s.processNext(ctx) {
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// processNext берет задачу из очереди и обрабатывает ее. Возвращает false, если очередь пуста
+func (s *importService) processNext(ctx context.Context) bool {
+	job, err := s.importRepo.ClaimJob(time.Now().Add(-importStaleAfter))
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			s.logger.Errorf("Failed to claim import job: %v", err)
+		}
+		return false
+	}
+	s.logger.Infof("Processing %s import %s of user %d", job.Format, job.ID, job.UserID)
+
+	// Коллекции по спискам выгрузки создаются один раз на задачу
+	collections := map[string]string{}
+	for {
+		rows, err := s.importRepo.GetPendingRows(job.ID, importBatchSize)
+		if err != nil {
+			s.finish(job, models.ImportStatusFailed, err)
+			return true
+		}
+		if len(rows) == 0 {
+			break
+		}
+		for i := range rows {
+			if ctx.Err() != nil {
+				// Задача останется в работе, и ее подхватят после importStaleAfter
+				return false
+			}
+			if err := s.processRow(job, &rows[i], collections); err != nil {
+				s.finish(job, models.ImportStatusFailed, err)
+				return true
+			}
+		}
+		s.publishProgress(job)
+	}
+
+	s.finish(job, models.ImportStatusCompleted, nil)
+	return true
+}
+
+func (s *importService) finish(job *models.ImportJob, status string, cause error) {
+	var message *string
+	if cause != nil {
+		s.logger.Errorf("Import %s failed: %v", job.ID, cause)
+		text := cause.Error()
+		message = &text
+	}
+	finished, err := s.importRepo.FinishJob(job.ID, status, message)
+	if err != nil {
+		s.logger.Errorf("Failed to finish import %s: %v", job.ID, err)
+		return
+	}
+	s.logger.Infof("Import %s %s: %d matched, %d created, %d unmatched, %d failed", finished.ID, finished.Status,
+		finished.MatchedRows, finished.CreatedRows, finished.UnmatchedRows, finished.FailedRows)
+	s.events.importProgress(finished)
+	s.events.notificationsChanged(finished.UserID)
+}
+
+// publishProgress сообщает пользователю прогресс задачи после каждой пачки строк
+func (s *importService) publishProgress(job *models.ImportJob) {
+	current, err := s.importRepo.GetJob(job.UserID, job.ID)
+	if err != nil {
+		s.logger.Warnf("Failed to get import %s progress: %v", job.ID, err)
+		return
+	}
+	s.events.importProgress(current)
+}
+
+// processRow сопоставляет строку и сохраняет результат. Ошибка строки не останавливает задачу,
+// возвращается только ошибка, из-за которой строку не удалось даже отметить ошибочной
+func (s *importService) processRow(job *models.ImportJob, row *models.ImportRow, collections map[string]string) error {
+	result, err := s.matchRow(job, row.Record)
+	if err == nil && result.ItemID != nil {
+		result.CollectionIDs, err = s.collections(job, row.Record, collections)
+	}
+	if err == nil {
+		err = s.importRepo.SaveRowResult(job.UserID, row, result)
+	}
+	if err == nil || errors.Is(err, repository.ErrNothingChanged) {
+		return nil
+	}
+
+	s.logger.Warnf("Import %s: line %d failed: %v", job.ID, row.Line, err)
+	message := err.Error()
+	failed := models.ImportRowResult{Status: models.ImportRowFailed, Error: &message}
+	if err := s.importRepo.SaveRowResult(job.UserID, row, failed); err != nil && !errors.Is(err, repository.ErrNothingChanged) {
+		return fmt.Errorf("failed to save import row %d: %w", row.ID, err)
+	}
+	return nil
+}
+
+// matchRow ищет элемент по внешним ID, затем по похожему названию и году. Не найденный элемент
+// создается как свой элемент пользователя, если задача это разрешает
+func (s *importService) matchRow(job *models.ImportJob, record importer.Record) (models.ImportRowResult, error) {
+	title := strings.TrimSpace(record.Title)
+	if title == "" {
+		return models.ImportRowResult{}, errors.New("record has no title")
+	}
+	if !lookupItemTypes[record.Type] {
+		return models.ImportRowResult{}, fmt.Errorf("unsupported item type %q", record.Type)
+	}
+
+	if len(record.ExternalIDs) > 0 {
+		itemID, err := s.importRepo.FindItemByExternalIDs(record.ExternalIDs, record.Type, job.UserID)
+		if err == nil {
+			return models.ImportRowResult{
+				Status:      models.ImportRowMatched,
+				MatchMethod: models.ImportMatchExternalID,
+				ItemID:      &itemID,
+				SourceIDs:   record.ExternalIDs,
+			}, nil
+		}
+		if !errors.Is(err, repository.ErrNotFound) {
+			return models.ImportRowResult{}, err
+		}
+	}
+
+	item := importRecordItem(record, job.UserID)
+	candidates, err := s.duplicateRepo.FindDuplicates(item, job.UserID, 1)
+	if err != nil {
+		return models.ImportRowResult{}, err
+	}
+	if len(candidates) > 0 && candidates[0].Similarity >= importTitleMatchThreshold {
+		return models.ImportRowResult{
+			Status:      models.ImportRowMatched,
+			MatchMethod: models.ImportMatchTitle,
+			ItemID:      &candidates[0].Item.ID,
+			SourceIDs:   record.ExternalIDs,
+		}, nil
+	}
+
+	if !job.CreateCustom {
+		return models.ImportRowResult{Status: models.ImportRowUnmatched}, nil
+	}
+	itemID, err := s.itemRepo.CreateItem(item)
+	if err != nil {
+		return models.ImportRowResult{}, err
+	}
+	s.events.itemsChanged()
+	return models.ImportRowResult{Status: models.ImportRowCreated, ItemID: &itemID, SourceIDs: record.ExternalIDs}, nil
+}
+
+// collections - ID коллекций пользователя для списков записи, cache хранит уже найденные коллекции задачи
+func (s *importService) collections(job *models.ImportJob, record importer.Record, cache map[string]string) ([]string, error) {
+	lists := record.Lists
+	if len(lists) == 0 {
+		lists = []string{importDefaultLists[job.Format]}
+	}
+
+	ids := make([]string, 0, len(lists))
+	for _, list := range lists {
+		name := truncateRunes(strings.TrimSpace(list), maxCollectionNameLength)
+		if name == "" {
+			continue
+		}
+		key := record.Type + "\x00" + strings.ToLower(name)
+		id, ok := cache[key]
+		if !ok {
+			var err error
+			if id, err = s.importRepo.EnsureCollection(job.UserID, name, record.Type); err != nil {
+				return nil, err
+			}
+			cache[key] = id
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// importRecordItem - свой элемент пользователя по записи выгрузки
+func importRecordItem(record importer.Record, userID int) *models.CollectionItem {
+	item := &models.CollectionItem{
+		Type:             record.Type,
+		Title:            truncateRunes(strings.TrimSpace(record.Title), maxImportTitleLength),
+		ReleaseYear:      record.Year,
+		IsCustom:         true,
+		IsPublic:         false,
+		CreatorID:        &userID,
+		ModerationStatus: models.ModerationStatusNone,
+	}
+	if item.ReleaseYear != nil && (*item.ReleaseYear < minReleaseYear || *item.ReleaseYear > maxReleaseYear) {
+		item.ReleaseYear = nil
+	}
+	if len(record.Creators) > 0 {
+		item.Description = "Авторы: " + strings.Join(record.Creators, ", ")
+	}
+	return item
+}
+
+func isUnmatchedImportRow(status string) bool {
+	for _, unmatched := range importUnmatchedStatuses {
+		if status == unmatched {
+			return true
+		}
+	}
+	return false
+}
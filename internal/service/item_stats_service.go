@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/repository"
+	"go.uber.org/zap"
+)
+
+const defaultItemStatsInterval = 5 * time.Minute
+
+// ItemStatsConfig - пересчет популярности и оценок элементов для фильтров, фасетов и сортировки ленты
+type ItemStatsConfig struct {
+	Enabled bool
+	// Interval - как часто пересчитывается статистика, на столько лента может отставать от коллекций и оценок
+	Interval time.Duration
+}
+
+type itemStatsService struct {
+	itemRepo repository.CollectionItem
+	cfg      ItemStatsConfig
+	logger   *zap.SugaredLogger
+}
+
+func NewItemStatsService(itemRepo repository.CollectionItem, cfg ItemStatsConfig, logger *zap.SugaredLogger) *itemStatsService {
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultItemStatsInterval
+	}
+	return &itemStatsService{
+		itemRepo: itemRepo,
+		cfg:      cfg,
+		logger:   logger,
+	}
+}
+
+// RunItemStats - фоновый пересчет статистики элементов раз в Interval, пока не отменен ctx
+func (s *itemStatsService) RunItemStats(ctx context.Context) {
+	if !s.cfg.Enabled {
+		return
+	}
+	s.logger.Infof("Item stats started: every %s", s.cfg.Interval)
+
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		if err := s.recomputeIfStale(); err != nil && ctx.Err() == nil {
+			s.logger.Errorf("Item stats recompute failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// recomputeIfStale пересчитывает статистику, если ее не пересчитывал недавно другой экземпляр
+// или предыдущий запуск приложения
+func (s *itemStatsService) recomputeIfStale() error {
+	computedAt, err := s.itemRepo.GetItemStatsComputedAt()
+	if err != nil {
+		return err
+	}
+	if computedAt != nil && time.Since(*computedAt) < s.cfg.Interval/2 {
+		return nil
+	}
+
+	started := time.Now()
+	rows, ran, err := s.itemRepo.RecomputeItemStats()
+	if err != nil {
+		return err
+	}
+	if ran {
+		s.logger.Infof("Item stats recomputed: %d items in %s", rows, time.Since(started).Round(time.Millisecond))
+	}
+	return nil
+}
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/repository"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/metadata"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrItemNotImported      = errors.New("item has no external ids")
+	ErrInvalidLockField     = errors.New("field can't be locked")
+	ErrFieldNotLocked       = errors.New("field is not locked")
+	ErrProviderNotAvailable = errors.New("metadata provider is not configured")
+)
+
+const (
+	defaultRefreshInterval  = time.Hour
+	defaultRefreshMaxAge    = 7 * 24 * time.Hour
+	defaultRefreshBatchSize = 100
+	defaultRefreshRateLimit = 30
+)
+
+// MetadataRefreshConfig - расписание обновления импортированных элементов из внешних каталогов
+type MetadataRefreshConfig struct {
+	Enabled bool
+	// Interval - как часто планировщик ищет устаревшие элементы
+	Interval time.Duration
+	// MaxAge - элемент обновляется, если не обновлялся дольше MaxAge
+	MaxAge time.Duration
+	// BatchSize - сколько элементов обновляется за один проход
+	BatchSize int
+	// RateLimits - запросов в минуту к каждому провайдеру, по умолчанию 30
+	RateLimits map[string]int
+}
+
+type metadataRefreshService struct {
+	registry     *metadata.Registry
+	metadataRepo repository.Metadata
+	itemRepo     repository.CollectionItem
+	userRepo     repository.UserRepository
+	cfg          MetadataRefreshConfig
+	events       *eventPublisher
+	// Ограничения частоты запросов общие для планировщика и ручного обновления
+	limitersMu sync.Mutex
+	limiters   map[string]*metadata.Limiter
+	logger     *zap.SugaredLogger
+}
+
+func NewMetadataRefreshService(registry *metadata.Registry, metadataRepo repository.Metadata, itemRepo repository.CollectionItem, userRepo repository.UserRepository, cfg MetadataRefreshConfig, events *eventPublisher, logger *zap.SugaredLogger) *metadataRefreshService {
+	if registry == nil {
+		registry = metadata.NewRegistry()
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultRefreshInterval
+	}
+	if cfg.MaxAge <= 0 {
+		cfg.MaxAge = defaultRefreshMaxAge
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultRefreshBatchSize
+	}
+	return &metadataRefreshService{
+		registry:     registry,
+		metadataRepo: metadataRepo,
+		itemRepo:     itemRepo,
+		userRepo:     userRepo,
+		cfg:          cfg,
+		events:       events,
+		limiters:     map[string]*metadata.Limiter{},
+		logger:       logger,
+	}
+}
+
+// RunMetadataRefresh - фоновый планировщик: раз в Interval обновляет устаревшие элементы, пока не отменен ctx
+func (s *metadataRefreshService) RunMetadataRefresh(ctx context.Context) {
+	if !s.cfg.Enabled {
+		return
+	}
+	s.logger.Infof("Metadata refresh started: every %s, max age %s", s.cfg.Interval, s.cfg.MaxAge)
+
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		if err := s.RefreshStale(ctx); err != nil && ctx.Err() == nil {
+			s.logger.Errorf("Metadata refresh failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RefreshStale обновляет одну партию устаревших элементов. Провайдеры обрабатываются параллельно,
+// запросы к каждому - последовательно в пределах его ограничения частоты
+func (s *metadataRefreshService) RefreshStale(ctx context.Context) error {
+	stale, err := s.metadataRepo.GetStaleExternalIDs(s.registry.Names(), time.Now().Add(-s.cfg.MaxAge), s.cfg.BatchSize)
+	if err != nil {
+		return err
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+
+	byProvider := map[string][]models.ExternalID{}
+	for _, externalID := range stale {
+		byProvider[externalID.Provider] = append(byProvider[externalID.Provider], externalID)
+	}
+
+	var mu sync.Mutex
+	changed, failed := 0, 0
+	var wg sync.WaitGroup
+	for _, externalIDs := range byProvider {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, externalID := range externalIDs {
+				result := s.refresh(ctx, externalID)
+				if ctx.Err() != nil {
+					return
+				}
+				mu.Lock()
+				if result.Error != "" {
+					failed++
+				} else if result.Revision != nil {
+					changed++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	s.logger.Infof("Metadata refresh: %d checked, %d changed, %d failed", len(stale), changed, failed)
+	return ctx.Err()
+}
+
+// RefreshItem - внеочередное обновление элемента модератором из его основного внешнего каталога
+func (s *metadataRefreshService) RefreshItem(ctx context.Context, userID int, itemID string) (*models.MetadataRefreshResult, error) {
+	if err := requireModerator(s.userRepo, userID); err != nil {
+		return nil, err
+	}
+	externalIDs, err := s.metadataRepo.GetExternalIDs(itemID)
+	if err != nil {
+		return nil, err
+	}
+	// Обновляется первый ID из подключенного каталога, ID из выгрузок других трекеров пропускаются
+	for _, externalID := range externalIDs {
+		if _, ok := s.registry.Get(externalID.Provider); ok {
+			return s.refresh(ctx, externalID), nil
+		}
+	}
+	if _, err := s.getItem(itemID); err != nil {
+		return nil, err
+	}
+	return nil, ErrItemNotImported
+}
+
+// refresh загружает запись из каталога и применяет ее к элементу. Ошибка сохраняется у внешнего ID
+// и возвращается в результате, следующая попытка - в очередном цикле после MaxAge
+func (s *metadataRefreshService) refresh(ctx context.Context, externalID models.ExternalID) *models.MetadataRefreshResult {
+	result := &models.MetadataRefreshResult{
+		ItemID:        externalID.ItemID,
+		Provider:      externalID.Provider,
+		ExternalID:    externalID.ExternalID,
+		SkippedFields: []string{},
+	}
+	fail := func(err error) *models.MetadataRefreshResult {
+		result.Error = err.Error()
+		if ctx.Err() != nil {
+			return result
+		}
+		s.logger.Warnf("Failed to refresh %s/%s: %v", externalID.Provider, externalID.ExternalID, err)
+		if err := s.metadataRepo.MarkRefreshFailed(externalID.Provider, externalID.ExternalID, result.Error); err != nil {
+			s.logger.Errorf("Failed to save refresh error: %v", err)
+		}
+		return result
+	}
+
+	provider, ok := s.registry.Get(externalID.Provider)
+	if !ok {
+		return fail(ErrProviderNotAvailable)
+	}
+	if err := s.limiter(provider.Name()).Wait(ctx); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	data, err := provider.Get(ctx, externalID.ExternalID)
+	if err != nil {
+		return fail(err)
+	}
+
+	revision, skipped, err := s.metadataRepo.ApplyRefresh(externalID, refreshChanges(data))
+	if err != nil {
+		return fail(err)
+	}
+	if revision != nil {
+		s.events.itemsChanged()
+	}
+	result.Revision = revision
+	result.SkippedFields = skipped
+	return result
+}
+
+func (s *metadataRefreshService) limiter(provider string) *metadata.Limiter {
+	s.limitersMu.Lock()
+	defer s.limitersMu.Unlock()
+
+	limiter, ok := s.limiters[provider]
+	if !ok {
+		perMinute := s.cfg.RateLimits[provider]
+		if perMinute <= 0 {
+			perMinute = defaultRefreshRateLimit
+		}
+		limiter = metadata.NewLimiter(perMinute)
+		s.limiters[provider] = limiter
+	}
+	return limiter
+}
+
+func (s *metadataRefreshService) GetLockedFields(itemID string) ([]models.ItemFieldLock, error) {
+	if _, err := s.getItem(itemID); err != nil {
+		return nil, err
+	}
+	return s.metadataRepo.GetLockedFields(itemID)
+}
+
+// LockField закрепляет поле: обновление из внешних каталогов перестает его менять
+func (s *metadataRefreshService) LockField(userID int, itemID string, field string) (*models.ItemFieldLock, error) {
+	if err := requireModerator(s.userRepo, userID); err != nil {
+		return nil, err
+	}
+	if !models.LockableItemFields[field] {
+		return nil, ErrInvalidLockField
+	}
+	if _, err := s.getItem(itemID); err != nil {
+		return nil, err
+	}
+	return s.metadataRepo.LockField(itemID, field, userID)
+}
+
+func (s *metadataRefreshService) UnlockField(userID int, itemID string, field string) error {
+	if err := requireModerator(s.userRepo, userID); err != nil {
+		return err
+	}
+	err := s.metadataRepo.UnlockField(itemID, field)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrFieldNotLocked
+	}
+	return err
+}
+
+func (s *metadataRefreshService) getItem(itemID string) (*models.CollectionItem, error) {
+	item, err := s.itemRepo.GetItemByID(itemID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrItemNotFound
+		}
+		return nil, err
+	}
+	return item, nil
+}
+
+// refreshChanges - поля элемента по данным каталога. Пустые значения каталога не затирают заполненные поля
+func refreshChanges(data *metadata.Result) map[string]any {
+	item := importedItem(data, 0)
+	changes := map[string]any{}
+	if item.Title != "" {
+		changes["title"] = item.Title
+	}
+	if item.Description != "" {
+		changes["description"] = item.Description
+	}
+	if item.CoverImage != nil {
+		changes["cover_image"] = *item.CoverImage
+	}
+	if item.ReleaseYear != nil {
+		changes["release_year"] = *item.ReleaseYear
+	}
+	return changes
+}
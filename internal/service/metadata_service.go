@@ -0,0 +1,302 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/repository"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/in_memory_cache"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/metadata"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrUnknownProvider      = errors.New("unknown metadata provider")
+	ErrLookupQueryEmpty     = errors.New("lookup requires q or isbn")
+	ErrLookupInvalidType    = errors.New("unknown item type")
+	ErrExternalItemNotFound = errors.New("item not found in external catalog")
+	ErrInvalidExternalID    = errors.New("invalid external id for provider")
+	ErrProviderUnavailable  = errors.New("metadata provider is unavailable")
+)
+
+const (
+	lookupCacheTTL        = 10 * time.Minute
+	maxLookupQueryLength  = 200
+	defaultLookupLimit    = 10
+	maxLookupLimit        = 25
+	maxImportTitleLength  = 255
+	maxImportedGenreCount = 20
+)
+
+var lookupItemTypes = map[string]bool{
+	metadata.TypeBooks:  true,
+	metadata.TypeMovies: true,
+	metadata.TypeSeries: true,
+	metadata.TypeAnime:  true,
+}
+
+type metadataService struct {
+	registry      *metadata.Registry
+	metadataRepo  repository.Metadata
+	duplicateRepo repository.Duplicate
+	// Кеш ответов провайдеров по запросу, чтобы не упираться в их лимиты при повторном поиске
+	lookupCache *in_memory_cache.Cache[[]metadata.Result]
+	events      *eventPublisher
+	logger      *zap.SugaredLogger
+}
+
+func NewMetadataService(registry *metadata.Registry, metadataRepo repository.Metadata, duplicateRepo repository.Duplicate, events *eventPublisher, logger *zap.SugaredLogger) *metadataService {
+	if registry == nil {
+		registry = metadata.NewRegistry()
+	}
+	return &metadataService{
+		registry:      registry,
+		metadataRepo:  metadataRepo,
+		duplicateRepo: duplicateRepo,
+		lookupCache:   in_memory_cache.NewMemoryCache[[]metadata.Result](lookupCacheTTL),
+		events:        events,
+		logger:        logger,
+	}
+}
+
+// Lookup опрашивает подходящих провайдеров параллельно. Ошибка провайдера попадает в Errors
+// и не мешает вернуть результаты остальных. Уже импортированные записи помечаются ID элемента
+func (s *metadataService) Lookup(ctx context.Context, query models.LookupQuery) (*models.LookupResponse, error) {
+	query.Text = strings.TrimSpace(query.Text)
+	query.ISBN = strings.TrimSpace(query.ISBN)
+	if query.Text == "" && query.ISBN == "" {
+		return nil, ErrLookupQueryEmpty
+	}
+	if len([]rune(query.Text)) > maxLookupQueryLength {
+		return nil, fmt.Errorf("%w: query is too long", ErrLookupQueryEmpty)
+	}
+	if query.Type != "" && !lookupItemTypes[query.Type] {
+		return nil, ErrLookupInvalidType
+	}
+	if query.Limit <= 0 {
+		query.Limit = defaultLookupLimit
+	}
+	if query.Limit > maxLookupLimit {
+		query.Limit = maxLookupLimit
+	}
+	// ISBN есть только у книг
+	if query.ISBN != "" && query.Type == "" {
+		query.Type = metadata.TypeBooks
+	}
+
+	providers := s.registry.ForType(query.Type)
+	if query.Provider != "" {
+		provider, ok := s.registry.Get(query.Provider)
+		if !ok {
+			return nil, ErrUnknownProvider
+		}
+		providers = []metadata.Provider{provider}
+	}
+
+	providerResults := make([][]metadata.Result, len(providers))
+	providerErrors := make([]error, len(providers))
+	var wg sync.WaitGroup
+	for i, provider := range providers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			providerResults[i], providerErrors[i] = s.search(ctx, provider, query)
+		}()
+	}
+	wg.Wait()
+
+	response := &models.LookupResponse{Results: []models.LookupResult{}}
+	for i, provider := range providers {
+		if err := providerErrors[i]; err != nil {
+			s.logger.Warnf("Metadata lookup in %s failed: %v", provider.Name(), err)
+			if response.Errors == nil {
+				response.Errors = map[string]string{}
+			}
+			response.Errors[provider.Name()] = err.Error()
+			continue
+		}
+
+		results := providerResults[i]
+		if len(results) == 0 {
+			continue
+		}
+		externalIDs := make([]string, len(results))
+		for j, result := range results {
+			externalIDs[j] = result.ExternalID
+		}
+		existing, err := s.metadataRepo.GetItemIDsByExternalIDs(provider.Name(), externalIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range results {
+			lookupResult := models.LookupResult{Result: result}
+			if itemID, ok := existing[result.ExternalID]; ok {
+				lookupResult.ExistingItemID = &itemID
+			}
+			response.Results = append(response.Results, lookupResult)
+		}
+	}
+	return response, nil
+}
+
+func (s *metadataService) search(ctx context.Context, provider metadata.Provider, query models.LookupQuery) ([]metadata.Result, error) {
+	cacheKey := strings.Join([]string{provider.Name(), query.Type, strings.ToLower(query.Text), query.ISBN, fmt.Sprint(query.Limit)}, "\x00")
+	if cached, err := s.lookupCache.Get(cacheKey); err == nil {
+		return *cached, nil
+	}
+
+	results, err := provider.Search(ctx, metadata.Query{
+		Text:  query.Text,
+		ISBN:  query.ISBN,
+		Type:  query.Type,
+		Limit: query.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Провайдер с несколькими типами может вернуть записи другого типа
+	if query.Type != "" {
+		filtered := results[:0]
+		for _, result := range results {
+			if result.Type == query.Type {
+				filtered = append(filtered, result)
+			}
+		}
+		results = filtered
+	}
+	s.lookupCache.Set(cacheKey, results)
+	return results, nil
+}
+
+// Import создает публичный элемент каталога по записи провайдера. Повторный импорт той же записи
+// возвращает уже созданный элемент
+func (s *metadataService) Import(ctx context.Context, userID int, providerName string, externalID string) (*models.ItemImportResult, error) {
+	provider, ok := s.registry.Get(providerName)
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+	externalID = strings.TrimSpace(externalID)
+	if externalID == "" {
+		return nil, ErrInvalidExternalID
+	}
+
+	if result, err := s.existingImport(providerName, externalID); result != nil || err != nil {
+		return result, err
+	}
+
+	data, err := provider.Get(ctx, externalID)
+	switch {
+	case errors.Is(err, metadata.ErrNotFound):
+		return nil, ErrExternalItemNotFound
+	case errors.Is(err, metadata.ErrInvalidExternalID):
+		return nil, ErrInvalidExternalID
+	case err != nil:
+		s.logger.Warnf("Failed to get %s/%s: %v", providerName, externalID, err)
+		return nil, fmt.Errorf("%w: %s", ErrProviderUnavailable, providerName)
+	}
+	if !lookupItemTypes[data.Type] || strings.TrimSpace(data.Title) == "" {
+		s.logger.Warnf("%s returned incomplete metadata for %s", providerName, externalID)
+		return nil, fmt.Errorf("%w: incomplete metadata from %s", ErrProviderUnavailable, providerName)
+	}
+
+	item := importedItem(data, userID)
+
+	// Поиск дубликатов не должен мешать импорту: клиент может слить элементы позже
+	candidates, err := s.duplicateRepo.FindDuplicates(item, userID, duplicateCandidatesLimit)
+	if err != nil {
+		s.logger.Warnf("Failed to find duplicates for imported %q: %v", item.Title, err)
+		candidates = []models.DuplicateCandidate{}
+	}
+
+	genres := data.Genres
+	if len(genres) > maxImportedGenreCount {
+		genres = genres[:maxImportedGenreCount]
+	}
+	itemID, err := s.metadataRepo.ImportItem(item, models.ExternalID{
+		Provider:   providerName,
+		ExternalID: data.ExternalID,
+		ImportedBy: &userID,
+	}, genres)
+	if errors.Is(err, repository.ErrAlreadyExists) {
+		// Ту же запись успели импортировать параллельно
+		if result, err := s.existingImport(providerName, data.ExternalID); result != nil || err != nil {
+			return result, err
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.events.itemsChanged()
+	s.logger.Infof("User %d imported %s/%s as item %s", userID, providerName, data.ExternalID, itemID)
+	return &models.ItemImportResult{
+		ItemID:             itemID,
+		Created:            true,
+		PossibleDuplicates: candidates,
+	}, nil
+}
+
+func (s *metadataService) existingImport(provider string, externalID string) (*models.ItemImportResult, error) {
+	itemID, err := s.metadataRepo.GetItemIDByExternalID(provider, externalID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &models.ItemImportResult{
+		ItemID:             itemID,
+		Created:            false,
+		PossibleDuplicates: []models.DuplicateCandidate{},
+	}, nil
+}
+
+func (s *metadataService) GetItemExternalIDs(itemID string) ([]models.ExternalID, error) {
+	return s.metadataRepo.GetExternalIDs(itemID)
+}
+
+// importedItem переносит метаданные в поля элемента. Данные внешнего каталога считаются проверенными,
+// поэтому элемент сразу публикуется
+func importedItem(data *metadata.Result, userID int) *models.CollectionItem {
+	item := &models.CollectionItem{
+		Type:             data.Type,
+		Title:            truncateRunes(strings.TrimSpace(data.Title), maxImportTitleLength),
+		ReleaseYear:      data.ReleaseYear,
+		IsCustom:         false,
+		IsPublic:         true,
+		CreatorID:        &userID,
+		ModerationStatus: models.ModerationStatusApproved,
+	}
+	if item.ReleaseYear != nil && (*item.ReleaseYear < minReleaseYear || *item.ReleaseYear > maxReleaseYear) {
+		item.ReleaseYear = nil
+	}
+
+	description := strings.TrimSpace(data.Description)
+	if len(data.Authors) > 0 {
+		authors := "Авторы: " + strings.Join(data.Authors, ", ")
+		if description == "" {
+			description = authors
+		} else {
+			description = authors + "\n\n" + description
+		}
+	}
+	item.Description = description
+	if data.CoverURL != "" {
+		cover := data.CoverURL
+		item.CoverImage = &cover
+	}
+	return item
+}
+
+func truncateRunes(value string, limit int) string {
+	runes := []rune(value)
+	if len(runes) <= limit {
+		return value
+	}
+	return string(runes[:limit])
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/repository"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrItemNotPending        = errors.New("item is not pending review")
+	ErrItemAlreadySubmitted  = errors.New("item is already pending review or published")
+	ErrModerationReasonEmpty = errors.New("reason is required")
+	ErrItemNotEditable       = errors.New("item can be edited only before submission or after review")
+)
+
+// Из каких статусов автор может отправить элемент на проверку
+var submittableStatuses = map[string]bool{
+	models.ModerationStatusNone:             true,
+	models.ModerationStatusRejected:         true,
+	models.ModerationStatusChangesRequested: true,
+}
+
+type moderationService struct {
+	moderationRepo repository.Moderation
+	itemRepo       repository.CollectionItem
+	userRepo       repository.UserRepository
+	events         *eventPublisher
+	logger         *zap.SugaredLogger
+}
+
+func NewModerationService(moderationRepo repository.Moderation, itemRepo repository.CollectionItem, userRepo repository.UserRepository, events *eventPublisher, logger *zap.SugaredLogger) *moderationService {
+	return &moderationService{
+		moderationRepo: moderationRepo,
+		itemRepo:       itemRepo,
+		userRepo:       userRepo,
+		events:         events,
+		logger:         logger,
+	}
+}
+
+func (s *moderationService) GetQueue(userID int, pagination pagination.PaginationRequest) (*pagination.PaginatedResponse[models.CollectionItem], error) {
+	if err := requireModerator(s.userRepo, userID); err != nil {
+		return nil, err
+	}
+	return s.moderationRepo.GetQueue(pagination)
+}
+
+// SubmitItem - автор отправляет свой элемент на проверку для публикации в каталоге
+func (s *moderationService) SubmitItem(userID int, itemID string) error {
+	item, err := s.getItem(itemID)
+	if err != nil {
+		return err
+	}
+	if item.CreatorID == nil || *item.CreatorID != userID {
+		return ErrNotItemCreator
+	}
+	if !submittableStatuses[item.ModerationStatus] {
+		return ErrItemAlreadySubmitted
+	}
+
+	err = s.moderationRepo.SubmitItem(itemID, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrItemAlreadySubmitted
+	}
+	return err
+}
+
+// UpdateItem - автор меняет свой элемент до отправки на проверку, после отклонения или запроса правок,
+// затем может снова отправить его через SubmitItem
+func (s *moderationService) UpdateItem(userID int, itemID string, changes models.ItemChanges) (*models.CollectionItem, error) {
+	item, err := s.getItem(itemID)
+	if err != nil {
+		return nil, err
+	}
+	if item.CreatorID == nil || *item.CreatorID != userID {
+		return nil, ErrNotItemCreator
+	}
+	if item.IsPublic || !submittableStatuses[item.ModerationStatus] {
+		return nil, ErrItemNotEditable
+	}
+
+	changes, err = normalizeItemChanges(changes)
+	if err != nil {
+		return nil, err
+	}
+	err = s.moderationRepo.UpdateDraftItem(itemID, userID, changes.ToMap())
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrItemNotEditable
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.events.itemsChanged()
+	return s.getItem(itemID)
+}
+
+func (s *moderationService) ApproveItem(userID int, itemID string) (*models.ModerationDecision, error) {
+	return s.decide(userID, itemID, models.ModerationDecisionApproved, nil)
+}
+
+func (s *moderationService) RejectItem(userID int, itemID string, reason string) (*models.ModerationDecision, error) {
+	return s.decideWithReason(userID, itemID, models.ModerationDecisionRejected, reason)
+}
+
+func (s *moderationService) RequestChanges(userID int, itemID string, reason string) (*models.ModerationDecision, error) {
+	return s.decideWithReason(userID, itemID, models.ModerationDecisionChangesRequested, reason)
+}
+
+// GetItemHistory - журнал модерации доступен автору элемента и модераторам
+func (s *moderationService) GetItemHistory(userID int, itemID string) ([]models.ModerationDecision, error) {
+	item, err := s.getItem(itemID)
+	if err != nil {
+		return nil, err
+	}
+	if item.CreatorID == nil || *item.CreatorID != userID {
+		if err := requireModerator(s.userRepo, userID); err != nil {
+			return nil, err
+		}
+	}
+	return s.moderationRepo.GetDecisions(itemID)
+}
+
+// decideWithReason - при отклонении и запросе правок автору нужна причина
+func (s *moderationService) decideWithReason(userID int, itemID string, decision string, reason string) (*models.ModerationDecision, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrModerationReasonEmpty
+	}
+	return s.decide(userID, itemID, decision, &reason)
+}
+
+func (s *moderationService) decide(userID int, itemID string, decision string, reason *string) (*models.ModerationDecision, error) {
+	if err := requireModerator(s.userRepo, userID); err != nil {
+		return nil, err
+	}
+	item, err := s.getItem(itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := s.moderationRepo.DecideItem(itemID, userID, decision, reason)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrItemNotPending
+	}
+	if err != nil {
+		return nil, err
+	}
+	if decision == models.ModerationDecisionApproved {
+		s.events.itemsChanged()
+	}
+	if item.CreatorID != nil {
+		s.events.notificationsChanged(*item.CreatorID)
+	}
+	return record, nil
+}
+
+func (s *moderationService) getItem(itemID string) (*models.CollectionItem, error) {
+	item, err := s.itemRepo.GetItemByID(itemID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrItemNotFound
+		}
+		return nil, err
+	}
+	return item, nil
+}
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/repository"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/notify"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrNotificationNotFound           = errors.New("notification not found")
+	ErrUnknownNotificationType        = errors.New("unknown notification type")
+	ErrUnsupportedNotificationChannel = errors.New("notification channel is not available")
+	errNotificationChannelMissing     = errors.New("notification channel is not configured")
+)
+
+const (
+	defaultDeliveryInterval    = 30 * time.Second
+	defaultDeliveryBatchSize   = 50
+	defaultDeliveryMaxAttempts = 5
+	// deliveryLease - на сколько откладывается забранная отправка, должно хватать на одну отправку
+	deliveryLease = 5 * time.Minute
+	// Пауза перед повтором удваивается с каждой попыткой: 1м, 2м, 4м... но не больше 6ч
+	deliveryRetryBase = time.Minute
+	deliveryRetryMax  = 6 * time.Hour
+)
+
+// NotificationConfig - доставка уведомлений во внешние каналы
+type NotificationConfig struct {
+	// Channels - подключенные каналы доставки, например email. В приложении уведомления видны всегда
+	Channels *notify.Registry
+	// Interval - как часто обработчик проверяет очередь отправок
+	Interval time.Duration
+	// BatchSize - сколько отправок забирается за один проход
+	BatchSize int
+	// MaxAttempts - после стольких неудачных попыток отправка считается проваленной
+	MaxAttempts int
+}
+
+type notificationService struct {
+	notificationRepo repository.Notification
+	cfg              NotificationConfig
+	events           *eventPublisher
+	logger           *zap.SugaredLogger
+}
+
+func NewNotificationService(notificationRepo repository.Notification, cfg NotificationConfig, events *eventPublisher, logger *zap.SugaredLogger) *notificationService {
+	if cfg.Channels == nil {
+		cfg.Channels = notify.NewRegistry()
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultDeliveryInterval
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultDeliveryBatchSize
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultDeliveryMaxAttempts
+	}
+	return &notificationService{
+		notificationRepo: notificationRepo,
+		cfg:              cfg,
+		events:           events,
+		logger:           logger,
+	}
+}
+
+func (s *notificationService) GetNotifications(userID int, unreadOnly bool, pagination pagination.PaginationRequest) (*pagination.PaginatedResponse[models.Notification], error) {
+	return s.notificationRepo.GetNotifications(userID, unreadOnly, pagination)
+}
+
+func (s *notificationService) GetUnreadCount(userID int) (*models.UnreadNotifications, error) {
+	count, err := s.notificationRepo.CountUnread(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.UnreadNotifications{Count: count}, nil
+}
+
+func (s *notificationService) MarkRead(userID int, notificationID int) error {
+	err := s.notificationRepo.MarkRead(userID, notificationID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrNotificationNotFound
+	}
+	if err != nil {
+		return err
+	}
+	// Счетчик обновится и в других вкладках и на других устройствах пользователя
+	s.events.notificationsChanged(userID)
+	return nil
+}
+
+// MarkAllRead отмечает прочитанными все уведомления и возвращает, сколько было непрочитано
+func (s *notificationService) MarkAllRead(userID int) (int64, error) {
+	marked, err := s.notificationRepo.MarkAllRead(userID)
+	if err != nil {
+		return 0, err
+	}
+	if marked > 0 {
+		s.events.notificationsChanged(userID)
+	}
+	return marked, nil
+}
+
+// GetPreferences - каналы уведомлений всех типов, для типов без настройки - in_app
+func (s *notificationService) GetPreferences(userID int) ([]models.NotificationPreference, error) {
+	stored, err := s.notificationRepo.GetPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+	preferences := make([]models.NotificationPreference, 0, len(models.NotificationTypes))
+	for _, notificationType := range models.NotificationTypes {
+		channel, ok := stored[notificationType]
+		if !ok {
+			channel = models.NotificationChannelInApp
+		}
+		preferences = append(preferences, models.NotificationPreference{Type: notificationType, Channel: channel})
+	}
+	return preferences, nil
+}
+
+// SetPreferences меняет каналы перечисленных типов, остальные не трогает
+func (s *notificationService) SetPreferences(userID int, preferences []models.NotificationPreference) ([]models.NotificationPreference, error) {
+	for _, preference := range preferences {
+		if !slices.Contains(models.NotificationTypes, preference.Type) {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownNotificationType, preference.Type)
+		}
+		if !s.channelAvailable(preference.Channel) {
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedNotificationChannel, preference.Channel)
+		}
+	}
+	if err := s.notificationRepo.SetPreferences(userID, preferences); err != nil {
+		return nil, err
+	}
+	return s.GetPreferences(userID)
+}
+
+// Channels - значения, которые можно выбрать в настройках: in_app, off и подключенные каналы
+func (s *notificationService) Channels() []string {
+	return append([]string{models.NotificationChannelInApp, models.NotificationChannelOff}, s.cfg.Channels.Names()...)
+}
+
+func (s *notificationService) channelAvailable(channel string) bool {
+	return slices.Contains(s.Channels(), channel)
+}
+
+// RunDeliveries - фоновый обработчик очереди отправок во внешние каналы, работает, пока не отменен ctx
+func (s *notificationService) RunDeliveries(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		for ctx.Err() == nil && s.deliverBatch(ctx) {
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliverBatch отправляет одну пачку. Возвращает true, если пачка была полной и очередь стоит проверить сразу
+func (s *notificationService) deliverBatch(ctx context.Context) bool {
+	deliveries, err := s.notificationRepo.ClaimDeliveries(s.cfg.BatchSize, deliveryLease)
+	if err != nil {
+		s.logger.Errorf("Failed to claim notification deliveries: %v", err)
+		return false
+	}
+	for _, delivery := range deliveries {
+		sendErr := s.deliver(ctx, delivery)
+		var retryAt *time.Time
+		if sendErr != nil {
+			s.logger.Warnf("Notification %d delivery via %s failed (attempt %d): %v",
+				delivery.Notification.ID, delivery.Channel, delivery.Attempts, sendErr)
+			if delivery.Attempts < s.cfg.MaxAttempts && !errors.Is(sendErr, errNotificationChannelMissing) && !errors.Is(sendErr, notify.ErrNoEmail) {
+				next := time.Now().Add(deliveryBackoff(delivery.Attempts))
+				retryAt = &next
+			}
+		}
+		if err := s.notificationRepo.FinishDelivery(delivery.ID, sendErr, retryAt); err != nil {
+			s.logger.Errorf("Failed to save notification delivery %d: %v", delivery.ID, err)
+		}
+	}
+	return len(deliveries) == s.cfg.BatchSize
+}
+
+func (s *notificationService) deliver(ctx context.Context, delivery models.NotificationDelivery) error {
+	channel, ok := s.cfg.Channels.Get(delivery.Channel)
+	if !ok {
+		return fmt.Errorf("%w: %s", errNotificationChannelMissing, delivery.Channel)
+	}
+	subject, text := renderNotification(delivery.Notification)
+	return channel.Send(ctx, notify.Message{
+		To: notify.Recipient{
+			UserID: delivery.Notification.UserID,
+			Name:   delivery.RecipientName,
+			Email:  delivery.RecipientEmail,
+		},
+		Type:      delivery.Notification.Type,
+		Subject:   subject,
+		Text:      text,
+		CreatedAt: delivery.Notification.CreatedAt,
+	})
+}
+
+func deliveryBackoff(attempts int) time.Duration {
+	backoff := deliveryRetryBase
+	for i := 1; i < attempts && backoff < deliveryRetryMax; i++ {
+		backoff *= 2
+	}
+	return min(backoff, deliveryRetryMax)
+}
+
+// renderNotification - тема и текст уведомления для внешних каналов
+func renderNotification(n models.Notification) (subject string, text string) {
+	switch n.Type {
+	case models.NotificationModerationDecision:
+		var p models.ModerationNotificationPayload
+		json.Unmarshal(n.Payload, &p)
+		subject = fmt.Sprintf("Модерация: «%s»", p.ItemTitle)
+		text = fmt.Sprintf("Решение модератора по элементу «%s»: %s.", p.ItemTitle, p.Decision)
+		if p.Reason != nil && *p.Reason != "" {
+			text += "\nПричина: " + *p.Reason
+		}
+	case models.NotificationEditReviewed:
+		var p models.EditReviewNotificationPayload
+		json.Unmarshal(n.Payload, &p)
+		subject = "Ваша правка рассмотрена"
+		text = fmt.Sprintf("Правка #%d: %s.", p.SuggestionID, p.Status)
+		if p.ReviewComment != nil && *p.ReviewComment != "" {
+			text += "\nКомментарий: " + *p.ReviewComment
+		}
+	case models.NotificationImportFinished:
+		var p models.ImportFinishedNotificationPayload
+		json.Unmarshal(n.Payload, &p)
+		subject = "Импорт завершен"
+		text = fmt.Sprintf("Импорт из %s: %s. Найдено в каталоге: %d, создано: %d, не распознано: %d, ошибок: %d.",
+			p.Format, p.Status, p.MatchedRows, p.CreatedRows, p.UnmatchedRows, p.FailedRows)
+	case models.NotificationNewFollower, models.NotificationFollowRequest, models.NotificationFollowAccepted:
+		var p models.FollowNotificationPayload
+		json.Unmarshal(n.Payload, &p)
+		switch n.Type {
+		case models.NotificationNewFollower:
+			subject = "Новый подписчик"
+			text = "У вас новый подписчик: " + p.UserName + "."
+		case models.NotificationFollowRequest:
+			subject = "Запрос на подписку"
+			text = "Запрос на подписку от " + p.UserName + "."
+		default:
+			subject = "Запрос на подписку принят"
+			text = p.UserName + ": ваш запрос на подписку принят."
+		}
+	case models.NotificationCollectionComment, models.NotificationCommentReply:
+		var p models.CommentNotificationPayload
+		json.Unmarshal(n.Payload, &p)
+		if n.Type == models.NotificationCommentReply {
+			subject = "Ответ на ваш комментарий"
+			text = fmt.Sprintf("Новый ответ на ваш комментарий к коллекции «%s» от %s.", p.CollectionName, p.UserName)
+		} else {
+			subject = fmt.Sprintf("Комментарий к коллекции «%s»", p.CollectionName)
+			text = fmt.Sprintf("Новый комментарий к вашей коллекции «%s» от %s.", p.CollectionName, p.UserName)
+		}
+	default:
+		subject = "Новое уведомление"
+		text = "У вас новое уведомление в Memoria."
+	}
+	return subject, text
+}
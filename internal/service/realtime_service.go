@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/repository"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/in_memory_cache"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/realtime"
+	"go.uber.org/zap"
+)
+
+// MaxWatchedCollections - за сколькими коллекциями можно следить в одном подключении
+const MaxWatchedCollections = 20
+
+const (
+	publishTimeout = 5 * time.Second
+	// listenRetryInterval - пауза перед повторным подключением к брокеру после ошибки
+	listenRetryInterval = 5 * time.Second
+)
+
+var ErrTooManyWatchedCollections = fmt.Errorf("at most %d collections can be watched", MaxWatchedCollections)
+
+type realtimeService struct {
+	broker         realtime.Broker
+	hub            *realtime.Hub
+	collectionRepo repository.Collection
+	logger         *zap.SugaredLogger
+}
+
+func NewRealtimeService(broker realtime.Broker, collectionRepo repository.Collection, logger *zap.SugaredLogger) *realtimeService {
+	return &realtimeService{
+		broker:         broker,
+		hub:            realtime.NewHub(realtime.DefaultSubscriptionBuffer),
+		collectionRepo: collectionRepo,
+		logger:         logger,
+	}
+}
+
+// Subscribe подключает клиента к событиям пользователя. Кроме своих событий клиент может следить
+// за чужими публичными коллекциями collectionIDs
+func (s *realtimeService) Subscribe(userID int, collectionIDs []string) (*realtime.Subscription, error) {
+	collectionIDs = unique(collectionIDs)
+	if len(collectionIDs) > MaxWatchedCollections {
+		return nil, ErrTooManyWatchedCollections
+	}
+	watched := make([]string, 0, len(collectionIDs))
+	for _, collectionID := range collectionIDs {
+		collection, err := s.collectionRepo.GetCollectionByID(collectionID)
+		if err != nil {
+			return nil, ErrCollectionNotFound
+		}
+		if collection.UserID == userID {
+			// События своих коллекций владелец получает и так
+			continue
+		}
+		if !collection.IsPublic {
+			return nil, ErrCollectionPrivate
+		}
+		watched = append(watched, collectionID)
+	}
+	return s.hub.Subscribe(userID, watched), nil
+}
+
+// RunRealtime раздает события брокера подключениям этого экземпляра, работает, пока не отменен ctx
+func (s *realtimeService) RunRealtime(ctx context.Context) {
+	for {
+		err := s.broker.Listen(ctx, s.hub.Dispatch)
+		if ctx.Err() != nil {
+			return
+		}
+		s.logger.Errorf("Realtime broker stopped, reconnecting in %s: %v", listenRetryInterval, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetryInterval):
+		}
+	}
+}
+
+// eventPublisher публикует события для клиентов /stream и сбрасывает кеши, зависящие от элементов каталога.
+// Ошибки публикации только логируются: событие - подсказка клиенту обновить данные, из-за него не должна падать сама операция
+type eventPublisher struct {
+	broker           realtime.Broker
+	notificationRepo repository.Notification
+	// suggestCache - кеш подсказок автодополнения, общий с collectionItemService
+	suggestCache *in_memory_cache.Cache[[]models.ItemSuggestion]
+	logger       *zap.SugaredLogger
+}
+
+func newEventPublisher(broker realtime.Broker, notificationRepo repository.Notification, suggestCache *in_memory_cache.Cache[[]models.ItemSuggestion], logger *zap.SugaredLogger) *eventPublisher {
+	return &eventPublisher{
+		broker:           broker,
+		notificationRepo: notificationRepo,
+		suggestCache:     suggestCache,
+		logger:           logger,
+	}
+}
+
+// itemsChanged вызывается после создания и удаления элементов и изменения их названия, обложки или видимости:
+// подсказки автодополнения пересобираются при следующем запросе
+func (p *eventPublisher) itemsChanged() {
+	p.suggestCache.Clear()
+}
+
+// notificationsChanged сообщает пользователям новое число непрочитанных уведомлений
+func (p *eventPublisher) notificationsChanged(userIDs ...int) {
+	for _, userID := range unique(userIDs) {
+		count, err := p.notificationRepo.CountUnread(userID)
+		if err != nil {
+			p.logger.Warnf("Failed to count unread notifications of user %d: %v", userID, err)
+			continue
+		}
+		p.publish(realtime.Event{Type: models.EventNotificationsUnread, UserID: userID}, models.UnreadNotifications{Count: count})
+	}
+}
+
+// collectionChanged сообщает об изменении коллекции владельцу и, если коллекция публичная, тем, кто за ней следит
+func (p *eventPublisher) collectionChanged(collection *models.Collection, eventType string, data any) {
+	p.publish(realtime.Event{
+		Type:         eventType,
+		UserID:       collection.UserID,
+		CollectionID: collection.ID,
+		Private:      !collection.IsPublic,
+	}, data)
+}
+
+func (p *eventPublisher) importProgress(job *models.ImportJob) {
+	p.publish(realtime.Event{Type: models.EventImportProgress, UserID: job.UserID}, job)
+}
+
+func (p *eventPublisher) publish(event realtime.Event, data any) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		p.logger.Errorf("Failed to encode %s event: %v", event.Type, err)
+		return
+	}
+	event.Data = payload
+
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+	if err := p.broker.Publish(ctx, event); err != nil {
+		p.logger.Warnf("Failed to publish %s event for user %d: %v", event.Type, event.UserID, err)
+	}
+}
+
+// unique убирает повторы, сохраняя порядок
+func unique[T comparable](values []T) []T {
+	result := make([]T, 0, len(values))
+	for _, value := range values {
+		if !slices.Contains(result, value) {
+			result = append(result, value)
+		}
+	}
+	return result
+}
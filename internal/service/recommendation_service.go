@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/repository"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultRecommendationInterval    = 6 * time.Hour
+	defaultRecommendationUserHistory = 200
+	defaultRecommendationMinCo       = 2
+	defaultRecommendationShrinkage   = 10
+	defaultRecommendationPerItem     = 50
+	defaultRecommendationLimit       = 20
+	maxRecommendationLimit           = 50
+)
+
+// RecommendationConfig - пересчет похожих элементов по совместной встречаемости в библиотеках
+type RecommendationConfig struct {
+	Enabled bool
+	// Interval - как часто пересчитываются похожие элементы
+	Interval time.Duration
+	// UserHistory - сколько последних элементов каждого пользователя учитывается
+	UserHistory int
+	// MinCoOccurrences - минимум пользователей, у которых есть оба элемента, чтобы считать их похожими
+	MinCoOccurrences int
+	// Shrinkage - чем больше, тем сильнее штрафуются пары, встречающиеся у немногих пользователей
+	Shrinkage float64
+	// PerItem - сколько похожих элементов хранится для каждого элемента
+	PerItem int
+}
+
+type recommendationService struct {
+	recommendationRepo repository.Recommendation
+	itemRepo           repository.CollectionItem
+	cfg                RecommendationConfig
+	logger             *zap.SugaredLogger
+}
+
+func NewRecommendationService(recommendationRepo repository.Recommendation, itemRepo repository.CollectionItem, cfg RecommendationConfig, logger *zap.SugaredLogger) *recommendationService {
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultRecommendationInterval
+	}
+	if cfg.UserHistory <= 0 {
+		cfg.UserHistory = defaultRecommendationUserHistory
+	}
+	if cfg.MinCoOccurrences <= 0 {
+		cfg.MinCoOccurrences = defaultRecommendationMinCo
+	}
+	if cfg.Shrinkage <= 0 {
+		cfg.Shrinkage = defaultRecommendationShrinkage
+	}
+	if cfg.PerItem <= 0 {
+		cfg.PerItem = defaultRecommendationPerItem
+	}
+	return &recommendationService{
+		recommendationRepo: recommendationRepo,
+		itemRepo:           itemRepo,
+		cfg:                cfg,
+		logger:             logger,
+	}
+}
+
+// GetSimilarItems - элементы, которые часто встречаются в библиотеках вместе с itemID
+func (s *recommendationService) GetSimilarItems(userID int, itemID string, excludeLibrary bool, limit int) ([]models.SimilarItem, error) {
+	item, err := s.itemRepo.GetItemByID(itemID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrItemNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !item.IsPublic && (item.CreatorID == nil || *item.CreatorID != userID) {
+		return nil, ErrItemAccessDenied
+	}
+	return s.recommendationRepo.GetSimilarItems(itemID, userID, excludeLibrary, recommendationLimit(limit))
+}
+
+// GetRecommendations - рекомендации по библиотеке пользователя, itemType ограничивает тип элементов
+func (s *recommendationService) GetRecommendations(userID int, itemType string, limit int) ([]models.RecommendedItem, error) {
+	if itemType != "" && !lookupItemTypes[itemType] {
+		return nil, fmt.Errorf("%w: unknown type %q", ErrInvalidItemFilter, itemType)
+	}
+	return s.recommendationRepo.GetRecommendations(userID, itemType, s.cfg.UserHistory, recommendationLimit(limit))
+}
+
+func recommendationLimit(limit int) int {
+	if limit < 1 {
+		return defaultRecommendationLimit
+	}
+	return min(limit, maxRecommendationLimit)
+}
+
+// RunRecommendations - фоновый пересчет похожих элементов раз в Interval, пока не отменен ctx
+func (s *recommendationService) RunRecommendations(ctx context.Context) {
+	if !s.cfg.Enabled {
+		return
+	}
+	s.logger.Infof("Recommendations started: every %s", s.cfg.Interval)
+
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		if err := s.recomputeIfStale(); err != nil && ctx.Err() == nil {
+			s.logger.Errorf("Item similarities recompute failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// recomputeIfStale пересчитывает похожие элементы, если их не пересчитывал недавно другой экземпляр
+// или предыдущий запуск приложения
+func (s *recommendationService) recomputeIfStale() error {
+	computedAt, err := s.recommendationRepo.GetSimilaritiesComputedAt()
+	if err != nil {
+		return err
+	}
+	if computedAt != nil && time.Since(*computedAt) < s.cfg.Interval/2 {
+		return nil
+	}
+
+	started := time.Now()
+	pairs, ran, err := s.recommendationRepo.RecomputeItemSimilarities(models.SimilarityParams{
+		UserHistory:      s.cfg.UserHistory,
+		MinCoOccurrences: s.cfg.MinCoOccurrences,
+		Shrinkage:        s.cfg.Shrinkage,
+		PerItem:          s.cfg.PerItem,
+	})
+	if err != nil {
+		return err
+	}
+	if ran {
+		s.logger.Infof("Item similarities recomputed: %d pairs in %s", pairs, time.Since(started).Round(time.Millisecond))
+	}
+	return nil
+}
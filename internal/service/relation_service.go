@@ -0,0 +1,214 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/repository"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultRelationGraphDepth = 1
+	maxRelationGraphDepth     = 5
+)
+
+var (
+	ErrRelationNotFound       = errors.New("relation not found")
+	ErrRelationAlreadyExists  = errors.New("relation already exists")
+	ErrInvalidRelationType    = errors.New("invalid relation type")
+	ErrSelfRelation           = errors.New("item can't be related to itself")
+	ErrFranchiseNotFound      = errors.New("franchise not found")
+	ErrFranchiseExists        = errors.New("franchise already exists")
+	ErrItemAlreadyInFranchise = errors.New("item already in franchise")
+	ErrModeratorRequired      = errors.New("moderator or admin role required")
+)
+
+type relationService struct {
+	relationRepo repository.Relation
+	itemRepo     repository.CollectionItem
+	userRepo     repository.UserRepository
+	logger       *zap.SugaredLogger
+}
+
+func NewRelationService(relationRepo repository.Relation, itemRepo repository.CollectionItem, userRepo repository.UserRepository, logger *zap.SugaredLogger) *relationService {
+	return &relationService{
+		relationRepo: relationRepo,
+		itemRepo:     itemRepo,
+		userRepo:     userRepo,
+		logger:       logger,
+	}
+}
+
+// CreateRelation - связи с публичными элементами курируют модераторы,
+// связывать между собой свои приватные элементы может их создатель
+func (s *relationService) CreateRelation(userID int, relation *models.ItemRelation) (*models.ItemRelation, error) {
+	if !models.RelationTypes[relation.RelationType] {
+		return nil, ErrInvalidRelationType
+	}
+	if relation.SourceItemID == relation.TargetItemID {
+		return nil, ErrSelfRelation
+	}
+
+	source, err := s.getVisibleItem(relation.SourceItemID, userID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.getVisibleItem(relation.TargetItemID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if source.IsPublic || target.IsPublic {
+		if err := requireModerator(s.userRepo, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	relation.CreatedBy = &userID
+	created, err := s.relationRepo.CreateRelation(relation)
+	if errors.Is(err, repository.ErrAlreadyExists) {
+		return nil, ErrRelationAlreadyExists
+	}
+	return created, err
+}
+
+func (s *relationService) DeleteRelation(userID int, itemID string, relationID int) error {
+	relation, err := s.relationRepo.GetRelationByID(relationID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrRelationNotFound
+		}
+		return err
+	}
+	if relation.SourceItemID != itemID && relation.TargetItemID != itemID {
+		return ErrRelationNotFound
+	}
+
+	source, err := s.getVisibleItem(relation.SourceItemID, userID)
+	if err != nil {
+		return err
+	}
+	target, err := s.getVisibleItem(relation.TargetItemID, userID)
+	if err != nil {
+		return err
+	}
+
+	if source.IsPublic || target.IsPublic {
+		if err := requireModerator(s.userRepo, userID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.relationRepo.DeleteRelation(relationID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrRelationNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *relationService) GetRelationGraph(userID int, itemID string, depth int) (*models.RelationGraph, error) {
+	if _, err := s.getVisibleItem(itemID, userID); err != nil {
+		return nil, err
+	}
+
+	if depth < 1 {
+		depth = defaultRelationGraphDepth
+	}
+	if depth > maxRelationGraphDepth {
+		depth = maxRelationGraphDepth
+	}
+
+	return s.relationRepo.GetRelationGraph(itemID, depth, userID)
+}
+
+func (s *relationService) CreateFranchise(userID int, franchise *models.Franchise) (*models.Franchise, error) {
+	if err := requireModerator(s.userRepo, userID); err != nil {
+		return nil, err
+	}
+
+	franchise.CreatedBy = &userID
+	created, err := s.relationRepo.CreateFranchise(franchise)
+	if errors.Is(err, repository.ErrAlreadyExists) {
+		return nil, ErrFranchiseExists
+	}
+	return created, err
+}
+
+func (s *relationService) GetFranchises() ([]models.Franchise, error) {
+	return s.relationRepo.GetFranchises()
+}
+
+func (s *relationService) GetFranchise(userID int, franchiseID string) (*models.Franchise, error) {
+	franchise, err := s.relationRepo.GetFranchiseByID(franchiseID, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrFranchiseNotFound
+	}
+	return franchise, err
+}
+
+func (s *relationService) AddItemToFranchise(userID int, franchiseID string, itemID string) error {
+	if err := requireModerator(s.userRepo, userID); err != nil {
+		return err
+	}
+	if _, err := s.getVisibleItem(itemID, userID); err != nil {
+		return err
+	}
+	if _, err := s.relationRepo.GetFranchiseByID(franchiseID, userID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrFranchiseNotFound
+		}
+		return err
+	}
+
+	err := s.relationRepo.AddItemToFranchise(franchiseID, itemID)
+	if errors.Is(err, repository.ErrAlreadyExists) {
+		return ErrItemAlreadyInFranchise
+	}
+	return err
+}
+
+func (s *relationService) RemoveItemFromFranchise(userID int, franchiseID string, itemID string) error {
+	if err := requireModerator(s.userRepo, userID); err != nil {
+		return err
+	}
+
+	err := s.relationRepo.RemoveItemFromFranchise(franchiseID, itemID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrItemNotFound
+	}
+	return err
+}
+
+func (s *relationService) getVisibleItem(itemID string, userID int) (*models.CollectionItem, error) {
+	item, err := s.itemRepo.GetItemByID(itemID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrItemNotFound
+		}
+		return nil, err
+	}
+	if !item.IsPublic && (item.CreatorID == nil || *item.CreatorID != userID) {
+		return nil, ErrItemAccessDenied
+	}
+	return item, nil
+}
+
+// isModeratorRole - модераторы и администраторы могут курировать публичный каталог
+func isModeratorRole(role string) bool {
+	return role == models.RoleModerator || role == models.RoleAdmin
+}
+
+// requireModerator возвращает ErrModeratorRequired, если пользователь не модератор и не администратор
+func requireModerator(userRepo repository.UserRepository, userID int) error {
+	role, err := userRepo.GetUserRole(userID)
+	if err != nil {
+		return err
+	}
+	if !isModeratorRole(role) {
+		return ErrModeratorRequired
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/repository"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
+	"go.uber.org/zap"
+)
+
+const (
+	minSearchQueryLength = 2
+	maxSearchQueryLength = 200
+)
+
+var ErrInvalidSearchQuery = errors.New("search query must be between 2 and 200 characters")
+
+type searchService struct {
+	repo   repository.Search
+	logger *zap.SugaredLogger
+}
+
+func NewSearchService(repo repository.Search, logger *zap.SugaredLogger) *searchService {
+	return &searchService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+func (s *searchService) SearchItems(query models.SearchQuery, pagination pagination.PaginationRequest) (*pagination.PaginatedResponse[models.SearchResult], error) {
+	query.Query = strings.Join(strings.Fields(query.Query), " ")
+
+	length := utf8.RuneCountInString(query.Query)
+	if length < minSearchQueryLength || length > maxSearchQueryLength {
+		return nil, ErrInvalidSearchQuery
+	}
+
+	return s.repo.SearchItems(query, pagination)
+}
@@ -1,9 +1,17 @@
 package service
 
 import (
+	"context"
+	"io"
+
 	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
 	"github.com/kefirchick13/memoria-collect-platform-golang/internal/repository"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/blobstore"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/exporter"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/in_memory_cache"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/metadata"
 	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/realtime"
 	"go.uber.org/zap"
 )
 
@@ -28,17 +36,204 @@ type UserService interface {
 type CollectionService interface {
 	CreateCollection(collection *models.Collection) (*models.Collection, error)
 	GetCollections(user_id int) ([]models.Collection, error)
-	GetCollectionsWithPagination(user_id int, pagination pagination.PaginationRequest) (*pagination.PaginatedResponse[models.Collection], error)
+	GetCollectionsWithPagination(user_id int, tags models.TagFilter, pagination pagination.PaginationRequest) (*pagination.PaginatedResponse[models.Collection], error)
+	GetCollectionsByCursor(user_id int, tags models.TagFilter, req pagination.CursorRequest) (*pagination.CursorResponse[models.Collection], error)
+	SetCollectionVisibility(userID int, collectionID string, isPublic bool) error
 }
 
 type CollectionItemService interface {
-	GetItemsByCurrentType(currentType string, pagination pagination.PaginationRequest) (*pagination.PaginatedResponse[models.CollectionItem], error)
-	GetItemsByCollection(collection_id string, user_id int) ([]models.CollectionItem, error)
+	GetItemsByCurrentType(filter models.ItemFilter, pagination pagination.PaginationRequest) (*models.ItemFeed, error)
+	GetItemsFeedByCursor(filter models.ItemFilter, req pagination.CursorRequest) (*pagination.CursorResponse[models.FeedItem], error)
+	GetItemsByCollection(collection_id string, user_id int, tags models.TagFilter) ([]models.CollectionItem, error)
 	GetItemByID(collection_item_id string) (*models.CollectionItem, error)
 	DeleteItem(collection_item_id string) error
 	UpdateItem(item *models.CollectionItem) error
 	AddItemToCollection(item_id string, collection_id string, user_review string, user_id int) (int, error)
-	CreateCollectionItem(item *models.CollectionItem) (string, error)
+	CreateCollectionItem(item *models.CollectionItem) (string, []models.DuplicateCandidate, error)
+	GetItem(userID int, itemID string) (*models.CollectionItem, string, error)
+	FindDuplicates(userID int, item *models.CollectionItem) ([]models.DuplicateCandidate, error)
+	MergeItems(userID int, canonicalID string, duplicateID string) (*models.ItemMergeResult, error)
+	SuggestItems(query string, itemType string, userID int, limit int) ([]models.ItemSuggestion, error)
+}
+
+type EpisodeService interface {
+	GetSeasons(itemID string, userID int) ([]models.Season, error)
+	CreateSeason(userID int, season *models.Season) (*models.Season, error)
+	AddEpisode(userID int, itemID string, seasonNumber int, episode *models.Episode) (string, error)
+	MarkEpisodeWatched(userID int, itemID string, episodeID string, watched bool) (*models.ItemProgress, error)
+	MarkSeasonWatched(userID int, itemID string, seasonNumber int, watched bool) (*models.ItemProgress, error)
+	GetItemProgress(userID int, itemID string) (*models.ItemProgress, error)
+	RateItem(userID int, itemID string, rating *int) (*models.ItemProgress, error)
+	GetNextEpisodes(userID int) ([]models.NextEpisode, error)
+}
+
+type RelationService interface {
+	CreateRelation(userID int, relation *models.ItemRelation) (*models.ItemRelation, error)
+	DeleteRelation(userID int, itemID string, relationID int) error
+	GetRelationGraph(userID int, itemID string, depth int) (*models.RelationGraph, error)
+	CreateFranchise(userID int, franchise *models.Franchise) (*models.Franchise, error)
+	GetFranchises() ([]models.Franchise, error)
+	GetFranchise(userID int, franchiseID string) (*models.Franchise, error)
+	AddItemToFranchise(userID int, franchiseID string, itemID string) error
+	RemoveItemFromFranchise(userID int, franchiseID string, itemID string) error
+}
+
+type TaxonomyService interface {
+	GetGenres() ([]models.Genre, error)
+	CreateGenre(userID int, genre *models.Genre) (*models.Genre, error)
+	GetItemGenres(userID int, itemID string) ([]models.Genre, error)
+	SetItemGenres(userID int, itemID string, slugs []string) error
+	GetUserTags(userID int) ([]models.Tag, error)
+	SuggestTags(userID int, prefix string, limit int) ([]models.Tag, error)
+	GetItemTags(userID int, itemID string) ([]models.Tag, error)
+	SetItemTags(userID int, itemID string, names []string) ([]models.Tag, error)
+	GetCollectionTags(userID int, collectionID string) ([]models.Tag, error)
+	SetCollectionTags(userID int, collectionID string, names []string) ([]models.Tag, error)
+}
+
+type SearchService interface {
+	SearchItems(query models.SearchQuery, pagination pagination.PaginationRequest) (*pagination.PaginatedResponse[models.SearchResult], error)
+}
+
+type ModerationService interface {
+	GetQueue(userID int, pagination pagination.PaginationRequest) (*pagination.PaginatedResponse[models.CollectionItem], error)
+	SubmitItem(userID int, itemID string) error
+	UpdateItem(userID int, itemID string, changes models.ItemChanges) (*models.CollectionItem, error)
+	ApproveItem(userID int, itemID string) (*models.ModerationDecision, error)
+	RejectItem(userID int, itemID string, reason string) (*models.ModerationDecision, error)
+	RequestChanges(userID int, itemID string, reason string) (*models.ModerationDecision, error)
+	GetItemHistory(userID int, itemID string) ([]models.ModerationDecision, error)
+}
+
+type NotificationService interface {
+	GetNotifications(userID int, unreadOnly bool, pagination pagination.PaginationRequest) (*pagination.PaginatedResponse[models.Notification], error)
+	GetUnreadCount(userID int) (*models.UnreadNotifications, error)
+	MarkRead(userID int, notificationID int) error
+	MarkAllRead(userID int) (int64, error)
+	GetPreferences(userID int) ([]models.NotificationPreference, error)
+	SetPreferences(userID int, preferences []models.NotificationPreference) ([]models.NotificationPreference, error)
+	Channels() []string
+	RunDeliveries(ctx context.Context)
+}
+
+type EditService interface {
+	SuggestEdit(userID int, itemID string, changes models.ItemChanges, comment *string) (*models.EditSuggestion, error)
+	GetItemSuggestions(userID int, itemID string, status string, pagination pagination.PaginationRequest) (*pagination.PaginatedResponse[models.EditSuggestion], error)
+	GetPendingSuggestions(userID int, pagination pagination.PaginationRequest) (*pagination.PaginatedResponse[models.EditSuggestion], error)
+	AcceptSuggestion(userID int, suggestionID int, comment *string) (*models.ItemRevision, error)
+	RejectSuggestion(userID int, suggestionID int, comment *string) error
+	GetItemHistory(userID int, itemID string) ([]models.ItemRevision, error)
+	RevertRevision(userID int, itemID string, revisionID int) (*models.ItemRevision, error)
+}
+
+type ImageService interface {
+	UploadItemCover(userID int, itemID string, file io.Reader) (*models.ImageUpload, error)
+	UploadCollectionCover(userID int, collectionID string, file io.Reader) (*models.ImageUpload, error)
+	UploadAvatar(userID int, file io.Reader) (*models.ImageUpload, error)
+	GetImageURL(imageID string, size string) (string, error)
+	OpenFile(ctx context.Context, key string, expires string, signature string) (*blobstore.Object, error)
+}
+
+type CollectionCoverService interface {
+	GetCollectionCover(ctx context.Context, userID int, collectionID string) (*models.CollectionCover, error)
+}
+
+type MetadataService interface {
+	Lookup(ctx context.Context, query models.LookupQuery) (*models.LookupResponse, error)
+	Import(ctx context.Context, userID int, provider string, externalID string) (*models.ItemImportResult, error)
+	GetItemExternalIDs(itemID string) ([]models.ExternalID, error)
+}
+
+type MetadataRefreshService interface {
+	RunMetadataRefresh(ctx context.Context)
+	RefreshStale(ctx context.Context) error
+	RefreshItem(ctx context.Context, userID int, itemID string) (*models.MetadataRefreshResult, error)
+	GetLockedFields(itemID string) ([]models.ItemFieldLock, error)
+	LockField(userID int, itemID string, field string) (*models.ItemFieldLock, error)
+	UnlockField(userID int, itemID string, field string) error
+}
+
+type ImportService interface {
+	CreateImport(userID int, format string, filename string, file io.Reader, createCustom bool) (*models.ImportJob, error)
+	GetImport(userID int, jobID string) (*models.ImportJob, error)
+	GetImports(userID int, pagination pagination.PaginationRequest) (*pagination.PaginatedResponse[models.ImportJob], error)
+	GetUnmatchedRows(userID int, jobID string, pagination pagination.PaginationRequest) (*pagination.PaginatedResponse[models.ImportRow], error)
+	ResolveRow(userID int, jobID string, rowID int, itemID string) (*models.ImportRow, error)
+	RunImports(ctx context.Context)
+}
+
+type ExportService interface {
+	ExportCollection(userID int, collectionID string, format string) (*models.Export, error)
+	ExportLibrary(userID int, format string) (*models.Export, error)
+}
+
+type BackupService interface {
+	CreateBackup(userID int) (*models.Export, error)
+	Restore(userID int, file io.Reader, mode string) (*models.RestoreResult, error)
+}
+
+type SyndicationService interface {
+	GetCollectionFeed(collectionID string, format string) (*models.SyndicationFeed, error)
+	GetUserFeed(userID int, format string) (*models.SyndicationFeed, error)
+	GetCatalogFeed(format string) (*models.SyndicationFeed, error)
+}
+
+type SocialService interface {
+	GetProfile(viewerID int, userID int) (*models.PublicProfile, error)
+	SetAccountPrivate(userID int, private bool) error
+	Follow(followerID int, followeeID int) (*models.Follow, error)
+	Unfollow(followerID int, followeeID int) error
+	GetFollowers(viewerID int, userID int, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.FollowUser], error)
+	GetFollowing(viewerID int, userID int, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.FollowUser], error)
+	GetFollowRequests(userID int, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.FollowUser], error)
+	AcceptFollowRequest(userID int, followerID int) error
+	RejectFollowRequest(userID int, followerID int) error
+	RemoveFollower(userID int, followerID int) error
+	GetHomeFeed(userID int, req pagination.CursorRequest) (*pagination.CursorResponse[models.Activity], error)
+	GetUserActivity(viewerID int, userID int, req pagination.CursorRequest) (*pagination.CursorResponse[models.Activity], error)
+}
+
+type EngagementService interface {
+	GetEngagement(userID int, collectionID string) (*models.CollectionEngagement, error)
+	SetLiked(userID int, collectionID string, liked bool) (*models.CollectionEngagement, error)
+	SetSaved(userID int, collectionID string, saved bool) (*models.CollectionEngagement, error)
+	GetSavedCollections(userID int, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.SavedCollection], error)
+	GetComments(userID int, collectionID string, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.CollectionComment], error)
+	AddComment(userID int, collectionID string, parentID *int64, body string) (*models.CollectionComment, error)
+	EditComment(userID int, commentID int64, body string) (*models.CollectionComment, error)
+	DeleteComment(userID int, commentID int64) error
+}
+
+type RealtimeService interface {
+	Subscribe(userID int, collectionIDs []string) (*realtime.Subscription, error)
+	RunRealtime(ctx context.Context)
+}
+
+type WebhookService interface {
+	EventTypes() []string
+	CreateWebhook(userID int, input models.WebhookInput) (*models.Webhook, error)
+	GetWebhooks(userID int) ([]models.Webhook, error)
+	GetWebhook(userID int, webhookID int) (*models.Webhook, error)
+	UpdateWebhook(userID int, webhookID int, input models.WebhookInput) (*models.Webhook, error)
+	DeleteWebhook(userID int, webhookID int) error
+	GetDeliveries(userID int, webhookID int, status string, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.WebhookDelivery], error)
+	SendTestEvent(ctx context.Context, userID int, webhookID int) (*models.WebhookDelivery, error)
+	RunWebhookDeliveries(ctx context.Context)
+}
+
+type RecommendationService interface {
+	GetSimilarItems(userID int, itemID string, excludeLibrary bool, limit int) ([]models.SimilarItem, error)
+	GetRecommendations(userID int, itemType string, limit int) ([]models.RecommendedItem, error)
+	RunRecommendations(ctx context.Context)
+}
+
+type ItemStatsService interface {
+	RunItemStats(ctx context.Context)
+}
+
+type ChartService interface {
+	GetChart(query models.ChartQuery, req pagination.PaginationRequest) (*models.Chart, error)
+	RunCharts(ctx context.Context)
 }
 
 type Service struct {
@@ -46,13 +241,88 @@ type Service struct {
 	UserService
 	CollectionService
 	CollectionItemService
+	EpisodeService
+	RelationService
+	TaxonomyService
+	SearchService
+	ModerationService
+	NotificationService
+	EditService
+	ImageService
+	CollectionCoverService
+	MetadataService
+	MetadataRefreshService
+	ImportService
+	ExportService
+	BackupService
+	SyndicationService
+	SocialService
+	EngagementService
+	RealtimeService
+	WebhookService
+	RecommendationService
+	ChartService
+	ItemStatsService
+}
+
+// Dependencies - внешние зависимости сервисов помимо репозиториев
+type Dependencies struct {
+	Storage blobstore.BlobStore
+	Images  ImageConfig
+	// Metadata - подключенные внешние каталоги для поиска и импорта элементов
+	Metadata        *metadata.Registry
+	MetadataRefresh MetadataRefreshConfig
+	// Exporters - форматы выгрузки коллекций, по умолчанию csv, json, md и html
+	Exporters *exporter.Registry
+	Feeds     FeedConfig
+	// Notifications - каналы доставки уведомлений вне приложения
+	Notifications NotificationConfig
+	// Realtime - брокер событий для клиентов /stream, по умолчанию в памяти процесса
+	Realtime realtime.Broker
+	// Webhooks - отправка событий на вебхуки пользователей
+	Webhooks WebhookConfig
+	// Recommendations - фоновый пересчет похожих элементов
+	Recommendations RecommendationConfig
+	// Charts - фоновый пересчет чартов каталога
+	Charts ChartConfig
+	// ItemStats - фоновый пересчет статистики элементов для ленты
+	ItemStats ItemStatsConfig
 }
 
-func NewService(repository *repository.Repository, logger *zap.SugaredLogger) *Service {
+func NewService(repository *repository.Repository, deps Dependencies, logger *zap.SugaredLogger) *Service {
+	broker := deps.Realtime
+	if broker == nil {
+		broker = realtime.NewMemoryBroker()
+	}
+	suggestCache := in_memory_cache.NewMemoryCache[[]models.ItemSuggestion](suggestCacheTTL)
+	events := newEventPublisher(broker, repository.Notification, suggestCache, logger)
+
 	return &Service{
-		AuthService:           NewAuthService(repository.UserRepository, logger),
-		UserService:           NewUserService(repository.UserRepository, logger),
-		CollectionService:     NewCollectionService(repository.Collection, logger),
-		CollectionItemService: NewCollectionItemService(repository.CollectionItem, repository.Collection, logger),
+		AuthService:            NewAuthService(repository.UserRepository, logger),
+		UserService:            NewUserService(repository.UserRepository, logger),
+		CollectionService:      NewCollectionService(repository.Collection, events, logger),
+		CollectionItemService:  NewCollectionItemService(repository.CollectionItem, repository.Collection, repository.Duplicate, repository.Moderation, repository.UserRepository, suggestCache, events, logger),
+		EpisodeService:         NewEpisodeService(repository.Episode, repository.CollectionItem, repository.UserRepository, logger),
+		RelationService:        NewRelationService(repository.Relation, repository.CollectionItem, repository.UserRepository, logger),
+		TaxonomyService:        NewTaxonomyService(repository.Taxonomy, repository.CollectionItem, repository.Collection, repository.UserRepository, events, logger),
+		SearchService:          NewSearchService(repository.Search, logger),
+		ModerationService:      NewModerationService(repository.Moderation, repository.CollectionItem, repository.UserRepository, events, logger),
+		NotificationService:    NewNotificationService(repository.Notification, deps.Notifications, events, logger),
+		EditService:            NewEditService(repository.Edit, repository.CollectionItem, repository.UserRepository, events, logger),
+		ImageService:           NewImageService(repository.Image, repository.CollectionItem, repository.Collection, repository.UserRepository, deps.Storage, deps.Images, events, logger),
+		CollectionCoverService: NewCollectionCoverService(repository.Collection, repository.Image, deps.Storage, deps.Images.PublicURL, logger),
+		MetadataService:        NewMetadataService(deps.Metadata, repository.Metadata, repository.Duplicate, events, logger),
+		MetadataRefreshService: NewMetadataRefreshService(deps.Metadata, repository.Metadata, repository.CollectionItem, repository.UserRepository, deps.MetadataRefresh, events, logger),
+		ImportService:          NewImportService(repository.Import, repository.CollectionItem, repository.Duplicate, events, logger),
+		ExportService:          NewExportService(deps.Exporters, repository.Export, repository.Collection, repository.UserRepository, logger),
+		BackupService:          NewBackupService(repository.Backup, events, logger),
+		SyndicationService:     NewSyndicationService(repository.Syndication, repository.CollectionItem, repository.Collection, repository.UserRepository, deps.Feeds, logger),
+		SocialService:          NewSocialService(repository.Social, events, logger),
+		EngagementService:      NewEngagementService(repository.Engagement, repository.Collection, events, logger),
+		RealtimeService:        NewRealtimeService(broker, repository.Collection, logger),
+		WebhookService:         NewWebhookService(repository.Webhook, deps.Webhooks, logger),
+		RecommendationService:  NewRecommendationService(repository.Recommendation, repository.CollectionItem, deps.Recommendations, logger),
+		ChartService:           NewChartService(repository.Chart, repository.Taxonomy, deps.Charts, logger),
+		ItemStatsService:       NewItemStatsService(repository.CollectionItem, deps.ItemStats, logger),
 	}
 }
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/repository"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrCannotFollowSelf      = errors.New("you cannot follow yourself")
+	ErrNotFollowing          = errors.New("you are not following this user")
+	ErrFollowRequestNotFound = errors.New("follow request not found")
+	ErrFollowerNotFound      = errors.New("follower not found")
+	ErrProfilePrivate        = errors.New("this account is private, follow it to see its activity")
+)
+
+type socialService struct {
+	socialRepo repository.Social
+	events     *eventPublisher
+	logger     *zap.SugaredLogger
+}
+
+func NewSocialService(socialRepo repository.Social, events *eventPublisher, logger *zap.SugaredLogger) *socialService {
+	return &socialService{
+		socialRepo: socialRepo,
+		events:     events,
+		logger:     logger,
+	}
+}
+
+func (s *socialService) GetProfile(viewerID int, userID int) (*models.PublicProfile, error) {
+	profile, err := s.socialRepo.GetPublicProfile(userID, viewerID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrUserNotFound
+	}
+	return profile, err
+}
+
+func (s *socialService) SetAccountPrivate(userID int, private bool) error {
+	err := s.socialRepo.SetAccountPrivate(userID, private)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrUserNotFound
+	}
+	return err
+}
+
+// Follow подписывает на пользователя. На приватный аккаунт отправляется запрос на подписку
+func (s *socialService) Follow(followerID int, followeeID int) (*models.Follow, error) {
+	if followerID == followeeID {
+		return nil, ErrCannotFollowSelf
+	}
+	follow, err := s.socialRepo.Follow(followerID, followeeID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.events.notificationsChanged(followeeID)
+	return follow, nil
+}
+
+// Unfollow отменяет подписку или неподтвержденный запрос на подписку
+func (s *socialService) Unfollow(followerID int, followeeID int) error {
+	err := s.socialRepo.DeleteFollow(followerID, followeeID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrNotFollowing
+	}
+	return err
+}
+
+func (s *socialService) GetFollowers(viewerID int, userID int, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.FollowUser], error) {
+	if err := s.checkVisible(viewerID, userID); err != nil {
+		return nil, err
+	}
+	return s.socialRepo.GetFollowers(userID, models.FollowStatusAccepted, req)
+}
+
+func (s *socialService) GetFollowing(viewerID int, userID int, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.FollowUser], error) {
+	if err := s.checkVisible(viewerID, userID); err != nil {
+		return nil, err
+	}
+	return s.socialRepo.GetFollowing(userID, req)
+}
+
+// GetFollowRequests - входящие запросы на подписку
+func (s *socialService) GetFollowRequests(userID int, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.FollowUser], error) {
+	return s.socialRepo.GetFollowers(userID, models.FollowStatusPending, req)
+}
+
+func (s *socialService) AcceptFollowRequest(userID int, followerID int) error {
+	err := s.socialRepo.AcceptFollowRequest(userID, followerID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrFollowRequestNotFound
+	}
+	if err != nil {
+		return err
+	}
+	s.events.notificationsChanged(followerID)
+	return nil
+}
+
+// RejectFollowRequest отклоняет запрос на подписку. Уже принятую подписку так не удалить
+func (s *socialService) RejectFollowRequest(userID int, followerID int) error {
+	status, err := s.socialRepo.GetFollowStatus(followerID, userID)
+	if err != nil {
+		return err
+	}
+	if status != models.FollowStatusPending {
+		return ErrFollowRequestNotFound
+	}
+	err = s.socialRepo.DeleteFollow(followerID, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrFollowRequestNotFound
+	}
+	return err
+}
+
+// RemoveFollower отписывает подписчика от пользователя
+func (s *socialService) RemoveFollower(userID int, followerID int) error {
+	status, err := s.socialRepo.GetFollowStatus(followerID, userID)
+	if err != nil {
+		return err
+	}
+	if status != models.FollowStatusAccepted {
+		return ErrFollowerNotFound
+	}
+	err = s.socialRepo.DeleteFollow(followerID, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrFollowerNotFound
+	}
+	return err
+}
+
+// GetHomeFeed - события пользователей, на которых подписан userID, сначала новые
+func (s *socialService) GetHomeFeed(userID int, req pagination.CursorRequest) (*pagination.CursorResponse[models.Activity], error) {
+	return s.socialRepo.GetHomeFeed(userID, req)
+}
+
+func (s *socialService) GetUserActivity(viewerID int, userID int, req pagination.CursorRequest) (*pagination.CursorResponse[models.Activity], error) {
+	if err := s.checkVisible(viewerID, userID); err != nil {
+		return nil, err
+	}
+	return s.socialRepo.GetUserActivity(userID, req)
+}
+
+// checkVisible проверяет, что viewerID может видеть подписки и активность userID:
+// активность приватного аккаунта видят только он сам и его подписчики
+func (s *socialService) checkVisible(viewerID int, userID int) error {
+	profile, err := s.GetProfile(viewerID, userID)
+	if err != nil {
+		return err
+	}
+	if profile.IsPrivate && viewerID != userID && profile.FollowStatus != models.FollowStatusAccepted {
+		return ErrProfilePrivate
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/repository"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/syndication"
+	"go.uber.org/zap"
+)
+
+var ErrUserNotFound = errors.New("user not found")
+
+const (
+	defaultFeedLimit     = 50
+	defaultFeedMaxAge    = 15 * time.Minute
+	maxFeedSummaryLength = 500
+	defaultFeedFormat    = syndication.FormatAtom
+)
+
+// FeedConfig - настройки лент Atom и RSS
+type FeedConfig struct {
+	// PublicURL - внешний адрес API, от него строятся ссылки в лентах
+	PublicURL string
+	// Limit - количество записей в ленте
+	Limit int
+	// MaxAge - время кеширования ленты ридерами и прокси
+	MaxAge time.Duration
+}
+
+type syndicationService struct {
+	syndicationRepo repository.Syndication
+	itemRepo        repository.CollectionItem
+	collectionRepo  repository.Collection
+	userRepo        repository.UserRepository
+	cfg             FeedConfig
+	logger          *zap.SugaredLogger
+}
+
+func NewSyndicationService(syndicationRepo repository.Syndication, itemRepo repository.CollectionItem, collectionRepo repository.Collection, userRepo repository.UserRepository, cfg FeedConfig, logger *zap.SugaredLogger) *syndicationService {
+	if cfg.Limit <= 0 {
+		cfg.Limit = defaultFeedLimit
+	}
+	if cfg.MaxAge <= 0 {
+		cfg.MaxAge = defaultFeedMaxAge
+	}
+	cfg.PublicURL = strings.TrimRight(cfg.PublicURL, "/")
+	return &syndicationService{
+		syndicationRepo: syndicationRepo,
+		itemRepo:        itemRepo,
+		collectionRepo:  collectionRepo,
+		userRepo:        userRepo,
+		cfg:             cfg,
+		logger:          logger,
+	}
+}
+
+// GetCollectionFeed - лента публичной коллекции: последние добавленные элементы с заметками владельца
+func (s *syndicationService) GetCollectionFeed(collectionID string, format string) (*models.SyndicationFeed, error) {
+	format, err := feedFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	collection, err := s.collectionRepo.GetCollectionByID(collectionID)
+	if err != nil {
+		return nil, ErrCollectionNotFound
+	}
+	if !collection.IsPublic {
+		return nil, ErrNotCollectionOwner
+	}
+	owner, err := s.userRepo.GetUserByID(collection.UserID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	entries, err := s.itemRepo.GetItemsByCollection(collection.ID, models.TagFilter{}, s.cfg.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	feed := syndication.Feed{
+		ID:       "urn:memoria:collections:" + collection.ID,
+		Title:    collection.Name,
+		Subtitle: collection.Description,
+		Link:     s.url("/collections/" + collection.ID + "/items"),
+		SelfLink: s.url("/feeds/collections/" + collection.ID + "?format=" + format),
+		Author:   owner.Name,
+		Updated:  collection.CreatedAt,
+	}
+	for _, entry := range entries {
+		feed.Entries = append(feed.Entries, s.itemEntry(entry.CollectionItem, collectionEntryID(collection.ID, entry.ID),
+			entry.AddedAt, entry.Review))
+		if entry.AddedAt.After(feed.Updated) {
+			feed.Updated = entry.AddedAt
+		}
+	}
+	return s.render(feed, format)
+}
+
+// GetUserFeed - лента публичной активности пользователя: новые публичные коллекции и добавления в них.
+// Для закрытого аккаунта ленты нет
+func (s *syndicationService) GetUserFeed(userID int, format string) (*models.SyndicationFeed, error) {
+	format, err := feedFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	activity, err := s.syndicationRepo.GetPublicActivity(userID, s.cfg.Limit)
+	if errors.Is(err, repository.ErrNotFound) {
+		// Закрытый аккаунт неотличим от несуществующего: лента не раскрывает ни активность, ни сам аккаунт
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	userPath := "/feeds/users/" + strconv.Itoa(userID)
+	feed := syndication.Feed{
+		ID:       "urn:memoria:users:" + strconv.Itoa(userID),
+		Title:    "Memoria: " + user.Name,
+		Subtitle: "Публичные коллекции " + user.Name,
+		Link:     s.url(userPath),
+		SelfLink: s.url(userPath + "?format=" + format),
+		Author:   user.Name,
+		Updated:  user.CreatedAt,
+	}
+	for _, event := range activity {
+		var entry syndication.Entry
+		switch event.Type {
+		case models.PublicActivityCollectionCreated:
+			entry = syndication.Entry{
+				ID:        "urn:memoria:collections:" + event.CollectionID,
+				Title:     fmt.Sprintf("Новая коллекция «%s»", event.CollectionName),
+				Link:      s.url("/collections/" + event.CollectionID + "/items"),
+				Published: event.CreatedAt,
+			}
+		case models.PublicActivityItemAdded:
+			entry = s.itemEntry(*event.Item, collectionEntryID(event.CollectionID, event.Item.ID), event.CreatedAt, event.Review)
+			entry.Title = fmt.Sprintf("%s → «%s»", entry.Title, event.CollectionName)
+		default:
+			continue
+		}
+		feed.Entries = append(feed.Entries, entry)
+		if event.CreatedAt.After(feed.Updated) {
+			feed.Updated = event.CreatedAt
+		}
+	}
+	return s.render(feed, format)
+}
+
+// GetCatalogFeed - лента элементов, последними появившихся в публичном каталоге
+func (s *syndicationService) GetCatalogFeed(format string) (*models.SyndicationFeed, error) {
+	format, err := feedFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.syndicationRepo.GetRecentCatalogItems(s.cfg.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Время пустой ленты должно быть постоянным, иначе ETag менялся бы при каждом запросе
+	feed := syndication.Feed{
+		ID:       "urn:memoria:catalog",
+		Title:    "Memoria: новое в каталоге",
+		Subtitle: "Элементы, недавно добавленные в публичный каталог",
+		Link:     s.url("/feeds/catalog"),
+		SelfLink: s.url("/feeds/catalog?format=" + format),
+		Updated:  time.Unix(0, 0),
+	}
+	for _, item := range items {
+		entry := s.itemEntry(item.CollectionItem, "urn:memoria:items:"+item.ID, item.PublishedAt, nil)
+		if item.UpdatedAt.After(item.PublishedAt) {
+			entry.Updated = item.UpdatedAt
+		}
+		feed.Entries = append(feed.Entries, entry)
+		if item.PublishedAt.After(feed.Updated) {
+			feed.Updated = item.PublishedAt
+		}
+	}
+	return s.render(feed, format)
+}
+
+// itemEntry - запись ленты об элементе: заметка и начало описания
+func (s *syndicationService) itemEntry(item models.CollectionItem, id string, published time.Time, review *string) syndication.Entry {
+	title := item.Title
+	if item.ReleaseYear != nil {
+		title = fmt.Sprintf("%s (%d)", title, *item.ReleaseYear)
+	}
+	var summary []string
+	if review != nil && strings.TrimSpace(*review) != "" {
+		summary = append(summary, strings.TrimSpace(*review))
+	}
+	if description := strings.TrimSpace(item.Description); description != "" {
+		if truncated := truncateRunes(description, maxFeedSummaryLength); truncated != description {
+			description = truncated + "…"
+		}
+		summary = append(summary, description)
+	}
+	var categories []string
+	if item.Type != "" {
+		categories = []string{item.Type}
+	}
+	return syndication.Entry{
+		ID:         id,
+		Title:      title,
+		Link:       s.url("/items/" + item.ID),
+		Published:  published,
+		Summary:    strings.Join(summary, "\n\n"),
+		Image:      item.CoverImage,
+		Categories: categories,
+	}
+}
+
+// render записывает ленту и считает ETag по ее содержимому
+func (s *syndicationService) render(feed syndication.Feed, format string) (*models.SyndicationFeed, error) {
+	contentType, err := syndication.ContentType(format)
+	if err != nil {
+		return nil, err
+	}
+	var body bytes.Buffer
+	if err := syndication.Write(&body, format, feed); err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(body.Bytes())
+	return &models.SyndicationFeed{
+		ContentType:  contentType,
+		Body:         body.Bytes(),
+		ETag:         hex.EncodeToString(sum[:16]),
+		LastModified: feed.Updated,
+		MaxAge:       s.cfg.MaxAge,
+	}, nil
+}
+
+func (s *syndicationService) url(path string) string {
+	return s.cfg.PublicURL + path
+}
+
+// collectionEntryID - ID записи о добавлении элемента в коллекцию, один и тот же в ленте коллекции и ленте пользователя
+func collectionEntryID(collectionID string, itemID string) string {
+	return "urn:memoria:collections:" + collectionID + ":items:" + itemID
+}
+
+func feedFormat(format string) (string, error) {
+	if format == "" {
+		return defaultFeedFormat, nil
+	}
+	format = strings.ToLower(format)
+	if _, err := syndication.ContentType(format); err != nil {
+		return "", err
+	}
+	return format, nil
+}
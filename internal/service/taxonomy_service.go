@@ -0,0 +1,227 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/repository"
+	"go.uber.org/zap"
+)
+
+const (
+	maxTagLength        = 50
+	maxTagsPerEntity    = 20
+	defaultSuggestLimit = 10
+	maxSuggestLimit     = 50
+)
+
+var (
+	ErrGenreNotFound      = errors.New("genre not found")
+	ErrGenreAlreadyExists = errors.New("genre already exists")
+	ErrInvalidGenreSlug   = errors.New("genre slug must contain only lowercase latin letters, digits and dashes")
+	ErrInvalidTag         = errors.New("tag must be non-empty and at most 50 characters")
+	ErrTooManyTags        = errors.New("too many tags")
+	ErrItemNotInLibrary   = errors.New("item is not in user's library")
+)
+
+var genreSlugRegexp = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+type taxonomyService struct {
+	taxonomyRepo   repository.Taxonomy
+	itemRepo       repository.CollectionItem
+	collectionRepo repository.Collection
+	userRepo       repository.UserRepository
+	events         *eventPublisher
+	logger         *zap.SugaredLogger
+}
+
+func NewTaxonomyService(taxonomyRepo repository.Taxonomy, itemRepo repository.CollectionItem, collectionRepo repository.Collection, userRepo repository.UserRepository, events *eventPublisher, logger *zap.SugaredLogger) *taxonomyService {
+	return &taxonomyService{
+		taxonomyRepo:   taxonomyRepo,
+		itemRepo:       itemRepo,
+		collectionRepo: collectionRepo,
+		userRepo:       userRepo,
+		events:         events,
+		logger:         logger,
+	}
+}
+
+func (s *taxonomyService) GetGenres() ([]models.Genre, error) {
+	return s.taxonomyRepo.GetGenres()
+}
+
+func (s *taxonomyService) CreateGenre(userID int, genre *models.Genre) (*models.Genre, error) {
+	if err := requireModerator(s.userRepo, userID); err != nil {
+		return nil, err
+	}
+
+	genre.Slug = strings.ToLower(strings.TrimSpace(genre.Slug))
+	if !genreSlugRegexp.MatchString(genre.Slug) {
+		return nil, ErrInvalidGenreSlug
+	}
+
+	created, err := s.taxonomyRepo.CreateGenre(genre)
+	if errors.Is(err, repository.ErrAlreadyExists) {
+		return nil, ErrGenreAlreadyExists
+	}
+	return created, err
+}
+
+func (s *taxonomyService) GetItemGenres(userID int, itemID string) ([]models.Genre, error) {
+	if _, err := s.getVisibleItem(itemID, userID); err != nil {
+		return nil, err
+	}
+	return s.taxonomyRepo.GetItemGenres(itemID)
+}
+
+// SetItemGenres - жанры публичных элементов курируют модераторы, своих приватных - создатель
+func (s *taxonomyService) SetItemGenres(userID int, itemID string, slugs []string) error {
+	item, err := s.getVisibleItem(itemID, userID)
+	if err != nil {
+		return err
+	}
+	if item.IsPublic {
+		if err := requireModerator(s.userRepo, userID); err != nil {
+			return err
+		}
+	}
+
+	err = s.taxonomyRepo.SetItemGenres(itemID, uniqueLower(slugs))
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrGenreNotFound
+	}
+	return err
+}
+
+func (s *taxonomyService) GetUserTags(userID int) ([]models.Tag, error) {
+	return s.taxonomyRepo.GetUserTags(userID)
+}
+
+func (s *taxonomyService) SuggestTags(userID int, prefix string, limit int) ([]models.Tag, error) {
+	if limit < 1 {
+		limit = defaultSuggestLimit
+	}
+	if limit > maxSuggestLimit {
+		limit = maxSuggestLimit
+	}
+	return s.taxonomyRepo.SuggestTags(userID, strings.ToLower(strings.TrimSpace(prefix)), limit)
+}
+
+func (s *taxonomyService) GetItemTags(userID int, itemID string) ([]models.Tag, error) {
+	return s.taxonomyRepo.GetItemTags(userID, itemID)
+}
+
+// SetItemTags - теги можно ставить только на элементы из своей медиатеки
+func (s *taxonomyService) SetItemTags(userID int, itemID string, names []string) ([]models.Tag, error) {
+	tags, err := normalizeTags(names)
+	if err != nil {
+		return nil, err
+	}
+
+	inLibrary, err := s.taxonomyRepo.IsItemInUserLibrary(userID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if !inLibrary {
+		return nil, ErrItemNotInLibrary
+	}
+
+	if err := s.taxonomyRepo.SetItemTags(userID, itemID, tags); err != nil {
+		return nil, err
+	}
+	return s.taxonomyRepo.GetItemTags(userID, itemID)
+}
+
+func (s *taxonomyService) GetCollectionTags(userID int, collectionID string) ([]models.Tag, error) {
+	collection, err := s.collectionRepo.GetCollectionByID(collectionID)
+	if err != nil {
+		return nil, ErrCollectionNotFound
+	}
+	if !collection.IsPublic && collection.UserID != userID {
+		return nil, ErrNotCollectionOwner
+	}
+	return s.taxonomyRepo.GetCollectionTags(collectionID)
+}
+
+func (s *taxonomyService) SetCollectionTags(userID int, collectionID string, names []string) ([]models.Tag, error) {
+	tags, err := normalizeTags(names)
+	if err != nil {
+		return nil, err
+	}
+
+	collection, err := s.collectionRepo.GetCollectionByID(collectionID)
+	if err != nil {
+		return nil, ErrCollectionNotFound
+	}
+	if collection.UserID != userID {
+		return nil, ErrNotCollectionOwner
+	}
+
+	if err := s.taxonomyRepo.SetCollectionTags(userID, collectionID, tags); err != nil {
+		return nil, err
+	}
+	saved, err := s.taxonomyRepo.GetCollectionTags(collectionID)
+	if err != nil {
+		return nil, err
+	}
+	s.events.collectionChanged(collection, models.EventCollectionUpdated, models.CollectionUpdatedEvent{
+		Change: models.CollectionChangeTags,
+		Tags:   saved,
+	})
+	return saved, nil
+}
+
+func (s *taxonomyService) getVisibleItem(itemID string, userID int) (*models.CollectionItem, error) {
+	item, err := s.itemRepo.GetItemByID(itemID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrItemNotFound
+		}
+		return nil, err
+	}
+	if !item.IsPublic && (item.CreatorID == nil || *item.CreatorID != userID) {
+		return nil, ErrItemAccessDenied
+	}
+	return item, nil
+}
+
+// normalizeTags обрезает пробелы и убирает дубликаты без учета регистра, сохраняя написание первого вхождения
+func normalizeTags(names []string) ([]string, error) {
+	if len(names) > maxTagsPerEntity {
+		return nil, ErrTooManyTags
+	}
+
+	seen := make(map[string]bool, len(names))
+	tags := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.Join(strings.Fields(name), " ")
+		if name == "" || utf8.RuneCountInString(name) > maxTagLength {
+			return nil, ErrInvalidTag
+		}
+		key := strings.ToLower(name)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		tags = append(tags, name)
+	}
+	return tags, nil
+}
+
+// uniqueLower приводит значения фильтра к нижнему регистру и убирает дубликаты и пустые значения
+func uniqueLower(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		result = append(result, value)
+	}
+	return result
+}
@@ -0,0 +1,329 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/models"
+	"github.com/kefirchick13/memoria-collect-platform-golang/internal/repository"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/pagination"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/safehttp"
+	"github.com/kefirchick13/memoria-collect-platform-golang/pkg/webhook"
+	"go.uber.org/zap"
+)
+
+// MaxWebhooksPerUser - сколько вебхуков может завести пользователь
+const MaxWebhooksPerUser = 10
+
+const (
+	defaultWebhookInterval    = 10 * time.Second
+	defaultWebhookBatchSize   = 50
+	defaultWebhookMaxAttempts = 8
+	defaultWebhookTimeout     = 10 * time.Second
+	maxWebhookURLLength       = 2048
+	maxWebhookDescription     = 255
+	// Пауза перед повтором удваивается с каждой попыткой: 30с, 1м, 2м... но не больше 12ч
+	webhookRetryBase = 30 * time.Second
+	webhookRetryMax  = 12 * time.Hour
+)
+
+var (
+	ErrWebhookNotFound           = errors.New("webhook not found")
+	ErrInvalidWebhookURL         = errors.New("webhook url must be an absolute http(s) url")
+	ErrWebhookPrivateURL         = errors.New("webhook url must not point to a private network")
+	ErrUnknownWebhookEvent       = errors.New("unknown webhook event")
+	ErrWebhookEventsEmpty        = errors.New("webhook must be subscribed to at least one event")
+	ErrWebhookDescriptionTooLong = fmt.Errorf("webhook description must be at most %d characters", maxWebhookDescription)
+	ErrTooManyWebhooks           = fmt.Errorf("at most %d webhooks are allowed", MaxWebhooksPerUser)
+	ErrUnknownDeliveryStatus     = errors.New("unknown delivery status")
+)
+
+// WebhookConfig - отправка событий на вебхуки пользователей
+type WebhookConfig struct {
+	// AllowPrivateNetworks разрешает адреса внутренней сети, например приемник на localhost при разработке.
+	// В продакшене должно быть выключено, иначе вебхуком можно достучаться до внутренних сервисов
+	AllowPrivateNetworks bool
+	// Timeout - сколько ждать ответа получателя
+	Timeout time.Duration
+	// Interval - как часто обработчик проверяет очередь отправок
+	Interval time.Duration
+	// BatchSize - сколько отправок забирается за один проход
+	BatchSize int
+	// MaxAttempts - после стольких неудачных попыток отправка считается проваленной
+	MaxAttempts int
+}
+
+type webhookService struct {
+	webhookRepo repository.Webhook
+	sender      *webhook.Sender
+	cfg         WebhookConfig
+	logger      *zap.SugaredLogger
+}
+
+func NewWebhookService(webhookRepo repository.Webhook, cfg WebhookConfig, logger *zap.SugaredLogger) *webhookService {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultWebhookTimeout
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultWebhookInterval
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultWebhookBatchSize
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultWebhookMaxAttempts
+	}
+
+	client := safehttp.NewClient(cfg.Timeout)
+	if cfg.AllowPrivateNetworks {
+		client = &http.Client{Timeout: cfg.Timeout}
+	}
+	return &webhookService{
+		webhookRepo: webhookRepo,
+		sender:      webhook.NewSender(client),
+		cfg:         cfg,
+		logger:      logger,
+	}
+}
+
+// EventTypes - события, на которые можно подписать вебхук
+func (s *webhookService) EventTypes() []string {
+	return models.WebhookEventTypes
+}
+
+// CreateWebhook создает вебхук со случайным секретом подписи. Секрет виден только в ответе на создание
+func (s *webhookService) CreateWebhook(userID int, input models.WebhookInput) (*models.Webhook, error) {
+	hook, err := s.normalizeInput(input)
+	if err != nil {
+		return nil, err
+	}
+	count, err := s.webhookRepo.CountWebhooks(userID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= MaxWebhooksPerUser {
+		return nil, ErrTooManyWebhooks
+	}
+
+	if hook.Secret, err = webhook.NewSecret(); err != nil {
+		return nil, err
+	}
+	hook.UserID = userID
+	hook.IsActive = input.IsActive == nil || *input.IsActive
+	return s.webhookRepo.CreateWebhook(hook)
+}
+
+func (s *webhookService) GetWebhooks(userID int) ([]models.Webhook, error) {
+	return s.webhookRepo.GetWebhooks(userID)
+}
+
+func (s *webhookService) GetWebhook(userID int, webhookID int) (*models.Webhook, error) {
+	hook, err := s.webhookRepo.GetWebhook(userID, webhookID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrWebhookNotFound
+	}
+	return hook, err
+}
+
+// UpdateWebhook меняет адрес, описание и события. Выключенный вебхук копит события и отправит их после включения
+func (s *webhookService) UpdateWebhook(userID int, webhookID int, input models.WebhookInput) (*models.Webhook, error) {
+	current, err := s.GetWebhook(userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	hook, err := s.normalizeInput(input)
+	if err != nil {
+		return nil, err
+	}
+	hook.ID = current.ID
+	hook.UserID = userID
+	hook.IsActive = current.IsActive
+	if input.IsActive != nil {
+		hook.IsActive = *input.IsActive
+	}
+
+	updated, err := s.webhookRepo.UpdateWebhook(hook)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrWebhookNotFound
+	}
+	return updated, err
+}
+
+func (s *webhookService) DeleteWebhook(userID int, webhookID int) error {
+	err := s.webhookRepo.DeleteWebhook(userID, webhookID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrWebhookNotFound
+	}
+	return err
+}
+
+// GetDeliveries - журнал отправок вебхука, сначала новые
+func (s *webhookService) GetDeliveries(userID int, webhookID int, status string, req pagination.PaginationRequest) (*pagination.PaginatedResponse[models.WebhookDelivery], error) {
+	if status != "" && status != models.DeliveryStatusPending && status != models.DeliveryStatusSent && status != models.DeliveryStatusFailed {
+		return nil, ErrUnknownDeliveryStatus
+	}
+	if _, err := s.GetWebhook(userID, webhookID); err != nil {
+		return nil, err
+	}
+	return s.webhookRepo.GetWebhookDeliveries(webhookID, status, req)
+}
+
+// SendTestEvent сразу отправляет тестовое событие и возвращает запись журнала с ответом получателя.
+// Тестовое событие не повторяется и уходит даже на выключенный вебхук
+func (s *webhookService) SendTestEvent(ctx context.Context, userID int, webhookID int) (*models.WebhookDelivery, error) {
+	if _, err := s.GetWebhook(userID, webhookID); err != nil {
+		return nil, err
+	}
+	task, err := s.webhookRepo.CreateWebhookDelivery(webhookID, models.WebhookEventTest, models.WebhookTestPayload{
+		WebhookID: webhookID,
+		Message:   "Тестовое событие Memoria",
+	}, s.cfg.Timeout*2)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrWebhookNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.webhookRepo.FinishWebhookDelivery(task.ID, s.send(ctx, task), nil)
+}
+
+// RunWebhookDeliveries - фоновый обработчик очереди отправок, работает, пока не отменен ctx
+func (s *webhookService) RunWebhookDeliveries(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		for ctx.Err() == nil && s.deliverBatch(ctx) {
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliverBatch отправляет одну пачку. Возвращает true, если пачка была полной и очередь стоит проверить сразу
+func (s *webhookService) deliverBatch(ctx context.Context) bool {
+	// Аренда покрывает отправку всей пачки, если все получатели отвечают по таймауту
+	lease := s.cfg.Timeout*time.Duration(s.cfg.BatchSize) + time.Minute
+	tasks, err := s.webhookRepo.ClaimWebhookDeliveries(s.cfg.BatchSize, lease)
+	if err != nil {
+		s.logger.Errorf("Failed to claim webhook deliveries: %v", err)
+		return false
+	}
+	for i := range tasks {
+		task := &tasks[i]
+		result := s.send(ctx, task)
+		var retryAt *time.Time
+		if result.Error != nil {
+			s.logger.Warnf("Webhook %d delivery %d of %s failed (attempt %d): %v",
+				task.WebhookID, task.ID, task.EventType, task.Attempts, result.Error)
+			if task.Attempts < s.cfg.MaxAttempts {
+				next := time.Now().Add(webhookBackoff(task.Attempts))
+				retryAt = &next
+			}
+		}
+		if _, err := s.webhookRepo.FinishWebhookDelivery(task.ID, result, retryAt); err != nil {
+			s.logger.Errorf("Failed to save webhook delivery %d: %v", task.ID, err)
+		}
+	}
+	return len(tasks) == s.cfg.BatchSize
+}
+
+func (s *webhookService) send(ctx context.Context, task *models.WebhookDeliveryTask) models.WebhookDeliveryResult {
+	body, err := json.Marshal(models.WebhookEnvelope{
+		ID:        task.ID,
+		Type:      task.EventType,
+		UserID:    task.UserID,
+		CreatedAt: task.CreatedAt,
+		Data:      task.Payload,
+	})
+	if err != nil {
+		return models.WebhookDeliveryResult{Error: fmt.Errorf("failed to encode webhook body: %w", err)}
+	}
+
+	resp, err := s.sender.Send(ctx, webhook.Request{
+		URL:        task.URL,
+		Secret:     task.Secret,
+		Event:      task.EventType,
+		DeliveryID: strconv.FormatInt(task.ID, 10),
+		Body:       body,
+	})
+	result := models.WebhookDeliveryResult{Error: err}
+	if resp != nil {
+		durationMs := int(resp.Duration.Milliseconds())
+		result.ResponseStatus = &resp.Status
+		result.ResponseBody = &resp.Body
+		result.DurationMs = &durationMs
+	}
+	return result
+}
+
+// normalizeInput проверяет адрес, описание и события
+func (s *webhookService) normalizeInput(input models.WebhookInput) (*models.Webhook, error) {
+	rawURL := strings.TrimSpace(input.URL)
+	if err := s.validateURL(rawURL); err != nil {
+		return nil, err
+	}
+
+	events := unique(input.Events)
+	if len(events) == 0 {
+		return nil, ErrWebhookEventsEmpty
+	}
+	for _, event := range events {
+		if !slices.Contains(models.WebhookEventTypes, event) {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownWebhookEvent, event)
+		}
+	}
+
+	hook := &models.Webhook{URL: rawURL, Events: events}
+	if input.Description != nil {
+		if description := strings.TrimSpace(*input.Description); description != "" {
+			if len([]rune(description)) > maxWebhookDescription {
+				return nil, ErrWebhookDescriptionTooLong
+			}
+			hook.Description = &description
+		}
+	}
+	return hook, nil
+}
+
+// validateURL отсекает очевидно внутренние адреса сразу. Имена, которые резолвятся во внутреннюю сеть,
+// safehttp не пропустит при отправке
+func (s *webhookService) validateURL(rawURL string) error {
+	if len(rawURL) > maxWebhookURLLength {
+		return ErrInvalidWebhookURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" || u.User != nil {
+		return ErrInvalidWebhookURL
+	}
+	if s.cfg.AllowPrivateNetworks {
+		return nil
+	}
+	host := u.Hostname()
+	if strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+		return ErrWebhookPrivateURL
+	}
+	if ip := net.ParseIP(host); ip != nil && !safehttp.IsPublicIP(ip) {
+		return ErrWebhookPrivateURL
+	}
+	return nil
+}
+
+func webhookBackoff(attempts int) time.Duration {
+	backoff := webhookRetryBase
+	for i := 1; i < attempts && backoff < webhookRetryMax; i++ {
+		backoff *= 2
+	}
+	return min(backoff, webhookRetryMax)
+}
@@ -0,0 +1,161 @@
+// Package backup описывает собственный формат резервной копии библиотеки Memoria: коллекции с порядком
+// элементов, заметки, свои элементы пользователя, прогресс, теги и отметки эпизодов. Копия переносится
+// между инстансами без потерь: элементы каталога ссылаются на себя по ID, внешним ID и названию
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	// Format - значение поля format, по нему копия отличается от других JSON-файлов
+	Format = "memoria-backup"
+	// Version - текущая версия формата. Копии более новых версий не принимаются
+	Version = 1
+	// Extension - расширение файла копии
+	Extension = "memoria.json.gz"
+)
+
+var (
+	ErrInvalidArchive     = errors.New("file is not a memoria backup")
+	ErrUnsupportedVersion = fmt.Errorf("backup version is not supported, supported versions are 1..%d", Version)
+)
+
+// Archive - резервная копия библиотеки пользователя
+type Archive struct {
+	Format    string    `json:"format"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	// Collections - коллекции в порядке создания
+	Collections []Collection `json:"collections"`
+	// Items - все элементы, на которые ссылаются коллекции, прогресс и теги
+	Items    []Item     `json:"items"`
+	Progress []Progress `json:"progress"`
+}
+
+type Collection struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Type        string    `json:"type"`
+	IsPublic    bool      `json:"is_public"`
+	CoverImage  *string   `json:"cover_image,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	Tags        []string  `json:"tags,omitempty"`
+	// Items - элементы коллекции в порядке добавления
+	Items []Assignment `json:"items"`
+}
+
+type Assignment struct {
+	ItemID  string    `json:"item_id"`
+	Review  *string   `json:"review,omitempty"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+// Item - элемент каталога или свой элемент пользователя (Custom). Свой элемент восстанавливается
+// со всеми данными, элемент каталога ищется по ID, внешним ID и названию, а если его нет -
+// создается своим элементом по этим же данным
+type Item struct {
+	ID          string       `json:"id"`
+	Custom      bool         `json:"custom"`
+	Type        string       `json:"type"`
+	Title       string       `json:"title"`
+	Description string       `json:"description,omitempty"`
+	CoverImage  *string      `json:"cover_image,omitempty"`
+	ReleaseYear *int         `json:"release_year,omitempty"`
+	ExternalIDs []ExternalID `json:"external_ids,omitempty"`
+	// Genres - слаги жанров
+	Genres []string `json:"genres,omitempty"`
+	// Tags - теги пользователя на элементе
+	Tags      []string  `json:"tags,omitempty"`
+	Seasons   []Season  `json:"seasons,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ExternalID struct {
+	Provider   string `json:"provider"`
+	ExternalID string `json:"external_id"`
+}
+
+type Season struct {
+	Number   int       `json:"number"`
+	Title    *string   `json:"title,omitempty"`
+	Episodes []Episode `json:"episodes,omitempty"`
+}
+
+type Episode struct {
+	Number         int     `json:"number"`
+	Title          *string `json:"title,omitempty"`
+	AirDate        *string `json:"air_date,omitempty"`
+	RuntimeMinutes *int    `json:"runtime_minutes,omitempty"`
+}
+
+type Progress struct {
+	ItemID     string     `json:"item_id"`
+	Status     string     `json:"status"`
+	Progress   int        `json:"progress"`
+	Rating     *int       `json:"rating,omitempty"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"`
+	// Watched - отмеченные эпизоды по номерам сезона и эпизода
+	Watched []WatchedEpisode `json:"watched,omitempty"`
+}
+
+type WatchedEpisode struct {
+	Season    int       `json:"season"`
+	Episode   int       `json:"episode"`
+	WatchedAt time.Time `json:"watched_at"`
+}
+
+// Encode записывает копию в сжатый JSON
+func Encode(w io.Writer, archive *Archive) error {
+	archive.Format = Format
+	archive.Version = Version
+
+	gz := gzip.NewWriter(w)
+	if err := json.NewEncoder(gz).Encode(archive); err != nil {
+		gz.Close()
+		return err
+	}
+	return gz.Close()
+}
+
+// Decode читает копию: сжатый или обычный JSON
+func Decode(r io.Reader, maxSize int64) (*Archive, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b {
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("%w: broken gzip archive", ErrInvalidArchive)
+		}
+		defer gz.Close()
+		if data, err = io.ReadAll(io.LimitReader(gz, maxSize+1)); err != nil {
+			return nil, fmt.Errorf("%w: broken gzip archive", ErrInvalidArchive)
+		}
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("%w: backup is larger than %d bytes", ErrInvalidArchive, maxSize)
+	}
+
+	var archive Archive
+	if err := json.Unmarshal(data, &archive); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+	if archive.Format != Format {
+		return nil, ErrInvalidArchive
+	}
+	if archive.Version < 1 || archive.Version > Version {
+		return nil, ErrUnsupportedVersion
+	}
+	return &archive, nil
+}
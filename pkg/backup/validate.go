@@ -0,0 +1,203 @@
+package backup
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Ограничения совпадают с ограничениями схемы базы и сервисов
+const (
+	MaxCollections       = 1000
+	MaxItems             = 50000
+	maxNameLength        = 100
+	maxTitleLength       = 255
+	maxTagLength         = 50
+	maxTagsPerEntity     = 20
+	maxExternalIDLength  = 100
+	maxProviderLength    = 30
+	maxReportedProblems  = 20
+	minReleaseYear       = 1000
+	maxReleaseYear       = 3000
+	maxEpisodeTitleRunes = 255
+	airDateLayout        = "2006-01-02"
+)
+
+var itemTypes = map[string]bool{"books": true, "anime": true, "series": true, "movies": true}
+
+var progressStatuses = map[string]bool{"planned": true, "in_progress": true, "completed": true, "dropped": true}
+
+// ValidationError - список проблем копии. errors.Is(err, ErrInvalidArchive) для него истинно
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid backup: " + strings.Join(e.Problems, "; ")
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidArchive
+}
+
+type validator struct {
+	problems []string
+	total    int
+}
+
+func (v *validator) addf(format string, args ...any) {
+	v.total++
+	if len(v.problems) < maxReportedProblems {
+		v.problems = append(v.problems, fmt.Sprintf(format, args...))
+	}
+}
+
+// Validate проверяет копию целиком до восстановления: типы и статусы, длины, диапазоны
+// и ссылки коллекций и прогресса на элементы копии
+func (a *Archive) Validate() error {
+	v := &validator{}
+	if len(a.Collections) > MaxCollections {
+		v.addf("too many collections: %d, at most %d", len(a.Collections), MaxCollections)
+	}
+	if len(a.Items) > MaxItems {
+		v.addf("too many items: %d, at most %d", len(a.Items), MaxItems)
+	}
+
+	items := make(map[string]bool, len(a.Items))
+	for i, item := range a.Items {
+		where := fmt.Sprintf("items[%d]", i)
+		if item.ID == "" {
+			v.addf("%s: id is required", where)
+		} else if items[item.ID] {
+			v.addf("%s: duplicate id %s", where, item.ID)
+		}
+		items[item.ID] = true
+
+		if !itemTypes[item.Type] {
+			v.addf("%s: unknown type %q", where, item.Type)
+		}
+		v.text(where+".title", item.Title, maxTitleLength)
+		if item.ReleaseYear != nil && (*item.ReleaseYear < minReleaseYear || *item.ReleaseYear > maxReleaseYear) {
+			v.addf("%s: release_year %d is out of range", where, *item.ReleaseYear)
+		}
+		for _, externalID := range item.ExternalIDs {
+			if externalID.Provider == "" || len(externalID.Provider) > maxProviderLength ||
+				externalID.ExternalID == "" || len(externalID.ExternalID) > maxExternalIDLength {
+				v.addf("%s: invalid external id %s/%s", where, externalID.Provider, externalID.ExternalID)
+			}
+		}
+		v.tags(where, item.Tags)
+
+		seasons := map[int]bool{}
+		for _, season := range item.Seasons {
+			if season.Number < 0 || seasons[season.Number] {
+				v.addf("%s: invalid or duplicate season %d", where, season.Number)
+			}
+			seasons[season.Number] = true
+			if season.Title != nil && utf8.RuneCountInString(*season.Title) > maxEpisodeTitleRunes {
+				v.addf("%s: season %d title is too long", where, season.Number)
+			}
+			episodes := map[int]bool{}
+			for _, episode := range season.Episodes {
+				if episode.Number < 1 || episodes[episode.Number] {
+					v.addf("%s: invalid or duplicate episode %d of season %d", where, episode.Number, season.Number)
+				}
+				episodes[episode.Number] = true
+				if episode.Title != nil && utf8.RuneCountInString(*episode.Title) > maxEpisodeTitleRunes {
+					v.addf("%s: episode %d title is too long", where, episode.Number)
+				}
+				if episode.AirDate != nil {
+					if _, err := time.Parse(airDateLayout, *episode.AirDate); err != nil {
+						v.addf("%s: episode %d air_date must be YYYY-MM-DD", where, episode.Number)
+					}
+				}
+				if episode.RuntimeMinutes != nil && *episode.RuntimeMinutes < 1 {
+					v.addf("%s: episode %d runtime must be positive", where, episode.Number)
+				}
+			}
+		}
+	}
+
+	collections := make(map[string]bool, len(a.Collections))
+	for i, collection := range a.Collections {
+		where := fmt.Sprintf("collections[%d]", i)
+		if collection.ID == "" {
+			v.addf("%s: id is required", where)
+		} else if collections[collection.ID] {
+			v.addf("%s: duplicate id %s", where, collection.ID)
+		}
+		collections[collection.ID] = true
+
+		v.text(where+".name", collection.Name, maxNameLength)
+		if collection.Type != "" && !itemTypes[collection.Type] {
+			v.addf("%s: unknown type %q", where, collection.Type)
+		}
+		v.tags(where, collection.Tags)
+
+		assigned := make(map[string]bool, len(collection.Items))
+		for _, assignment := range collection.Items {
+			if !items[assignment.ItemID] {
+				v.addf("%s: item %s is not in the backup", where, assignment.ItemID)
+			}
+			if assigned[assignment.ItemID] {
+				v.addf("%s: item %s is added twice", where, assignment.ItemID)
+			}
+			assigned[assignment.ItemID] = true
+		}
+	}
+
+	progress := make(map[string]bool, len(a.Progress))
+	for i, p := range a.Progress {
+		where := fmt.Sprintf("progress[%d]", i)
+		if !items[p.ItemID] {
+			v.addf("%s: item %s is not in the backup", where, p.ItemID)
+		}
+		if progress[p.ItemID] {
+			v.addf("%s: duplicate progress of item %s", where, p.ItemID)
+		}
+		progress[p.ItemID] = true
+
+		if !progressStatuses[p.Status] {
+			v.addf("%s: unknown status %q", where, p.Status)
+		}
+		if p.Progress < 0 {
+			v.addf("%s: progress must not be negative", where)
+		}
+		if p.Rating != nil && (*p.Rating < 1 || *p.Rating > 10) {
+			v.addf("%s: rating must be between 1 and 10", where)
+		}
+		for _, watched := range p.Watched {
+			if watched.Season < 0 || watched.Episode < 1 {
+				v.addf("%s: invalid watched episode S%dE%d", where, watched.Season, watched.Episode)
+			}
+		}
+	}
+
+	if v.total == 0 {
+		return nil
+	}
+	if v.total > len(v.problems) {
+		v.problems = append(v.problems, fmt.Sprintf("and %d more", v.total-len(v.problems)))
+	}
+	return &ValidationError{Problems: v.problems}
+}
+
+func (v *validator) text(where string, value string, limit int) {
+	if strings.TrimSpace(value) == "" {
+		v.addf("%s is required", where)
+	} else if utf8.RuneCountInString(value) > limit {
+		v.addf("%s is longer than %d characters", where, limit)
+	}
+}
+
+func (v *validator) tags(where string, tags []string) {
+	if len(tags) > maxTagsPerEntity {
+		v.addf("%s: too many tags", where)
+	}
+	for _, tag := range tags {
+		if strings.TrimSpace(tag) == "" || utf8.RuneCountInString(tag) > maxTagLength {
+			v.addf("%s: invalid tag %q", where, tag)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Package blobstore - хранилище файлов (обложек, аватаров) с доступом по подписанным ссылкам
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNotFound возвращается, когда объекта с таким ключом нет
+	ErrNotFound = errors.New("blob not found")
+	// ErrInvalidKey возвращается для ключей с обходом каталогов и абсолютных путей
+	ErrInvalidKey = errors.New("invalid blob key")
+	// ErrInvalidSignature возвращается для неверной или просроченной подписи ссылки
+	ErrInvalidSignature = errors.New("invalid or expired signature")
+)
+
+// Object - содержимое объекта. Body нужно закрыть после чтения
+type Object struct {
+	Body        io.ReadCloser
+	ContentType string
+	Size        int64
+}
+
+// BlobStore - хранилище объектов по ключу вида "covers/<id>/original.jpg"
+type BlobStore interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Get(ctx context.Context, key string) (*Object, error)
+	Delete(ctx context.Context, key string) error
+	// SignedURL возвращает ссылку на чтение объекта, действующую ttl
+	SignedURL(key string, ttl time.Duration) (string, error)
+}
+
+// SignatureVerifier реализуют хранилища, чьи подписанные ссылки отдает само приложение
+type SignatureVerifier interface {
+	VerifySignature(key string, expires string, signature string) error
+}
+
+type Config struct {
+	// Driver - "local" или "s3"
+	Driver string
+	Local  LocalConfig
+	S3     S3Config
+}
+
+func New(cfg Config) (BlobStore, error) {
+	switch cfg.Driver {
+	case "", "local":
+		return NewLocalStore(cfg.Local)
+	case "s3":
+		return NewS3Store(cfg.S3)
+	default:
+		return nil, fmt.Errorf("unknown blob store driver %q", cfg.Driver)
+	}
+}
+
+// validateKey допускает только относительные нормализованные пути без ".."
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key {
+		return ErrInvalidKey
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == ".." || part == "." {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestValidateKey(t *testing.T) {
+	tests := []struct {
+		key   string
+		valid bool
+	}{
+		{"images/item/1/original.jpg", true},
+		{"avatar.png", true},
+		{"a..b/c", true},
+		{"", false},
+		{"/etc/passwd", false},
+		{"../secret", false},
+		{"images/../../secret", false},
+		{"images/..", false},
+		{"./images/a.jpg", false},
+		{"images/./a.jpg", false},
+		{"images//a.jpg", false},
+		{"images/", false},
+	}
+	for _, tt := range tests {
+		err := validateKey(tt.key)
+		if tt.valid && err != nil {
+			t.Errorf("validateKey(%q) = %v, want nil", tt.key, err)
+		}
+		if !tt.valid && !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("validateKey(%q) = %v, want ErrInvalidKey", tt.key, err)
+		}
+	}
+}
+
+// testStore проверяет общий контракт BlobStore: запись, чтение, удаление и отказ для ключей с обходом каталогов
+func testStore(t *testing.T, store BlobStore) {
+	t.Helper()
+	ctx := context.Background()
+	key := "images/item/cover/original.png"
+	data := []byte("\x89PNG\r\n\x1a\nfake image body")
+
+	if err := store.Put(ctx, key, data, "image/png"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	object, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	body, err := io.ReadAll(object.Body)
+	object.Body.Close()
+	if err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+	if !bytes.Equal(body, data) {
+		t.Errorf("Get body = %q, want %q", body, data)
+	}
+	if object.ContentType != "image/png" {
+		t.Errorf("Get content type = %q, want image/png", object.ContentType)
+	}
+	if object.Size != int64(len(data)) {
+		t.Errorf("Get size = %d, want %d", object.Size, len(data))
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete = %v, want ErrNotFound", err)
+	}
+	if err := store.Delete(ctx, key); err != nil {
+		t.Errorf("Delete of missing key = %v, want nil", err)
+	}
+
+	for _, bad := range []string{"../outside.png", "/abs.png", "images/../../outside.png"} {
+		if err := store.Put(ctx, bad, data, "image/png"); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Put(%q) = %v, want ErrInvalidKey", bad, err)
+		}
+		if _, err := store.Get(ctx, bad); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Get(%q) = %v, want ErrInvalidKey", bad, err)
+		}
+		if err := store.Delete(ctx, bad); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Delete(%q) = %v, want ErrInvalidKey", bad, err)
+		}
+		if _, err := store.SignedURL(bad, 0); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("SignedURL(%q) = %v, want ErrInvalidKey", bad, err)
+		}
+	}
+}
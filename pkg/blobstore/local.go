@@ -0,0 +1,160 @@
+package blobstore
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+type LocalConfig struct {
+	// Root - каталог с файлами
+	Root string
+	// BaseURL - адрес, по которому приложение отдает файлы: BaseURL + "/" + key
+	BaseURL string
+	// Secret - ключ подписи ссылок. Если пуст - случайный на каждый запуск
+	Secret string
+}
+
+// LocalStore хранит объекты в каталоге на диске. Ссылки подписываются HMAC и проверяются приложением
+type LocalStore struct {
+	root    string
+	baseURL string
+	secret  []byte
+}
+
+func NewLocalStore(cfg LocalConfig) (*LocalStore, error) {
+	if cfg.Root == "" {
+		return nil, errors.New("local blob store root is not set")
+	}
+	if err := os.MkdirAll(cfg.Root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create blob store root: %w", err)
+	}
+
+	secret := []byte(cfg.Secret)
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("failed to generate signing secret: %w", err)
+		}
+	}
+
+	return &LocalStore{
+		root:    cfg.Root,
+		baseURL: cfg.BaseURL,
+		secret:  secret,
+	}, nil
+}
+
+func (s *LocalStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	filename, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+		return fmt.Errorf("failed to create blob directory: %w", err)
+	}
+
+	// Запись во временный файл и переименование: читатели не видят недописанный объект
+	tmp, err := os.CreateTemp(filepath.Dir(filename), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write blob: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write blob: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filename); err != nil {
+		return fmt.Errorf("failed to save blob: %w", err)
+	}
+	return nil
+}
+
+func (s *LocalStore) Get(ctx context.Context, key string) (*Object, error) {
+	filename, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to open blob: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to stat blob: %w", err)
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return &Object{Body: file, ContentType: contentType, Size: info.Size()}, nil
+}
+
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	filename, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filename); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete blob: %w", err)
+	}
+	return nil
+}
+
+// SignedURL - ссылка вида BaseURL/key?expires=<unix>&signature=<hmac>
+func (s *LocalStore) SignedURL(key string, ttl time.Duration) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	expires := strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", s.sign(key, expires))
+	return fmt.Sprintf("%s/%s?%s", s.baseURL, (&url.URL{Path: key}).EscapedPath(), query.Encode()), nil
+}
+
+func (s *LocalStore) VerifySignature(key string, expires string, signature string) error {
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > unix {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.sign(key, expires))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func (s *LocalStore) sign(key string, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *LocalStore) path(key string) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
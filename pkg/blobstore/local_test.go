@@ -0,0 +1,106 @@
+package blobstore
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestLocalStore(t *testing.T) (*LocalStore, string) {
+	t.Helper()
+	root := filepath.Join(t.TempDir(), "blobs")
+	store, err := NewLocalStore(LocalConfig{Root: root, BaseURL: "http://api.test/files", Secret: "test-secret"})
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+	return store, root
+}
+
+func TestLocalStore(t *testing.T) {
+	store, _ := newTestLocalStore(t)
+	testStore(t, store)
+}
+
+func TestLocalStoreStaysInsideRoot(t *testing.T) {
+	store, root := newTestLocalStore(t)
+	ctx := t.Context()
+
+	if err := store.Put(ctx, "../escaped.txt", []byte("x"), "text/plain"); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("Put outside root = %v, want ErrInvalidKey", err)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(root), "escaped.txt")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("file outside root was created: %v", err)
+	}
+
+	if err := store.Put(ctx, "a/b/c.txt", []byte("x"), "text/plain"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "a", "b", "c.txt")); err != nil {
+		t.Errorf("nested file is not under root: %v", err)
+	}
+	leftovers, _ := filepath.Glob(filepath.Join(root, "a", "b", ".upload-*"))
+	if len(leftovers) > 0 {
+		t.Errorf("temp files left after Put: %v", leftovers)
+	}
+}
+
+func TestLocalStoreSignedURL(t *testing.T) {
+	store, _ := newTestLocalStore(t)
+	key := "images/item/1/original.jpg"
+
+	signed, err := store.SignedURL(key, time.Minute)
+	if err != nil {
+		t.Fatalf("SignedURL: %v", err)
+	}
+	if !strings.HasPrefix(signed, "http://api.test/files/"+key+"?") {
+		t.Fatalf("SignedURL = %q, want BaseURL/key?...", signed)
+	}
+	u, err := url.Parse(signed)
+	if err != nil {
+		t.Fatalf("parse signed url: %v", err)
+	}
+	expires, signature := u.Query().Get("expires"), u.Query().Get("signature")
+
+	if err := store.VerifySignature(key, expires, signature); err != nil {
+		t.Errorf("VerifySignature of issued url = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name      string
+		key       string
+		expires   string
+		signature string
+	}{
+		{"other key", "images/item/2/original.jpg", expires, signature},
+		{"tampered expires", key, expires + "0", signature},
+		{"tampered signature", key, expires, strings.Repeat("0", len(signature))},
+		{"empty signature", key, expires, ""},
+		{"bad expires", key, "soon", signature},
+	}
+	for _, tt := range tests {
+		if err := store.VerifySignature(tt.key, tt.expires, tt.signature); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%s: VerifySignature = %v, want ErrInvalidSignature", tt.name, err)
+		}
+	}
+
+	expired, err := store.SignedURL(key, -time.Minute)
+	if err != nil {
+		t.Fatalf("SignedURL: %v", err)
+	}
+	u, _ = url.Parse(expired)
+	if err := store.VerifySignature(key, u.Query().Get("expires"), u.Query().Get("signature")); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("VerifySignature of expired url = %v, want ErrInvalidSignature", err)
+	}
+
+	other, err := NewLocalStore(LocalConfig{Root: t.TempDir(), Secret: "other-secret"})
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+	if err := other.VerifySignature(key, expires, signature); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("VerifySignature with other secret = %v, want ErrInvalidSignature", err)
+	}
+}
@@ -0,0 +1,312 @@
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	sigV4Algorithm  = "AWS4-HMAC-SHA256"
+	sigV4Service    = "s3"
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+	amzDateFormat   = "20060102T150405Z"
+	// Максимальный срок действия presigned-ссылки в S3
+	maxPresignTTL = 7 * 24 * time.Hour
+)
+
+type S3Config struct {
+	// Endpoint - адрес S3-совместимого сервиса, например https://s3.eu-central-1.amazonaws.com или http://localhost:9000
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// PathStyle - адресация endpoint/bucket/key вместо bucket.endpoint/key (нужна MinIO и аналогам)
+	PathStyle bool
+}
+
+// S3Store хранит объекты в S3-совместимом хранилище. Запросы подписываются AWS Signature V4
+type S3Store struct {
+	endpoint *url.URL
+	cfg      S3Config
+	client   *http.Client
+	now      func() time.Time
+}
+
+func NewS3Store(cfg S3Config) (*S3Store, error) {
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", cfg.Endpoint)
+	}
+	if cfg.Bucket == "" || cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return nil, errors.New("s3 bucket and credentials are required")
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+
+	return &S3Store{
+		endpoint: endpoint,
+		cfg:      cfg,
+		client:   &http.Client{Timeout: 30 * time.Second},
+		now:      time.Now,
+	}, nil
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	req, err := s.newRequest(ctx, http.MethodPut, key, data)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.ContentLength = int64(len(data))
+
+	resp, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *S3Store) Get(ctx context.Context, key string) (*Object, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		return nil, err
+	}
+	return &Object{
+		Body:        resp.Body,
+		ContentType: resp.Header.Get("Content-Type"),
+		Size:        resp.ContentLength,
+	}, nil
+}
+
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// SignedURL - presigned GET-ссылка: подпись передается в query, тело не подписывается
+func (s *S3Store) SignedURL(key string, ttl time.Duration) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	if ttl > maxPresignTTL {
+		ttl = maxPresignTTL
+	}
+
+	now := s.now().UTC()
+	objectURL := s.objectURL(key)
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", sigV4Algorithm)
+	query.Set("X-Amz-Credential", s.cfg.AccessKey+"/"+s.scope(now))
+	query.Set("X-Amz-Date", now.Format(amzDateFormat))
+	query.Set("X-Amz-Expires", strconv.Itoa(int(ttl.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+	objectURL.RawQuery = canonicalQuery(query)
+
+	headers := http.Header{}
+	headers.Set("Host", objectURL.Host)
+	signature := s.signature(now, http.MethodGet, objectURL, headers, unsignedPayload)
+
+	objectURL.RawQuery += "&X-Amz-Signature=" + signature
+	return objectURL.String(), nil
+}
+
+func (s *S3Store) newRequest(ctx context.Context, method string, key string, body []byte) (*http.Request, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+
+	objectURL := s.objectURL(key)
+	req, err := http.NewRequestWithContext(ctx, method, objectURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build s3 request: %w", err)
+	}
+
+	now := s.now().UTC()
+	payloadHash := sha256Hex(body)
+	req.Header.Set("Host", objectURL.Host)
+	req.Header.Set("X-Amz-Date", now.Format(amzDateFormat))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signed := http.Header{}
+	for _, name := range []string{"Host", "X-Amz-Content-Sha256", "X-Amz-Date"} {
+		signed.Set(name, req.Header.Get(name))
+	}
+	signature := s.signature(now, method, objectURL, signed, payloadHash)
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algorithm, s.cfg.AccessKey, s.scope(now), signedHeaderNames(signed), signature))
+	return req, nil
+}
+
+// do выполняет запрос и переводит ответы с ошибкой в error. Body успешного ответа закрывает вызывающий
+func (s *S3Store) do(req *http.Request) (*http.Response, error) {
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("s3 request failed: %w", err)
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return nil, fmt.Errorf("s3 %s %s: %s: %s", req.Method, req.URL.Path, resp.Status, strings.TrimSpace(string(body)))
+}
+
+func (s *S3Store) objectURL(key string) *url.URL {
+	objectURL := *s.endpoint
+	basePath := strings.TrimSuffix(objectURL.Path, "/")
+	if s.cfg.PathStyle {
+		objectURL.Path = basePath + "/" + s.cfg.Bucket + "/" + key
+	} else {
+		objectURL.Host = s.cfg.Bucket + "." + objectURL.Host
+		objectURL.Path = basePath + "/" + key
+	}
+	objectURL.RawPath = canonicalURI(objectURL.Path)
+	objectURL.RawQuery = ""
+	return &objectURL
+}
+
+func (s *S3Store) scope(now time.Time) string {
+	return strings.Join([]string{now.Format("20060102"), s.cfg.Region, sigV4Service, "aws4_request"}, "/")
+}
+
+// signature считает подпись SigV4: canonical request -> string to sign -> HMAC производным ключом
+func (s *S3Store) signature(now time.Time, method string, u *url.URL, headers http.Header, payloadHash string) string {
+	canonicalRequest := strings.Join([]string{
+		method,
+		canonicalURI(u.Path),
+		u.RawQuery,
+		canonicalHeaders(headers),
+		signedHeaderNames(headers),
+		payloadHash,
+	}, "\n")
+
+	stringToSign := strings.Join([]string{
+		sigV4Algorithm,
+		now.Format(amzDateFormat),
+		s.scope(now),
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretKey), now.Format("20060102"))
+	key = hmacSHA256(key, s.cfg.Region)
+	key = hmacSHA256(key, sigV4Service)
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// canonicalURI кодирует каждый сегмент пути, сохраняя "/"
+func canonicalURI(p string) string {
+	if p == "" {
+		return "/"
+	}
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		segments[i] = uriEncode(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// canonicalQuery - параметры, отсортированные по имени, с кодированием по правилам SigV4
+func canonicalQuery(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		for _, value := range values[key] {
+			parts = append(parts, uriEncode(key)+"="+uriEncode(value))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+func canonicalHeaders(headers http.Header) string {
+	var b strings.Builder
+	for _, name := range sortedHeaderNames(headers) {
+		b.WriteString(name)
+		b.WriteByte(':')
+		b.WriteString(strings.TrimSpace(headers.Get(name)))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func signedHeaderNames(headers http.Header) string {
+	return strings.Join(sortedHeaderNames(headers), ";")
+}
+
+func sortedHeaderNames(headers http.Header) []string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, strings.ToLower(name))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// uriEncode кодирует все, кроме незарезервированных символов A-Z a-z 0-9 - _ . ~
+func uriEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,86 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// CSV - одна строка на элемент коллекции, коллекция указывается в первых колонках.
+// Файл начинается с BOM, чтобы Excel правильно открывал кириллицу
+type CSV struct{}
+
+func (CSV) Format() string      { return "csv" }
+func (CSV) ContentType() string { return "text/csv; charset=utf-8" }
+func (CSV) Extension() string   { return "csv" }
+
+var csvHeader = []string{
+	"collection", "collection_type", "title", "type", "release_year", "status", "progress", "rating",
+	"started_at", "finished_at", "added_at", "genres", "tags", "note", "item_id",
+}
+
+func (CSV) NewWriter(w io.Writer, doc Document) (Writer, error) {
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return nil, err
+	}
+	writer := &csvWriter{csv: csv.NewWriter(w)}
+	if err := writer.csv.Write(csvHeader); err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+type csvWriter struct {
+	csv        *csv.Writer
+	collection Collection
+}
+
+func (w *csvWriter) StartCollection(collection Collection) error {
+	w.collection = collection
+	return nil
+}
+
+func (w *csvWriter) WriteEntry(entry Entry) error {
+	var year, rating, progress string
+	if entry.ReleaseYear != nil {
+		year = strconv.Itoa(*entry.ReleaseYear)
+	}
+	if entry.Rating != nil {
+		rating = strconv.Itoa(*entry.Rating)
+	}
+	if entry.Status != "" {
+		progress = strconv.Itoa(entry.Progress)
+	}
+	return w.csv.Write([]string{
+		csvText(w.collection.Name),
+		w.collection.Type,
+		csvText(entry.Title),
+		entry.Type,
+		year,
+		entry.Status,
+		progress,
+		rating,
+		formatDate(entry.StartedAt),
+		formatDate(entry.FinishedAt),
+		entry.AddedAt.Format(dateLayout),
+		csvText(strings.Join(entry.Genres, ", ")),
+		csvText(strings.Join(entry.Tags, ", ")),
+		csvText(entry.Note),
+		entry.ItemID,
+	})
+}
+
+// csvText защищает от формул в ячейках (CSV injection): текст пользователя, начинающийся с =, +, -, @,
+// табуляции или перевода строки, Excel и LibreOffice выполнят как формулу. Апостроф делает ячейку текстом
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func (w *csvWriter) Close() error {
+	w.csv.Flush()
+	return w.csv.Error()
+}
@@ -0,0 +1,84 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCSVText(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"Dune", "Dune"},
+		{"", ""},
+		{"=HYPERLINK(\"http://evil.test\",\"click\")", "'=HYPERLINK(\"http://evil.test\",\"click\")"},
+		{"+1+cmd|' /C calc'!A0", "'+1+cmd|' /C calc'!A0"},
+		{"-2+3", "'-2+3"},
+		{"@SUM(A1:A2)", "'@SUM(A1:A2)"},
+		{"\t=1+1", "'\t=1+1"},
+		{"\r=1+1", "'\r=1+1"},
+		{"a=1", "a=1"},
+		{"Spider-Man", "Spider-Man"},
+	}
+	for _, tt := range tests {
+		if got := csvText(tt.value); got != tt.want {
+			t.Errorf("csvText(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestCSVWriterEscapesFormulas(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := CSV{}.NewWriter(&buf, Document{})
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	if err := writer.StartCollection(Collection{Name: "=cmd", Type: "books"}); err != nil {
+		t.Fatalf("StartCollection: %v", err)
+	}
+	err = writer.WriteEntry(Entry{
+		ItemID:  "item-1",
+		Type:    "books",
+		Title:   "@title",
+		Genres:  []string{"-genre"},
+		Tags:    []string{"+tag", "=other"},
+		Note:    "=note",
+		AddedAt: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("WriteEntry: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(buf.String(), "\ufeff"))).ReadAll()
+	if err != nil {
+		t.Fatalf("read csv: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want header and one entry", len(records))
+	}
+	row := map[string]string{}
+	for i, column := range records[0] {
+		row[column] = records[1][i]
+	}
+	want := map[string]string{
+		"collection": "'=cmd",
+		"title":      "'@title",
+		"genres":     "'-genre",
+		"tags":       "'+tag, =other",
+		"note":       "'=note",
+		"added_at":   "2026-01-02",
+		"item_id":    "item-1",
+	}
+	for column, value := range want {
+		if row[column] != value {
+			t.Errorf("column %s = %q, want %q", column, row[column], value)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+// Package exporter сериализует коллекции пользователя в файлы для использования вне приложения.
+// Форматы подключаются через Registry, запись идет потоково: коллекция за коллекцией, элемент за элементом
+package exporter
+
+import (
+	"io"
+	"sort"
+	"time"
+)
+
+// Document - заголовок выгрузки
+type Document struct {
+	Title       string    `json:"title"`
+	Owner       string    `json:"owner"`
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// Collection - коллекция, записи которой следуют за ней
+type Collection struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Type        string    `json:"type"`
+	IsPublic    bool      `json:"is_public"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Entry - элемент коллекции с заметкой и прогрессом владельца коллекции
+type Entry struct {
+	ItemID      string   `json:"item_id"`
+	Type        string   `json:"type"`
+	Title       string   `json:"title"`
+	ReleaseYear *int     `json:"release_year,omitempty"`
+	Description string   `json:"description,omitempty"`
+	CoverImage  string   `json:"cover_image,omitempty"`
+	Genres      []string `json:"genres,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	// Note - заметка к элементу в этой коллекции
+	Note       string     `json:"note,omitempty"`
+	Status     string     `json:"status,omitempty"`
+	Progress   int        `json:"progress,omitempty"`
+	Rating     *int       `json:"rating,omitempty"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	AddedAt    time.Time  `json:"added_at"`
+}
+
+// Writer записывает один документ
+type Writer interface {
+	// StartCollection начинает раздел коллекции, следующие записи относятся к ней
+	StartCollection(collection Collection) error
+	WriteEntry(entry Entry) error
+	// Close дописывает окончание документа. Writer не закрывает нижележащий io.Writer
+	Close() error
+}
+
+// Exporter - формат выгрузки
+type Exporter interface {
+	// Format - имя формата в запросе (?format=csv)
+	Format() string
+	ContentType() string
+	Extension() string
+	NewWriter(w io.Writer, doc Document) (Writer, error)
+}
+
+// Registry - подключенные форматы выгрузки
+type Registry struct {
+	byFormat map[string]Exporter
+}
+
+func NewRegistry(exporters ...Exporter) *Registry {
+	r := &Registry{byFormat: map[string]Exporter{}}
+	for _, exporter := range exporters {
+		r.Register(exporter)
+	}
+	return r
+}
+
+// NewDefaultRegistry - реестр со встроенными форматами csv, json, md и html
+func NewDefaultRegistry() *Registry {
+	return NewRegistry(CSV{}, JSON{}, Markdown{}, HTML{})
+}
+
+// Register добавляет формат, формат с тем же именем заменяется
+func (r *Registry) Register(exporter Exporter) {
+	r.byFormat[exporter.Format()] = exporter
+}
+
+func (r *Registry) Get(format string) (Exporter, bool) {
+	exporter, ok := r.byFormat[format]
+	return exporter, ok
+}
+
+// Formats - имена подключенных форматов по алфавиту
+func (r *Registry) Formats() []string {
+	formats := make([]string, 0, len(r.byFormat))
+	for format := range r.byFormat {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// statusLabels - подписи статусов для форматов, которые читает человек
+var statusLabels = map[string]string{
+	"planned":     "В планах",
+	"in_progress": "В процессе",
+	"completed":   "Завершено",
+	"dropped":     "Брошено",
+}
+
+func statusLabel(status string) string {
+	if label, ok := statusLabels[status]; ok {
+		return label
+	}
+	return status
+}
+
+const dateLayout = "2006-01-02"
+
+func formatDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(dateLayout)
+}
@@ -0,0 +1,122 @@
+package exporter
+
+import (
+	"html/template"
+	"io"
+)
+
+// HTML - страница для печати: таблица на коллекцию, стили встроены, внешних ресурсов нет
+type HTML struct{}
+
+func (HTML) Format() string      { return "html" }
+func (HTML) ContentType() string { return "text/html; charset=utf-8" }
+func (HTML) Extension() string   { return "html" }
+
+// htmlTemplates - части страницы, которые пишутся по мере чтения коллекций
+var htmlTemplates = template.Must(template.New("").Funcs(template.FuncMap{
+	"status": statusLabel,
+	"date":   formatDate,
+}).Parse(`
+{{define "header"}}<!DOCTYPE html>
+<html lang="ru">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: Georgia, "Times New Roman", serif; color: #222; max-width: 960px; margin: 2em auto; padding: 0 1em; }
+h1 { margin-bottom: 0; }
+.meta { color: #777; margin-top: .3em; }
+section { margin-top: 2em; }
+table { width: 100%; border-collapse: collapse; font-size: 14px; }
+th, td { text-align: left; vertical-align: top; padding: 6px 8px; border-bottom: 1px solid #ddd; }
+th { border-bottom: 2px solid #999; }
+.year, .rating { white-space: nowrap; }
+.note { color: #555; font-style: italic; white-space: pre-line; }
+.tags { color: #777; font-size: 12px; }
+.empty { color: #999; font-style: italic; }
+@media print {
+  body { margin: 0; max-width: none; }
+  section { break-inside: avoid-page; }
+  tr { break-inside: avoid; }
+}
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="meta">{{.Owner}}, {{.GeneratedAt.Format "02.01.2006"}}</p>
+{{end}}
+
+{{define "collection"}}<section>
+<h2>{{.Name}}</h2>
+{{with .Description}}<p>{{.}}</p>
+{{end}}<table>
+<thead><tr><th>Название</th><th>Год</th><th>Статус</th><th>Оценка</th><th>Заметка</th></tr></thead>
+<tbody>
+{{end}}
+
+{{define "entry"}}<tr>
+<td>{{.Title}}{{with .Tags}}<div class="tags">{{range $i, $tag := .}}{{if $i}}, {{end}}#{{$tag}}{{end}}</div>{{end}}</td>
+<td class="year">{{with .ReleaseYear}}{{.}}{{end}}</td>
+<td>{{status .Status}}{{with date .FinishedAt}}<br>{{.}}{{end}}</td>
+<td class="rating">{{with .Rating}}{{.}}/10{{end}}</td>
+<td class="note">{{.Note}}</td>
+</tr>
+{{end}}
+
+{{define "empty"}}<tr><td colspan="5" class="empty">Коллекция пуста</td></tr>
+{{end}}
+
+{{define "collection_end"}}</tbody>
+</table>
+</section>
+{{end}}
+
+{{define "footer"}}</body>
+</html>
+{{end}}
+`))
+
+func (HTML) NewWriter(w io.Writer, doc Document) (Writer, error) {
+	if err := htmlTemplates.ExecuteTemplate(w, "header", doc); err != nil {
+		return nil, err
+	}
+	return &htmlWriter{w: w, entries: -1}, nil
+}
+
+type htmlWriter struct {
+	w io.Writer
+	// entries - записей в текущей коллекции, -1 до первой коллекции
+	entries int
+}
+
+func (w *htmlWriter) StartCollection(collection Collection) error {
+	if err := w.closeCollection(); err != nil {
+		return err
+	}
+	w.entries = 0
+	return htmlTemplates.ExecuteTemplate(w.w, "collection", collection)
+}
+
+func (w *htmlWriter) WriteEntry(entry Entry) error {
+	w.entries++
+	return htmlTemplates.ExecuteTemplate(w.w, "entry", entry)
+}
+
+func (w *htmlWriter) Close() error {
+	if err := w.closeCollection(); err != nil {
+		return err
+	}
+	return htmlTemplates.ExecuteTemplate(w.w, "footer", nil)
+}
+
+func (w *htmlWriter) closeCollection() error {
+	if w.entries < 0 {
+		return nil
+	}
+	if w.entries == 0 {
+		if err := htmlTemplates.ExecuteTemplate(w.w, "empty", nil); err != nil {
+			return err
+		}
+	}
+	return htmlTemplates.ExecuteTemplate(w.w, "collection_end", nil)
+}
@@ -0,0 +1,71 @@
+package exporter
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// JSON - документ {"title", "owner", "generated_at", "collections": [{..., "items": [...]}]}.
+// Пишется по частям, поэтому размер выгрузки не ограничен памятью
+type JSON struct{}
+
+func (JSON) Format() string      { return "json" }
+func (JSON) ContentType() string { return "application/json; charset=utf-8" }
+func (JSON) Extension() string   { return "json" }
+
+func (JSON) NewWriter(w io.Writer, doc Document) (Writer, error) {
+	header, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	// Открываем объект документа без закрывающей скобки и дописываем массив коллекций
+	if _, err := w.Write(append(header[:len(header)-1], `,"collections":[`...)); err != nil {
+		return nil, err
+	}
+	return &jsonWriter{w: w}, nil
+}
+
+type jsonWriter struct {
+	w           io.Writer
+	collections int
+	entries     int
+}
+
+func (w *jsonWriter) StartCollection(collection Collection) error {
+	data, err := json.Marshal(collection)
+	if err != nil {
+		return err
+	}
+	prefix := "\n"
+	if w.collections > 0 {
+		prefix = "]},\n"
+	}
+	w.collections++
+	w.entries = 0
+
+	data = append([]byte(prefix), data[:len(data)-1]...)
+	_, err = w.w.Write(append(data, `,"items":[`...))
+	return err
+}
+
+func (w *jsonWriter) WriteEntry(entry Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if w.entries > 0 {
+		data = append([]byte{','}, data...)
+	}
+	w.entries++
+	_, err = w.w.Write(data)
+	return err
+}
+
+func (w *jsonWriter) Close() error {
+	end := "]}\n"
+	if w.collections > 0 {
+		end = "]}\n]}\n"
+	}
+	_, err := io.WriteString(w.w, end)
+	return err
+}
@@ -0,0 +1,99 @@
+package exporter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Markdown - список для README, заметок и мессенджеров: раздел на коллекцию, пункт на элемент
+type Markdown struct{}
+
+func (Markdown) Format() string      { return "md" }
+func (Markdown) ContentType() string { return "text/markdown; charset=utf-8" }
+func (Markdown) Extension() string   { return "md" }
+
+func (Markdown) NewWriter(w io.Writer, doc Document) (Writer, error) {
+	writer := &markdownWriter{w: bufio.NewWriter(w), entries: -1}
+	_, err := fmt.Fprintf(writer.w, "# %s\n\n_%s, %s_\n",
+		markdownEscape(doc.Title), markdownEscape(doc.Owner), doc.GeneratedAt.Format(dateLayout))
+	return writer, err
+}
+
+// markdownWriter буферизует вывод, bufio.Writer запоминает первую ошибку записи и возвращает ее дальше
+type markdownWriter struct {
+	w *bufio.Writer
+	// entries - записей в текущей коллекции, -1 до первой коллекции
+	entries int
+}
+
+func (w *markdownWriter) StartCollection(collection Collection) error {
+	var b strings.Builder
+	w.closeCollection(&b)
+	w.entries = 0
+
+	b.WriteString("\n## " + markdownEscape(collection.Name) + "\n\n")
+	if description := strings.TrimSpace(collection.Description); description != "" {
+		b.WriteString(markdownEscape(description) + "\n\n")
+	}
+	_, err := w.w.WriteString(b.String())
+	return err
+}
+
+func (w *markdownWriter) WriteEntry(entry Entry) error {
+	w.entries++
+
+	var b strings.Builder
+	b.WriteString("- **" + markdownEscape(entry.Title) + "**")
+	if entry.ReleaseYear != nil {
+		b.WriteString(" (" + strconv.Itoa(*entry.ReleaseYear) + ")")
+	}
+	var details []string
+	if entry.Status != "" {
+		details = append(details, statusLabel(entry.Status))
+	}
+	if entry.Rating != nil {
+		details = append(details, fmt.Sprintf("%d/10", *entry.Rating))
+	}
+	if len(entry.Tags) > 0 {
+		details = append(details, "#"+strings.Join(entry.Tags, " #"))
+	}
+	if len(details) > 0 {
+		b.WriteString(" — " + markdownEscape(strings.Join(details, " · ")))
+	}
+	b.WriteString("\n")
+
+	if note := strings.TrimSpace(entry.Note); note != "" {
+		for _, line := range strings.Split(note, "\n") {
+			b.WriteString("  > " + markdownEscape(strings.TrimRight(line, "\r")) + "\n")
+		}
+	}
+	_, err := w.w.WriteString(b.String())
+	return err
+}
+
+func (w *markdownWriter) Close() error {
+	var b strings.Builder
+	w.closeCollection(&b)
+	if _, err := w.w.WriteString(b.String()); err != nil {
+		return err
+	}
+	return w.w.Flush()
+}
+
+func (w *markdownWriter) closeCollection(b *strings.Builder) {
+	if w.entries == 0 {
+		b.WriteString("_Коллекция пуста_\n")
+	}
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"<", `\<`, ">", `\>`, "#", `\#`, "|", `\|`,
+)
+
+func markdownEscape(value string) string {
+	return markdownEscaper.Replace(value)
+}
@@ -0,0 +1,76 @@
+package importer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Серия в названии Goodreads: "Dune (Dune Chronicles, #1)"
+var goodreadsSeries = regexp.MustCompile(`\s*\([^()]*#[\d.]+\)\s*$`)
+
+var goodreadsStatuses = map[string]string{
+	"read":              StatusCompleted,
+	"currently-reading": StatusInProgress,
+	"to-read":           StatusPlanned,
+	"did-not-finish":    StatusDropped,
+}
+
+// parseGoodreads разбирает goodreads_library_export.csv
+func parseGoodreads(data []byte) ([]Record, error) {
+	table, err := readCSV(data)
+	if err != nil {
+		return nil, err
+	}
+	if !table.has("Title", "Exclusive Shelf") {
+		return nil, fmt.Errorf("%w: expected Goodreads library export", ErrInvalidFile)
+	}
+
+	records := make([]Record, 0, len(table.rows))
+	for i, row := range table.rows {
+		title := goodreadsSeries.ReplaceAllString(table.get(row, "Title"), "")
+		if title == "" {
+			continue
+		}
+		record := Record{
+			Line:        table.offset + i,
+			Type:        TypeBooks,
+			Title:       title,
+			Year:        parseYear(table.get(row, "Original Publication Year")),
+			ExternalIDs: map[string]string{},
+			Rating:      scaledRating(table.get(row, "My Rating"), 5),
+			FinishedAt:  parseDate(table.get(row, "Date Read")),
+			Review:      table.get(row, "My Review"),
+		}
+		if record.Year == nil {
+			record.Year = parseYear(table.get(row, "Year Published"))
+		}
+		record.Creators = appendUnique(splitList(table.get(row, "Author"), ","),
+			splitList(table.get(row, "Additional Authors"), ",")...)
+
+		if id := table.get(row, "Book Id"); id != "" {
+			record.ExternalIDs[SourceGoodreads] = id
+		}
+		if isbn := goodreadsISBN(table.get(row, "ISBN13")); isbn != "" {
+			record.ExternalIDs[SourceISBN] = isbn
+		} else if isbn := goodreadsISBN(table.get(row, "ISBN")); isbn != "" {
+			record.ExternalIDs[SourceISBN] = isbn
+		}
+
+		shelf := table.get(row, "Exclusive Shelf")
+		record.Status = goodreadsStatuses[shelf]
+		if record.Status == "" {
+			record.Status = StatusPlanned
+		}
+		// Полки перечислены без исключительной, она добавляется первой
+		record.Lists = appendUnique([]string{shelf}, splitList(table.get(row, "Bookshelves"), ",")...)
+
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// goodreadsISBN убирает обертку ="..." из колонок ISBN
+func goodreadsISBN(value string) string {
+	return strings.Trim(value, `="`)
+}
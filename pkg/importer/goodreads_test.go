@@ -0,0 +1,57 @@
+package importer
+
+import "testing"
+
+func TestParseGoodreads(t *testing.T) {
+	records, err := Parse(FormatGoodreads, "goodreads_library_export.csv", readFixture(t, "goodreads_library_export.csv"))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	// Строка без названия пропускается, номера строк остаются номерами строк файла
+	assertRecords(t, records, []Record{
+		{
+			Line:        2,
+			Type:        TypeBooks,
+			Title:       "Dune",
+			Year:        intPtr(1965),
+			Creators:    []string{"Frank Herbert"},
+			ExternalIDs: map[string]string{SourceGoodreads: "234225", SourceISBN: "9780441172719"},
+			Status:      StatusCompleted,
+			Rating:      intPtr(10),
+			FinishedAt:  date(2024, 3, 15),
+			Lists:       []string{"read", "favorites", "sci-fi"},
+			Review:      "Spice must flow.",
+		},
+		{
+			Line:        3,
+			Type:        TypeBooks,
+			Title:       "The Hobbit",
+			Year:        intPtr(2002),
+			Creators:    []string{"J.R.R. Tolkien", "Douglas A. Anderson"},
+			ExternalIDs: map[string]string{SourceGoodreads: "5907"},
+			Status:      StatusInProgress,
+			Lists:       []string{"currently-reading"},
+		},
+		{
+			Line:        4,
+			Type:        TypeBooks,
+			Title:       "The Hitchhiker's Guide to the Galaxy",
+			Year:        intPtr(1979),
+			Creators:    []string{"Douglas Adams"},
+			ExternalIDs: map[string]string{SourceGoodreads: "11", SourceISBN: "0345391802"},
+			Status:      StatusPlanned,
+			Lists:       []string{"to-read"},
+		},
+		{
+			Line:        6,
+			Type:        TypeBooks,
+			Title:       "Infinite Jest",
+			Year:        intPtr(1996),
+			Creators:    []string{"David Foster Wallace"},
+			ExternalIDs: map[string]string{SourceGoodreads: "77"},
+			Status:      StatusDropped,
+			Rating:      intPtr(4),
+			Lists:       []string{"did-not-finish"},
+		},
+	})
+}
@@ -0,0 +1,70 @@
+package importer
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	imdbRatingsList   = "IMDb Ratings"
+	imdbWatchlistList = "IMDb Watchlist"
+)
+
+// Типы записей IMDb. Эпизоды, игры и подкасты не импортируются
+var imdbTitleTypes = map[string]string{
+	"movie":        TypeMovies,
+	"tvmovie":      TypeMovies,
+	"short":        TypeMovies,
+	"tvshort":      TypeMovies,
+	"video":        TypeMovies,
+	"tvspecial":    TypeMovies,
+	"tvseries":     TypeSeries,
+	"tvminiseries": TypeSeries,
+}
+
+// parseIMDb разбирает ratings.csv или выгрузку списка IMDb (watchlist).
+// Оценки IMDb уже по шкале 1-10, оцененное считается просмотренным
+func parseIMDb(data []byte) ([]Record, error) {
+	table, err := readCSV(data)
+	if err != nil {
+		return nil, err
+	}
+	if !table.has("Const", "Title") {
+		return nil, fmt.Errorf("%w: expected IMDb ratings or list export", ErrInvalidFile)
+	}
+	// В выгрузке списка есть позиция, в выгрузке оценок - нет. Оценки могут быть в обеих
+	list := imdbWatchlistList
+	if !table.has("Position") {
+		list = imdbRatingsList
+	}
+
+	records := make([]Record, 0, len(table.rows))
+	for i, row := range table.rows {
+		title := table.get(row, "Title")
+		// Старые выгрузки пишут тип как tvSeries, новые - как TV Series
+		titleType := strings.ToLower(strings.ReplaceAll(table.get(row, "Title Type"), " ", ""))
+		itemType, ok := imdbTitleTypes[titleType]
+		if title == "" || !ok {
+			continue
+		}
+		record := Record{
+			Line:        table.offset + i,
+			Type:        itemType,
+			Title:       title,
+			Year:        parseYear(table.get(row, "Year")),
+			Creators:    splitList(table.get(row, "Directors"), ","),
+			ExternalIDs: map[string]string{},
+			Status:      StatusPlanned,
+			Lists:       []string{list},
+		}
+		if id := table.get(row, "Const"); id != "" {
+			record.ExternalIDs[SourceIMDb] = id
+		}
+		if record.Rating = scaledRating(table.get(row, "Your Rating"), 10); record.Rating != nil {
+			record.Status = StatusCompleted
+			record.FinishedAt = parseDate(table.get(row, "Date Rated"))
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
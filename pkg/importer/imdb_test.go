@@ -0,0 +1,66 @@
+package importer
+
+import "testing"
+
+func TestParseIMDbRatings(t *testing.T) {
+	records, err := Parse(FormatIMDb, "ratings.csv", readFixture(t, "imdb_ratings.csv"))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	// Эпизоды сериалов не импортируются
+	assertRecords(t, records, []Record{
+		{
+			Line:        2,
+			Type:        TypeMovies,
+			Title:       "The Matrix",
+			Year:        intPtr(1999),
+			Creators:    []string{"Lana Wachowski", "Lilly Wachowski"},
+			ExternalIDs: map[string]string{SourceIMDb: "tt0133093"},
+			Status:      StatusCompleted,
+			Rating:      intPtr(9),
+			FinishedAt:  date(2024, 4, 1),
+			Lists:       []string{imdbRatingsList},
+		},
+		{
+			Line:        3,
+			Type:        TypeSeries,
+			Title:       "Breaking Bad",
+			Year:        intPtr(2008),
+			ExternalIDs: map[string]string{SourceIMDb: "tt0903747"},
+			Status:      StatusCompleted,
+			Rating:      intPtr(10),
+			FinishedAt:  date(2024, 4, 2),
+			Lists:       []string{imdbRatingsList},
+		},
+	})
+}
+
+func TestParseIMDbWatchlist(t *testing.T) {
+	records, err := Parse(FormatIMDb, "watchlist.csv", readFixture(t, "imdb_watchlist.csv"))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	assertRecords(t, records, []Record{
+		{
+			Line:        2,
+			Type:        TypeMovies,
+			Title:       "Inception",
+			Year:        intPtr(2010),
+			Creators:    []string{"Christopher Nolan"},
+			ExternalIDs: map[string]string{SourceIMDb: "tt1375666"},
+			Status:      StatusPlanned,
+			Lists:       []string{imdbWatchlistList},
+		},
+		{
+			Line:        3,
+			Type:        TypeSeries,
+			Title:       "Rick and Morty",
+			Year:        intPtr(2013),
+			ExternalIDs: map[string]string{SourceIMDb: "tt2861424"},
+			Status:      StatusCompleted,
+			Rating:      intPtr(7),
+			FinishedAt:  date(2024, 5, 3),
+			Lists:       []string{imdbWatchlistList},
+		},
+	})
+}
@@ -0,0 +1,244 @@
+// Package importer разбирает выгрузки других трекеров (Goodreads, MyAnimeList, Letterboxd, IMDb)
+// в общий формат записей: элемент, оценка, статус, даты и списки, в которых он лежал
+package importer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Форматы выгрузок
+const (
+	FormatGoodreads  = "goodreads"
+	FormatMAL        = "mal"
+	FormatLetterboxd = "letterboxd"
+	FormatIMDb       = "imdb"
+)
+
+// Типы элементов, совпадают с типами элементов каталога
+const (
+	TypeBooks  = "books"
+	TypeMovies = "movies"
+	TypeSeries = "series"
+	TypeAnime  = "anime"
+)
+
+// Статусы, совпадают со статусами прогресса пользователя
+const (
+	StatusPlanned    = "planned"
+	StatusInProgress = "in_progress"
+	StatusCompleted  = "completed"
+	StatusDropped    = "dropped"
+)
+
+// Источники внешних ID записей
+const (
+	SourceGoodreads  = "goodreads"
+	SourceISBN       = "isbn"
+	SourceMAL        = "mal"
+	SourceShikimori  = "shikimori" // ID Shikimori совпадают с ID MyAnimeList
+	SourceLetterboxd = "letterboxd"
+	SourceIMDb       = "imdb"
+)
+
+// MaxRecords - ограничение на число записей в одной выгрузке
+const MaxRecords = 20000
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported import format")
+	ErrInvalidFile       = errors.New("file doesn't match import format")
+	ErrTooManyRecords    = fmt.Errorf("import is limited to %d records", MaxRecords)
+)
+
+// Record - строка выгрузки, приведенная к полям элемента и прогресса
+type Record struct {
+	// Line - номер строки или позиция записи в файле, для отчета о несопоставленных строках
+	Line        int               `json:"line"`
+	Type        string            `json:"type"`
+	Title       string            `json:"title"`
+	Year        *int              `json:"year,omitempty"`
+	Creators    []string          `json:"creators,omitempty"`
+	ExternalIDs map[string]string `json:"external_ids,omitempty"`
+	Status      string            `json:"status,omitempty"`
+	Progress    int               `json:"progress,omitempty"`
+	// Rating - оценка по шкале 1-10
+	Rating     *int       `json:"rating,omitempty"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	// Lists - полки и списки, для каждого создается коллекция
+	Lists  []string `json:"lists,omitempty"`
+	Review string   `json:"review,omitempty"`
+}
+
+// Parse разбирает выгрузку в формате format. По имени файла Letterboxd отличает, например,
+// watchlist.csv от watched.csv с теми же колонками
+func Parse(format string, filename string, data []byte) ([]Record, error) {
+	data, err := gunzip(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []Record
+	switch format {
+	case FormatGoodreads:
+		records, err = parseGoodreads(data)
+	case FormatMAL:
+		records, err = parseMAL(data)
+	case FormatLetterboxd:
+		records, err = parseLetterboxd(filename, data)
+	case FormatIMDb:
+		records, err = parseIMDb(data)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(records) > MaxRecords {
+		return nil, ErrTooManyRecords
+	}
+	return records, nil
+}
+
+// gunzip распаковывает сжатые выгрузки (MyAnimeList отдает .xml.gz)
+func gunzip(data []byte) ([]byte, error) {
+	if len(data) < 2 || data[0] != 0x1f || data[1] != 0x8b {
+		return data, nil
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: broken gzip archive", ErrInvalidFile)
+	}
+	defer reader.Close()
+
+	unpacked, err := io.ReadAll(io.LimitReader(reader, maxUnpackedSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: broken gzip archive", ErrInvalidFile)
+	}
+	if len(unpacked) > maxUnpackedSize {
+		return nil, fmt.Errorf("%w: archive is too large", ErrInvalidFile)
+	}
+	return unpacked, nil
+}
+
+const maxUnpackedSize = 100 << 20
+
+// csvTable - CSV с доступом к колонкам по имени заголовка
+type csvTable struct {
+	columns map[string]int
+	rows    [][]string
+	// offset - номер строки файла, с которой начинаются rows
+	offset int
+}
+
+func readCSV(data []byte) (*csvTable, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: file is empty", ErrInvalidFile)
+	}
+	return newCSVTable(rows[0], rows[1:], 2), nil
+}
+
+func newCSVTable(header []string, rows [][]string, offset int) *csvTable {
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	return &csvTable{columns: columns, rows: rows, offset: offset}
+}
+
+func (t *csvTable) has(names ...string) bool {
+	for _, name := range names {
+		if _, ok := t.columns[strings.ToLower(name)]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *csvTable) get(row []string, name string) string {
+	i, ok := t.columns[strings.ToLower(name)]
+	if !ok || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+func parseYear(value string) *int {
+	year, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || year < 1000 || year > 3000 {
+		return nil
+	}
+	return &year
+}
+
+var dateLayouts = []string{"2006-01-02", "2006/01/02", "2006-01-02 15:04:05", time.RFC3339}
+
+// parseDate разбирает даты выгрузок. Нулевые даты MyAnimeList (0000-00-00) считаются пустыми
+func parseDate(value string) *time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.HasPrefix(value, "0000") {
+		return nil
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+// scaledRating переводит оценку из шкалы 0-max в 1-10. Ноль означает отсутствие оценки
+func scaledRating(value string, max float64) *int {
+	score, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || score <= 0 || score > max {
+		return nil
+	}
+	rating := int(score*10/max + 0.5)
+	if rating < 1 {
+		rating = 1
+	}
+	return &rating
+}
+
+func splitList(value string, separator string) []string {
+	var items []string
+	for _, item := range strings.Split(value, separator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// appendUnique добавляет значения, которых еще нет в списке
+func appendUnique(list []string, values ...string) []string {
+	for _, value := range values {
+		found := false
+		for _, existing := range list {
+			if existing == value {
+				found = true
+				break
+			}
+		}
+		if !found && value != "" {
+			list = append(list, value)
+		}
+	}
+	return list
+}
@@ -0,0 +1,145 @@
+package importer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	return data
+}
+
+func intPtr(value int) *int {
+	return &value
+}
+
+func date(year int, month time.Month, day int) *time.Time {
+	t := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	return &t
+}
+
+// assertRecords сравнивает записи целиком, при расхождении печатает их в JSON
+func assertRecords(t *testing.T, got []Record, want []Record) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d records %s, want %d", len(got), recordJSON(got), len(want))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("record %d:\n got %s\nwant %s", i, recordJSON(got[i]), recordJSON(want[i]))
+		}
+	}
+}
+
+func recordJSON(value any) string {
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+func TestParseErrors(t *testing.T) {
+	goodreads := readFixture(t, "goodreads_library_export.csv")
+	tests := []struct {
+		name   string
+		format string
+		data   []byte
+		want   error
+	}{
+		{"unknown format", "trakt", goodreads, ErrUnsupportedFormat},
+		{"goodreads from imdb", FormatGoodreads, readFixture(t, "imdb_ratings.csv"), ErrInvalidFile},
+		{"imdb from goodreads", FormatIMDb, goodreads, ErrInvalidFile},
+		{"mal from csv", FormatMAL, goodreads, ErrInvalidFile},
+		{"letterboxd from imdb", FormatLetterboxd, readFixture(t, "imdb_ratings.csv"), ErrInvalidFile},
+		{"empty file", FormatGoodreads, nil, ErrInvalidFile},
+		{"broken gzip", FormatMAL, []byte{0x1f, 0x8b, 0x00}, ErrInvalidFile},
+		{"broken zip", FormatLetterboxd, []byte("PK\x03\x04broken"), ErrInvalidFile},
+	}
+	for _, tt := range tests {
+		if _, err := Parse(tt.format, "export.csv", tt.data); !errors.Is(err, tt.want) {
+			t.Errorf("%s: Parse = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestParseTooManyRecords(t *testing.T) {
+	var data strings.Builder
+	data.WriteString("Const,Title,Title Type\n")
+	for range MaxRecords + 1 {
+		data.WriteString("tt0000001,Film,movie\n")
+	}
+	if _, err := Parse(FormatIMDb, "ratings.csv", []byte(data.String())); !errors.Is(err, ErrTooManyRecords) {
+		t.Errorf("Parse of %d records = %v, want ErrTooManyRecords", MaxRecords+1, err)
+	}
+}
+
+func TestParseGzip(t *testing.T) {
+	plain := readFixture(t, "mal_animelist.xml")
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	writer.Write(plain)
+	writer.Close()
+
+	want, err := Parse(FormatMAL, "animelist.xml", plain)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	got, err := Parse(FormatMAL, "animelist.xml.gz", compressed.Bytes())
+	if err != nil {
+		t.Fatalf("Parse of gzip: %v", err)
+	}
+	assertRecords(t, got, want)
+}
+
+func TestScaledRating(t *testing.T) {
+	tests := []struct {
+		value string
+		max   float64
+		want  *int
+	}{
+		{"5", 5, intPtr(10)},
+		{"3", 5, intPtr(6)},
+		{"0.5", 5, intPtr(1)},
+		{"4.5", 5, intPtr(9)},
+		{"7", 10, intPtr(7)},
+		{"0", 5, nil},
+		{"6", 5, nil},
+		{"", 10, nil},
+		{"great", 10, nil},
+	}
+	for _, tt := range tests {
+		if got := scaledRating(tt.value, tt.max); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("scaledRating(%q, %v) = %s, want %s", tt.value, tt.max, recordJSON(got), recordJSON(tt.want))
+		}
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		value string
+		want  *time.Time
+	}{
+		{"2024-03-15", date(2024, 3, 15)},
+		{"2024/03/15", date(2024, 3, 15)},
+		{"2024-03-15 00:00:00", date(2024, 3, 15)},
+		{"0000-00-00", nil},
+		{"", nil},
+		{"yesterday", nil},
+	}
+	for _, tt := range tests {
+		if got := parseDate(tt.value); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseDate(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
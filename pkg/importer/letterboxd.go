@@ -0,0 +1,220 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+)
+
+const (
+	letterboxdWatched   = "Watched"
+	letterboxdWatchlist = "Watchlist"
+	letterboxdListMark  = "Letterboxd list export"
+	maxLetterboxdFile   = 20 << 20
+)
+
+// letterboxdImport собирает записи о фильме из нескольких файлов выгрузки по названию и году:
+// в diary.csv и reviews.csv ссылка ведет на запись дневника, а не на фильм
+type letterboxdImport struct {
+	records map[string]*Record
+	order   []string
+}
+
+// parseLetterboxd разбирает ZIP-выгрузку Letterboxd или отдельный CSV из нее
+func parseLetterboxd(filename string, data []byte) ([]Record, error) {
+	imp := &letterboxdImport{records: map[string]*Record{}}
+
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		if err := imp.readZip(data); err != nil {
+			return nil, err
+		}
+	} else if err := imp.readFile(path.Base(filename), data); err != nil {
+		return nil, err
+	}
+
+	if len(imp.order) == 0 {
+		return nil, fmt.Errorf("%w: expected Letterboxd export", ErrInvalidFile)
+	}
+	records := make([]Record, 0, len(imp.order))
+	for i, key := range imp.order {
+		record := imp.records[key]
+		record.Line = i + 1
+		records = append(records, *record)
+	}
+	return records, nil
+}
+
+func (imp *letterboxdImport) readZip(data []byte) error {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("%w: broken zip archive", ErrInvalidFile)
+	}
+
+	// Остальные файлы выгрузки (profile.csv, comments.csv, likes, deleted) не описывают просмотры.
+	// Файлы с фактом просмотра читаются раньше списка желаемого, чтобы статус не откатывался
+	files := make([]*zip.File, 0, len(archive.File))
+	for _, file := range archive.File {
+		if strings.Contains("/"+file.Name, "/lists/") && strings.HasSuffix(file.Name, ".csv") ||
+			letterboxdFilePriority(file.Name) < letterboxdListPriority {
+			files = append(files, file)
+		}
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return letterboxdFilePriority(files[i].Name) < letterboxdFilePriority(files[j].Name)
+	})
+
+	for _, file := range files {
+		if file.UncompressedSize64 > maxLetterboxdFile {
+			return fmt.Errorf("%w: %s is too large", ErrInvalidFile, file.Name)
+		}
+		reader, err := file.Open()
+		if err != nil {
+			return fmt.Errorf("%w: can't read %s", ErrInvalidFile, file.Name)
+		}
+		content, err := io.ReadAll(io.LimitReader(reader, maxLetterboxdFile))
+		reader.Close()
+		if err != nil {
+			return fmt.Errorf("%w: can't read %s", ErrInvalidFile, file.Name)
+		}
+
+		if err := imp.readFile(path.Base(file.Name), content); err != nil {
+			return err
+		}
+		if len(imp.order) > MaxRecords {
+			return ErrTooManyRecords
+		}
+	}
+	return nil
+}
+
+const letterboxdListPriority = 5
+
+func letterboxdFilePriority(name string) int {
+	switch path.Base(name) {
+	case "watched.csv":
+		return 0
+	case "diary.csv":
+		return 1
+	case "ratings.csv":
+		return 2
+	case "reviews.csv":
+		return 3
+	case "watchlist.csv":
+		return 4
+	}
+	return letterboxdListPriority
+}
+
+func (imp *letterboxdImport) readFile(name string, data []byte) error {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if bytes.HasPrefix(data, []byte(letterboxdListMark)) {
+		return imp.readList(data)
+	}
+
+	table, err := readCSV(data)
+	if err != nil {
+		return err
+	}
+	if !table.has("Name", "Year") {
+		return fmt.Errorf("%w: expected Letterboxd export", ErrInvalidFile)
+	}
+
+	diaryEntry := table.has("Watched Date") || table.has("Review")
+	for i, row := range table.rows {
+		record := imp.record(table.get(row, "Name"), table.get(row, "Year"), table.offset+i)
+		if record == nil {
+			continue
+		}
+		if uri := table.get(row, "Letterboxd URI"); uri != "" && !diaryEntry {
+			record.ExternalIDs[SourceLetterboxd] = uri
+		}
+
+		if name == "watchlist.csv" {
+			if record.Status == "" {
+				record.Status = StatusPlanned
+			}
+			record.Lists = appendUnique(record.Lists, letterboxdWatchlist)
+			continue
+		}
+
+		record.Status = StatusCompleted
+		record.Lists = appendUnique(record.Lists, letterboxdWatched)
+		if rating := scaledRating(table.get(row, "Rating"), 5); rating != nil {
+			record.Rating = rating
+		}
+		watched := parseDate(table.get(row, "Watched Date"))
+		if watched == nil {
+			watched = parseDate(table.get(row, "Date"))
+		}
+		if watched != nil && (record.FinishedAt == nil || watched.After(*record.FinishedAt)) {
+			record.FinishedAt = watched
+		}
+		if review := table.get(row, "Review"); review != "" {
+			record.Review = review
+		}
+	}
+	return nil
+}
+
+// readList разбирает файл списка: строка версии, шапка и описание списка, пустая строка и фильмы
+func (imp *letterboxdImport) readList(data []byte) error {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+
+	listName := ""
+	for i := 1; i+1 < len(rows); i++ {
+		header := newCSVTable(rows[i], nil, 0)
+		if listName == "" && header.has("Name", "URL") && !header.has("Position") {
+			listName = header.get(rows[i+1], "Name")
+			continue
+		}
+		if header.has("Name", "Year") {
+			films := newCSVTable(rows[i], rows[i+1:], i+2)
+			if listName == "" {
+				listName = "Letterboxd"
+			}
+			for j, row := range films.rows {
+				record := imp.record(films.get(row, "Name"), films.get(row, "Year"), films.offset+j)
+				if record == nil {
+					continue
+				}
+				if uri := films.get(row, "URL"); uri != "" {
+					record.ExternalIDs[SourceLetterboxd] = uri
+				}
+				record.Lists = appendUnique(record.Lists, listName)
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+func (imp *letterboxdImport) record(title string, year string, line int) *Record {
+	if title == "" {
+		return nil
+	}
+	key := strings.ToLower(title) + "\x00" + year
+	record, ok := imp.records[key]
+	if !ok {
+		record = &Record{
+			Line:        line,
+			Type:        TypeMovies,
+			Title:       title,
+			Year:        parseYear(year),
+			ExternalIDs: map[string]string{},
+		}
+		imp.records[key] = record
+		imp.order = append(imp.order, key)
+	}
+	return record
+}
@@ -0,0 +1,115 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// letterboxdZip собирает ZIP-выгрузку из файлов testdata/letterboxd
+func letterboxdZip(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	root := filepath.Join("testdata", "letterboxd")
+	err := filepath.WalkDir(root, func(path string, entry os.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		name, _ := filepath.Rel(root, path)
+		file, err := archive.Create("letterboxd-tester-2024-03-01/" + filepath.ToSlash(name))
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		_, err = file.Write(data)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("build zip: %v", err)
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatalf("close zip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestParseLetterboxdZip(t *testing.T) {
+	records, err := Parse(FormatLetterboxd, "letterboxd-tester.zip", letterboxdZip(t))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	// Записи дневника и рецензии дополняют фильм из watched.csv: последняя дата просмотра,
+	// оценка и рецензия. Список желаемого не откатывает статус просмотренного фильма
+	assertRecords(t, records, []Record{
+		{
+			Line:        1,
+			Type:        TypeMovies,
+			Title:       "Parasite",
+			Year:        intPtr(2019),
+			ExternalIDs: map[string]string{SourceLetterboxd: "https://boxd.it/hTha"},
+			Status:      StatusCompleted,
+			Rating:      intPtr(10),
+			FinishedAt:  date(2024, 2, 28),
+			Lists:       []string{letterboxdWatched},
+			Review:      "Even better the second time.",
+		},
+		{
+			Line:        2,
+			Type:        TypeMovies,
+			Title:       "Heat",
+			Year:        intPtr(1995),
+			ExternalIDs: map[string]string{SourceLetterboxd: "https://boxd.it/2aAy"},
+			Status:      StatusCompleted,
+			Rating:      intPtr(8),
+			FinishedAt:  date(2024, 1, 12),
+			Lists:       []string{letterboxdWatched, letterboxdWatchlist, "Favourite heists"},
+		},
+		{
+			Line:        3,
+			Type:        TypeMovies,
+			Title:       "Dune: Part Two",
+			Year:        intPtr(2024),
+			ExternalIDs: map[string]string{SourceLetterboxd: "https://boxd.it/dunept2"},
+			Status:      StatusPlanned,
+			Lists:       []string{letterboxdWatchlist},
+		},
+		{
+			Line:        4,
+			Type:        TypeMovies,
+			Title:       "Inside Man",
+			Year:        intPtr(2006),
+			ExternalIDs: map[string]string{SourceLetterboxd: "https://boxd.it/insideman"},
+			Lists:       []string{"Favourite heists"},
+		},
+	})
+}
+
+func TestParseLetterboxdFile(t *testing.T) {
+	data := readFixture(t, filepath.Join("letterboxd", "watchlist.csv"))
+	records, err := Parse(FormatLetterboxd, "watchlist.csv", data)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	for _, record := range records {
+		if record.Status != StatusPlanned {
+			t.Errorf("%s status = %q, want planned from watchlist.csv", record.Title, record.Status)
+		}
+	}
+
+	// Тот же файл под другим именем читается как просмотренные фильмы
+	records, err = Parse(FormatLetterboxd, "watched.csv", data)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	for _, record := range records {
+		if record.Status != StatusCompleted {
+			t.Errorf("%s status = %q, want completed from watched.csv", record.Title, record.Status)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Статусы MyAnimeList: текстовые в новых выгрузках и числовые в старых
+var malStatuses = map[string]string{
+	"watching":      StatusInProgress,
+	"reading":       StatusInProgress,
+	"completed":     StatusCompleted,
+	"on-hold":       StatusInProgress,
+	"dropped":       StatusDropped,
+	"plan to watch": StatusPlanned,
+	"plan to read":  StatusPlanned,
+	"1":             StatusInProgress,
+	"2":             StatusCompleted,
+	"3":             StatusInProgress,
+	"4":             StatusDropped,
+	"6":             StatusPlanned,
+}
+
+// Названия списков MyAnimeList для числовых статусов
+var malStatusLists = map[string]string{
+	"1": "Watching",
+	"2": "Completed",
+	"3": "On-Hold",
+	"4": "Dropped",
+	"6": "Plan to Watch",
+}
+
+type malExport struct {
+	XMLName xml.Name   `xml:"myanimelist"`
+	Anime   []malEntry `xml:"anime"`
+	Manga   []malEntry `xml:"manga"`
+}
+
+type malEntry struct {
+	AnimeID         string `xml:"series_animedb_id"`
+	MangaID         string `xml:"manga_mangadb_id"`
+	AnimeTitle      string `xml:"series_title"`
+	MangaTitle      string `xml:"manga_title"`
+	WatchedEpisodes int    `xml:"my_watched_episodes"`
+	ReadChapters    int    `xml:"my_read_chapters"`
+	StartDate       string `xml:"my_start_date"`
+	FinishDate      string `xml:"my_finish_date"`
+	Score           string `xml:"my_score"`
+	Status          string `xml:"my_status"`
+	Comments        string `xml:"my_comments"`
+}
+
+// parseMAL разбирает XML-выгрузку списков аниме и манги MyAnimeList. Манга импортируется как книги,
+// списки соответствуют статусам MyAnimeList
+func parseMAL(data []byte) ([]Record, error) {
+	var export malExport
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	// Выгрузка объявлена в UTF-8, но старые файлы бывают в другой кодировке
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+	if err := decoder.Decode(&export); err != nil {
+		return nil, fmt.Errorf("%w: expected MyAnimeList XML export", ErrInvalidFile)
+	}
+
+	records := make([]Record, 0, len(export.Anime)+len(export.Manga))
+	for i, entry := range export.Anime {
+		record := entry.record(i+1, TypeAnime, entry.AnimeTitle, entry.WatchedEpisodes)
+		if entry.AnimeID != "" && entry.AnimeID != "0" {
+			record.ExternalIDs[SourceMAL] = entry.AnimeID
+			record.ExternalIDs[SourceShikimori] = entry.AnimeID
+		}
+		if record.Title != "" {
+			records = append(records, record)
+		}
+	}
+	for i, entry := range export.Manga {
+		record := entry.record(len(export.Anime)+i+1, TypeBooks, entry.MangaTitle, entry.ReadChapters)
+		if entry.MangaID != "" && entry.MangaID != "0" {
+			record.ExternalIDs[SourceMAL] = "manga/" + entry.MangaID
+		}
+		if record.Title != "" {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+func (e malEntry) record(position int, itemType string, title string, progress int) Record {
+	status := strings.TrimSpace(e.Status)
+	record := Record{
+		Line:        position,
+		Type:        itemType,
+		Title:       strings.TrimSpace(title),
+		ExternalIDs: map[string]string{},
+		Status:      malStatuses[strings.ToLower(status)],
+		Progress:    progress,
+		Rating:      scaledRating(e.Score, 10),
+		StartedAt:   parseDate(e.StartDate),
+		FinishedAt:  parseDate(e.FinishDate),
+		Review:      strings.TrimSpace(e.Comments),
+	}
+	if record.Status == "" {
+		record.Status = StatusPlanned
+	}
+	if list, ok := malStatusLists[status]; ok {
+		status = list
+	}
+	record.Lists = []string{status}
+	return record
+}
@@ -0,0 +1,45 @@
+package importer
+
+import "testing"
+
+func TestParseMAL(t *testing.T) {
+	records, err := Parse(FormatMAL, "animelist.xml", readFixture(t, "mal_animelist.xml"))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	// Запись без названия пропускается, манга идет после аниме как книги
+	assertRecords(t, records, []Record{
+		{
+			Line:        1,
+			Type:        TypeAnime,
+			Title:       "Fullmetal Alchemist: Brotherhood",
+			ExternalIDs: map[string]string{SourceMAL: "5114", SourceShikimori: "5114"},
+			Status:      StatusCompleted,
+			Progress:    64,
+			Rating:      intPtr(10),
+			StartedAt:   date(2023, 1, 5),
+			FinishedAt:  date(2023, 2, 20),
+			Lists:       []string{"Completed"},
+			Review:      "Masterpiece",
+		},
+		{
+			Line:        2,
+			Type:        TypeAnime,
+			Title:       "Death Note",
+			ExternalIDs: map[string]string{SourceMAL: "1535", SourceShikimori: "1535"},
+			Status:      StatusInProgress,
+			Progress:    12,
+			Lists:       []string{"Watching"},
+		},
+		{
+			Line:        4,
+			Type:        TypeBooks,
+			Title:       "Berserk",
+			ExternalIDs: map[string]string{SourceMAL: "manga/2"},
+			Status:      StatusInProgress,
+			Progress:    120,
+			Rating:      intPtr(9),
+			Lists:       []string{"Reading"},
+		},
+	})
+}
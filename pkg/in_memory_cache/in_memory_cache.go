@@ -24,7 +24,7 @@ func NewMemoryCache[T any](ttl time.Duration) *Cache[T] {
 		items: make(map[string]CacheItem[T]),
 	}
 
-	cache.startEvictionLoop()
+	go cache.startEvictionLoop()
 
 	return cache
 }
@@ -46,12 +46,20 @@ func (c *Cache[T]) Delete(key string) {
 	delete(c.items, key)
 }
 
+// Clear удаляет все элементы кеша
+func (c *Cache[T]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items = make(map[string]CacheItem[T])
+}
+
 func (c *Cache[T]) Get(key string) (*T, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
 	cache_item, exists := c.items[key]
-	if !exists {
+	if !exists || cache_item.ExpiresAt <= time.Now().UnixNano() {
 		return nil, fmt.Errorf("the item with this key does not exist")
 	} else {
 		return &cache_item.Value, nil
@@ -67,7 +75,7 @@ func (c *Cache[T]) startEvictionLoop() {
 }
 
 func (c *Cache[T]) evictExpired() {
-	c.mu.RLock()
+	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	now := time.Now().UnixNano()
@@ -0,0 +1,172 @@
+package metadata
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	aniListName       = "anilist"
+	aniListDefaultURL = "https://graphql.anilist.co"
+	aniListSiteURL    = "https://anilist.co"
+)
+
+var (
+	numericExternalID = regexp.MustCompile(`^\d+$`)
+	// Описания AniList содержат HTML-разметку
+	htmlTag = regexp.MustCompile(`<[^>]*>`)
+)
+
+const aniListMediaFields = `
+	id
+	title { romaji english }
+	description(asHtml: false)
+	startDate { year }
+	coverImage { large }
+	genres
+	siteUrl
+`
+
+var (
+	aniListSearchQuery = `query ($search: String, $perPage: Int) {
+		Page(perPage: $perPage) { media(search: $search, type: ANIME) {` + aniListMediaFields + `} }
+	}`
+	aniListGetQuery = `query ($id: Int) {
+		Media(id: $id, type: ANIME) {` + aniListMediaFields + `}
+	}`
+)
+
+type AniListConfig struct {
+	BaseURL string
+}
+
+// AniList - аниме из anilist.co через GraphQL API. Внешний ID - числовой ID AniList
+type AniList struct {
+	client
+}
+
+func NewAniList(cfg AniListConfig, httpClient *http.Client) *AniList {
+	return &AniList{client: newClient(cfg.BaseURL, aniListDefaultURL, httpClient)}
+}
+
+func (p *AniList) Name() string {
+	return aniListName
+}
+
+func (p *AniList) Types() []string {
+	return []string{TypeAnime}
+}
+
+type aniListMedia struct {
+	ID    int `json:"id"`
+	Title struct {
+		Romaji  string `json:"romaji"`
+		English string `json:"english"`
+	} `json:"title"`
+	Description string `json:"description"`
+	StartDate   struct {
+		Year *int `json:"year"`
+	} `json:"startDate"`
+	CoverImage struct {
+		Large string `json:"large"`
+	} `json:"coverImage"`
+	Genres  []string `json:"genres"`
+	SiteURL string   `json:"siteUrl"`
+}
+
+func (p *AniList) Search(ctx context.Context, query Query) ([]Result, error) {
+	var resp struct {
+		Page struct {
+			Media []aniListMedia `json:"media"`
+		} `json:"Page"`
+	}
+	variables := map[string]any{"search": query.Text, "perPage": searchLimit(query.Limit)}
+	if err := p.graphql(ctx, aniListSearchQuery, variables, &resp); err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0, len(resp.Page.Media))
+	for _, media := range resp.Page.Media {
+		results = append(results, p.result(media))
+	}
+	return results, nil
+}
+
+func (p *AniList) Get(ctx context.Context, externalID string) (*Result, error) {
+	if !numericExternalID.MatchString(externalID) {
+		return nil, ErrInvalidExternalID
+	}
+	id, _ := strconv.Atoi(externalID)
+
+	var resp struct {
+		Media *aniListMedia `json:"Media"`
+	}
+	if err := p.graphql(ctx, aniListGetQuery, map[string]any{"id": id}, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Media == nil {
+		return nil, ErrNotFound
+	}
+
+	result := p.result(*resp.Media)
+	return &result, nil
+}
+
+// graphql отправляет запрос и разбирает data. Ошибка "Not Found." в errors означает отсутствие записи
+func (p *AniList) graphql(ctx context.Context, query string, variables map[string]any, data any) error {
+	body, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	if err != nil {
+		return fmt.Errorf("failed to marshal graphql request: %w", err)
+	}
+
+	var resp struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+			Status  int    `json:"status"`
+		} `json:"errors"`
+	}
+	if err := p.doJSON(ctx, http.MethodPost, "", bytes.NewReader(body), &resp); err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		if resp.Errors[0].Status == http.StatusNotFound {
+			return ErrNotFound
+		}
+		return fmt.Errorf("anilist: %s", resp.Errors[0].Message)
+	}
+	return json.Unmarshal(resp.Data, data)
+}
+
+func (p *AniList) result(media aniListMedia) Result {
+	title := media.Title.Romaji
+	if title == "" {
+		title = media.Title.English
+	}
+	siteURL := media.SiteURL
+	if siteURL == "" {
+		siteURL = fmt.Sprintf("%s/anime/%d", aniListSiteURL, media.ID)
+	}
+	return Result{
+		Provider:    aniListName,
+		ExternalID:  strconv.Itoa(media.ID),
+		Type:        TypeAnime,
+		Title:       title,
+		Description: stripHTML(media.Description),
+		CoverURL:    media.CoverImage.Large,
+		ReleaseYear: media.StartDate.Year,
+		Genres:      media.Genres,
+		URL:         siteURL,
+	}
+}
+
+func stripHTML(text string) string {
+	text = strings.ReplaceAll(text, "<br>", "\n")
+	return strings.TrimSpace(htmlTag.ReplaceAllString(text, ""))
+}
@@ -0,0 +1,141 @@
+package metadata
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func newTestAniList(t *testing.T) (*AniList, *fakeCatalog) {
+	t.Helper()
+	catalog := newFakeCatalog(t)
+	return NewAniList(AniListConfig{BaseURL: catalog.URL}, catalog.Client()), catalog
+}
+
+type aniListRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+func decodeAniListRequest(t *testing.T, catalog *fakeCatalog) aniListRequest {
+	t.Helper()
+	req, body := catalog.lastRequest(t)
+	if req.Method != http.MethodPost || req.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("request = %s %s, want POST application/json", req.Method, req.Header.Get("Content-Type"))
+	}
+	var graphqlReq aniListRequest
+	if err := json.Unmarshal(body, &graphqlReq); err != nil {
+		t.Fatalf("decode graphql request: %v", err)
+	}
+	return graphqlReq
+}
+
+func TestAniListSearch(t *testing.T) {
+	provider, catalog := newTestAniList(t)
+	catalog.fixture(t, "/", "anilist_search.json")
+
+	results, err := provider.Search(t.Context(), Query{Text: "bebop", Limit: 5})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	assertResults(t, results, []Result{
+		{
+			Provider:    aniListName,
+			ExternalID:  "1",
+			Type:        TypeAnime,
+			Title:       "Cowboy Bebop",
+			Description: "In the year 2071, humanity has colonized several of the planets.\n\n\n(Source: Sunrise)",
+			CoverURL:    "https://s4.anilist.co/file/anilistcdn/media/anime/cover/medium/bx1-CXtrrkMpJ8Zq.png",
+			ReleaseYear: intPtr(1998),
+			Genres:      []string{"Action", "Adventure", "Drama", "Sci-Fi"},
+			URL:         "https://anilist.co/anime/1",
+		},
+		{
+			Provider:   aniListName,
+			ExternalID: "5",
+			Type:       TypeAnime,
+			Title:      "Cowboy Bebop: The Movie",
+			Genres:     []string{},
+			URL:        "https://anilist.co/anime/5",
+		},
+	})
+
+	graphqlReq := decodeAniListRequest(t, catalog)
+	if !strings.Contains(graphqlReq.Query, "Page(perPage: $perPage)") {
+		t.Errorf("query = %q, want page search", graphqlReq.Query)
+	}
+	if graphqlReq.Variables["search"] != "bebop" || graphqlReq.Variables["perPage"] != float64(5) {
+		t.Errorf("variables = %v, want search=bebop perPage=5", graphqlReq.Variables)
+	}
+}
+
+func TestAniListGet(t *testing.T) {
+	provider, catalog := newTestAniList(t)
+	catalog.fixture(t, "/", "anilist_media.json")
+
+	result, err := provider.Get(t.Context(), "1")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	assertResult(t, result, &Result{
+		Provider:    aniListName,
+		ExternalID:  "1",
+		Type:        TypeAnime,
+		Title:       "Cowboy Bebop",
+		Description: "Enter a world in the distant future.\n\nBounty hunters roam the galaxy.",
+		CoverURL:    "https://s4.anilist.co/file/anilistcdn/media/anime/cover/medium/bx1-CXtrrkMpJ8Zq.png",
+		ReleaseYear: intPtr(1998),
+		Genres:      []string{"Action", "Sci-Fi"},
+		URL:         "https://anilist.co/anime/1",
+	})
+
+	if graphqlReq := decodeAniListRequest(t, catalog); graphqlReq.Variables["id"] != float64(1) {
+		t.Errorf("variables = %v, want numeric id 1", graphqlReq.Variables)
+	}
+}
+
+func TestAniListGetErrors(t *testing.T) {
+	provider, catalog := newTestAniList(t)
+
+	for _, id := range []string{"", "abc", "1; drop", "-1"} {
+		if _, err := provider.Get(t.Context(), id); !errors.Is(err, ErrInvalidExternalID) {
+			t.Errorf("Get(%q) = %v, want ErrInvalidExternalID", id, err)
+		}
+	}
+
+	// AniList сообщает об отсутствии записи в errors с кодом 200 или 404
+	catalog.fixture(t, "/", "anilist_not_found.json")
+	if _, err := provider.Get(t.Context(), "999999"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get of missing media = %v, want ErrNotFound", err)
+	}
+
+	catalog.fixture(t, "/", "anilist_error.json")
+	_, err := provider.Get(t.Context(), "1")
+	if err == nil || errors.Is(err, ErrNotFound) || !strings.Contains(err.Error(), "Too Many Requests") {
+		t.Errorf("Get on graphql error = %v, want error with message", err)
+	}
+
+	catalog.status("/", http.StatusBadGateway, "bad gateway")
+	if _, err := provider.Search(t.Context(), Query{Text: "bebop"}); err == nil {
+		t.Error("Search on server error = nil, want error")
+	}
+}
+
+func TestStripHTML(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"plain", "plain"},
+		{"<b>bold</b> and <i>italic</i>", "bold and italic"},
+		{"line<br>next", "line\nnext"},
+		{"  <p>padded</p>  ", "padded"},
+	}
+	for _, tt := range tests {
+		if got := stripHTML(tt.text); got != tt.want {
+			t.Errorf("stripHTML(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package metadata
+
+import (
+	"net/http"
+	"time"
+)
+
+// Config - какие провайдеры подключены и их настройки. Base URL можно заменить на локальный фейковый сервер
+type Config struct {
+	Timeout     time.Duration
+	OpenLibrary ProviderConfig[OpenLibraryConfig]
+	TMDB        ProviderConfig[TMDBConfig]
+	AniList     ProviderConfig[AniListConfig]
+	Shikimori   ProviderConfig[ShikimoriConfig]
+}
+
+type ProviderConfig[T any] struct {
+	Enabled bool
+	Config  T
+}
+
+// NewRegistryFromConfig подключает включенные провайдеры. TMDB без ключа API не подключается
+func NewRegistryFromConfig(cfg Config) *Registry {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	httpClient := &http.Client{Timeout: timeout}
+
+	registry := NewRegistry()
+	if cfg.OpenLibrary.Enabled {
+		registry.Register(NewOpenLibrary(cfg.OpenLibrary.Config, httpClient))
+	}
+	if cfg.TMDB.Enabled && (cfg.TMDB.Config.APIKey != "" || cfg.TMDB.Config.AccessToken != "") {
+		registry.Register(NewTMDB(cfg.TMDB.Config, httpClient))
+	}
+	if cfg.AniList.Enabled {
+		registry.Register(NewAniList(cfg.AniList.Config, httpClient))
+	}
+	if cfg.Shikimori.Enabled {
+		registry.Register(NewShikimori(cfg.Shikimori.Config, httpClient))
+	}
+	return registry
+}
@@ -0,0 +1,51 @@
+package metadata
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter ограничивает частоту запросов к провайдеру: запросы равномерно распределяются по минуте
+type Limiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// NewLimiter создает ограничение perMinute запросов в минуту. perMinute <= 0 - без ограничения
+func NewLimiter(perMinute int) *Limiter {
+	l := &Limiter{}
+	if perMinute > 0 {
+		l.interval = time.Minute / time.Duration(perMinute)
+	}
+	return l
+}
+
+// Wait ждет, пока можно будет выполнить следующий запрос, или отмены ctx
+func (l *Limiter) Wait(ctx context.Context) error {
+	if l.interval == 0 {
+		return ctx.Err()
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	wait := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,94 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLimiterUnlimited(t *testing.T) {
+	for _, perMinute := range []int{0, -1} {
+		limiter := NewLimiter(perMinute)
+		started := time.Now()
+		for range 100 {
+			if err := limiter.Wait(t.Context()); err != nil {
+				t.Fatalf("Wait: %v", err)
+			}
+		}
+		if elapsed := time.Since(started); elapsed > 50*time.Millisecond {
+			t.Errorf("NewLimiter(%d): 100 waits took %s, want no delay", perMinute, elapsed)
+		}
+	}
+}
+
+func TestLimiterSpacesRequests(t *testing.T) {
+	// 1200 в минуту - запрос раз в 50 мс
+	limiter := NewLimiter(1200)
+	started := time.Now()
+	for range 4 {
+		if err := limiter.Wait(t.Context()); err != nil {
+			t.Fatalf("Wait: %v", err)
+		}
+	}
+	// Первый запрос сразу, следующие три - через 50 мс каждый
+	if elapsed := time.Since(started); elapsed < 140*time.Millisecond {
+		t.Errorf("4 waits took %s, want at least 150ms", elapsed)
+	}
+}
+
+func TestLimiterConcurrentWaiters(t *testing.T) {
+	limiter := NewLimiter(1200)
+	started := time.Now()
+
+	var mu sync.Mutex
+	var times []time.Duration
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := limiter.Wait(t.Context()); err != nil {
+				t.Errorf("Wait: %v", err)
+				return
+			}
+			mu.Lock()
+			times = append(times, time.Since(started))
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	// Каждому ожидающему достается свой слот: последний ждет четыре интервала
+	latest := time.Duration(0)
+	for _, d := range times {
+		latest = max(latest, d)
+	}
+	if latest < 190*time.Millisecond {
+		t.Errorf("last of 5 concurrent waiters finished after %s, want at least 200ms", latest)
+	}
+}
+
+func TestLimiterCanceled(t *testing.T) {
+	limiter := NewLimiter(1) // запрос раз в минуту
+	if err := limiter.Wait(t.Context()); err != nil {
+		t.Fatalf("first Wait: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	if err := limiter.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Errorf("canceled Wait returned after %s, want on context deadline", elapsed)
+	}
+
+	canceled, cancel := context.WithCancel(t.Context())
+	cancel()
+	if err := NewLimiter(0).Wait(canceled); !errors.Is(err, context.Canceled) {
+		t.Errorf("unlimited Wait with canceled context = %v, want context.Canceled", err)
+	}
+}
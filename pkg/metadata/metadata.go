@@ -0,0 +1,207 @@
+// Package metadata - поиск и получение метаданных книг, фильмов, сериалов и аниме во внешних каталогах.
+// Каждый каталог подключается адаптером, реализующим Provider
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Типы элементов, совпадают с типами элементов каталога
+const (
+	TypeBooks  = "books"
+	TypeMovies = "movies"
+	TypeSeries = "series"
+	TypeAnime  = "anime"
+)
+
+var (
+	// ErrNotFound возвращается, если во внешнем каталоге нет записи с таким ID
+	ErrNotFound = errors.New("metadata not found")
+	// ErrInvalidExternalID возвращается для ID в неверном для провайдера формате
+	ErrInvalidExternalID = errors.New("invalid external id")
+)
+
+const (
+	defaultTimeout     = 10 * time.Second
+	defaultSearchLimit = 10
+	maxResponseSize    = 5 << 20
+	userAgent          = "Memoria/1.0 (+https://github.com/kefirchick13/memoria-collect-platform-golang)"
+)
+
+// Query - параметры поиска. Провайдер использует только понятные ему поля
+type Query struct {
+	Text  string
+	ISBN  string
+	Type  string
+	Limit int
+}
+
+// Result - метаданные записи внешнего каталога, приведенные к полям элемента
+type Result struct {
+	Provider    string   `json:"provider" example:"openlibrary"`
+	ExternalID  string   `json:"external_id" example:"OL893415W"`
+	Type        string   `json:"type" example:"books"`
+	Title       string   `json:"title" example:"Dune"`
+	Description string   `json:"description,omitempty"`
+	CoverURL    string   `json:"cover_url,omitempty"`
+	ReleaseYear *int     `json:"release_year,omitempty" example:"1965"`
+	Genres      []string `json:"genres,omitempty"`
+	Authors     []string `json:"authors,omitempty"`
+	URL         string   `json:"url,omitempty"`
+}
+
+// Provider - адаптер внешнего каталога
+type Provider interface {
+	// Name - постоянное имя провайдера, сохраняется вместе с внешним ID
+	Name() string
+	// Types - типы элементов, которые есть в каталоге
+	Types() []string
+	Search(ctx context.Context, query Query) ([]Result, error)
+	Get(ctx context.Context, externalID string) (*Result, error)
+}
+
+// Registry - подключенные провайдеры в порядке регистрации
+type Registry struct {
+	providers []Provider
+	byName    map[string]Provider
+}
+
+func NewRegistry(providers ...Provider) *Registry {
+	r := &Registry{byName: map[string]Provider{}}
+	for _, provider := range providers {
+		r.Register(provider)
+	}
+	return r
+}
+
+func (r *Registry) Register(provider Provider) {
+	if _, ok := r.byName[provider.Name()]; ok {
+		return
+	}
+	r.providers = append(r.providers, provider)
+	r.byName[provider.Name()] = provider
+}
+
+func (r *Registry) Get(name string) (Provider, bool) {
+	provider, ok := r.byName[name]
+	return provider, ok
+}
+
+// Names - имена зарегистрированных провайдеров в порядке регистрации
+func (r *Registry) Names() []string {
+	names := make([]string, len(r.providers))
+	for i, provider := range r.providers {
+		names[i] = provider.Name()
+	}
+	return names
+}
+
+// ForType - провайдеры, в каталогах которых есть элементы типа itemType. Пустой тип - все провайдеры
+func (r *Registry) ForType(itemType string) []Provider {
+	if itemType == "" {
+		return r.providers
+	}
+	var providers []Provider
+	for _, provider := range r.providers {
+		for _, t := range provider.Types() {
+			if t == itemType {
+				providers = append(providers, provider)
+				break
+			}
+		}
+	}
+	return providers
+}
+
+// client - общая часть адаптеров: базовый адрес, HTTP-клиент и разбор JSON-ответов
+type client struct {
+	baseURL string
+	http    *http.Client
+	headers map[string]string
+}
+
+func newClient(baseURL string, defaultURL string, httpClient *http.Client) client {
+	if baseURL == "" {
+		baseURL = defaultURL
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: defaultTimeout}
+	}
+	return client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		http:    httpClient,
+		headers: map[string]string{},
+	}
+}
+
+// doJSON выполняет запрос и декодирует ответ. 404 превращается в ErrNotFound
+func (c client) doJSON(ctx context.Context, method string, path string, body io.Reader, target any) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, value := range c.headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %s: %s", resp.Status, strings.TrimSpace(string(snippet)))
+	}
+
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(target); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+func (c client) getJSON(ctx context.Context, path string, target any) error {
+	return c.doJSON(ctx, http.MethodGet, path, nil, target)
+}
+
+func searchLimit(limit int) int {
+	if limit <= 0 || limit > 50 {
+		return defaultSearchLimit
+	}
+	return limit
+}
+
+// yearFromDate достает год из дат вида "1965", "1965-08-01" или "August 1, 1965"
+func yearFromDate(date string) *int {
+	for i := 0; i+4 <= len(date); i++ {
+		if (i > 0 && isDigit(date[i-1])) || (i+4 < len(date) && isDigit(date[i+4])) {
+			continue
+		}
+		year, err := strconv.Atoi(date[i : i+4])
+		if err == nil && isDigit(date[i]) && year >= 1000 && year <= 3000 {
+			return &year
+		}
+	}
+	return nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,184 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeCatalog - HTTP-сервер каталога, отвечающий фикстурами из testdata по пути запроса
+type fakeCatalog struct {
+	*httptest.Server
+	mu       sync.Mutex
+	routes   map[string]func(w http.ResponseWriter, r *http.Request, body []byte)
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func newFakeCatalog(t *testing.T) *fakeCatalog {
+	t.Helper()
+	c := &fakeCatalog{routes: map[string]func(http.ResponseWriter, *http.Request, []byte){}}
+	c.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		c.mu.Lock()
+		c.requests = append(c.requests, r)
+		c.bodies = append(c.bodies, body)
+		route, ok := c.routes[r.URL.Path]
+		c.mu.Unlock()
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		route(w, r, body)
+	}))
+	t.Cleanup(c.Close)
+	return c
+}
+
+// fixture отвечает на path содержимым testdata/name
+func (c *fakeCatalog) fixture(t *testing.T, path string, name string) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	c.handle(path, func(w http.ResponseWriter, r *http.Request, body []byte) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	})
+}
+
+// status отвечает на path кодом status и текстом body
+func (c *fakeCatalog) status(path string, status int, body string) {
+	c.handle(path, func(w http.ResponseWriter, r *http.Request, _ []byte) {
+		http.Error(w, body, status)
+	})
+}
+
+func (c *fakeCatalog) handle(path string, route func(w http.ResponseWriter, r *http.Request, body []byte)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.routes[path] = route
+}
+
+func (c *fakeCatalog) lastRequest(t *testing.T) (*http.Request, []byte) {
+	t.Helper()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.requests) == 0 {
+		t.Fatal("catalog received no requests")
+	}
+	return c.requests[len(c.requests)-1], c.bodies[len(c.bodies)-1]
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func assertResults(t *testing.T, got []Result, want []Result) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d results, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		assertResult(t, &got[i], &want[i])
+	}
+}
+
+func assertResult(t *testing.T, got *Result, want *Result) {
+	t.Helper()
+	if got == nil {
+		t.Fatal("result is nil")
+	}
+	if !reflect.DeepEqual(*got, *want) {
+		t.Errorf("result mismatch\n got: %+v\nwant: %+v", *got, *want)
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	catalog := newFakeCatalog(t)
+	catalog.status("/missing", http.StatusNotFound, "not found")
+	catalog.status("/limited", http.StatusTooManyRequests, "slow down")
+	catalog.handle("/broken", func(w http.ResponseWriter, r *http.Request, _ []byte) {
+		w.Write([]byte(`{"docs": [`))
+	})
+	c := newClient(catalog.URL, "", nil)
+
+	var target map[string]any
+	if err := c.getJSON(t.Context(), "/missing", &target); !errors.Is(err, ErrNotFound) {
+		t.Errorf("404 = %v, want ErrNotFound", err)
+	}
+	err := c.getJSON(t.Context(), "/limited", &target)
+	if err == nil || !strings.Contains(err.Error(), "429") || !strings.Contains(err.Error(), "slow down") {
+		t.Errorf("429 = %v, want error with status and body", err)
+	}
+	if err := c.getJSON(t.Context(), "/broken", &target); err == nil || !strings.Contains(err.Error(), "decode") {
+		t.Errorf("broken json = %v, want decode error", err)
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	if err := c.getJSON(ctx, "/missing", &target); !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled context = %v, want context.Canceled", err)
+	}
+
+	req, _ := catalog.lastRequest(t)
+	if req.Header.Get("User-Agent") != userAgent || req.Header.Get("Accept") != "application/json" {
+		t.Errorf("request headers = %v, want User-Agent and Accept", req.Header)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	books := NewOpenLibrary(OpenLibraryConfig{}, nil)
+	anime := NewAniList(AniListConfig{}, nil)
+	registry := NewRegistry(books, anime, NewOpenLibrary(OpenLibraryConfig{BaseURL: "http://other"}, nil))
+
+	if got := registry.Names(); !reflect.DeepEqual(got, []string{openLibraryName, aniListName}) {
+		t.Errorf("Names = %v, want providers once in registration order", got)
+	}
+	if provider, ok := registry.Get(openLibraryName); !ok || provider != books {
+		t.Errorf("Get(%q) = %v, %v, want first registered provider", openLibraryName, provider, ok)
+	}
+	if _, ok := registry.Get("unknown"); ok {
+		t.Error("Get(unknown) found a provider")
+	}
+	if got := registry.ForType(TypeAnime); len(got) != 1 || got[0] != anime {
+		t.Errorf("ForType(anime) = %v, want [anilist]", got)
+	}
+	if got := registry.ForType(TypeMovies); len(got) != 0 {
+		t.Errorf("ForType(movies) = %v, want none", got)
+	}
+	if got := registry.ForType(""); len(got) != 2 {
+		t.Errorf("ForType(\"\") = %v, want all providers", got)
+	}
+}
+
+func TestYearFromDate(t *testing.T) {
+	tests := []struct {
+		date string
+		want *int
+	}{
+		{"1965", intPtr(1965)},
+		{"1965-08-01", intPtr(1965)},
+		{"August 1, 1965", intPtr(1965)},
+		{"1 августа 1965 г.", intPtr(1965)},
+		{"", nil},
+		{"19650", nil},
+		{"0999", nil},
+		{"unknown", nil},
+	}
+	for _, tt := range tests {
+		got := yearFromDate(tt.date)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("yearFromDate(%q) = %v, want %v", tt.date, got, tt.want)
+		}
+	}
+}
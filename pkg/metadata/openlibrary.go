@@ -0,0 +1,171 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	openLibraryName       = "openlibrary"
+	openLibraryDefaultURL = "https://openlibrary.org"
+	openLibraryCoversURL  = "https://covers.openlibrary.org"
+)
+
+// Ключ произведения OpenLibrary: OL893415W
+var openLibraryWorkID = regexp.MustCompile(`^OL\d+W$`)
+
+type OpenLibraryConfig struct {
+	BaseURL string
+	// CoversURL - адрес сервиса обложек
+	CoversURL string
+}
+
+// OpenLibrary - книги из openlibrary.org. Внешний ID - ключ произведения (work)
+type OpenLibrary struct {
+	client
+	coversURL string
+}
+
+func NewOpenLibrary(cfg OpenLibraryConfig, httpClient *http.Client) *OpenLibrary {
+	coversURL := cfg.CoversURL
+	if coversURL == "" {
+		coversURL = openLibraryCoversURL
+	}
+	return &OpenLibrary{
+		client:    newClient(cfg.BaseURL, openLibraryDefaultURL, httpClient),
+		coversURL: strings.TrimSuffix(coversURL, "/"),
+	}
+}
+
+func (p *OpenLibrary) Name() string {
+	return openLibraryName
+}
+
+func (p *OpenLibrary) Types() []string {
+	return []string{TypeBooks}
+}
+
+type openLibrarySearchResponse struct {
+	Docs []struct {
+		Key              string   `json:"key"`
+		Title            string   `json:"title"`
+		FirstPublishYear *int     `json:"first_publish_year"`
+		CoverID          int      `json:"cover_i"`
+		AuthorName       []string `json:"author_name"`
+		Subject          []string `json:"subject"`
+	} `json:"docs"`
+}
+
+// Search ищет по ISBN, если он задан, иначе по тексту
+func (p *OpenLibrary) Search(ctx context.Context, query Query) ([]Result, error) {
+	params := url.Values{}
+	if isbn := normalizeISBN(query.ISBN); isbn != "" {
+		params.Set("isbn", isbn)
+	} else {
+		params.Set("q", query.Text)
+	}
+	params.Set("limit", fmt.Sprint(searchLimit(query.Limit)))
+	params.Set("fields", "key,title,first_publish_year,cover_i,author_name,subject")
+
+	var resp openLibrarySearchResponse
+	if err := p.getJSON(ctx, "/search.json?"+params.Encode(), &resp); err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0, len(resp.Docs))
+	for _, doc := range resp.Docs {
+		id := strings.TrimPrefix(doc.Key, "/works/")
+		results = append(results, Result{
+			Provider:    openLibraryName,
+			ExternalID:  id,
+			Type:        TypeBooks,
+			Title:       doc.Title,
+			CoverURL:    p.coverURL(doc.CoverID),
+			ReleaseYear: doc.FirstPublishYear,
+			Genres:      firstN(doc.Subject, 5),
+			Authors:     doc.AuthorName,
+			URL:         openLibraryDefaultURL + "/works/" + id,
+		})
+	}
+	return results, nil
+}
+
+type openLibraryWork struct {
+	Key              string          `json:"key"`
+	Title            string          `json:"title"`
+	Description      json.RawMessage `json:"description"`
+	Covers           []int           `json:"covers"`
+	Subjects         []string        `json:"subjects"`
+	FirstPublishDate string          `json:"first_publish_date"`
+}
+
+func (p *OpenLibrary) Get(ctx context.Context, externalID string) (*Result, error) {
+	if !openLibraryWorkID.MatchString(externalID) {
+		return nil, ErrInvalidExternalID
+	}
+
+	var work openLibraryWork
+	if err := p.getJSON(ctx, "/works/"+externalID+".json", &work); err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Provider:    openLibraryName,
+		ExternalID:  externalID,
+		Type:        TypeBooks,
+		Title:       work.Title,
+		Description: openLibraryText(work.Description),
+		ReleaseYear: yearFromDate(work.FirstPublishDate),
+		Genres:      firstN(work.Subjects, 5),
+		URL:         openLibraryDefaultURL + "/works/" + externalID,
+	}
+	if len(work.Covers) > 0 {
+		result.CoverURL = p.coverURL(work.Covers[0])
+	}
+	return result, nil
+}
+
+func (p *OpenLibrary) coverURL(coverID int) string {
+	if coverID <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s/b/id/%d-L.jpg", p.coversURL, coverID)
+}
+
+// openLibraryText - описание приходит строкой или объектом {"type": "/type/text", "value": "..."}
+func openLibraryText(raw json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+	var typed struct {
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal(raw, &typed); err == nil {
+		return typed.Value
+	}
+	return ""
+}
+
+// normalizeISBN оставляет только цифры и X
+func normalizeISBN(isbn string) string {
+	var b strings.Builder
+	for _, c := range strings.ToUpper(isbn) {
+		if (c >= '0' && c <= '9') || c == 'X' {
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+func firstN(values []string, n int) []string {
+	if len(values) > n {
+		return values[:n]
+	}
+	return values
+}
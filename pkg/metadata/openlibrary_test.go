@@ -0,0 +1,122 @@
+package metadata
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func newTestOpenLibrary(t *testing.T) (*OpenLibrary, *fakeCatalog) {
+	t.Helper()
+	catalog := newFakeCatalog(t)
+	return NewOpenLibrary(OpenLibraryConfig{BaseURL: catalog.URL, CoversURL: "https://covers.test/"}, catalog.Client()), catalog
+}
+
+func TestOpenLibrarySearch(t *testing.T) {
+	provider, catalog := newTestOpenLibrary(t)
+	catalog.fixture(t, "/search.json", "openlibrary_search.json")
+
+	results, err := provider.Search(t.Context(), Query{Text: "dune", Limit: 2})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	assertResults(t, results, []Result{
+		{
+			Provider:    openLibraryName,
+			ExternalID:  "OL893415W",
+			Type:        TypeBooks,
+			Title:       "Dune",
+			CoverURL:    "https://covers.test/b/id/11481354-L.jpg",
+			ReleaseYear: intPtr(1965),
+			Genres:      []string{"Science fiction", "Dune (Imaginary place)", "Fiction", "Life on other planets", "Deserts"},
+			Authors:     []string{"Frank Herbert"},
+			URL:         "https://openlibrary.org/works/OL893415W",
+		},
+		{
+			Provider:   openLibraryName,
+			ExternalID: "OL45804W",
+			Type:       TypeBooks,
+			Title:      "Dune Messiah",
+			Authors:    []string{"Frank Herbert"},
+			URL:        "https://openlibrary.org/works/OL45804W",
+		},
+	})
+
+	req, _ := catalog.lastRequest(t)
+	query := req.URL.Query()
+	if query.Get("q") != "dune" || query.Get("limit") != "2" || query.Has("isbn") {
+		t.Errorf("search query = %v, want q=dune&limit=2", query)
+	}
+}
+
+func TestOpenLibrarySearchByISBN(t *testing.T) {
+	provider, catalog := newTestOpenLibrary(t)
+	catalog.fixture(t, "/search.json", "openlibrary_search.json")
+
+	if _, err := provider.Search(t.Context(), Query{Text: "dune", ISBN: "978-0-441-17271-9", Limit: 100}); err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	req, _ := catalog.lastRequest(t)
+	query := req.URL.Query()
+	if query.Get("isbn") != "9780441172719" || query.Has("q") {
+		t.Errorf("search query = %v, want normalized isbn without text", query)
+	}
+	if query.Get("limit") != "10" {
+		t.Errorf("limit = %s, want default 10 for out of range limit", query.Get("limit"))
+	}
+}
+
+func TestOpenLibraryGet(t *testing.T) {
+	provider, catalog := newTestOpenLibrary(t)
+	catalog.fixture(t, "/works/OL893415W.json", "openlibrary_work.json")
+
+	result, err := provider.Get(t.Context(), "OL893415W")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	assertResult(t, result, &Result{
+		Provider:    openLibraryName,
+		ExternalID:  "OL893415W",
+		Type:        TypeBooks,
+		Title:       "Dune",
+		Description: "Set on the desert planet Arrakis, Dune is the story of the boy Paul Atreides.",
+		CoverURL:    "https://covers.test/b/id/11481354-L.jpg",
+		ReleaseYear: intPtr(1965),
+		Genres:      []string{"Science fiction", "Dune (Imaginary place)", "Fiction", "Life on other planets", "Deserts"},
+		URL:         "https://openlibrary.org/works/OL893415W",
+	})
+}
+
+func TestOpenLibraryGetErrors(t *testing.T) {
+	provider, catalog := newTestOpenLibrary(t)
+	catalog.status("/works/OL2W.json", http.StatusInternalServerError, "boom")
+
+	for _, id := range []string{"", "OL1M", "OL1W/../../admin", "893415"} {
+		if _, err := provider.Get(t.Context(), id); !errors.Is(err, ErrInvalidExternalID) {
+			t.Errorf("Get(%q) = %v, want ErrInvalidExternalID", id, err)
+		}
+	}
+	if _, err := provider.Get(t.Context(), "OL1W"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get of missing work = %v, want ErrNotFound", err)
+	}
+	if _, err := provider.Get(t.Context(), "OL2W"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("Get on server error = %v, want non-ErrNotFound error", err)
+	}
+}
+
+func TestOpenLibraryText(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{`"plain"`, "plain"},
+		{`{"type": "/type/text", "value": "typed"}`, "typed"},
+		{`null`, ""},
+		{`42`, ""},
+	}
+	for _, tt := range tests {
+		if got := openLibraryText([]byte(tt.raw)); got != tt.want {
+			t.Errorf("openLibraryText(%s) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	shikimoriName       = "shikimori"
+	shikimoriDefaultURL = "https://shikimori.one"
+)
+
+// Разметка описаний Shikimori: [character=123]Имя[/character], [i]...[/i]
+var shikimoriMarkup = regexp.MustCompile(`\[/?[a-z_]+(=[^\]]*)?\]`)
+
+type ShikimoriConfig struct {
+	// BaseURL - адрес сайта, API находится по пути /api
+	BaseURL string
+}
+
+// Shikimori - аниме из shikimori.one с русскими названиями. Внешний ID - числовой ID Shikimori
+type Shikimori struct {
+	client
+}
+
+func NewShikimori(cfg ShikimoriConfig, httpClient *http.Client) *Shikimori {
+	return &Shikimori{client: newClient(cfg.BaseURL, shikimoriDefaultURL, httpClient)}
+}
+
+func (p *Shikimori) Name() string {
+	return shikimoriName
+}
+
+func (p *Shikimori) Types() []string {
+	return []string{TypeAnime}
+}
+
+type shikimoriAnime struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Russian     string `json:"russian"`
+	URL         string `json:"url"`
+	AiredOn     string `json:"aired_on"`
+	Description string `json:"description"`
+	Image       struct {
+		Original string `json:"original"`
+	} `json:"image"`
+	Genres []struct {
+		Name    string `json:"name"`
+		Russian string `json:"russian"`
+	} `json:"genres"`
+}
+
+func (p *Shikimori) Search(ctx context.Context, query Query) ([]Result, error) {
+	params := url.Values{}
+	params.Set("search", query.Text)
+	params.Set("limit", fmt.Sprint(searchLimit(query.Limit)))
+
+	var animes []shikimoriAnime
+	if err := p.getJSON(ctx, "/api/animes?"+params.Encode(), &animes); err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0, len(animes))
+	for _, anime := range animes {
+		results = append(results, p.result(anime))
+	}
+	return results, nil
+}
+
+func (p *Shikimori) Get(ctx context.Context, externalID string) (*Result, error) {
+	if !numericExternalID.MatchString(externalID) {
+		return nil, ErrInvalidExternalID
+	}
+
+	var anime shikimoriAnime
+	if err := p.getJSON(ctx, "/api/animes/"+externalID, &anime); err != nil {
+		return nil, err
+	}
+
+	result := p.result(anime)
+	return &result, nil
+}
+
+func (p *Shikimori) result(anime shikimoriAnime) Result {
+	title := anime.Russian
+	if title == "" {
+		title = anime.Name
+	}
+	result := Result{
+		Provider:    shikimoriName,
+		ExternalID:  fmt.Sprint(anime.ID),
+		Type:        TypeAnime,
+		Title:       title,
+		Description: strings.TrimSpace(shikimoriMarkup.ReplaceAllString(anime.Description, "")),
+		ReleaseYear: yearFromDate(anime.AiredOn),
+		URL:         p.absolute(anime.URL),
+	}
+	// Ссылки на изображения относительные, заглушка "missing_original" - не обложка
+	if anime.Image.Original != "" && !strings.Contains(anime.Image.Original, "missing") {
+		result.CoverURL = p.absolute(anime.Image.Original)
+	}
+	for _, genre := range anime.Genres {
+		name := genre.Russian
+		if name == "" {
+			name = genre.Name
+		}
+		result.Genres = append(result.Genres, name)
+	}
+	return result
+}
+
+func (p *Shikimori) absolute(path string) string {
+	if path == "" || strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	return p.baseURL + path
+}
@@ -0,0 +1,101 @@
+package metadata
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func newTestShikimori(t *testing.T) (*Shikimori, *fakeCatalog) {
+	t.Helper()
+	catalog := newFakeCatalog(t)
+	return NewShikimori(ShikimoriConfig{BaseURL: catalog.URL}, catalog.Client()), catalog
+}
+
+func TestShikimoriSearch(t *testing.T) {
+	provider, catalog := newTestShikimori(t)
+	catalog.fixture(t, "/api/animes", "shikimori_search.json")
+
+	results, err := provider.Search(t.Context(), Query{Text: "бибоп", Limit: 2})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	assertResults(t, results, []Result{
+		{
+			Provider:    shikimoriName,
+			ExternalID:  "1",
+			Type:        TypeAnime,
+			Title:       "Ковбой Бибоп",
+			CoverURL:    catalog.URL + "/system/animes/original/1.jpg",
+			ReleaseYear: intPtr(1998),
+			URL:         catalog.URL + "/animes/1-cowboy-bebop",
+		},
+		{
+			Provider:   shikimoriName,
+			ExternalID: "59999",
+			Type:       TypeAnime,
+			Title:      "Untitled Project",
+			URL:        catalog.URL + "/animes/59999-untitled-project",
+		},
+	})
+
+	req, _ := catalog.lastRequest(t)
+	if query := req.URL.Query(); query.Get("search") != "бибоп" || query.Get("limit") != "2" {
+		t.Errorf("search query = %v, want search and limit", query)
+	}
+}
+
+func TestShikimoriGet(t *testing.T) {
+	provider, catalog := newTestShikimori(t)
+	catalog.fixture(t, "/api/animes/1", "shikimori_anime.json")
+
+	result, err := provider.Get(t.Context(), "1")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	assertResult(t, result, &Result{
+		Provider:    shikimoriName,
+		ExternalID:  "1",
+		Type:        TypeAnime,
+		Title:       "Ковбой Бибоп",
+		Description: "2071 год. Человечество колонизировало Солнечную систему. Спайк Шпигель и Джет Блэк - охотники за головами.",
+		CoverURL:    catalog.URL + "/system/animes/original/1.jpg",
+		ReleaseYear: intPtr(1998),
+		Genres:      []string{"Экшен", "Sci-Fi"},
+		URL:         catalog.URL + "/animes/1-cowboy-bebop",
+	})
+}
+
+func TestShikimoriGetErrors(t *testing.T) {
+	provider, catalog := newTestShikimori(t)
+	catalog.status("/api/animes/2", http.StatusTooManyRequests, "Retry later")
+
+	for _, id := range []string{"", "z1", "1/related", "../users"} {
+		if _, err := provider.Get(t.Context(), id); !errors.Is(err, ErrInvalidExternalID) {
+			t.Errorf("Get(%q) = %v, want ErrInvalidExternalID", id, err)
+		}
+	}
+	if _, err := provider.Get(t.Context(), "1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get of missing anime = %v, want ErrNotFound", err)
+	}
+	if _, err := provider.Get(t.Context(), "2"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("Get when rate limited = %v, want non-ErrNotFound error", err)
+	}
+}
+
+func TestShikimoriAbsoluteURLs(t *testing.T) {
+	provider := NewShikimori(ShikimoriConfig{BaseURL: "https://shiki.test/"}, nil)
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"", ""},
+		{"/animes/1", "https://shiki.test/animes/1"},
+		{"https://cdn.test/1.jpg", "https://cdn.test/1.jpg"},
+	}
+	for _, tt := range tests {
+		if got := provider.absolute(tt.path); got != tt.want {
+			t.Errorf("absolute(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
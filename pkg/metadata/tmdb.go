@@ -0,0 +1,170 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	tmdbName            = "tmdb"
+	tmdbDefaultURL      = "https://api.themoviedb.org/3"
+	tmdbDefaultImageURL = "https://image.tmdb.org/t/p/w500"
+	tmdbSiteURL         = "https://www.themoviedb.org"
+)
+
+// Внешний ID TMDB включает тип записи: movie/603, tv/1399
+var tmdbExternalID = regexp.MustCompile(`^(movie|tv)/(\d+)$`)
+
+type TMDBConfig struct {
+	BaseURL string
+	// ImageURL - префикс ссылок на постеры с нужным размером
+	ImageURL string
+	// APIKey - ключ API v3, передается в query. AccessToken - токен чтения API v4, передается в заголовке
+	APIKey      string
+	AccessToken string
+	// Language - язык названий и описаний, например ru-RU
+	Language string
+}
+
+// TMDB - фильмы и сериалы из themoviedb.org
+type TMDB struct {
+	client
+	imageURL string
+	apiKey   string
+	language string
+}
+
+func NewTMDB(cfg TMDBConfig, httpClient *http.Client) *TMDB {
+	p := &TMDB{
+		client:   newClient(cfg.BaseURL, tmdbDefaultURL, httpClient),
+		imageURL: strings.TrimSuffix(cfg.ImageURL, "/"),
+		apiKey:   cfg.APIKey,
+		language: cfg.Language,
+	}
+	if p.imageURL == "" {
+		p.imageURL = tmdbDefaultImageURL
+	}
+	if cfg.AccessToken != "" {
+		p.headers["Authorization"] = "Bearer " + cfg.AccessToken
+	}
+	return p
+}
+
+func (p *TMDB) Name() string {
+	return tmdbName
+}
+
+func (p *TMDB) Types() []string {
+	return []string{TypeMovies, TypeSeries}
+}
+
+// tmdbMedia - общие поля фильмов и сериалов в ответах поиска и карточки
+type tmdbMedia struct {
+	ID           int    `json:"id"`
+	MediaType    string `json:"media_type"`
+	Title        string `json:"title"`
+	Name         string `json:"name"`
+	Overview     string `json:"overview"`
+	PosterPath   string `json:"poster_path"`
+	ReleaseDate  string `json:"release_date"`
+	FirstAirDate string `json:"first_air_date"`
+	Genres       []struct {
+		Name string `json:"name"`
+	} `json:"genres"`
+}
+
+// Search ищет фильмы или сериалы в зависимости от типа, без типа - и те, и другие
+func (p *TMDB) Search(ctx context.Context, query Query) ([]Result, error) {
+	endpoint := "/search/multi"
+	switch query.Type {
+	case TypeMovies:
+		endpoint = "/search/movie"
+	case TypeSeries:
+		endpoint = "/search/tv"
+	}
+
+	params := p.params()
+	params.Set("query", query.Text)
+
+	var resp struct {
+		Results []tmdbMedia `json:"results"`
+	}
+	if err := p.getJSON(ctx, endpoint+"?"+params.Encode(), &resp); err != nil {
+		return nil, err
+	}
+
+	limit := searchLimit(query.Limit)
+	results := make([]Result, 0, limit)
+	for _, media := range resp.Results {
+		mediaType := media.MediaType
+		switch query.Type {
+		case TypeMovies:
+			mediaType = "movie"
+		case TypeSeries:
+			mediaType = "tv"
+		}
+		// multi-поиск возвращает еще и людей
+		if mediaType != "movie" && mediaType != "tv" {
+			continue
+		}
+		results = append(results, p.result(mediaType, media))
+		if len(results) == limit {
+			break
+		}
+	}
+	return results, nil
+}
+
+func (p *TMDB) Get(ctx context.Context, externalID string) (*Result, error) {
+	match := tmdbExternalID.FindStringSubmatch(externalID)
+	if match == nil {
+		return nil, ErrInvalidExternalID
+	}
+
+	var media tmdbMedia
+	if err := p.getJSON(ctx, "/"+externalID+"?"+p.params().Encode(), &media); err != nil {
+		return nil, err
+	}
+
+	result := p.result(match[1], media)
+	return &result, nil
+}
+
+func (p *TMDB) result(mediaType string, media tmdbMedia) Result {
+	result := Result{
+		Provider:    tmdbName,
+		ExternalID:  fmt.Sprintf("%s/%d", mediaType, media.ID),
+		Type:        TypeMovies,
+		Title:       media.Title,
+		Description: media.Overview,
+		ReleaseYear: yearFromDate(media.ReleaseDate),
+		URL:         fmt.Sprintf("%s/%s/%d", tmdbSiteURL, mediaType, media.ID),
+	}
+	if mediaType == "tv" {
+		result.Type = TypeSeries
+		result.Title = media.Name
+		result.ReleaseYear = yearFromDate(media.FirstAirDate)
+	}
+	if media.PosterPath != "" {
+		result.CoverURL = p.imageURL + media.PosterPath
+	}
+	for _, genre := range media.Genres {
+		result.Genres = append(result.Genres, genre.Name)
+	}
+	return result
+}
+
+func (p *TMDB) params() url.Values {
+	params := url.Values{}
+	if p.apiKey != "" {
+		params.Set("api_key", p.apiKey)
+	}
+	if p.language != "" {
+		params.Set("language", p.language)
+	}
+	return params
+}
@@ -0,0 +1,143 @@
+package metadata
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func newTestTMDB(t *testing.T, cfg TMDBConfig) (*TMDB, *fakeCatalog) {
+	t.Helper()
+	catalog := newFakeCatalog(t)
+	cfg.BaseURL = catalog.URL
+	cfg.ImageURL = "https://images.test/w500/"
+	return NewTMDB(cfg, catalog.Client()), catalog
+}
+
+func TestTMDBSearchMulti(t *testing.T) {
+	provider, catalog := newTestTMDB(t, TMDBConfig{APIKey: "key", Language: "ru-RU"})
+	catalog.fixture(t, "/search/multi", "tmdb_search_multi.json")
+
+	results, err := provider.Search(t.Context(), Query{Text: "matrix"})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	assertResults(t, results, []Result{
+		{
+			Provider:    tmdbName,
+			ExternalID:  "movie/603",
+			Type:        TypeMovies,
+			Title:       "The Matrix",
+			Description: "Set in the 22nd century, The Matrix tells the story of a computer hacker.",
+			CoverURL:    "https://images.test/w500/f89U3ADr1oiB1s9GkdPOEpXUk5H.jpg",
+			ReleaseYear: intPtr(1999),
+			URL:         "https://www.themoviedb.org/movie/603",
+		},
+		{
+			Provider:    tmdbName,
+			ExternalID:  "tv/1399",
+			Type:        TypeSeries,
+			Title:       "Game of Thrones",
+			Description: "Seven noble families fight for control of the mythical land of Westeros.",
+			CoverURL:    "https://images.test/w500/1XS1oqL89opfnbLl8WnZY1O1uJx.jpg",
+			ReleaseYear: intPtr(2011),
+			URL:         "https://www.themoviedb.org/tv/1399",
+		},
+	})
+
+	req, _ := catalog.lastRequest(t)
+	query := req.URL.Query()
+	if query.Get("query") != "matrix" || query.Get("api_key") != "key" || query.Get("language") != "ru-RU" {
+		t.Errorf("search query = %v, want query, api_key and language", query)
+	}
+	if req.Header.Get("Authorization") != "" {
+		t.Errorf("Authorization = %q, want none with api key", req.Header.Get("Authorization"))
+	}
+}
+
+func TestTMDBSearchByType(t *testing.T) {
+	provider, catalog := newTestTMDB(t, TMDBConfig{AccessToken: "token"})
+	catalog.fixture(t, "/search/tv", "tmdb_search_multi.json")
+
+	results, err := provider.Search(t.Context(), Query{Text: "thrones", Type: TypeSeries, Limit: 1})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	// Поиск по типу не возвращает media_type: все записи считаются сериалами, лимит обрезает ответ
+	if len(results) != 1 || results[0].ExternalID != "tv/603" || results[0].Type != TypeSeries {
+		t.Errorf("Search = %+v, want one series tv/603", results)
+	}
+
+	req, _ := catalog.lastRequest(t)
+	if req.Header.Get("Authorization") != "Bearer token" {
+		t.Errorf("Authorization = %q, want bearer token", req.Header.Get("Authorization"))
+	}
+	if req.URL.Query().Has("api_key") {
+		t.Errorf("query = %v, want no api_key with access token", req.URL.Query())
+	}
+}
+
+func TestTMDBGet(t *testing.T) {
+	provider, catalog := newTestTMDB(t, TMDBConfig{APIKey: "key"})
+	catalog.fixture(t, "/movie/603", "tmdb_movie.json")
+	catalog.fixture(t, "/tv/1399", "tmdb_tv.json")
+
+	movie, err := provider.Get(t.Context(), "movie/603")
+	if err != nil {
+		t.Fatalf("Get movie: %v", err)
+	}
+	assertResult(t, movie, &Result{
+		Provider:    tmdbName,
+		ExternalID:  "movie/603",
+		Type:        TypeMovies,
+		Title:       "Матрица",
+		Description: "Жизнь Томаса Андерсона разделена на две части.",
+		CoverURL:    "https://images.test/w500/f89U3ADr1oiB1s9GkdPOEpXUk5H.jpg",
+		ReleaseYear: intPtr(1999),
+		Genres:      []string{"боевик", "фантастика"},
+		URL:         "https://www.themoviedb.org/movie/603",
+	})
+
+	series, err := provider.Get(t.Context(), "tv/1399")
+	if err != nil {
+		t.Fatalf("Get series: %v", err)
+	}
+	assertResult(t, series, &Result{
+		Provider:    tmdbName,
+		ExternalID:  "tv/1399",
+		Type:        TypeSeries,
+		Title:       "Игра престолов",
+		Description: "К концу подходит время благоденствия.",
+		ReleaseYear: intPtr(2011),
+		Genres:      []string{"Sci-Fi & Fantasy", "драма"},
+		URL:         "https://www.themoviedb.org/tv/1399",
+	})
+}
+
+func TestTMDBGetErrors(t *testing.T) {
+	provider, catalog := newTestTMDB(t, TMDBConfig{APIKey: "key"})
+	catalog.status("/movie/2", http.StatusUnauthorized, `{"status_message": "Invalid API key"}`)
+
+	for _, id := range []string{"", "603", "person/6384", "movie/abc", "movie/603/credits"} {
+		if _, err := provider.Get(t.Context(), id); !errors.Is(err, ErrInvalidExternalID) {
+			t.Errorf("Get(%q) = %v, want ErrInvalidExternalID", id, err)
+		}
+	}
+	if _, err := provider.Get(t.Context(), "movie/1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get of missing movie = %v, want ErrNotFound", err)
+	}
+	if _, err := provider.Get(t.Context(), "movie/2"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("Get with invalid key = %v, want non-ErrNotFound error", err)
+	}
+}
+
+func TestTMDBRequiresCredentials(t *testing.T) {
+	registry := NewRegistryFromConfig(Config{TMDB: ProviderConfig[TMDBConfig]{Enabled: true}})
+	if _, ok := registry.Get(tmdbName); ok {
+		t.Error("TMDB registered without api key or access token")
+	}
+	registry = NewRegistryFromConfig(Config{TMDB: ProviderConfig[TMDBConfig]{Enabled: true, Config: TMDBConfig{AccessToken: "token"}}})
+	if _, ok := registry.Get(tmdbName); !ok {
+		t.Error("TMDB not registered with access token")
+	}
+}
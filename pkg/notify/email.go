@@ -0,0 +1,146 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// EmailChannelName - имя почтового канала в настройках уведомлений
+const EmailChannelName = "email"
+
+var ErrNoEmail = errors.New("recipient has no email")
+
+// SMTPConfig - настройки почтового сервера. Канал подключается только при заданном Host
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	// From - адрес отправителя, например "Memoria <noreply@example.com>"
+	From    string
+	Timeout time.Duration
+}
+
+// Email отправляет уведомления письмом через SMTP. Если сервер поддерживает STARTTLS, соединение шифруется
+type Email struct {
+	cfg  SMTPConfig
+	from *mail.Address
+}
+
+func NewEmail(cfg SMTPConfig) (*Email, error) {
+	if cfg.Host == "" {
+		return nil, errors.New("smtp host is required")
+	}
+	if cfg.Port == 0 {
+		cfg.Port = 587
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address: %w", err)
+	}
+	return &Email{cfg: cfg, from: from}, nil
+}
+
+func (e *Email) Name() string { return EmailChannelName }
+
+func (e *Email) Send(ctx context.Context, msg Message) error {
+	if msg.To.Email == "" {
+		return ErrNoEmail
+	}
+	to := &mail.Address{Name: msg.To.Name, Address: msg.To.Email}
+
+	ctx, cancel := context.WithTimeout(ctx, e.cfg.Timeout)
+	defer cancel()
+	addr := net.JoinHostPort(e.cfg.Host, strconv.Itoa(e.cfg.Port))
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, e.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start smtp session: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: e.cfg.Host}); err != nil {
+			return fmt.Errorf("failed to start tls: %w", err)
+		}
+	}
+	if e.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", e.cfg.Username, e.cfg.Password, e.cfg.Host)); err != nil {
+			return fmt.Errorf("smtp authentication failed: %w", err)
+		}
+	}
+	if err := client.Mail(e.from.Address); err != nil {
+		return fmt.Errorf("smtp MAIL failed: %w", err)
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return fmt.Errorf("smtp RCPT failed: %w", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA failed: %w", err)
+	}
+	if _, err := w.Write(e.message(to, msg)); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	return client.Quit()
+}
+
+// message собирает письмо: заголовки в кодировке RFC 2047, тело - UTF-8 как есть (8bit)
+func (e *Email) message(to *mail.Address, msg Message) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", e.from.String())
+	fmt.Fprintf(&b, "To: %s\r\n", to.String())
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", msg.CreatedAt.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(normalizeNewlines(msg.Text))
+	if msg.Link != "" {
+		b.WriteString("\r\n\r\n")
+		b.WriteString(msg.Link)
+	}
+	b.WriteString("\r\n")
+	return b.Bytes()
+}
+
+func normalizeNewlines(text string) string {
+	var b bytes.Buffer
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '\r':
+			if i+1 < len(text) && text[i+1] == '\n' {
+				i++
+			}
+			b.WriteString("\r\n")
+		case '\n':
+			b.WriteString("\r\n")
+		default:
+			b.WriteByte(text[i])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,67 @@
+// Package notify доставляет уведомления пользователям вне приложения: по почте и другим каналам.
+// Каналы подключаются через Registry, имя канала совпадает со значением настройки уведомлений пользователя
+package notify
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// Recipient - получатель уведомления
+type Recipient struct {
+	UserID int
+	Name   string
+	Email  string
+}
+
+// Message - уведомление, готовое к отправке
+type Message struct {
+	To      Recipient
+	Type    string
+	Subject string
+	Text    string
+	// Link - страница, к которой относится уведомление, может быть пустой
+	Link      string
+	CreatedAt time.Time
+}
+
+// Channel - канал доставки
+type Channel interface {
+	// Name - имя канала в настройках уведомлений (email)
+	Name() string
+	Send(ctx context.Context, msg Message) error
+}
+
+// Registry - подключенные каналы доставки
+type Registry struct {
+	byName map[string]Channel
+}
+
+func NewRegistry(channels ...Channel) *Registry {
+	r := &Registry{byName: map[string]Channel{}}
+	for _, channel := range channels {
+		r.Register(channel)
+	}
+	return r
+}
+
+// Register добавляет канал, канал с тем же именем заменяется
+func (r *Registry) Register(channel Channel) {
+	r.byName[channel.Name()] = channel
+}
+
+func (r *Registry) Get(name string) (Channel, bool) {
+	channel, ok := r.byName[name]
+	return channel, ok
+}
+
+// Names - имена подключенных каналов по алфавиту
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.byName))
+	for name := range r.byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,172 @@
+package pagination
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Секрет подписи курсоров. По умолчанию случайный - курсоры становятся
+// невалидными после рестарта, для нескольких инстансов задайте общий через SetCursorSecret
+var cursorSecret = randomSecret()
+
+func randomSecret() []byte {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		panic("pagination: failed to generate cursor secret: " + err.Error())
+	}
+	return secret
+}
+
+// SetCursorSecret задает секрет подписи курсоров. Вызывать при старте приложения
+func SetCursorSecret(secret string) {
+	if secret != "" {
+		cursorSecret = []byte(secret)
+	}
+}
+
+// Cursor - позиция в keyset-пагинации: значения ключа сортировки и id последней/первой строки.
+// Клиенту отдается непрозрачной подписанной строкой
+type Cursor struct {
+	Scope    string   `json:"s"` // Выборка и сортировка, для которой выдан курсор
+	Keys     []string `json:"k"`
+	Backward bool     `json:"b,omitempty"` // true - курсор на предыдущую страницу
+}
+
+// cursorPayload - Cursor без MarshalJSON, чтобы сериализация не зацикливалась
+type cursorPayload Cursor
+
+// Encode сериализует курсор в строку вида payload.signature (base64url)
+func (c Cursor) Encode() string {
+	payload, _ := json.Marshal(cursorPayload(c))
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(signCursor(encoded))
+}
+
+func (c Cursor) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.Encode())
+}
+
+// DecodeCursor проверяет подпись и разбирает курсор
+func DecodeCursor(value string) (*Cursor, error) {
+	encoded, signature, ok := strings.Cut(value, ".")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(sig, signCursor(encoded)) {
+		return nil, ErrInvalidCursor
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor cursorPayload
+	if err := json.Unmarshal(payload, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	result := Cursor(cursor)
+	return &result, nil
+}
+
+func signCursor(encoded string) []byte {
+	mac := hmac.New(sha256.New, cursorSecret)
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
+
+// CursorRequest - запрос страницы в keyset-режиме. Cursor = nil - первая страница
+type CursorRequest struct {
+	cursor    *Cursor
+	limit     int
+	withTotal bool
+}
+
+// Конструктор с валидацией лимита, как у PaginationRequest
+func NewCursorRequest(cursor *Cursor, limit int, withTotal bool) CursorRequest {
+	if limit < 1 {
+		limit = minPaginationlimit
+	}
+	if limit > maxPaginationLimit {
+		limit = maxPaginationLimit
+	}
+
+	return CursorRequest{
+		cursor:    cursor,
+		limit:     limit,
+		withTotal: withTotal,
+	}
+}
+
+func (cr CursorRequest) Cursor() *Cursor {
+	return cr.cursor
+}
+
+func (cr CursorRequest) Limit() int {
+	return cr.limit
+}
+
+// FetchLimit - сколько строк запрашивать: на одну больше, чтобы понять, есть ли следующая страница
+func (cr CursorRequest) FetchLimit() int {
+	return cr.limit + 1
+}
+
+func (cr CursorRequest) Backward() bool {
+	return cr.cursor != nil && cr.cursor.Backward
+}
+
+// WithTotal - нужен ли COUNT(*) по всей выборке
+func (cr CursorRequest) WithTotal() bool {
+	return cr.withTotal
+}
+
+type CursorPagination struct {
+	Next  *Cursor `json:"next" swaggertype:"string"`
+	Prev  *Cursor `json:"prev" swaggertype:"string"`
+	Limit int     `json:"limit"`
+	Total *int64  `json:"total,omitempty"`
+}
+
+type CursorResponse[T any] struct {
+	Data       []T              `json:"data"`
+	Pagination CursorPagination `json:"pagination"`
+}
+
+// NewCursorResponse собирает страницу из строк, полученных с лимитом FetchLimit в порядке запроса
+// (для Backward - в обратном порядке сортировки). keys возвращает значения ключа сортировки строки
+func NewCursorResponse[T any](rows []T, req CursorRequest, scope string, keys func(T) []string, total *int64) *CursorResponse[T] {
+	hasMore := len(rows) > req.limit
+	if hasMore {
+		rows = rows[:req.limit]
+	}
+	if req.Backward() {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+
+	response := &CursorResponse[T]{
+		Data:       rows,
+		Pagination: CursorPagination{Limit: req.limit, Total: total},
+	}
+	if len(rows) == 0 {
+		return response
+	}
+
+	first, last := rows[0], rows[len(rows)-1]
+	// Назад листали - следующая страница точно есть, вперед - предыдущая есть, если пришли по курсору
+	if hasMore || req.Backward() {
+		response.Pagination.Next = &Cursor{Scope: scope, Keys: keys(last)}
+	}
+	if (req.Backward() && hasMore) || (!req.Backward() && req.cursor != nil) {
+		response.Pagination.Prev = &Cursor{Scope: scope, Keys: keys(first), Backward: true}
+	}
+	return response
+}
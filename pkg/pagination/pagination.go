@@ -7,6 +7,8 @@ import (
 const (
 	minPaginationlimit = 10
 	maxPaginationLimit = 100
+	// LIMIT 0 в Postgres возвращает пустой результат, поэтому "без лимита" - это заведомо большой лимит
+	unlimitedPaginationLimit = math.MaxInt32
 )
 
 type PaginationResponse struct {
@@ -48,7 +50,7 @@ func NewPaginationRequest(page, limit int) PaginationRequest {
 func NewUnlimitedPagination() PaginationRequest {
 	return PaginationRequest{
 		page:  1,
-		limit: 0, // 0 может означать "без лимита"
+		limit: unlimitedPaginationLimit,
 	}
 }
 
@@ -0,0 +1,122 @@
+package realtime
+
+import "sync"
+
+// DefaultSubscriptionBuffer - сколько событий ждет отправки одному клиенту
+const DefaultSubscriptionBuffer = 64
+
+// Hub раздает события подключениям этого экземпляра: пользователю - его события и события его коллекций,
+// остальным - события публичных коллекций, за которыми они следят
+type Hub struct {
+	mu           sync.RWMutex
+	byUser       map[int]map[*Subscription]struct{}
+	byCollection map[string]map[*Subscription]struct{}
+	buffer       int
+}
+
+// Subscription - одно подключение клиента
+type Subscription struct {
+	UserID      int
+	collections []string
+	events      chan Event
+	closed      bool
+	hub         *Hub
+}
+
+func NewHub(buffer int) *Hub {
+	if buffer <= 0 {
+		buffer = DefaultSubscriptionBuffer
+	}
+	return &Hub{
+		byUser:       map[int]map[*Subscription]struct{}{},
+		byCollection: map[string]map[*Subscription]struct{}{},
+		buffer:       buffer,
+	}
+}
+
+// Subscribe подключает клиента пользователя userID. collectionIDs - коллекции, за которыми клиент следит,
+// права на них проверяет вызывающий
+func (h *Hub) Subscribe(userID int, collectionIDs []string) *Subscription {
+	sub := &Subscription{
+		UserID:      userID,
+		collections: collectionIDs,
+		events:      make(chan Event, h.buffer),
+		hub:         h,
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	add(h.byUser, userID, sub)
+	for _, collectionID := range collectionIDs {
+		add(h.byCollection, collectionID, sub)
+	}
+	return sub
+}
+
+// Dispatch отправляет событие подходящим подключениям. Клиент, который не успевает читать события,
+// отключается: он переподключится и перечитает данные, а остальные клиенты не ждут его
+func (h *Hub) Dispatch(event Event) {
+	var slow []*Subscription
+	h.mu.RLock()
+	sent := map[*Subscription]struct{}{}
+	send := func(sub *Subscription) {
+		if _, ok := sent[sub]; ok {
+			return
+		}
+		sent[sub] = struct{}{}
+		select {
+		case sub.events <- event:
+		default:
+			slow = append(slow, sub)
+		}
+	}
+	for sub := range h.byUser[event.UserID] {
+		send(sub)
+	}
+	if event.CollectionID != "" && !event.Private {
+		for sub := range h.byCollection[event.CollectionID] {
+			send(sub)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, sub := range slow {
+		sub.Close()
+	}
+}
+
+// Events - события подключения. Канал закрывается, когда подключение отключено
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Close отключает клиента, повторный вызов ничего не делает
+func (s *Subscription) Close() {
+	h := s.hub
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	remove(h.byUser, s.UserID, s)
+	for _, collectionID := range s.collections {
+		remove(h.byCollection, collectionID, s)
+	}
+	close(s.events)
+}
+
+func add[K comparable](index map[K]map[*Subscription]struct{}, key K, sub *Subscription) {
+	subs, ok := index[key]
+	if !ok {
+		subs = map[*Subscription]struct{}{}
+		index[key] = subs
+	}
+	subs[sub] = struct{}{}
+}
+
+func remove[K comparable](index map[K]map[*Subscription]struct{}, key K, sub *Subscription) {
+	delete(index[key], sub)
+	if len(index[key]) == 0 {
+		delete(index, key)
+	}
+}
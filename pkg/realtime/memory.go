@@ -0,0 +1,41 @@
+package realtime
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryBroker раздает события внутри процесса. Подходит, пока сервис запущен в одном экземпляре
+type MemoryBroker struct {
+	mu       sync.RWMutex
+	nextID   int
+	handlers map[int]func(Event)
+}
+
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{handlers: map[int]func(Event){}}
+}
+
+func (b *MemoryBroker) Publish(ctx context.Context, event Event) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, handler := range b.handlers {
+		handler(event)
+	}
+	return nil
+}
+
+func (b *MemoryBroker) Listen(ctx context.Context, handler func(Event)) error {
+	b.mu.Lock()
+	id := b.nextID
+	b.nextID++
+	b.handlers[id] = handler
+	b.mu.Unlock()
+
+	<-ctx.Done()
+
+	b.mu.Lock()
+	delete(b.handlers, id)
+	b.mu.Unlock()
+	return nil
+}
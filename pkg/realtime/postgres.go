@@ -0,0 +1,81 @@
+package realtime
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	// DefaultPostgresChannel - канал LISTEN/NOTIFY по умолчанию
+	DefaultPostgresChannel = "memoria_events"
+	// maxNotifyPayload - postgres не принимает в NOTIFY строку длиннее 8000 байт
+	maxNotifyPayload     = 8000
+	listenerPingInterval = time.Minute
+)
+
+// PostgresBroker передает события между экземплярами сервиса через LISTEN/NOTIFY. Доставка не гарантируется:
+// пока соединение слушателя переподключается, события теряются, поэтому клиенты перечитывают данные после переподключения
+type PostgresBroker struct {
+	db      *sql.DB
+	dsn     string
+	channel string
+}
+
+// NewPostgresBroker - db используется для публикации, для прослушивания по dsn открывается отдельное соединение
+func NewPostgresBroker(db *sql.DB, dsn string, channel string) *PostgresBroker {
+	if channel == "" {
+		channel = DefaultPostgresChannel
+	}
+	return &PostgresBroker{db: db, dsn: dsn, channel: channel}
+}
+
+// Publish отправляет событие. Слишком большое событие уходит без данных, клиент получит только тип и запросит данные сам
+func (b *PostgresBroker) Publish(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if len(payload) > maxNotifyPayload {
+		event.Data = nil
+		if payload, err = json.Marshal(event); err != nil {
+			return err
+		}
+	}
+	_, err = b.db.ExecContext(ctx, "SELECT pg_notify($1, $2)", b.channel, string(payload))
+	return err
+}
+
+func (b *PostgresBroker) Listen(ctx context.Context, handler func(Event)) error {
+	listener := pq.NewListener(b.dsn, 10*time.Second, time.Minute, nil)
+	defer listener.Close()
+	if err := listener.Listen(b.channel); err != nil {
+		return fmt.Errorf("failed to listen to %s: %w", b.channel, err)
+	}
+
+	ping := time.NewTicker(listenerPingInterval)
+	defer ping.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case notification := <-listener.Notify:
+			// nil приходит после переподключения
+			if notification == nil {
+				continue
+			}
+			var event Event
+			if err := json.Unmarshal([]byte(notification.Extra), &event); err != nil {
+				continue
+			}
+			handler(event)
+		case <-ping.C:
+			// Ping проверяет соединение, при обрыве слушатель переподключится сам
+			go listener.Ping()
+		}
+	}
+}
@@ -0,0 +1,32 @@
+// Package realtime доставляет события подключенным клиентам: уведомления, изменения коллекций, прогресс задач.
+// События публикуются в Broker, каждый экземпляр сервиса слушает брокер и раздает события своим подключениям через Hub
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+)
+
+const (
+	BrokerMemory   = "memory"
+	BrokerPostgres = "postgres"
+)
+
+// Event - событие для клиентов
+type Event struct {
+	Type string `json:"type"`
+	// UserID - получатель. Для событий коллекции - владелец коллекции
+	UserID int `json:"user_id"`
+	// CollectionID - коллекция события. Такие события получают еще и клиенты, которые следят за коллекцией
+	CollectionID string `json:"collection_id,omitempty"`
+	// Private - событие закрытой коллекции, его получает только владелец
+	Private bool            `json:"private,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// Broker передает события между экземплярами сервиса
+type Broker interface {
+	Publish(ctx context.Context, event Event) error
+	// Listen вызывает handler для каждого опубликованного события, пока не отменен ctx
+	Listen(ctx context.Context, handler func(Event)) error
+}
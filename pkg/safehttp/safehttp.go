@@ -0,0 +1,93 @@
+// Package safehttp - HTTP-клиент для запросов по адресам, которые передали пользователи.
+// Не дает обратиться к внутренней сети (SSRF): адрес проверяется после DNS-резолва, при каждом соединении
+package safehttp
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress возвращается при попытке соединиться с локальным или приватным адресом
+var ErrForbiddenAddress = errors.New("connection to private network address is not allowed")
+
+const maxRedirects = 3
+
+// NewClient возвращает клиент с общим таймаутом, без прокси из окружения и с ограничением редиректов
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: denyPrivateAddresses,
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   timeout,
+			ResponseHeaderTimeout: timeout,
+			MaxIdleConns:          10,
+			IdleConnTimeout:       90 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return fmt.Errorf("redirect to unsupported scheme %q", req.URL.Scheme)
+			}
+			return nil
+		},
+	}
+}
+
+func denyPrivateAddresses(network string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !IsPublicIP(ip) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+	}
+	return nil
+}
+
+// Специальные диапазоны, которые не покрыты методами net.IP, но ведут во внутреннюю сеть
+var reservedNetworks = parseCIDRs(
+	"0.0.0.0/8",     // "этот" хост и сеть
+	"100.64.0.0/10", // CGNAT, часто внутренняя сеть облака
+	"192.0.0.0/24",  // служебные адреса IETF
+	"198.18.0.0/15", // тестирование сетевого оборудования
+	"64:ff9b::/96",  // NAT64: за ним может оказаться любой IPv4-адрес
+)
+
+// IsPublicIP - адрес не loopback, не приватный, не link-local, не multicast и не из зарезервированных диапазонов
+func IsPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, network := range reservedNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func parseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
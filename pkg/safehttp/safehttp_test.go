@@ -0,0 +1,101 @@
+package safehttp
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewClientRejectsLoopback(t *testing.T) {
+	var served bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served = true
+	}))
+	defer server.Close()
+
+	resp, err := NewClient(time.Second).Get(server.URL)
+	if err == nil {
+		resp.Body.Close()
+	}
+	if !errors.Is(err, ErrForbiddenAddress) {
+		t.Errorf("Get %s = %v, want ErrForbiddenAddress", server.URL, err)
+	}
+	if served {
+		t.Error("request to loopback address reached the server")
+	}
+}
+
+func TestNewClientRejectsRedirectToLoopback(t *testing.T) {
+	// Сервер теста сам на loopback, поэтому первый запрос идет обычным транспортом,
+	// а редирект - транспортом safehttp: адрес проверяется при каждом соединении
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://127.0.0.1:1/internal", http.StatusFound)
+	}))
+	defer server.Close()
+
+	client := NewClient(time.Second)
+	safe := client.Transport.(*http.Transport)
+	client.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if req.URL.String() == server.URL {
+			return http.DefaultTransport.RoundTrip(req)
+		}
+		return safe.RoundTrip(req)
+	})
+
+	resp, err := client.Get(server.URL)
+	if err == nil {
+		resp.Body.Close()
+	}
+	if !errors.Is(err, ErrForbiddenAddress) {
+		t.Errorf("redirect to loopback = %v, want ErrForbiddenAddress", err)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestIsPublicIP(t *testing.T) {
+	tests := []struct {
+		ip     string
+		public bool
+	}{
+		{"8.8.8.8", true},
+		{"93.184.216.34", true},
+		{"2606:4700:4700::1111", true},
+		{"127.0.0.1", false},
+		{"127.1.2.3", false},
+		{"::1", false},
+		{"10.0.0.1", false},
+		{"172.16.5.4", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false}, // метаданные облака
+		{"fe80::1", false},
+		{"fc00::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"224.0.0.1", false},
+		{"ff02::1", false},
+		{"::ffff:127.0.0.1", false},
+		{"0.1.2.3", false},
+		{"100.64.0.1", false},
+		{"100.127.255.254", false},
+		{"100.128.0.1", true},
+		{"192.0.0.170", false},
+		{"198.18.0.1", false},
+		{"198.19.255.254", false},
+		{"198.20.0.1", true},
+		{"64:ff9b::7f00:1", false},
+		{"::ffff:100.64.0.1", false},
+	}
+	for _, tt := range tests {
+		if got := IsPublicIP(net.ParseIP(tt.ip)); got != tt.public {
+			t.Errorf("IsPublicIP(%s) = %v, want %v", tt.ip, got, tt.public)
+		}
+	}
+}
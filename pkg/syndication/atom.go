@@ -0,0 +1,104 @@
+package syndication
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+type atomDocument struct {
+	XMLName   xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Subtitle  string      `xml:"subtitle,omitempty"`
+	Updated   string      `xml:"updated"`
+	Links     []atomLink  `xml:"link"`
+	Author    *atomPerson `xml:"author"`
+	Generator string      `xml:"generator"`
+	Entries   []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Href string `xml:"href,attr"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published,omitempty"`
+	Links      []atomLink     `xml:"link"`
+	Author     *atomPerson    `xml:"author"`
+	Categories []atomCategory `xml:"category"`
+	Content    *atomText      `xml:"content"`
+}
+
+func atomFeed(feed Feed) atomDocument {
+	doc := atomDocument{
+		ID:        feed.ID,
+		Title:     feed.Title,
+		Subtitle:  feed.Subtitle,
+		Updated:   atomTime(feed.Updated),
+		Generator: generator,
+		Entries:   make([]atomEntry, 0, len(feed.Entries)),
+	}
+	if feed.SelfLink != "" {
+		doc.Links = append(doc.Links, atomLink{Rel: "self", Href: feed.SelfLink, Type: "application/atom+xml"})
+	}
+	if feed.Link != "" {
+		doc.Links = append(doc.Links, atomLink{Rel: "alternate", Href: feed.Link})
+	}
+	// Автор ленты обязателен, если он не указан у каждой записи
+	if feed.Author != "" {
+		doc.Author = &atomPerson{Name: feed.Author}
+	} else {
+		doc.Author = &atomPerson{Name: generator}
+	}
+
+	for _, entry := range feed.Entries {
+		updated := entry.Updated
+		if updated.IsZero() {
+			updated = entry.Published
+		}
+		e := atomEntry{
+			ID:      entry.ID,
+			Title:   entry.Title,
+			Updated: atomTime(updated),
+		}
+		if !entry.Published.IsZero() {
+			e.Published = atomTime(entry.Published)
+		}
+		if entry.Link != "" {
+			e.Links = []atomLink{{Rel: "alternate", Href: entry.Link}}
+		}
+		if entry.Author != "" {
+			e.Author = &atomPerson{Name: entry.Author}
+		}
+		for _, category := range entry.Categories {
+			e.Categories = append(e.Categories, atomCategory{Term: category})
+		}
+		if content := contentHTML(entry); content != "" {
+			e.Content = &atomText{Type: "html", Body: content}
+		}
+		doc.Entries = append(doc.Entries, e)
+	}
+	return doc
+}
+
+func atomTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,80 @@
+package syndication
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	SelfLink      *atomLink `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Generator     string    `xml:"generator"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link,omitempty"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+	Categories  []string `xml:"category"`
+	Description string   `xml:"description,omitempty"`
+}
+
+func rssFeed(feed Feed) rssDocument {
+	// description канала обязателен
+	description := feed.Subtitle
+	if description == "" {
+		description = feed.Title
+	}
+	channel := rssChannel{
+		Title:         feed.Title,
+		Link:          feed.Link,
+		Description:   description,
+		LastBuildDate: rssTime(feed.Updated),
+		Generator:     generator,
+		Items:         make([]rssItem, 0, len(feed.Entries)),
+	}
+	if feed.SelfLink != "" {
+		channel.SelfLink = &atomLink{Rel: "self", Href: feed.SelfLink, Type: "application/rss+xml"}
+	}
+
+	for _, entry := range feed.Entries {
+		published := entry.Published
+		if published.IsZero() {
+			published = entry.Updated
+		}
+		channel.Items = append(channel.Items, rssItem{
+			Title:       entry.Title,
+			Link:        entry.Link,
+			GUID:        rssGUID{Value: entry.ID},
+			PubDate:     rssTime(published),
+			Categories:  entry.Categories,
+			Description: contentHTML(entry),
+		})
+	}
+	return rssDocument{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		Channel: channel,
+	}
+}
+
+func rssTime(t time.Time) string {
+	return t.UTC().Format(time.RFC1123Z)
+}
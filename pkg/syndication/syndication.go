@@ -0,0 +1,101 @@
+// Package syndication формирует ленты Atom 1.0 и RSS 2.0 для чтения в RSS-ридерах.
+// Лента описывается один раз в Feed и записывается в любом из форматов
+package syndication
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	FormatAtom = "atom"
+	FormatRSS  = "rss"
+)
+
+var ErrUnknownFormat = errors.New("unknown feed format, supported formats are atom, rss")
+
+const generator = "Memoria"
+
+// Feed - лента: заголовок и записи, новые первыми
+type Feed struct {
+	// ID - постоянный идентификатор ленты (IRI)
+	ID       string
+	Title    string
+	Subtitle string
+	// Link - страница, которую описывает лента, SelfLink - адрес самой ленты
+	Link     string
+	SelfLink string
+	Author   string
+	Updated  time.Time
+	Entries  []Entry
+}
+
+type Entry struct {
+	// ID - постоянный идентификатор записи: по нему ридер отличает новые записи от прочитанных
+	ID        string
+	Title     string
+	Link      string
+	Author    string
+	Published time.Time
+	Updated   time.Time
+	// Summary - текст записи, абзацы разделяются пустой строкой
+	Summary    string
+	Image      *string
+	Categories []string
+}
+
+// ContentType - тип содержимого ленты в формате format
+func ContentType(format string) (string, error) {
+	switch format {
+	case FormatAtom:
+		return "application/atom+xml; charset=utf-8", nil
+	case FormatRSS:
+		return "application/rss+xml; charset=utf-8", nil
+	default:
+		return "", ErrUnknownFormat
+	}
+}
+
+// Write записывает ленту в формате format
+func Write(w io.Writer, format string, feed Feed) error {
+	var doc any
+	switch format {
+	case FormatAtom:
+		doc = atomFeed(feed)
+	case FormatRSS:
+		doc = rssFeed(feed)
+	default:
+		return ErrUnknownFormat
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode %s feed: %w", format, err)
+	}
+	return enc.Close()
+}
+
+// contentHTML - HTML записи: обложка и абзацы текста
+func contentHTML(entry Entry) string {
+	var b strings.Builder
+	if entry.Image != nil && *entry.Image != "" {
+		fmt.Fprintf(&b, `<p><img src="%s" alt="%s"></p>`, html.EscapeString(*entry.Image), html.EscapeString(entry.Title))
+	}
+	for _, paragraph := range strings.Split(entry.Summary, "\n\n") {
+		if paragraph = strings.TrimSpace(paragraph); paragraph != "" {
+			b.WriteString("<p>")
+			b.WriteString(strings.ReplaceAll(html.EscapeString(paragraph), "\n", "<br>"))
+			b.WriteString("</p>")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,78 @@
+package thumbnail
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+)
+
+// Fill масштабирует изображение до точного размера width x height, обрезая лишнее по центру
+func Fill(src image.Image, width, height int) image.Image {
+	bounds := src.Bounds()
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+
+	// Наибольший прямоугольник с пропорциями результата внутри исходника
+	cropWidth, cropHeight := srcWidth, srcWidth*height/width
+	if cropHeight > srcHeight {
+		cropWidth, cropHeight = srcHeight*width/height, srcHeight
+	}
+	cropWidth, cropHeight = max(1, cropWidth), max(1, cropHeight)
+
+	x0 := bounds.Min.X + (srcWidth-cropWidth)/2
+	y0 := bounds.Min.Y + (srcHeight-cropHeight)/2
+	crop := image.NewRGBA(image.Rect(0, 0, cropWidth, cropHeight))
+	draw.Draw(crop, crop.Bounds(), src, image.Point{X: x0, Y: y0}, draw.Src)
+
+	return downscale(crop, width, height)
+}
+
+// Mosaic собирает квадратную сетку grid x grid со стороной size из плиток в порядке строк.
+// Отсутствующие плитки (nil или за концом списка) заливаются цветом empty
+func Mosaic(tiles []image.Image, grid int, size int, empty color.Color) image.Image {
+	canvas := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(canvas, canvas.Bounds(), image.NewUniform(empty), image.Point{}, draw.Src)
+
+	for i := 0; i < grid*grid && i < len(tiles); i++ {
+		if tiles[i] == nil {
+			continue
+		}
+		row, col := i/grid, i%grid
+		cell := image.Rect(col*size/grid, row*size/grid, (col+1)*size/grid, (row+1)*size/grid)
+		tile := Fill(tiles[i], cell.Dx(), cell.Dy())
+		draw.Draw(canvas, cell, tile, image.Point{}, draw.Over)
+	}
+	return canvas
+}
+
+// Placeholder - квадрат с диагональным градиентом от from к to
+func Placeholder(size int, from, to color.RGBA) image.Image {
+	canvas := image.NewRGBA(image.Rect(0, 0, size, size))
+	steps := max(1, 2*(size-1))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			t := (x + y) * 255 / steps
+			offset := canvas.PixOffset(x, y)
+			canvas.Pix[offset] = mix(from.R, to.R, t)
+			canvas.Pix[offset+1] = mix(from.G, to.G, t)
+			canvas.Pix[offset+2] = mix(from.B, to.B, t)
+			canvas.Pix[offset+3] = 255
+		}
+	}
+	return canvas
+}
+
+func EncodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := png.Encoder{CompressionLevel: png.BestSpeed}
+	if err := encoder.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode png: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func mix(a, b uint8, t int) uint8 {
+	return uint8((int(a)*(255-t) + int(b)*t) / 255)
+}
@@ -0,0 +1,143 @@
+// Package thumbnail декодирует изображения и уменьшает их средствами стандартной библиотеки
+package thumbnail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"net/http"
+
+	_ "image/gif" // регистрация декодеров
+	_ "image/png"
+)
+
+var (
+	// ErrUnsupportedFormat возвращается для содержимого, которое не является jpeg, png или gif
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	// ErrTooManyPixels защищает от "бомб" - маленьких файлов с огромным разрешением
+	ErrTooManyPixels = errors.New("image resolution is too large")
+)
+
+// Поддерживаемые типы содержимого и расширения файлов для них
+var supportedTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+const (
+	maxPixels   = 40_000_000
+	jpegQuality = 85
+)
+
+// Sniff определяет тип по содержимому, а не по заголовкам запроса, и возвращает расширение файла
+func Sniff(data []byte) (contentType string, ext string, err error) {
+	contentType = http.DetectContentType(data)
+	ext, ok := supportedTypes[contentType]
+	if !ok {
+		return "", "", fmt.Errorf("%w: %s", ErrUnsupportedFormat, contentType)
+	}
+	return contentType, ext, nil
+}
+
+// Decode проверяет размеры по заголовку до полного декодирования
+func Decode(data []byte) (image.Image, error) {
+	return DecodeLimited(data, maxPixels)
+}
+
+// DecodeLimited работает как Decode, но с собственным ограничением на число пикселей
+func DecodeLimited(data []byte, limit int) (image.Image, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedFormat, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > limit {
+		return nil, ErrTooManyPixels
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedFormat, err)
+	}
+	return img, nil
+}
+
+// Fit уменьшает изображение так, чтобы большая сторона была не больше maxSize, сохраняя пропорции.
+// Маленькие изображения не увеличиваются
+func Fit(src image.Image, maxSize int) image.Image {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= maxSize && height <= maxSize {
+		return toRGBA(src)
+	}
+
+	if width >= height {
+		height = max(1, height*maxSize/width)
+		width = maxSize
+	} else {
+		width = max(1, width*maxSize/height)
+		height = maxSize
+	}
+	return downscale(toRGBA(src), width, height)
+}
+
+// EncodeJPEG кодирует миниатюру. Прозрачность заменяется белым фоном
+func EncodeJPEG(img image.Image) ([]byte, error) {
+	canvas := image.NewRGBA(img.Bounds())
+	draw.Draw(canvas, canvas.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(canvas, canvas.Bounds(), img, img.Bounds().Min, draw.Over)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, canvas, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, fmt.Errorf("failed to encode jpeg: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func toRGBA(src image.Image) *image.RGBA {
+	if rgba, ok := src.(*image.RGBA); ok && rgba.Bounds().Min == (image.Point{}) {
+		return rgba
+	}
+	bounds := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Src)
+	return dst
+}
+
+// downscale - усреднение по площади: каждый пиксель результата - среднее покрываемого им прямоугольника исходника
+func downscale(src *image.RGBA, width, height int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	srcWidth, srcHeight := src.Bounds().Dx(), src.Bounds().Dy()
+
+	for y := 0; y < height; y++ {
+		y0 := y * srcHeight / height
+		y1 := max(y0+1, (y+1)*srcHeight/height)
+		for x := 0; x < width; x++ {
+			x0 := x * srcWidth / width
+			x1 := max(x0+1, (x+1)*srcWidth/width)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				offset := src.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					r += uint64(src.Pix[offset])
+					g += uint64(src.Pix[offset+1])
+					b += uint64(src.Pix[offset+2])
+					a += uint64(src.Pix[offset+3])
+					offset += 4
+					n++
+				}
+			}
+
+			offset := dst.PixOffset(x, y)
+			dst.Pix[offset] = uint8(r / n)
+			dst.Pix[offset+1] = uint8(g / n)
+			dst.Pix[offset+2] = uint8(b / n)
+			dst.Pix[offset+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
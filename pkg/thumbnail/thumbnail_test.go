@@ -0,0 +1,128 @@
+package thumbnail
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func testImage(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{uint8(x), uint8(y), 128, 255})
+		}
+	}
+	return img
+}
+
+func encode(t *testing.T, format string, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "png":
+		err = png.Encode(&buf, img)
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, nil)
+	case "gif":
+		err = gif.Encode(&buf, img, nil)
+	}
+	if err != nil {
+		t.Fatalf("encode %s: %v", format, err)
+	}
+	return buf.Bytes()
+}
+
+func TestSniff(t *testing.T) {
+	img := testImage(4, 4)
+	tests := []struct {
+		name        string
+		data        []byte
+		contentType string
+		ext         string
+	}{
+		{"png", encode(t, "png", img), "image/png", ".png"},
+		{"jpeg", encode(t, "jpeg", img), "image/jpeg", ".jpg"},
+		{"gif", encode(t, "gif", img), "image/gif", ".gif"},
+		{"empty", nil, "", ""},
+		{"text", []byte("definitely not an image"), "", ""},
+		{"html", []byte("<html><body><img src=x onerror=alert(1)></body></html>"), "", ""},
+		{"svg", []byte(`<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`), "", ""},
+		{"pdf", []byte("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n"), "", ""},
+		{"webp", []byte("RIFF\x00\x00\x00\x00WEBPVP8 "), "", ""},
+		{"bmp", []byte("BM\x00\x00\x00\x00\x00\x00\x00\x00"), "", ""},
+	}
+	for _, tt := range tests {
+		contentType, ext, err := Sniff(tt.data)
+		if tt.contentType == "" {
+			if !errors.Is(err, ErrUnsupportedFormat) {
+				t.Errorf("%s: Sniff error = %v, want ErrUnsupportedFormat", tt.name, err)
+			}
+			continue
+		}
+		if err != nil || contentType != tt.contentType || ext != tt.ext {
+			t.Errorf("%s: Sniff = %q, %q, %v, want %q, %q", tt.name, contentType, ext, err, tt.contentType, tt.ext)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	img, err := Decode(encode(t, "png", testImage(30, 20)))
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if got := img.Bounds().Size(); got != (image.Point{30, 20}) {
+		t.Errorf("Decode size = %v, want 30x20", got)
+	}
+
+	// PNG 1x1 с разрешением 10000x10000 в заголовке: отказ должен прийти до декодирования пикселей
+	huge := encode(t, "png", testImage(1, 1))
+	huge[16], huge[17], huge[18], huge[19] = 0, 0, 0x27, 0x10
+	huge[20], huge[21], huge[22], huge[23] = 0, 0, 0x27, 0x10
+	binary.BigEndian.PutUint32(huge[29:33], crc32.ChecksumIEEE(huge[12:29]))
+	if _, err := Decode(huge); !errors.Is(err, ErrTooManyPixels) {
+		t.Errorf("Decode of huge image = %v, want ErrTooManyPixels", err)
+	}
+
+	truncated := encode(t, "jpeg", testImage(8, 8))[:20]
+	if _, err := Decode(truncated); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("Decode of truncated jpeg = %v, want ErrUnsupportedFormat", err)
+	}
+}
+
+func TestDecodeLimited(t *testing.T) {
+	data := encode(t, "png", testImage(30, 20))
+	if _, err := DecodeLimited(data, 600); err != nil {
+		t.Errorf("DecodeLimited at the limit: %v", err)
+	}
+	if _, err := DecodeLimited(data, 599); !errors.Is(err, ErrTooManyPixels) {
+		t.Errorf("DecodeLimited over the limit = %v, want ErrTooManyPixels", err)
+	}
+}
+
+func TestFit(t *testing.T) {
+	tests := []struct {
+		width, height int
+		maxSize       int
+		want          image.Point
+	}{
+		{400, 200, 100, image.Point{100, 50}},
+		{200, 400, 100, image.Point{50, 100}},
+		{50, 30, 100, image.Point{50, 30}},
+		{1000, 1, 100, image.Point{100, 1}},
+	}
+	for _, tt := range tests {
+		got := Fit(testImage(tt.width, tt.height), tt.maxSize).Bounds().Size()
+		if got != tt.want {
+			t.Errorf("Fit(%dx%d, %d) = %v, want %v", tt.width, tt.height, tt.maxSize, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Package webhook отправляет события на адреса пользователей. Тело запроса подписывается HMAC-SHA256:
+// получатель считает подпись от "<timestamp>.<body>" своим секретом и сравнивает с заголовком X-Memoria-Signature
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Заголовки запроса вебхука
+const (
+	HeaderEvent     = "X-Memoria-Event"
+	HeaderDelivery  = "X-Memoria-Delivery"
+	HeaderTimestamp = "X-Memoria-Timestamp"
+	HeaderSignature = "X-Memoria-Signature"
+)
+
+const (
+	signaturePrefix = "sha256="
+	secretPrefix    = "whsec_"
+	userAgent       = "Memoria-Webhooks/1.0"
+	// maxResponseBody - сколько байт ответа получателя сохраняется в журнал отправок
+	maxResponseBody = 1024
+)
+
+var (
+	ErrUnexpectedStatus = errors.New("receiver responded with non-2xx status")
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+)
+
+// NewSecret - случайный секрет для подписи
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return secretPrefix + hex.EncodeToString(b), nil
+}
+
+// Sign - подпись тела запроса, отправленного в момент timestamp (unix-время)
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify проверяет подпись на стороне получателя. tolerance ограничивает возраст запроса, чтобы его нельзя было повторить позже
+func Verify(secret string, timestamp string, body []byte, signature string, tolerance time.Duration) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if tolerance > 0 {
+		if age := time.Since(time.Unix(ts, 0)); age > tolerance || age < -tolerance {
+			return ErrInvalidSignature
+		}
+	}
+	if !hmac.Equal([]byte(Sign(secret, ts, body)), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Request - одна отправка события
+type Request struct {
+	URL        string
+	Secret     string
+	Event      string
+	DeliveryID string
+	Body       []byte
+}
+
+// Response - ответ получателя для журнала отправок
+type Response struct {
+	Status int
+	// Body - начало ответа, не больше maxResponseBody байт
+	Body     string
+	Duration time.Duration
+}
+
+// Sender отправляет события. Клиент для адресов пользователей должен запрещать обращения во внутреннюю сеть (safehttp)
+type Sender struct {
+	client *http.Client
+}
+
+func NewSender(client *http.Client) *Sender {
+	return &Sender{client: client}
+}
+
+// Send отправляет событие. Ответ возвращается и при ошибке, если получатель успел ответить
+func (s *Sender) Send(ctx context.Context, req Request) (*Response, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, req.URL, bytes.NewReader(req.Body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", userAgent)
+	httpReq.Header.Set(HeaderEvent, req.Event)
+	httpReq.Header.Set(HeaderDelivery, req.DeliveryID)
+	timestamp := time.Now().Unix()
+	httpReq.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	httpReq.Header.Set(HeaderSignature, Sign(req.Secret, timestamp, req.Body))
+
+	started := time.Now()
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	// Дочитываем остаток, чтобы соединение вернулось в пул
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	result := &Response{
+		Status:   resp.StatusCode,
+		Body:     strings.ToValidUTF8(string(body), ""),
+		Duration: time.Since(started),
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return result, fmt.Errorf("%w: %d", ErrUnexpectedStatus, resp.StatusCode)
+	}
+	return result, nil
+}
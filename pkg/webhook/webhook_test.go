@@ -0,0 +1,177 @@
+package webhook
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSecret = "whsec_test"
+
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// newReceiver - получатель вебхуков, отвечающий status и body и передающий полученные запросы в канал
+func newReceiver(t *testing.T, status int, body string) (*httptest.Server, <-chan receivedRequest) {
+	t.Helper()
+	received := make(chan receivedRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		received <- receivedRequest{header: r.Header.Clone(), body: data}
+		w.WriteHeader(status)
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return server, received
+}
+
+func TestSend(t *testing.T) {
+	server, received := newReceiver(t, http.StatusNoContent, "")
+	body := []byte(`{"id":42,"type":"item.added"}`)
+
+	resp, err := NewSender(server.Client()).Send(t.Context(), Request{
+		URL:        server.URL,
+		Secret:     testSecret,
+		Event:      "item.added",
+		DeliveryID: "42",
+		Body:       body,
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if resp.Status != http.StatusNoContent {
+		t.Errorf("response status = %d, want 204", resp.Status)
+	}
+
+	req := <-received
+	if string(req.body) != string(body) {
+		t.Errorf("received body = %s, want %s", req.body, body)
+	}
+	wantHeaders := map[string]string{
+		"Content-Type":  "application/json",
+		"User-Agent":    userAgent,
+		HeaderEvent:     "item.added",
+		HeaderDelivery:  "42",
+		HeaderTimestamp: req.header.Get(HeaderTimestamp),
+	}
+	for name, want := range wantHeaders {
+		if got := req.header.Get(name); got != want || got == "" {
+			t.Errorf("header %s = %q, want %q", name, got, want)
+		}
+	}
+
+	timestamp, err := strconv.ParseInt(req.header.Get(HeaderTimestamp), 10, 64)
+	if err != nil || time.Since(time.Unix(timestamp, 0)) > time.Minute {
+		t.Errorf("timestamp = %q, want current unix time", req.header.Get(HeaderTimestamp))
+	}
+	signature := req.header.Get(HeaderSignature)
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		t.Errorf("signature = %q, want %s prefix", signature, signaturePrefix)
+	}
+	if err := Verify(testSecret, req.header.Get(HeaderTimestamp), req.body, signature, 5*time.Minute); err != nil {
+		t.Errorf("Verify of sent request = %v, want nil", err)
+	}
+}
+
+func TestSendNon2xx(t *testing.T) {
+	for _, status := range []int{http.StatusMovedPermanently, http.StatusBadRequest, http.StatusInternalServerError} {
+		server, received := newReceiver(t, status, "receiver is down")
+
+		resp, err := NewSender(server.Client()).Send(t.Context(), Request{URL: server.URL, Secret: testSecret, Body: []byte(`{}`)})
+		<-received
+		if !errors.Is(err, ErrUnexpectedStatus) {
+			t.Errorf("status %d: Send error = %v, want ErrUnexpectedStatus", status, err)
+		}
+		// Ответ нужен для журнала отправок и при ошибке
+		if resp == nil || resp.Status != status || resp.Body != "receiver is down" {
+			t.Errorf("status %d: response = %+v, want status and body", status, resp)
+		}
+	}
+}
+
+func TestSendTruncatesResponse(t *testing.T) {
+	server, received := newReceiver(t, http.StatusOK, strings.Repeat("a", 10*maxResponseBody))
+
+	resp, err := NewSender(server.Client()).Send(t.Context(), Request{URL: server.URL, Secret: testSecret})
+	<-received
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if len(resp.Body) != maxResponseBody {
+		t.Errorf("response body length = %d, want %d", len(resp.Body), maxResponseBody)
+	}
+}
+
+func TestSendConnectionError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	resp, err := NewSender(http.DefaultClient).Send(t.Context(), Request{URL: url, Secret: testSecret})
+	if err == nil || errors.Is(err, ErrUnexpectedStatus) {
+		t.Errorf("Send to closed server = %v, want connection error", err)
+	}
+	if resp != nil {
+		t.Errorf("response = %+v, want nil without answer", resp)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"id":1}`)
+	now := time.Now().Unix()
+	signature := Sign(testSecret, now, body)
+	timestamp := strconv.FormatInt(now, 10)
+	old := time.Now().Add(-time.Hour).Unix()
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		body      string
+		signature string
+		valid     bool
+	}{
+		{"valid", testSecret, timestamp, string(body), signature, true},
+		{"other secret", "whsec_other", timestamp, string(body), signature, false},
+		{"changed body", testSecret, timestamp, `{"id":2}`, signature, false},
+		{"changed timestamp", testSecret, strconv.FormatInt(now+1, 10), string(body), signature, false},
+		{"no prefix", testSecret, timestamp, string(body), strings.TrimPrefix(signature, signaturePrefix), false},
+		{"bad timestamp", testSecret, "now", string(body), signature, false},
+		{"expired", testSecret, strconv.FormatInt(old, 10), string(body), Sign(testSecret, old, body), false},
+	}
+	for _, tt := range tests {
+		err := Verify(tt.secret, tt.timestamp, []byte(tt.body), tt.signature, 5*time.Minute)
+		if tt.valid && err != nil {
+			t.Errorf("%s: Verify = %v, want nil", tt.name, err)
+		}
+		if !tt.valid && !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%s: Verify = %v, want ErrInvalidSignature", tt.name, err)
+		}
+	}
+
+	// Без tolerance возраст запроса не проверяется
+	if err := Verify(testSecret, strconv.FormatInt(old, 10), body, Sign(testSecret, old, body), 0); err != nil {
+		t.Errorf("Verify without tolerance = %v, want nil", err)
+	}
+}
+
+func TestNewSecret(t *testing.T) {
+	first, err := NewSecret()
+	if err != nil {
+		t.Fatalf("NewSecret: %v", err)
+	}
+	second, _ := NewSecret()
+	if !strings.HasPrefix(first, secretPrefix) || len(first) != len(secretPrefix)+64 {
+		t.Errorf("NewSecret = %q, want %s and 32 random bytes in hex", first, secretPrefix)
+	}
+	if first == second {
+		t.Error("NewSecret returned the same secret twice")
+	}
+}
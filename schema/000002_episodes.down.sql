@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS user_item_progress;
+DROP TABLE IF EXISTS user_watched_episodes;
+DROP TABLE IF EXISTS item_episodes;
+DROP TABLE IF EXISTS item_seasons;
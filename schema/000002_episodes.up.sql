@@ -0,0 +1,50 @@
+-- Сезоны сериалов и аниме
+CREATE TABLE item_seasons (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    item_id UUID NOT NULL REFERENCES collection_items(id) ON DELETE CASCADE,
+    number int NOT NULL CHECK (number >= 0), -- 0 - спецвыпуски
+    title VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE(item_id, number)
+);
+
+-- Эпизоды сезона
+CREATE TABLE item_episodes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    season_id UUID NOT NULL REFERENCES item_seasons(id) ON DELETE CASCADE,
+    number int NOT NULL CHECK (number > 0),
+    title VARCHAR(255),
+    air_date DATE,
+    runtime_minutes int CHECK (runtime_minutes > 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE(season_id, number)
+);
+
+-- Просмотренные пользователем эпизоды
+CREATE TABLE user_watched_episodes (
+    user_id int NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    episode_id UUID NOT NULL REFERENCES item_episodes(id) ON DELETE CASCADE,
+    watched_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY(user_id, episode_id)
+);
+
+-- Прогресс и статус пользователя по элементу (общий для всех коллекций пользователя)
+CREATE TABLE user_item_progress (
+    user_id int NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    item_id UUID NOT NULL REFERENCES collection_items(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'planned' CHECK (status IN ('planned', 'in_progress', 'completed', 'dropped')),
+    progress int NOT NULL DEFAULT 0, -- кол-во просмотренных эпизодов
+    started_at TIMESTAMP WITH TIME ZONE,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY(user_id, item_id)
+);
+
+CREATE INDEX idx_seasons_item_id ON item_seasons(item_id);
+CREATE INDEX idx_episodes_season_id ON item_episodes(season_id);
+CREATE INDEX idx_watched_episodes_episode_id ON user_watched_episodes(episode_id);
+CREATE INDEX idx_item_progress_user_status ON user_item_progress(user_id, status);
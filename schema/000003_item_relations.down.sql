@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS franchise_items;
+DROP TABLE IF EXISTS franchises;
+DROP TABLE IF EXISTS item_relations;
+ALTER TABLE users DROP COLUMN IF EXISTS role;
@@ -0,0 +1,38 @@
+-- Роли пользователей для модерации публичного каталога
+ALTER TABLE users ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'user' CHECK (role IN ('user', 'moderator', 'admin'));
+
+-- Направленные связи между элементами: source является <relation_type> для target
+-- Например: (фильм, adaptation, книга) - фильм является экранизацией книги
+CREATE TABLE item_relations (
+    id serial PRIMARY KEY,
+    source_item_id UUID NOT NULL REFERENCES collection_items(id) ON DELETE CASCADE,
+    target_item_id UUID NOT NULL REFERENCES collection_items(id) ON DELETE CASCADE,
+    relation_type VARCHAR(20) NOT NULL CHECK (relation_type IN ('sequel', 'prequel', 'adaptation', 'spin_off', 'same_franchise', 'remake')),
+    created_by int REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CHECK (source_item_id <> target_item_id),
+    UNIQUE(source_item_id, target_item_id, relation_type)
+);
+
+CREATE INDEX idx_item_relations_source ON item_relations(source_item_id);
+CREATE INDEX idx_item_relations_target ON item_relations(target_item_id);
+
+-- Франшизы - группировка элементов разных типов
+CREATE TABLE franchises (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    created_by int REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE franchise_items (
+    franchise_id UUID NOT NULL REFERENCES franchises(id) ON DELETE CASCADE,
+    item_id UUID NOT NULL REFERENCES collection_items(id) ON DELETE CASCADE,
+    added_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY(franchise_id, item_id)
+);
+
+CREATE INDEX idx_franchise_items_item_id ON franchise_items(item_id);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS collection_tags;
+DROP TABLE IF EXISTS item_tags;
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS item_genres;
+DROP TABLE IF EXISTS genres;
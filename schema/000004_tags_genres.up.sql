@@ -0,0 +1,66 @@
+-- Курируемая таксономия жанров для публичного каталога
+CREATE TABLE genres (
+    id serial PRIMARY KEY,
+    slug VARCHAR(50) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE item_genres (
+    item_id UUID NOT NULL REFERENCES collection_items(id) ON DELETE CASCADE,
+    genre_id int NOT NULL REFERENCES genres(id) ON DELETE CASCADE,
+
+    PRIMARY KEY(item_id, genre_id)
+);
+
+CREATE INDEX idx_item_genres_genre_id ON item_genres(genre_id);
+
+-- Свободные пользовательские теги
+CREATE TABLE tags (
+    id serial PRIMARY KEY,
+    user_id int NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Имя тега уникально в рамках пользователя без учета регистра, индекс также используется для автодополнения по префиксу
+CREATE UNIQUE INDEX idx_tags_user_name ON tags(user_id, lower(name));
+CREATE INDEX idx_tags_user_name_prefix ON tags(user_id, lower(name) text_pattern_ops);
+
+-- Теги пользователя на элементах его медиатеки
+CREATE TABLE item_tags (
+    user_id int NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    item_id UUID NOT NULL REFERENCES collection_items(id) ON DELETE CASCADE,
+    tag_id int NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+
+    PRIMARY KEY(user_id, item_id, tag_id)
+);
+
+CREATE INDEX idx_item_tags_tag_id ON item_tags(tag_id);
+
+-- Теги коллекций
+CREATE TABLE collection_tags (
+    collection_id UUID NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+    tag_id int NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+
+    PRIMARY KEY(collection_id, tag_id)
+);
+
+CREATE INDEX idx_collection_tags_tag_id ON collection_tags(tag_id);
+
+-- Базовый набор жанров
+INSERT INTO genres (slug, name) VALUES
+    ('action', 'Боевик'),
+    ('adventure', 'Приключения'),
+    ('comedy', 'Комедия'),
+    ('drama', 'Драма'),
+    ('fantasy', 'Фэнтези'),
+    ('horror', 'Ужасы'),
+    ('mystery', 'Детектив'),
+    ('romance', 'Мелодрама'),
+    ('sci-fi', 'Научная фантастика'),
+    ('slice-of-life', 'Повседневность'),
+    ('thriller', 'Триллер'),
+    ('documentary', 'Документальный'),
+    ('history', 'История'),
+    ('non-fiction', 'Нон-фикшн');
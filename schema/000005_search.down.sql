@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_collection_items_title_trgm;
+DROP INDEX IF EXISTS idx_collection_items_search_vector;
+ALTER TABLE collection_items DROP COLUMN IF EXISTS search_vector;
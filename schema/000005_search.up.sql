@@ -0,0 +1,15 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Полнотекстовый поиск: название весит больше описания (A > B).
+-- Используются конфигурации russian и english, чтобы корректно обрабатывать оба языка
+ALTER TABLE collection_items ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('russian', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('russian', coalesce(description, '')), 'B') ||
+    setweight(to_tsvector('english', coalesce(description, '')), 'B')
+) STORED;
+
+CREATE INDEX idx_collection_items_search_vector ON collection_items USING GIN(search_vector);
+
+-- Нечеткий поиск по названию (опечатки)
+CREATE INDEX idx_collection_items_title_trgm ON collection_items USING GIN(lower(title) gin_trgm_ops);
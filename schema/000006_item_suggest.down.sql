@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_collection_items_creator_id;
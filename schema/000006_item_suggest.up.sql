@@ -0,0 +1,2 @@
+-- Автодополнение ищет среди публичных элементов и собственных элементов пользователя
+CREATE INDEX idx_collection_items_creator_id ON collection_items(creator_id);
@@ -0,0 +1,5 @@
+DROP VIEW IF EXISTS collection_item_stats;
+DROP INDEX IF EXISTS idx_item_progress_item_rating;
+ALTER TABLE user_item_progress DROP COLUMN IF EXISTS rating;
+DROP INDEX IF EXISTS idx_collection_items_release_year;
+ALTER TABLE collection_items DROP COLUMN IF EXISTS release_year;
@@ -0,0 +1,26 @@
+-- Год выхода элемента для фильтрации ленты
+ALTER TABLE collection_items ADD COLUMN release_year int CHECK (release_year BETWEEN 1000 AND 3000);
+CREATE INDEX idx_collection_items_release_year ON collection_items(release_year);
+
+-- Оценка пользователя по шкале 1-10
+ALTER TABLE user_item_progress ADD COLUMN rating smallint CHECK (rating BETWEEN 1 AND 10);
+CREATE INDEX idx_item_progress_item_rating ON user_item_progress(item_id) WHERE rating IS NOT NULL;
+
+-- Статистика элементов для сортировки и фильтрации ленты
+CREATE VIEW collection_item_stats AS
+SELECT ci.id AS item_id,
+       COALESCE(a.collections_count, 0) AS popularity,
+       r.avg_rating,
+       COALESCE(r.ratings_count, 0) AS ratings_count
+FROM collection_items ci
+LEFT JOIN (
+    SELECT item_id, COUNT(*) AS collections_count
+    FROM collections_items_assignment
+    GROUP BY item_id
+) a ON a.item_id = ci.id
+LEFT JOIN (
+    SELECT item_id, AVG(rating)::float8 AS avg_rating, COUNT(*) AS ratings_count
+    FROM user_item_progress
+    WHERE rating IS NOT NULL
+    GROUP BY item_id
+) r ON r.item_id = ci.id;
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS item_redirects;
+DROP INDEX IF EXISTS idx_collection_items_normalized_title_trgm;
+DROP INDEX IF EXISTS idx_collection_items_type_normalized_title;
+ALTER TABLE collection_items DROP COLUMN IF EXISTS normalized_title;
+DROP FUNCTION IF EXISTS item_title_year(text);
+DROP FUNCTION IF EXISTS normalize_item_title(text);
@@ -0,0 +1,30 @@
+-- Нормализованное название для поиска дубликатов: нижний регистр, без года в скобках и пунктуации.
+-- "DUNE (1965)", "Dune " и "Dune" дают одно и то же значение
+CREATE FUNCTION normalize_item_title(title text) RETURNS text
+LANGUAGE sql IMMUTABLE PARALLEL SAFE AS $$
+    SELECT btrim(regexp_replace(
+        regexp_replace(lower(title), '\(\s*\d{4}\s*\)', ' ', 'g'),
+        '[^[:alnum:]]+', ' ', 'g'
+    ))
+$$;
+
+-- Год из названия вида "Dune (1965)", если release_year не заполнен
+CREATE FUNCTION item_title_year(title text) RETURNS int
+LANGUAGE sql IMMUTABLE PARALLEL SAFE AS $$
+    SELECT (regexp_match(title, '\(\s*(\d{4})\s*\)'))[1]::int
+$$;
+
+ALTER TABLE collection_items ADD COLUMN normalized_title text GENERATED ALWAYS AS (normalize_item_title(title)) STORED;
+
+CREATE INDEX idx_collection_items_type_normalized_title ON collection_items(type, normalized_title);
+CREATE INDEX idx_collection_items_normalized_title_trgm ON collection_items USING GIN(normalized_title gin_trgm_ops);
+
+-- Редиректы со старых ID элементов, слитых с каноническими
+CREATE TABLE item_redirects (
+    old_id UUID PRIMARY KEY,
+    new_id UUID NOT NULL REFERENCES collection_items(id) ON DELETE CASCADE,
+    merged_by int REFERENCES users(id) ON DELETE SET NULL,
+    merged_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_item_redirects_new_id ON item_redirects(new_id);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS moderation_decisions;
+DROP INDEX IF EXISTS idx_collection_items_pending;
+ALTER TABLE collection_items DROP COLUMN IF EXISTS moderation_status;
@@ -0,0 +1,33 @@
+-- Статус модерации пользовательских элементов:
+-- none - приватный элемент, pending - ждет проверки, approved - в публичном каталоге,
+-- rejected - отклонен, changes_requested - нужны правки от автора
+ALTER TABLE collection_items ADD COLUMN moderation_status VARCHAR(20) NOT NULL DEFAULT 'none'
+    CHECK (moderation_status IN ('none', 'pending', 'approved', 'rejected', 'changes_requested'));
+
+UPDATE collection_items SET moderation_status = 'approved' WHERE is_public = TRUE;
+
+CREATE INDEX idx_collection_items_pending ON collection_items(created_at) WHERE moderation_status = 'pending';
+
+-- Журнал решений модерации
+CREATE TABLE moderation_decisions (
+    id serial PRIMARY KEY,
+    item_id UUID NOT NULL REFERENCES collection_items(id) ON DELETE CASCADE,
+    actor_id int REFERENCES users(id) ON DELETE SET NULL,
+    decision VARCHAR(20) NOT NULL CHECK (decision IN ('submitted', 'approved', 'rejected', 'changes_requested')),
+    reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_moderation_decisions_item_id ON moderation_decisions(item_id, created_at);
+
+-- Уведомления пользователей
+CREATE TABLE notifications (
+    id serial PRIMARY KEY,
+    user_id int NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}',
+    read_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_notifications_user_id ON notifications(user_id, created_at DESC);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS item_revisions;
+DROP TABLE IF EXISTS item_edit_suggestions;
@@ -0,0 +1,31 @@
+-- Предложенные пользователями правки элементов каталога.
+-- changes - новые значения полей: {"title": "...", "release_year": 1965}
+CREATE TABLE item_edit_suggestions (
+    id serial PRIMARY KEY,
+    item_id UUID NOT NULL REFERENCES collection_items(id) ON DELETE CASCADE,
+    author_id int REFERENCES users(id) ON DELETE SET NULL,
+    changes JSONB NOT NULL,
+    comment TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'rejected')),
+    reviewer_id int REFERENCES users(id) ON DELETE SET NULL,
+    review_comment TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    reviewed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_item_edit_suggestions_item_id ON item_edit_suggestions(item_id, created_at DESC);
+CREATE INDEX idx_item_edit_suggestions_pending ON item_edit_suggestions(created_at) WHERE status = 'pending';
+
+-- История изменений элемента. diff - старое и новое значение каждого измененного поля:
+-- {"title": {"old": "Dnue", "new": "Dune"}}
+CREATE TABLE item_revisions (
+    id serial PRIMARY KEY,
+    item_id UUID NOT NULL REFERENCES collection_items(id) ON DELETE CASCADE,
+    author_id int REFERENCES users(id) ON DELETE SET NULL,
+    suggestion_id int REFERENCES item_edit_suggestions(id) ON DELETE SET NULL,
+    reverted_revision_id int REFERENCES item_revisions(id) ON DELETE SET NULL,
+    diff JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_item_revisions_item_id ON item_revisions(item_id, created_at DESC);
@@ -0,0 +1,18 @@
+-- Загруженные изображения: обложки элементов и коллекций, аватары.
+-- Оригинал и миниатюры лежат в хранилище по ключам <blob_prefix>/original.<ext> и <blob_prefix>/<size>.jpg,
+-- а в cover_image/avatar_url сохраняется постоянная ссылка /images/<id>, которая редиректит на подписанную ссылку
+CREATE TABLE images (
+    id UUID PRIMARY KEY,
+    owner_id int REFERENCES users(id) ON DELETE SET NULL,
+    target_type VARCHAR(20) NOT NULL CHECK (target_type IN ('item', 'collection', 'avatar')),
+    target_id TEXT NOT NULL,
+    blob_prefix TEXT NOT NULL,
+    original_key TEXT NOT NULL,
+    content_type VARCHAR(50) NOT NULL,
+    size bigint NOT NULL,
+    width int NOT NULL,
+    height int NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_images_target ON images(target_type, target_id, created_at DESC);
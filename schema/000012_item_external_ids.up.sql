@@ -0,0 +1,12 @@
+-- Внешние ID элементов в каталогах провайдеров метаданных (openlibrary, tmdb, anilist, shikimori).
+-- Уникальность пары provider + external_id не дает импортировать одну запись дважды
+CREATE TABLE item_external_ids (
+    provider VARCHAR(30) NOT NULL,
+    external_id VARCHAR(100) NOT NULL,
+    item_id UUID NOT NULL REFERENCES collection_items(id) ON DELETE CASCADE,
+    imported_by int REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (provider, external_id)
+);
+
+CREATE INDEX idx_item_external_ids_item_id ON item_external_ids(item_id);
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS item_locked_fields;
+
+ALTER TABLE item_revisions DROP COLUMN IF EXISTS provider;
+
+DROP INDEX IF EXISTS idx_item_external_ids_refreshed_at;
+ALTER TABLE item_external_ids
+    DROP COLUMN IF EXISTS refresh_error,
+    DROP COLUMN IF EXISTS refreshed_at;
@@ -0,0 +1,19 @@
+-- Состояние периодического обновления импортированных элементов из внешних каталогов.
+-- refresh_error - текст ошибки последнего обновления, NULL если оно прошло успешно
+ALTER TABLE item_external_ids
+    ADD COLUMN refreshed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ADD COLUMN refresh_error TEXT;
+
+CREATE INDEX idx_item_external_ids_refreshed_at ON item_external_ids(refreshed_at);
+
+-- Ревизии, созданные обновлением из внешнего каталога, хранят имя провайдера
+ALTER TABLE item_revisions ADD COLUMN provider VARCHAR(30);
+
+-- Поля элемента, закрепленные модератором: обновление из внешних каталогов их не меняет
+CREATE TABLE item_locked_fields (
+    item_id UUID NOT NULL REFERENCES collection_items(id) ON DELETE CASCADE,
+    field VARCHAR(30) NOT NULL,
+    locked_by int REFERENCES users(id) ON DELETE SET NULL,
+    locked_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (item_id, field)
+);
@@ -0,0 +1,40 @@
+-- Импорт выгрузок других трекеров (goodreads, mal, letterboxd, imdb). Задача обрабатывается в фоне,
+-- heartbeat_at обновляется по ходу обработки: задачу, которая давно не обновлялась, подхватывает обработчик заново
+CREATE TABLE import_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id int NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    format VARCHAR(20) NOT NULL,
+    filename VARCHAR(255),
+    create_custom BOOLEAN NOT NULL DEFAULT true, -- создавать свои элементы для несопоставленных строк
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    total_rows int NOT NULL DEFAULT 0,
+    processed_rows int NOT NULL DEFAULT 0,
+    matched_rows int NOT NULL DEFAULT 0,
+    created_rows int NOT NULL DEFAULT 0,
+    unmatched_rows int NOT NULL DEFAULT 0,
+    failed_rows int NOT NULL DEFAULT 0,
+    resolved_rows int NOT NULL DEFAULT 0,
+    error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMP WITH TIME ZONE,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    heartbeat_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_import_jobs_user_id ON import_jobs(user_id, created_at DESC);
+CREATE INDEX idx_import_jobs_queue ON import_jobs(created_at) WHERE status IN ('pending', 'running');
+
+-- Строки выгрузки. data - разобранная запись, item_id - сопоставленный или созданный элемент.
+-- created и unmatched - строки без элемента каталога, которые пользователь может сопоставить вручную
+CREATE TABLE import_job_rows (
+    id serial PRIMARY KEY,
+    job_id UUID NOT NULL REFERENCES import_jobs(id) ON DELETE CASCADE,
+    line int NOT NULL,
+    data JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'matched', 'created', 'unmatched', 'failed', 'resolved')),
+    match_method VARCHAR(20) CHECK (match_method IN ('external_id', 'title', 'manual')),
+    item_id UUID REFERENCES collection_items(id) ON DELETE SET NULL,
+    error TEXT
+);
+
+CREATE INDEX idx_import_job_rows_job_status ON import_job_rows(job_id, status, id);
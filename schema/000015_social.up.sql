@@ -0,0 +1,31 @@
+-- Приватный аккаунт: подписка на него требует подтверждения, активность видна только подписчикам
+ALTER TABLE users ADD COLUMN is_private BOOLEAN NOT NULL DEFAULT false;
+
+-- Подписки. pending - запрос на подписку к приватному аккаунту, ожидающий подтверждения
+CREATE TABLE follows (
+    follower_id int NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    followee_id int NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'accepted' CHECK (status IN ('accepted', 'pending')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    accepted_at TIMESTAMP WITH TIME ZONE,
+
+    PRIMARY KEY(follower_id, followee_id),
+    CHECK (follower_id <> followee_id)
+);
+
+CREATE INDEX idx_follows_followee ON follows(followee_id, status, created_at DESC);
+
+-- Журнал активности пользователей. Домашняя лента собирается при чтении (fan-out on read):
+-- подписок у пользователя немного, а запись события одной строкой не зависит от числа подписчиков.
+-- payload - данные события, например оценка: {"rating": 8}
+CREATE TABLE activity_events (
+    id bigserial PRIMARY KEY,
+    user_id int NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(30) NOT NULL CHECK (type IN ('item_added', 'item_completed', 'item_rated', 'collection_published')),
+    item_id UUID REFERENCES collection_items(id) ON DELETE CASCADE,
+    collection_id UUID REFERENCES collections(id) ON DELETE CASCADE,
+    payload JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_activity_events_user ON activity_events(user_id, created_at DESC, id DESC);
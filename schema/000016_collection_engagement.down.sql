@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS collection_comments;
+DROP TABLE IF EXISTS collection_saves;
+DROP TABLE IF EXISTS collection_likes;
+ALTER TABLE collections
+    DROP COLUMN IF EXISTS likes_count,
+    DROP COLUMN IF EXISTS saves_count,
+    DROP COLUMN IF EXISTS comments_count;
@@ -0,0 +1,43 @@
+-- Счетчики хранятся в коллекции, чтобы списки коллекций не считали их при каждом запросе.
+-- Обновляются в той же транзакции, что и лайк, сохранение или комментарий
+ALTER TABLE collections
+    ADD COLUMN likes_count int NOT NULL DEFAULT 0,
+    ADD COLUMN saves_count int NOT NULL DEFAULT 0,
+    ADD COLUMN comments_count int NOT NULL DEFAULT 0;
+
+CREATE TABLE collection_likes (
+    user_id int NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    collection_id UUID NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY(user_id, collection_id)
+);
+
+-- Сохраненные коллекции (закладки). Индекс по user_id - для списка "сохраненные мной"
+CREATE TABLE collection_saves (
+    user_id int NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    collection_id UUID NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY(user_id, collection_id)
+);
+
+CREATE INDEX idx_collection_saves_user ON collection_saves(user_id, created_at DESC);
+
+-- Комментарии к коллекциям. parent_id - комментарий, на который дан ответ,
+-- root_id - корневой комментарий ветки (NULL у самого корня): ветку целиком можно выбрать одним запросом.
+-- Удаленный комментарий остается без текста, чтобы не разрывать ветку ответов
+CREATE TABLE collection_comments (
+    id bigserial PRIMARY KEY,
+    collection_id UUID NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+    user_id int NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    parent_id bigint REFERENCES collection_comments(id) ON DELETE CASCADE,
+    root_id bigint REFERENCES collection_comments(id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    edited_at TIMESTAMP WITH TIME ZONE,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_collection_comments_roots ON collection_comments(collection_id, created_at DESC, id DESC) WHERE root_id IS NULL;
+CREATE INDEX idx_collection_comments_thread ON collection_comments(root_id, created_at, id);
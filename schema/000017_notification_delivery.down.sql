@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_notifications_unread;
+DROP TABLE IF EXISTS notification_deliveries;
+DROP TABLE IF EXISTS notification_preferences;
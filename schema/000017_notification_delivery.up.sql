@@ -0,0 +1,28 @@
+-- Настройки уведомлений по типам: in_app - только в приложении, email - в приложении и письмом, off - не уведомлять.
+-- Нет строки - действует in_app
+CREATE TABLE notification_preferences (
+    user_id int NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    channel VARCHAR(20) NOT NULL,
+
+    PRIMARY KEY(user_id, type)
+);
+
+-- Очередь доставки уведомлений во внешние каналы. Строка создается в транзакции уведомления,
+-- фоновый обработчик забирает ее, продлевая next_attempt_at, и повторяет неудачные отправки с нарастающей паузой
+CREATE TABLE notification_deliveries (
+    id bigserial PRIMARY KEY,
+    notification_id int NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+    channel VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'failed')),
+    attempts int NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    sent_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_notification_deliveries_pending ON notification_deliveries(next_attempt_at) WHERE status = 'pending';
+
+-- Счетчик непрочитанных
+CREATE INDEX idx_notifications_unread ON notifications(user_id) WHERE read_at IS NULL;
@@ -0,0 +1,14 @@
+-- Похожие элементы по совместной встречаемости в библиотеках пользователей (коллекции и прогресс с оценками).
+-- Таблица целиком пересчитывается фоновой задачей, для каждого элемента хранятся лучшие совпадения.
+-- score - косинусная похожесть с поправкой на малое число совпадений, co_count - у скольких пользователей встречаются оба
+CREATE TABLE item_similarities (
+    item_id UUID NOT NULL REFERENCES collection_items(id) ON DELETE CASCADE,
+    similar_item_id UUID NOT NULL REFERENCES collection_items(id) ON DELETE CASCADE,
+    score double precision NOT NULL,
+    co_count int NOT NULL,
+    computed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY(item_id, similar_item_id)
+);
+
+CREATE INDEX idx_item_similarities_score ON item_similarities(item_id, score DESC);
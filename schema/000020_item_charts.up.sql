@@ -0,0 +1,19 @@
+-- Чарты каталога, пересчитываются фоновой задачей целиком. Для каждого чарта хранятся первые места
+-- по всему каталогу (item_type и genre пустые), по типу, по жанру и по типу с жанром.
+-- trending - добавления в библиотеки за последние дни с затуханием, most_collected - у скольких пользователей
+-- элемент в коллекциях, top_rated - байесовское среднее оценок
+CREATE TABLE item_charts (
+    chart VARCHAR(20) NOT NULL CHECK (chart IN ('trending', 'most_collected', 'top_rated')),
+    item_type VARCHAR(20) NOT NULL DEFAULT '',
+    genre VARCHAR(50) NOT NULL DEFAULT '',
+    position int NOT NULL,
+    item_id UUID NOT NULL REFERENCES collection_items(id) ON DELETE CASCADE,
+    score double precision NOT NULL,
+    collectors int NOT NULL DEFAULT 0,
+    recent_adds int NOT NULL DEFAULT 0,
+    ratings_count int NOT NULL DEFAULT 0,
+    avg_rating double precision,
+    computed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY(chart, item_type, genre, position)
+);
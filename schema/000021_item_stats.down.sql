@@ -0,0 +1,19 @@
+DROP TABLE IF EXISTS item_stats;
+
+CREATE VIEW collection_item_stats AS
+SELECT ci.id AS item_id,
+       COALESCE(a.collections_count, 0) AS popularity,
+       r.avg_rating,
+       COALESCE(r.ratings_count, 0) AS ratings_count
+FROM collection_items ci
+LEFT JOIN (
+    SELECT item_id, COUNT(*) AS collections_count
+    FROM collections_items_assignment
+    GROUP BY item_id
+) a ON a.item_id = ci.id
+LEFT JOIN (
+    SELECT item_id, AVG(rating)::float8 AS avg_rating, COUNT(*) AS ratings_count
+    FROM user_item_progress
+    WHERE rating IS NOT NULL
+    GROUP BY item_id
+) r ON r.item_id = ci.id;
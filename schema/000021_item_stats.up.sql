@@ -0,0 +1,34 @@
+-- Статистика элементов для фильтров, фасетов и сортировки ленты. Раньше считалась представлением
+-- collection_item_stats по всем коллекциям и оценкам на каждый запрос, теперь пересчитывается фоновой задачей.
+-- Элемент, созданный после пересчета, еще не имеет строки: лента считает его статистику нулевой
+DROP VIEW IF EXISTS collection_item_stats;
+
+CREATE TABLE item_stats (
+    item_id UUID PRIMARY KEY REFERENCES collection_items(id) ON DELETE CASCADE,
+    popularity bigint NOT NULL DEFAULT 0,
+    avg_rating double precision,
+    ratings_count bigint NOT NULL DEFAULT 0,
+    computed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_item_stats_popularity ON item_stats(popularity DESC, item_id);
+CREATE INDEX idx_item_stats_avg_rating ON item_stats(avg_rating) WHERE avg_rating IS NOT NULL;
+
+-- Первое заполнение, чтобы лента не показывала нули до первого запуска задачи
+INSERT INTO item_stats (item_id, popularity, avg_rating, ratings_count)
+SELECT ci.id,
+       COALESCE(a.collections_count, 0),
+       r.avg_rating,
+       COALESCE(r.ratings_count, 0)
+FROM collection_items ci
+LEFT JOIN (
+    SELECT item_id, COUNT(*) AS collections_count
+    FROM collections_items_assignment
+    GROUP BY item_id
+) a ON a.item_id = ci.id
+LEFT JOIN (
+    SELECT item_id, AVG(rating)::float8 AS avg_rating, COUNT(*) AS ratings_count
+    FROM user_item_progress
+    WHERE rating IS NOT NULL
+    GROUP BY item_id
+) r ON r.item_id = ci.id;
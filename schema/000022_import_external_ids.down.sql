@@ -0,0 +1,6 @@
+INSERT INTO item_external_ids (provider, external_id, item_id, imported_by, created_at)
+SELECT provider, external_id, item_id, user_id, created_at
+FROM import_external_ids
+ON CONFLICT DO NOTHING;
+
+DROP TABLE IF EXISTS import_external_ids;
@@ -0,0 +1,35 @@
+-- Внешние ID из выгрузок других трекеров. Файл присылает пользователь, поэтому ID не попадают
+-- в item_external_ids, по которым элементы каталога обновляются из провайдеров метаданных,
+-- и помогают сопоставлять строки только в следующих импортах того же пользователя
+CREATE TABLE import_external_ids (
+    user_id int NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(30) NOT NULL,
+    external_id VARCHAR(100) NOT NULL,
+    item_id UUID NOT NULL REFERENCES collection_items(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, provider, external_id)
+);
+
+CREATE INDEX idx_import_external_ids_item_id ON import_external_ids(item_id);
+
+-- ID, которые импорт уже записал в item_external_ids: источники выгрузок, не являющиеся провайдерами
+-- метаданных, и ID Shikimori, скопированные из ID MyAnimeList той же строки
+INSERT INTO import_external_ids (user_id, provider, external_id, item_id, created_at)
+SELECT e.imported_by, e.provider, e.external_id, e.item_id, e.created_at
+FROM item_external_ids e
+WHERE e.imported_by IS NOT NULL
+    AND (e.provider NOT IN ('openlibrary', 'tmdb', 'anilist', 'shikimori')
+        OR e.provider = 'shikimori' AND EXISTS (
+            SELECT 1 FROM item_external_ids m
+            WHERE m.provider = 'mal' AND m.external_id = e.external_id
+                AND m.item_id = e.item_id AND m.imported_by = e.imported_by
+        ))
+ON CONFLICT DO NOTHING;
+
+DELETE FROM item_external_ids e
+WHERE e.provider NOT IN ('openlibrary', 'tmdb', 'anilist', 'shikimori')
+    OR e.provider = 'shikimori' AND EXISTS (
+        SELECT 1 FROM item_external_ids m
+        WHERE m.provider = 'mal' AND m.external_id = e.external_id
+            AND m.item_id = e.item_id AND m.imported_by = e.imported_by
+    );